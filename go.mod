@@ -3,10 +3,12 @@ module github.com/poiesic/wonda
 go 1.25.1
 
 require (
+	github.com/charmbracelet/x/term v0.2.1
 	github.com/liushuangls/go-anthropic/v2 v2.16.1
 	github.com/pelletier/go-toml/v2 v2.2.4
 	github.com/sashabaranov/go-openai v1.41.2
 	github.com/stretchr/testify v1.11.1
+	golang.org/x/sync v0.17.0
 )
 
 require (
@@ -15,7 +17,6 @@ require (
 	github.com/charmbracelet/lipgloss v1.1.0 // indirect
 	github.com/charmbracelet/x/ansi v0.10.2 // indirect
 	github.com/charmbracelet/x/cellbuf v0.0.13 // indirect
-	github.com/charmbracelet/x/term v0.2.1 // indirect
 	github.com/clipperhouse/uax29/v2 v2.2.0 // indirect
 	github.com/daulet/tokenizers v1.23.0 // indirect
 	github.com/davecgh/go-spew v1.1.1 // indirect
@@ -0,0 +1,482 @@
+// Package api serves simulations over HTTP, so other services can start
+// scenarios, follow their chronicles and cancel them without the CLI.
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/poiesic/wonda/internal/memory"
+	"github.com/poiesic/wonda/internal/runs"
+	"github.com/poiesic/wonda/internal/scenarios"
+	"github.com/poiesic/wonda/internal/simulations"
+)
+
+// pollInterval is how often a followed chronicle is checked for new lines.
+const pollInterval = 250 * time.Millisecond
+
+// defaultMaxRuntime bounds runs whose scenario sets no max_runtime, as the CLI does.
+const defaultMaxRuntime = 30 * time.Minute
+
+// States of a simulation run.
+const (
+	StateInitializing = "initializing" // Loading characters, seeding memories
+	StateRunning      = "running"      // Playing turns
+	StateFinished     = "finished"     // Ended on its own, whatever became of its goals
+	StateFailed       = "failed"       // Stopped by an error
+	StateCanceled     = "canceled"     // Stopped by a DELETE request or the server closing
+)
+
+// StartRequest is the body of a POST /simulations request.
+type StartRequest struct {
+	Scenario string `json:"scenario"`          // Scenario in the config directory's scenarios/, with or without .toml
+	DryRun   bool   `json:"dry_run,omitempty"` // Answer LLM requests with canned responses
+	Speed    string `json:"speed,omitempty"`   // Speed profile (default balanced)
+	Stream   bool   `json:"stream,omitempty"`  // Write partial utterances to the chronicle as agents speak
+}
+
+// Status describes a simulation run.
+type Status struct {
+	ID        string     `json:"id"`
+	User      string     `json:"user,omitempty"` // Who started the run, when the server has users
+	Scenario  string     `json:"scenario"`       // Scenario file, without .toml
+	State     string     `json:"state"`
+	Turn      int        `json:"turn"` // Turn in progress, or the last one started
+	MaxTurns  int        `json:"max_turns"`
+	StartedAt time.Time  `json:"started_at"`
+	EndedAt   *time.Time `json:"ended_at,omitempty"`
+	Error     string     `json:"error,omitempty"`
+	Chronicle string     `json:"chronicle,omitempty"` // Set once the chronicle is written
+	Outcomes  string     `json:"outcomes,omitempty"`  // Set once the run ends
+}
+
+// Server runs simulations for HTTP clients. Runs are kept in memory, so
+// their statuses last as long as the server; their chronicles, outcomes and
+// run manifests are written as the CLI writes them.
+type Server struct {
+	// OnRunEnd, if set, is called with the config directory a run's manifest
+	// was saved in and the manifest, as when signing its artifacts.
+	OnRunEnd func(configDir string, manifest runs.Manifest)
+
+	configDir string
+	maxRuns   int
+	play      func(ctx context.Context, r *run) error // Plays a run; replaced in tests
+	users     *Users                                  // Nil when anyone may use the server
+	oidc      *oidcVerifier                           // Nil unless users sign in with OIDC
+
+	ctx    context.Context // Canceled when the server closes, canceling every run
+	cancel context.CancelFunc
+	mu     sync.Mutex
+	runs   map[string]*run
+	order  []string // Run IDs in the order they started
+	active int      // Runs not yet ended
+	wg     sync.WaitGroup
+}
+
+// run is one simulation started through the server.
+type run struct {
+	sim       *simulations.Simulation
+	configDir string // The config directory of whoever started it
+	data      []byte // Scenario TOML exactly as it was run
+	cancel    context.CancelFunc
+	done      chan struct{} // Closed when the run has ended
+	mu        sync.Mutex
+	status    Status
+	canceled  bool
+}
+
+// New creates a server running scenarios from a config directory, at most
+// maxRuns at a time.
+func New(configDir string, maxRuns int) *Server {
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &Server{
+		configDir: configDir,
+		maxRuns:   maxRuns,
+		ctx:       ctx,
+		cancel:    cancel,
+		runs:      make(map[string]*run),
+	}
+	s.play = s.playSimulation
+	return s
+}
+
+// Handler serves the API:
+//
+//	POST   /simulations                 start a run from a StartRequest
+//	GET    /simulations                 list runs
+//	GET    /simulations/{id}            a run's status
+//	GET    /simulations/{id}/chronicle  a run's chronicle, followed until it ends unless ?follow=false
+//	DELETE /simulations/{id}            cancel a run
+//
+// With users (see SetUsers), requests must authenticate, and users only see
+// their own runs.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /simulations", s.start)
+	mux.HandleFunc("GET /simulations", s.list)
+	mux.HandleFunc("GET /simulations/{id}", s.get)
+	mux.HandleFunc("GET /simulations/{id}/chronicle", s.streamChronicle)
+	mux.HandleFunc("DELETE /simulations/{id}", s.delete)
+	if s.users == nil {
+		return mux
+	}
+	return s.authenticate(mux)
+}
+
+// Close cancels every run and waits for them to end.
+func (s *Server) Close() {
+	s.cancel()
+	s.wg.Wait()
+}
+
+// start loads a scenario from the user's config directory and starts running
+// it, unless the user has spent their budget.
+func (s *Server) start(w http.ResponseWriter, r *http.Request) {
+	var req StartRequest
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid request: %w", err))
+		return
+	}
+	name := strings.TrimSuffix(req.Scenario, ".toml")
+	if name == "" || strings.ContainsAny(name, `/\`) || strings.HasPrefix(name, ".") {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("scenario must name a file in the scenarios directory (got %q)", req.Scenario))
+		return
+	}
+
+	user := requestUser(r)
+	configDir := s.configDir
+	if user != nil {
+		configDir = user.configDir(s.configDir)
+	}
+
+	scenarioFile := name + ".toml"
+	data, err := os.ReadFile(path.Join(configDir, "scenarios", scenarioFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			writeError(w, http.StatusNotFound, fmt.Errorf("scenario %s not found", name))
+			return
+		}
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	scenario, err := scenarios.LoadScenario(data)
+	if err != nil {
+		writeError(w, http.StatusUnprocessableEntity, fmt.Errorf("invalid scenario %s: %w", name, err))
+		return
+	}
+
+	// Dry runs cost nothing, so they aren't held to the budget
+	if user != nil && !req.DryRun {
+		if err := user.checkBudget(configDir, time.Now()); err != nil {
+			writeError(w, http.StatusForbidden, err)
+			return
+		}
+	}
+
+	sim := simulations.NewSimulation(scenario, configDir)
+	if req.Speed != "" {
+		speed, err := simulations.ParseSpeedProfile(req.Speed)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		sim.Speed = speed
+	}
+	if req.DryRun {
+		sim.DryRun = &simulations.MockScript{}
+	}
+	sim.Stream = req.Stream
+	sim.ScenarioFile = scenarioFile
+	sim.ScenarioSource = string(data)
+
+	timeout := scenario.Basics.MaxRuntime.ToDuration()
+	if timeout == 0 {
+		timeout = defaultMaxRuntime
+	}
+
+	s.mu.Lock()
+	if s.ctx.Err() != nil {
+		s.mu.Unlock()
+		writeError(w, http.StatusServiceUnavailable, fmt.Errorf("the server is shutting down"))
+		return
+	}
+	if s.active >= s.maxRuns {
+		s.mu.Unlock()
+		writeError(w, http.StatusTooManyRequests, fmt.Errorf("already running %d simulation(s), the most this server runs at once", s.active))
+		return
+	}
+	ctx, cancel := context.WithTimeout(s.ctx, timeout)
+	run := &run{
+		sim:       sim,
+		configDir: configDir,
+		data:      data,
+		cancel:    cancel,
+		done:      make(chan struct{}),
+		status: Status{
+			ID:        sim.ID.String(),
+			User:      userName(user),
+			Scenario:  name,
+			State:     StateInitializing,
+			MaxTurns:  sim.MaxTurns(),
+			StartedAt: time.Now(),
+		},
+	}
+	s.runs[run.status.ID] = run
+	s.order = append(s.order, run.status.ID)
+	s.active++
+	s.wg.Add(1)
+	s.mu.Unlock()
+
+	slog.Info("simulation started", "id", run.status.ID, "scenario", name, "user", run.status.User)
+	go s.execute(ctx, run)
+
+	w.Header().Set("Location", "/simulations/"+run.status.ID)
+	writeJSON(w, http.StatusCreated, run.snapshot())
+}
+
+// execute plays a run, then records its manifest and how it ended.
+func (s *Server) execute(ctx context.Context, r *run) {
+	defer s.wg.Done()
+	defer r.cancel()
+
+	err := s.play(ctx, r)
+
+	status := r.snapshot()
+	if status.Chronicle != "" {
+		manifest := runs.Manifest{
+			SimulationID: status.ID,
+			ScenarioFile: r.sim.ScenarioFile,
+			ScenarioName: r.sim.Scenario.Basics.Name,
+			StartTime:    status.StartedAt,
+			Chronicle:    status.Chronicle,
+			Outcomes:     status.Outcomes,
+			Scenario:     string(r.data),
+		}
+		if saveErr := runs.Save(r.configDir, manifest); saveErr != nil {
+			slog.Warn("failed to save run manifest", "id", status.ID, "error", saveErr)
+		}
+		if s.OnRunEnd != nil {
+			s.OnRunEnd(r.configDir, manifest)
+		}
+	}
+
+	r.mu.Lock()
+	ended := time.Now()
+	r.status.EndedAt = &ended
+	switch {
+	case err == nil:
+		r.status.State = StateFinished
+	case r.canceled || s.ctx.Err() != nil:
+		r.status.State = StateCanceled
+		r.status.Error = err.Error()
+	default:
+		r.status.State = StateFailed
+		r.status.Error = err.Error()
+	}
+	state := r.status.State
+	r.mu.Unlock()
+
+	s.mu.Lock()
+	s.active--
+	s.mu.Unlock()
+	close(r.done)
+	slog.Info("simulation ended", "id", status.ID, "state", state, "error", err)
+}
+
+// playSimulation initializes and runs a simulation as the CLI does, tracking
+// its turns and chronicle in the run's status.
+func (s *Server) playSimulation(ctx context.Context, r *run) error {
+	sim := r.sim
+	knowledge, err := memory.LoadKnowledge(r.configDir, strings.TrimSuffix(sim.ScenarioFile, ".toml"))
+	if err != nil {
+		return err
+	}
+	sim.Knowledge = knowledge
+
+	if err := sim.Initialize(ctx); err != nil {
+		return fmt.Errorf("failed to initialize simulation: %w", err)
+	}
+	defer sim.Close()
+
+	// The chronicle is open by the time the first turn starts
+	sim.OnTurnStart(func(ctx context.Context, turn int) {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.status.Turn = turn
+		r.status.Chronicle = sim.ChroniclePath()
+	})
+	r.mu.Lock()
+	r.status.State = StateRunning
+	r.mu.Unlock()
+
+	err = sim.Start(ctx)
+	r.mu.Lock()
+	r.status.Chronicle = sim.ChroniclePath()
+	r.status.Outcomes = sim.OutcomesPath()
+	r.mu.Unlock()
+	return err
+}
+
+// list writes the status of every run the user started, in the order they started.
+func (s *Server) list(w http.ResponseWriter, r *http.Request) {
+	user := userName(requestUser(r))
+	s.mu.Lock()
+	statuses := make([]Status, 0, len(s.order))
+	for _, id := range s.order {
+		if status := s.runs[id].snapshot(); status.User == user {
+			statuses = append(statuses, status)
+		}
+	}
+	s.mu.Unlock()
+	writeJSON(w, http.StatusOK, statuses)
+}
+
+// get writes a run's status.
+func (s *Server) get(w http.ResponseWriter, r *http.Request) {
+	run, ok := s.lookup(w, r)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, run.snapshot())
+}
+
+// delete cancels a run. The run ends, as canceled, once its turn in progress
+// notices.
+func (s *Server) delete(w http.ResponseWriter, r *http.Request) {
+	run, ok := s.lookup(w, r)
+	if !ok {
+		return
+	}
+	select {
+	case <-run.done:
+		writeError(w, http.StatusConflict, fmt.Errorf("simulation %s has already ended", run.snapshot().ID))
+		return
+	default:
+	}
+
+	run.mu.Lock()
+	run.canceled = true
+	run.mu.Unlock()
+	run.cancel()
+	slog.Info("simulation canceled", "id", run.snapshot().ID)
+	writeJSON(w, http.StatusAccepted, run.snapshot())
+}
+
+// streamChronicle writes a run's chronicle as JSON lines. Unless follow=false,
+// lines are sent as they are written until the run ends.
+func (s *Server) streamChronicle(w http.ResponseWriter, r *http.Request) {
+	run, ok := s.lookup(w, r)
+	if !ok {
+		return
+	}
+	follow := r.URL.Query().Get("follow") != "false"
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.WriteHeader(http.StatusOK)
+	flusher, _ := w.(http.Flusher)
+
+	var file *os.File
+	defer func() {
+		if file != nil {
+			file.Close()
+		}
+	}()
+	var pending []byte // Part of a line still being written
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	for {
+		// Whatever is written before the run ends is read below
+		var ended bool
+		select {
+		case <-run.done:
+			ended = true
+		default:
+		}
+
+		if file == nil {
+			if chroniclePath := run.snapshot().Chronicle; chroniclePath != "" {
+				var err error
+				if file, err = os.Open(chroniclePath); err != nil {
+					slog.Warn("failed to open chronicle", "path", chroniclePath, "error", err)
+					return
+				}
+			}
+		}
+		if file != nil {
+			data, err := io.ReadAll(file)
+			if err != nil {
+				slog.Warn("failed to read chronicle", "error", err)
+				return
+			}
+			pending = append(pending, data...)
+			if end := bytes.LastIndexByte(pending, '\n'); end >= 0 {
+				if _, err := w.Write(pending[:end+1]); err != nil {
+					return
+				}
+				pending = slices.Clone(pending[end+1:])
+				if flusher != nil {
+					flusher.Flush()
+				}
+			}
+		}
+
+		if ended || !follow {
+			return
+		}
+		select {
+		case <-ticker.C:
+		case <-run.done:
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+// lookup finds the run a request names, answering 404 if there is none or
+// another user started it.
+func (s *Server) lookup(w http.ResponseWriter, r *http.Request) (*run, bool) {
+	id := r.PathValue("id")
+	s.mu.Lock()
+	run, ok := s.runs[id]
+	s.mu.Unlock()
+	if ok && run.snapshot().User != userName(requestUser(r)) {
+		ok = false
+	}
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Errorf("simulation %s not found", id))
+	}
+	return run, ok
+}
+
+// snapshot returns a copy of the run's status.
+func (r *run) snapshot() Status {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.status
+}
+
+// writeJSON writes a JSON response.
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		slog.Debug("failed to write response", "error", err)
+	}
+}
+
+// writeError writes an error response: {"error": "..."}.
+func writeError(w http.ResponseWriter, code int, err error) {
+	writeJSON(w, code, map[string]string{"error": err.Error()})
+}
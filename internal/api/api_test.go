@@ -0,0 +1,182 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const dinnerScenario = `
+version = "1.0.0"
+
+[scenario]
+name = "Dinner"
+description = "Pick a restaurant"
+max_runtime = "30m"
+
+[agents.alice]
+character = "pragmatist"
+
+[goals.restaurant]
+description = "Agree on a restaurant"
+priority = 1
+deadline = "10m"
+`
+
+// newTestServer serves the API over a config directory holding the dinner
+// scenario, playing runs with play.
+func newTestServer(t *testing.T, play func(ctx context.Context, r *run) error) (*Server, *httptest.Server) {
+	configDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(configDir, "scenarios"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(configDir, "scenarios", "dinner.toml"), []byte(dinnerScenario), 0644))
+
+	api := New(configDir, 1)
+	api.play = play
+	server := httptest.NewServer(api.Handler())
+	t.Cleanup(func() {
+		server.Close()
+		api.Close()
+	})
+	return api, server
+}
+
+// startRun posts a start request and returns the response and its status.
+func startRun(t *testing.T, server *httptest.Server, body string) (*http.Response, Status) {
+	resp, err := http.Post(server.URL+"/simulations", "application/json", strings.NewReader(body))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	var status Status
+	if resp.StatusCode == http.StatusCreated {
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&status))
+	}
+	return resp, status
+}
+
+// getStatus fetches a run's status.
+func getStatus(t *testing.T, server *httptest.Server, id string) Status {
+	resp, err := http.Get(server.URL + "/simulations/" + id)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var status Status
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&status))
+	return status
+}
+
+// setChronicle records a run's chronicle, as the first turn does.
+func (r *run) setChronicle(path string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.status.State = StateRunning
+	r.status.Turn = 1
+	r.status.Chronicle = path
+}
+
+func TestServer(t *testing.T) {
+	t.Run("rejects bad requests", func(t *testing.T) {
+		_, server := newTestServer(t, func(ctx context.Context, r *run) error { return nil })
+		for body, code := range map[string]int{
+			`{"scenario": "missing"}`:                      http.StatusNotFound,
+			`{"scenario": "../secrets"}`:                   http.StatusBadRequest,
+			`{"scenario": "dinner", "speed": "ludicrous"}`: http.StatusBadRequest,
+			`{"scenario": "dinner", "turbo": true}`:        http.StatusBadRequest,
+			`not json`:                                     http.StatusBadRequest,
+		} {
+			resp, _ := startRun(t, server, body)
+			assert.Equal(t, code, resp.StatusCode, body)
+		}
+
+		resp, err := http.Get(server.URL + "/simulations/nope")
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+
+	t.Run("runs a scenario and streams its chronicle", func(t *testing.T) {
+		chroniclePath := filepath.Join(t.TempDir(), "chronicle.jsonl")
+		release := make(chan struct{})
+		_, server := newTestServer(t, func(ctx context.Context, r *run) error {
+			require.NoError(t, os.WriteFile(chroniclePath, []byte(`{"type":"metadata"}`+"\n"+`{"type":"turn","number":1`), 0644))
+			r.setChronicle(chroniclePath)
+			<-release
+			file, err := os.OpenFile(chroniclePath, os.O_APPEND|os.O_WRONLY, 0644)
+			require.NoError(t, err)
+			defer file.Close()
+			_, err = file.WriteString(`}` + "\n" + `{"type":"end"}` + "\n")
+			return err
+		})
+
+		resp, status := startRun(t, server, `{"scenario": "dinner", "dry_run": true}`)
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		assert.Equal(t, "/simulations/"+status.ID, resp.Header.Get("Location"))
+		assert.Equal(t, "dinner", status.Scenario)
+
+		// Another run waits for this one
+		busy, _ := startRun(t, server, `{"scenario": "dinner"}`)
+		assert.Equal(t, http.StatusTooManyRequests, busy.StatusCode)
+
+		require.Eventually(t, func() bool {
+			return getStatus(t, server, status.ID).Chronicle != ""
+		}, 5*time.Second, 10*time.Millisecond)
+
+		stream, err := http.Get(server.URL + "/simulations/" + status.ID + "/chronicle")
+		require.NoError(t, err)
+		defer stream.Body.Close()
+		assert.Equal(t, "application/x-ndjson", stream.Header.Get("Content-Type"))
+		lines := bufio.NewScanner(stream.Body)
+		require.True(t, lines.Scan())
+		assert.Equal(t, `{"type":"metadata"}`, lines.Text(), "complete lines are sent as they are written")
+
+		close(release)
+		require.True(t, lines.Scan())
+		assert.Equal(t, `{"type":"turn","number":1}`, lines.Text())
+		require.True(t, lines.Scan())
+		assert.Equal(t, `{"type":"end"}`, lines.Text())
+		assert.False(t, lines.Scan(), "the stream ends with the run")
+
+		ended := getStatus(t, server, status.ID)
+		assert.Equal(t, StateFinished, ended.State)
+		assert.NotNil(t, ended.EndedAt)
+
+		resp, err = http.Get(server.URL + "/simulations")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		var list []Status
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&list))
+		require.Len(t, list, 1)
+		assert.Equal(t, status.ID, list[0].ID)
+	})
+
+	t.Run("cancels a run", func(t *testing.T) {
+		_, server := newTestServer(t, func(ctx context.Context, r *run) error {
+			<-ctx.Done()
+			return ctx.Err()
+		})
+		_, status := startRun(t, server, `{"scenario": "dinner.toml"}`)
+
+		cancel := func() int {
+			req, err := http.NewRequest(http.MethodDelete, server.URL+"/simulations/"+status.ID, nil)
+			require.NoError(t, err)
+			resp, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			resp.Body.Close()
+			return resp.StatusCode
+		}
+		assert.Equal(t, http.StatusAccepted, cancel())
+		require.Eventually(t, func() bool {
+			return getStatus(t, server, status.ID).State == StateCanceled
+		}, 5*time.Second, 10*time.Millisecond)
+		assert.Equal(t, "context canceled", getStatus(t, server, status.ID).Error)
+		assert.Equal(t, http.StatusConflict, cancel(), "ended runs can't be canceled")
+	})
+}
@@ -0,0 +1,77 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+// userKey is the request context key of the authenticated user.
+type userKey struct{}
+
+// SetUsers requires every request to authenticate as one of the users, and
+// runs each user's scenarios from their own config directory,
+// <config-dir>/users/<name>, within their budget. Without users, the server
+// is open to anyone who can reach it and runs scenarios from its config directory.
+func (s *Server) SetUsers(users *Users) {
+	s.users = users
+	s.oidc = nil
+	if users != nil && users.OIDC != nil {
+		s.oidc = newOIDCVerifier(users.OIDC)
+	}
+}
+
+// authenticate wraps a handler so requests must carry one of the users' API
+// keys or an OIDC ID token naming one of them, as "Authorization: Bearer <token>".
+func (s *Server) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		token = strings.TrimSpace(token)
+		if !ok || token == "" {
+			unauthorized(w, fmt.Errorf("authentication required: send an API key or ID token as a bearer token"))
+			return
+		}
+
+		user := s.users.byAPIKey(token)
+		if user == nil && s.oidc != nil && strings.Count(token, ".") == 2 {
+			subject, err := s.oidc.verify(r.Context(), token)
+			if err != nil {
+				slog.Info("rejected ID token", "error", err)
+				unauthorized(w, fmt.Errorf("invalid ID token: %w", err))
+				return
+			}
+			if user = s.users.bySubject(subject); user == nil {
+				writeError(w, http.StatusForbidden, fmt.Errorf("%s is not a user of this server", subject))
+				return
+			}
+		}
+		if user == nil {
+			unauthorized(w, fmt.Errorf("invalid API key"))
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), userKey{}, user)))
+	})
+}
+
+// requestUser returns the user a request authenticated as, or nil when the
+// server has no users.
+func requestUser(r *http.Request) *User {
+	user, _ := r.Context().Value(userKey{}).(*User)
+	return user
+}
+
+// userName returns a user's name, or "" for nil.
+func userName(user *User) string {
+	if user == nil {
+		return ""
+	}
+	return user.Name
+}
+
+// unauthorized writes a 401 response asking for a bearer token.
+func unauthorized(w http.ResponseWriter, err error) {
+	w.Header().Set("WWW-Authenticate", `Bearer realm="wonda"`)
+	writeError(w, http.StatusUnauthorized, err)
+}
@@ -0,0 +1,195 @@
+package api
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/poiesic/wonda/internal/usage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func keyDigest(key string) string {
+	digest := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(digest[:])
+}
+
+func TestLoadUsers(t *testing.T) {
+	valid := `version = "1.0.0"
+[users.alice]
+api_key_sha256 = "` + keyDigest("alice-key") + `"
+`
+	tests := []struct {
+		name    string
+		file    string
+		wantErr string
+	}{
+		{name: "valid", file: valid},
+		{name: "missing version", file: strings.Replace(valid, `version = "1.0.0"`, "", 1), wantErr: "missing version"},
+		{name: "no users", file: `version = "1.0.0"`, wantErr: "names no users"},
+		{name: "no credentials", file: "version = \"1.0.0\"\n[users.alice]\nbudget = 5.0\n", wantErr: "api_key_sha256 or oidc_subject is required"},
+		{name: "bad digest", file: "version = \"1.0.0\"\n[users.alice]\napi_key_sha256 = \"secret\"\n", wantErr: "hex SHA-256 digest"},
+		{name: "bad user name", file: strings.Replace(valid, "users.alice", `users."../alice"`, 1), wantErr: "invalid user name"},
+		{name: "shared key", file: valid + "[users.bob]\napi_key_sha256 = \"" + keyDigest("alice-key") + "\"\n", wantErr: "share an API key"},
+		{name: "subject without oidc", file: "version = \"1.0.0\"\n[users.alice]\noidc_subject = \"alice@example.com\"\n", wantErr: "needs an [oidc] section"},
+		{name: "oidc without audience", file: valid + "[oidc]\nissuer = \"https://id.example.com\"\n", wantErr: "issuer and audience are required"},
+		{name: "negative budget", file: valid + "budget = -1.0\n", wantErr: "budget can't be negative"},
+		{name: "bad budget period", file: valid + "budget = 1.0\nbudget_period = \"fortnight\"\n", wantErr: "unknown period"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			usersPath := filepath.Join(t.TempDir(), "users.toml")
+			require.NoError(t, os.WriteFile(usersPath, []byte(tt.file), 0644))
+			users, err := LoadUsers(usersPath)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			alice := users.Users["alice"]
+			assert.Equal(t, "alice", alice.Name)
+			assert.Equal(t, usage.PeriodMonth, alice.BudgetPeriod, "budgets are monthly by default")
+		})
+	}
+}
+
+// newUsersTestServer serves the API for alice and bob, each with the dinner
+// scenario in their own config directory, playing runs with play.
+func newUsersTestServer(t *testing.T, play func(ctx context.Context, r *run) error) (*Server, *httptest.Server) {
+	configDir := t.TempDir()
+	for _, name := range []string{"alice", "bob"} {
+		scenariosDir := filepath.Join(configDir, UsersDir, name, "scenarios")
+		require.NoError(t, os.MkdirAll(scenariosDir, 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(scenariosDir, "dinner.toml"), []byte(dinnerScenario), 0644))
+	}
+	// Only alice has a lunch scenario
+	require.NoError(t, os.WriteFile(filepath.Join(configDir, UsersDir, "alice", "scenarios", "lunch.toml"), []byte(dinnerScenario), 0644))
+
+	users := &Users{Version: "1.0.0", Users: map[string]*User{
+		"alice": {APIKeySHA256: keyDigest("alice-key"), Budget: 1},
+		"bob":   {APIKeySHA256: keyDigest("bob-key")},
+	}}
+	require.NoError(t, users.Validate())
+
+	api := New(configDir, 2)
+	api.play = play
+	api.SetUsers(users)
+	server := httptest.NewServer(api.Handler())
+	t.Cleanup(func() {
+		server.Close()
+		api.Close()
+	})
+	return api, server
+}
+
+// send makes a request with a bearer token, returning the response code and body.
+func send(t *testing.T, method, url, token, body string) (int, http.Header, []byte) {
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	require.NoError(t, err)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	var data json.RawMessage
+	json.NewDecoder(resp.Body).Decode(&data)
+	return resp.StatusCode, resp.Header, data
+}
+
+func TestServerUsers(t *testing.T) {
+	t.Run("requires a user's API key", func(t *testing.T) {
+		_, server := newUsersTestServer(t, func(ctx context.Context, r *run) error { return nil })
+
+		code, header, _ := send(t, http.MethodGet, server.URL+"/simulations", "", "")
+		assert.Equal(t, http.StatusUnauthorized, code)
+		assert.Equal(t, `Bearer realm="wonda"`, header.Get("WWW-Authenticate"))
+
+		code, _, _ = send(t, http.MethodGet, server.URL+"/simulations", "mallory-key", "")
+		assert.Equal(t, http.StatusUnauthorized, code)
+
+		code, _, body := send(t, http.MethodGet, server.URL+"/simulations", "alice-key", "")
+		assert.Equal(t, http.StatusOK, code)
+		assert.JSONEq(t, `[]`, string(body))
+	})
+
+	t.Run("runs each user's scenarios from their own config directory", func(t *testing.T) {
+		dirs := make(chan string, 2)
+		api, server := newUsersTestServer(t, func(ctx context.Context, r *run) error {
+			dirs <- r.configDir
+			<-ctx.Done()
+			return ctx.Err()
+		})
+
+		code, _, _ := send(t, http.MethodPost, server.URL+"/simulations", "bob-key", `{"scenario": "lunch"}`)
+		assert.Equal(t, http.StatusNotFound, code, "bob has no lunch scenario")
+
+		code, _, body := send(t, http.MethodPost, server.URL+"/simulations", "alice-key", `{"scenario": "lunch"}`)
+		require.Equal(t, http.StatusCreated, code)
+		var alices Status
+		require.NoError(t, json.Unmarshal(body, &alices))
+		assert.Equal(t, "alice", alices.User)
+		assert.Equal(t, filepath.Join(api.configDir, UsersDir, "alice"), <-dirs)
+
+		code, _, body = send(t, http.MethodPost, server.URL+"/simulations", "bob-key", `{"scenario": "dinner"}`)
+		require.Equal(t, http.StatusCreated, code)
+		var bobs Status
+		require.NoError(t, json.Unmarshal(body, &bobs))
+		assert.Equal(t, filepath.Join(api.configDir, UsersDir, "bob"), <-dirs)
+
+		// Users only see their own runs
+		_, _, body = send(t, http.MethodGet, server.URL+"/simulations", "bob-key", "")
+		var list []Status
+		require.NoError(t, json.Unmarshal(body, &list))
+		require.Len(t, list, 1)
+		assert.Equal(t, bobs.ID, list[0].ID)
+
+		for _, method := range []string{http.MethodGet, http.MethodDelete} {
+			code, _, _ = send(t, method, server.URL+"/simulations/"+alices.ID, "bob-key", "")
+			assert.Equal(t, http.StatusNotFound, code, "%s of another user's run", method)
+		}
+		code, _, _ = send(t, http.MethodGet, server.URL+"/simulations/"+alices.ID+"/chronicle?follow=false", "bob-key", "")
+		assert.Equal(t, http.StatusNotFound, code)
+
+		code, _, _ = send(t, http.MethodDelete, server.URL+"/simulations/"+alices.ID, "alice-key", "")
+		assert.Equal(t, http.StatusAccepted, code)
+	})
+
+	t.Run("refuses runs once the budget is spent", func(t *testing.T) {
+		api, server := newUsersTestServer(t, func(ctx context.Context, r *run) error { return nil })
+		catalog := usage.CatalogPath(filepath.Join(api.configDir, UsersDir, "alice"))
+		spend := func(cost float64, at time.Time) {
+			require.NoError(t, usage.AppendReport(catalog, usage.Report{
+				StartTime: at,
+				Entries:   []usage.Entry{{Provider: "openai", Model: "gpt-4o", Requests: 1, Cost: cost}},
+			}))
+		}
+
+		// Last year's spending doesn't count against this month's budget
+		spend(5, time.Now().AddDate(-1, 0, 0))
+		spend(0.6, time.Now())
+		code, _, _ := send(t, http.MethodPost, server.URL+"/simulations", "alice-key", `{"scenario": "dinner"}`)
+		assert.Equal(t, http.StatusCreated, code)
+
+		spend(0.6, time.Now())
+		code, _, body := send(t, http.MethodPost, server.URL+"/simulations", "alice-key", `{"scenario": "dinner"}`)
+		assert.Equal(t, http.StatusForbidden, code)
+		assert.Contains(t, string(body), "alice has spent 1.20 of their 1.00 monthly budget")
+
+		code, _, _ = send(t, http.MethodPost, server.URL+"/simulations", "alice-key", `{"scenario": "dinner", "dry_run": true}`)
+		assert.Equal(t, http.StatusCreated, code, "dry runs cost nothing")
+
+		code, _, _ = send(t, http.MethodPost, server.URL+"/simulations", "bob-key", `{"scenario": "dinner"}`)
+		assert.Equal(t, http.StatusCreated, code, "bob has no budget")
+	})
+}
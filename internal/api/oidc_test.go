@@ -0,0 +1,201 @@
+package api
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testIssuer is an OpenID Connect provider serving its discovery document and
+// keys, which signs ID tokens with an RSA and a P-256 key.
+type testIssuer struct {
+	*httptest.Server
+	rsaKey *rsa.PrivateKey
+	ecKey  *ecdsa.PrivateKey
+}
+
+func newTestIssuer(t *testing.T) *testIssuer {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	issuer := &testIssuer{rsaKey: rsaKey, ecKey: ecKey}
+
+	encode := base64.RawURLEncoding.EncodeToString
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{"issuer": issuer.URL, "jwks_uri": issuer.URL + "/keys"})
+	})
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{"keys": []jsonWebKey{
+			{Kty: "RSA", Kid: "rsa", Use: "sig", N: encode(rsaKey.N.Bytes()), E: encode(big.NewInt(int64(rsaKey.E)).Bytes())},
+			{Kty: "EC", Kid: "ec", Crv: "P-256", X: encode(ecKey.X.FillBytes(make([]byte, 32))), Y: encode(ecKey.Y.FillBytes(make([]byte, 32)))},
+			{Kty: "OKP", Kid: "ed", Crv: "Ed25519", X: "ignored"},
+		}})
+	})
+	issuer.Server = httptest.NewServer(mux)
+	t.Cleanup(issuer.Close)
+	return issuer
+}
+
+// sign returns an ID token with the claims, signed with the issuer's key for alg.
+func (i *testIssuer) sign(t *testing.T, alg string, claims map[string]interface{}) string {
+	kid := map[string]string{"RS256": "rsa", "ES256": "ec"}[alg]
+	header, err := json.Marshal(map[string]string{"alg": alg, "kid": kid, "typ": "JWT"})
+	require.NoError(t, err)
+	payload, err := json.Marshal(claims)
+	require.NoError(t, err)
+	signed := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+
+	digest := sha256.Sum256([]byte(signed))
+	var signature []byte
+	if alg == "ES256" {
+		r, s, err := ecdsa.Sign(rand.Reader, i.ecKey, digest[:])
+		require.NoError(t, err)
+		signature = append(r.FillBytes(make([]byte, 32)), s.FillBytes(make([]byte, 32))...)
+	} else {
+		signature, err = rsa.SignPKCS1v15(rand.Reader, i.rsaKey, crypto.SHA256, digest[:])
+		require.NoError(t, err)
+	}
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+// claims returns valid claims for the issuer, overridden by changes.
+func (i *testIssuer) claims(changes map[string]interface{}) map[string]interface{} {
+	now := time.Now()
+	claims := map[string]interface{}{
+		"iss":   i.URL,
+		"aud":   "wonda",
+		"sub":   "1234",
+		"email": "alice@example.com",
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	}
+	for name, value := range changes {
+		if value == nil {
+			delete(claims, name)
+		} else {
+			claims[name] = value
+		}
+	}
+	return claims
+}
+
+func TestOIDCVerifier(t *testing.T) {
+	issuer := newTestIssuer(t)
+	hourAgo := time.Now().Add(-time.Hour).Unix()
+
+	tests := []struct {
+		name    string
+		alg     string
+		changes map[string]interface{}
+		want    string
+		wantErr string
+	}{
+		{name: "RS256", alg: "RS256", want: "alice@example.com"},
+		{name: "ES256", alg: "ES256", want: "alice@example.com"},
+		{name: "audience list", alg: "RS256", changes: map[string]interface{}{"aud": []string{"other", "wonda"}}, want: "alice@example.com"},
+		{name: "issuer with trailing slash", alg: "RS256", changes: map[string]interface{}{"iss": issuer.URL + "/"}, want: "alice@example.com"},
+		{name: "wrong issuer", alg: "RS256", changes: map[string]interface{}{"iss": "https://evil.example.com"}, wantErr: "token issued by"},
+		{name: "wrong audience", alg: "RS256", changes: map[string]interface{}{"aud": "other"}, wantErr: `token not issued for "wonda"`},
+		{name: "expired", alg: "ES256", changes: map[string]interface{}{"exp": hourAgo}, wantErr: "token expired"},
+		{name: "no expiry", alg: "RS256", changes: map[string]interface{}{"exp": nil}, wantErr: "token expired"},
+		{name: "not valid yet", alg: "RS256", changes: map[string]interface{}{"nbf": time.Now().Add(time.Hour).Unix()}, wantErr: "token not valid yet"},
+		{name: "no claim", alg: "RS256", changes: map[string]interface{}{"email": nil}, wantErr: "token has no email claim"},
+	}
+
+	verifier := newOIDCVerifier(&OIDCConfig{Issuer: issuer.URL, Audience: "wonda", Claim: "email"})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			subject, err := verifier.verify(context.Background(), issuer.sign(t, tt.alg, issuer.claims(tt.changes)))
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, subject)
+		})
+	}
+
+	t.Run("rejects a tampered token", func(t *testing.T) {
+		token := issuer.sign(t, "RS256", issuer.claims(nil))
+		forged := issuer.sign(t, "RS256", issuer.claims(map[string]interface{}{"email": "mallory@example.com"}))
+		header, _, signature := cut(token)
+		_, payload, _ := cut(forged)
+		_, err := verifier.verify(context.Background(), header+"."+payload+"."+signature)
+		assert.ErrorContains(t, err, "invalid token signature")
+	})
+
+	t.Run("rejects a key of the wrong type", func(t *testing.T) {
+		token := issuer.sign(t, "RS256", issuer.claims(nil))
+		_, payload, signature := cut(token)
+		header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"ES256","kid":"rsa"}`))
+		_, err := verifier.verify(context.Background(), header+"."+payload+"."+signature)
+		assert.ErrorContains(t, err, "doesn't match its key")
+	})
+
+	t.Run("refetches keys at most once a minute", func(t *testing.T) {
+		header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"RS256","kid":"unknown"}`))
+		_, payload, signature := cut(issuer.sign(t, "RS256", issuer.claims(nil)))
+		_, err := verifier.verify(context.Background(), header+"."+payload+"."+signature)
+		assert.ErrorContains(t, err, `unknown signing key "unknown"`)
+
+		issuer.Close()
+		_, err = verifier.verify(context.Background(), issuer.sign(t, "RS256", issuer.claims(nil)))
+		assert.NoError(t, err, "known keys don't need the issuer")
+
+		verifier.now = func() time.Time { return time.Now().Add(2 * jwksRefreshInterval) }
+		_, err = verifier.verify(context.Background(), header+"."+payload+"."+signature)
+		assert.ErrorContains(t, err, "failed to fetch the issuer's keys")
+	})
+}
+
+// cut splits a token into its header, payload and signature.
+func cut(token string) (string, string, string) {
+	parts := strings.SplitN(token, ".", 3)
+	return parts[0], parts[1], parts[2]
+}
+
+func TestServerOIDC(t *testing.T) {
+	issuer := newTestIssuer(t)
+	users := &Users{
+		Version: "1.0.0",
+		OIDC:    &OIDCConfig{Issuer: issuer.URL, Audience: "wonda", Claim: "email"},
+		Users:   map[string]*User{"alice": {OIDCSubject: "alice@example.com"}},
+	}
+	require.NoError(t, users.Validate())
+
+	api := New(t.TempDir(), 1)
+	api.SetUsers(users)
+	server := httptest.NewServer(api.Handler())
+	t.Cleanup(func() {
+		server.Close()
+		api.Close()
+	})
+
+	code, _, _ := send(t, http.MethodGet, server.URL+"/simulations", issuer.sign(t, "RS256", issuer.claims(nil)), "")
+	assert.Equal(t, http.StatusOK, code)
+
+	code, header, _ := send(t, http.MethodGet, server.URL+"/simulations", issuer.sign(t, "RS256", issuer.claims(map[string]interface{}{"aud": "other"})), "")
+	assert.Equal(t, http.StatusUnauthorized, code)
+	assert.Equal(t, `Bearer realm="wonda"`, header.Get("WWW-Authenticate"))
+
+	code, _, body := send(t, http.MethodGet, server.URL+"/simulations", issuer.sign(t, "ES256", issuer.claims(map[string]interface{}{"email": "bob@example.com"})), "")
+	assert.Equal(t, http.StatusForbidden, code)
+	assert.Contains(t, string(body), "bob@example.com is not a user of this server")
+}
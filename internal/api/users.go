@@ -0,0 +1,196 @@
+package api
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path"
+	"regexp"
+	"time"
+
+	"github.com/pelletier/go-toml/v2"
+	"github.com/poiesic/wonda/internal/config"
+	"github.com/poiesic/wonda/internal/usage"
+)
+
+// UsersDir is the directory under the server's config directory holding each
+// user's config directory when the server has users.
+const UsersDir = "users"
+
+// validUserName matches user names, which name their config directories.
+var validUserName = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9_.-]*$`)
+
+// Users is who may use a server, loaded from a users file:
+//
+//	version = "1.0.0"
+//
+//	[oidc]
+//	issuer = "https://accounts.example.com"
+//	audience = "wonda"
+//	claim = "email"
+//
+//	[users.alice]
+//	api_key_sha256 = "..."
+//	oidc_subject = "alice@example.com"
+//	budget = 20.0
+//	budget_period = "month"
+//
+// Each user's scenarios, characters, providers and usage are kept in their
+// own config directory, <config-dir>/users/<name>.
+type Users struct {
+	Version string           `toml:"version"`
+	OIDC    *OIDCConfig      `toml:"oidc"`  // Optional: accept ID tokens from an OpenID Connect provider
+	Users   map[string]*User `toml:"users"` // By user name
+}
+
+// OIDCConfig sets which OpenID Connect ID tokens the server accepts.
+type OIDCConfig struct {
+	Issuer   string `toml:"issuer"`   // Issuer URL, whose discovery document names its keys
+	Audience string `toml:"audience"` // Client ID the tokens must be issued for
+	Claim    string `toml:"claim"`    // Optional: claim naming the user, matched against oidc_subject (default "sub")
+}
+
+// User is one user of a server.
+type User struct {
+	Name         string  `toml:"-"`
+	APIKeySHA256 string  `toml:"api_key_sha256"` // Optional: hex SHA-256 of the user's API key
+	OIDCSubject  string  `toml:"oidc_subject"`   // Optional: the user's OIDC claim value
+	Budget       float64 `toml:"budget"`         // Optional: most the user's runs may cost per budget period (0 is no limit)
+	BudgetPeriod string  `toml:"budget_period"`  // Optional: day, week, month or all (default month)
+
+	apiKey []byte // Decoded APIKeySHA256
+}
+
+// LoadUsers loads and validates a users file.
+func LoadUsers(filePath string) (*Users, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	var users Users
+	if err := toml.Unmarshal(data, &users); err != nil {
+		return nil, fmt.Errorf("invalid users file: %w", err)
+	}
+	if err := users.Validate(); err != nil {
+		return nil, err
+	}
+	return &users, nil
+}
+
+// Validate checks the users file and fills in defaults.
+func (u *Users) Validate() error {
+	if err := config.ValidateVersion("users", u.Version); err != nil {
+		return err
+	}
+	if len(u.Users) == 0 {
+		return fmt.Errorf("users file names no users")
+	}
+	if u.OIDC != nil {
+		if u.OIDC.Issuer == "" || u.OIDC.Audience == "" {
+			return fmt.Errorf("oidc: issuer and audience are required")
+		}
+		if u.OIDC.Claim == "" {
+			u.OIDC.Claim = "sub"
+		}
+	}
+
+	keys := make(map[string]string)
+	subjects := make(map[string]string)
+	for name, user := range u.Users {
+		user.Name = name
+		if !validUserName.MatchString(name) {
+			return fmt.Errorf("invalid user name '%s': must start with a letter and contain only letters, digits, '.', '-' or '_'", name)
+		}
+		if user.APIKeySHA256 == "" && user.OIDCSubject == "" {
+			return fmt.Errorf("user %s: api_key_sha256 or oidc_subject is required", name)
+		}
+		if user.APIKeySHA256 != "" {
+			key, err := hex.DecodeString(user.APIKeySHA256)
+			if err != nil || len(key) != sha256.Size {
+				return fmt.Errorf("user %s: api_key_sha256 must be a hex SHA-256 digest", name)
+			}
+			if other, ok := keys[string(key)]; ok {
+				return fmt.Errorf("users %s and %s share an API key", other, name)
+			}
+			keys[string(key)] = name
+			user.apiKey = key
+		}
+		if user.OIDCSubject != "" {
+			if u.OIDC == nil {
+				return fmt.Errorf("user %s: oidc_subject needs an [oidc] section", name)
+			}
+			if other, ok := subjects[user.OIDCSubject]; ok {
+				return fmt.Errorf("users %s and %s share an OIDC subject", other, name)
+			}
+			subjects[user.OIDCSubject] = name
+		}
+		if user.Budget < 0 {
+			return fmt.Errorf("user %s: budget can't be negative", name)
+		}
+		if user.BudgetPeriod == "" {
+			user.BudgetPeriod = usage.PeriodMonth
+		}
+		if _, err := usage.Spent(nil, user.BudgetPeriod, time.Now()); err != nil {
+			return fmt.Errorf("user %s: budget_period: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// byAPIKey returns the user with the API key, comparing every user's digest
+// in constant time.
+func (u *Users) byAPIKey(key string) *User {
+	digest := sha256.Sum256([]byte(key))
+	var found *User
+	for _, user := range u.Users {
+		if user.apiKey != nil && subtle.ConstantTimeCompare(user.apiKey, digest[:]) == 1 {
+			found = user
+		}
+	}
+	return found
+}
+
+// bySubject returns the user with the OIDC claim value.
+func (u *Users) bySubject(subject string) *User {
+	for _, user := range u.Users {
+		if user.OIDCSubject != "" && user.OIDCSubject == subject {
+			return user
+		}
+	}
+	return nil
+}
+
+// configDir returns the user's config directory under the server's.
+func (user *User) configDir(serverConfigDir string) string {
+	return path.Join(serverConfigDir, UsersDir, user.Name)
+}
+
+// checkBudget returns an error when the user's runs have spent their budget
+// for the current period, according to the usage catalog in their config directory.
+func (user *User) checkBudget(configDir string, now time.Time) error {
+	if user.Budget == 0 {
+		return nil
+	}
+	reports, err := usage.LoadReports(usage.CatalogPath(configDir))
+	if err != nil {
+		return fmt.Errorf("failed to read usage: %w", err)
+	}
+	spent, err := usage.Spent(reports, user.BudgetPeriod, now)
+	if err != nil {
+		return err
+	}
+	if spent >= user.Budget {
+		return fmt.Errorf("%s has spent %.2f of their %.2f %s budget", user.Name, spent, user.Budget, budgetNames[user.BudgetPeriod])
+	}
+	return nil
+}
+
+// budgetNames describes budgets by period for messages.
+var budgetNames = map[string]string{
+	usage.PeriodDay:   "daily",
+	usage.PeriodWeek:  "weekly",
+	usage.PeriodMonth: "monthly",
+	usage.PeriodAll:   "total",
+}
@@ -0,0 +1,207 @@
+// Package batch runs a scenario many times, optionally sweeping the models,
+// temperatures and characters it runs with, and aggregates how the runs went:
+// how often their goals were all met, how many turns that took, and the cost.
+package batch
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	mcpsim "github.com/poiesic/wonda/internal/mcp/simulation"
+	"github.com/poiesic/wonda/internal/memory"
+	"github.com/poiesic/wonda/internal/runs"
+	"github.com/poiesic/wonda/internal/scenarios"
+	"github.com/poiesic/wonda/internal/simulations"
+)
+
+// defaultMaxRuntime bounds runs whose scenario sets no max_runtime, as the CLI does.
+const defaultMaxRuntime = 30 * time.Minute
+
+// Batch runs a scenario from a config directory several times.
+type Batch struct {
+	ConfigDir    string
+	ScenarioFile string // File in the config directory's scenarios/, with .toml
+	Runs         int    // Runs of each variant
+	Parallel     int    // Most runs played at once
+	Sweep        *Sweep // Variants to run (nil runs the scenario as it is)
+	OutputDir    string // Where each run's chronicle directory is created
+
+	// Set on every run, as 'scenarios run' sets them
+	DryRun *simulations.MockScript
+	Speed  *simulations.SpeedProfile
+
+	// OnRunEnd, if set, is called with each run's manifest once it is saved,
+	// as when signing its artifacts.
+	OnRunEnd func(runs.Manifest)
+}
+
+// job is one run of a variant.
+type job struct {
+	variant Variant
+	number  int
+	data    []byte // Scenario definition as the variant runs it
+}
+
+// Run plays every run and reports how they went. Every variant is checked
+// before any run starts; a run that fails is recorded in the report, and
+// the rest carry on. Canceling the context cancels the runs in progress and
+// leaves the rest unplayed.
+func (b *Batch) Run(ctx context.Context) (*Report, error) {
+	scenarioPath := path.Join(b.ConfigDir, "scenarios", b.ScenarioFile)
+	data, err := os.ReadFile(scenarioPath)
+	if err != nil {
+		return nil, err
+	}
+
+	variants := []Variant{{Name: DefaultVariant}}
+	if b.Sweep != nil {
+		variants = b.Sweep.Variants
+	}
+
+	// Catch a bad model or character name before spending on any run
+	var jobs []job
+	var scenarioName string
+	for _, variant := range variants {
+		variantData, err := variant.Apply(data)
+		if err != nil {
+			return nil, err
+		}
+		scenario, err := scenarios.LoadScenario(variantData)
+		if err != nil {
+			return nil, fmt.Errorf("variant %s: %w", variant.Name, err)
+		}
+		if err := simulations.NewSimulation(scenario, b.ConfigDir).Validate(); err != nil {
+			return nil, fmt.Errorf("variant %s: %w", variant.Name, err)
+		}
+		scenarioName = scenario.Basics.Name
+		for number := 1; number <= max(b.Runs, 1); number++ {
+			jobs = append(jobs, job{variant: variant, number: number, data: variantData})
+		}
+	}
+
+	report := &Report{Scenario: scenarioName, ScenarioFile: b.ScenarioFile, Runs: make([]Result, len(jobs))}
+	slots := make(chan struct{}, max(b.Parallel, 1))
+	var wg sync.WaitGroup
+	for i, job := range jobs {
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			report.Runs[i] = Result{Variant: job.variant.Name, Run: job.number, Error: ctx.Err().Error()}
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-slots }()
+			report.Runs[i] = b.play(ctx, job)
+		}()
+	}
+	wg.Wait()
+
+	report.Variants = summarize(variants, report.Runs)
+	return report, nil
+}
+
+// play runs a job's simulation in its own directory and records its manifest.
+func (b *Batch) play(ctx context.Context, j job) Result {
+	result := Result{Variant: j.variant.Name, Run: j.number}
+	slog.Info("batch run starting", "variant", j.variant.Name, "run", j.number)
+
+	scenario, err := scenarios.LoadScenario(j.data)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	sim := simulations.NewSimulation(scenario, b.ConfigDir)
+	sim.ScenarioFile = b.ScenarioFile
+	sim.ScenarioSource = string(j.data)
+	sim.DryRun = b.DryRun
+	sim.Speed = b.Speed
+	sim.Temperature = j.variant.Temperature
+	sim.OutputDir = filepath.Join(b.OutputDir, fmt.Sprintf("%s-%03d", j.variant.Name, j.number))
+	result.SimulationID = sim.ID.String()
+
+	startTime := time.Now()
+	err = b.simulate(ctx, sim)
+	result.Chronicle = sim.ChroniclePath()
+	result.Outcomes = sim.OutcomesPath()
+	result.Cost = sim.Usage.Total().Cost
+	result.Seconds = time.Since(startTime).Seconds()
+	if err != nil {
+		result.Error = err.Error()
+	}
+
+	world := sim.World.Snapshot()
+	result.Turns = world.CurrentTurn
+	result.Goals = len(world.Goals)
+	for _, goal := range world.Goals {
+		if goal.Status != mcpsim.GoalCompleted {
+			continue
+		}
+		result.GoalsCompleted++
+		result.TurnsToCompletion = max(result.TurnsToCompletion, goal.CompletedAt)
+	}
+	result.Consensus = result.Goals > 0 && result.GoalsCompleted == result.Goals
+	if !result.Consensus {
+		result.TurnsToCompletion = 0
+	}
+	result.Success = result.Consensus
+	if success := sim.Success(); success != nil {
+		result.Success = success.Passed
+	}
+
+	if result.Chronicle != "" {
+		manifest := runs.Manifest{
+			SimulationID: result.SimulationID,
+			ScenarioFile: b.ScenarioFile,
+			ScenarioName: scenario.Basics.Name,
+			StartTime:    startTime,
+			Chronicle:    result.Chronicle,
+			Outcomes:     result.Outcomes,
+			Scenario:     string(j.data),
+		}
+		if saveErr := runs.Save(b.ConfigDir, manifest); saveErr != nil {
+			slog.Warn("failed to save run manifest", "id", result.SimulationID, "error", saveErr)
+		}
+		if b.OnRunEnd != nil {
+			b.OnRunEnd(manifest)
+		}
+	}
+
+	slog.Info("batch run ended", "variant", j.variant.Name, "run", j.number, "consensus", result.Consensus, "success", result.Success, "turns", result.Turns, "error", err)
+	return result
+}
+
+// simulate initializes and runs a simulation as the CLI does.
+func (b *Batch) simulate(ctx context.Context, sim *simulations.Simulation) error {
+	if err := os.MkdirAll(sim.OutputDir, 0755); err != nil {
+		return fmt.Errorf("failed to create run directory: %w", err)
+	}
+	knowledge, err := memory.LoadKnowledge(b.ConfigDir, strings.TrimSuffix(b.ScenarioFile, ".toml"))
+	if err != nil {
+		return err
+	}
+	sim.Knowledge = knowledge
+
+	timeout := sim.Scenario.Basics.MaxRuntime.ToDuration()
+	if timeout == 0 {
+		timeout = defaultMaxRuntime
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	if err := sim.Initialize(ctx); err != nil {
+		return fmt.Errorf("failed to initialize simulation: %w", err)
+	}
+	defer sim.Close()
+	return sim.Start(ctx)
+}
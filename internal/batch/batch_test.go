@@ -0,0 +1,141 @@
+package batch
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/poiesic/wonda/internal/scenarios"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testScenario = `version = "1.0.0"
+
+[scenario]
+name = "Dinner"
+max_turns = 10
+
+[scenario.defaults]
+model = "claude-haiku"
+
+[agents.Alice]
+character = "alice"
+
+[agents.Bob]
+character = "bob"
+model = "gpt-4o"
+
+[agents.Carol]
+character = "carol"
+controller = "human"
+
+[goals.restaurant]
+description = "Pick a restaurant"
+type = "ConsensusGoal"
+assignment = ["Alice", "Bob"]
+`
+
+func TestLoadSweep(t *testing.T) {
+	write := func(t *testing.T, content string) string {
+		path := filepath.Join(t.TempDir(), "sweep.toml")
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+		return path
+	}
+
+	t.Run("loads variants", func(t *testing.T) {
+		sweep, err := LoadSweep(write(t, `
+[[variants]]
+name = "cool"
+model = "claude-sonnet"
+temperature = 0.2
+
+[[variants]]
+name = "skeptic"
+characters = { Alice = "alice-skeptic" }
+`))
+		require.NoError(t, err)
+		require.Len(t, sweep.Variants, 2)
+		assert.Equal(t, "claude-sonnet", sweep.Variants[0].Model)
+		assert.InDelta(t, 0.2, *sweep.Variants[0].Temperature, 1e-9)
+		assert.Equal(t, map[string]string{"Alice": "alice-skeptic"}, sweep.Variants[1].Characters)
+	})
+
+	for name, content := range map[string]string{
+		"no variants":      ``,
+		"unnamed":          "[[variants]]\nmodel = \"x\"\n",
+		"duplicate names":  "[[variants]]\nname = \"a\"\n[[variants]]\nname = \"a\"\n",
+		"path in name":     "[[variants]]\nname = \"../a\"\n",
+		"temperature high": "[[variants]]\nname = \"a\"\ntemperature = 2.5\n",
+	} {
+		t.Run("rejects "+name, func(t *testing.T) {
+			_, err := LoadSweep(write(t, content))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestVariantApply(t *testing.T) {
+	t.Run("leaves the scenario as it is without changes", func(t *testing.T) {
+		data, err := Variant{Name: "default", Temperature: new(float64)}.Apply([]byte(testScenario))
+		require.NoError(t, err)
+		assert.Equal(t, testScenario, string(data))
+	})
+
+	t.Run("sets the model and characters", func(t *testing.T) {
+		variant := Variant{Name: "v", Model: "claude-sonnet", Characters: map[string]string{"Bob": "bob-grumpy"}}
+		data, err := variant.Apply([]byte(testScenario))
+		require.NoError(t, err)
+
+		scenario, err := scenarios.LoadScenario(data)
+		require.NoError(t, err)
+		assert.Equal(t, "claude-sonnet", scenario.Basics.Defaults.Model)
+		assert.Equal(t, "claude-sonnet", scenario.Agents["Alice"].Model)
+		assert.Equal(t, "claude-sonnet", scenario.Agents["Bob"].Model)
+		assert.Empty(t, scenario.Agents["Carol"].Model, "human agents have no model")
+		assert.Equal(t, "bob-grumpy", scenario.Agents["Bob"].Character)
+		assert.Equal(t, "alice", scenario.Agents["Alice"].Character)
+		assert.Equal(t, "Dinner", scenario.Basics.Name)
+	})
+
+	t.Run("rejects characters for unknown agents", func(t *testing.T) {
+		_, err := Variant{Name: "v", Characters: map[string]string{"Zed": "zed"}}.Apply([]byte(testScenario))
+		assert.ErrorContains(t, err, "agent Zed is not in the scenario")
+	})
+}
+
+func TestReport(t *testing.T) {
+	temperature := 0.5
+	variants := []Variant{{Name: "a", Model: "m", Temperature: &temperature}, {Name: "b"}}
+	report := &Report{Runs: []Result{
+		{Variant: "a", Run: 1, Turns: 4, Goals: 1, GoalsCompleted: 1, Consensus: true, TurnsToCompletion: 4, Success: true, Cost: 0.25},
+		{Variant: "a", Run: 2, Turns: 10, Goals: 1, Cost: 0.5},
+		{Variant: "a", Run: 3, Turns: 6, Goals: 1, GoalsCompleted: 1, Consensus: true, TurnsToCompletion: 6, Cost: 0.25},
+		{Variant: "b", Run: 1, Error: "failed to initialize simulation: boom"},
+	}}
+	report.Variants = summarize(variants, report.Runs)
+
+	require.Len(t, report.Variants, 2)
+	a := report.Variants[0]
+	assert.Equal(t, 3, a.Runs)
+	assert.Equal(t, 0, a.Failed)
+	assert.InDelta(t, 2.0/3, a.ConsensusRate, 1e-9)
+	assert.InDelta(t, 1.0/3, a.SuccessRate, 1e-9)
+	assert.InDelta(t, 5, a.MeanTurnsToCompletion, 1e-9)
+	assert.InDelta(t, 1, a.TotalCost, 1e-9)
+	b := report.Variants[1]
+	assert.Equal(t, 1, b.Failed)
+	assert.Zero(t, b.ConsensusRate)
+
+	var buf bytes.Buffer
+	require.NoError(t, report.WriteSummaryCSV(&buf))
+	assert.Equal(t, `variant,model,temperature,runs,failed,consensus_rate,success_rate,mean_turns_to_completion,mean_cost,total_cost
+a,m,0.5,3,0,0.6667,0.3333,5,0.3333,1
+b,,,1,1,0,0,0,0,0
+`, buf.String())
+
+	buf.Reset()
+	require.NoError(t, report.WriteRunsCSV(&buf))
+	assert.Contains(t, buf.String(), "b,1,,0,0,0,false,0,false,0,0.0,,failed to initialize simulation: boom\n")
+}
@@ -0,0 +1,173 @@
+package batch
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"math"
+	"strconv"
+)
+
+// Report is how a batch's runs went, run by run and variant by variant.
+type Report struct {
+	Scenario     string    `json:"scenario"`
+	ScenarioFile string    `json:"scenario_file"`
+	Variants     []Summary `json:"variants"`
+	Runs         []Result  `json:"runs"` // By variant, then run
+}
+
+// Result is how one run went.
+type Result struct {
+	Variant           string  `json:"variant"`
+	Run               int     `json:"run"` // From 1, within the variant
+	SimulationID      string  `json:"simulation_id,omitempty"`
+	Chronicle         string  `json:"chronicle,omitempty"`
+	Outcomes          string  `json:"outcomes,omitempty"`
+	Turns             int     `json:"turns"`
+	Goals             int     `json:"goals"`
+	GoalsCompleted    int     `json:"goals_completed"`
+	Consensus         bool    `json:"consensus"`                     // Every goal was completed
+	TurnsToCompletion int     `json:"turns_to_completion,omitempty"` // Turn the last goal was completed, with consensus
+	Success           bool    `json:"success"`                       // Met the scenario's success criteria, or without any, reached consensus
+	Cost              float64 `json:"cost"`
+	Seconds           float64 `json:"seconds"`
+	Error             string  `json:"error,omitempty"`
+}
+
+// Summary aggregates a variant's runs. Failed runs count against the
+// consensus and success rates and towards the cost.
+type Summary struct {
+	Variant               string            `json:"variant"`
+	Model                 string            `json:"model,omitempty"`
+	Temperature           *float64          `json:"temperature,omitempty"`
+	Characters            map[string]string `json:"characters,omitempty"`
+	Runs                  int               `json:"runs"`
+	Failed                int               `json:"failed"`
+	ConsensusRate         float64           `json:"consensus_rate"`                     // Share of runs that completed every goal
+	SuccessRate           float64           `json:"success_rate"`                       // Share of runs that succeeded
+	MeanTurnsToCompletion float64           `json:"mean_turns_to_completion,omitempty"` // Over the runs with consensus
+	MeanCost              float64           `json:"mean_cost"`
+	TotalCost             float64           `json:"total_cost"`
+}
+
+// summarize aggregates the runs of each variant, in the sweep's order.
+func summarize(variants []Variant, results []Result) []Summary {
+	summaries := make([]Summary, 0, len(variants))
+	for _, variant := range variants {
+		summary := Summary{
+			Variant:     variant.Name,
+			Model:       variant.Model,
+			Temperature: variant.Temperature,
+			Characters:  variant.Characters,
+		}
+		consensus, succeeded, turns := 0, 0, 0
+		for _, result := range results {
+			if result.Variant != variant.Name {
+				continue
+			}
+			summary.Runs++
+			if result.Error != "" {
+				summary.Failed++
+			}
+			if result.Consensus {
+				consensus++
+				turns += result.TurnsToCompletion
+			}
+			if result.Success {
+				succeeded++
+			}
+			summary.TotalCost += result.Cost
+		}
+		if summary.Runs > 0 {
+			summary.ConsensusRate = float64(consensus) / float64(summary.Runs)
+			summary.SuccessRate = float64(succeeded) / float64(summary.Runs)
+			summary.MeanCost = summary.TotalCost / float64(summary.Runs)
+		}
+		if consensus > 0 {
+			summary.MeanTurnsToCompletion = float64(turns) / float64(consensus)
+		}
+		summaries = append(summaries, summary)
+	}
+	return summaries
+}
+
+// WriteJSON writes the report as indented JSON.
+func (r *Report) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(r)
+}
+
+// SummaryCSVHeader names the columns WriteSummaryCSV writes.
+var SummaryCSVHeader = []string{"variant", "model", "temperature", "runs", "failed", "consensus_rate", "success_rate", "mean_turns_to_completion", "mean_cost", "total_cost"}
+
+// WriteSummaryCSV writes one row per variant. Variants that don't set a
+// model or temperature leave them empty.
+func (r *Report) WriteSummaryCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(SummaryCSVHeader); err != nil {
+		return err
+	}
+	for _, summary := range r.Variants {
+		var temperature string
+		if summary.Temperature != nil {
+			temperature = formatFloat(*summary.Temperature)
+		}
+		row := []string{
+			summary.Variant,
+			summary.Model,
+			temperature,
+			strconv.Itoa(summary.Runs),
+			strconv.Itoa(summary.Failed),
+			formatFloat(summary.ConsensusRate),
+			formatFloat(summary.SuccessRate),
+			formatFloat(summary.MeanTurnsToCompletion),
+			formatFloat(summary.MeanCost),
+			formatFloat(summary.TotalCost),
+		}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// RunsCSVHeader names the columns WriteRunsCSV writes.
+var RunsCSVHeader = []string{"variant", "run", "simulation_id", "turns", "goals", "goals_completed", "consensus", "turns_to_completion", "success", "cost", "seconds", "chronicle", "error"}
+
+// WriteRunsCSV writes one row per run.
+func (r *Report) WriteRunsCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(RunsCSVHeader); err != nil {
+		return err
+	}
+	for _, result := range r.Runs {
+		row := []string{
+			result.Variant,
+			strconv.Itoa(result.Run),
+			result.SimulationID,
+			strconv.Itoa(result.Turns),
+			strconv.Itoa(result.Goals),
+			strconv.Itoa(result.GoalsCompleted),
+			strconv.FormatBool(result.Consensus),
+			strconv.Itoa(result.TurnsToCompletion),
+			strconv.FormatBool(result.Success),
+			formatFloat(result.Cost),
+			strconv.FormatFloat(result.Seconds, 'f', 1, 64),
+			result.Chronicle,
+			result.Error,
+		}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// formatFloat writes a number with no more precision than it has, up to
+// four decimal places.
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(math.Round(f*10000)/10000, 'f', -1, 64)
+}
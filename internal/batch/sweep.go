@@ -0,0 +1,119 @@
+package batch
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"regexp"
+	"slices"
+
+	"github.com/pelletier/go-toml/v2"
+	"github.com/poiesic/wonda/internal/scenarios"
+)
+
+// DefaultVariant names the only variant of a batch run without a sweep.
+const DefaultVariant = "default"
+
+// namePattern is what variant names may use, since they name run directories.
+var namePattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// Sweep varies the runs of a batch: the scenario is run the batch's number of
+// times under each variant.
+type Sweep struct {
+	Variants []Variant `toml:"variants"`
+}
+
+// Variant is one way of running the scenario.
+type Variant struct {
+	Name        string            `toml:"name"`
+	Model       string            `toml:"model"`       // Optional: model for the scenario's default and every agent played by a model
+	Temperature *float64          `toml:"temperature"` // Optional: sampling temperature for every model the run uses
+	Characters  map[string]string `toml:"characters"`  // Optional: character each listed agent plays instead
+}
+
+// LoadSweep loads a sweep from a TOML file.
+func LoadSweep(path string) (*Sweep, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var sweep Sweep
+	if err := toml.Unmarshal(data, &sweep); err != nil {
+		return nil, fmt.Errorf("invalid sweep: %w", err)
+	}
+	if err := sweep.Validate(); err != nil {
+		return nil, err
+	}
+	return &sweep, nil
+}
+
+// Validate checks the sweep has variants with distinct names and
+// temperatures in range.
+func (s *Sweep) Validate() error {
+	if len(s.Variants) == 0 {
+		return fmt.Errorf("sweep has no variants")
+	}
+	var names []string
+	for i, variant := range s.Variants {
+		if variant.Name == "" {
+			return fmt.Errorf("sweep variant %d has no name", i+1)
+		}
+		if !namePattern.MatchString(variant.Name) {
+			return fmt.Errorf("invalid sweep variant name %q: use letters, digits, '-' and '_'", variant.Name)
+		}
+		if slices.Contains(names, variant.Name) {
+			return fmt.Errorf("sweep variant %s is defined more than once", variant.Name)
+		}
+		names = append(names, variant.Name)
+		if t := variant.Temperature; t != nil && (*t < 0 || *t > 2) {
+			return fmt.Errorf("sweep variant %s: temperature must be between 0 and 2 (got %g)", variant.Name, *t)
+		}
+	}
+	return nil
+}
+
+// Apply returns the scenario definition as the variant runs it. Definitions
+// the variant doesn't change are returned as they are; changed ones are
+// re-encoded, so they lose their comments but still record exactly what ran.
+func (v Variant) Apply(data []byte) ([]byte, error) {
+	if v.Model == "" && len(v.Characters) == 0 {
+		return data, nil
+	}
+
+	var definition map[string]any
+	if err := toml.Unmarshal(data, &definition); err != nil {
+		return nil, err
+	}
+	agents, _ := definition["agents"].(map[string]any)
+
+	if v.Model != "" {
+		basics, ok := definition["scenario"].(map[string]any)
+		if !ok {
+			basics = make(map[string]any)
+			definition["scenario"] = basics
+		}
+		defaults, ok := basics["defaults"].(map[string]any)
+		if !ok {
+			defaults = make(map[string]any)
+			basics["defaults"] = defaults
+		}
+		defaults["model"] = v.Model
+		for _, agent := range agents {
+			agent, ok := agent.(map[string]any)
+			if !ok || agent["controller"] == scenarios.ControllerHuman {
+				continue
+			}
+			agent["model"] = v.Model
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(v.Characters)) {
+		agent, ok := agents[name].(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("sweep variant %s: agent %s is not in the scenario", v.Name, name)
+		}
+		agent["character"] = v.Characters[name]
+	}
+
+	return toml.Marshal(definition)
+}
@@ -0,0 +1,150 @@
+// Package campaigns stores state that carries across the scenarios of a campaign,
+// such as how characters feel about each other.
+package campaigns
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"regexp"
+	"sort"
+	"time"
+)
+
+// Dir is the name of the campaign directory in the config directory.
+const Dir = "campaigns"
+
+// relationshipsFile is the name of a campaign's relationship store.
+const relationshipsFile = "relationships.json"
+
+// Relationship values (affinity) and trust range from MinRelationship
+// (bitter enemies, no trust at all) to MaxRelationship (devoted allies,
+// complete trust); 0 is neutral. Familiarity ranges from 0 (strangers) to
+// MaxFamiliarity (know each other well).
+const (
+	MinRelationship = -10
+	MaxRelationship = 10
+	MaxFamiliarity  = 10
+)
+
+var namePattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// ValidateName checks that a campaign name is usable as a directory name.
+func ValidateName(name string) error {
+	if !namePattern.MatchString(name) {
+		return fmt.Errorf("invalid campaign name %q: use letters, digits, '-' and '_'", name)
+	}
+	return nil
+}
+
+// Relationship is how one character feels about another: how much they like
+// (Value), trust and know them. Relationships are directional: a grudge need
+// not be returned.
+type Relationship struct {
+	From        string    `json:"from"`
+	To          string    `json:"to"`
+	Value       int       `json:"value"`
+	Trust       int       `json:"trust,omitempty"`
+	Familiarity int       `json:"familiarity,omitempty"`
+	Reason      string    `json:"reason,omitempty"`   // Why the value last changed
+	Scenario    string    `json:"scenario,omitempty"` // Scenario that last changed the value
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// RelationshipStore holds a campaign's relationships.
+type RelationshipStore struct {
+	Campaign      string         `json:"campaign"`
+	Relationships []Relationship `json:"relationships"`
+}
+
+// CampaignDir returns the directory for a campaign in a config directory.
+func CampaignDir(configDir, campaign string) string {
+	return path.Join(configDir, Dir, campaign)
+}
+
+// LoadRelationships reads a campaign's relationship store.
+// A campaign that has never been saved returns an empty store.
+func LoadRelationships(configDir, campaign string) (*RelationshipStore, error) {
+	if err := ValidateName(campaign); err != nil {
+		return nil, err
+	}
+
+	store := &RelationshipStore{Campaign: campaign}
+	data, err := os.ReadFile(path.Join(CampaignDir(configDir, campaign), relationshipsFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return store, nil
+		}
+		return nil, fmt.Errorf("failed to read relationships for campaign %s: %w", campaign, err)
+	}
+	if err := json.Unmarshal(data, store); err != nil {
+		return nil, fmt.Errorf("failed to parse relationships for campaign %s: %w", campaign, err)
+	}
+	return store, nil
+}
+
+// Save writes the store to its campaign directory, creating it if needed.
+func (s *RelationshipStore) Save(configDir string) error {
+	dir := CampaignDir(configDir, s.Campaign)
+	if err := os.MkdirAll(dir, 0744); err != nil {
+		return fmt.Errorf("failed to create campaign directory: %w", err)
+	}
+
+	s.sort()
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal relationships: %w", err)
+	}
+	if err := os.WriteFile(path.Join(dir, relationshipsFile), data, 0644); err != nil {
+		return fmt.Errorf("failed to write relationships: %w", err)
+	}
+	return nil
+}
+
+// Get returns how from feels about to, if the campaign has recorded it.
+func (s *RelationshipStore) Get(from, to string) (Relationship, bool) {
+	for _, rel := range s.Relationships {
+		if rel.From == from && rel.To == to {
+			return rel, true
+		}
+	}
+	return Relationship{}, false
+}
+
+// Set records a relationship, replacing any previous value for the pair.
+// The value, trust and familiarity are clamped to their ranges.
+func (s *RelationshipStore) Set(rel Relationship) {
+	rel.Value = min(max(rel.Value, MinRelationship), MaxRelationship)
+	rel.Trust = min(max(rel.Trust, MinRelationship), MaxRelationship)
+	rel.Familiarity = min(max(rel.Familiarity, 0), MaxFamiliarity)
+	for i := range s.Relationships {
+		if s.Relationships[i].From == rel.From && s.Relationships[i].To == rel.To {
+			s.Relationships[i] = rel
+			return
+		}
+	}
+	s.Relationships = append(s.Relationships, rel)
+}
+
+// Involving returns the relationships from or to a character, or all of them
+// when character is empty, sorted by from and to.
+func (s *RelationshipStore) Involving(character string) []Relationship {
+	s.sort()
+	var result []Relationship
+	for _, rel := range s.Relationships {
+		if character == "" || rel.From == character || rel.To == character {
+			result = append(result, rel)
+		}
+	}
+	return result
+}
+
+func (s *RelationshipStore) sort() {
+	sort.Slice(s.Relationships, func(i, j int) bool {
+		if s.Relationships[i].From != s.Relationships[j].From {
+			return s.Relationships[i].From < s.Relationships[j].From
+		}
+		return s.Relationships[i].To < s.Relationships[j].To
+	})
+}
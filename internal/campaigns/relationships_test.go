@@ -0,0 +1,60 @@
+package campaigns
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRelationshipStore(t *testing.T) {
+	t.Run("loads an empty store for a new campaign", func(t *testing.T) {
+		store, err := LoadRelationships(t.TempDir(), "heist")
+		require.NoError(t, err)
+		assert.Equal(t, "heist", store.Campaign)
+		assert.Empty(t, store.Relationships)
+	})
+
+	t.Run("rejects names that aren't directory names", func(t *testing.T) {
+		_, err := LoadRelationships(t.TempDir(), "../heist")
+		assert.Error(t, err)
+	})
+
+	t.Run("round trips through the campaign directory", func(t *testing.T) {
+		configDir := t.TempDir()
+		store, err := LoadRelationships(configDir, "heist")
+		require.NoError(t, err)
+
+		store.Set(Relationship{From: "Bob", To: "Alice", Value: -4, Reason: "She took the credit"})
+		store.Set(Relationship{From: "Alice", To: "Bob", Value: 3})
+		require.NoError(t, store.Save(configDir))
+
+		loaded, err := LoadRelationships(configDir, "heist")
+		require.NoError(t, err)
+		rel, ok := loaded.Get("Bob", "Alice")
+		require.True(t, ok)
+		assert.Equal(t, -4, rel.Value)
+		assert.Equal(t, "She took the credit", rel.Reason)
+		assert.Equal(t, "Alice", loaded.Involving("")[0].From, "relationships are sorted")
+	})
+
+	t.Run("replaces and clamps values", func(t *testing.T) {
+		store := &RelationshipStore{Campaign: "heist"}
+		store.Set(Relationship{From: "Bob", To: "Alice", Value: 2})
+		store.Set(Relationship{From: "Bob", To: "Alice", Value: 25})
+
+		assert.Len(t, store.Relationships, 1)
+		rel, _ := store.Get("Bob", "Alice")
+		assert.Equal(t, MaxRelationship, rel.Value)
+	})
+
+	t.Run("filters by character", func(t *testing.T) {
+		store := &RelationshipStore{Campaign: "heist"}
+		store.Set(Relationship{From: "Bob", To: "Alice", Value: 1})
+		store.Set(Relationship{From: "Carol", To: "Bob", Value: 1})
+		store.Set(Relationship{From: "Carol", To: "Alice", Value: 1})
+
+		assert.Len(t, store.Involving("Bob"), 2)
+		assert.Len(t, store.Involving("Dave"), 0)
+	})
+}
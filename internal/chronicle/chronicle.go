@@ -1,12 +1,21 @@
 package chronicle
 
 import (
+	"bufio"
 	"encoding/json"
+	"fmt"
+	"os"
 	"time"
 
 	"github.com/oklog/ulid/v2"
+
+	"github.com/poiesic/wonda/internal/usage"
 )
 
+// maxLineSize is the longest chronicle line read; turns with long dialogue
+// and reasoning outgrow bufio's default.
+const maxLineSize = 16 * 1024 * 1024
+
 // Metadata is the first line in the chronicle JSONL file.
 type Metadata struct {
 	Type         string    `json:"type"` // Always "metadata"
@@ -16,31 +25,222 @@ type Metadata struct {
 	Time         string    `json:"time"`
 	Atmosphere   string    `json:"atmosphere,omitempty"`
 	StartTime    time.Time `json:"start_time"`
+	MaxTurns     int       `json:"max_turns,omitempty"` // Turns the simulation was allowed to run
+	DryRun       bool      `json:"dry_run,omitempty"`   // LLM requests were answered by a mock client
+
+	ReasoningShared bool `json:"reasoning_shared,omitempty"` // Agents saw the reasons others gave
+
+	Characters map[string]CharacterProfile `json:"characters,omitempty"` // How to present agents whose characters say, by agent name
+}
+
+// CharacterProfile is how exports present an agent's character to readers.
+type CharacterProfile struct {
+	Pronouns string `json:"pronouns,omitempty"`
+	Age      int    `json:"age,omitempty"`
+	Portrait string `json:"portrait,omitempty"` // Image file path or URL
+	Bio      string `json:"bio,omitempty"`
 }
 
 // Turn represents all events that occurred in a single turn.
 type Turn struct {
-	Type            string           `json:"type"` // Always "turn"
-	Number          int              `json:"number"`
-	Events          []Event          `json:"events"`
-	GoalCompletions []GoalCompletion `json:"goal_completions,omitempty"` // Goals completed this turn
+	Type            string            `json:"type"` // Always "turn"
+	Number          int               `json:"number"`
+	Ambient         []string          `json:"ambient,omitempty"` // Ambient events that happened at the start of the turn
+	Events          []Event           `json:"events"`
+	GoalCompletions []GoalCompletion  `json:"goal_completions,omitempty"` // Goals completed this turn
+	Skipped         []PhaseSkip       `json:"skipped,omitempty"`          // Phases or agent turns skipped as pointless
+	Condition       []ConditionChange `json:"condition,omitempty"`        // Changes to agents' condition this turn
+	Forecasts       []GoalForecast    `json:"forecasts,omitempty"`        // Turn budget projections for open goals
+	Injected        []Injection       `json:"injected,omitempty"`         // What the director brought into the scene this turn
+	TieBreaks       []TieBreak        `json:"tie_breaks,omitempty"`       // How tied votes were broken this turn
+	Hash            string            `json:"hash,omitempty"`             // Digest of everything above (see HashTurn)
+}
+
+// Injection records something a director brought into the scene.
+// Injected events also appear among the turn's ambient events.
+type Injection struct {
+	Kind    string `json:"kind"`    // event, atmosphere, or nudge
+	Content string `json:"content"` // The event, new atmosphere, or nudge to the agents
+	Source  string `json:"source"`  // Model that injected it
+}
+
+// TieBreak records how a tied vote on a majority or weighted vote goal's
+// proposal was broken.
+type TieBreak struct {
+	GoalName   string `json:"goal_name"`
+	ProposalID string `json:"proposal_id"`
+	Proposal   string `json:"proposal"`
+	Policy     string `json:"policy"`  // facilitator, priority, coin_flip or extend
+	Outcome    string `json:"outcome"` // accepted, rejected, or extended to another vote next turn
+	Reason     string `json:"reason"`
+}
+
+// GoalForecast records the turn budget's projection for an open goal at the end of a turn.
+type GoalForecast struct {
+	GoalName      string  `json:"goal_name"`
+	Support       float64 `json:"support"`        // Best share of backing any proposal had this turn
+	Needed        float64 `json:"needed"`         // Share of backing a proposal needs to be accepted
+	ProjectedTurn int     `json:"projected_turn"` // Turn the goal is projected to be settled; 0 if support isn't rising
+	Deadline      int     `json:"deadline"`       // Last turn the goal can be settled on
+	AtRisk        bool    `json:"at_risk"`        // Projected to miss its deadline
+}
+
+// ConditionChange records a change to an agent's condition (health and energy, 0-100).
+type ConditionChange struct {
+	AgentName string `json:"agent_name"`
+	Before    int    `json:"before"`
+	After     int    `json:"after"`
+	Cause     string `json:"cause"` // e.g. "end of turn", "rested", or the ambient event
+}
+
+// PhaseSkip records a phase, or one agent's turn in it, that was skipped
+// because there was nothing to do.
+type PhaseSkip struct {
+	Phase     string `json:"phase"`
+	AgentName string `json:"agent_name,omitempty"` // Set when only this agent's turn was skipped
+	Reason    string `json:"reason"`
 }
 
 // Event captures what one agent did during a turn.
 type Event struct {
-	AgentName string        `json:"agent_name"`
-	Type      string        `json:"type,omitempty"`      // dialogue, action, monologue
-	Dialogue  string        `json:"dialogue,omitempty"`  // What they said
-	Reasoning string        `json:"reasoning,omitempty"` // LLM thinking
-	Emotion   *AgentEmotion `json:"emotion,omitempty"`   // Emotional state change
-	Proposals []string      `json:"proposals,omitempty"` // Proposals made
-	Votes     []Vote        `json:"votes,omitempty"`     // Votes cast
+	AgentName   string        `json:"agent_name"`
+	Type        string        `json:"type,omitempty"`         // dialogue, action, monologue, whisper, pass, refusal
+	Dialogue    string        `json:"dialogue,omitempty"`     // What they said
+	Reasoning   string        `json:"reasoning,omitempty"`    // LLM thinking
+	Emotion     *AgentEmotion `json:"emotion,omitempty"`      // Emotional state change
+	Proposals   []string      `json:"proposals,omitempty"`    // Proposals made
+	ProposalIDs []string      `json:"proposal_ids,omitempty"` // IDs of the proposals made, in the same order
+	Votes       []Vote        `json:"votes,omitempty"`        // Votes cast
+	Candidates  []Candidate   `json:"candidates,omitempty"`   // Ensemble samples considered for this event
+	Refusal     *Refusal      `json:"refusal,omitempty"`      // Set on refusal events
+	Citations   []Citation    `json:"citations,omitempty"`    // Memories the agent said informed the event
+	Topics      []string      `json:"topics,omitempty"`       // Tags of the goal the agent was working on
+	Goal        string        `json:"goal,omitempty"`         // Goal the event was about, when it can be told
+	Rationale   string        `json:"rationale,omitempty"`    // Reason the agent gave for what they said
+	Recipient   string        `json:"recipient,omitempty"`    // Only agent who heard it, for whispers
+	Visibility  string        `json:"visibility,omitempty"`   // Who perceived it; empty when everyone present did
+	Fallback    string        `json:"fallback,omitempty"`     // Model error the event stood in for, for fallback actions
+	Entries     []Entry       `json:"entries,omitempty"`      // What the agent did, in order, as typed entries
+	Model       string        `json:"model,omitempty"`        // Model that took the agent's turn
+	Iterations  int           `json:"iterations,omitempty"`   // Model requests the turn took, one per round of tool calls
+}
+
+// EntriesOf returns the event's entries of one kind.
+func (e Event) EntriesOf(kind string) []Entry {
+	var entries []Entry
+	for _, entry := range e.Entries {
+		if entry.Kind == kind {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// Kinds of event entries.
+const (
+	EntryToolCall       = "tool_call"       // The agent called a tool
+	EntryProposal       = "proposal"        // The agent proposed a solution to a goal
+	EntryVote           = "vote"            // The agent voted on a proposal
+	EntryGoalCompletion = "goal_completion" // A goal was completed with the agent's proposal, or by the agent alone for individual goals
+)
+
+// Entry is one thing an agent did during an event, recorded as it happened so
+// exporters and analyzers needn't reconstruct it from dialogue or logs. Only
+// the fields for the entry's kind are set.
+type Entry struct {
+	Kind string `json:"kind"` // tool_call, proposal, vote or goal_completion
+
+	// Tool calls
+	Tool      string `json:"tool,omitempty"`
+	Arguments string `json:"arguments,omitempty"` // JSON object
+	Error     string `json:"error,omitempty"`     // Set when the tool failed
+
+	// Proposals, votes and goal completions
+	Goal       string `json:"goal,omitempty"`
+	ProposalID string `json:"proposal_id,omitempty"`
+	Proposal   string `json:"proposal,omitempty"` // Proposal made, or the solution a goal was completed with
+	Choice     string `json:"choice,omitempty"`   // yes or no, for votes
+	Status     string `json:"status,omitempty"`   // completed or failed, for goal completions
+}
+
+// VisibilityPrivate marks an event only its agent and recipient perceived.
+const VisibilityPrivate = "private"
+
+// Citation links an event to a memory the agent cited as informing it.
+// Citations are only recorded when a run asks agents to cite their memories.
+type Citation struct {
+	MemoryID string `json:"memory_id"`
+	Type     string `json:"type,omitempty"`     // Memory type, e.g. character or episodic
+	Category string `json:"category,omitempty"` // Memory category, e.g. identity or background
+	Content  string `json:"content,omitempty"`
+	Unknown  bool   `json:"unknown,omitempty"` // The cited ID doesn't match any memory
+}
+
+// Refusal describes a response the model refused or the provider filtered.
+// Refusal events have type "refusal"; Dialogue holds the refusal text, if any.
+type Refusal struct {
+	Kind      string `json:"kind"`      // filtered (provider content filter) or declined (model refused)
+	Reason    string `json:"reason"`    // Finish reason or matched refusal pattern
+	Recovered bool   `json:"recovered"` // A retry produced a usable response
+}
+
+// End is the last line of a chronicle, recording why the simulation stopped.
+// Chronicles of runs that crashed, or are still running, have none.
+type End struct {
+	Type     string `json:"type"` // Always "end"
+	Turns    int    `json:"turns"`
+	MaxTurns int    `json:"max_turns"`
+	Reason   string `json:"reason"`          // goals_completed, goals_decided, max_turns or error
+	Error    string `json:"error,omitempty"` // Set when the reason is error
+}
+
+// Reasons a simulation ends.
+const (
+	EndGoalsCompleted = "goals_completed" // Every goal was completed
+	EndGoalsDecided   = "goals_decided"   // Every goal was completed or failed, and some failed
+	EndMaxTurns       = "max_turns"       // The turn limit ran out with goals still pending
+	EndError          = "error"           // The run stopped with an error or was cancelled
+)
+
+// Usage records the tokens the run's requests used and what they cost, in
+// total and by model, caller and turn. It comes just before the end record.
+// Costs are in the currency of the models' configured prices, and zero for
+// models without prices.
+type Usage struct {
+	Type   string                  `json:"type"` // Always "usage"
+	Total  usage.Totals            `json:"total"`
+	Models []usage.Entry           `json:"models"`
+	Agents map[string]usage.Totals `json:"agents"` // By agent, with goal judges and post-mortems under their role
+	Turns  []usage.TurnTotals      `json:"turns"`  // Turn 0 covers requests made before the first turn
+}
+
+// Partial is an utterance as it streams in from the model.
+// Partial lines appear between turn records only when streaming is enabled; the
+// last one for an utterance is marked Final. The turn record still holds the
+// complete event, so consumers that only need the transcript can skip partials.
+type Partial struct {
+	Type      string `json:"type"` // Always "partial"
+	Turn      int    `json:"turn"`
+	AgentName string `json:"agent_name"`
+	Text      string `json:"text"`            // Everything said so far
+	Final     bool   `json:"final,omitempty"` // The utterance is complete
+}
+
+// Candidate is one sampled response from an ensemble agent.
+// Exactly one candidate per event is marked as selected.
+type Candidate struct {
+	Model     string   `json:"model"`
+	Dialogue  string   `json:"dialogue,omitempty"`
+	Reasoning string   `json:"reasoning,omitempty"`
+	ToolCalls []string `json:"tool_calls,omitempty"` // Formatted as name(arguments)
+	Selected  bool     `json:"selected"`
 }
 
 // AgentEmotion captures emotional state before and after an action.
 type AgentEmotion struct {
 	Before EmotionState `json:"before"`
 	After  EmotionState `json:"after"`
+	Cause  string       `json:"cause,omitempty"` // Why the agent's feelings changed, when they did
 }
 
 // EmotionState represents an emotional state at a point in time.
@@ -58,16 +258,57 @@ type Vote struct {
 // GoalCompletion represents a goal that was completed this turn.
 type GoalCompletion struct {
 	GoalName    string   `json:"goal_name"`
-	Status      string   `json:"status"`      // completed, failed
-	Solution    string   `json:"solution"`    // The accepted proposal
-	ProposedBy  string   `json:"proposed_by"` // Who proposed the solution
-	VotedYes    []string `json:"voted_yes"`   // Agents who voted yes
-	VotedNo     []string `json:"voted_no"`    // Agents who voted no
-	CompletedAt int      `json:"completed_at"` // Turn number
+	Status      string   `json:"status"`            // completed, failed
+	Solution    string   `json:"solution"`          // The accepted proposal, or why the goal failed
+	ProposedBy  string   `json:"proposed_by"`       // Who proposed the solution
+	VotedYes    []string `json:"voted_yes"`         // Agents who voted yes
+	VotedNo     []string `json:"voted_no"`          // Agents who voted no
+	CompletedAt int      `json:"completed_at"`      // Turn number
+	Support     float64  `json:"support,omitempty"` // Share of the goal's deciding agents who voted yes
+
+	// Set for goals completed by a judge; Solution holds the judge's assessment
+	JudgedBy   string  `json:"judged_by,omitempty"`  // Judge model
+	Confidence float64 `json:"confidence,omitempty"` // Judge confidence that the criteria are met
+
+	// Set for goals settled by ranking their proposals: ranked-choice goals,
+	// and goals that ran out of time
+	RankedChoice bool                `json:"ranked_choice,omitempty"`
+	Rankings     map[string][]string `json:"rankings,omitempty"` // Each agent's ranking, proposal IDs most preferred first
+	Runoff       []RunoffRound       `json:"runoff,omitempty"`   // The instant-runoff count, round by round
+
+	// Set for negotiation goals: every offer made, in order
+	Offers []Offer `json:"offers,omitempty"`
+
+	// Set for allocation goals; Solution holds the allocation in words
+	Allocation map[string]float64 `json:"allocation,omitempty"` // Shares by recipient
+
+	// Set for individual goals, once per agent; Solution holds the agent's summary
+	CompletedBy string `json:"completed_by,omitempty"` // Agent who completed their goal
+}
+
+// RunoffRound is one round of an instant-runoff count.
+type RunoffRound struct {
+	Counts     map[string]int `json:"counts"`               // Ballots counting for each proposal still standing, by ID
+	Eliminated string         `json:"eliminated,omitempty"` // Proposal dropped after the round; empty in the deciding round
 }
 
-// NewMetadata creates a metadata record for the chronicle.
-func NewMetadata(id ulid.ULID, scenario, location, tod, atmosphere string) Metadata {
+// Offer is one offer in a negotiation, with how far its side conceded from
+// its previous offer.
+type Offer struct {
+	ProposalID string   `json:"proposal_id"`
+	Side       string   `json:"side"`
+	ProposedBy string   `json:"proposed_by"`
+	Turn       int      `json:"turn"`
+	Terms      string   `json:"terms"`
+	Amount     *float64 `json:"amount,omitempty"`
+	Counters   string   `json:"counters,omitempty"`   // The offer this one answered
+	Concession float64  `json:"concession,omitempty"` // Change in amount from the side's previous offer
+	Status     string   `json:"status"`
+}
+
+// NewMetadata creates a metadata record for the chronicle, started at the
+// clock's current time.
+func NewMetadata(clock Clock, id ulid.ULID, scenario, location, tod, atmosphere string) Metadata {
 	return Metadata{
 		Type:         "metadata",
 		SimulationID: id.String(),
@@ -75,7 +316,7 @@ func NewMetadata(id ulid.ULID, scenario, location, tod, atmosphere string) Metad
 		Location:     location,
 		Time:         tod,
 		Atmosphere:   atmosphere,
-		StartTime:    time.Now(),
+		StartTime:    clock.Now(),
 	}
 }
 
@@ -83,3 +324,70 @@ func NewMetadata(id ulid.ULID, scenario, location, tod, atmosphere string) Metad
 func ToJSON(v interface{}) ([]byte, error) {
 	return json.Marshal(v)
 }
+
+// ReadFile reads and parses a JSONL chronicle file.
+// Partial utterances are skipped; turn records hold the complete events.
+// Line checksums are verified when the chronicle has them. A damaged last
+// line, left by a crash mid-write, is skipped; damage anywhere else is an error.
+func ReadFile(path string) (*Metadata, []Turn, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer file.Close()
+
+	var metadata *Metadata
+	var turns []Turn
+	var damaged error
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(nil, maxLineSize)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		if damaged != nil {
+			return nil, nil, damaged
+		}
+
+		line, err := verifyLine(scanner.Bytes())
+		if err != nil {
+			damaged = fmt.Errorf("line %d: %w", lineNumber, err)
+			continue
+		}
+
+		// Parse JSON to determine type
+		var typeCheck struct {
+			Type string `json:"type"`
+		}
+		if err := json.Unmarshal(line, &typeCheck); err != nil {
+			damaged = fmt.Errorf("failed to parse line %d: %w", lineNumber, err)
+			continue
+		}
+
+		switch typeCheck.Type {
+		case "metadata":
+			var m Metadata
+			if err := json.Unmarshal(line, &m); err != nil {
+				return nil, nil, fmt.Errorf("failed to parse metadata: %w", err)
+			}
+			metadata = &m
+		case "turn":
+			var t Turn
+			if err := json.Unmarshal(line, &t); err != nil {
+				return nil, nil, fmt.Errorf("failed to parse turn: %w", err)
+			}
+			turns = append(turns, t)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	if metadata == nil {
+		return nil, nil, fmt.Errorf("no metadata found in chronicle")
+	}
+
+	return metadata, turns, nil
+}
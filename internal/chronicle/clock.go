@@ -0,0 +1,23 @@
+package chronicle
+
+import "time"
+
+// Clock tells the time for chronicle names and timestamps, so tests and
+// scripted runs can pin it down instead of using the wall clock.
+type Clock interface {
+	Now() time.Time
+}
+
+// SystemClock is the wall clock.
+type SystemClock struct{}
+
+// Now returns the current time.
+func (SystemClock) Now() time.Time { return time.Now() }
+
+// FixedClock always tells the same time.
+type FixedClock struct {
+	Time time.Time
+}
+
+// Now returns the fixed time.
+func (c FixedClock) Now() time.Time { return c.Time }
@@ -0,0 +1,228 @@
+package chronicle
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// Comparison lines up two runs turn by turn and agent by agent, for seeing
+// how a change to prompts, characters or models changed what happened.
+type Comparison struct {
+	TurnsA int               `json:"turns_a"`
+	TurnsB int               `json:"turns_b"`
+	Turns  []TurnComparison  `json:"turns"`
+	Goals  []GoalComparison  `json:"goals"`
+	Same   bool              `json:"same"` // Every agent turn and goal outcome matched
+	Stats  ComparisonSummary `json:"summary"`
+}
+
+// ComparisonSummary sums up how much two runs differ.
+type ComparisonSummary struct {
+	AgentTurns         int     `json:"agent_turns"`         // Agent turns compared
+	Differing          int     `json:"differing"`           // Agent turns that differ in any way
+	DialogueSimilarity float64 `json:"dialogue_similarity"` // Mean word overlap of dialogue, 0-1
+	GoalsDiffering     int     `json:"goals_differing"`
+}
+
+// TurnComparison compares what each agent did in one turn of both runs.
+// A turn only one of the runs reached has the other side empty.
+type TurnComparison struct {
+	Number int               `json:"number"`
+	Agents []AgentComparison `json:"agents"`
+}
+
+// AgentComparison compares one agent's turn in both runs.
+type AgentComparison struct {
+	AgentName  string   `json:"agent_name"`
+	DialogueA  []string `json:"dialogue_a,omitempty"` // What they said, whispered or did
+	DialogueB  []string `json:"dialogue_b,omitempty"`
+	ProposalsA []string `json:"proposals_a,omitempty"`
+	ProposalsB []string `json:"proposals_b,omitempty"`
+	VotesA     []string `json:"votes_a,omitempty"` // Formatted as "choice proposal_id"
+	VotesB     []string `json:"votes_b,omitempty"`
+	Similarity float64  `json:"similarity"` // Word overlap of the dialogue, 0-1 (1 when neither said anything)
+	Same       bool     `json:"same"`
+}
+
+// GoalComparison compares how a goal ended in both runs. Status is empty in
+// a run that didn't settle the goal.
+type GoalComparison struct {
+	GoalName  string `json:"goal_name"`
+	StatusA   string `json:"status_a,omitempty"`
+	StatusB   string `json:"status_b,omitempty"`
+	SolutionA string `json:"solution_a,omitempty"`
+	SolutionB string `json:"solution_b,omitempty"`
+	TurnA     int    `json:"turn_a,omitempty"`
+	TurnB     int    `json:"turn_b,omitempty"`
+	Same      bool   `json:"same"` // Same status and solution; the turn may differ
+}
+
+// Compare lines up two chronicles' turns by number and, within each turn,
+// agents by name, and compares their dialogue, proposals and votes and how
+// each goal ended.
+func Compare(a, b []Turn) *Comparison {
+	c := &Comparison{TurnsA: len(a), TurnsB: len(b), Same: true}
+
+	turnsA, turnsB := turnsByNumber(a), turnsByNumber(b)
+	numbers := slices.Sorted(maps.Keys(turnsA))
+	for number := range turnsB {
+		if _, ok := turnsA[number]; !ok {
+			numbers = append(numbers, number)
+		}
+	}
+	slices.Sort(numbers)
+
+	similarity := 0.0
+	for _, number := range numbers {
+		byAgentA, byAgentB := agentTurns(turnsA[number]), agentTurns(turnsB[number])
+		names := slices.Sorted(maps.Keys(byAgentA))
+		for name := range byAgentB {
+			if _, ok := byAgentA[name]; !ok {
+				names = append(names, name)
+			}
+		}
+		slices.Sort(names)
+
+		turn := TurnComparison{Number: number}
+		for _, name := range names {
+			agentA, agentB := byAgentA[name], byAgentB[name]
+			agent := AgentComparison{
+				AgentName:  name,
+				DialogueA:  agentA.dialogue,
+				DialogueB:  agentB.dialogue,
+				ProposalsA: agentA.proposals,
+				ProposalsB: agentB.proposals,
+				VotesA:     agentA.votes,
+				VotesB:     agentB.votes,
+				Similarity: wordOverlap(strings.Join(agentA.dialogue, " "), strings.Join(agentB.dialogue, " ")),
+			}
+			agent.Same = slices.Equal(agent.DialogueA, agent.DialogueB) &&
+				slices.Equal(agent.ProposalsA, agent.ProposalsB) &&
+				slices.Equal(agent.VotesA, agent.VotesB)
+			turn.Agents = append(turn.Agents, agent)
+
+			c.Stats.AgentTurns++
+			similarity += agent.Similarity
+			if !agent.Same {
+				c.Stats.Differing++
+				c.Same = false
+			}
+		}
+		c.Turns = append(c.Turns, turn)
+	}
+	if c.Stats.AgentTurns > 0 {
+		c.Stats.DialogueSimilarity = similarity / float64(c.Stats.AgentTurns)
+	}
+
+	outcomesA, outcomesB := goalOutcomes(a), goalOutcomes(b)
+	goals := slices.Sorted(maps.Keys(outcomesA))
+	for name := range outcomesB {
+		if _, ok := outcomesA[name]; !ok {
+			goals = append(goals, name)
+		}
+	}
+	slices.Sort(goals)
+	for _, name := range goals {
+		outcomeA, outcomeB := outcomesA[name], outcomesB[name]
+		goal := GoalComparison{
+			GoalName:  name,
+			StatusA:   outcomeA.Status,
+			StatusB:   outcomeB.Status,
+			SolutionA: outcomeA.Solution,
+			SolutionB: outcomeB.Solution,
+			TurnA:     outcomeA.CompletedAt,
+			TurnB:     outcomeB.CompletedAt,
+		}
+		goal.Same = goal.StatusA == goal.StatusB && goal.SolutionA == goal.SolutionB
+		if !goal.Same {
+			c.Stats.GoalsDiffering++
+			c.Same = false
+		}
+		c.Goals = append(c.Goals, goal)
+	}
+	return c
+}
+
+// agentTurn is what one agent did in a turn.
+type agentTurn struct {
+	dialogue  []string
+	proposals []string
+	votes     []string
+}
+
+// turnsByNumber indexes turns by their number.
+func turnsByNumber(turns []Turn) map[int]*Turn {
+	byNumber := make(map[int]*Turn, len(turns))
+	for i := range turns {
+		byNumber[turns[i].Number] = &turns[i]
+	}
+	return byNumber
+}
+
+// agentTurns collects what each agent did in a turn, which may be nil.
+func agentTurns(turn *Turn) map[string]agentTurn {
+	byAgent := make(map[string]agentTurn)
+	if turn == nil {
+		return byAgent
+	}
+	for _, event := range turn.Events {
+		agent := byAgent[event.AgentName]
+		if event.Dialogue != "" && event.Refusal == nil && event.Type != "pass" && event.Type != "monologue" {
+			line := event.Dialogue
+			switch event.Type {
+			case "action":
+				line = "*" + line + "*"
+			case "whisper":
+				line = fmt.Sprintf("(to %s) %s", event.Recipient, line)
+			}
+			agent.dialogue = append(agent.dialogue, line)
+		}
+		agent.proposals = append(agent.proposals, event.Proposals...)
+		for _, vote := range event.Votes {
+			agent.votes = append(agent.votes, vote.Choice+" "+vote.ProposalID)
+		}
+		byAgent[event.AgentName] = agent
+	}
+	return byAgent
+}
+
+// goalOutcomes returns the last completion of each goal.
+func goalOutcomes(turns []Turn) map[string]GoalCompletion {
+	outcomes := make(map[string]GoalCompletion)
+	for _, turn := range turns {
+		for _, completion := range turn.GoalCompletions {
+			outcomes[completion.GoalName] = completion
+		}
+	}
+	return outcomes
+}
+
+// wordOverlap returns the Jaccard similarity of two texts' sets of words,
+// ignoring case and punctuation. Two empty texts are the same.
+func wordOverlap(a, b string) float64 {
+	wordsA, wordsB := wordSet(a), wordSet(b)
+	if len(wordsA) == 0 && len(wordsB) == 0 {
+		return 1
+	}
+	shared := 0
+	for word := range wordsA {
+		if wordsB[word] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(wordsA)+len(wordsB)-shared)
+}
+
+// wordSet returns the distinct lowercase words in a text.
+func wordSet(text string) map[string]bool {
+	words := make(map[string]bool)
+	for _, word := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r) && r != '\''
+	}) {
+		words[word] = true
+	}
+	return words
+}
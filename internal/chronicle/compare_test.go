@@ -0,0 +1,89 @@
+package chronicle
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompare(t *testing.T) {
+	a := []Turn{
+		{Number: 1, Events: []Event{
+			{AgentName: "Alice", Type: "dialogue", Dialogue: "Let's get pizza.", Proposals: []string{"Pizza"}},
+			{AgentName: "Bob", Type: "action", Dialogue: "nods"},
+		}},
+		{Number: 2, Events: []Event{
+			{AgentName: "Bob", Type: "dialogue", Dialogue: "Fine.", Votes: []Vote{{ProposalID: "dinner_1", Choice: "yes"}}},
+		}, GoalCompletions: []GoalCompletion{{GoalName: "dinner", Status: "completed", Solution: "Pizza", CompletedAt: 2}}},
+	}
+	b := []Turn{
+		{Number: 1, Events: []Event{
+			{AgentName: "Alice", Type: "dialogue", Dialogue: "Let's get tacos.", Proposals: []string{"Tacos"}},
+			{AgentName: "Bob", Type: "action", Dialogue: "nods"},
+		}},
+		{Number: 2, Events: []Event{
+			{AgentName: "Bob", Type: "dialogue", Dialogue: "Fine.", Votes: []Vote{{ProposalID: "dinner_1", Choice: "no"}}},
+			{AgentName: "Bob", Type: "monologue", Dialogue: "I hate tacos."},
+		}},
+		{Number: 3, Events: []Event{
+			{AgentName: "Alice", Type: "whisper", Recipient: "Bob", Dialogue: "Please?"},
+		}, GoalCompletions: []GoalCompletion{{GoalName: "dinner", Status: "failed", Solution: "No agreement", CompletedAt: 3}}},
+	}
+
+	c := Compare(a, b)
+	assert.False(t, c.Same)
+	assert.Equal(t, 2, c.TurnsA)
+	assert.Equal(t, 3, c.TurnsB)
+	require.Len(t, c.Turns, 3)
+
+	t.Run("aligns agents within a turn", func(t *testing.T) {
+		turn := c.Turns[0]
+		require.Len(t, turn.Agents, 2)
+		alice, bob := turn.Agents[0], turn.Agents[1]
+		assert.Equal(t, "Alice", alice.AgentName)
+		assert.False(t, alice.Same)
+		assert.Equal(t, []string{"Pizza"}, alice.ProposalsA)
+		assert.Equal(t, []string{"Tacos"}, alice.ProposalsB)
+		assert.InDelta(t, 0.5, alice.Similarity, 0.001) // let's and get of let's, get, pizza and tacos
+		assert.True(t, bob.Same)
+		assert.Equal(t, []string{"*nods*"}, bob.DialogueA)
+	})
+
+	t.Run("compares votes and ignores thoughts", func(t *testing.T) {
+		bob := c.Turns[1].Agents[0]
+		assert.False(t, bob.Same)
+		assert.Equal(t, []string{"Fine."}, bob.DialogueB)
+		assert.Equal(t, []string{"yes dinner_1"}, bob.VotesA)
+		assert.Equal(t, []string{"no dinner_1"}, bob.VotesB)
+		assert.Equal(t, 1.0, bob.Similarity)
+	})
+
+	t.Run("keeps turns only one run reached", func(t *testing.T) {
+		alice := c.Turns[2].Agents[0]
+		assert.Empty(t, alice.DialogueA)
+		assert.Equal(t, []string{"(to Bob) Please?"}, alice.DialogueB)
+		assert.Equal(t, 0.0, alice.Similarity)
+	})
+
+	t.Run("compares outcomes", func(t *testing.T) {
+		assert.Equal(t, []GoalComparison{{
+			GoalName: "dinner", StatusA: "completed", StatusB: "failed",
+			SolutionA: "Pizza", SolutionB: "No agreement", TurnA: 2, TurnB: 3,
+		}}, c.Goals)
+	})
+
+	t.Run("sums up", func(t *testing.T) {
+		assert.Equal(t, 4, c.Stats.AgentTurns)
+		assert.Equal(t, 3, c.Stats.Differing)
+		assert.Equal(t, 1, c.Stats.GoalsDiffering)
+		assert.InDelta(t, 0.625, c.Stats.DialogueSimilarity, 0.001)
+	})
+
+	t.Run("a run matches itself", func(t *testing.T) {
+		same := Compare(a, a)
+		assert.True(t, same.Same)
+		assert.Equal(t, 0, same.Stats.Differing)
+		assert.Equal(t, 1.0, same.Stats.DialogueSimilarity)
+	})
+}
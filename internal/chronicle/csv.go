@@ -0,0 +1,63 @@
+package chronicle
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// CSVHeader names the columns WriteCSV writes.
+var CSVHeader = []string{"turn", "agent", "type", "dialogue_length", "emotion", "emotion_intensity", "proposal_id", "vote", "goal"}
+
+// WriteCSV writes one row per event, for pivoting in a spreadsheet. Dialogue
+// length is in characters and the emotion is the agent's after the event.
+// Events with several proposals or votes list them separated by semicolons,
+// and the goal is the one the event was about, if known.
+func WriteCSV(w io.Writer, turns []Turn) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(CSVHeader); err != nil {
+		return err
+	}
+
+	for _, turn := range turns {
+		for _, event := range turn.Events {
+			eventType := event.Type
+			if eventType == "" {
+				eventType = "dialogue"
+			}
+
+			var emotion, intensity string
+			if event.Emotion != nil {
+				emotion = event.Emotion.After.Emotion
+				intensity = strconv.Itoa(event.Emotion.After.Intensity)
+			}
+
+			proposalIDs := append([]string(nil), event.ProposalIDs...)
+			var votes []string
+			for _, vote := range event.Votes {
+				proposalIDs = append(proposalIDs, vote.ProposalID)
+				votes = append(votes, vote.Choice)
+			}
+
+			row := []string{
+				strconv.Itoa(turn.Number),
+				event.AgentName,
+				eventType,
+				strconv.Itoa(utf8.RuneCountInString(event.Dialogue)),
+				emotion,
+				intensity,
+				strings.Join(proposalIDs, ";"),
+				strings.Join(votes, ";"),
+				event.Goal,
+			}
+			if err := writer.Write(row); err != nil {
+				return err
+			}
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,32 @@
+package chronicle
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteCSV(t *testing.T) {
+	emotion := &AgentEmotion{After: EmotionState{Emotion: "happy", Intensity: 6}}
+	turns := []Turn{
+		{Number: 1, Events: []Event{
+			{AgentName: "Alice", Dialogue: "Café, then?", Emotion: emotion},
+			{AgentName: "Alice", Type: "dialogue", Dialogue: "Bella's, everyone.", Proposals: []string{"Bella's"}, ProposalIDs: []string{"proposal_1"}, Goal: "dinner"},
+		}},
+		{Number: 2, Events: []Event{
+			{AgentName: "Bob", Type: "dialogue", Dialogue: "Fine, \"Bella's\" it is.", Votes: []Vote{{ProposalID: "proposal_1", Choice: "yes"}}},
+			{AgentName: "Bob", Type: "pass"},
+		}},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteCSV(&buf, turns))
+	assert.Equal(t, `turn,agent,type,dialogue_length,emotion,emotion_intensity,proposal_id,vote,goal
+1,Alice,dialogue,11,happy,6,,,
+1,Alice,dialogue,18,,,proposal_1,,dinner
+2,Bob,dialogue,22,,,proposal_1,yes,
+2,Bob,pass,0,,,,,
+`, buf.String())
+}
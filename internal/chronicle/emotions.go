@@ -0,0 +1,107 @@
+package chronicle
+
+import (
+	"encoding/csv"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// EmotionPoint is an agent's emotional state at the end of a turn.
+type EmotionPoint struct {
+	Turn      int    `json:"turn"`
+	Emotion   string `json:"emotion"`
+	Intensity int    `json:"intensity"`
+}
+
+// EmotionSeries is one agent's emotional state over a run.
+type EmotionSeries struct {
+	AgentName string         `json:"agent_name"`
+	Points    []EmotionPoint `json:"points"` // One per turn of the run
+}
+
+// EmotionTimelineHeader names the columns WriteEmotionTimelineCSV writes.
+var EmotionTimelineHeader = []string{"turn", "agent", "emotion", "intensity"}
+
+// EmotionTimeline returns each agent's emotional state at the end of every
+// turn, sorted by agent name, so their trajectories can be charted side by
+// side. A state carries over turns in which it didn't change, and turns before
+// the agent's first change take the state it changed from. Agents whose
+// emotions were never recorded are left out.
+func EmotionTimeline(turns []Turn) []EmotionSeries {
+	current := make(map[string]EmotionState)
+	first := make(map[string]EmotionState) // The state each agent started in
+	var agents []string
+	byTurn := make([]map[string]EmotionState, len(turns))
+	for i, turn := range turns {
+		for _, event := range turn.Events {
+			if event.Emotion == nil {
+				continue
+			}
+			if _, ok := first[event.AgentName]; !ok {
+				first[event.AgentName] = event.Emotion.Before
+				agents = append(agents, event.AgentName)
+			}
+			current[event.AgentName] = event.Emotion.After
+		}
+		byTurn[i] = make(map[string]EmotionState, len(current))
+		for agent, state := range current {
+			byTurn[i][agent] = state
+		}
+	}
+
+	sort.Strings(agents)
+	series := make([]EmotionSeries, 0, len(agents))
+	for _, agent := range agents {
+		points := make([]EmotionPoint, len(turns))
+		for i, turn := range turns {
+			state, ok := byTurn[i][agent]
+			if !ok {
+				state = first[agent]
+			}
+			points[i] = EmotionPoint{Turn: turn.Number, Emotion: state.Emotion, Intensity: state.Intensity}
+		}
+		series = append(series, EmotionSeries{AgentName: agent, Points: points})
+	}
+	return series
+}
+
+// WriteEmotionTimelineCSV writes one row per agent and turn of an emotion
+// timeline, for charting in a spreadsheet.
+func WriteEmotionTimelineCSV(w io.Writer, series []EmotionSeries) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(EmotionTimelineHeader); err != nil {
+		return err
+	}
+
+	for _, s := range series {
+		for _, point := range s.Points {
+			row := []string{
+				strconv.Itoa(point.Turn),
+				s.AgentName,
+				point.Emotion,
+				strconv.Itoa(point.Intensity),
+			}
+			if err := writer.Write(row); err != nil {
+				return err
+			}
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// sparkBlocks are the bars of a sparkline, lowest first.
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// Sparkline renders a series' intensities (0-10) as a line of bars, one per turn.
+func (s EmotionSeries) Sparkline() string {
+	var b strings.Builder
+	for _, point := range s.Points {
+		intensity := min(max(point.Intensity, 0), 10)
+		b.WriteRune(sparkBlocks[intensity*(len(sparkBlocks)-1)/10])
+	}
+	return b.String()
+}
@@ -0,0 +1,50 @@
+package chronicle
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmotionTimeline(t *testing.T) {
+	feel := func(before, after EmotionState) *AgentEmotion {
+		return &AgentEmotion{Before: before, After: after}
+	}
+	neutral := EmotionState{Emotion: "neutral", Intensity: 2}
+	angry := EmotionState{Emotion: "angry", Intensity: 7}
+	happy := EmotionState{Emotion: "happy", Intensity: 10}
+	turns := []Turn{
+		{Number: 1, Events: []Event{
+			{AgentName: "Bob", Dialogue: "Pizza again?", Emotion: feel(neutral, angry)},
+			{AgentName: "Carol", Dialogue: "I'm just watching."},
+		}},
+		{Number: 2, Events: []Event{
+			{AgentName: "Bob", Dialogue: "Fine."},
+		}},
+		{Number: 3, Events: []Event{
+			{AgentName: "Alice", Dialogue: "Dessert is on me!", Emotion: feel(neutral, happy)},
+		}},
+	}
+
+	timeline := EmotionTimeline(turns)
+	assert.Equal(t, []EmotionSeries{
+		{AgentName: "Alice", Points: []EmotionPoint{
+			{Turn: 1, Emotion: "neutral", Intensity: 2},
+			{Turn: 2, Emotion: "neutral", Intensity: 2},
+			{Turn: 3, Emotion: "happy", Intensity: 10},
+		}},
+		{AgentName: "Bob", Points: []EmotionPoint{
+			{Turn: 1, Emotion: "angry", Intensity: 7},
+			{Turn: 2, Emotion: "angry", Intensity: 7},
+			{Turn: 3, Emotion: "angry", Intensity: 7},
+		}},
+	}, timeline, "Carol's emotions were never recorded")
+
+	assert.Equal(t, "▂▂█", timeline[0].Sparkline())
+
+	var b strings.Builder
+	require.NoError(t, WriteEmotionTimelineCSV(&b, timeline[1:]))
+	assert.Equal(t, "turn,agent,emotion,intensity\n1,Bob,angry,7\n2,Bob,angry,7\n3,Bob,angry,7\n", b.String())
+}
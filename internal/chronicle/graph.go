@@ -0,0 +1,324 @@
+package chronicle
+
+import (
+	"cmp"
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Node labels in a run's graph.
+const (
+	NodeAgent    = "Agent"
+	NodeGoal     = "Goal"
+	NodeProposal = "Proposal"
+	NodeMemory   = "Memory"
+)
+
+// Edge types in a run's graph.
+const (
+	EdgeProposed  = "PROPOSED"     // Agent made a proposal
+	EdgeAddresses = "ADDRESSES"    // Proposal was made for a goal
+	EdgeVoted     = "VOTED"        // Agent voted on a proposal (choice property)
+	EdgeResolved  = "RESOLVED"     // Accepted proposal settled a goal
+	EdgeSupported = "SUPPORTED"    // Agent voted yes on another's proposals (weight property)
+	EdgeOpposed   = "OPPOSED"      // Agent voted no on another's proposals (weight property)
+	EdgeWhispered = "WHISPERED_TO" // Agent whispered to another (weight property)
+	EdgeCited     = "CITED"        // Agent cited a memory
+	EdgeRelatesTo = "RELATES_TO"   // Relationship between agents (value, trust, familiarity)
+)
+
+// GraphNode is an agent, goal, proposal or memory.
+type GraphNode struct {
+	ID         string
+	Label      string
+	Properties map[string]string
+}
+
+// GraphEdge connects two nodes.
+type GraphEdge struct {
+	From       string
+	To         string
+	Type       string
+	Properties map[string]string
+}
+
+// Graph is a run as a network of agents, goals, proposals and memories, for
+// analyzing who influenced and agreed with whom.
+type Graph struct {
+	Nodes []GraphNode
+	Edges []GraphEdge
+	index map[string]int    // Node position by ID
+	tally map[[3]string]int // Weights of summed edges, by from, to and type
+}
+
+// BuildGraph builds a graph from a chronicle's turns: who proposed what for
+// which goal, who voted which way on it, which proposal settled each goal,
+// who whispered to whom and which memories agents cited. Votes are also
+// summed into SUPPORTED and OPPOSED edges between voters and proposers.
+func BuildGraph(turns []Turn) *Graph {
+	g := &Graph{index: make(map[string]int), tally: make(map[[3]string]int)}
+
+	proposers := make(map[string]string) // Proposer by proposal ID
+	var proposals []string               // Proposal IDs in the order they were made
+	for _, turn := range turns {
+		turnNumber := strconv.Itoa(turn.Number)
+		for _, event := range turn.Events {
+			agentID := g.agent(event.AgentName)
+			if event.Goal != "" {
+				g.node(graphID(NodeGoal, event.Goal), NodeGoal, map[string]string{"name": event.Goal})
+			}
+
+			for i, text := range event.Proposals {
+				proposalID := fmt.Sprintf("turn%d-%s-%d", turn.Number, event.AgentName, i+1)
+				if i < len(event.ProposalIDs) {
+					proposalID = event.ProposalIDs[i]
+				}
+				proposers[proposalID] = event.AgentName
+				proposals = append(proposals, proposalID)
+				id := g.node(graphID(NodeProposal, proposalID), NodeProposal, map[string]string{"name": proposalID, "text": text, "turn": turnNumber})
+				g.edge(agentID, id, EdgeProposed, map[string]string{"turn": turnNumber})
+				if event.Goal != "" {
+					g.edge(id, graphID(NodeGoal, event.Goal), EdgeAddresses, nil)
+				}
+			}
+
+			for _, vote := range event.Votes {
+				id := g.node(graphID(NodeProposal, vote.ProposalID), NodeProposal, map[string]string{"name": vote.ProposalID})
+				g.edge(agentID, id, EdgeVoted, map[string]string{"choice": vote.Choice, "turn": turnNumber})
+				proposer, ok := proposers[vote.ProposalID]
+				if !ok || proposer == event.AgentName {
+					continue
+				}
+				if vote.Choice == "yes" {
+					g.count(agentID, graphID(NodeAgent, proposer), EdgeSupported)
+				} else {
+					g.count(agentID, graphID(NodeAgent, proposer), EdgeOpposed)
+				}
+			}
+
+			if event.Type == "whisper" && event.Recipient != "" {
+				g.count(agentID, g.agent(event.Recipient), EdgeWhispered)
+			}
+
+			for _, citation := range event.Citations {
+				if citation.Unknown {
+					continue
+				}
+				id := g.node(graphID(NodeMemory, citation.MemoryID), NodeMemory, map[string]string{
+					"name": citation.MemoryID, "type": citation.Type, "category": citation.Category, "text": citation.Content,
+				})
+				g.edge(agentID, id, EdgeCited, map[string]string{"turn": turnNumber})
+			}
+		}
+
+		for _, completion := range turn.GoalCompletions {
+			goalID := g.node(graphID(NodeGoal, completion.GoalName), NodeGoal, map[string]string{"name": completion.GoalName})
+			g.set(goalID, map[string]string{"status": completion.Status, "solution": completion.Solution, "turn": strconv.Itoa(completion.CompletedAt)})
+			if completion.Status != "completed" {
+				continue
+			}
+			// Credit the proposal whose text was accepted, when it can be found
+			for _, proposalID := range slices.Backward(proposals) {
+				id := graphID(NodeProposal, proposalID)
+				if proposers[proposalID] == completion.ProposedBy && g.Nodes[g.index[id]].Properties["text"] == completion.Solution {
+					g.edge(id, goalID, EdgeResolved, nil)
+					break
+				}
+			}
+		}
+	}
+
+	// Summed edges follow the rest, ordered by type and then endpoints
+	keys := slices.SortedFunc(maps.Keys(g.tally), func(a, b [3]string) int {
+		return cmp.Or(cmp.Compare(a[2], b[2]), cmp.Compare(a[0], b[0]), cmp.Compare(a[1], b[1]))
+	})
+	for _, key := range keys {
+		g.edge(key[0], key[1], key[2], map[string]string{"weight": strconv.Itoa(g.tally[key])})
+	}
+	return g
+}
+
+// AddRelationship adds how one agent regards another, e.g. from the
+// campaign the run was part of.
+func (g *Graph) AddRelationship(from, to string, value, trust, familiarity int) {
+	g.edge(g.agent(from), g.agent(to), EdgeRelatesTo, map[string]string{
+		"value":       strconv.Itoa(value),
+		"trust":       strconv.Itoa(trust),
+		"familiarity": strconv.Itoa(familiarity),
+	})
+}
+
+// WriteGraphML writes the graph as GraphML, for Gephi, yEd, NetworkX and
+// the like. Every property is a string; node labels and edge types are the
+// "label" and "type" attributes.
+func (g *Graph) WriteGraphML(w io.Writer) error {
+	nodeKeys, edgeKeys := g.propertyKeys()
+	ew := &errWriter{w: w}
+	ew.printf("<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n")
+	ew.printf("<graphml xmlns=\"http://graphml.graphdrawing.org/xmlns\">\n")
+	ew.printf("  <key id=\"label\" for=\"node\" attr.name=\"label\" attr.type=\"string\"/>\n")
+	for _, key := range nodeKeys {
+		ew.printf("  <key id=\"n_%s\" for=\"node\" attr.name=\"%s\" attr.type=\"string\"/>\n", key, key)
+	}
+	ew.printf("  <key id=\"type\" for=\"edge\" attr.name=\"type\" attr.type=\"string\"/>\n")
+	for _, key := range edgeKeys {
+		ew.printf("  <key id=\"e_%s\" for=\"edge\" attr.name=\"%s\" attr.type=\"string\"/>\n", key, key)
+	}
+	ew.printf("  <graph id=\"run\" edgedefault=\"directed\">\n")
+	for _, node := range g.Nodes {
+		ew.printf("    <node id=\"%s\">\n", escapeXML(node.ID))
+		ew.printf("      <data key=\"label\">%s</data>\n", node.Label)
+		for _, key := range slices.Sorted(maps.Keys(node.Properties)) {
+			ew.printf("      <data key=\"n_%s\">%s</data>\n", key, escapeXML(node.Properties[key]))
+		}
+		ew.printf("    </node>\n")
+	}
+	for i, edge := range g.Edges {
+		ew.printf("    <edge id=\"e%d\" source=\"%s\" target=\"%s\">\n", i+1, escapeXML(edge.From), escapeXML(edge.To))
+		ew.printf("      <data key=\"type\">%s</data>\n", edge.Type)
+		for _, key := range slices.Sorted(maps.Keys(edge.Properties)) {
+			ew.printf("      <data key=\"e_%s\">%s</data>\n", key, escapeXML(edge.Properties[key]))
+		}
+		ew.printf("    </edge>\n")
+	}
+	ew.printf("  </graph>\n</graphml>\n")
+	return ew.err
+}
+
+// WriteNeo4jCSV writes the graph as nodes.csv and relationships.csv in dir,
+// with the headers neo4j-admin import expects.
+func (g *Graph) WriteNeo4jCSV(dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	nodeKeys, edgeKeys := g.propertyKeys()
+
+	nodeRows := make([][]string, 0, len(g.Nodes)+1)
+	nodeRows = append(nodeRows, append([]string{"id:ID", ":LABEL"}, nodeKeys...))
+	for _, node := range g.Nodes {
+		row := []string{node.ID, node.Label}
+		for _, key := range nodeKeys {
+			row = append(row, node.Properties[key])
+		}
+		nodeRows = append(nodeRows, row)
+	}
+	if err := writeCSVFile(path.Join(dir, "nodes.csv"), nodeRows); err != nil {
+		return err
+	}
+
+	edgeRows := make([][]string, 0, len(g.Edges)+1)
+	edgeRows = append(edgeRows, append([]string{":START_ID", ":END_ID", ":TYPE"}, edgeKeys...))
+	for _, edge := range g.Edges {
+		row := []string{edge.From, edge.To, edge.Type}
+		for _, key := range edgeKeys {
+			row = append(row, edge.Properties[key])
+		}
+		edgeRows = append(edgeRows, row)
+	}
+	return writeCSVFile(path.Join(dir, "relationships.csv"), edgeRows)
+}
+
+// graphID makes a node ID unique across labels.
+func graphID(label, name string) string {
+	return label + ":" + name
+}
+
+// agent adds an agent node, if it isn't in the graph yet, and returns its ID.
+func (g *Graph) agent(name string) string {
+	return g.node(graphID(NodeAgent, name), NodeAgent, map[string]string{"name": name})
+}
+
+// node adds a node, or fills in properties it was missing, and returns its ID.
+func (g *Graph) node(id, label string, properties map[string]string) string {
+	if _, ok := g.index[id]; !ok {
+		g.index[id] = len(g.Nodes)
+		g.Nodes = append(g.Nodes, GraphNode{ID: id, Label: label, Properties: make(map[string]string)})
+	}
+	for key, value := range properties {
+		if value != "" && g.Nodes[g.index[id]].Properties[key] == "" {
+			g.Nodes[g.index[id]].Properties[key] = value
+		}
+	}
+	return id
+}
+
+// set overwrites a node's properties.
+func (g *Graph) set(id string, properties map[string]string) {
+	for key, value := range properties {
+		if value != "" {
+			g.Nodes[g.index[id]].Properties[key] = value
+		}
+	}
+}
+
+// edge adds an edge.
+func (g *Graph) edge(from, to, edgeType string, properties map[string]string) {
+	if properties == nil {
+		properties = make(map[string]string)
+	}
+	g.Edges = append(g.Edges, GraphEdge{From: from, To: to, Type: edgeType, Properties: properties})
+}
+
+// count adds one to the weight of an edge summed over the run.
+func (g *Graph) count(from, to, edgeType string) {
+	g.tally[[3]string{from, to, edgeType}]++
+}
+
+// propertyKeys returns the property names used by nodes and by edges.
+func (g *Graph) propertyKeys() (nodeKeys, edgeKeys []string) {
+	nodes := make(map[string]bool)
+	for _, node := range g.Nodes {
+		for key := range node.Properties {
+			nodes[key] = true
+		}
+	}
+	edges := make(map[string]bool)
+	for _, edge := range g.Edges {
+		for key := range edge.Properties {
+			edges[key] = true
+		}
+	}
+	return slices.Sorted(maps.Keys(nodes)), slices.Sorted(maps.Keys(edges))
+}
+
+// escapeXML escapes text for an XML attribute or element.
+func escapeXML(text string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(text))
+	return b.String()
+}
+
+// errWriter keeps the first error writing, so output can be written
+// without checking every line.
+type errWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (w *errWriter) printf(format string, args ...interface{}) {
+	if w.err == nil {
+		_, w.err = fmt.Fprintf(w.w, format, args...)
+	}
+}
+
+// writeCSVFile writes rows to a CSV file.
+func writeCSVFile(path string, rows [][]string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	writer := csv.NewWriter(file)
+	if err := writer.WriteAll(rows); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
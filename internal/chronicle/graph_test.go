@@ -0,0 +1,79 @@
+package chronicle
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildGraph(t *testing.T) {
+	turns := []Turn{
+		{Number: 1, Events: []Event{
+			{AgentName: "Alice", Dialogue: "Bella's?", Goal: "dinner", Proposals: []string{"Bella's"}, ProposalIDs: []string{"proposal_1"}},
+			{AgentName: "Bob", Type: "whisper", Recipient: "Carol", Dialogue: "Not again.",
+				Citations: []Citation{{MemoryID: "m1", Type: "episodic", Content: "Bella's was loud"}, {MemoryID: "m2", Unknown: true}}},
+		}},
+		{Number: 2, Events: []Event{
+			{AgentName: "Bob", Votes: []Vote{{ProposalID: "proposal_1", Choice: "no"}}},
+			{AgentName: "Carol", Votes: []Vote{{ProposalID: "proposal_1", Choice: "yes"}}},
+			{AgentName: "Alice", Votes: []Vote{{ProposalID: "proposal_1", Choice: "yes"}}},
+		}, GoalCompletions: []GoalCompletion{{GoalName: "dinner", Status: "completed", Solution: "Bella's", ProposedBy: "Alice", CompletedAt: 2}}},
+	}
+
+	g := BuildGraph(turns)
+	g.AddRelationship("Bob", "Alice", -3, 2, 5)
+
+	labels := make(map[string]string)
+	for _, node := range g.Nodes {
+		labels[node.ID] = node.Label
+	}
+	assert.Equal(t, map[string]string{
+		"Agent:Alice": NodeAgent, "Agent:Bob": NodeAgent, "Agent:Carol": NodeAgent,
+		"Goal:dinner": NodeGoal, "Proposal:proposal_1": NodeProposal, "Memory:m1": NodeMemory,
+	}, labels)
+	assert.Equal(t, "completed", g.Nodes[g.index["Goal:dinner"]].Properties["status"])
+
+	edges := make(map[string]string)
+	for _, edge := range g.Edges {
+		edges[edge.From+" "+edge.Type+" "+edge.To] = edge.Properties["weight"] + edge.Properties["choice"] + edge.Properties["trust"]
+	}
+	assert.Equal(t, map[string]string{
+		"Agent:Alice PROPOSED Proposal:proposal_1":  "",
+		"Proposal:proposal_1 ADDRESSES Goal:dinner": "",
+		"Agent:Bob CITED Memory:m1":                 "",
+		"Agent:Bob VOTED Proposal:proposal_1":       "no",
+		"Agent:Carol VOTED Proposal:proposal_1":     "yes",
+		"Agent:Alice VOTED Proposal:proposal_1":     "yes",
+		"Proposal:proposal_1 RESOLVED Goal:dinner":  "",
+		"Agent:Bob OPPOSED Agent:Alice":             "1",
+		"Agent:Carol SUPPORTED Agent:Alice":         "1",
+		"Agent:Bob WHISPERED_TO Agent:Carol":        "1",
+		"Agent:Bob RELATES_TO Agent:Alice":          "2",
+	}, edges, "voting on your own proposal is not support")
+
+	t.Run("GraphML", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, g.WriteGraphML(&buf))
+		out := buf.String()
+		assert.Contains(t, out, `<node id="Memory:m1">`)
+		assert.Contains(t, out, `<data key="n_text">Bella&#39;s was loud</data>`)
+		assert.Contains(t, out, `<key id="e_weight" for="edge" attr.name="weight" attr.type="string"/>`)
+	})
+
+	t.Run("Neo4j CSV", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, g.WriteNeo4jCSV(dir))
+		nodes, err := os.ReadFile(filepath.Join(dir, "nodes.csv"))
+		require.NoError(t, err)
+		assert.Contains(t, string(nodes), "id:ID,:LABEL,name,solution,status,text,turn,type\n")
+		assert.Contains(t, string(nodes), "Agent:Alice,Agent,Alice,,,,,\n")
+		edges, err := os.ReadFile(filepath.Join(dir, "relationships.csv"))
+		require.NoError(t, err)
+		assert.Contains(t, string(edges), ":START_ID,:END_ID,:TYPE,choice,familiarity,trust,turn,value,weight\n")
+		assert.Contains(t, string(edges), "Agent:Bob,Agent:Alice,OPPOSED,,,,,,1\n")
+	})
+}
@@ -0,0 +1,50 @@
+package chronicle
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// HashTurn returns a digest of what changed in the world during a turn: the
+// turn record without its own hash. Runs that played out the same way, such
+// as two dry runs with the same script, have the same hash for every turn, so
+// the first turn whose hashes differ is where their behavior diverged. Goals
+// settled in the same turn are recorded in no particular order, so their
+// completions are hashed in goal order.
+func HashTurn(turn Turn) (string, error) {
+	turn.Hash = ""
+	turn.GoalCompletions = slices.SortedStableFunc(slices.Values(turn.GoalCompletions), func(a, b GoalCompletion) int {
+		if order := strings.Compare(a.GoalName, b.GoalName); order != 0 {
+			return order
+		}
+		return strings.Compare(a.CompletedBy, b.CompletedBy)
+	})
+	data, err := json.Marshal(turn)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal turn %d: %w", turn.Number, err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// TurnHashes returns each turn's hash by turn number: the hash recorded with
+// it, or for chronicles written before turns were hashed, one computed from
+// the turn as recorded.
+func TurnHashes(turns []Turn) (map[int]string, error) {
+	hashes := make(map[int]string, len(turns))
+	for _, turn := range turns {
+		hash := turn.Hash
+		if hash == "" {
+			var err error
+			if hash, err = HashTurn(turn); err != nil {
+				return nil, err
+			}
+		}
+		hashes[turn.Number] = hash
+	}
+	return hashes, nil
+}
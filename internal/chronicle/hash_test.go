@@ -0,0 +1,65 @@
+package chronicle
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/oklog/ulid/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHashTurn(t *testing.T) {
+	turn := Turn{
+		Type:   "turn",
+		Number: 3,
+		Events: []Event{{AgentName: "Alice", Type: "dialogue", Dialogue: "Bella's?"}},
+		GoalCompletions: []GoalCompletion{
+			{GoalName: "restaurant", Status: "completed", Solution: "Bella's", CompletedAt: 3},
+			{GoalName: "dessert", Status: "failed", CompletedAt: 3},
+		},
+	}
+	hash, err := HashTurn(turn)
+	require.NoError(t, err)
+	assert.Len(t, hash, 64)
+
+	t.Run("leaves out the turn's own hash", func(t *testing.T) {
+		hashed := turn
+		hashed.Hash = hash
+		again, err := HashTurn(hashed)
+		require.NoError(t, err)
+		assert.Equal(t, hash, again)
+	})
+
+	t.Run("ignores the order goals were settled in", func(t *testing.T) {
+		reordered := turn
+		reordered.GoalCompletions = []GoalCompletion{turn.GoalCompletions[1], turn.GoalCompletions[0]}
+		again, err := HashTurn(reordered)
+		require.NoError(t, err)
+		assert.Equal(t, hash, again)
+		assert.Equal(t, "restaurant", turn.GoalCompletions[0].GoalName, "the turn is left as it was")
+	})
+
+	t.Run("changes with what happened", func(t *testing.T) {
+		changed := turn
+		changed.Events = []Event{{AgentName: "Alice", Type: "dialogue", Dialogue: "Luigi's?"}}
+		again, err := HashTurn(changed)
+		require.NoError(t, err)
+		assert.NotEqual(t, hash, again)
+	})
+
+	t.Run("matches once read back from a chronicle", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "chronicle.jsonl")
+		writer, err := Create(path, false)
+		require.NoError(t, err)
+		require.NoError(t, writer.Write(NewMetadata(SystemClock{}, ulid.Make(), "Dinner", "Cafe", "evening", "")))
+		require.NoError(t, writer.Write(turn))
+		require.NoError(t, writer.Close())
+
+		_, turns, err := ReadFile(path)
+		require.NoError(t, err)
+		hashes, err := TurnHashes(turns)
+		require.NoError(t, err)
+		assert.Equal(t, map[int]string{3: hash}, hashes)
+	})
+}
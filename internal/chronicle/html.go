@@ -0,0 +1,140 @@
+package chronicle
+
+import (
+	_ "embed"
+	"encoding/base64"
+	"html/template"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+//go:embed export.html
+var htmlSource string
+
+var htmlTemplate = template.Must(template.New("chronicle").Funcs(template.FuncMap{
+	"eventType": func(event Event) string {
+		if event.Type == "" {
+			return "dialogue"
+		}
+		return event.Type
+	},
+	"formatTime": func(t time.Time) string {
+		return t.Format("2006-01-02 15:04:05")
+	},
+}).Parse(htmlSource))
+
+// agentColors are the colors agents are told apart by, in the order they
+// first act. They start over when there are more agents than colors.
+var agentColors = []string{"#2563eb", "#dc2626", "#059669", "#d97706", "#7c3aed", "#db2777", "#0891b2", "#65a30d"}
+
+// HTMLAgent is an agent as the HTML export shows it: its color and how it
+// took part in the decisions.
+type HTMLAgent struct {
+	Name      string
+	Color     string
+	Proposals int
+	VotedYes  int
+	VotedNo   int
+}
+
+// htmlCharacter is an agent whose character has a profile, as the HTML
+// export presents it.
+type htmlCharacter struct {
+	HTMLAgent
+	Profile  CharacterProfile
+	Portrait template.URL // Image URL, with portrait files embedded as data URLs
+}
+
+// htmlPage is what the HTML export template renders.
+type htmlPage struct {
+	Metadata   *Metadata
+	Turns      []Turn
+	Agents     []HTMLAgent
+	Characters []htmlCharacter   // Agents with profiles, in the same order
+	Colors     map[string]string // Agent color by name
+	Goals      []GoalCompletion
+}
+
+// HTMLAgents lists the agents in a chronicle in the order they first act,
+// with their colors and proposal and vote counts.
+func HTMLAgents(turns []Turn) []HTMLAgent {
+	var agents []HTMLAgent
+	index := make(map[string]int)
+	for _, turn := range turns {
+		for _, event := range turn.Events {
+			i, ok := index[event.AgentName]
+			if !ok {
+				i = len(agents)
+				index[event.AgentName] = i
+				agents = append(agents, HTMLAgent{Name: event.AgentName, Color: agentColors[i%len(agentColors)]})
+			}
+			agents[i].Proposals += len(event.Proposals)
+			for _, vote := range event.Votes {
+				if vote.Choice == "yes" {
+					agents[i].VotedYes++
+				} else {
+					agents[i].VotedNo++
+				}
+			}
+		}
+	}
+	return agents
+}
+
+// WriteHTML writes a chronicle as a self-contained HTML page: turns with
+// navigation between them, each agent in its own color, reasoning folded
+// away until opened, a summary of how the goals were decided, and the cast's
+// profiles, with portrait files embedded.
+func WriteHTML(w io.Writer, metadata *Metadata, turns []Turn) error {
+	page := htmlPage{
+		Metadata: metadata,
+		Turns:    turns,
+		Agents:   HTMLAgents(turns),
+		Colors:   make(map[string]string),
+	}
+	for _, agent := range page.Agents {
+		page.Colors[agent.Name] = agent.Color
+		if metadata == nil {
+			continue
+		}
+		if profile, ok := metadata.Characters[agent.Name]; ok {
+			page.Characters = append(page.Characters, htmlCharacter{
+				HTMLAgent: agent,
+				Profile:   profile,
+				Portrait:  portraitURL(profile.Portrait),
+			})
+		}
+	}
+	for _, turn := range turns {
+		page.Goals = append(page.Goals, turn.GoalCompletions...)
+	}
+	return htmlTemplate.Execute(w, page)
+}
+
+// portraitURL returns the URL a page shows a portrait from: URLs as they are,
+// and image files embedded as data URLs, so the page stays self-contained.
+// Portraits that can't be read are left out.
+func portraitURL(portrait string) template.URL {
+	if portrait == "" {
+		return ""
+	}
+	if strings.Contains(portrait, "://") || strings.HasPrefix(portrait, "data:") {
+		return template.URL(portrait)
+	}
+	data, err := os.ReadFile(portrait)
+	if err != nil {
+		slog.Warn("failed to read portrait", "path", portrait, "error", err)
+		return ""
+	}
+	contentType := mime.TypeByExtension(filepath.Ext(portrait))
+	if contentType == "" {
+		contentType = http.DetectContentType(data)
+	}
+	return template.URL("data:" + contentType + ";base64," + base64.StdEncoding.EncodeToString(data))
+}
@@ -0,0 +1,75 @@
+package chronicle
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/oklog/ulid/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteHTML(t *testing.T) {
+	metadata := NewMetadata(SystemClock{}, ulid.Make(), "Dinner <Plans>", "Cafe", "evening", "")
+	turns := []Turn{
+		{Type: "turn", Number: 1, Events: []Event{
+			{AgentName: "Alice", Dialogue: "Bella's, everyone.", Reasoning: "They liked it last time.", Proposals: []string{"Bella's"}},
+			{AgentName: "Bob", Type: "whisper", Recipient: "Alice", Dialogue: "<b>Not</b> again."},
+		}},
+		{Type: "turn", Number: 2, Events: []Event{
+			{AgentName: "Bob", Votes: []Vote{{ProposalID: "proposal_1", Choice: "yes"}}, Entries: []Entry{
+				{Kind: EntryToolCall, Tool: "vote", Arguments: `{"choice":"yes"}`},
+				{Kind: EntryVote, Goal: "dinner", ProposalID: "proposal_1", Choice: "yes"},
+			}},
+			{AgentName: "Alice", Votes: []Vote{{ProposalID: "proposal_1", Choice: "no"}}},
+		}, GoalCompletions: []GoalCompletion{{GoalName: "dinner", Status: "completed", Solution: "Bella's", ProposedBy: "Alice", VotedYes: []string{"Bob"}, CompletedAt: 2}}},
+	}
+
+	agents := HTMLAgents(turns)
+	require.Len(t, agents, 2)
+	assert.Equal(t, HTMLAgent{Name: "Alice", Color: agentColors[0], Proposals: 1, VotedNo: 1}, agents[0])
+	assert.Equal(t, HTMLAgent{Name: "Bob", Color: agentColors[1], VotedYes: 1}, agents[1])
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteHTML(&buf, &metadata, turns))
+	page := buf.String()
+
+	assert.Contains(t, page, "<title>Dinner &lt;Plans&gt;</title>")
+	assert.Contains(t, page, `<a href="#turn-2">Turn 2</a>`)
+	assert.Contains(t, page, `<section class="turn" id="turn-1">`)
+	assert.Contains(t, page, `<div class="event whisper" style="--agent: `+agentColors[1]+`">`)
+	assert.Contains(t, page, "<details><summary>Reasoning</summary><div>They liked it last time.</div></details>")
+	assert.Contains(t, page, "&lt;b&gt;Not&lt;/b&gt; again.", "dialogue is escaped")
+	assert.Contains(t, page, `<div class="completion completed">`)
+	assert.Contains(t, page, "Proposed by Alice")
+	assert.Contains(t, page, "<details><summary>Tool calls (1)</summary>")
+	assert.Contains(t, page, "vote({&#34;choice&#34;:&#34;yes&#34;})")
+}
+
+func TestWriteHTMLCharacters(t *testing.T) {
+	portrait := filepath.Join(t.TempDir(), "alice.png")
+	require.NoError(t, os.WriteFile(portrait, []byte("\x89PNG\r\n\x1a\n"), 0644))
+
+	metadata := NewMetadata(SystemClock{}, ulid.Make(), "Dinner", "Cafe", "evening", "")
+	metadata.Characters = map[string]CharacterProfile{
+		"Alice": {Pronouns: "she/her", Age: 34, Portrait: portrait, Bio: "Runs the cafe."},
+		"Bob":   {Portrait: "https://example.com/bob.jpg"},
+		"Cara":  {Bio: "Never showed up."},
+	}
+	turns := []Turn{{Type: "turn", Number: 1, Events: []Event{
+		{AgentName: "Alice", Dialogue: "Pizza?"},
+		{AgentName: "Bob", Dialogue: "Sure."},
+	}}}
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteHTML(&buf, &metadata, turns))
+	page := buf.String()
+
+	assert.Contains(t, page, `<img src="data:image/png;base64,iVBORw0KGgo=" alt="Alice">`, "portrait files are embedded")
+	assert.Contains(t, page, `<div class="about">she/her, 34</div>`)
+	assert.Contains(t, page, "<div>Runs the cafe.</div>")
+	assert.Contains(t, page, `<img src="https://example.com/bob.jpg" alt="Bob">`)
+	assert.NotContains(t, page, "Never showed up.", "agents who never act aren't listed")
+}
@@ -0,0 +1,168 @@
+package chronicle
+
+import (
+	"cmp"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// AgentStats counts what one agent did over a run.
+type AgentStats struct {
+	AgentName string `json:"agent_name"`
+	Turns     int    `json:"turns"` // Turns with at least one counted event
+	Said      int    `json:"said"`  // Dialogue events
+	Actions   int    `json:"actions"`
+	Thoughts  int    `json:"thoughts"`
+	Passes    int    `json:"passes"`
+	Refusals  int    `json:"refusals"`
+	Words     int    `json:"words"` // Words of dialogue
+
+	TalkShare float64 `json:"talk_share"` // Share of all the words of dialogue counted, 0-1
+	Proposals int     `json:"proposals"`  // Proposals made
+	Accepted  int     `json:"accepted"`   // Goals completed with one of the agent's proposals
+	Votes     int     `json:"votes"`      // Votes cast
+	VotedYes  int     `json:"voted_yes"`
+}
+
+// GoalStats summarizes how one goal was decided.
+type GoalStats struct {
+	GoalName         string `json:"goal_name"`
+	Status           string `json:"status"`             // completed, failed, or pending if the run ended first
+	Proposals        int    `json:"proposals"`          // Proposals made for it
+	Votes            int    `json:"votes"`              // Votes cast on them
+	FirstProposal    int    `json:"first_proposal"`     // Turn of the first proposal for it; 0 if none was recorded
+	TurnsToConsensus int    `json:"turns_to_consensus"` // Turn it was completed on; 0 unless completed
+	DecidedBy        string `json:"decided_by,omitempty"`
+}
+
+// Stats counts each agent's events, sorted by agent name. With a topic, only
+// events tagged with it are counted, so runs can be compared by what was being
+// discussed; an agent's accepted proposals then only count goals they
+// proposed for on the topic.
+func Stats(turns []Turn, topic string) []AgentStats {
+	byAgent := make(map[string]*AgentStats)
+	proposedFor := make(map[string]map[string]bool) // Goals proposed for, by agent
+	totalWords := 0
+	for _, turn := range turns {
+		active := make(map[string]bool)
+		for _, event := range turn.Events {
+			if topic != "" && !slices.Contains(event.Topics, topic) {
+				continue
+			}
+			stats, ok := byAgent[event.AgentName]
+			if !ok {
+				stats = &AgentStats{AgentName: event.AgentName}
+				byAgent[event.AgentName] = stats
+			}
+			if !active[event.AgentName] {
+				active[event.AgentName] = true
+				stats.Turns++
+			}
+
+			switch {
+			case event.Refusal != nil:
+				stats.Refusals++
+			case event.Type == "pass":
+				stats.Passes++
+			case event.Type == "action":
+				stats.Actions++
+			case event.Type == "monologue":
+				stats.Thoughts++
+			case event.Dialogue != "":
+				stats.Said++
+				words := len(strings.Fields(event.Dialogue))
+				stats.Words += words
+				totalWords += words
+			}
+
+			stats.Proposals += len(event.Proposals)
+			if len(event.Proposals) > 0 && event.Goal != "" {
+				if proposedFor[event.AgentName] == nil {
+					proposedFor[event.AgentName] = make(map[string]bool)
+				}
+				proposedFor[event.AgentName][event.Goal] = true
+			}
+			for _, vote := range event.Votes {
+				stats.Votes++
+				if vote.Choice == "yes" {
+					stats.VotedYes++
+				}
+			}
+		}
+	}
+
+	for _, turn := range turns {
+		for _, completion := range turn.GoalCompletions {
+			stats, ok := byAgent[completion.ProposedBy]
+			if !ok || completion.Status != "completed" {
+				continue
+			}
+			if topic == "" || proposedFor[completion.ProposedBy][completion.GoalName] {
+				stats.Accepted++
+			}
+		}
+	}
+
+	stats := make([]AgentStats, 0, len(byAgent))
+	for _, agentStats := range byAgent {
+		if totalWords > 0 {
+			agentStats.TalkShare = float64(agentStats.Words) / float64(totalWords)
+		}
+		stats = append(stats, *agentStats)
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].AgentName < stats[j].AgentName })
+	return stats
+}
+
+// Goals summarizes how each goal discussed or decided in the run went, in
+// the order goals were first proposed for or decided. Proposals and votes
+// count toward a goal when their event records it.
+func Goals(turns []Turn) []GoalStats {
+	var goals []GoalStats
+	index := make(map[string]int)
+	goal := func(name string) *GoalStats {
+		i, ok := index[name]
+		if !ok {
+			i = len(goals)
+			index[name] = i
+			goals = append(goals, GoalStats{GoalName: name, Status: "pending"})
+		}
+		return &goals[i]
+	}
+
+	for _, turn := range turns {
+		for _, event := range turn.Events {
+			if event.Goal == "" || (len(event.Proposals) == 0 && len(event.Votes) == 0) {
+				continue
+			}
+			stats := goal(event.Goal)
+			if len(event.Proposals) > 0 && stats.FirstProposal == 0 {
+				stats.FirstProposal = turn.Number
+			}
+			stats.Proposals += len(event.Proposals)
+			stats.Votes += len(event.Votes)
+		}
+		for _, completion := range turn.GoalCompletions {
+			stats := goal(completion.GoalName)
+			stats.Status = completion.Status
+			stats.DecidedBy = cmp.Or(completion.CompletedBy, completion.ProposedBy)
+			if completion.Status == "completed" {
+				stats.TurnsToConsensus = completion.CompletedAt
+			}
+		}
+	}
+	return goals
+}
+
+// Topics returns every topic events in the turns are tagged with, sorted.
+func Topics(turns []Turn) []string {
+	var topics []string
+	for _, turn := range turns {
+		for _, event := range turn.Events {
+			topics = append(topics, event.Topics...)
+		}
+	}
+	sort.Strings(topics)
+	return slices.Compact(topics)
+}
@@ -0,0 +1,76 @@
+package chronicle
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStats(t *testing.T) {
+	turns := []Turn{
+		{Number: 1, Events: []Event{
+			{AgentName: "Bob", Type: "dialogue", Dialogue: "Pizza again?", Topics: []string{"food"}},
+			{AgentName: "Alice", Type: "dialogue", Dialogue: "Sure, why not.", Topics: []string{"food"}},
+			{AgentName: "Alice", Type: "monologue", Dialogue: "He always says that."},
+		}},
+		{Number: 2, Events: []Event{
+			{AgentName: "Alice", Type: "pass", Topics: []string{"budget"}},
+			{AgentName: "Bob", Type: "refusal", Refusal: &Refusal{Kind: "declined"}, Topics: []string{"budget"}},
+			{AgentName: "Bob", Type: "action", Dialogue: "checks his wallet", Topics: []string{"budget", "food"}},
+		}},
+	}
+
+	t.Run("counts every event without a topic", func(t *testing.T) {
+		assert.Equal(t, []AgentStats{
+			{AgentName: "Alice", Turns: 2, Said: 1, Thoughts: 1, Passes: 1, Words: 3, TalkShare: 0.6},
+			{AgentName: "Bob", Turns: 2, Said: 1, Actions: 1, Refusals: 1, Words: 2, TalkShare: 0.4},
+		}, Stats(turns, ""))
+	})
+
+	t.Run("counts only events tagged with the topic", func(t *testing.T) {
+		assert.Equal(t, []AgentStats{
+			{AgentName: "Alice", Turns: 1, Said: 1, Words: 3, TalkShare: 0.6},
+			{AgentName: "Bob", Turns: 2, Said: 1, Actions: 1, Words: 2, TalkShare: 0.4},
+		}, Stats(turns, "food"))
+	})
+
+	t.Run("lists topics", func(t *testing.T) {
+		assert.Equal(t, []string{"budget", "food"}, Topics(turns))
+	})
+}
+
+func TestDecisionStats(t *testing.T) {
+	turns := []Turn{
+		{Number: 1, Events: []Event{
+			{AgentName: "Alice", Dialogue: "Bella's?", Goal: "dinner", Topics: []string{"food"}, Proposals: []string{"Bella's"}},
+			{AgentName: "Bob", Dialogue: "Pay later.", Goal: "bill", Topics: []string{"money"}, Proposals: []string{"Pay later"}},
+		}},
+		{Number: 2, Events: []Event{
+			{AgentName: "Bob", Goal: "dinner", Votes: []Vote{{ProposalID: "proposal_1", Choice: "yes"}}},
+			{AgentName: "Alice", Goal: "bill", Votes: []Vote{{ProposalID: "proposal_2", Choice: "no"}}},
+		}},
+		{Number: 3, GoalCompletions: []GoalCompletion{
+			{GoalName: "dinner", Status: "completed", Solution: "Bella's", ProposedBy: "Alice", CompletedAt: 3},
+			{GoalName: "venue", Status: "failed", Solution: "Deadline passed", CompletedAt: 3},
+		}},
+	}
+
+	t.Run("counts proposals, acceptances and votes", func(t *testing.T) {
+		assert.Equal(t, []AgentStats{
+			{AgentName: "Alice", Turns: 2, Said: 1, Words: 1, TalkShare: 1.0 / 3, Proposals: 1, Accepted: 1, Votes: 1},
+			{AgentName: "Bob", Turns: 2, Said: 1, Words: 2, TalkShare: 2.0 / 3, Proposals: 1, Votes: 1, VotedYes: 1},
+		}, Stats(turns, ""))
+
+		stats := Stats(turns, "money")
+		assert.Equal(t, []AgentStats{{AgentName: "Bob", Turns: 1, Said: 1, Words: 2, TalkShare: 1, Proposals: 1}}, stats,
+			"Alice's accepted proposal was about food")
+	})
+
+	t.Run("summarizes goals", func(t *testing.T) {
+		assert.Equal(t, []GoalStats{
+			{GoalName: "dinner", Status: "completed", Proposals: 1, Votes: 1, FirstProposal: 1, TurnsToConsensus: 3, DecidedBy: "Alice"},
+			{GoalName: "bill", Status: "pending", Proposals: 1, Votes: 1, FirstProposal: 1},
+			{GoalName: "venue", Status: "failed"},
+		}, Goals(turns))
+	})
+}
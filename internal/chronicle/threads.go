@@ -0,0 +1,49 @@
+package chronicle
+
+// Post is one event in a goal's thread, with the turn it happened in.
+type Post struct {
+	Turn  int
+	Event Event
+}
+
+// Thread is the discussion of one goal, in the order it happened.
+type Thread struct {
+	Goal  string
+	Posts []Post
+}
+
+// GoalThreads groups what agents said, whispered, proposed and voted by the
+// goal it was about. Threads are ordered by when each goal was first
+// discussed; events about no goal, and actions, thoughts, passes and refusals,
+// are left out.
+func GoalThreads(turns []Turn) []Thread {
+	var threads []Thread
+	index := make(map[string]int)
+	for _, turn := range turns {
+		for _, event := range turn.Events {
+			if event.Goal == "" || !discussion(event) {
+				continue
+			}
+			i, ok := index[event.Goal]
+			if !ok {
+				i = len(threads)
+				index[event.Goal] = i
+				threads = append(threads, Thread{Goal: event.Goal})
+			}
+			threads[i].Posts = append(threads[i].Posts, Post{Turn: turn.Number, Event: event})
+		}
+	}
+	return threads
+}
+
+// discussion reports whether an event said, proposed or voted something.
+func discussion(event Event) bool {
+	if event.Refusal != nil {
+		return false
+	}
+	switch event.Type {
+	case "", "dialogue", "whisper":
+		return event.Dialogue != "" || len(event.Proposals) > 0 || len(event.Votes) > 0
+	}
+	return false
+}
@@ -0,0 +1,36 @@
+package chronicle
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGoalThreads(t *testing.T) {
+	turns := []Turn{
+		{Number: 1, Events: []Event{
+			{AgentName: "Alice", Dialogue: "Let's sort out the budget first.", Goal: "budget"},
+			{AgentName: "Bob", Type: "dialogue", Dialogue: "Bella's for dinner.", Goal: "dinner", Proposals: []string{"Bella's"}},
+			{AgentName: "Bob", Type: "action", Dialogue: "checks his wallet", Goal: "budget"},
+		}},
+		{Number: 2, Events: []Event{
+			{AgentName: "Alice", Type: "whisper", Dialogue: "Bob is broke.", Goal: "budget", Recipient: "Carol"},
+			{AgentName: "Carol", Type: "dialogue", Dialogue: "Nice weather.", Topics: []string{"food"}},
+			{AgentName: "Alice", Type: "dialogue", Goal: "dinner", Votes: []Vote{{ProposalID: "proposal_1", Choice: "no"}}},
+			{AgentName: "Carol", Type: "pass", Dialogue: "Nothing to add.", Goal: "dinner"},
+		}},
+	}
+
+	threads := GoalThreads(turns)
+	assert.Equal(t, []Thread{
+		{Goal: "budget", Posts: []Post{
+			{Turn: 1, Event: turns[0].Events[0]},
+			{Turn: 2, Event: turns[1].Events[0]},
+		}},
+		{Goal: "dinner", Posts: []Post{
+			{Turn: 1, Event: turns[0].Events[1]},
+			{Turn: 2, Event: turns[1].Events[2]},
+		}},
+	}, threads)
+	assert.Empty(t, GoalThreads(nil))
+}
@@ -0,0 +1,113 @@
+package chronicle
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+)
+
+// argumentErrorPrefixes begin the errors the tool server returns for calls it
+// rejected before running them: unknown tools and arguments that don't match
+// the tool's schema.
+var argumentErrorPrefixes = []string{"invalid arguments for ", "tool not found: "}
+
+// ToolUsage summarizes how the agents played by one model used their tools,
+// so models can be compared for tool-heavy scenarios.
+type ToolUsage struct {
+	Model          string      `json:"model"` // Empty for chronicles that didn't record models
+	Agents         []string    `json:"agents"`
+	Turns          int         `json:"turns"`           // Agent turns taken
+	Iterations     int         `json:"iterations"`      // Model requests made over those turns
+	AvgIterations  float64     `json:"avg_iterations"`  // Model requests per turn; 0 if not recorded
+	Calls          int         `json:"calls"`           // Tool calls made
+	Errors         int         `json:"errors"`          // Calls that failed
+	ArgumentErrors int         `json:"argument_errors"` // Calls rejected for naming an unknown tool or bad arguments
+	ArgumentRate   float64     `json:"argument_rate"`   // Share of calls rejected for their arguments, 0-1
+	Tools          []ToolCount `json:"tools"`           // By calls, most called first
+}
+
+// ToolCount counts the calls made to one tool.
+type ToolCount struct {
+	Tool           string `json:"tool"`
+	Calls          int    `json:"calls"`
+	Errors         int    `json:"errors"`
+	ArgumentErrors int    `json:"argument_errors"`
+}
+
+// ToolStats aggregates the tool calls recorded on agents' events by the model
+// that took each turn, sorted by model. Turns are events recording a model or
+// a tool call; runs recorded before models were, are counted under no model.
+func ToolStats(turns []Turn) []ToolUsage {
+	byModel := make(map[string]*ToolUsage)
+	byTool := make(map[string]map[string]*ToolCount)
+	for _, turn := range turns {
+		for _, event := range turn.Events {
+			calls := event.EntriesOf(EntryToolCall)
+			if event.Model == "" && len(calls) == 0 {
+				continue
+			}
+			usage, ok := byModel[event.Model]
+			if !ok {
+				usage = &ToolUsage{Model: event.Model}
+				byModel[event.Model] = usage
+				byTool[event.Model] = make(map[string]*ToolCount)
+			}
+			if !slices.Contains(usage.Agents, event.AgentName) {
+				usage.Agents = append(usage.Agents, event.AgentName)
+			}
+			usage.Turns++
+			usage.Iterations += event.Iterations
+
+			for _, call := range calls {
+				count, ok := byTool[event.Model][call.Tool]
+				if !ok {
+					count = &ToolCount{Tool: call.Tool}
+					byTool[event.Model][call.Tool] = count
+				}
+				usage.Calls++
+				count.Calls++
+				if call.Error == "" {
+					continue
+				}
+				usage.Errors++
+				count.Errors++
+				if argumentError(call.Error) {
+					usage.ArgumentErrors++
+					count.ArgumentErrors++
+				}
+			}
+		}
+	}
+
+	result := make([]ToolUsage, 0, len(byModel))
+	for model, usage := range byModel {
+		slices.Sort(usage.Agents)
+		if usage.Iterations > 0 {
+			usage.AvgIterations = float64(usage.Iterations) / float64(usage.Turns)
+		}
+		if usage.Calls > 0 {
+			usage.ArgumentRate = float64(usage.ArgumentErrors) / float64(usage.Calls)
+		}
+		usage.Tools = make([]ToolCount, 0, len(byTool[model]))
+		for _, count := range byTool[model] {
+			usage.Tools = append(usage.Tools, *count)
+		}
+		slices.SortFunc(usage.Tools, func(a, b ToolCount) int {
+			return cmp.Or(cmp.Compare(b.Calls, a.Calls), cmp.Compare(a.Tool, b.Tool))
+		})
+		result = append(result, *usage)
+	}
+	slices.SortFunc(result, func(a, b ToolUsage) int { return cmp.Compare(a.Model, b.Model) })
+	return result
+}
+
+// argumentError reports whether a tool call failed because the server
+// rejected its tool name or arguments.
+func argumentError(message string) bool {
+	for _, prefix := range argumentErrorPrefixes {
+		if strings.HasPrefix(message, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,56 @@
+package chronicle
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToolStats(t *testing.T) {
+	call := func(tool, err string) Entry {
+		return Entry{Kind: EntryToolCall, Tool: tool, Arguments: "{}", Error: err}
+	}
+	turns := []Turn{
+		{Number: 1, Events: []Event{
+			{AgentName: "Alice", Model: "qwen", Iterations: 3, Entries: []Entry{
+				call("perceive", ""),
+				call("propose_solution", "invalid arguments for propose_solution:\n- solution: is required\nFix these and call propose_solution again."),
+				call("propose_solution", ""),
+				{Kind: EntryProposal, Goal: "dinner", Proposal: "Bella's"},
+			}},
+			{AgentName: "Alice", Dialogue: "I proposed Bella's."},
+			{AgentName: "Bob", Model: "llama", Iterations: 1},
+		}},
+		{Number: 2, Events: []Event{
+			{AgentName: "Bob", Model: "llama", Iterations: 2, Entries: []Entry{
+				call("vote_on_proposal", "proposal not found: proposal_9"),
+				call("look_around", "tool not found: look_around"),
+			}},
+			{AgentName: "Carol", Model: "qwen", Iterations: 2, Entries: []Entry{call("perceive", "")}},
+		}},
+	}
+
+	stats := ToolStats(turns)
+	require.Len(t, stats, 2)
+
+	llama := stats[0]
+	assert.Equal(t, "llama", llama.Model)
+	assert.Equal(t, []string{"Bob"}, llama.Agents)
+	assert.Equal(t, 2, llama.Turns)
+	assert.Equal(t, 1.5, llama.AvgIterations)
+	assert.Equal(t, 2, llama.Errors)
+	assert.Equal(t, 1, llama.ArgumentErrors, "a failed vote isn't an argument error")
+	assert.Equal(t, 0.5, llama.ArgumentRate)
+
+	qwen := stats[1]
+	assert.Equal(t, []string{"Alice", "Carol"}, qwen.Agents)
+	assert.Equal(t, 2, qwen.Turns, "events without a model or tool calls aren't turns")
+	assert.Equal(t, 2.5, qwen.AvgIterations)
+	assert.Equal(t, 4, qwen.Calls)
+	assert.Equal(t, 0.25, qwen.ArgumentRate)
+	assert.Equal(t, []ToolCount{
+		{Tool: "perceive", Calls: 2},
+		{Tool: "propose_solution", Calls: 2, Errors: 1, ArgumentErrors: 1},
+	}, qwen.Tools)
+}
@@ -0,0 +1,334 @@
+package chronicle
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// ViewLine is one line of a chronicle laid out for the terminal viewer,
+// tagged with the turn it came from.
+type ViewLine struct {
+	Text  string
+	Turn  int  // 0 for the header
+	Start bool // First line of a turn
+}
+
+// View lays a chronicle out as lines for a pager and tracks the agent filter,
+// scroll position and search over them. It doesn't touch the terminal, so the
+// viewer only reads keys and draws Lines from Top.
+type View struct {
+	Metadata *Metadata
+	Turns    []Turn
+	Lines    []ViewLine
+	Agents   []string // In order of first appearance
+
+	ShowReasoning bool
+	Top           int // First visible line
+	Width         int
+	Height        int
+	Query         string
+	MatchLine     int // Line of the current match, -1 if none
+
+	agentFilter int // -1 shows all agents, otherwise an index into Agents
+}
+
+// NewView returns a view of the chronicle showing all agents from the top.
+// Call Layout once the terminal size is known.
+func NewView(metadata *Metadata, turns []Turn) *View {
+	v := &View{agentFilter: -1, MatchLine: -1, Width: 80, Height: 24}
+	v.SetChronicle(metadata, turns)
+	return v
+}
+
+// SetChronicle replaces the chronicle being viewed, as when a followed file
+// grows, keeping the agent filter when the agent is still there.
+func (v *View) SetChronicle(metadata *Metadata, turns []Turn) {
+	agent := v.Agent()
+	v.Metadata = metadata
+	v.Turns = turns
+
+	seen := make(map[string]bool)
+	v.Agents = v.Agents[:0]
+	for _, turn := range turns {
+		for _, event := range turn.Events {
+			if !seen[event.AgentName] {
+				seen[event.AgentName] = true
+				v.Agents = append(v.Agents, event.AgentName)
+			}
+		}
+	}
+
+	v.agentFilter = -1
+	for i, name := range v.Agents {
+		if agent != "" && name == agent {
+			v.agentFilter = i
+		}
+	}
+}
+
+// Agent returns the agent the view is filtered to, or "" for all agents.
+func (v *View) Agent() string {
+	if v.agentFilter < 0 || v.agentFilter >= len(v.Agents) {
+		return ""
+	}
+	return v.Agents[v.agentFilter]
+}
+
+// CycleAgent moves the filter to the next agent, then back to all agents.
+func (v *View) CycleAgent() {
+	v.agentFilter++
+	if v.agentFilter >= len(v.Agents) {
+		v.agentFilter = -1
+	}
+	v.MatchLine = -1
+	v.Layout()
+}
+
+// ToggleReasoning shows or hides agents' reasoning.
+func (v *View) ToggleReasoning() {
+	v.ShowReasoning = !v.ShowReasoning
+	v.MatchLine = -1
+	v.Layout()
+}
+
+// Turn returns the turn of the top visible line, 0 in the header.
+func (v *View) Turn() int {
+	if v.Top < len(v.Lines) {
+		return v.Lines[v.Top].Turn
+	}
+	return 0
+}
+
+// Layout renders the chronicle into Lines for the current width and filters.
+func (v *View) Layout() {
+	v.Lines = v.Lines[:0]
+	wrap := v.Width - 4
+	if wrap < 20 {
+		wrap = 20
+	}
+
+	add := func(turn int, text string) {
+		v.Lines = append(v.Lines, ViewLine{Text: text, Turn: turn})
+	}
+	addWrapped := func(turn int, prefix, text string) {
+		for _, line := range wrapText(text, wrap-utf8.RuneCountInString(prefix)) {
+			add(turn, prefix+line)
+		}
+	}
+
+	// Header
+	m := v.Metadata
+	add(0, fmt.Sprintf("\x1b[1m%s\x1b[0m", m.Scenario))
+	add(0, fmt.Sprintf("%s · %s · %s", m.Location, m.Time, m.StartTime.Format("2006-01-02 15:04")))
+	if m.Atmosphere != "" {
+		addWrapped(0, "", m.Atmosphere)
+	}
+	add(0, "")
+
+	agent := v.Agent()
+	for _, turn := range v.Turns {
+		v.Lines = append(v.Lines, ViewLine{Text: fmt.Sprintf("\x1b[1;36m── Turn %d ──\x1b[0m", turn.Number), Turn: turn.Number, Start: true})
+		add(turn.Number, "")
+
+		for _, ambient := range turn.Ambient {
+			addWrapped(turn.Number, "🌦️  ", ambient)
+		}
+		directed := false
+		for _, injection := range turn.Injected {
+			switch injection.Kind {
+			case "atmosphere":
+				addWrapped(turn.Number, "🎭 ", "The mood shifts: "+injection.Content)
+				directed = true
+			case "nudge":
+				addWrapped(turn.Number, "🎭 ", injection.Content)
+				directed = true
+			}
+		}
+		if len(turn.Ambient) > 0 || directed {
+			add(turn.Number, "")
+		}
+
+		for _, event := range turn.Events {
+			if agent != "" && event.AgentName != agent {
+				continue
+			}
+
+			add(turn.Number, fmt.Sprintf("\x1b[1m%s\x1b[0m", event.AgentName))
+			if v.ShowReasoning && event.Reasoning != "" {
+				addWrapped(turn.Number, "  🧠 ", event.Reasoning)
+			}
+			if event.Refusal != nil {
+				outcome := "not recovered"
+				if event.Refusal.Recovered {
+					outcome = "recovered on retry"
+				}
+				add(turn.Number, fmt.Sprintf("  🚫 refused (%s: %s, %s)", event.Refusal.Kind, event.Refusal.Reason, outcome))
+			}
+			if event.Type == "pass" {
+				add(turn.Number, "  ⏭️ passes")
+			}
+			if event.Dialogue != "" {
+				switch event.Type {
+				case "pass":
+					addWrapped(turn.Number, "     ", event.Dialogue)
+				case "refusal":
+					addWrapped(turn.Number, "     ", event.Dialogue)
+				case "action":
+					addWrapped(turn.Number, "  🎬 ", event.Dialogue)
+				case "monologue":
+					addWrapped(turn.Number, "  💭 ", event.Dialogue)
+				case "whisper":
+					addWrapped(turn.Number, "  🤫 ", fmt.Sprintf("to %s: \"%s\"", event.Recipient, event.Dialogue))
+				default:
+					addWrapped(turn.Number, "  💬 ", "\""+event.Dialogue+"\"")
+				}
+			}
+			if event.Emotion != nil && event.Emotion.Before != event.Emotion.After {
+				add(turn.Number, fmt.Sprintf("  😊 %s (%d/10) → %s (%d/10)",
+					event.Emotion.Before.Emotion, event.Emotion.Before.Intensity,
+					event.Emotion.After.Emotion, event.Emotion.After.Intensity))
+			}
+			for _, proposal := range event.Proposals {
+				addWrapped(turn.Number, "  🎯 ", proposal)
+			}
+			for _, vote := range event.Votes {
+				add(turn.Number, fmt.Sprintf("  🗳️  %s %s", vote.Choice, vote.ProposalID))
+			}
+			add(turn.Number, "")
+		}
+
+		for _, skip := range turn.Skipped {
+			if skip.AgentName == "" {
+				add(turn.Number, fmt.Sprintf("⏩ %s phase skipped: %s", skip.Phase, skip.Reason))
+				add(turn.Number, "")
+			}
+		}
+
+		for _, change := range turn.Condition {
+			add(turn.Number, fmt.Sprintf("❤️  %s condition %d → %d (%s)", change.AgentName, change.Before, change.After, change.Cause))
+		}
+		if len(turn.Condition) > 0 {
+			add(turn.Number, "")
+		}
+
+		for _, tieBreak := range turn.TieBreaks {
+			addWrapped(turn.Number, "⚖️  ", fmt.Sprintf("Tied vote on %s's %s broken by %s: %s - %s", tieBreak.GoalName, tieBreak.ProposalID, tieBreak.Policy, tieBreak.Outcome, tieBreak.Reason))
+		}
+
+		for _, completion := range turn.GoalCompletions {
+			statusEmoji := "✅"
+			if completion.Status == "failed" {
+				statusEmoji = "❌"
+			}
+			if completion.CompletedBy != "" {
+				add(turn.Number, fmt.Sprintf("%s Goal %s: %s by %s", statusEmoji, completion.GoalName, completion.Status, completion.CompletedBy))
+			} else {
+				add(turn.Number, fmt.Sprintf("%s Goal %s: %s", statusEmoji, completion.GoalName, completion.Status))
+			}
+			addWrapped(turn.Number, "  ", completion.Solution)
+			add(turn.Number, "")
+		}
+	}
+
+	v.ClampTop()
+}
+
+// wrapText splits text into lines of at most width runes, breaking on spaces.
+func wrapText(text string, width int) []string {
+	if width < 10 {
+		width = 10
+	}
+
+	var lines []string
+	for _, paragraph := range strings.Split(text, "\n") {
+		var line strings.Builder
+		lineLen := 0
+		for _, word := range strings.Fields(paragraph) {
+			wordLen := utf8.RuneCountInString(word)
+			if lineLen > 0 && lineLen+1+wordLen > width {
+				lines = append(lines, line.String())
+				line.Reset()
+				lineLen = 0
+			}
+			if lineLen > 0 {
+				line.WriteByte(' ')
+				lineLen++
+			}
+			line.WriteString(word)
+			lineLen += wordLen
+		}
+		lines = append(lines, line.String())
+	}
+	return lines
+}
+
+// PageSize is the number of chronicle lines that fit above the status bar.
+func (v *View) PageSize() int {
+	if v.Height < 2 {
+		return 1
+	}
+	return v.Height - 1
+}
+
+// ClampTop keeps the last page full and Top within the lines.
+func (v *View) ClampTop() {
+	maxTop := len(v.Lines) - v.PageSize()
+	if v.Top > maxTop {
+		v.Top = maxTop
+	}
+	if v.Top < 0 {
+		v.Top = 0
+	}
+}
+
+// Scroll moves the view by delta lines.
+func (v *View) Scroll(delta int) {
+	v.Top += delta
+	v.ClampTop()
+}
+
+// ScrollToEnd shows the last page.
+func (v *View) ScrollToEnd() {
+	v.Top = len(v.Lines)
+	v.ClampTop()
+}
+
+// JumpTurn moves to the start of the next (dir > 0) or previous (dir < 0) turn.
+func (v *View) JumpTurn(dir int) {
+	for i := v.Top + dir; i >= 0 && i < len(v.Lines); i += dir {
+		if v.Lines[i].Start {
+			v.Top = i
+			v.ClampTop()
+			return
+		}
+	}
+	if dir < 0 {
+		v.Top = 0
+	}
+}
+
+// FindMatch moves to the next (dir > 0) or previous (dir < 0) line containing
+// the query, ignoring case and wrapping around. It reports whether one was found.
+func (v *View) FindMatch(dir int) bool {
+	if v.Query == "" || len(v.Lines) == 0 {
+		return false
+	}
+	query := strings.ToLower(v.Query)
+	start := v.MatchLine
+	if start < 0 {
+		start = v.Top - dir
+	}
+	for step := 1; step <= len(v.Lines); step++ {
+		i := ((start+dir*step)%len(v.Lines) + len(v.Lines)) % len(v.Lines)
+		if strings.Contains(strings.ToLower(v.Lines[i].Text), query) {
+			v.MatchLine = i
+			// Keep the match a few lines below the top for context
+			v.Top = i - 3
+			v.ClampTop()
+			return true
+		}
+	}
+	v.MatchLine = -1
+	return false
+}
@@ -0,0 +1,178 @@
+package chronicle
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func viewTurns() []Turn {
+	return []Turn{
+		{Number: 1, Events: []Event{
+			{AgentName: "Alice", Type: "dialogue", Dialogue: "We should take the north pass", Reasoning: "It is shorter"},
+			{AgentName: "Bob", Type: "action", Dialogue: "unrolls the map"},
+		}},
+		{Number: 2, Events: []Event{
+			{AgentName: "Bob", Type: "dialogue", Dialogue: "The north pass is snowed in"},
+			{AgentName: "Carol", Type: "pass"},
+		}},
+		{Number: 3, Events: []Event{
+			{AgentName: "Alice", Type: "dialogue", Dialogue: "Then the river road"},
+		}},
+	}
+}
+
+func viewText(v *View) string {
+	texts := make([]string, len(v.Lines))
+	for i, line := range v.Lines {
+		texts[i] = line.Text
+	}
+	return strings.Join(texts, "\n")
+}
+
+func newTestView(height int) *View {
+	v := NewView(&Metadata{Scenario: "The Pass", Location: "Base camp", Time: "dawn"}, viewTurns())
+	v.Height = height
+	v.Layout()
+	return v
+}
+
+func TestViewAgentFilter(t *testing.T) {
+	v := newTestView(24)
+	assert.Equal(t, []string{"Alice", "Bob", "Carol"}, v.Agents)
+	assert.Equal(t, "", v.Agent())
+	assert.Contains(t, viewText(v), "north pass is snowed in")
+
+	v.CycleAgent()
+	assert.Equal(t, "Alice", v.Agent())
+	text := viewText(v)
+	assert.Contains(t, text, "river road")
+	assert.NotContains(t, text, "Bob")
+	assert.Contains(t, text, "── Turn 2 ──", "turns without the agent keep their heading")
+
+	v.CycleAgent()
+	v.CycleAgent()
+	assert.Equal(t, "Carol", v.Agent())
+	assert.Contains(t, viewText(v), "passes")
+
+	v.CycleAgent()
+	assert.Equal(t, "", v.Agent(), "cycling past the last agent shows them all again")
+
+	t.Run("reloading keeps the filtered agent", func(t *testing.T) {
+		v.CycleAgent()
+		v.CycleAgent()
+		turns := append([]Turn{{Number: 0, Events: []Event{{AgentName: "Dave", Type: "dialogue", Dialogue: "Hello"}}}}, viewTurns()...)
+		v.SetChronicle(v.Metadata, turns)
+		assert.Equal(t, []string{"Dave", "Alice", "Bob", "Carol"}, v.Agents)
+		assert.Equal(t, "Bob", v.Agent())
+
+		v.SetChronicle(v.Metadata, viewTurns()[:1])
+		assert.Equal(t, "Bob", v.Agent())
+		v.SetChronicle(v.Metadata, viewTurns()[2:])
+		assert.Equal(t, "", v.Agent(), "an agent no longer in the chronicle isn't filtered on")
+	})
+}
+
+func TestViewReasoning(t *testing.T) {
+	v := newTestView(24)
+	assert.NotContains(t, viewText(v), "It is shorter")
+
+	v.ToggleReasoning()
+	assert.Contains(t, viewText(v), "🧠 It is shorter")
+}
+
+func TestViewPaging(t *testing.T) {
+	v := newTestView(5)
+	require.Greater(t, len(v.Lines), 10)
+	assert.Equal(t, 4, v.PageSize(), "one line is left for the status bar")
+
+	v.Scroll(-3)
+	assert.Equal(t, 0, v.Top, "scrolling stops at the start")
+
+	v.Scroll(v.PageSize())
+	assert.Equal(t, 4, v.Top)
+
+	v.ScrollToEnd()
+	assert.Equal(t, len(v.Lines)-v.PageSize(), v.Top, "the last page is full")
+	v.Scroll(10)
+	assert.Equal(t, len(v.Lines)-v.PageSize(), v.Top, "scrolling stops at the end")
+
+	t.Run("short chronicles stay at the top", func(t *testing.T) {
+		short := newTestView(100)
+		short.ScrollToEnd()
+		assert.Equal(t, 0, short.Top)
+	})
+
+	t.Run("tiny terminals show a line", func(t *testing.T) {
+		tiny := newTestView(1)
+		assert.Equal(t, 1, tiny.PageSize())
+	})
+}
+
+func TestViewJumpTurn(t *testing.T) {
+	v := newTestView(5)
+	assert.Equal(t, 0, v.Turn(), "the view opens on the header")
+
+	v.JumpTurn(1)
+	assert.True(t, v.Lines[v.Top].Start)
+	assert.Equal(t, 1, v.Turn())
+
+	v.JumpTurn(1)
+	assert.Equal(t, 2, v.Turn())
+
+	v.JumpTurn(-1)
+	assert.Equal(t, 1, v.Turn())
+
+	v.JumpTurn(-1)
+	assert.Equal(t, 0, v.Top, "jumping back before the first turn returns to the header")
+}
+
+func TestViewFindMatch(t *testing.T) {
+	v := newTestView(5)
+
+	v.Query = "NORTH PASS"
+	require.True(t, v.FindMatch(1), "search ignores case")
+	first := v.MatchLine
+	assert.Contains(t, v.Lines[first].Text, "take the north pass")
+	assert.Equal(t, max(first-3, 0), v.Top, "the match is shown below a few lines of context")
+
+	require.True(t, v.FindMatch(1))
+	second := v.MatchLine
+	assert.Contains(t, v.Lines[second].Text, "snowed in")
+
+	require.True(t, v.FindMatch(1))
+	assert.Equal(t, first, v.MatchLine, "search wraps around")
+
+	require.True(t, v.FindMatch(-1))
+	assert.Equal(t, second, v.MatchLine, "search runs backwards")
+
+	v.Query = "avalanche"
+	assert.False(t, v.FindMatch(1))
+	assert.Equal(t, -1, v.MatchLine)
+
+	v.Query = ""
+	assert.False(t, v.FindMatch(1), "an empty query matches nothing")
+}
+
+func TestWrapText(t *testing.T) {
+	tests := []struct {
+		name  string
+		text  string
+		width int
+		want  []string
+	}{
+		{name: "fits", text: "a short line", width: 20, want: []string{"a short line"}},
+		{name: "breaks on spaces", text: "the quick brown fox jumps", width: 10, want: []string{"the quick", "brown fox", "jumps"}},
+		{name: "keeps paragraphs", text: "one\ntwo", width: 20, want: []string{"one", "two"}},
+		{name: "long words overflow", text: "antidisestablishment", width: 10, want: []string{"antidisestablishment"}},
+		{name: "minimum width", text: "aaaa bbbb cccc", width: 2, want: []string{"aaaa bbbb", "cccc"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, wrapText(tt.text, tt.width))
+		})
+	}
+}
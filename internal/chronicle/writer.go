@@ -0,0 +1,256 @@
+package chronicle
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"strconv"
+)
+
+// checksumPrefix starts the field a chronicle written with checksums ends each
+// line with: the CRC-32 of the line's JSON without the field, as 8 hex digits.
+// The lines stay valid JSON, so readers that don't check sums still read them.
+const checksumPrefix = `,"crc32":"`
+
+// checksumLength is the length of the checksum field and the closing brace.
+const checksumLength = len(checksumPrefix) + 8 + len(`"}`)
+
+// Writer appends records to a chronicle. Records are validated before they
+// are written and buffered until the writer is flushed or synced, so a crash
+// loses at most the records written since the last sync rather than leaving a
+// corrupt line mid-file.
+type Writer struct {
+	file      *os.File
+	buf       *bufio.Writer
+	checksums bool
+}
+
+// Create creates (or truncates) a chronicle file for writing. With
+// checksums, every line carries a CRC-32 readers use to detect damage.
+func Create(path string, checksums bool) (*Writer, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	return &Writer{file: file, buf: bufio.NewWriter(file), checksums: checksums}, nil
+}
+
+// Append opens a chronicle for writing after its first offset bytes,
+// dropping anything written after them (such as a partial turn from a crash).
+func Append(path string, offset int64, checksums bool) (*Writer, error) {
+	file, err := os.OpenFile(path, os.O_RDWR, 0644)
+	if err != nil {
+		return nil, err
+	}
+	if err := file.Truncate(offset); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to truncate chronicle: %w", err)
+	}
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to seek chronicle: %w", err)
+	}
+	return &Writer{file: file, buf: bufio.NewWriter(file), checksums: checksums}, nil
+}
+
+// Write validates a record and buffers it as one JSONL line.
+func (w *Writer) Write(record interface{}) error {
+	if err := Validate(record); err != nil {
+		return err
+	}
+	line, err := ToJSON(record)
+	if err != nil {
+		return err
+	}
+	if w.checksums {
+		line = withChecksum(line)
+	}
+	if _, err := w.buf.Write(line); err != nil {
+		return err
+	}
+	return w.buf.WriteByte('\n')
+}
+
+// Flush hands buffered lines to the operating system, so readers tailing the
+// file see them, without waiting for them to reach the disk.
+func (w *Writer) Flush() error {
+	return w.buf.Flush()
+}
+
+// Sync flushes buffered lines and waits for them to reach the disk.
+func (w *Writer) Sync() error {
+	if err := w.buf.Flush(); err != nil {
+		return err
+	}
+	return w.file.Sync()
+}
+
+// Offset syncs the chronicle and returns its length, the point a resumed
+// run continues writing from.
+func (w *Writer) Offset() (int64, error) {
+	if err := w.Sync(); err != nil {
+		return 0, err
+	}
+	return w.file.Seek(0, io.SeekCurrent)
+}
+
+// Close syncs and closes the chronicle.
+func (w *Writer) Close() error {
+	syncErr := w.Sync()
+	if err := w.file.Close(); err != nil {
+		return err
+	}
+	return syncErr
+}
+
+// Validate checks that a record is one a chronicle holds and has the fields
+// readers rely on.
+func Validate(record interface{}) error {
+	switch r := record.(type) {
+	case Metadata:
+		if r.Type != "metadata" {
+			return fmt.Errorf("metadata record has type %q", r.Type)
+		}
+		if r.SimulationID == "" {
+			return fmt.Errorf("metadata record has no simulation ID")
+		}
+	case Turn:
+		if r.Type != "turn" {
+			return fmt.Errorf("turn record has type %q", r.Type)
+		}
+		if r.Number < 1 {
+			return fmt.Errorf("turn record has number %d", r.Number)
+		}
+		for i, event := range r.Events {
+			if event.AgentName == "" {
+				return fmt.Errorf("turn %d: event %d has no agent", r.Number, i+1)
+			}
+		}
+		for _, completion := range r.GoalCompletions {
+			if completion.GoalName == "" || completion.Status == "" {
+				return fmt.Errorf("turn %d: goal completion needs a goal and a status", r.Number)
+			}
+		}
+	case Partial:
+		if r.Type != "partial" {
+			return fmt.Errorf("partial record has type %q", r.Type)
+		}
+		if r.AgentName == "" {
+			return fmt.Errorf("partial utterance has no agent")
+		}
+	case Usage:
+		if r.Type != "usage" {
+			return fmt.Errorf("usage record has type %q", r.Type)
+		}
+	case End:
+		if r.Type != "end" {
+			return fmt.Errorf("end record has type %q", r.Type)
+		}
+		switch r.Reason {
+		case EndGoalsCompleted, EndGoalsDecided, EndMaxTurns, EndError:
+		default:
+			return fmt.Errorf("end record has unknown reason %q", r.Reason)
+		}
+	default:
+		return fmt.Errorf("%T is not a chronicle record", record)
+	}
+	return nil
+}
+
+// withChecksum adds the checksum field to a line of JSON.
+func withChecksum(line []byte) []byte {
+	sum := crc32.ChecksumIEEE(line)
+	return fmt.Appendf(bytes.Clone(line[:len(line)-1]), `%s%08x"}`, checksumPrefix, sum)
+}
+
+// verifyLine checks a line's checksum, if it has one, and returns the line
+// without it. Lines without checksums are returned as they are.
+func verifyLine(line []byte) ([]byte, error) {
+	n := len(line)
+	if n < checksumLength+2 || !bytes.HasSuffix(line, []byte(`"}`)) ||
+		!bytes.Equal(line[n-checksumLength:n-checksumLength+len(checksumPrefix)], []byte(checksumPrefix)) {
+		return line, nil
+	}
+
+	want, err := strconv.ParseUint(string(line[n-checksumLength+len(checksumPrefix):n-2]), 16, 32)
+	if err != nil {
+		return nil, fmt.Errorf("unreadable checksum")
+	}
+	original := append(bytes.Clone(line[:n-checksumLength]), '}')
+	if crc32.ChecksumIEEE(original) != uint32(want) {
+		return nil, fmt.Errorf("checksum mismatch")
+	}
+	return original, nil
+}
+
+// Repair truncates a chronicle after its last intact line, dropping a line
+// left partly written (or failing its checksum) by a crash. It returns the
+// number of bytes dropped. Damage before the last line is reported rather
+// than repaired, since cutting there would lose whole turns.
+func Repair(path string) (int64, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+
+	for offset := 0; offset < len(data); {
+		end := len(data)
+		if i := bytes.IndexByte(data[offset:], '\n'); i >= 0 {
+			end = offset + i + 1
+		}
+		line := bytes.TrimSpace(data[offset:end])
+		if len(line) > 0 {
+			if err := checkLine(line); err != nil {
+				if len(bytes.TrimSpace(data[end:])) > 0 {
+					return 0, fmt.Errorf("line at byte %d is damaged (%v) and is not the last; not repairing", offset, err)
+				}
+				if err := os.Truncate(path, int64(offset)); err != nil {
+					return 0, err
+				}
+				return int64(len(data) - offset), nil
+			}
+		}
+		offset = end
+	}
+
+	// An intact last line missing only its newline is finished, not dropped
+	if len(data) > 0 && data[len(data)-1] != '\n' {
+		return 0, appendNewline(path)
+	}
+	return 0, nil
+}
+
+// checkLine reports whether a line is an intact chronicle record.
+func checkLine(line []byte) error {
+	line, err := verifyLine(line)
+	if err != nil {
+		return err
+	}
+	var record struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(line, &record); err != nil {
+		return err
+	}
+	if record.Type == "" {
+		return fmt.Errorf("record has no type")
+	}
+	return nil
+}
+
+// appendNewline finishes a chronicle whose last line lacks its newline.
+func appendNewline(path string) error {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := file.WriteString("\n"); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
@@ -0,0 +1,90 @@
+package chronicle
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriter(t *testing.T) {
+	start := time.Date(2025, 3, 14, 18, 30, 0, 0, time.UTC)
+	write := func(t *testing.T, checksums bool) string {
+		path := filepath.Join(t.TempDir(), "chronicle.jsonl")
+		writer, err := Create(path, checksums)
+		require.NoError(t, err)
+		require.NoError(t, writer.Write(NewMetadata(FixedClock{Time: start}, ulid.Make(), "Dinner", "Cafe", "evening", "")))
+		require.NoError(t, writer.Write(Turn{Type: "turn", Number: 1, Events: []Event{{AgentName: "Alice", Dialogue: "Bella's?"}}}))
+		require.NoError(t, writer.Write(Turn{Type: "turn", Number: 2}))
+		require.NoError(t, writer.Close())
+		return path
+	}
+
+	t.Run("round trips with checksums", func(t *testing.T) {
+		path := write(t, true)
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.Contains(t, string(data), `,"crc32":"`)
+
+		metadata, turns, err := ReadFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, "Dinner", metadata.Scenario)
+		assert.True(t, start.Equal(metadata.StartTime))
+		require.Len(t, turns, 2)
+		assert.Equal(t, "Bella's?", turns[0].Events[0].Dialogue)
+	})
+
+	t.Run("rejects invalid records", func(t *testing.T) {
+		writer, err := Create(filepath.Join(t.TempDir(), "chronicle.jsonl"), false)
+		require.NoError(t, err)
+		defer writer.Close()
+
+		assert.ErrorContains(t, writer.Write(Turn{Type: "turn"}), "number 0")
+		assert.ErrorContains(t, writer.Write(Turn{Type: "turn", Number: 1, Events: []Event{{Dialogue: "Hi"}}}), "has no agent")
+		assert.ErrorContains(t, writer.Write(End{Type: "end", Reason: "bored"}), "unknown reason")
+		assert.ErrorContains(t, writer.Write("hello"), "not a chronicle record")
+	})
+
+	t.Run("skips and repairs a partly written last line", func(t *testing.T) {
+		path := write(t, false)
+		appendTo(t, path, `{"type":"turn","numb`)
+
+		_, turns, err := ReadFile(path)
+		require.NoError(t, err)
+		assert.Len(t, turns, 2)
+
+		dropped, err := Repair(path)
+		require.NoError(t, err)
+		assert.Equal(t, int64(len(`{"type":"turn","numb`)), dropped)
+		dropped, err = Repair(path)
+		require.NoError(t, err)
+		assert.Zero(t, dropped)
+	})
+
+	t.Run("reports damage before the last line", func(t *testing.T) {
+		path := write(t, true)
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		data[len(`{"type":"metadata","simulation_id":"`)+2] ^= 1
+		require.NoError(t, os.WriteFile(path, data, 0644))
+
+		_, _, err = ReadFile(path)
+		assert.ErrorContains(t, err, "checksum mismatch")
+		_, err = Repair(path)
+		assert.ErrorContains(t, err, "not repairing")
+	})
+}
+
+// appendTo appends text to a file.
+func appendTo(t *testing.T, path, text string) {
+	t.Helper()
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0644)
+	require.NoError(t, err)
+	_, err = file.WriteString(text)
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+}
@@ -0,0 +1,114 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/poiesic/wonda/internal/memory"
+	"github.com/poiesic/wonda/internal/scenarios"
+	"github.com/poiesic/wonda/internal/simulations"
+	"github.com/spf13/cobra"
+)
+
+var benchCommand = &cobra.Command{
+	Use:   "bench",
+	Short: "Measure simulation overhead on a generated scenario",
+	Long: `Generate a synthetic scenario of the given size and time each stage of running it:
+loading the scenario and characters, initializing the simulation (embeddings and
+character seeding), seeding episodic memories, searching them, and playing turns.
+
+LLM requests are answered by the dry-run mock client, so the timings are wonda's
+own overhead and nothing is spent. Providers, models and the embedding come from
+the config directory; the chronicle the run writes is thrown away.
+
+With --write, the generated scenario and characters are saved to the config
+directory instead, to run with 'wonda scenarios run'.`,
+	Args: cobra.NoArgs,
+	Run:  bench,
+}
+
+var (
+	benchAgents   int
+	benchGoals    int
+	benchMemories int
+	benchTurns    int
+	benchQueries  int
+	benchSeed     int64
+	benchModel    string
+	benchWrite    string
+)
+
+func init() {
+	rootCommand.AddCommand(benchCommand)
+
+	benchCommand.Flags().IntVar(&benchAgents, "agents", 4, "Number of agents")
+	benchCommand.Flags().IntVar(&benchGoals, "goals", 2, "Number of goals")
+	benchCommand.Flags().IntVar(&benchMemories, "memories", 50, "Episodic memories seeded for each agent")
+	benchCommand.Flags().IntVar(&benchTurns, "turns", 5, "Turns to play")
+	benchCommand.Flags().IntVar(&benchQueries, "queries", 100, "Memory searches to time")
+	benchCommand.Flags().Int64Var(&benchSeed, "seed", 0, "Seed for the generated scenario and mock responses")
+	benchCommand.Flags().StringVar(&benchModel, "model", "", "Model the agents use (default: the first configured)")
+	benchCommand.Flags().StringVar(&benchWrite, "write", "", "Save the generated scenario under this name instead of running it")
+}
+
+func bench(cmd *cobra.Command, args []string) {
+	opts := simulations.BenchOptions{
+		FixtureOptions: scenarios.FixtureOptions{
+			Agents:   benchAgents,
+			Goals:    benchGoals,
+			Memories: benchMemories,
+			Turns:    benchTurns,
+			Model:    benchModel,
+			Seed:     benchSeed,
+		},
+		Queries: benchQueries,
+	}
+
+	if benchWrite != "" {
+		if opts.Model == "" {
+			reportErrorAndDieS("--write needs a --model for the scenario")
+		}
+		opts.Name = benchWrite
+		fixture, err := scenarios.GenerateFixture(opts.FixtureOptions)
+		if err != nil {
+			reportErrorAndDie(err)
+		}
+		if err := fixture.Write(configDir, benchWrite); err != nil {
+			reportErrorAndDie(err)
+		}
+		reportSuccess(fmt.Sprintf("✅ Wrote scenario %s with %d characters", benchWrite, len(fixture.Characters)))
+		return
+	}
+
+	// Ensure ONNX environment is cleaned up when the benchmark ends
+	defer memory.DestroyONNXEnvironment()
+
+	report, err := simulations.RunBench(context.Background(), configDir, opts)
+	if err != nil {
+		reportErrorAndDieS(fmt.Sprintf("Benchmark failed: %v", err))
+	}
+
+	fmt.Printf("%d agents, %d goals, %d memories each, model %s\n\n",
+		opts.Agents, opts.Goals, opts.Memories, report.Options.Model)
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "STAGE\tCOUNT\tTOTAL\tPER OP")
+	for _, stage := range report.Stages {
+		fmt.Fprintf(w, "%s\t%d\t%s\t%s\n", stage.Name, stage.Count,
+			stage.Duration.Round(time.Microsecond), stage.PerOp().Round(time.Microsecond))
+	}
+	w.Flush()
+
+	if len(report.Turns) == 0 {
+		return
+	}
+	fmt.Println()
+	w = tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "TURN\tDURATION")
+	for i, turn := range report.Turns {
+		fmt.Fprintf(w, "%d\t%s\n", i+1, turn.Round(time.Microsecond))
+	}
+	w.Flush()
+}
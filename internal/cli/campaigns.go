@@ -0,0 +1,126 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path"
+
+	"github.com/poiesic/wonda/internal/campaigns"
+	"github.com/spf13/cobra"
+)
+
+var campaignsCommand = &cobra.Command{
+	Use:     "campaigns",
+	Short:   "Inspect state carried across a campaign's scenarios",
+	Aliases: []string{"camp"},
+}
+
+var listCampaignsCommand = &cobra.Command{
+	Use:     "list",
+	Short:   "List campaigns",
+	Aliases: []string{"l"},
+	Run:     listCampaigns,
+}
+
+var campaignRelationshipsCommand = &cobra.Command{
+	Use:     "relationships",
+	Short:   "Inspect how characters feel about each other across a campaign",
+	Aliases: []string{"rel"},
+}
+
+var showCampaignRelationshipsCommand = &cobra.Command{
+	Use:     "show <campaign> [character]",
+	Short:   "Display a campaign's relationships, optionally only those involving a character",
+	Aliases: []string{"s"},
+	Args:    cobra.RangeArgs(1, 2),
+	Run:     showCampaignRelationships,
+}
+
+func init() {
+	rootCommand.AddCommand(campaignsCommand)
+	campaignsCommand.AddCommand(listCampaignsCommand, campaignRelationshipsCommand)
+	campaignRelationshipsCommand.AddCommand(showCampaignRelationshipsCommand)
+}
+
+func listCampaigns(cmd *cobra.Command, args []string) {
+	campaignsDir := path.Join(configDir, campaigns.Dir)
+	entries, err := os.ReadDir(campaignsDir)
+	if err != nil && !os.IsNotExist(err) {
+		reportErrorAndDieP(campaignsDir, err)
+	}
+
+	found := false
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		if !found {
+			fmt.Printf("Campaigns in %s:\n\n", campaignsDir)
+			found = true
+		}
+		store, err := campaigns.LoadRelationships(configDir, entry.Name())
+		if err != nil {
+			fmt.Printf("  ❌ %s (%s)\n", entry.Name(), err)
+			continue
+		}
+		fmt.Printf("  • %s (%d relationships)\n", entry.Name(), len(store.Relationships))
+	}
+	if !found {
+		fmt.Println("No campaigns found. Set 'campaign' in a scenario to start one.")
+	}
+}
+
+func showCampaignRelationships(cmd *cobra.Command, args []string) {
+	campaign := args[0]
+	character := ""
+	if len(args) > 1 {
+		character = args[1]
+	}
+
+	store, err := campaigns.LoadRelationships(configDir, campaign)
+	if err != nil {
+		reportErrorAndDie(err)
+	}
+
+	relationships := store.Involving(character)
+	if len(relationships) == 0 {
+		if character != "" {
+			fmt.Printf("No relationships involving %s in campaign %s.\n", character, campaign)
+		} else {
+			fmt.Printf("No relationships recorded in campaign %s.\n", campaign)
+		}
+		return
+	}
+
+	fmt.Printf("Relationships in campaign %s:\n\n", campaign)
+	for _, rel := range relationships {
+		fmt.Printf("  %s → %s: %+d (%s)\n", rel.From, rel.To, rel.Value, describeRelationship(rel.Value))
+		fmt.Printf("    Trust: %+d  Familiarity: %d/%d\n", rel.Trust, rel.Familiarity, campaigns.MaxFamiliarity)
+		if rel.Reason != "" {
+			fmt.Printf("    Reason: %s\n", rel.Reason)
+		}
+		if rel.Scenario != "" {
+			fmt.Printf("    Last changed: %s (%s)\n", rel.Scenario, rel.UpdatedAt.Format("2006-01-02 15:04"))
+		}
+	}
+}
+
+// describeRelationship puts a relationship value into words.
+func describeRelationship(value int) string {
+	switch {
+	case value <= -7:
+		return "enemies"
+	case value <= -3:
+		return "hostile"
+	case value < 0:
+		return "wary"
+	case value == 0:
+		return "neutral"
+	case value < 3:
+		return "friendly"
+	case value < 7:
+		return "close"
+	default:
+		return "allies"
+	}
+}
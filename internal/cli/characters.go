@@ -50,6 +50,8 @@ var listCharactersCommand = &cobra.Command{
 
 func init() {
 	charactersCommand.AddCommand(showCharacterCommand, editCharacterCommand, newCharacterCommand, listCharactersCommand)
+
+	addListFormatFlag(listCharactersCommand)
 }
 
 func showCharacter(cmd *cobra.Command, args []string) {
@@ -119,63 +121,99 @@ func newCharacter(cmd *cobra.Command, args []string) {
 	editFile(tomlFile)
 }
 
+// characterListEntry is one character in `characters list` output.
+type characterListEntry struct {
+	File           string   `json:"file"` // Name in the characters directory, without .toml
+	Archetype      string   `json:"archetype,omitempty"`
+	Description    string   `json:"description,omitempty"`
+	PositiveTraits []string `json:"positive_traits,omitempty"`
+	NegativeTraits []string `json:"negative_traits,omitempty"`
+	Error          string   `json:"error,omitempty"` // Why the file couldn't be loaded
+}
+
 func listCharacters(cmd *cobra.Command, args []string) {
+	asJSON := listAsJSON()
 	charactersDir := path.Join(configDir, "characters")
 
 	entries, err := os.ReadDir(charactersDir)
 	if err != nil {
 		if os.IsNotExist(err) {
+			if asJSON {
+				printJSON([]characterListEntry{})
+				return
+			}
 			reportWarning("No characters directory found. Run 'wonda init' first.")
 			return
 		}
 		reportErrorAndDieP(charactersDir, err)
 	}
 
-	if len(entries) == 0 {
-		fmt.Println("No character definitions found.")
-		return
-	}
-
-	fmt.Printf("Characters in %s:\n\n", charactersDir)
-
+	list := []characterListEntry{}
 	for _, entry := range entries {
 		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".toml") {
 			continue
 		}
 
-		characterFile := path.Join(charactersDir, entry.Name())
-		contents, err := os.ReadFile(characterFile)
+		item := characterListEntry{File: strings.TrimSuffix(entry.Name(), ".toml")}
+		contents, err := os.ReadFile(path.Join(charactersDir, entry.Name()))
 		if err != nil {
-			fmt.Printf("  ❌ %s (error reading file)\n", entry.Name())
+			item.Error = "error reading file"
+			list = append(list, item)
 			continue
 		}
-
 		character, err := scenarios.LoadCharacter(contents)
 		if err != nil {
-			fmt.Printf("  ❌ %s (invalid TOML)\n", entry.Name())
+			item.Error = "invalid TOML"
+			list = append(list, item)
 			continue
 		}
 
-		nameDisplay := strings.TrimSuffix(entry.Name(), ".toml")
-		if character.External != nil && character.External.Archetype != "" {
-			fmt.Printf("  • %s\n", nameDisplay)
-			fmt.Printf("    Archetype: %s\n", character.External.Archetype)
-			if character.External.Description != "" {
-				// Truncate description if too long
-				desc := character.External.Description
-				if len(desc) > 60 {
-					desc = desc[:57] + "..."
-				}
-				fmt.Printf("    Description: %s\n", desc)
-			}
-			if len(character.External.PositiveTraits) > 0 {
-				fmt.Printf("    Positive Traits: %s\n", strings.Join(character.External.PositiveTraits, ", "))
-			}
-			if len(character.External.NegativeTraits) > 0 {
-				fmt.Printf("    Negative Traits: %s\n", strings.Join(character.External.NegativeTraits, ", "))
+		if character.External != nil {
+			item.Archetype = character.External.Archetype
+			item.Description = character.External.Description
+			item.PositiveTraits = character.External.PositiveTraits
+			item.NegativeTraits = character.External.NegativeTraits
+		}
+		list = append(list, item)
+	}
+
+	if asJSON {
+		printJSON(list)
+		return
+	}
+
+	if len(list) == 0 {
+		fmt.Println("No character definitions found.")
+		return
+	}
+
+	fmt.Printf("Characters in %s:\n\n", charactersDir)
+
+	for _, item := range list {
+		if item.Error != "" {
+			fmt.Printf("  ❌ %s.toml (%s)\n", item.File, item.Error)
+			continue
+		}
+		if item.Archetype == "" {
+			fmt.Printf("  • %s (incomplete)\n", item.File)
+			continue
+		}
+
+		fmt.Printf("  • %s\n", item.File)
+		fmt.Printf("    Archetype: %s\n", item.Archetype)
+		if item.Description != "" {
+			// Truncate description if too long
+			desc := item.Description
+			if len(desc) > 60 {
+				desc = desc[:57] + "..."
 			}
-		} else {
-			fmt.Printf("  • %s (incomplete)\n", nameDisplay)
+			fmt.Printf("    Description: %s\n", desc)
+		}
+		if len(item.PositiveTraits) > 0 {
+			fmt.Printf("    Positive Traits: %s\n", strings.Join(item.PositiveTraits, ", "))
+		}
+		if len(item.NegativeTraits) > 0 {
+			fmt.Printf("    Negative Traits: %s\n", strings.Join(item.NegativeTraits, ", "))
 		}
 	}
 }
@@ -5,6 +5,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -23,36 +26,69 @@ var chronicleExportCommand = &cobra.Command{
 	Use:     "export <chronicle-file>",
 	Aliases: []string{"e"},
 	Short:   "Export a chronicle file to readable format",
-	Long:    "Export a chronicle JSONL file to Markdown (default) or pretty JSON",
+	Long:    "Export a chronicle JSONL file to Markdown (default), pretty JSON, CSV with one row per event, or a self-contained HTML page",
 	Args:    cobra.ExactArgs(1),
 	Run:     chronicleExport,
 }
 
 var chronicleTailCommand = &cobra.Command{
-	Use:     "tail <chronicle-file>",
+	Use:     "tail [chronicle-file]",
 	Aliases: []string{"t"},
 	Short:   "Stream chronicle entries as they're written",
-	Long:    "Continuously monitor a chronicle file and output new entries in Markdown format",
-	Args:    cobra.ExactArgs(1),
-	Run:     chronicleTail,
+	Long: `Continuously monitor a chronicle file and output new entries in Markdown format.
+
+With --dir, tail the newest chronicle in a directory instead, waiting for one if
+there are none yet. Add --latest to switch to each new chronicle that appears
+there, which is convenient when launching runs one after another from another
+terminal.`,
+	Args: cobra.MaximumNArgs(1),
+	Run:  chronicleTail,
+}
+
+var chronicleRepairCommand = &cobra.Command{
+	Use:   "repair <chronicle-file>",
+	Short: "Drop a damaged last line left by a crash",
+	Long: `Truncate a chronicle after its last intact line. A run that crashed while
+writing can leave a partly written line (or, with --checksums, one that fails
+its checksum) at the end of the file; repair removes it. Damage before the last
+line is reported and left alone.`,
+	Args: cobra.ExactArgs(1),
+	Run:  chronicleRepair,
 }
 
 var exportFormat string
 var tailPollInterval time.Duration
+var tailDir string
+var tailLatest bool
 
 func init() {
 	rootCommand.AddCommand(chronicleCommand)
-	chronicleCommand.AddCommand(chronicleExportCommand, chronicleTailCommand)
+	chronicleCommand.AddCommand(chronicleExportCommand, chronicleTailCommand, chronicleRepairCommand)
 
-	chronicleExportCommand.Flags().StringVar(&exportFormat, "format", "markdown", "Output format: markdown or json")
+	chronicleExportCommand.Flags().StringVar(&exportFormat, "format", "markdown", "Output format: markdown, json, csv, or html")
 	chronicleTailCommand.Flags().DurationVar(&tailPollInterval, "interval", 100*time.Millisecond, "Polling interval for checking file updates")
+	chronicleTailCommand.Flags().StringVar(&tailDir, "dir", "", "Tail the newest chronicle in this directory")
+	chronicleTailCommand.Flags().BoolVar(&tailLatest, "latest", false, "With --dir, switch to each new chronicle as runs start")
+}
+
+func chronicleRepair(cmd *cobra.Command, args []string) {
+	chroniclePath := args[0]
+	dropped, err := chronicle.Repair(chroniclePath)
+	if err != nil {
+		reportErrorAndDieS(fmt.Sprintf("Failed to repair chronicle: %v", err))
+	}
+	if dropped == 0 {
+		fmt.Println("Chronicle is intact.")
+		return
+	}
+	fmt.Printf("Dropped a damaged last line (%d bytes).\n", dropped)
 }
 
 func chronicleExport(cmd *cobra.Command, args []string) {
 	chroniclePath := args[0]
 
 	// Read and parse the JSONL file
-	metadata, turns, err := readChronicleFile(chroniclePath)
+	metadata, turns, err := chronicle.ReadFile(chroniclePath)
 	if err != nil {
 		reportErrorAndDieS(fmt.Sprintf("Failed to read chronicle: %v", err))
 	}
@@ -63,46 +99,127 @@ func chronicleExport(cmd *cobra.Command, args []string) {
 		exportMarkdown(metadata, turns)
 	case "json":
 		exportJSON(metadata, turns)
+	case "csv":
+		exportCSV(turns)
+	case "html":
+		exportHTML(metadata, turns)
 	default:
-		reportErrorAndDieS(fmt.Sprintf("Unknown format: %s (use 'markdown' or 'json')", exportFormat))
+		reportErrorAndDieS(fmt.Sprintf("Unknown format: %s (use 'markdown', 'json', 'csv', or 'html')", exportFormat))
 	}
 }
 
 func chronicleTail(cmd *cobra.Command, args []string) {
-	chroniclePath := args[0]
+	switch {
+	case len(args) == 1 && tailDir != "":
+		reportErrorAndDieS("Give a chronicle file or --dir, not both")
+	case len(args) == 0 && tailDir == "":
+		reportErrorAndDieS("Give a chronicle file to tail, or --dir to tail the newest in a directory")
+	case tailLatest && tailDir == "":
+		reportErrorAndDieS("--latest needs --dir")
+	}
 
-	// Check if file exists
-	fileInfo, err := os.Stat(chroniclePath)
-	if err != nil {
-		if os.IsNotExist(err) {
-			reportErrorAndDieS(fmt.Sprintf("Chronicle file not found: %s", chroniclePath))
+	chroniclePath := ""
+	seen := make(map[string]bool)
+	if len(args) == 1 {
+		chroniclePath = args[0]
+	} else {
+		// Attach to the newest chronicle, waiting for one if there are none yet
+		var err error
+		for chroniclePath == "" {
+			chroniclePath, err = newestChronicle(tailDir, seen)
+			if err != nil {
+				reportErrorAndDieS(fmt.Sprintf("Failed to read directory: %v", err))
+			}
+			if chroniclePath == "" {
+				time.Sleep(tailPollInterval)
+			}
 		}
-		reportErrorAndDieS(fmt.Sprintf("Failed to access file: %v", err))
 	}
 
+	t := openTail(chroniclePath)
+	for {
+		t.poll()
+		time.Sleep(tailPollInterval)
+
+		// Switch to a chronicle started since the last look
+		if !tailLatest {
+			continue
+		}
+		newer, err := newChronicle(tailDir, seen)
+		if err != nil {
+			reportErrorAndDieS(fmt.Sprintf("Failed to read directory: %v", err))
+		}
+		if newer != "" {
+			t.close()
+			fmt.Printf("*⏭️ Following %s*\n\n", newer)
+			t = openTail(newer)
+		}
+	}
+}
+
+// chronicleTailer prints a chronicle's entries as Markdown as they are written.
+type chronicleTailer struct {
+	path      string
+	file      *os.File
+	metadata  *chronicle.Metadata
+	lineCount int
+	lastSize  int64
+}
+
+// openTail opens a chronicle and prints what it holds so far.
+func openTail(chroniclePath string) *chronicleTailer {
 	// Open the file
 	file, err := os.Open(chroniclePath)
 	if err != nil {
+		if os.IsNotExist(err) {
+			reportErrorAndDieS(fmt.Sprintf("Chronicle file not found: %s", chroniclePath))
+		}
 		reportErrorAndDieS(fmt.Sprintf("Failed to open file: %v", err))
 	}
-	defer file.Close()
 
-	// Read and output existing contents
-	var metadata *chronicle.Metadata
-	lineCount := 0
-	lastSize := fileInfo.Size()
+	// A new chronicle doesn't continue the last one's streaming utterance
+	tailPartial = nil
+
+	t := &chronicleTailer{path: chroniclePath, file: file}
+	t.poll()
+	return t
+}
+
+// poll prints entries written since the last poll.
+func (t *chronicleTailer) poll() {
+	// Check current file size
+	fileInfo, err := os.Stat(t.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			reportErrorAndDieS("Chronicle file was deleted")
+		}
+		reportErrorAndDieS(fmt.Sprintf("Failed to stat file: %v", err))
+	}
+
+	currentSize := fileInfo.Size()
+
+	// Check for truncation
+	if currentSize < t.lastSize {
+		reportErrorAndDieS("Chronicle file was truncated")
+	}
+
+	// Check if there's new data
+	if currentSize == t.lastSize {
+		return
+	}
 
-	scanner := bufio.NewScanner(file)
+	// Read new content
+	scanner := bufio.NewScanner(t.file)
 	for scanner.Scan() {
 		line := scanner.Text()
-		lineCount++
+		t.lineCount++
 		if line == "" {
 			continue
 		}
 
 		// Parse and output the entry
-		if err := parseLine(line, &metadata); err != nil {
-			reportErrorAndDieS(fmt.Sprintf("Failed to parse line %d: %v", lineCount, err))
+		if err := parseLine(line, &t.metadata); err != nil {
+			reportErrorAndDieS(fmt.Sprintf("Failed to parse line %d: %v", t.lineCount, err))
 		}
 	}
 
@@ -110,51 +227,56 @@ func chronicleTail(cmd *cobra.Command, args []string) {
 		reportErrorAndDieS(fmt.Sprintf("Error reading file: %v", err))
 	}
 
-	// Start polling for new content
-	for {
-		time.Sleep(tailPollInterval)
+	// Update size tracking
+	t.lastSize = currentSize
+}
 
-		// Check current file size
-		fileInfo, err := os.Stat(chroniclePath)
-		if err != nil {
-			if os.IsNotExist(err) {
-				reportErrorAndDieS("Chronicle file was deleted")
-			}
-			reportErrorAndDieS(fmt.Sprintf("Failed to stat file: %v", err))
-		}
+// close closes the chronicle.
+func (t *chronicleTailer) close() {
+	t.file.Close()
+}
 
-		currentSize := fileInfo.Size()
+// newestChronicle returns the most recently modified chronicle in a
+// directory, or "" if there are none, and marks every chronicle there seen.
+func newestChronicle(dir string, seen map[string]bool) (string, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "chronicle-*.jsonl"))
+	if err != nil {
+		return "", err
+	}
 
-		// Check for truncation
-		if currentSize < lastSize {
-			reportErrorAndDieS("Chronicle file was truncated")
+	newest := ""
+	var newestTime time.Time
+	for _, chroniclePath := range paths {
+		seen[chroniclePath] = true
+		info, err := os.Stat(chroniclePath)
+		if err != nil {
+			continue
 		}
+		if newest == "" || info.ModTime().After(newestTime) {
+			newest, newestTime = chroniclePath, info.ModTime()
+		}
+	}
+	return newest, nil
+}
 
-		// Check if there's new data
-		if currentSize > lastSize {
-			// Read new content
-			newScanner := bufio.NewScanner(file)
-			for newScanner.Scan() {
-				line := newScanner.Text()
-				lineCount++
-				if line == "" {
-					continue
-				}
-
-				// Parse and output the entry
-				if err := parseLine(line, &metadata); err != nil {
-					reportErrorAndDieS(fmt.Sprintf("Failed to parse line %d: %v", lineCount, err))
-				}
-			}
-
-			if err := newScanner.Err(); err != nil {
-				reportErrorAndDieS(fmt.Sprintf("Error reading new content: %v", err))
-			}
+// newChronicle returns a chronicle that appeared in a directory since it was
+// last looked at, or "" if none has. If several have, the newest is returned.
+func newChronicle(dir string, seen map[string]bool) (string, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "chronicle-*.jsonl"))
+	if err != nil {
+		return "", err
+	}
 
-			// Update size tracking
-			lastSize = currentSize
+	fresh := make(map[string]bool)
+	for _, chroniclePath := range paths {
+		if !seen[chroniclePath] {
+			fresh[chroniclePath] = true
 		}
 	}
+	if len(fresh) == 0 {
+		return "", nil
+	}
+	return newestChronicle(dir, seen)
 }
 
 // parseLine parses a single JSONL line and outputs it as Markdown.
@@ -184,6 +306,27 @@ func parseLine(line string, metadata **chronicle.Metadata) error {
 		}
 		outputTurnMarkdown(&t)
 
+	case "partial":
+		var p chronicle.Partial
+		if err := json.Unmarshal([]byte(line), &p); err != nil {
+			return fmt.Errorf("failed to parse partial: %w", err)
+		}
+		outputPartial(&p)
+
+	case "usage":
+		var u chronicle.Usage
+		if err := json.Unmarshal([]byte(line), &u); err != nil {
+			return fmt.Errorf("failed to parse usage: %w", err)
+		}
+		outputUsageMarkdown(&u)
+
+	case "end":
+		var e chronicle.End
+		if err := json.Unmarshal([]byte(line), &e); err != nil {
+			return fmt.Errorf("failed to parse end: %w", err)
+		}
+		outputEndMarkdown(&e)
+
 	default:
 		return fmt.Errorf("unknown entry type: %s", typeCheck.Type)
 	}
@@ -191,6 +334,28 @@ func parseLine(line string, metadata **chronicle.Metadata) error {
 	return nil
 }
 
+// tailPartial is the streaming utterance printed so far by chronicle tail.
+var tailPartial *chronicle.Partial
+
+// outputPartial prints a streaming utterance as it grows, then ends the line
+// when it is final. The complete event is printed again with its turn.
+func outputPartial(p *chronicle.Partial) {
+	if tailPartial != nil && tailPartial.AgentName == p.AgentName && strings.HasPrefix(p.Text, tailPartial.Text) {
+		fmt.Print(p.Text[len(tailPartial.Text):])
+	} else {
+		if tailPartial != nil {
+			fmt.Print("\n\n")
+		}
+		fmt.Printf("*%s is speaking:* %s", p.AgentName, p.Text)
+	}
+
+	tailPartial = p
+	if p.Final {
+		fmt.Print("\n\n")
+		tailPartial = nil
+	}
+}
+
 // outputMetadataMarkdown outputs metadata as Markdown header.
 func outputMetadataMarkdown(m *chronicle.Metadata) {
 	fmt.Printf("# Simulation Chronicle: %s\n\n", m.Scenario)
@@ -200,16 +365,99 @@ func outputMetadataMarkdown(m *chronicle.Metadata) {
 	if m.Atmosphere != "" {
 		fmt.Printf("**Atmosphere:** %s  \n", m.Atmosphere)
 	}
+	if m.ReasoningShared {
+		fmt.Printf("**Reasoning:** shared  \n")
+	}
 	fmt.Printf("**Started:** %s  \n", m.StartTime.Format("2006-01-02 15:04:05"))
 	fmt.Println()
+	if len(m.Characters) > 0 {
+		outputCastMarkdown(m.Characters)
+	}
 	fmt.Println("---")
 	fmt.Println()
 }
 
+// outputCastMarkdown lists the agents whose characters have profiles, with
+// their pronouns, age and bio.
+func outputCastMarkdown(characters map[string]chronicle.CharacterProfile) {
+	names := make([]string, 0, len(characters))
+	for name := range characters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Printf("## Cast\n\n")
+	for _, name := range names {
+		profile := characters[name]
+		var about []string
+		if profile.Pronouns != "" {
+			about = append(about, profile.Pronouns)
+		}
+		if profile.Age > 0 {
+			about = append(about, strconv.Itoa(profile.Age))
+		}
+		fmt.Printf("- **%s**", name)
+		if len(about) > 0 {
+			fmt.Printf(" (%s)", strings.Join(about, ", "))
+		}
+		if profile.Bio != "" {
+			fmt.Printf(": %s", profile.Bio)
+		}
+		fmt.Println()
+	}
+	fmt.Println()
+}
+
+// outputEndMarkdown outputs why the simulation ended as Markdown.
+func outputEndMarkdown(e *chronicle.End) {
+	switch e.Reason {
+	case chronicle.EndGoalsCompleted:
+		fmt.Printf("*Simulation ended after %d turns: all goals completed.*\n\n", e.Turns)
+	case chronicle.EndGoalsDecided:
+		fmt.Printf("*Simulation ended after %d turns: all goals completed or failed.*\n\n", e.Turns)
+	case chronicle.EndMaxTurns:
+		fmt.Printf("*Simulation ended after %d turns: turn limit of %d reached.*\n\n", e.Turns, e.MaxTurns)
+	default:
+		fmt.Printf("*Simulation stopped after %d turns: %s.*\n\n", e.Turns, e.Error)
+	}
+}
+
+// outputUsageMarkdown outputs the run's token usage and cost as Markdown.
+func outputUsageMarkdown(u *chronicle.Usage) {
+	if u.Total.Requests == 0 {
+		return
+	}
+	fmt.Printf("## Usage\n\n")
+	fmt.Printf("%d requests, %d input tokens, %d output tokens", u.Total.Requests, u.Total.InputTokens, u.Total.OutputTokens)
+	if u.Total.Cost > 0 {
+		fmt.Printf(", cost %.4f", u.Total.Cost)
+	}
+	fmt.Printf("\n\n")
+
+	names := make([]string, 0, len(u.Agents))
+	for name := range u.Agents {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		totals := u.Agents[name]
+		fmt.Printf("- **%s**: %d requests, %d input tokens, %d output tokens", name, totals.Requests, totals.InputTokens, totals.OutputTokens)
+		if totals.Cost > 0 {
+			fmt.Printf(", cost %.4f", totals.Cost)
+		}
+		fmt.Printf("\n")
+	}
+	fmt.Printf("\n")
+}
+
 // outputTurnMarkdown outputs a turn as Markdown.
 func outputTurnMarkdown(t *chronicle.Turn) {
 	fmt.Printf("## Turn %d\n\n", t.Number)
 
+	for _, ambient := range t.Ambient {
+		fmt.Printf("*🌦️ %s*\n\n", ambient)
+	}
+
 	for _, event := range t.Events {
 		fmt.Printf("### %s\n\n", event.AgentName)
 
@@ -219,8 +467,34 @@ func outputTurnMarkdown(t *chronicle.Turn) {
 			fmt.Printf("> %s\n\n", event.Reasoning)
 		}
 
+		// Refusal
+		if event.Refusal != nil {
+			outcome := "not recovered"
+			if event.Refusal.Recovered {
+				outcome = "recovered on retry"
+			}
+			fmt.Printf("**🚫 Refused** (%s: %s, %s)\n\n", event.Refusal.Kind, event.Refusal.Reason, outcome)
+			if event.Dialogue != "" {
+				fmt.Printf("> %s\n\n", event.Dialogue)
+			}
+		}
+
+		// Fallback for a failed model
+		if event.Fallback != "" {
+			fmt.Printf("**🛟 Fallback** (model failed: %s)\n\n", event.Fallback)
+		}
+
+		// Pass
+		if event.Type == "pass" {
+			fmt.Printf("**⏭️ Passes**\n")
+			if event.Dialogue != "" {
+				fmt.Printf("> _%s_\n", event.Dialogue)
+			}
+			fmt.Printf("\n")
+		}
+
 		// Dialogue/Action/Monologue
-		if event.Dialogue != "" {
+		if event.Dialogue != "" && event.Refusal == nil && event.Type != "pass" {
 			switch event.Type {
 			case "action":
 				fmt.Printf("**🎬 Does:**\n")
@@ -228,10 +502,16 @@ func outputTurnMarkdown(t *chronicle.Turn) {
 			case "monologue":
 				fmt.Printf("**💭 Thinks:**\n")
 				fmt.Printf("> _%s_\n\n", event.Dialogue)
+			case "whisper":
+				fmt.Printf("**🤫 Whispers to %s:**\n", event.Recipient)
+				fmt.Printf("> \"%s\"\n\n", event.Dialogue)
 			default: // "dialogue" or empty (default to dialogue)
 				fmt.Printf("**💬 Says:**\n")
 				fmt.Printf("> \"%s\"\n\n", event.Dialogue)
 			}
+			if event.Rationale != "" {
+				fmt.Printf("**🗣️ Because:** %s\n\n", event.Rationale)
+			}
 		}
 
 		// Emotion
@@ -241,6 +521,9 @@ func outputTurnMarkdown(t *chronicle.Turn) {
 				event.Emotion.Before.Intensity,
 				event.Emotion.After.Emotion,
 				event.Emotion.After.Intensity)
+			if event.Emotion.Cause != "" {
+				fmt.Printf("*Because: %s*\n\n", event.Emotion.Cause)
+			}
 		}
 
 		// Proposals
@@ -265,10 +548,72 @@ func outputTurnMarkdown(t *chronicle.Turn) {
 			fmt.Println()
 		}
 
+		// Ensemble candidates
+		if len(event.Candidates) > 0 {
+			fmt.Printf("**🎲 Candidates:**\n")
+			for _, candidate := range event.Candidates {
+				selectedSymbol := " "
+				if candidate.Selected {
+					selectedSymbol = "✓"
+				}
+				summary := candidate.Dialogue
+				if len(candidate.ToolCalls) > 0 {
+					summary = strings.Join(candidate.ToolCalls, ", ")
+				}
+				fmt.Printf("- %s [%s] %s\n", selectedSymbol, candidate.Model, summary)
+			}
+			fmt.Println()
+		}
+
+		// Tools called
+		if toolCalls := event.EntriesOf(chronicle.EntryToolCall); len(toolCalls) > 0 {
+			fmt.Printf("**🔧 Tools:**\n")
+			for _, call := range toolCalls {
+				if call.Error != "" {
+					fmt.Printf("- `%s(%s)` failed: %s\n", call.Tool, call.Arguments, call.Error)
+					continue
+				}
+				fmt.Printf("- `%s(%s)`\n", call.Tool, call.Arguments)
+			}
+			fmt.Println()
+		}
+
+		// Cited memories
+		if len(event.Citations) > 0 {
+			fmt.Printf("**📎 Cites:**\n")
+			for _, citation := range event.Citations {
+				if citation.Unknown {
+					fmt.Printf("- `%s` (unknown memory)\n", citation.MemoryID)
+					continue
+				}
+				fmt.Printf("- `%s` [%s/%s] %s\n", citation.MemoryID, citation.Type, citation.Category, citation.Content)
+			}
+			fmt.Println()
+		}
+
 		fmt.Println("---")
 		fmt.Println()
 	}
 
+	// Skipped phases
+	for _, skip := range t.Skipped {
+		if skip.AgentName != "" {
+			fmt.Printf("*⏩ Skipped %s's %s turn: %s*\n\n", skip.AgentName, skip.Phase, skip.Reason)
+		} else {
+			fmt.Printf("*⏩ Skipped %s phase: %s*\n\n", skip.Phase, skip.Reason)
+		}
+	}
+
+	// Condition changes
+	for _, change := range t.Condition {
+		fmt.Printf("*❤️ %s's condition: %d → %d (%s)*\n\n", change.AgentName, change.Before, change.After, change.Cause)
+	}
+
+	// Tied votes broken
+	for _, tieBreak := range t.TieBreaks {
+		fmt.Printf("*⚖️ Tied vote on %s's %s (%s) broken by %s: %s - %s*\n\n", tieBreak.GoalName, tieBreak.ProposalID, tieBreak.Proposal, tieBreak.Policy, tieBreak.Outcome, tieBreak.Reason)
+	}
+
 	// Goal completions
 	if len(t.GoalCompletions) > 0 {
 		fmt.Printf("### 🏆 Goal Completions\n\n")
@@ -280,14 +625,61 @@ func outputTurnMarkdown(t *chronicle.Turn) {
 
 			fmt.Printf("**%s Goal: %s**\n\n", statusEmoji, completion.GoalName)
 			fmt.Printf("**Solution:** %s\n\n", completion.Solution)
-			fmt.Printf("**Proposed by:** %s\n\n", completion.ProposedBy)
+			switch {
+			case completion.Status == "failed" && completion.ProposedBy == "":
+				// Expired goals have no one to credit
+			case completion.JudgedBy != "":
+				fmt.Printf("**Judged by:** %s (confidence %.2f)\n\n", completion.JudgedBy, completion.Confidence)
+			case completion.CompletedBy != "":
+				fmt.Printf("**Completed by:** %s\n\n", completion.CompletedBy)
+			default:
+				fmt.Printf("**Proposed by:** %s\n\n", completion.ProposedBy)
+			}
 
-			if len(completion.VotedYes) > 0 {
+			switch {
+			case len(completion.VotedYes) > 0 && completion.Support > 0:
+				fmt.Printf("**Voted Yes:** %s (%.0f%% support)\n\n", joinSlice(completion.VotedYes), completion.Support*100)
+			case len(completion.VotedYes) > 0:
+				// Chronicles from before support was recorded
 				fmt.Printf("**Voted Yes:** %s\n\n", joinSlice(completion.VotedYes))
 			}
 			if len(completion.VotedNo) > 0 {
 				fmt.Printf("**Voted No:** %s\n\n", joinSlice(completion.VotedNo))
 			}
+			if len(completion.Offers) > 0 {
+				fmt.Printf("**Offers:**\n\n")
+				for _, offer := range completion.Offers {
+					fmt.Printf("- %s (turn %d, %s for %s): %s", offer.ProposalID, offer.Turn, offer.ProposedBy, offer.Side, offer.Terms)
+					if offer.Amount != nil {
+						fmt.Printf(" (%g", *offer.Amount)
+						if offer.Concession > 0 {
+							fmt.Printf(", conceding %g", offer.Concession)
+						}
+						fmt.Printf(")")
+					}
+					if offer.Counters != "" {
+						fmt.Printf(", countering %s", offer.Counters)
+					}
+					fmt.Println()
+				}
+				fmt.Println()
+			}
+			if len(completion.Runoff) > 0 {
+				fmt.Printf("**Ranked choice:**\n\n")
+				for i, round := range completion.Runoff {
+					counts := make([]string, 0, len(round.Counts))
+					for id, count := range round.Counts {
+						counts = append(counts, fmt.Sprintf("%s %d", id, count))
+					}
+					sort.Strings(counts)
+					fmt.Printf("- Round %d: %s", i+1, strings.Join(counts, ", "))
+					if round.Eliminated != "" {
+						fmt.Printf(" (%s eliminated)", round.Eliminated)
+					}
+					fmt.Println()
+				}
+				fmt.Println()
+			}
 
 			fmt.Println("---")
 			fmt.Println()
@@ -295,59 +687,6 @@ func outputTurnMarkdown(t *chronicle.Turn) {
 	}
 }
 
-// readChronicleFile reads and parses a JSONL chronicle file.
-func readChronicleFile(path string) (*chronicle.Metadata, []chronicle.Turn, error) {
-	file, err := os.Open(path)
-	if err != nil {
-		return nil, nil, err
-	}
-	defer file.Close()
-
-	var metadata *chronicle.Metadata
-	var turns []chronicle.Turn
-
-	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
-		line := scanner.Text()
-		if line == "" {
-			continue
-		}
-
-		// Parse JSON to determine type
-		var typeCheck struct {
-			Type string `json:"type"`
-		}
-		if err := json.Unmarshal([]byte(line), &typeCheck); err != nil {
-			return nil, nil, fmt.Errorf("failed to parse line: %w", err)
-		}
-
-		switch typeCheck.Type {
-		case "metadata":
-			var m chronicle.Metadata
-			if err := json.Unmarshal([]byte(line), &m); err != nil {
-				return nil, nil, fmt.Errorf("failed to parse metadata: %w", err)
-			}
-			metadata = &m
-		case "turn":
-			var t chronicle.Turn
-			if err := json.Unmarshal([]byte(line), &t); err != nil {
-				return nil, nil, fmt.Errorf("failed to parse turn: %w", err)
-			}
-			turns = append(turns, t)
-		}
-	}
-
-	if err := scanner.Err(); err != nil {
-		return nil, nil, err
-	}
-
-	if metadata == nil {
-		return nil, nil, fmt.Errorf("no metadata found in chronicle")
-	}
-
-	return metadata, turns, nil
-}
-
 // exportJSON exports the chronicle as pretty-printed JSON.
 func exportJSON(metadata *chronicle.Metadata, turns []chronicle.Turn) {
 	output := map[string]interface{}{
@@ -362,6 +701,20 @@ func exportJSON(metadata *chronicle.Metadata, turns []chronicle.Turn) {
 	}
 }
 
+// exportCSV exports the chronicle's events as CSV, one row per event.
+func exportCSV(turns []chronicle.Turn) {
+	if err := chronicle.WriteCSV(os.Stdout, turns); err != nil {
+		reportErrorAndDieS(fmt.Sprintf("Failed to write CSV: %v", err))
+	}
+}
+
+// exportHTML exports the chronicle as a self-contained HTML page.
+func exportHTML(metadata *chronicle.Metadata, turns []chronicle.Turn) {
+	if err := chronicle.WriteHTML(os.Stdout, metadata, turns); err != nil {
+		reportErrorAndDieS(fmt.Sprintf("Failed to write HTML: %v", err))
+	}
+}
+
 // exportMarkdown exports the chronicle as Markdown.
 func exportMarkdown(metadata *chronicle.Metadata, turns []chronicle.Turn) {
 	// Header
@@ -377,6 +730,39 @@ func exportMarkdown(metadata *chronicle.Metadata, turns []chronicle.Turn) {
 	for _, turn := range turns {
 		outputTurnMarkdown(&turn)
 	}
+
+	// With several goals under discussion, follow each one's thread on its own
+	if threads := chronicle.GoalThreads(turns); len(threads) > 1 {
+		outputThreadsMarkdown(threads)
+	}
+}
+
+// outputThreadsMarkdown outputs what was said about each goal, goal by goal.
+func outputThreadsMarkdown(threads []chronicle.Thread) {
+	fmt.Printf("## 🧵 Goal Threads\n\n")
+	for _, thread := range threads {
+		fmt.Printf("### %s\n\n", thread.Goal)
+		for _, post := range thread.Posts {
+			event := post.Event
+			speaker := event.AgentName
+			if event.Type == "whisper" {
+				speaker = fmt.Sprintf("%s 🤫 to %s", event.AgentName, event.Recipient)
+			}
+
+			var parts []string
+			if event.Dialogue != "" {
+				parts = append(parts, fmt.Sprintf("\"%s\"", event.Dialogue))
+			}
+			for _, proposal := range event.Proposals {
+				parts = append(parts, fmt.Sprintf("🎯 proposes %s", proposal))
+			}
+			for _, vote := range event.Votes {
+				parts = append(parts, fmt.Sprintf("🗳️ votes %s on %s", vote.Choice, vote.ProposalID))
+			}
+			fmt.Printf("- **Turn %d, %s:** %s\n", post.Turn, speaker, strings.Join(parts, " "))
+		}
+		fmt.Println()
+	}
 }
 
 // joinSlice joins a slice of strings with commas.
@@ -0,0 +1,147 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/poiesic/wonda/internal/chronicle"
+	"github.com/spf13/cobra"
+)
+
+var chronicleCompareCommand = &cobra.Command{
+	Use:     "compare <chronicle-a> <chronicle-b>",
+	Aliases: []string{"cmp"},
+	Short:   "Show how two runs differ, turn by turn",
+	Long: `Line up the turns of two chronicles, typically two runs of the same scenario
+before and after tuning a prompt or character, and show where they differ: what
+each agent said or did, the proposals they made, the votes they cast, and how
+each goal ended. Lines only in the first run are marked -, lines only in the
+second +.
+
+Dialogue similarity is the overlap of the words each agent used in a turn, so
+reworded lines still count as close. Agent turns that match are hidden unless
+--all is given. --format json writes the full comparison as a report.`,
+	Args: cobra.ExactArgs(2),
+	Run:  chronicleCompare,
+}
+
+var (
+	compareFormat string
+	compareAll    bool
+)
+
+func init() {
+	chronicleCommand.AddCommand(chronicleCompareCommand)
+
+	chronicleCompareCommand.Flags().StringVar(&compareFormat, "format", "text", "Output format: text or json")
+	chronicleCompareCommand.Flags().BoolVar(&compareAll, "all", false, "Also show agent turns that match")
+}
+
+func chronicleCompare(cmd *cobra.Command, args []string) {
+	metadataA, turnsA, err := chronicle.ReadFile(args[0])
+	if err != nil {
+		reportErrorAndDieS(fmt.Sprintf("Failed to read chronicle: %v", err))
+	}
+	metadataB, turnsB, err := chronicle.ReadFile(args[1])
+	if err != nil {
+		reportErrorAndDieS(fmt.Sprintf("Failed to read chronicle: %v", err))
+	}
+
+	comparison := chronicle.Compare(turnsA, turnsB)
+
+	switch compareFormat {
+	case "text":
+	case "json":
+		printJSON(map[string]interface{}{
+			"a":          args[0],
+			"b":          args[1],
+			"scenario_a": metadataA.Scenario,
+			"scenario_b": metadataB.Scenario,
+			"comparison": comparison,
+		})
+		return
+	default:
+		reportErrorAndDieS(fmt.Sprintf("Unknown format: %s (use 'text' or 'json')", compareFormat))
+	}
+
+	fmt.Printf("- %s (%s, %d turns)\n", args[0], metadataA.Scenario, comparison.TurnsA)
+	fmt.Printf("+ %s (%s, %d turns)\n", args[1], metadataB.Scenario, comparison.TurnsB)
+	if metadataA.Scenario != metadataB.Scenario {
+		fmt.Println(warnStyle.Render("The runs are of different scenarios"))
+	}
+	fmt.Println()
+
+	for _, turn := range comparison.Turns {
+		header := false
+		for _, agent := range turn.Agents {
+			if agent.Same && !compareAll {
+				continue
+			}
+			if !header {
+				fmt.Printf("Turn %d\n", turn.Number)
+				header = true
+			}
+			if agent.Same {
+				fmt.Printf("  %s: same\n", agent.AgentName)
+				continue
+			}
+			fmt.Printf("  %s: dialogue %.0f%% similar\n", agent.AgentName, agent.Similarity*100)
+			printCompareLines("", agent.DialogueA, agent.DialogueB)
+			printCompareLines("🎯 ", agent.ProposalsA, agent.ProposalsB)
+			printCompareLines("🗳️ ", agent.VotesA, agent.VotesB)
+		}
+		if header {
+			fmt.Println()
+		}
+	}
+
+	if len(comparison.Goals) > 0 {
+		fmt.Println("Outcomes")
+		for _, goal := range comparison.Goals {
+			a, b := goalOutcome(goal.StatusA, goal.SolutionA, goal.TurnA), goalOutcome(goal.StatusB, goal.SolutionB, goal.TurnB)
+			if goal.Same {
+				fmt.Printf("  %s: %s\n", goal.GoalName, b)
+				continue
+			}
+			fmt.Println(warnStyle.Render(fmt.Sprintf("  ~ %s: %s → %s", goal.GoalName, a, b)))
+		}
+		fmt.Println()
+	}
+
+	stats := comparison.Stats
+	fmt.Printf("%d of %d agent turns differ, dialogue %.0f%% similar overall, %d of %d goal outcomes differ\n",
+		stats.Differing, stats.AgentTurns, stats.DialogueSimilarity*100, stats.GoalsDiffering, len(comparison.Goals))
+	if comparison.Same {
+		reportSuccess("The runs match")
+	}
+}
+
+// printCompareLines prints the lines of one side that the other lacks.
+func printCompareLines(prefix string, a, b []string) {
+	for _, line := range a {
+		if !contains(b, line) {
+			fmt.Println(errorStyle.Render(fmt.Sprintf("    - %s%s", prefix, line)))
+		}
+	}
+	for _, line := range b {
+		if !contains(a, line) {
+			fmt.Println(successStyle.Render(fmt.Sprintf("    + %s%s", prefix, line)))
+		}
+	}
+}
+
+// goalOutcome describes how a goal ended in one run.
+func goalOutcome(status, solution string, turn int) string {
+	if status == "" {
+		return "unsettled"
+	}
+	var b strings.Builder
+	b.WriteString(status)
+	if solution != "" {
+		fmt.Fprintf(&b, " %q", solution)
+	}
+	if turn > 0 {
+		fmt.Fprintf(&b, " (turn %d)", turn)
+	}
+	return b.String()
+}
@@ -0,0 +1,154 @@
+package cli
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/poiesic/wonda/internal/chronicle"
+	"github.com/poiesic/wonda/internal/dataset"
+	"github.com/poiesic/wonda/internal/scenarios"
+	"github.com/spf13/cobra"
+)
+
+var chronicleDatasetCommand = &cobra.Command{
+	Use:     "dataset <chronicle-file>...",
+	Aliases: []string{"ds"},
+	Short:   "Export chronicles as an anonymized JSONL dataset",
+	Long: `Export one or more chronicles as a JSONL dataset of (context, persona, action) examples
+for fine-tuning or analysis. Agent names are replaced with "Agent N" and PII such as
+emails and phone numbers is scrubbed unless disabled.
+
+Optional fields: scenario, setting, turn, persona, context, reasoning (default: persona, context).
+Personas are read from the characters of the scenario given with --scenario.
+
+--format writes fine-tuning data instead of wonda's own records: openai (chat JSONL),
+anthropic (system and messages) or sharegpt (conversations). The persona and setting
+become the system prompt, the conversation so far the user message, and what the agent
+said or did the reply. Examples without dialogue or proposals, such as bare votes, are
+left out of these formats.
+
+--agent limits the examples to some agents, named as in the chronicle. --min-consistency
+keeps only agents who stayed in character in at least that share of their events (0-1),
+counting refusals, fallback actions and dialogue that broke character against them.`,
+	Args: cobra.MinimumNArgs(1),
+	Run:  chronicleDataset,
+}
+
+var datasetFields []string
+var datasetContextSize int
+var datasetScenario string
+var datasetOutput string
+var datasetKeepNames bool
+var datasetNoScrub bool
+var datasetFormat string
+var datasetAgents []string
+var datasetMinConsistency float64
+
+func init() {
+	chronicleCommand.AddCommand(chronicleDatasetCommand)
+
+	chronicleDatasetCommand.Flags().StringSliceVar(&datasetFields, "fields", nil, "Optional fields to include (comma-separated)")
+	chronicleDatasetCommand.Flags().IntVar(&datasetContextSize, "context", 10, "Number of preceding messages to include as context")
+	chronicleDatasetCommand.Flags().StringVar(&datasetScenario, "scenario", "", "Scenario to read agent personas from")
+	chronicleDatasetCommand.Flags().StringVarP(&datasetOutput, "output", "o", "", "Write the dataset to a file instead of stdout")
+	chronicleDatasetCommand.Flags().BoolVar(&datasetKeepNames, "keep-names", false, "Keep agent names instead of anonymizing them")
+	chronicleDatasetCommand.Flags().BoolVar(&datasetNoScrub, "no-scrub", false, "Don't scrub PII from text")
+	chronicleDatasetCommand.Flags().StringVar(&datasetFormat, "format", dataset.FormatWonda, "Output format (wonda, openai, anthropic, sharegpt)")
+	chronicleDatasetCommand.Flags().StringSliceVar(&datasetAgents, "agent", nil, "Only export these agents (comma-separated)")
+	chronicleDatasetCommand.Flags().Float64Var(&datasetMinConsistency, "min-consistency", 0, "Only export agents whose persona consistency is at least this (0-1)")
+}
+
+func chronicleDataset(cmd *cobra.Command, args []string) {
+	if err := dataset.ValidateFields(datasetFields); err != nil {
+		reportErrorAndDie(err)
+	}
+	if err := dataset.ValidateFormat(datasetFormat); err != nil {
+		reportErrorAndDie(err)
+	}
+	if datasetMinConsistency < 0 || datasetMinConsistency > 1 {
+		reportErrorAndDieS("--min-consistency must be between 0 and 1")
+	}
+
+	opts := dataset.Options{
+		Fields:      datasetFields,
+		ContextSize: datasetContextSize,
+		Anonymize:   !datasetKeepNames,
+		Scrub:       !datasetNoScrub,
+
+		Agents:         datasetAgents,
+		MinConsistency: datasetMinConsistency,
+	}
+	if datasetScenario != "" {
+		personas, err := loadPersonas(datasetScenario)
+		if err != nil {
+			reportErrorAndDie(err)
+		}
+		opts.Personas = personas
+	}
+
+	// Write to stdout unless an output file is given
+	out := os.Stdout
+	if datasetOutput != "" {
+		file, err := os.Create(datasetOutput)
+		if err != nil {
+			reportErrorAndDieP(datasetOutput, err)
+		}
+		defer file.Close()
+		out = file
+	}
+	writer := bufio.NewWriter(out)
+	encoder := json.NewEncoder(writer)
+
+	total := 0
+	for _, chroniclePath := range args {
+		metadata, turns, err := chronicle.ReadFile(chroniclePath)
+		if err != nil {
+			reportErrorAndDieP(chroniclePath, err)
+		}
+		for _, example := range dataset.Build(metadata, turns, opts) {
+			record, ok := dataset.Format(example, datasetFormat)
+			if !ok {
+				continue
+			}
+			if err := encoder.Encode(record); err != nil {
+				reportErrorAndDieS(fmt.Sprintf("Failed to encode example: %v", err))
+			}
+			total++
+		}
+	}
+	if err := writer.Flush(); err != nil {
+		reportErrorAndDieS(fmt.Sprintf("Failed to write dataset: %v", err))
+	}
+
+	if datasetOutput != "" {
+		reportSuccess(fmt.Sprintf("Wrote %d examples from %d chronicle(s) to %s", total, len(args), datasetOutput))
+	}
+}
+
+// loadPersonas summarizes the character of each agent in a scenario.
+func loadPersonas(scenarioName string) (map[string]string, error) {
+	if !strings.HasSuffix(scenarioName, ".toml") {
+		scenarioName = scenarioName + ".toml"
+	}
+	scenarioPath := path.Join(configDir, "scenarios", scenarioName)
+	scenario, err := scenarios.LoadScenarioFromFile(scenarioPath)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", scenarioPath, err)
+	}
+
+	personas := make(map[string]string, len(scenario.Agents))
+	for agentName, agent := range scenario.Agents {
+		characterPath := path.Join(configDir, "characters", agent.Character+".toml")
+		character, err := scenarios.LoadCharacterFromFile(characterPath)
+		if err != nil {
+			reportWarning(fmt.Sprintf("No persona for %s: %v", agentName, err))
+			continue
+		}
+		personas[agentName] = dataset.PersonaSummary(character)
+	}
+	return personas, nil
+}
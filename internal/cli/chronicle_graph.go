@@ -0,0 +1,98 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/poiesic/wonda/internal/campaigns"
+	"github.com/poiesic/wonda/internal/chronicle"
+	"github.com/spf13/cobra"
+)
+
+var chronicleGraphCommand = &cobra.Command{
+	Use:   "graph <chronicle-file>",
+	Short: "Export a run as a graph of agents, proposals, votes and goals",
+	Long: `Build a graph from a chronicle for network analysis of influence and agreement:
+agents, goals, proposals and cited memories as nodes, and who proposed what,
+who voted which way, which proposal settled each goal, who whispered to whom
+and which memories were cited as edges. Votes are also summed into SUPPORTED
+and OPPOSED edges from each voter to the agents whose proposals they voted on.
+
+With --campaign, the campaign's relationships between the run's agents are
+added as RELATES_TO edges.
+
+--format graphml (default) writes GraphML to stdout or --output, for Gephi,
+yEd or NetworkX. --format neo4j writes nodes.csv and relationships.csv to the
+--output directory, ready for neo4j-admin database import.`,
+	Args: cobra.ExactArgs(1),
+	Run:  chronicleGraph,
+}
+
+var (
+	graphFormat   string
+	graphOutput   string
+	graphCampaign string
+)
+
+func init() {
+	chronicleCommand.AddCommand(chronicleGraphCommand)
+
+	chronicleGraphCommand.Flags().StringVar(&graphFormat, "format", "graphml", "Output format: graphml or neo4j")
+	chronicleGraphCommand.Flags().StringVarP(&graphOutput, "output", "o", "", "File (graphml) or directory (neo4j) to write")
+	chronicleGraphCommand.Flags().StringVar(&graphCampaign, "campaign", "", "Add relationships between the agents from this campaign")
+}
+
+func chronicleGraph(cmd *cobra.Command, args []string) {
+	chroniclePath := args[0]
+	_, turns, err := chronicle.ReadFile(chroniclePath)
+	if err != nil {
+		reportErrorAndDieS(fmt.Sprintf("Failed to read chronicle: %v", err))
+	}
+
+	graph := chronicle.BuildGraph(turns)
+	if graphCampaign != "" {
+		if err := campaigns.ValidateName(graphCampaign); err != nil {
+			reportErrorAndDie(err)
+		}
+		store, err := campaigns.LoadRelationships(configDir, graphCampaign)
+		if err != nil {
+			reportErrorAndDie(err)
+		}
+		agents := make(map[string]bool)
+		for _, node := range graph.Nodes {
+			if node.Label == chronicle.NodeAgent {
+				agents[node.Properties["name"]] = true
+			}
+		}
+		for _, rel := range store.Relationships {
+			if agents[rel.From] && agents[rel.To] {
+				graph.AddRelationship(rel.From, rel.To, rel.Value, rel.Trust, rel.Familiarity)
+			}
+		}
+	}
+
+	switch graphFormat {
+	case "graphml":
+		out := os.Stdout
+		if graphOutput != "" {
+			out, err = os.Create(graphOutput)
+			if err != nil {
+				reportErrorAndDie(err)
+			}
+			defer out.Close()
+		}
+		if err := graph.WriteGraphML(out); err != nil {
+			reportErrorAndDieS(fmt.Sprintf("Failed to write GraphML: %v", err))
+		}
+	case "neo4j":
+		if graphOutput == "" {
+			reportErrorAndDieS("--format neo4j needs an --output directory")
+		}
+		if err := graph.WriteNeo4jCSV(graphOutput); err != nil {
+			reportErrorAndDieS(fmt.Sprintf("Failed to write Neo4j CSV: %v", err))
+		}
+		fmt.Printf("Wrote %d nodes and %d relationships to %s\n", len(graph.Nodes), len(graph.Edges), graphOutput)
+	default:
+		reportErrorAndDieS(fmt.Sprintf("Unknown format: %s (use 'graphml' or 'neo4j')", graphFormat))
+	}
+}
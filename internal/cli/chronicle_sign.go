@@ -0,0 +1,111 @@
+package cli
+
+import (
+	"crypto/ed25519"
+	"fmt"
+	"os"
+
+	"github.com/poiesic/wonda/internal/runs"
+	"github.com/poiesic/wonda/internal/signing"
+	"github.com/spf13/cobra"
+)
+
+var chronicleKeygenCommand = &cobra.Command{
+	Use:   "keygen",
+	Short: "Create the ed25519 key used to sign run artifacts",
+	Long: `Create an ed25519 signing key in the config directory. Once it exists, each run's
+chronicle, outcomes and manifest are signed, with the signature written alongside
+each file as <file>.sig. Share the printed public key so others can verify results.`,
+	Args: cobra.NoArgs,
+	Run:  chronicleKeygen,
+}
+
+var chronicleVerifyCommand = &cobra.Command{
+	Use:     "verify <file>...",
+	Aliases: []string{"v"},
+	Short:   "Verify that signed run artifacts are unmodified",
+	Long: `Check chronicle, outcomes or manifest files against their .sig signatures.
+Without --key, a valid signature only shows the file is unchanged since the holder of
+the embedded public key signed it; pass the publisher's public key to check who signed it.`,
+	Args: cobra.MinimumNArgs(1),
+	Run:  chronicleVerify,
+}
+
+var keygenForce bool
+var verifyPublicKey string
+
+func init() {
+	chronicleCommand.AddCommand(chronicleKeygenCommand, chronicleVerifyCommand)
+
+	chronicleKeygenCommand.Flags().BoolVar(&keygenForce, "force", false, "Replace an existing signing key")
+	chronicleVerifyCommand.Flags().StringVar(&verifyPublicKey, "key", "", "Base64 public key the files must be signed with")
+}
+
+func chronicleKeygen(cmd *cobra.Command, args []string) {
+	publicKey, err := signing.GenerateKey(configDir, keygenForce)
+	if err != nil {
+		reportErrorAndDie(err)
+	}
+	reportSuccess(fmt.Sprintf("Signing key written to %s", signing.KeyPath(configDir)))
+	fmt.Printf("Public key: %s\n", signing.EncodePublicKey(publicKey))
+}
+
+func chronicleVerify(cmd *cobra.Command, args []string) {
+	var trusted ed25519.PublicKey
+	if verifyPublicKey != "" {
+		key, err := signing.ParsePublicKey(verifyPublicKey)
+		if err != nil {
+			reportErrorAndDie(err)
+		}
+		trusted = key
+	}
+
+	failed := 0
+	for _, filePath := range args {
+		signature, err := signing.VerifyFile(filePath, trusted)
+		if err != nil {
+			fmt.Printf("  ❌ %s: %s\n", filePath, err)
+			failed++
+			continue
+		}
+		fmt.Printf("  ✅ %s (signed %s by %s)\n", filePath, signature.SignedAt.Format("2006-01-02 15:04"), signature.PublicKey)
+	}
+	if failed > 0 {
+		reportErrorAndDieS(fmt.Sprintf("%d of %d files failed verification", failed, len(args)))
+	}
+	if trusted == nil {
+		reportWarning("No --key given: signatures were checked against the keys embedded in them")
+	}
+}
+
+// signRunArtifacts signs a run's chronicle, outcomes and manifest when a
+// signing key is configured.
+func signRunArtifacts(manifest runs.Manifest) {
+	signRunArtifactsIn(configDir, manifest)
+}
+
+// signRunArtifactsIn signs the artifacts of a run whose manifest is kept in
+// runConfigDir, as a user's runs are by the API server, with the key of the
+// config directory.
+func signRunArtifactsIn(runConfigDir string, manifest runs.Manifest) {
+	key, err := signing.LoadKey(configDir)
+	if err != nil {
+		reportWarning(fmt.Sprintf("Failed to load signing key: %v", err))
+		return
+	}
+	if key == nil {
+		return
+	}
+
+	for _, filePath := range []string{manifest.Chronicle, manifest.Outcomes, runs.ManifestPath(runConfigDir, manifest.SimulationID)} {
+		if filePath == "" {
+			continue
+		}
+		if _, err := os.Stat(filePath); err != nil {
+			continue
+		}
+		if _, err := signing.SignFile(key, filePath); err != nil {
+			reportWarning(fmt.Sprintf("Failed to sign %s: %v", filePath, err))
+		}
+	}
+}
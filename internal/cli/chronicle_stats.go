@@ -0,0 +1,206 @@
+package cli
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/poiesic/wonda/internal/chronicle"
+	"github.com/spf13/cobra"
+)
+
+var chronicleStatsCommand = &cobra.Command{
+	Use:     "stats <chronicle-file>",
+	Aliases: []string{"st"},
+	Short:   "Show what each agent did in a run",
+	Long: `Count each agent's dialogue, actions, thoughts, passes and refusals in a chronicle,
+with their share of the talk, the proposals they made and had accepted, and the
+votes they cast. With --topic, only events made while working on goals tagged
+with that topic are counted.
+
+Each goal is listed with how many proposals and votes it drew, the turn it was
+first proposed for, and the turn consensus was reached on.
+
+Each agent's emotional state at the end of every turn is shown as a sparkline of
+its intensity. --format json writes the counts and emotion timelines, and
+--format csv writes the emotion timelines one row per agent and turn.
+
+With --tools, shows how the agents played by each model used their tools
+instead: turns taken, model requests per turn, tool calls, failed calls and
+calls rejected for naming an unknown tool or bad arguments, and the tools
+called most. Chronicles from before models were recorded count every call
+under no model.`,
+	Args: cobra.ExactArgs(1),
+	Run:  chronicleStats,
+}
+
+var (
+	statsTopic  string
+	statsFormat string
+	statsTools  bool
+)
+
+func init() {
+	chronicleCommand.AddCommand(chronicleStatsCommand)
+
+	chronicleStatsCommand.Flags().StringVar(&statsTopic, "topic", "", "Only count events about goals with this tag")
+	chronicleStatsCommand.Flags().StringVar(&statsFormat, "format", "text", "Output format: text, json, or csv")
+	chronicleStatsCommand.Flags().BoolVar(&statsTools, "tools", false, "Show tool usage by model instead")
+}
+
+func chronicleStats(cmd *cobra.Command, args []string) {
+	chroniclePath := args[0]
+	metadata, turns, err := chronicle.ReadFile(chroniclePath)
+	if err != nil {
+		reportErrorAndDieS(fmt.Sprintf("Failed to read chronicle: %v", err))
+	}
+
+	if statsTools {
+		chronicleToolStats(metadata, turns)
+		return
+	}
+
+	topics := chronicle.Topics(turns)
+	stats := chronicle.Stats(turns, statsTopic)
+	goals := chronicle.Goals(turns)
+	emotions := chronicle.EmotionTimeline(turns)
+
+	if len(stats) == 0 {
+		if statsTopic != "" {
+			reportErrorAndDieS(fmt.Sprintf("No events about '%s' (topics: %s)", statsTopic, joinOrNone(topics)))
+		}
+		fmt.Println("No events recorded.")
+		return
+	}
+
+	switch statsFormat {
+	case "text":
+	case "json":
+		output := map[string]interface{}{
+			"scenario": metadata.Scenario,
+			"turns":    len(turns),
+			"topic":    statsTopic,
+			"agents":   stats,
+			"goals":    goals,
+			"emotions": emotions,
+		}
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(output); err != nil {
+			reportErrorAndDieS(fmt.Sprintf("Failed to encode JSON: %v", err))
+		}
+		return
+	case "csv":
+		if err := chronicle.WriteEmotionTimelineCSV(os.Stdout, emotions); err != nil {
+			reportErrorAndDieS(fmt.Sprintf("Failed to write CSV: %v", err))
+		}
+		return
+	default:
+		reportErrorAndDieS(fmt.Sprintf("Unknown format: %s (use 'text', 'json', or 'csv')", statsFormat))
+	}
+
+	fmt.Printf("%s: %d turns\n", metadata.Scenario, len(turns))
+	if statsTopic != "" {
+		fmt.Printf("Topic: %s\n", statsTopic)
+	} else {
+		fmt.Printf("Topics: %s\n", joinOrNone(topics))
+	}
+	fmt.Println()
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "AGENT\tTURNS\tSAID\tWORDS\tTALK\tACTIONS\tTHOUGHTS\tPASSES\tREFUSALS\tPROPOSED\tACCEPTED\tVOTES")
+	for _, s := range stats {
+		fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%.0f%%\t%d\t%d\t%d\t%d\t%d\t%d\t%d (%d yes)\n",
+			s.AgentName, s.Turns, s.Said, s.Words, s.TalkShare*100, s.Actions, s.Thoughts, s.Passes, s.Refusals,
+			s.Proposals, s.Accepted, s.Votes, s.VotedYes)
+	}
+	w.Flush()
+
+	if len(goals) > 0 {
+		fmt.Println()
+		w = tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "GOAL\tSTATUS\tPROPOSALS\tVOTES\tFIRST PROPOSAL\tCONSENSUS\tDECIDED BY")
+		for _, g := range goals {
+			fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%s\t%s\t%s\n",
+				g.GoalName, g.Status, g.Proposals, g.Votes, turnOrDash(g.FirstProposal), turnOrDash(g.TurnsToConsensus), cmp.Or(g.DecidedBy, "-"))
+		}
+		w.Flush()
+	}
+
+	if len(emotions) == 0 {
+		return
+	}
+	fmt.Println()
+	w = tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "AGENT\tINTENSITY\tEMOTION")
+	for _, series := range emotions {
+		start, end := series.Points[0], series.Points[len(series.Points)-1]
+		fmt.Fprintf(w, "%s\t%s\t%s %d → %s %d\n",
+			series.AgentName, series.Sparkline(), start.Emotion, start.Intensity, end.Emotion, end.Intensity)
+	}
+	w.Flush()
+}
+
+// chronicleToolStats prints how each model's agents used their tools.
+func chronicleToolStats(metadata *chronicle.Metadata, turns []chronicle.Turn) {
+	usage := chronicle.ToolStats(turns)
+
+	switch statsFormat {
+	case "text":
+	case "json":
+		output := map[string]interface{}{
+			"scenario": metadata.Scenario,
+			"turns":    len(turns),
+			"models":   usage,
+		}
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(output); err != nil {
+			reportErrorAndDieS(fmt.Sprintf("Failed to encode JSON: %v", err))
+		}
+		return
+	default:
+		reportErrorAndDieS(fmt.Sprintf("Unknown format for --tools: %s (use 'text' or 'json')", statsFormat))
+	}
+
+	if len(usage) == 0 {
+		fmt.Println("No tool use recorded.")
+		return
+	}
+	fmt.Printf("%s: %d turns\n\n", metadata.Scenario, len(turns))
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "MODEL\tAGENTS\tTURNS\tREQUESTS/TURN\tCALLS\tFAILED\tBAD ARGUMENTS\tMOST CALLED")
+	for _, u := range usage {
+		requests := "-"
+		if u.AvgIterations > 0 {
+			requests = fmt.Sprintf("%.1f", u.AvgIterations)
+		}
+		var tools []string
+		for _, count := range u.Tools[:min(len(u.Tools), 3)] {
+			tools = append(tools, fmt.Sprintf("%s (%d)", count.Tool, count.Calls))
+		}
+		fmt.Fprintf(w, "%s\t%s\t%d\t%s\t%d\t%d\t%d (%.0f%%)\t%s\n",
+			cmp.Or(u.Model, "-"), strings.Join(u.Agents, ", "), u.Turns, requests, u.Calls, u.Errors,
+			u.ArgumentErrors, u.ArgumentRate*100, joinOrNone(tools))
+	}
+	w.Flush()
+}
+
+// turnOrDash formats a turn number, or a dash for none.
+func turnOrDash(turn int) string {
+	if turn == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("turn %d", turn)
+}
+
+// joinOrNone joins items with commas, or returns "none".
+func joinOrNone(items []string) string {
+	if len(items) == 0 {
+		return "none"
+	}
+	return strings.Join(items, ", ")
+}
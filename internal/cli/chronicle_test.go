@@ -0,0 +1,112 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeChronicle writes a chronicle holding the lines, modified at modTime.
+func writeChronicle(t *testing.T, dir, name string, modTime time.Time, lines ...string) string {
+	chroniclePath := filepath.Join(dir, name)
+	var data []byte
+	for _, line := range lines {
+		data = append(data, line+"\n"...)
+	}
+	require.NoError(t, os.WriteFile(chroniclePath, data, 0644))
+	require.NoError(t, os.Chtimes(chroniclePath, modTime, modTime))
+	return chroniclePath
+}
+
+func metadataLine(scenario string) string {
+	return `{"type":"metadata","simulation_id":"sim-1","scenario":"` + scenario + `","location":"Cafe","time":"Noon","start_time":"2026-01-02T15:04:05Z"}`
+}
+
+func TestNewestChronicle(t *testing.T) {
+	now := time.Now()
+
+	t.Run("none yet", func(t *testing.T) {
+		dir := t.TempDir()
+		writeChronicle(t, dir, "notes.jsonl", now)
+		seen := make(map[string]bool)
+		newest, err := newestChronicle(dir, seen)
+		require.NoError(t, err)
+		assert.Empty(t, newest)
+		assert.Empty(t, seen)
+	})
+
+	t.Run("most recently modified", func(t *testing.T) {
+		dir := t.TempDir()
+		older := writeChronicle(t, dir, "chronicle-b.jsonl", now.Add(-time.Hour))
+		newer := writeChronicle(t, dir, "chronicle-a.jsonl", now)
+		seen := make(map[string]bool)
+		newest, err := newestChronicle(dir, seen)
+		require.NoError(t, err)
+		assert.Equal(t, newer, newest)
+		assert.Equal(t, map[string]bool{older: true, newer: true}, seen)
+	})
+}
+
+func TestNewChronicle(t *testing.T) {
+	now := time.Now()
+	dir := t.TempDir()
+	seen := make(map[string]bool)
+	first := writeChronicle(t, dir, "chronicle-1.jsonl", now.Add(-time.Hour))
+	_, err := newestChronicle(dir, seen)
+	require.NoError(t, err)
+
+	newer, err := newChronicle(dir, seen)
+	require.NoError(t, err)
+	assert.Empty(t, newer, "nothing new since the first look")
+
+	// A chronicle that appears is followed once, even while older ones grow
+	second := writeChronicle(t, dir, "chronicle-2.jsonl", now)
+	newer, err = newChronicle(dir, seen)
+	require.NoError(t, err)
+	assert.Equal(t, second, newer)
+
+	require.NoError(t, os.Chtimes(first, now.Add(time.Minute), now.Add(time.Minute)))
+	newer, err = newChronicle(dir, seen)
+	require.NoError(t, err)
+	assert.Empty(t, newer)
+
+	// Of several new chronicles, the newest is followed
+	writeChronicle(t, dir, "chronicle-3.jsonl", now.Add(2*time.Minute))
+	fourth := writeChronicle(t, dir, "chronicle-4.jsonl", now.Add(3*time.Minute))
+	newer, err = newChronicle(dir, seen)
+	require.NoError(t, err)
+	assert.Equal(t, fourth, newer)
+}
+
+func TestChronicleTailer(t *testing.T) {
+	dir := t.TempDir()
+	chroniclePath := writeChronicle(t, dir, "chronicle-1.jsonl", time.Now(), metadataLine("Dinner"))
+
+	var tailer *chronicleTailer
+	output := captureStdout(t, func() { tailer = openTail(chroniclePath) })
+	defer tailer.close()
+	assert.Contains(t, output, "# Simulation Chronicle: Dinner")
+
+	output = captureStdout(t, tailer.poll)
+	assert.Empty(t, output, "nothing was written since")
+
+	file, err := os.OpenFile(chroniclePath, os.O_APPEND|os.O_WRONLY, 0644)
+	require.NoError(t, err)
+	_, err = file.WriteString(`{"type":"partial","agent_name":"Alex","text":"How about"}` + "\n")
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+
+	output = captureStdout(t, tailer.poll)
+	assert.Equal(t, "*Alex is speaking:* How about", output)
+
+	// Following another chronicle doesn't continue the last utterance
+	next := writeChronicle(t, dir, "chronicle-2.jsonl", time.Now(), metadataLine("Lunch"),
+		`{"type":"partial","agent_name":"Alex","text":"How about sushi?","final":true}`)
+	output = captureStdout(t, func() { openTail(next).close() })
+	assert.Contains(t, output, "# Simulation Chronicle: Lunch")
+	assert.Contains(t, output, "*Alex is speaking:* How about sushi?\n\n")
+}
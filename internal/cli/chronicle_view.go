@@ -0,0 +1,323 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/x/term"
+	"github.com/poiesic/wonda/internal/chronicle"
+	"github.com/spf13/cobra"
+)
+
+var chronicleViewCommand = &cobra.Command{
+	Use:     "view <chronicle-file>",
+	Aliases: []string{"v"},
+	Short:   "Browse a chronicle interactively",
+	Long: `Open a chronicle in a keyboard-driven viewer.
+
+Keys:
+  j/k, ↓/↑         scroll one line
+  space/b, PgDn/PgUp  scroll one page
+  g/G, Home/End    jump to start/end
+  ]/[              next/previous turn
+  a                cycle agent filter (all agents, then each agent)
+  r                toggle reasoning
+  /                search; n/N next/previous match
+  f                toggle follow mode (reload and stick to the end as the file grows)
+  q                quit`,
+	Args: cobra.ExactArgs(1),
+	Run:  chronicleView,
+}
+
+var viewFollow bool
+var viewPollInterval time.Duration
+
+func init() {
+	chronicleCommand.AddCommand(chronicleViewCommand)
+
+	chronicleViewCommand.Flags().BoolVar(&viewFollow, "follow", false, "Start in follow mode")
+	chronicleViewCommand.Flags().DurationVar(&viewPollInterval, "interval", 500*time.Millisecond, "Polling interval for follow mode")
+}
+
+// chronicleViewer is the interactive viewer: a chronicle.View plus the
+// terminal, the followed file and the search being typed.
+type chronicleViewer struct {
+	*chronicle.View
+
+	path   string
+	size   int64 // File size at last load, for follow mode
+	follow bool
+
+	search     string
+	searching  bool // Typing a search query
+	statusText string
+}
+
+func chronicleView(cmd *cobra.Command, args []string) {
+	viewer := &chronicleViewer{
+		path:   args[0],
+		follow: viewFollow,
+	}
+	if err := viewer.load(); err != nil {
+		reportErrorAndDieS(fmt.Sprintf("Failed to read chronicle: %v", err))
+	}
+
+	fd := os.Stdin.Fd()
+	if !term.IsTerminal(fd) {
+		reportErrorAndDieS("chronicle view requires an interactive terminal (use 'wonda chronicle export' instead)")
+	}
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		reportErrorAndDieS(fmt.Sprintf("Failed to set up terminal: %v", err))
+	}
+	defer term.Restore(fd, state)
+
+	// Alternate screen, hidden cursor
+	fmt.Print("\x1b[?1049h\x1b[?25l")
+	defer fmt.Print("\x1b[?25h\x1b[?1049l")
+
+	keys := make(chan string)
+	go readKeys(keys)
+
+	ticker := time.NewTicker(viewPollInterval)
+	defer ticker.Stop()
+
+	viewer.rebuild()
+	if viewer.follow {
+		viewer.ScrollToEnd()
+	}
+	viewer.render()
+
+	for {
+		select {
+		case key, ok := <-keys:
+			if !ok || !viewer.handleKey(key) {
+				return
+			}
+		case <-ticker.C:
+			if !viewer.follow {
+				continue
+			}
+			if changed, err := viewer.reloadIfChanged(); err != nil {
+				viewer.statusText = fmt.Sprintf("reload failed: %v", err)
+			} else if changed {
+				viewer.rebuild()
+				viewer.ScrollToEnd()
+			}
+		}
+		viewer.render()
+	}
+}
+
+// readKeys reads raw key sequences from stdin until it is closed.
+func readKeys(keys chan<- string) {
+	buf := make([]byte, 32)
+	for {
+		n, err := os.Stdin.Read(buf)
+		if err != nil {
+			close(keys)
+			return
+		}
+		keys <- string(buf[:n])
+	}
+}
+
+// load reads the chronicle file and records its size.
+func (v *chronicleViewer) load() error {
+	metadata, turns, err := chronicle.ReadFile(v.path)
+	if err != nil {
+		return err
+	}
+	info, err := os.Stat(v.path)
+	if err != nil {
+		return err
+	}
+
+	if v.View == nil {
+		v.View = chronicle.NewView(metadata, turns)
+	} else {
+		v.SetChronicle(metadata, turns)
+	}
+	v.size = info.Size()
+	return nil
+}
+
+// reloadIfChanged reloads the chronicle when the file has grown.
+func (v *chronicleViewer) reloadIfChanged() (bool, error) {
+	info, err := os.Stat(v.path)
+	if err != nil {
+		return false, err
+	}
+	if info.Size() == v.size {
+		return false, nil
+	}
+	return true, v.load()
+}
+
+// rebuild lays the chronicle out again for the current terminal size.
+func (v *chronicleViewer) rebuild() {
+	v.updateSize()
+	v.Layout()
+}
+
+// updateSize reads the terminal size, falling back to 80x24.
+func (v *chronicleViewer) updateSize() {
+	width, height, err := term.GetSize(os.Stdout.Fd())
+	if err != nil || width <= 0 || height <= 0 {
+		width, height = 80, 24
+	}
+	v.Width = width
+	v.Height = height
+}
+
+// findMatch moves to the next (dir > 0) or previous (dir < 0) match, noting a miss in the status bar.
+func (v *chronicleViewer) findMatch(dir int) {
+	if v.Query == "" {
+		return
+	}
+	v.statusText = ""
+	if !v.FindMatch(dir) {
+		v.statusText = fmt.Sprintf("not found: %s", v.Query)
+	}
+}
+
+// handleKey applies a key press. It returns false when the viewer should exit.
+func (v *chronicleViewer) handleKey(key string) bool {
+	if v.searching {
+		switch key {
+		case "\r", "\n":
+			v.searching = false
+			v.Query = v.search
+			v.MatchLine = -1
+			v.findMatch(1)
+		case "\x1b", "\x03":
+			v.searching = false
+		case "\x7f", "\b":
+			if v.search != "" {
+				_, size := utf8.DecodeLastRuneInString(v.search)
+				v.search = v.search[:len(v.search)-size]
+			}
+		default:
+			if utf8.ValidString(key) && !strings.ContainsAny(key, "\x1b\r\n\t") {
+				v.search += key
+			}
+		}
+		return true
+	}
+
+	v.statusText = ""
+	switch key {
+	case "q", "\x03":
+		return false
+	case "j", "\x1b[B", "\r":
+		v.Scroll(1)
+	case "k", "\x1b[A":
+		v.Scroll(-1)
+	case " ", "\x1b[6~":
+		v.Scroll(v.PageSize())
+	case "b", "\x1b[5~":
+		v.Scroll(-v.PageSize())
+	case "g", "\x1b[H":
+		v.Top = 0
+	case "G", "\x1b[F":
+		v.ScrollToEnd()
+	case "]":
+		v.JumpTurn(1)
+	case "[":
+		v.JumpTurn(-1)
+	case "a":
+		v.CycleAgent()
+	case "r":
+		v.ToggleReasoning()
+	case "/":
+		v.searching = true
+		v.search = ""
+	case "n":
+		v.findMatch(1)
+	case "N":
+		v.findMatch(-1)
+	case "f":
+		v.follow = !v.follow
+		if v.follow {
+			if _, err := v.reloadIfChanged(); err != nil {
+				v.statusText = fmt.Sprintf("reload failed: %v", err)
+			}
+			v.rebuild()
+			v.ScrollToEnd()
+		}
+	}
+	return true
+}
+
+// render draws the visible lines and the status bar.
+func (v *chronicleViewer) render() {
+	v.updateSize()
+	v.ClampTop()
+
+	var buf bytes.Buffer
+	buf.WriteString("\x1b[H\x1b[2J")
+
+	end := v.Top + v.PageSize()
+	for i := v.Top; i < end; i++ {
+		if i < len(v.Lines) {
+			text := truncateRunes(v.Lines[i].Text, v.Width)
+			if i == v.MatchLine {
+				buf.WriteString("\x1b[7m" + text + "\x1b[0m")
+			} else {
+				buf.WriteString(text)
+			}
+		}
+		buf.WriteString("\r\n")
+	}
+
+	// Status bar
+	var status string
+	switch {
+	case v.searching:
+		status = "/" + v.search
+	case v.statusText != "":
+		status = v.statusText
+	default:
+		agent := "all agents"
+		if name := v.Agent(); name != "" {
+			agent = name
+		}
+		flags := ""
+		if v.ShowReasoning {
+			flags += " · reasoning"
+		}
+		if v.follow {
+			flags += " · follow"
+		}
+		status = fmt.Sprintf("turn %d/%d · %s%s · q quit, --help lists keys", v.Turn(), len(v.Turns), agent, flags)
+	}
+	buf.WriteString("\x1b[7m" + truncateRunes(status, v.Width) + "\x1b[0m")
+
+	os.Stdout.Write(buf.Bytes())
+}
+
+// truncateRunes shortens s to at most n runes, ignoring ANSI escape sequences when counting.
+func truncateRunes(s string, n int) string {
+	count := 0
+	inEscape := false
+	for i, r := range s {
+		switch {
+		case r == '\x1b':
+			inEscape = true
+		case inEscape:
+			if r == 'm' {
+				inEscape = false
+			}
+		default:
+			count++
+			if count > n {
+				return s[:i] + "\x1b[0m"
+			}
+		}
+	}
+	return s
+}
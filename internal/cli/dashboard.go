@@ -0,0 +1,42 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/poiesic/wonda/internal/dashboard"
+)
+
+// serveDashboard serves a dashboard at addr in the background. The returned
+// function ends the viewers' feeds and stops the server.
+func serveDashboard(web *dashboard.Server, addr string) (func(), error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	server := &http.Server{Handler: web.Handler()}
+	go func() {
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			slog.Warn("dashboard server stopped", "error", err)
+		}
+	}()
+
+	host := "localhost"
+	tcpAddr := listener.Addr().(*net.TCPAddr)
+	if !tcpAddr.IP.IsUnspecified() {
+		host = tcpAddr.IP.String()
+	}
+	fmt.Printf("🌐 Dashboard at http://%s/\n", net.JoinHostPort(host, fmt.Sprint(tcpAddr.Port)))
+
+	return func() {
+		web.Close()
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		server.Shutdown(ctx)
+	}, nil
+}
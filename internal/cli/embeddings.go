@@ -69,6 +69,12 @@ func showEmbeddings(cmd *cobra.Command, args []string) {
 		fmt.Printf("    Provider:   %s\n", emb.Provider)
 		fmt.Printf("    Model:      %s\n", emb.Model)
 		fmt.Printf("    Dimensions: %d\n", emb.Dimensions)
+		metric := emb.Metric
+		if metric == "" {
+			metric = "cosine"
+		}
+		fmt.Printf("    Metric:     %s\n", metric)
+		fmt.Printf("    Normalize:  %t\n", emb.Normalize)
 		fmt.Println()
 	}
 }
@@ -0,0 +1,99 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/poiesic/wonda/internal/config/examples"
+	"github.com/spf13/cobra"
+)
+
+var examplesCommand = &cobra.Command{
+	Use:     "examples",
+	Short:   "Install ready-to-run example scenarios",
+	Aliases: []string{"ex"},
+}
+
+var listExamplesCommand = &cobra.Command{
+	Use:     "list",
+	Short:   "List the example scenarios bundled with wonda",
+	Aliases: []string{"l"},
+	Args:    cobra.NoArgs,
+	Run:     listExamples,
+}
+
+var installExamplesCommand = &cobra.Command{
+	Use:   "install [example-name...]",
+	Short: "Install example scenarios and their characters into the config directory",
+	Long: `Copy example scenarios bundled with wonda, or all of them if none are named,
+into the config directory's scenarios/, with the characters they use in
+characters/. Files that already exist are left alone unless --force is given.
+
+The examples use the model example_model, which 'wonda init' creates; point it
+at your provider, or use --model to have the examples use one of your own.`,
+	Run: installExamples,
+}
+
+var examplesModel string
+var examplesForce bool
+
+func init() {
+	rootCommand.AddCommand(examplesCommand)
+	examplesCommand.AddCommand(listExamplesCommand, installExamplesCommand)
+
+	addListFormatFlag(listExamplesCommand)
+	installExamplesCommand.Flags().StringVar(&examplesModel, "model", "", "Model the installed scenarios use (default: "+examples.Model+")")
+	installExamplesCommand.Flags().BoolVar(&examplesForce, "force", false, "Overwrite scenarios and characters that already exist")
+}
+
+func listExamples(cmd *cobra.Command, args []string) {
+	asJSON := listAsJSON()
+	list, err := examples.List()
+	if err != nil {
+		reportErrorAndDie(err)
+	}
+	if asJSON {
+		printJSON(list)
+		return
+	}
+
+	fmt.Printf("Example scenarios:\n\n")
+	for _, example := range list {
+		fmt.Printf("  • %s - %s\n", example.Name, example.Title)
+		fmt.Printf("    %s\n", example.Description)
+		fmt.Printf("    Goals: %s\n", strings.Join(example.Goals, ", "))
+		fmt.Printf("    Characters: %s\n", strings.Join(example.Characters, ", "))
+	}
+	fmt.Printf("\nInstall them with 'wonda examples install [example-name...]'.\n")
+}
+
+func installExamples(cmd *cobra.Command, args []string) {
+	result, err := examples.Install(configDir, args, examples.InstallOptions{Model: examplesModel, Force: examplesForce})
+	if err != nil {
+		reportErrorAndDie(err)
+	}
+
+	for _, file := range result.Written {
+		fmt.Printf("  ✅ %s\n", file)
+	}
+	for _, file := range result.Skipped {
+		reportWarning(fmt.Sprintf("skipped existing file %s (use --force to overwrite it)", file))
+	}
+	reportSuccess(fmt.Sprintf("Installed %d files", len(result.Written)))
+
+	model := examplesModel
+	if model == "" {
+		model = examples.Model
+	}
+	if _, err := os.Stat(path.Join(configDir, "models", model+".toml")); os.IsNotExist(err) {
+		reportWarning(fmt.Sprintf("The examples use the model %s, which isn't configured yet. Run 'wonda init' or 'wonda models new %s' to set it up.", model, model))
+	}
+
+	name := "dinner-plans"
+	if len(args) > 0 {
+		name = args[0]
+	}
+	fmt.Printf("\nRun one with 'wonda scenarios run %s', or add --dry-run to try it without calling a model.\n", name)
+}
@@ -0,0 +1,187 @@
+package cli
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"path"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/poiesic/wonda/internal/runs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// captureStdout returns what fn prints to stdout.
+func captureStdout(t *testing.T, fn func()) string {
+	r, w, err := os.Pipe()
+	require.NoError(t, err)
+	saved := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = saved }()
+
+	output := make(chan string)
+	go func() {
+		data, _ := io.ReadAll(r)
+		output <- string(data)
+	}()
+	fn()
+	require.NoError(t, w.Close())
+	return <-output
+}
+
+// useListConfig points the list commands at a fresh config directory and
+// output format for the length of a test.
+func useListConfig(t *testing.T, format string) string {
+	savedDir, savedFormat, savedFilter := configDir, listFormat, runsScenarioFilter
+	t.Cleanup(func() {
+		configDir, listFormat, runsScenarioFilter = savedDir, savedFormat, savedFilter
+	})
+	configDir, listFormat, runsScenarioFilter = t.TempDir(), format, ""
+	return configDir
+}
+
+func writeConfigFile(t *testing.T, dir, name, contents string) {
+	require.NoError(t, os.MkdirAll(path.Join(configDir, dir), 0755))
+	require.NoError(t, os.WriteFile(path.Join(configDir, dir, name), []byte(contents), 0644))
+}
+
+func TestListJSON(t *testing.T) {
+	t.Run("scenarios", func(t *testing.T) {
+		useListConfig(t, "json")
+		writeConfigFile(t, "scenarios", "dinner.toml", `version = "1.0.0"
+
+[scenario]
+name = "Dinner Planning"
+description = "Two friends pick a restaurant"
+tags = ["social"]
+
+[agents.Jordan]
+character = "idealist"
+
+[agents.Alex]
+character = "pragmatist"
+
+[goals.restaurant]
+description = "Agree on a restaurant"
+priority = 1
+type = "ConsensusGoal"
+`)
+		writeConfigFile(t, "scenarios", "broken.toml", "[scenario")
+		writeConfigFile(t, "scenarios", "notes.txt", "not a scenario")
+
+		var list []scenarioListEntry
+		require.NoError(t, json.Unmarshal([]byte(captureStdout(t, func() { listScenarios(nil, nil) })), &list))
+		assert.Equal(t, []scenarioListEntry{
+			{File: "broken", Error: "invalid TOML"},
+			{
+				File:        "dinner",
+				Name:        "Dinner Planning",
+				Description: "Two friends pick a restaurant",
+				Agents:      []string{"Alex", "Jordan"},
+				Goals:       1,
+				Tags:        []string{"social"},
+			},
+		}, list)
+	})
+
+	t.Run("characters", func(t *testing.T) {
+		useListConfig(t, "json")
+		writeConfigFile(t, "characters", "pragmatist.toml", `version = "1.0.0"
+
+[external]
+archetype = "The Pragmatist"
+description = "Gets things done"
+positive_traits = ["practical"]
+negative_traits = ["impatient"]
+`)
+		writeConfigFile(t, "characters", "broken.toml", "[external")
+
+		var list []characterListEntry
+		require.NoError(t, json.Unmarshal([]byte(captureStdout(t, func() { listCharacters(nil, nil) })), &list))
+		assert.Equal(t, []characterListEntry{
+			{File: "broken", Error: "invalid TOML"},
+			{
+				File:           "pragmatist",
+				Archetype:      "The Pragmatist",
+				Description:    "Gets things done",
+				PositiveTraits: []string{"practical"},
+				NegativeTraits: []string{"impatient"},
+			},
+		}, list)
+	})
+
+	t.Run("prints empty arrays without a config directory", func(t *testing.T) {
+		useListConfig(t, "json")
+		for name, list := range map[string]func(){
+			"scenarios":  func() { listScenarios(nil, nil) },
+			"characters": func() { listCharacters(nil, nil) },
+			"runs":       func() { listRuns(nil, nil) },
+		} {
+			assert.JSONEq(t, `[]`, captureStdout(t, list), name)
+		}
+	})
+}
+
+func TestListRuns(t *testing.T) {
+	started := time.Date(2025, time.March, 3, 12, 0, 0, 0, time.UTC)
+	saveRuns := func(t *testing.T) {
+		for i, manifest := range []runs.Manifest{
+			{SimulationID: "first", ScenarioFile: "dinner.toml", ScenarioName: "Dinner", StartTime: started, Chronicle: "first.jsonl", Scenario: "[scenario]"},
+			{SimulationID: "third", ScenarioFile: "dinner.toml", ScenarioName: "Dinner", StartTime: started.Add(2 * time.Hour)},
+			{SimulationID: "second", ScenarioFile: "lunch.toml", ScenarioName: "Lunch", StartTime: started.Add(time.Hour)},
+		} {
+			require.NoError(t, runs.Save(configDir, manifest), "manifest %d", i)
+		}
+	}
+	ids := func(list []runListEntry) []string {
+		var ids []string
+		for _, item := range list {
+			ids = append(ids, item.SimulationID)
+		}
+		return ids
+	}
+
+	t.Run("lists runs newest first as JSON", func(t *testing.T) {
+		useListConfig(t, "json")
+		saveRuns(t)
+
+		var list []runListEntry
+		require.NoError(t, json.Unmarshal([]byte(captureStdout(t, func() { listRuns(nil, nil) })), &list))
+		assert.Equal(t, []string{"third", "second", "first"}, ids(list))
+		assert.Equal(t, runListEntry{
+			SimulationID: "first",
+			ScenarioFile: "dinner.toml",
+			ScenarioName: "Dinner",
+			StartTime:    started,
+			Chronicle:    "first.jsonl",
+			Manifest:     runs.ManifestPath(configDir, "first"),
+		}, list[2])
+	})
+
+	t.Run("filters by scenario", func(t *testing.T) {
+		useListConfig(t, "json")
+		saveRuns(t)
+		runsScenarioFilter = "dinner.toml"
+
+		var list []runListEntry
+		require.NoError(t, json.Unmarshal([]byte(captureStdout(t, func() { listRuns(nil, nil) })), &list))
+		assert.Equal(t, []string{"third", "first"}, ids(list))
+
+		runsScenarioFilter = "breakfast.toml"
+		assert.JSONEq(t, `[]`, captureStdout(t, func() { listRuns(nil, nil) }))
+	})
+
+	t.Run("lists runs as text", func(t *testing.T) {
+		useListConfig(t, "text")
+		assert.Equal(t, "No runs found.\n", captureStdout(t, func() { listRuns(nil, nil) }))
+
+		saveRuns(t)
+		output := captureStdout(t, func() { listRuns(nil, nil) })
+		assert.Contains(t, output, "  • third\n    Scenario: Dinner (dinner.toml)\n")
+		assert.Contains(t, output, "    Chronicle: first.jsonl\n")
+		assert.Less(t, strings.Index(output, "third"), strings.Index(output, "first"))
+	})
+}
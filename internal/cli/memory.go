@@ -0,0 +1,91 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/poiesic/wonda/internal/memory"
+	"github.com/poiesic/wonda/internal/scenarios"
+	"github.com/poiesic/wonda/internal/simulations"
+	"github.com/spf13/cobra"
+)
+
+var memoryCommand = &cobra.Command{
+	Use:     "memory",
+	Short:   "Manage knowledge seeded into simulation memory",
+	Aliases: []string{"mem"},
+}
+
+var memoryIngestCommand = &cobra.Command{
+	Use:   "ingest <file>...",
+	Short: "Ingest documents as knowledge for a scenario's runs",
+	Long: `Chunk and embed text or Markdown documents, such as briefings or case files, and
+seed them into every run of a scenario as shared scene knowledge that agents can look
+up with the query_knowledge tool. Re-ingesting a file replaces its earlier chunks.
+
+PDFs must be converted to text first, e.g. with pdftotext.`,
+	Args: cobra.MinimumNArgs(1),
+	Run:  memoryIngest,
+}
+
+var ingestScenario string
+var ingestScope string
+
+func init() {
+	rootCommand.AddCommand(memoryCommand)
+	memoryCommand.AddCommand(memoryIngestCommand)
+
+	memoryIngestCommand.Flags().StringVar(&ingestScenario, "scenario", "", "Scenario whose runs are seeded with the documents")
+	memoryIngestCommand.Flags().StringVar(&ingestScope, "scope", memory.ScopeScene, "Who knows the documents (scene: every agent)")
+	memoryIngestCommand.MarkFlagRequired("scenario")
+}
+
+func memoryIngest(cmd *cobra.Command, args []string) {
+	defer memory.DestroyONNXEnvironment()
+
+	scenarioName := strings.TrimSuffix(ingestScenario, ".toml")
+	scenarioPath := path.Join(configDir, "scenarios", scenarioName+".toml")
+	scenarioData, err := os.ReadFile(scenarioPath)
+	if err != nil {
+		reportErrorAndDieP(scenarioPath, err)
+	}
+	scenario, err := scenarios.LoadScenario(scenarioData)
+	if err != nil {
+		reportErrorAndDieP(scenarioPath, err)
+	}
+
+	kb, err := memory.LoadKnowledge(configDir, scenarioName)
+	if err != nil {
+		reportErrorAndDie(err)
+	}
+
+	// Embed with the model the scenario's runs will search with
+	embedder, model, err := simulations.NewKnowledgeEmbedder(configDir, scenario)
+	if err != nil {
+		reportErrorAndDie(err)
+	}
+
+	ctx := context.Background()
+	for _, filePath := range args {
+		if strings.EqualFold(path.Ext(filePath), ".pdf") {
+			reportErrorAndDieS(fmt.Sprintf("%s: extract the text first (e.g. pdftotext %s)", filePath, filePath))
+		}
+		text, err := os.ReadFile(filePath)
+		if err != nil {
+			reportErrorAndDieP(filePath, err)
+		}
+		chunks, err := kb.Ingest(ctx, embedder, model, path.Base(filePath), string(text), ingestScope)
+		if err != nil {
+			reportErrorAndDieP(filePath, err)
+		}
+		fmt.Printf("  • %s (%d chunks)\n", filePath, chunks)
+	}
+
+	if err := kb.Save(configDir); err != nil {
+		reportErrorAndDie(err)
+	}
+	reportSuccess(fmt.Sprintf("Scenario %s now has %d knowledge chunks", scenarioName, len(kb.Chunks)))
+}
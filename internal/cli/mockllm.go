@@ -0,0 +1,72 @@
+package cli
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/poiesic/wonda/internal/simulations"
+	"github.com/spf13/cobra"
+)
+
+var mockLLMCommand = &cobra.Command{
+	Use:   "mockllm",
+	Short: "Serve mock OpenAI- and Anthropic-compatible LLM endpoints",
+	Long: `Serve OpenAI-compatible (/v1/chat/completions) and Anthropic-compatible
+(/v1/messages) endpoints that answer the way a dry run does, so end-to-end demos
+and CI runs go through the real provider clients without a real provider.
+
+Point a provider's base_url at http://<addr>/v1 and give its models a name the
+server lists ("mock" unless the script lists others). A script can script what
+each agent says, proposes and votes, as for --dry-run-script, and add rules:
+canned replies and tool calls written as templates, extra latency, and errors.`,
+	Args: cobra.NoArgs,
+	Run:  mockLLM,
+}
+
+var mockLLMAddr string
+var mockLLMScript string
+var mockLLMLatency string
+var mockLLMChaos string
+
+func init() {
+	rootCommand.AddCommand(mockLLMCommand)
+
+	mockLLMCommand.Flags().StringVar(&mockLLMAddr, "addr", "127.0.0.1:8089", "Address to listen on")
+	mockLLMCommand.Flags().StringVar(&mockLLMScript, "script", "", "TOML file scripting the server's responses")
+	mockLLMCommand.Flags().StringVar(&mockLLMLatency, "latency", "", "Delay before every response, e.g. 250ms (overrides the script)")
+	mockLLMCommand.Flags().StringVar(&mockLLMChaos, "chaos", "", "Inject failures: 'on' or e.g. 'errors=0.1,slow=0.1,delay=5s,malformed=0.1,truncate=0.1,seed=42'")
+}
+
+func mockLLM(cmd *cobra.Command, args []string) {
+	script := &simulations.MockServerScript{}
+	if mockLLMScript != "" {
+		loaded, err := simulations.LoadMockServerScript(mockLLMScript)
+		if err != nil {
+			reportErrorAndDieP(mockLLMScript, err)
+		}
+		script = loaded
+	}
+	if mockLLMLatency != "" {
+		script.Latency = mockLLMLatency
+		if err := script.Validate(); err != nil {
+			reportErrorAndDie(err)
+		}
+	}
+
+	var chaos *simulations.ChaosConfig
+	if mockLLMChaos != "" {
+		var err error
+		chaos, err = simulations.ParseChaosSpec(mockLLMChaos)
+		if err != nil {
+			reportErrorAndDie(err)
+		}
+	}
+
+	server := simulations.NewMockServer(script, chaos)
+	slog.Info("mock LLM server listening", "addr", mockLLMAddr, "rules", len(script.Rules))
+	fmt.Printf("Mock LLM server listening on http://%s/v1\n", mockLLMAddr)
+	if err := http.ListenAndServe(mockLLMAddr, server); err != nil {
+		reportErrorAndDie(err)
+	}
+}
@@ -1,12 +1,15 @@
 package cli
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path"
 	"strings"
+	"time"
 
 	"github.com/poiesic/wonda/internal/config"
+	"github.com/poiesic/wonda/internal/simulations"
 	"github.com/spf13/cobra"
 )
 
@@ -40,6 +43,27 @@ var newModelCommand = &cobra.Command{
 	Run:     newModel,
 }
 
+var probeModelCommand = &cobra.Command{
+	Use:   "probe <model-name>",
+	Short: "Detect how a model returns its reasoning and write the thinking parser into its configuration",
+	Long: `Make one small request to the model's provider and look at the raw response
+for where the model puts its reasoning: a field beside its answer (such as
+reasoning_content), delimiters within it (such as <think>), or nowhere. The
+detected [thinking_parser] replaces the one in the model's configuration, or
+the parser guessed from the model's name when it has none. The rest of the
+file is kept as it is.
+
+Models run with llama_server can't be probed, since their server only runs
+during a simulation.`,
+	Args: cobra.ExactArgs(1),
+	Run:  probeModel,
+}
+
+// probeTimeout bounds a model probe, which waits for a complete answer.
+const probeTimeout = 2 * time.Minute
+
+var newModelProbe bool
+
 var listModelsCommand = &cobra.Command{
 	Use:     "list",
 	Short:   "List all model configurations",
@@ -48,7 +72,11 @@ var listModelsCommand = &cobra.Command{
 }
 
 func init() {
-	modelsCommand.AddCommand(showModelCommand, editModelCommand, newModelCommand, listModelsCommand)
+	modelsCommand.AddCommand(showModelCommand, editModelCommand, newModelCommand, listModelsCommand, probeModelCommand)
+
+	newModelCommand.Flags().BoolVar(&newModelProbe, "probe", false, "After editing, probe the provider and write the detected thinking parser into the configuration")
+
+	addListFormatFlag(listModelsCommand)
 }
 
 func showModel(cmd *cobra.Command, args []string) {
@@ -118,57 +146,160 @@ func newModel(cmd *cobra.Command, args []string) {
 
 	// Open in editor
 	editFile(tomlFile)
+
+	if newModelProbe {
+		probeModelFile(tomlFile)
+	}
+}
+
+func probeModel(cmd *cobra.Command, args []string) {
+	modelName := args[0]
+	if !strings.HasSuffix(modelName, ".toml") {
+		modelName = modelName + ".toml"
+	}
+	probeModelFile(path.Join(configDir, "models", modelName))
+}
+
+// probeModelFile probes a configured model's provider and writes the
+// thinking parser it detects into the model's configuration file.
+func probeModelFile(tomlFile string) {
+	data, err := os.ReadFile(tomlFile)
+	if err != nil {
+		reportErrorAndDieP(tomlFile, err)
+	}
+	model, err := config.LoadModel(data)
+	if err != nil {
+		reportErrorAndDieP(tomlFile, err)
+	}
+	if err := model.Validate(); err != nil {
+		reportErrorAndDieP(tomlFile, err)
+	}
+	if model.LlamaServer != nil {
+		reportErrorAndDieS(fmt.Sprintf("%s runs with llama_server and can't be probed; set its thinking_parser by hand", tomlFile))
+	}
+
+	providersPath := path.Join(configDir, "providers.toml")
+	providers, err := config.LoadProvidersFromFile(providersPath)
+	if err != nil {
+		reportErrorAndDieP(providersPath, err)
+	}
+	provider, ok := providers.Providers[model.Provider]
+	if !ok {
+		reportErrorAndDieS(fmt.Sprintf("provider %s (from model %s) not found in %s", model.Provider, model.Name, providersPath))
+	}
+
+	fmt.Printf("Probing %s on %s...\n", model.Name, provider.Name)
+	ctx, cancel := context.WithTimeout(context.Background(), probeTimeout)
+	defer cancel()
+	parser, err := simulations.ProbeThinkingParser(ctx, provider, model)
+	if err != nil {
+		reportErrorAndDieS(fmt.Sprintf("Failed to probe %s: %v", model.Name, err))
+	}
+
+	updated, err := config.SetThinkingParser(data, parser)
+	if err != nil {
+		reportErrorAndDieP(tomlFile, err)
+	}
+	if err := os.WriteFile(tomlFile, updated, 0644); err != nil {
+		reportErrorAndDieP(tomlFile, err)
+	}
+
+	switch parser.Type {
+	case config.ThinkingParserOutOfBand:
+		reportSuccess(fmt.Sprintf("✅ %s returns its reasoning in %s; wrote an out_of_band thinking parser to %s", model.Name, parser.FieldPath, tomlFile))
+	case config.ThinkingParserInBand:
+		reportSuccess(fmt.Sprintf("✅ %s wraps its reasoning in %s...%s; wrote an in_band thinking parser to %s", model.Name, parser.StartDelimiter, parser.EndDelimiter, tomlFile))
+	default:
+		reportSuccess(fmt.Sprintf("✅ %s returned no reasoning; wrote a thinking parser of type none to %s", model.Name, tomlFile))
+	}
+}
+
+// modelListEntry is one model in `models list` output.
+type modelListEntry struct {
+	File     string `json:"file"` // Name in the models directory, without .toml
+	Model    string `json:"model,omitempty"`
+	Provider string `json:"provider,omitempty"`
+	Thinking string `json:"thinking,omitempty"` // Thinking parser type, if any
+	Error    string `json:"error,omitempty"`    // Why the file couldn't be loaded
 }
 
 func listModels(cmd *cobra.Command, args []string) {
+	asJSON := listAsJSON()
 	modelsDir := path.Join(configDir, "models")
 
 	entries, err := os.ReadDir(modelsDir)
 	if err != nil {
 		if os.IsNotExist(err) {
+			if asJSON {
+				printJSON([]modelListEntry{})
+				return
+			}
 			reportWarning("No models directory found. Run 'wonda init' first.")
 			return
 		}
 		reportErrorAndDieP(modelsDir, err)
 	}
 
-	if len(entries) == 0 {
-		fmt.Println("No model configurations found.")
-		return
-	}
-
-	fmt.Printf("Models in %s:\n\n", modelsDir)
-
+	list := []modelListEntry{}
 	for _, entry := range entries {
 		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".toml") {
 			continue
 		}
 
-		modelFile := path.Join(modelsDir, entry.Name())
-		contents, err := os.ReadFile(modelFile)
+		item := modelListEntry{File: strings.TrimSuffix(entry.Name(), ".toml")}
+		contents, err := os.ReadFile(path.Join(modelsDir, entry.Name()))
 		if err != nil {
-			fmt.Printf("  ❌ %s (error reading file)\n", entry.Name())
+			item.Error = "error reading file"
+			list = append(list, item)
 			continue
 		}
-
 		model, err := config.LoadModel(contents)
 		if err != nil {
-			fmt.Printf("  ❌ %s (invalid TOML)\n", entry.Name())
+			item.Error = "invalid TOML"
+			list = append(list, item)
 			continue
 		}
 
-		nameDisplay := strings.TrimSuffix(entry.Name(), ".toml")
-		if model.Name != "" {
-			fmt.Printf("  • %s\n", nameDisplay)
-			fmt.Printf("    Model: %s\n", model.Name)
-			if model.Provider != "" {
-				fmt.Printf("    Provider: %s\n", model.Provider)
-			}
-			if model.ThinkingParser != nil && model.ThinkingParser.Type != config.ThinkingParserNone {
-				fmt.Printf("    Thinking: %s\n", model.ThinkingParser.Type)
-			}
-		} else {
-			fmt.Printf("  • %s (incomplete)\n", nameDisplay)
+		item.Model = model.Name
+		item.Provider = model.Provider
+		if model.LlamaServer != nil {
+			item.Provider = "llama-server"
+		}
+		if model.ThinkingParser != nil && model.ThinkingParser.Type != config.ThinkingParserNone {
+			item.Thinking = string(model.ThinkingParser.Type)
+		}
+		list = append(list, item)
+	}
+
+	if asJSON {
+		printJSON(list)
+		return
+	}
+
+	if len(list) == 0 {
+		fmt.Println("No model configurations found.")
+		return
+	}
+
+	fmt.Printf("Models in %s:\n\n", modelsDir)
+
+	for _, item := range list {
+		if item.Error != "" {
+			fmt.Printf("  ❌ %s.toml (%s)\n", item.File, item.Error)
+			continue
+		}
+		if item.Model == "" {
+			fmt.Printf("  • %s (incomplete)\n", item.File)
+			continue
+		}
+
+		fmt.Printf("  • %s\n", item.File)
+		fmt.Printf("    Model: %s\n", item.Model)
+		if item.Provider != "" {
+			fmt.Printf("    Provider: %s\n", item.Provider)
+		}
+		if item.Thinking != "" {
+			fmt.Printf("    Thinking: %s\n", item.Thinking)
 		}
 	}
 }
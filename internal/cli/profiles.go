@@ -0,0 +1,76 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path"
+
+	"github.com/spf13/cobra"
+)
+
+// profilesDir is the directory under the base config directory holding named profiles.
+// Each profile is a complete config directory with its own providers, models, and scenarios.
+const profilesDir = "profiles"
+
+var profilesCommand = &cobra.Command{
+	Use:     "profiles",
+	Aliases: []string{"profile"},
+	Short:   "Work with configuration profiles",
+	Long: `Profiles are named configurations (e.g. work, personal, local-only) stored under
+<config-dir>/profiles/<name>, each with its own providers, models, characters, and scenarios.
+
+Select a profile with --profile or $WONDA_PROFILE. Create one with 'wonda --profile <name> init'.`,
+}
+
+var listProfilesCommand = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List configuration profiles",
+	Run:     listProfiles,
+}
+
+var currentProfileCommand = &cobra.Command{
+	Use:   "current",
+	Short: "Show the active profile and its config directory",
+	Run:   currentProfile,
+}
+
+func init() {
+	profilesCommand.AddCommand(listProfilesCommand, currentProfileCommand)
+}
+
+func listProfiles(cmd *cobra.Command, args []string) {
+	dir := path.Join(baseConfigDir, profilesDir)
+	entries, err := os.ReadDir(dir)
+	if err != nil && !os.IsNotExist(err) {
+		reportErrorAndDieP(dir, err)
+	}
+
+	found := false
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		found = true
+		marker := "  "
+		if entry.Name() == profile {
+			marker = "* "
+		}
+		fmt.Printf("%s%s\n", marker, entry.Name())
+	}
+	if !found {
+		fmt.Printf("No profiles in %s\n", dir)
+	}
+}
+
+func currentProfile(cmd *cobra.Command, args []string) {
+	name := profile
+	if name == "" {
+		name = "(none)"
+	}
+	fmt.Printf("Profile: %s\n", name)
+	fmt.Printf("Config:  %s\n", configDir)
+	if _, err := os.Stat(configDir); os.IsNotExist(err) {
+		reportWarning("Config directory doesn't exist yet; run 'wonda init' with the same options to create it")
+	}
+}
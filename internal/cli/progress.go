@@ -0,0 +1,146 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/poiesic/wonda/internal/chronicle"
+	mcpsim "github.com/poiesic/wonda/internal/mcp/simulation"
+	"github.com/poiesic/wonda/internal/simulations"
+	"github.com/spf13/cobra"
+)
+
+// progressTextLength is the most of an event's text a progress line shows.
+const progressTextLength = 72
+
+// asciiReplacements spell common typographic characters in ASCII.
+var asciiReplacements = strings.NewReplacer(
+	"‘", "'", "’", "'", "“", `"`, "”", `"`,
+	"–", "-", "—", "-", "…", "...",
+)
+
+// applyOutputMode sets up the console for --plain and --quiet: no colors, and
+// for --quiet only errors logged unless the log level was chosen.
+func applyOutputMode(cmd *cobra.Command) {
+	if runPlain && runQuiet {
+		reportErrorAndDieS("--plain and --quiet can't be combined")
+	}
+	if !runPlain && !runQuiet {
+		return
+	}
+	if runLive {
+		reportErrorAndDieS("--live can't be combined with --plain or --quiet")
+	}
+	errorStyle, successStyle, warnStyle = lipgloss.NewStyle(), lipgloss.NewStyle(), lipgloss.NewStyle()
+	if runQuiet && !cmd.Flag("log-level").Changed {
+		initLogger("error")
+	}
+}
+
+// progressPrinter writes compact ASCII progress lines for a run, for CI logs.
+// Turns and goals always get a line; agent events at most one per interval,
+// with the number left out noted on the next line.
+type progressPrinter struct {
+	out      io.Writer
+	maxTurns int
+	interval time.Duration
+	now      func() time.Time
+
+	lastEvent time.Time
+	skipped   int
+}
+
+// watchProgress prints a run's progress to out as it happens.
+func watchProgress(sim *simulations.Simulation, out io.Writer, interval time.Duration) {
+	p := &progressPrinter{out: out, maxTurns: sim.MaxTurns(), interval: interval, now: time.Now}
+	sim.OnTurnStart(func(ctx context.Context, turn int) {
+		p.turnStarted(turn)
+	})
+	sim.OnAgentAction(func(ctx context.Context, turn int, event chronicle.Event) {
+		p.agentActed(event)
+	})
+	sim.OnGoalComplete(func(ctx context.Context, turn int, completion chronicle.GoalCompletion) {
+		p.goalCompleted(completion)
+	})
+}
+
+func (p *progressPrinter) turnStarted(turn int) {
+	p.flushSkipped()
+	fmt.Fprintf(p.out, "turn %d/%d\n", turn, p.maxTurns)
+}
+
+func (p *progressPrinter) agentActed(event chronicle.Event) {
+	now := p.now()
+	if !p.lastEvent.IsZero() && now.Sub(p.lastEvent) < p.interval {
+		p.skipped++
+		return
+	}
+	p.lastEvent = now
+	p.flushSkipped()
+
+	eventType := event.Type
+	if eventType == "" {
+		eventType = "dialogue"
+	}
+	line := fmt.Sprintf("  %s (%s)", asciiText(event.AgentName), eventType)
+	if event.Dialogue != "" {
+		line += ": " + truncateText(asciiText(event.Dialogue), progressTextLength)
+	}
+	fmt.Fprintln(p.out, line)
+}
+
+func (p *progressPrinter) goalCompleted(completion chronicle.GoalCompletion) {
+	p.flushSkipped()
+	fmt.Fprintf(p.out, "  goal %s %s: %s\n", asciiText(completion.GoalName), completion.Status,
+		truncateText(asciiText(completion.Solution), progressTextLength))
+}
+
+// flushSkipped notes the events left out since the last line, if any.
+func (p *progressPrinter) flushSkipped() {
+	if p.skipped == 0 {
+		return
+	}
+	fmt.Fprintf(p.out, "  (%d more events)\n", p.skipped)
+	p.skipped = 0
+}
+
+// printRunResult writes a one-line result of a finished run.
+func printRunResult(sim *simulations.Simulation, out io.Writer) {
+	world := sim.World.Snapshot()
+	completed := 0
+	for _, goal := range world.Goals {
+		if goal.Status == mcpsim.GoalCompleted {
+			completed++
+		}
+	}
+	fmt.Fprintf(out, "finished after %d/%d turns: %d/%d goals completed; chronicle %s\n",
+		world.CurrentTurn, sim.MaxTurns(), completed, len(world.Goals), sim.ChroniclePath())
+}
+
+// asciiText folds text onto one line of ASCII, replacing characters it
+// can't spell with '?'.
+func asciiText(text string) string {
+	text = asciiReplacements.Replace(strings.Join(strings.Fields(text), " "))
+	var b strings.Builder
+	for _, r := range text {
+		if r < utf8.RuneSelf {
+			b.WriteRune(r)
+		} else {
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
+
+// truncateText shortens ASCII text to at most length bytes, marking the cut.
+func truncateText(text string, length int) string {
+	if len(text) <= length {
+		return text
+	}
+	return text[:length-3] + "..."
+}
@@ -0,0 +1,137 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/poiesic/wonda/internal/chronicle"
+	mcpsim "github.com/poiesic/wonda/internal/mcp/simulation"
+	"github.com/poiesic/wonda/internal/scenarios"
+	"github.com/poiesic/wonda/internal/simulations"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProgressPrinter(t *testing.T) {
+	var out bytes.Buffer
+	clock := time.Date(2026, 1, 2, 15, 4, 5, 0, time.UTC)
+	p := &progressPrinter{out: &out, maxTurns: 10, interval: 5 * time.Second, now: func() time.Time { return clock }}
+	say := func(after time.Duration, event chronicle.Event) {
+		clock = clock.Add(after)
+		p.agentActed(event)
+	}
+
+	p.turnStarted(1)
+	say(0, chronicle.Event{AgentName: "Alex", Dialogue: "How about “sushi”… or tacos?"})
+	say(time.Second, chronicle.Event{AgentName: "Jordan", Dialogue: "Sushi!"})
+	say(time.Second, chronicle.Event{AgentName: "Alex", Type: "action", Dialogue: "nods"})
+	say(5*time.Second, chronicle.Event{AgentName: "Jordan", Type: "action", Dialogue: strings.Repeat("waves ", 20)})
+	say(time.Second, chronicle.Event{AgentName: "Zoë", Type: "thinking"})
+	p.goalCompleted(chronicle.GoalCompletion{GoalName: "restaurant", Status: "completed", Solution: "Sushi\non Main St"})
+	p.turnStarted(2)
+
+	assert.Equal(t, `turn 1/10
+  Alex (dialogue): How about "sushi"... or tacos?
+  (2 more events)
+  Jordan (action): waves waves waves waves waves waves waves waves waves waves waves wav...
+  (1 more events)
+  goal restaurant completed: Sushi on Main St
+turn 2/10
+`, out.String())
+}
+
+func TestAsciiText(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{text: "plain", want: "plain"},
+		{text: "it’s “fine” – really—ok…", want: `it's "fine" - really-ok...`},
+		{text: "  several\n lines\there ", want: "several lines here"},
+		{text: "café 🍣", want: "caf? ?"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			assert.Equal(t, tt.want, asciiText(tt.text))
+		})
+	}
+}
+
+func TestTruncateText(t *testing.T) {
+	assert.Equal(t, "short", truncateText("short", 10))
+	assert.Equal(t, "exactly10!", truncateText("exactly10!", 10))
+	assert.Equal(t, "too lon...", truncateText("too long by far", 10))
+}
+
+func TestApplyOutputMode(t *testing.T) {
+	// useOutputMode sets the console flags for the length of a test, restoring
+	// the styles and logger applyOutputMode changes.
+	useOutputMode := func(t *testing.T, plain, quiet bool) {
+		savedPlain, savedQuiet, savedLive := runPlain, runQuiet, runLive
+		savedStyles := []lipgloss.Style{errorStyle, successStyle, warnStyle}
+		savedLogger := slog.Default()
+		t.Cleanup(func() {
+			runPlain, runQuiet, runLive = savedPlain, savedQuiet, savedLive
+			errorStyle, successStyle, warnStyle = savedStyles[0], savedStyles[1], savedStyles[2]
+			slog.SetDefault(savedLogger)
+		})
+		runPlain, runQuiet, runLive = plain, quiet, false
+		initLogger("warn")
+	}
+	newCommand := func(args ...string) *cobra.Command {
+		cmd := &cobra.Command{Use: "run"}
+		cmd.Flags().String("log-level", "warn", "")
+		require.NoError(t, cmd.ParseFlags(args))
+		return cmd
+	}
+	ctx := context.Background()
+
+	t.Run("leaves the console alone by default", func(t *testing.T) {
+		useOutputMode(t, false, false)
+		applyOutputMode(newCommand())
+		assert.True(t, errorStyle.GetBold())
+		assert.True(t, slog.Default().Enabled(ctx, slog.LevelWarn))
+	})
+
+	t.Run("plain drops colors", func(t *testing.T) {
+		useOutputMode(t, true, false)
+		applyOutputMode(newCommand())
+		assert.False(t, errorStyle.GetBold())
+		assert.Equal(t, lipgloss.NewStyle(), successStyle)
+		assert.True(t, slog.Default().Enabled(ctx, slog.LevelWarn))
+	})
+
+	t.Run("quiet logs only errors", func(t *testing.T) {
+		useOutputMode(t, false, true)
+		applyOutputMode(newCommand())
+		assert.Equal(t, lipgloss.NewStyle(), warnStyle)
+		assert.False(t, slog.Default().Enabled(ctx, slog.LevelWarn))
+		assert.True(t, slog.Default().Enabled(ctx, slog.LevelError))
+	})
+
+	t.Run("quiet keeps a chosen log level", func(t *testing.T) {
+		useOutputMode(t, false, true)
+		applyOutputMode(newCommand("--log-level", "warn"))
+		assert.True(t, slog.Default().Enabled(ctx, slog.LevelWarn))
+	})
+}
+
+func TestPrintRunResult(t *testing.T) {
+	scenario := scenarios.NewScenario()
+	scenario.Basics.MaxTurns = 10
+	sim := simulations.NewSimulation(scenario, t.TempDir())
+	sim.World.SetTurn(4)
+	sim.World.AddGoal(&mcpsim.InteractiveGoal{Name: "restaurant", Status: mcpsim.GoalCompleted})
+	sim.World.AddGoal(&mcpsim.InteractiveGoal{Name: "dessert", Status: mcpsim.GoalPending})
+
+	var out bytes.Buffer
+	printRunResult(sim, &out)
+	assert.Equal(t, "finished after 4/10 turns: 1/2 goals completed; chronicle \n", out.String())
+}
@@ -4,7 +4,10 @@ import (
 	"fmt"
 	"os"
 	"path"
+	"text/tabwriter"
+	"time"
 
+	"github.com/poiesic/wonda/internal/usage"
 	"github.com/spf13/cobra"
 )
 
@@ -28,10 +31,23 @@ var editProviderCommand = &cobra.Command{
 	Run:     editProvider,
 }
 
+var statsProviderCommand = &cobra.Command{
+	Use:   "stats",
+	Short: "Show token usage and cost per provider/model across runs",
+	Run:   providerStats,
+}
+
 var editors = []string{"vi", "vim", "nvi", "nano"}
 
+var statsPeriod string
+var statsSince time.Duration
+var statsCSV string
+
 func init() {
-	providersCommand.AddCommand(showProviderCommand, editProviderCommand)
+	statsProviderCommand.Flags().StringVar(&statsPeriod, "by", "day", "Group usage by period: day, week, month, or all")
+	statsProviderCommand.Flags().DurationVar(&statsSince, "since", 0, "Only include runs started within this duration (e.g. 168h)")
+	statsProviderCommand.Flags().StringVar(&statsCSV, "csv", "", "Also write the stats as CSV to this file (- for stdout)")
+	providersCommand.AddCommand(showProviderCommand, editProviderCommand, statsProviderCommand)
 }
 
 func showProvider(cmd *cobra.Command, args []string) {
@@ -51,3 +67,61 @@ func editProvider(cmd *cobra.Command, args []string) {
 	}
 	editFile(tomlFile)
 }
+
+func providerStats(cmd *cobra.Command, args []string) {
+	catalogPath := usage.CatalogPath(configDir)
+	reports, err := usage.LoadReports(catalogPath)
+	if err != nil {
+		reportErrorAndDieP(catalogPath, err)
+	}
+
+	var since time.Time
+	if statsSince > 0 {
+		since = time.Now().Add(-statsSince)
+	}
+
+	rows, err := usage.Aggregate(reports, statsPeriod, since)
+	if err != nil {
+		reportErrorAndDie(err)
+	}
+
+	if len(rows) == 0 {
+		fmt.Println("No usage recorded yet.")
+		fmt.Println("\nUsage is recorded each time a scenario runs.")
+		return
+	}
+
+	if statsCSV == "-" {
+		if err := usage.WriteCSV(os.Stdout, rows); err != nil {
+			reportErrorAndDie(err)
+		}
+		return
+	}
+
+	fmt.Printf("Usage from %s:\n\n", catalogPath)
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "PERIOD\tPROVIDER\tMODEL\tRUNS\tREQUESTS\tINPUT\tOUTPUT\tCOST")
+	var totalInput, totalOutput int
+	var totalCost float64
+	for _, row := range rows {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%d\t%d\t%d\t%.4f\n",
+			row.Period, row.Provider, row.Model, row.Runs, row.Requests, row.InputTokens, row.OutputTokens, row.Cost)
+		totalInput += row.InputTokens
+		totalOutput += row.OutputTokens
+		totalCost += row.Cost
+	}
+	fmt.Fprintf(w, "TOTAL\t\t\t\t\t%d\t%d\t%.4f\n", totalInput, totalOutput, totalCost)
+	w.Flush()
+
+	if statsCSV != "" {
+		file, err := os.Create(statsCSV)
+		if err != nil {
+			reportErrorAndDieP(statsCSV, err)
+		}
+		defer file.Close()
+		if err := usage.WriteCSV(file, rows); err != nil {
+			reportErrorAndDieP(statsCSV, err)
+		}
+		reportSuccess(fmt.Sprintf("\nWrote CSV: %s", statsCSV))
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path"
+	"strings"
 
 	"github.com/spf13/cobra"
 )
@@ -17,8 +18,9 @@ func init() {
 
 	flagDescription := fmt.Sprintf("Path to Wonda configuration (source: %s)", source)
 	rootCommand.PersistentFlags().StringVarP(&configDir, "config-dir", "c", defaultConfig, flagDescription)
+	rootCommand.PersistentFlags().StringVarP(&profile, "profile", "p", "", "Named configuration profile under <config-dir>/profiles (default $WONDA_PROFILE)")
 	rootCommand.PersistentFlags().StringVar(&logLevel, "log-level", "warn", "Log level (debug, info, warn, error)")
-	rootCommand.AddCommand(initCommand, nukeCommand, providersCommand, embeddingsCommand, modelsCommand, charactersCommand, scenariosCommand, versionCommand)
+	rootCommand.AddCommand(initCommand, nukeCommand, providersCommand, embeddingsCommand, modelsCommand, charactersCommand, scenariosCommand, profilesCommand, versionCommand)
 }
 
 // getDefaultConfigDirWithSource returns the default configuration directory
@@ -38,7 +40,27 @@ func getDefaultConfigDirWithSource() (string, string) {
 	return path.Join(homeDir, ".config", "wonda"), "default"
 }
 
+// resolveProfile points configDir at the selected profile's directory,
+// the --profile flag's or else $WONDA_PROFILE's.
+// The base config directory is kept in baseConfigDir for listing profiles.
+func resolveProfile() error {
+	baseConfigDir = configDir
+	if profile == "" {
+		profile = os.Getenv("WONDA_PROFILE")
+	}
+	if profile == "" {
+		return nil
+	}
+	if strings.ContainsAny(profile, `/\`) || profile == "." || profile == ".." {
+		return fmt.Errorf("invalid profile name '%s'", profile)
+	}
+	configDir = path.Join(baseConfigDir, profilesDir, profile)
+	return nil
+}
+
 var configDir string
+var baseConfigDir string
+var profile string
 var logLevel string
 
 var rootCommand = &cobra.Command{
@@ -47,6 +69,9 @@ var rootCommand = &cobra.Command{
 	Long:  `Your creative sandbox for character-driven storytelling`,
 	PersistentPreRun: func(cmd *cobra.Command, args []string) {
 		initLogger(logLevel)
+		if err := resolveProfile(); err != nil {
+			reportErrorAndDie(err)
+		}
 	},
 	Run: func(cmd *cobra.Command, args []string) {
 		cmd.Help()
@@ -0,0 +1,52 @@
+package cli
+
+import (
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveProfile(t *testing.T) {
+	tests := []struct {
+		name    string
+		flag    string
+		env     string
+		want    string
+		wantErr bool
+	}{
+		{name: "no profile", want: "/wonda"},
+		{name: "flag", flag: "work", want: path.Join("/wonda", profilesDir, "work")},
+		{name: "$WONDA_PROFILE default", env: "home", want: path.Join("/wonda", profilesDir, "home")},
+		{name: "flag over $WONDA_PROFILE", flag: "work", env: "home", want: path.Join("/wonda", profilesDir, "work")},
+		{name: "dotted names are fine", flag: "v1.2", want: path.Join("/wonda", profilesDir, "v1.2")},
+		{name: "parent directory", flag: "..", wantErr: true},
+		{name: "current directory", flag: ".", wantErr: true},
+		{name: "slash", flag: "../other", wantErr: true},
+		{name: "nested", flag: "a/b", wantErr: true},
+		{name: "backslash", flag: `..\other`, wantErr: true},
+		{name: "invalid $WONDA_PROFILE", env: "..", wantErr: true},
+	}
+
+	savedDir, savedProfile := configDir, profile
+	t.Cleanup(func() {
+		configDir, baseConfigDir, profile = savedDir, "", savedProfile
+	})
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("WONDA_PROFILE", tt.env)
+			configDir, profile = "/wonda", tt.flag
+
+			err := resolveProfile()
+			assert.Equal(t, "/wonda", baseConfigDir)
+			if tt.wantErr {
+				assert.ErrorContains(t, err, "invalid profile name")
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, configDir)
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package cli
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/poiesic/wonda/internal/runs"
+	"github.com/spf13/cobra"
+)
+
+var runsCommand = &cobra.Command{
+	Use:   "runs",
+	Short: "Inspect recorded simulation runs",
+}
+
+var listRunsCommand = &cobra.Command{
+	Use:     "list",
+	Short:   "List simulation runs, newest first",
+	Aliases: []string{"l"},
+	Run:     listRuns,
+}
+
+var runsScenarioFilter string
+
+func init() {
+	rootCommand.AddCommand(runsCommand)
+	runsCommand.AddCommand(listRunsCommand)
+
+	listRunsCommand.Flags().StringVar(&runsScenarioFilter, "scenario", "", "Only list runs of this scenario file")
+	addListFormatFlag(listRunsCommand)
+}
+
+// runListEntry is one run in `runs list` output. The scenario TOML is left
+// out; it's available from the manifest itself.
+type runListEntry struct {
+	SimulationID string    `json:"simulation_id"`
+	ScenarioFile string    `json:"scenario_file"`
+	ScenarioName string    `json:"scenario_name"`
+	StartTime    time.Time `json:"start_time"`
+	Chronicle    string    `json:"chronicle,omitempty"`
+	Outcomes     string    `json:"outcomes,omitempty"`
+	Manifest     string    `json:"manifest"`
+}
+
+func listRuns(cmd *cobra.Command, args []string) {
+	asJSON := listAsJSON()
+
+	manifests, err := runs.List(configDir)
+	if err != nil {
+		reportErrorAndDieP(runs.ManifestDir(configDir), err)
+	}
+
+	list := []runListEntry{}
+	for _, manifest := range manifests {
+		if runsScenarioFilter != "" && manifest.ScenarioFile != runsScenarioFilter {
+			continue
+		}
+		list = append(list, runListEntry{
+			SimulationID: manifest.SimulationID,
+			ScenarioFile: manifest.ScenarioFile,
+			ScenarioName: manifest.ScenarioName,
+			StartTime:    manifest.StartTime,
+			Chronicle:    manifest.Chronicle,
+			Outcomes:     manifest.Outcomes,
+			Manifest:     runs.ManifestPath(configDir, manifest.SimulationID),
+		})
+	}
+
+	if asJSON {
+		printJSON(list)
+		return
+	}
+
+	if len(list) == 0 {
+		fmt.Println("No runs found.")
+		return
+	}
+
+	fmt.Printf("Runs in %s:\n\n", runs.ManifestDir(configDir))
+	for _, item := range list {
+		fmt.Printf("  • %s\n", item.SimulationID)
+		fmt.Printf("    Scenario: %s (%s)\n", item.ScenarioName, item.ScenarioFile)
+		fmt.Printf("    Started: %s\n", item.StartTime.Local().Format(time.DateTime))
+		if item.Chronicle != "" {
+			fmt.Printf("    Chronicle: %s\n", item.Chronicle)
+		}
+	}
+}
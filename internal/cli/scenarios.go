@@ -6,11 +6,15 @@ import (
 	"log/slog"
 	"os"
 	"path"
+	"sort"
 	"strings"
 	"time"
 
+	"github.com/poiesic/wonda/internal/chronicle"
 	"github.com/poiesic/wonda/internal/config"
+	"github.com/poiesic/wonda/internal/dashboard"
 	"github.com/poiesic/wonda/internal/memory"
+	"github.com/poiesic/wonda/internal/runs"
 	"github.com/poiesic/wonda/internal/scenarios"
 	"github.com/poiesic/wonda/internal/simulations"
 	"github.com/spf13/cobra"
@@ -61,8 +65,71 @@ var runScenarioCommand = &cobra.Command{
 	Run:     runScenario,
 }
 
+var resumeScenarioCommand = &cobra.Command{
+	Use:   "resume <checkpoint>",
+	Short: "Continue a crashed or timed out simulation from its checkpoint",
+	Long: `Continue a simulation from the checkpoint file written next to its chronicle
+after every turn. The run picks up with the turn after the checkpoint, using the
+scenario definition it started with, and appends to the same chronicle.`,
+	Args: cobra.ExactArgs(1),
+	Run:  resumeScenario,
+}
+
+var validateScenarioCommand = &cobra.Command{
+	Use:     "validate <scenario-name>",
+	Aliases: []string{"v"},
+	Short:   "Check a scenario for problems without running it",
+	Long: `Statically check a scenario: that it parses, its characters exist, its goals
+are assigned to defined agents with thresholds in range, every agent's model,
+ensemble member and goal judge resolves to a configured provider, its embedding
+and memory backend are configured, and its durations are positive. No requests
+are made to providers; 'run' still checks credentials and model names before starting.`,
+	Args: cobra.ExactArgs(1),
+	Run:  validateScenario,
+}
+
+var runChaos string
+var runStream bool
+var runLive bool
+var runPlain bool
+var runQuiet bool
+var runProgressInterval time.Duration
+var runCiteMemories bool
+var runToolTranscript bool
+var runChecksums bool
+var runReasoning string
+var runSpeed string
+var runDryRun bool
+var runDryRunScript string
+var runWeb string
+var runReplay string
+
 func init() {
-	scenariosCommand.AddCommand(showScenarioCommand, editScenarioCommand, newScenarioCommand, listScenariosCommand, runScenarioCommand)
+	scenariosCommand.AddCommand(showScenarioCommand, editScenarioCommand, newScenarioCommand, listScenariosCommand, runScenarioCommand, resumeScenarioCommand, diffScenarioCommand, validateScenarioCommand, batchScenarioCommand)
+
+	addListFormatFlag(listScenariosCommand)
+
+	runScenarioCommand.Flags().StringVar(&runChaos, "chaos", "", "Inject failures for robustness testing: 'on' or e.g. 'errors=0.1,slow=0.1,delay=5s,malformed=0.1,truncate=0.1,seed=42'")
+	runScenarioCommand.Flags().BoolVar(&runStream, "stream", false, "Write partial utterances to the chronicle as agents speak, for live viewers")
+	runScenarioCommand.Flags().BoolVar(&runCiteMemories, "cite-memories", false, "Debug: have agents cite the memory IDs behind what they say and record them in the chronicle")
+	runScenarioCommand.Flags().BoolVar(&runToolTranscript, "tool-transcript", false, "Debug: record every tool call with its arguments, result, latency and agent in <chronicle>.tools.jsonl")
+	resumeScenarioCommand.Flags().BoolVar(&runToolTranscript, "tool-transcript", false, "Debug: record every tool call with its arguments, result, latency and agent in <chronicle>.tools.jsonl")
+	runScenarioCommand.Flags().BoolVar(&runChecksums, "checksums", false, "End every chronicle line with a CRC-32 so damage to the file is detected when it is read")
+	runScenarioCommand.Flags().StringVar(&runReasoning, "reasoning", "", "Override the scenario's transparency: 'shared' shows agents the reasons others give for what they say, 'private' keeps them to the chronicle")
+	runScenarioCommand.Flags().BoolVar(&runLive, "live", false, "Print agent thinking and dialogue to the terminal token by token as it streams in")
+	for _, cmd := range []*cobra.Command{runScenarioCommand, resumeScenarioCommand} {
+		cmd.Flags().BoolVar(&runPlain, "plain", false, "Print compact ASCII progress lines without colors, for CI logs")
+		cmd.Flags().BoolVar(&runQuiet, "quiet", false, "Print only errors and a one-line result; the chronicle has the details")
+		cmd.Flags().DurationVar(&runProgressInterval, "progress-interval", 5*time.Second, "Least time between agent event lines with --plain")
+	}
+	resumeScenarioCommand.Flags().BoolVar(&runStream, "stream", false, "Write partial utterances to the chronicle as agents speak, for live viewers")
+	resumeScenarioCommand.Flags().BoolVar(&runLive, "live", false, "Print agent thinking and dialogue to the terminal token by token as it streams in")
+	runScenarioCommand.Flags().BoolVar(&runDryRun, "dry-run", false, "Answer every LLM request with deterministic canned responses instead of calling providers: no API keys, no cost")
+	runScenarioCommand.Flags().StringVar(&runDryRunScript, "dry-run-script", "", "TOML file scripting what agents say, propose and vote in a dry run (implies --dry-run)")
+	runScenarioCommand.Flags().StringVar(&runWeb, "web", "", "Serve a live dashboard of the run at this address, e.g. ':8080'")
+	runScenarioCommand.Flags().StringVar(&runReplay, "replay", "", "Chronicle of an earlier run to compare this one with turn by turn, reporting the first turn that played out differently")
+	resumeScenarioCommand.Flags().StringVar(&runWeb, "web", "", "Serve a live dashboard of the run at this address, e.g. ':8080'")
+	runScenarioCommand.Flags().StringVar(&runSpeed, "speed", simulations.SpeedBalanced, "Speed profile trading fidelity for speed: "+strings.Join(simulations.SpeedProfileNames, ", "))
 }
 
 func showScenario(cmd *cobra.Command, args []string) {
@@ -132,92 +199,264 @@ func newScenario(cmd *cobra.Command, args []string) {
 	editFile(tomlFile)
 }
 
+// scenarioListEntry is one scenario in `scenarios list` output.
+type scenarioListEntry struct {
+	File        string   `json:"file"` // Name in the scenarios directory, without .toml
+	Name        string   `json:"name,omitempty"`
+	Description string   `json:"description,omitempty"`
+	Agents      []string `json:"agents,omitempty"`
+	Goals       int      `json:"goals"`
+	Tags        []string `json:"tags,omitempty"`
+	Error       string   `json:"error,omitempty"` // Why the file couldn't be loaded
+}
+
 func listScenarios(cmd *cobra.Command, args []string) {
+	asJSON := listAsJSON()
 	scenariosDir := path.Join(configDir, "scenarios")
 
 	entries, err := os.ReadDir(scenariosDir)
 	if err != nil {
 		if os.IsNotExist(err) {
+			if asJSON {
+				printJSON([]scenarioListEntry{})
+				return
+			}
 			reportWarning("No scenarios directory found. Run 'wonda init' first.")
 			return
 		}
 		reportErrorAndDieP(scenariosDir, err)
 	}
 
-	if len(entries) == 0 {
-		fmt.Println("No scenario definitions found.")
-		return
-	}
-
-	fmt.Printf("Scenarios in %s:\n\n", scenariosDir)
-
+	list := []scenarioListEntry{}
 	for _, entry := range entries {
 		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".toml") {
 			continue
 		}
 
-		scenarioFile := path.Join(scenariosDir, entry.Name())
-		contents, err := os.ReadFile(scenarioFile)
+		item := scenarioListEntry{File: strings.TrimSuffix(entry.Name(), ".toml")}
+		contents, err := os.ReadFile(path.Join(scenariosDir, entry.Name()))
 		if err != nil {
-			fmt.Printf("  ❌ %s (error reading file)\n", entry.Name())
+			item.Error = "error reading file"
+			list = append(list, item)
 			continue
 		}
-
 		scenario, err := scenarios.LoadScenario(contents)
 		if err != nil {
-			fmt.Printf("  ❌ %s (invalid TOML)\n", entry.Name())
+			item.Error = "invalid TOML"
+			list = append(list, item)
 			continue
 		}
 
-		nameDisplay := strings.TrimSuffix(entry.Name(), ".toml")
-		if scenario.Basics != nil && scenario.Basics.Name != "" {
-			fmt.Printf("  • %s\n", nameDisplay)
-			fmt.Printf("    Name: %s\n", scenario.Basics.Name)
-			if scenario.Basics.Description != "" {
-				// Truncate description if too long
-				desc := scenario.Basics.Description
-				if len(desc) > 60 {
-					desc = desc[:57] + "..."
-				}
-				fmt.Printf("    Description: %s\n", desc)
-			}
-			if len(scenario.Agents) > 0 {
-				agentNames := make([]string, 0, len(scenario.Agents))
-				for name := range scenario.Agents {
-					agentNames = append(agentNames, name)
-				}
-				fmt.Printf("    Agents: %d (%s)\n", len(scenario.Agents), strings.Join(agentNames, ", "))
-			}
-			if len(scenario.Goals) > 0 {
-				fmt.Printf("    Goals: %d\n", len(scenario.Goals))
-			}
-			if len(scenario.Basics.Tags) > 0 {
-				fmt.Printf("    Tags: %s\n", strings.Join(scenario.Basics.Tags, ", "))
+		if scenario.Basics != nil {
+			item.Name = scenario.Basics.Name
+			item.Description = scenario.Basics.Description
+			item.Tags = scenario.Basics.Tags
+		}
+		for name := range scenario.Agents {
+			item.Agents = append(item.Agents, name)
+		}
+		sort.Strings(item.Agents)
+		item.Goals = len(scenario.Goals)
+		list = append(list, item)
+	}
+
+	if asJSON {
+		printJSON(list)
+		return
+	}
+
+	if len(list) == 0 {
+		fmt.Println("No scenario definitions found.")
+		return
+	}
+
+	fmt.Printf("Scenarios in %s:\n\n", scenariosDir)
+
+	for _, item := range list {
+		if item.Error != "" {
+			fmt.Printf("  ❌ %s.toml (%s)\n", item.File, item.Error)
+			continue
+		}
+		if item.Name == "" {
+			fmt.Printf("  • %s (incomplete)\n", item.File)
+			continue
+		}
+
+		fmt.Printf("  • %s\n", item.File)
+		fmt.Printf("    Name: %s\n", item.Name)
+		if item.Description != "" {
+			// Truncate description if too long
+			desc := item.Description
+			if len(desc) > 60 {
+				desc = desc[:57] + "..."
 			}
-		} else {
-			fmt.Printf("  • %s (incomplete)\n", nameDisplay)
+			fmt.Printf("    Description: %s\n", desc)
+		}
+		if len(item.Agents) > 0 {
+			fmt.Printf("    Agents: %d (%s)\n", len(item.Agents), strings.Join(item.Agents, ", "))
+		}
+		if item.Goals > 0 {
+			fmt.Printf("    Goals: %d\n", item.Goals)
+		}
+		if len(item.Tags) > 0 {
+			fmt.Printf("    Tags: %s\n", strings.Join(item.Tags, ", "))
 		}
 	}
 }
 
+func validateScenario(cmd *cobra.Command, args []string) {
+	scenarioName := args[0]
+	if !strings.HasSuffix(scenarioName, ".toml") {
+		scenarioName = scenarioName + ".toml"
+	}
+	scenarioPath := path.Join(configDir, "scenarios", scenarioName)
+	scenario, err := scenarios.LoadScenarioFromFile(scenarioPath)
+	if err != nil {
+		reportErrorAndDieP(scenarioPath, err)
+	}
+
+	sim := simulations.NewSimulation(scenario, configDir)
+	if err := sim.Validate(); err != nil {
+		problems := []error{err}
+		if joined, ok := err.(interface{ Unwrap() []error }); ok {
+			problems = joined.Unwrap()
+		}
+		fmt.Fprintln(os.Stderr, errorStyle.Render(fmt.Sprintf("%s: %d problem(s)", scenarioPath, len(problems))))
+		for _, problem := range problems {
+			fmt.Fprintf(os.Stderr, "  - %s\n", problem)
+		}
+		os.Exit(1)
+	}
+	reportSuccess(fmt.Sprintf("✅ %s is valid", strings.TrimSuffix(scenarioName, ".toml")))
+}
+
 func runScenario(cmd *cobra.Command, args []string) {
 	// Ensure ONNX environment is cleaned up when simulation ends
 	defer memory.DestroyONNXEnvironment()
+	applyOutputMode(cmd)
 
 	scenarioName := args[0]
 	if !strings.HasSuffix(scenarioName, ".toml") {
 		scenarioName = scenarioName + ".toml"
 	}
 
-	// Load scenario
+	// Load scenario, keeping the raw definition for the run manifest
 	scenarioPath := path.Join(configDir, "scenarios", scenarioName)
-	scenario, err := scenarios.LoadScenarioFromFile(scenarioPath)
+	scenarioData, err := os.ReadFile(scenarioPath)
+	if err != nil {
+		reportErrorAndDieP(scenarioPath, err)
+	}
+	scenario, err := scenarios.LoadScenario(scenarioData)
 	if err != nil {
 		reportErrorAndDieP(scenarioPath, err)
 	}
 
 	// Create simulation
 	sim := simulations.NewSimulation(scenario, configDir)
+	if runChaos != "" {
+		chaos, err := simulations.ParseChaosSpec(runChaos)
+		if err != nil {
+			reportErrorAndDie(err)
+		}
+		sim.Chaos = chaos
+	}
+	sim.Stream = runStream
+	if runLive {
+		sim.Echo = os.Stdout
+	}
+	sim.CiteMemories = runCiteMemories
+	sim.ToolTranscript = runToolTranscript
+	sim.ChronicleChecksums = runChecksums
+	if runReasoning != "" {
+		transparency := &scenarios.TransparencyConfig{Reasoning: runReasoning}
+		if err := transparency.Validate(); err != nil {
+			reportErrorAndDie(err)
+		}
+		sim.World.SetReasoningShared(transparency.Reasoning == scenarios.ReasoningShared)
+	}
+	speed, err := simulations.ParseSpeedProfile(runSpeed)
+	if err != nil {
+		reportErrorAndDie(err)
+	}
+	sim.Speed = speed
+	if runDryRunScript != "" {
+		script, err := simulations.LoadMockScript(runDryRunScript)
+		if err != nil {
+			reportErrorAndDieP(runDryRunScript, err)
+		}
+		sim.DryRun = script
+	} else if runDryRun {
+		sim.DryRun = &simulations.MockScript{}
+	}
+	if runReplay != "" {
+		_, turns, err := chronicle.ReadFile(runReplay)
+		if err != nil {
+			reportErrorAndDieP(runReplay, err)
+		}
+		replay, err := simulations.NewReplay(turns)
+		if err != nil {
+			reportErrorAndDieP(runReplay, err)
+		}
+		sim.Replay = replay
+	}
+
+	sim.ScenarioFile = scenarioName
+	sim.ScenarioSource = string(scenarioData)
+
+	executeSimulation(sim, scenario, scenarioName, scenarioData, nil)
+}
+
+func resumeScenario(cmd *cobra.Command, args []string) {
+	// Ensure ONNX environment is cleaned up when simulation ends
+	defer memory.DestroyONNXEnvironment()
+	applyOutputMode(cmd)
+
+	checkpointPath := args[0]
+	checkpoint, err := simulations.LoadCheckpoint(checkpointPath)
+	if err != nil {
+		reportErrorAndDieP(checkpointPath, err)
+	}
+	scenario, err := scenarios.LoadScenario([]byte(checkpoint.Scenario))
+	if err != nil {
+		reportErrorAndDieP(checkpointPath, err)
+	}
+
+	sim := simulations.NewSimulation(scenario, configDir)
+	sim.Stream = runStream
+	if runLive {
+		sim.Echo = os.Stdout
+	}
+	sim.ToolTranscript = runToolTranscript
+	speed, err := simulations.ParseSpeedProfile(checkpoint.Speed)
+	if err != nil {
+		reportErrorAndDie(err)
+	}
+	sim.Speed = speed
+	sim.DryRun = checkpoint.DryRun
+
+	executeSimulation(sim, scenario, checkpoint.ScenarioFile, []byte(checkpoint.Scenario), checkpoint)
+}
+
+// executeSimulation initializes and runs a simulation, continuing from the
+// checkpoint when there is one, and records the run manifest.
+func executeSimulation(sim *simulations.Simulation, scenario *scenarios.Scenario, scenarioName string, scenarioData []byte, checkpoint *simulations.Checkpoint) {
+	// Seed documents ingested with 'wonda memory ingest'
+	knowledge, err := memory.LoadKnowledge(configDir, strings.TrimSuffix(scenarioName, ".toml"))
+	if err != nil {
+		reportErrorAndDie(err)
+	}
+	sim.Knowledge = knowledge
+
+	// Claim the dashboard's address before the slow setup, so a busy port fails fast
+	var web *dashboard.Server
+	var stopDashboard func()
+	if runWeb != "" {
+		web = dashboard.New()
+		stopDashboard, err = serveDashboard(web, runWeb)
+		if err != nil {
+			reportErrorAndDieP("Failed to start dashboard", err)
+		}
+	}
 
 	// Initialize simulation (load characters, create agents)
 	slog.Info("initializing simulation", "id", sim.ID.String())
@@ -234,10 +473,62 @@ func runScenario(cmd *cobra.Command, args []string) {
 	if err := sim.Initialize(ctx); err != nil {
 		reportErrorAndDieS(fmt.Sprintf("Failed to initialize simulation: %v", err))
 	}
+	if checkpoint != nil {
+		if err := sim.Resume(checkpoint); err != nil {
+			sim.Close()
+			reportErrorAndDieS(fmt.Sprintf("Failed to resume simulation: %v", err))
+		}
+	}
+
+	if web != nil {
+		web.Watch(sim)
+	}
+	if runPlain {
+		watchProgress(sim, os.Stdout, runProgressInterval)
+	}
 
 	// Start simulation
-	fmt.Println()
-	if err := sim.Start(ctx); err != nil {
+	if !runPlain && !runQuiet {
+		fmt.Println()
+	}
+	startTime := time.Now()
+	err = sim.Start(ctx)
+	sim.Close()
+	if web != nil {
+		end := dashboard.Message{Type: dashboard.MessageEnd}
+		if err != nil {
+			end.Text = err.Error()
+		}
+		web.Publish(end)
+		stopDashboard()
+	}
+
+	// Record the run manifest whether or not the run succeeded
+	manifest := runs.Manifest{
+		SimulationID: sim.ID.String(),
+		ScenarioFile: scenarioName,
+		ScenarioName: scenario.Basics.Name,
+		StartTime:    startTime,
+		Chronicle:    sim.ChroniclePath(),
+		Outcomes:     sim.OutcomesPath(),
+		Scenario:     string(scenarioData),
+	}
+	if saveErr := runs.Save(configDir, manifest); saveErr != nil {
+		reportWarning(fmt.Sprintf("Failed to save run manifest: %v", saveErr))
+	}
+	signRunArtifacts(manifest)
+
+	if runPlain || runQuiet {
+		printRunResult(sim, os.Stdout)
+	}
+	if err != nil {
 		reportErrorAndDieS(fmt.Sprintf("Simulation error: %v", err))
 	}
+
+	if sim.Replay != nil {
+		if turn := sim.Replay.FirstDivergence(); turn != 0 {
+			reportErrorAndDieS(fmt.Sprintf("Replay diverged from %s at turn %d", runReplay, turn))
+		}
+		reportSuccess(fmt.Sprintf("Replay matched all %d turns of %s", sim.Replay.Turns(), runReplay))
+	}
 }
@@ -0,0 +1,145 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/poiesic/wonda/internal/batch"
+	"github.com/poiesic/wonda/internal/memory"
+	"github.com/poiesic/wonda/internal/simulations"
+	"github.com/spf13/cobra"
+)
+
+var batchScenarioCommand = &cobra.Command{
+	Use:     "batch <scenario-name>",
+	Aliases: []string{"b"},
+	Short:   "Run a scenario many times and aggregate the results",
+	Long: `Run a scenario several times, a few at once, and report how often the runs
+completed every goal (the consensus rate), how many turns that took, and what
+they cost.
+
+A sweep file varies the runs: each variant is run --runs times.
+
+  [[variants]]
+  name = "sonnet-cool"
+  model = "claude-sonnet"       # every agent, and the scenario's default model
+  temperature = 0.2             # every model the run uses
+
+  [[variants]]
+  name = "skeptical-alice"
+  characters = { Alice = "alice-skeptic" }
+
+Each run writes its chronicle to its own directory under --output, next to
+results.json (every run and each variant's summary), results.csv (a row per
+variant) and runs.csv (a row per run). Interrupting the batch cancels the runs
+in progress and writes the results so far.`,
+	Args: cobra.ExactArgs(1),
+	Run:  batchScenario,
+}
+
+var batchRuns int
+var batchParallel int
+var batchSweep string
+var batchOutput string
+
+func init() {
+	batchScenarioCommand.Flags().IntVar(&batchRuns, "runs", 5, "Runs of each variant")
+	batchScenarioCommand.Flags().IntVar(&batchParallel, "parallel", 2, "Most runs played at once")
+	batchScenarioCommand.Flags().StringVar(&batchSweep, "sweep", "", "TOML file of variants to run (default: the scenario as it is)")
+	batchScenarioCommand.Flags().StringVar(&batchOutput, "output", "", "Directory for the runs and results (default: batch-<scenario>-<time>)")
+	batchScenarioCommand.Flags().BoolVar(&runDryRun, "dry-run", false, "Answer every LLM request with deterministic canned responses instead of calling providers: no API keys, no cost")
+	batchScenarioCommand.Flags().StringVar(&runSpeed, "speed", simulations.SpeedBalanced, "Speed profile trading fidelity for speed: "+strings.Join(simulations.SpeedProfileNames, ", "))
+}
+
+func batchScenario(cmd *cobra.Command, args []string) {
+	// Ensure ONNX environment is cleaned up when the batch ends
+	defer memory.DestroyONNXEnvironment()
+
+	scenarioName := args[0]
+	if !strings.HasSuffix(scenarioName, ".toml") {
+		scenarioName = scenarioName + ".toml"
+	}
+	if batchRuns < 1 {
+		reportErrorAndDieS(fmt.Sprintf("--runs must be at least 1 (got %d)", batchRuns))
+	}
+	if batchParallel < 1 {
+		reportErrorAndDieS(fmt.Sprintf("--parallel must be at least 1 (got %d)", batchParallel))
+	}
+
+	b := &batch.Batch{
+		ConfigDir:    configDir,
+		ScenarioFile: scenarioName,
+		Runs:         batchRuns,
+		Parallel:     batchParallel,
+		OutputDir:    batchOutput,
+		OnRunEnd:     signRunArtifacts,
+	}
+	if batchSweep != "" {
+		sweep, err := batch.LoadSweep(batchSweep)
+		if err != nil {
+			reportErrorAndDieP(batchSweep, err)
+		}
+		b.Sweep = sweep
+	}
+	speed, err := simulations.ParseSpeedProfile(runSpeed)
+	if err != nil {
+		reportErrorAndDie(err)
+	}
+	b.Speed = speed
+	if runDryRun {
+		b.DryRun = &simulations.MockScript{}
+	}
+	if b.OutputDir == "" {
+		b.OutputDir = fmt.Sprintf("batch-%s-%s", strings.TrimSuffix(scenarioName, ".toml"), time.Now().Format("20060102-150405"))
+	}
+	if err := os.MkdirAll(b.OutputDir, 0755); err != nil {
+		reportErrorAndDieP(b.OutputDir, err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	report, err := b.Run(ctx)
+	if err != nil {
+		reportErrorAndDieS(fmt.Sprintf("Batch failed: %v", err))
+	}
+
+	writeBatchFile(filepath.Join(b.OutputDir, "results.json"), report.WriteJSON)
+	writeBatchFile(filepath.Join(b.OutputDir, "results.csv"), report.WriteSummaryCSV)
+	writeBatchFile(filepath.Join(b.OutputDir, "runs.csv"), report.WriteRunsCSV)
+
+	fmt.Println()
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VARIANT\tRUNS\tFAILED\tCONSENSUS\tTURNS\tMEAN COST\tTOTAL COST")
+	for _, summary := range report.Variants {
+		turns := "-"
+		if summary.MeanTurnsToCompletion > 0 {
+			turns = fmt.Sprintf("%.1f", summary.MeanTurnsToCompletion)
+		}
+		fmt.Fprintf(w, "%s\t%d\t%d\t%.0f%%\t%s\t%.4f\t%.4f\n", summary.Variant, summary.Runs, summary.Failed,
+			summary.ConsensusRate*100, turns, summary.MeanCost, summary.TotalCost)
+	}
+	w.Flush()
+	fmt.Println()
+	reportSuccess(fmt.Sprintf("✅ Results written to %s", b.OutputDir))
+}
+
+// writeBatchFile writes one of a batch's results files, warning if it can't.
+func writeBatchFile(path string, write func(w io.Writer) error) {
+	file, err := os.Create(path)
+	if err != nil {
+		reportWarning(fmt.Sprintf("Failed to write %s: %v", path, err))
+		return
+	}
+	defer file.Close()
+	if err := write(file); err != nil {
+		reportWarning(fmt.Sprintf("Failed to write %s: %v", path, err))
+	}
+}
@@ -0,0 +1,110 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/poiesic/wonda/internal/runs"
+	"github.com/poiesic/wonda/internal/scenarios"
+	"github.com/spf13/cobra"
+)
+
+var diffScenarioCommand = &cobra.Command{
+	Use:     "diff <scenario-name>",
+	Aliases: []string{"d"},
+	Short:   "Show changes since the scenario was last run",
+	Long:    "Compare a scenario file with the version used in its most recent recorded run, setting by setting",
+	Args:    cobra.ExactArgs(1),
+	Run:     diffScenario,
+}
+
+// diffSectionOrder lists the sections shown first, in order; others follow alphabetically.
+var diffSectionOrder = []string{"scenario", "agents", "initial_state", "goals"}
+
+func diffScenario(cmd *cobra.Command, args []string) {
+	scenarioName := args[0]
+	if !strings.HasSuffix(scenarioName, ".toml") {
+		scenarioName = scenarioName + ".toml"
+	}
+
+	scenarioPath := path.Join(configDir, "scenarios", scenarioName)
+	current, err := os.ReadFile(scenarioPath)
+	if err != nil {
+		reportErrorAndDieP(scenarioPath, err)
+	}
+
+	manifest, err := runs.Latest(configDir, scenarioName)
+	if err != nil {
+		reportErrorAndDieP("Failed to read run manifests", err)
+	}
+	if manifest == nil {
+		reportErrorAndDieS(fmt.Sprintf("No recorded runs of %s", scenarioName))
+	}
+
+	changes, err := scenarios.Diff([]byte(manifest.Scenario), current)
+	if err != nil {
+		reportErrorAndDie(err)
+	}
+
+	fmt.Printf("Comparing %s with run %s (%s)\n", scenarioName, manifest.SimulationID, manifest.StartTime.Local().Format("2006-01-02 15:04"))
+	if manifest.Chronicle != "" {
+		fmt.Printf("Chronicle: %s\n", manifest.Chronicle)
+	}
+	fmt.Println()
+
+	if len(changes) == 0 {
+		reportSuccess("No changes since the last run")
+		return
+	}
+
+	// Group changes by section
+	bySection := make(map[string][]scenarios.Change)
+	var extraSections []string
+	for _, change := range changes {
+		section := change.Section()
+		if _, seen := bySection[section]; !seen && !contains(diffSectionOrder, section) {
+			extraSections = append(extraSections, section)
+		}
+		bySection[section] = append(bySection[section], change)
+	}
+
+	for _, section := range append(append([]string{}, diffSectionOrder...), extraSections...) {
+		sectionChanges := bySection[section]
+		if len(sectionChanges) == 0 {
+			continue
+		}
+		fmt.Printf("[%s]\n", section)
+		for _, change := range sectionChanges {
+			switch change.Kind {
+			case scenarios.ChangeAdded:
+				fmt.Println(successStyle.Render(fmt.Sprintf("  + %s = %s", change.Path, formatDiffValue(change.New))))
+			case scenarios.ChangeRemoved:
+				fmt.Println(errorStyle.Render(fmt.Sprintf("  - %s = %s", change.Path, formatDiffValue(change.Old))))
+			default:
+				fmt.Println(warnStyle.Render(fmt.Sprintf("  ~ %s: %s → %s", change.Path, formatDiffValue(change.Old), formatDiffValue(change.New))))
+			}
+		}
+		fmt.Println()
+	}
+	fmt.Printf("%d change(s)\n", len(changes))
+}
+
+// formatDiffValue renders a TOML value for diff output.
+func formatDiffValue(value interface{}) string {
+	if s, ok := value.(string); ok {
+		return fmt.Sprintf("%q", s)
+	}
+	return fmt.Sprintf("%v", value)
+}
+
+// contains reports whether items includes item.
+func contains(items []string, item string) bool {
+	for _, candidate := range items {
+		if candidate == item {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,104 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"path"
+	"syscall"
+	"time"
+
+	"github.com/poiesic/wonda/internal/api"
+	"github.com/poiesic/wonda/internal/memory"
+	"github.com/spf13/cobra"
+)
+
+var serveCommand = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve an HTTP API for starting, following and canceling simulations",
+	Long: `Serve a REST API so other services can run wonda without the CLI:
+
+  POST   /simulations                 start a scenario: {"scenario": "dinner", "dry_run": false, "speed": "balanced", "stream": false}
+  GET    /simulations                 list runs
+  GET    /simulations/{id}            a run's status
+  GET    /simulations/{id}/chronicle  the chronicle as JSON lines, followed until the run ends (?follow=false for what is written so far)
+  DELETE /simulations/{id}            cancel a run
+
+Scenarios are run from the config directory, and write their chronicles,
+outcomes and run manifests as 'scenarios run' does. Stopping the server
+cancels the runs in progress.
+
+With a users file (--users, or <config-dir>/users.toml if there is one),
+requests must send a user's API key or OIDC ID token as a bearer token. Each
+user's scenarios, characters, providers and usage live in their own config
+directory, <config-dir>/users/<name> (set one up with
+'wonda --config-dir <config-dir>/users/<name> init'), and their runs are
+refused once they have spent their budget.`,
+	Args: cobra.NoArgs,
+	Run:  serve,
+}
+
+var serveAddr string
+var serveMaxRuns int
+var serveUsers string
+
+func init() {
+	rootCommand.AddCommand(serveCommand)
+
+	serveCommand.Flags().StringVar(&serveAddr, "addr", "127.0.0.1:8090", "Address to listen on")
+	serveCommand.Flags().IntVar(&serveMaxRuns, "max-runs", 1, "Most simulations run at once")
+	serveCommand.Flags().StringVar(&serveUsers, "users", "", "Users file requiring authentication (default <config-dir>/users.toml, if it exists)")
+}
+
+func serve(cmd *cobra.Command, args []string) {
+	// Ensure ONNX environment is cleaned up when the server stops
+	defer memory.DestroyONNXEnvironment()
+
+	if serveMaxRuns < 1 {
+		reportErrorAndDieS(fmt.Sprintf("--max-runs must be at least 1 (got %d)", serveMaxRuns))
+	}
+
+	runner := api.New(configDir, serveMaxRuns)
+	runner.OnRunEnd = signRunArtifactsIn
+
+	usersPath := serveUsers
+	if usersPath == "" {
+		candidate := path.Join(configDir, "users.toml")
+		if _, err := os.Stat(candidate); err == nil {
+			usersPath = candidate
+		}
+	}
+	if usersPath != "" {
+		users, err := api.LoadUsers(usersPath)
+		if err != nil {
+			reportErrorAndDieP(usersPath, err)
+		}
+		runner.SetUsers(users)
+		slog.Info("API server requires authentication", "users", len(users.Users), "oidc", users.OIDC != nil)
+	} else {
+		slog.Info("API server has no users file, so anyone who can reach it can run scenarios")
+	}
+	server := &http.Server{Addr: serveAddr, Handler: runner.Handler()}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		slog.Info("stopping API server")
+		runner.Close()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		server.Shutdown(shutdownCtx)
+	}()
+
+	slog.Info("API server listening", "addr", serveAddr, "max_runs", serveMaxRuns)
+	fmt.Printf("API server listening on http://%s/simulations\n", serveAddr)
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		reportErrorAndDie(err)
+	}
+	runner.Close()
+}
@@ -2,12 +2,14 @@ package cli
 
 import (
 	"bufio"
+	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/cobra"
 )
 
 // Colors
@@ -43,6 +45,37 @@ func reportSuccess(msg string) {
 	fmt.Fprintln(os.Stdout, successStyle.Render(msg))
 }
 
+// listFormat is the output format of list commands: text or json.
+var listFormat string
+
+// addListFormatFlag adds the --format flag to a list command.
+func addListFormatFlag(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&listFormat, "format", "text", "Output format: text or json")
+}
+
+// listAsJSON reports whether a list command should print JSON.
+// Unknown formats are fatal.
+func listAsJSON() bool {
+	switch listFormat {
+	case "text", "":
+		return false
+	case "json":
+		return true
+	default:
+		reportErrorAndDieS(fmt.Sprintf("Unknown format: %s (use 'text' or 'json')", listFormat))
+		return false
+	}
+}
+
+// printJSON writes a value to stdout as indented JSON.
+func printJSON(v interface{}) {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(v); err != nil {
+		reportErrorAndDieS(fmt.Sprintf("Failed to encode JSON: %v", err))
+	}
+}
+
 func askForConfirmation(msg, confirmation string) bool {
 	var response string
 	r := bufio.NewReader(os.Stdin)
@@ -3,6 +3,8 @@ package config
 import (
 	"fmt"
 	"os"
+	"regexp"
+	"slices"
 
 	"github.com/pelletier/go-toml/v2"
 )
@@ -15,6 +17,42 @@ type Embedding struct {
 	Model      string `toml:"model"`
 	Dimensions int    `toml:"dimensions"`
 	ModelURL   string `toml:"model_url,omitempty"` // Custom download URL (for onnx type)
+	Metric     string `toml:"metric"`              // Optional: "cosine" (default), "dot", or "euclidean"
+	Normalize  bool   `toml:"normalize"`           // Optional: L2-normalize vectors before storing and searching
+	// Optional: languages the model embeds well, as ISO 639-1 codes or "*" for any (default ["en"])
+	Languages []string `toml:"languages"`
+}
+
+// DefaultEmbeddingLanguages are the languages assumed for embeddings that don't list any.
+var DefaultEmbeddingLanguages = []string{"en"}
+
+var languagePattern = regexp.MustCompile(`^[a-z]{2,3}$`)
+
+// ValidateLanguage checks that a language is a lowercase ISO 639 code such as "en" or "es".
+func ValidateLanguage(language string) error {
+	if !languagePattern.MatchString(language) {
+		return fmt.Errorf("invalid language '%s' (use an ISO 639-1 code such as en, es, or ja)", language)
+	}
+	return nil
+}
+
+// SupportsLanguage reports whether the model embeds text in a language well.
+// An empty language is treated as English.
+func (e *Embedding) SupportsLanguage(language string) bool {
+	if language == "" {
+		language = "en"
+	}
+	languages := e.Languages
+	if len(languages) == 0 {
+		languages = DefaultEmbeddingLanguages
+	}
+	return slices.Contains(languages, "*") || slices.Contains(languages, language)
+}
+
+// CrossLingual reports whether the model handles more than one language,
+// so text in one language can retrieve text in another.
+func (e *Embedding) CrossLingual() bool {
+	return len(e.Languages) > 1 || slices.Contains(e.Languages, "*")
 }
 
 // Validate checks if the embedding configuration is valid.
@@ -28,6 +66,19 @@ func (e *Embedding) Validate() error {
 	if e.Dimensions <= 0 {
 		return fmt.Errorf("embedding '%s': dimensions must be positive", e.Name)
 	}
+	switch e.Metric {
+	case "", "cosine", "dot", "euclidean":
+	default:
+		return fmt.Errorf("embedding '%s': unknown metric '%s' (use cosine, dot, or euclidean)", e.Name, e.Metric)
+	}
+	for _, language := range e.Languages {
+		if language == "*" {
+			continue
+		}
+		if err := ValidateLanguage(language); err != nil {
+			return fmt.Errorf("embedding '%s': %w", e.Name, err)
+		}
+	}
 	// Common embedding dimensions (sanity check)
 	validDimensions := map[int]bool{
 		384:  true, // sentence-transformers/all-MiniLM-L6-v2
@@ -0,0 +1,40 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmbeddingLanguages(t *testing.T) {
+	t.Run("assumes English when no languages are listed", func(t *testing.T) {
+		embedding := &Embedding{Name: "local"}
+		assert.True(t, embedding.SupportsLanguage("en"))
+		assert.True(t, embedding.SupportsLanguage(""))
+		assert.False(t, embedding.SupportsLanguage("es"))
+		assert.False(t, embedding.CrossLingual())
+	})
+
+	t.Run("supports listed languages", func(t *testing.T) {
+		embedding := &Embedding{Name: "multi", Languages: []string{"en", "es", "de"}}
+		assert.True(t, embedding.SupportsLanguage("es"))
+		assert.False(t, embedding.SupportsLanguage("ja"))
+		assert.True(t, embedding.CrossLingual())
+	})
+
+	t.Run("supports any language with a wildcard", func(t *testing.T) {
+		embedding := &Embedding{Name: "multi", Languages: []string{"*"}}
+		assert.True(t, embedding.SupportsLanguage("ja"))
+		assert.True(t, embedding.CrossLingual())
+	})
+
+	t.Run("validates language codes", func(t *testing.T) {
+		embedding := &Embedding{Name: "multi", Provider: "local", Model: "e5", Dimensions: 768}
+		embedding.Languages = []string{"en", "*"}
+		require.NoError(t, embedding.Validate())
+
+		embedding.Languages = []string{"Spanish"}
+		assert.Error(t, embedding.Validate())
+	})
+}
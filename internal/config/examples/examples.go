@@ -0,0 +1,151 @@
+// Package examples bundles ready-to-run example scenarios, and the characters
+// they use, for installing into a config directory.
+package examples
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/poiesic/wonda/internal/scenarios"
+)
+
+// FS contains the example scenarios and characters embedded at build time.
+//
+//go:embed scenarios/*.toml characters/*.toml
+var FS embed.FS
+
+// Model is the model the example scenarios use: the example model 'wonda
+// init' creates.
+const Model = "example_model"
+
+// Example is a bundled scenario and the characters it uses.
+type Example struct {
+	Name        string   `json:"name"` // File name, without .toml
+	Title       string   `json:"title"`
+	Description string   `json:"description"`
+	Goals       []string `json:"goals"`      // Goal types, sorted
+	Characters  []string `json:"characters"` // Character names, sorted
+}
+
+// InstallOptions adjusts how examples are installed.
+type InstallOptions struct {
+	Model string // Optional: model the scenarios use instead of Model
+	Force bool   // Overwrite files that already exist
+}
+
+// InstallResult lists the files an install wrote and those it left alone
+// because they already existed.
+type InstallResult struct {
+	Written []string
+	Skipped []string
+}
+
+// List returns the bundled examples, sorted by name.
+func List() ([]Example, error) {
+	files, err := fs.Glob(FS, "scenarios/*.toml")
+	if err != nil {
+		return nil, err
+	}
+	examples := make([]Example, 0, len(files))
+	for _, file := range files {
+		example, err := load(strings.TrimSuffix(path.Base(file), ".toml"))
+		if err != nil {
+			return nil, err
+		}
+		examples = append(examples, *example)
+	}
+	return examples, nil
+}
+
+// load reads a bundled example by name.
+func load(name string) (*Example, error) {
+	data, err := FS.ReadFile("scenarios/" + name + ".toml")
+	if err != nil {
+		return nil, fmt.Errorf("example not found: %s", name)
+	}
+	scenario, err := scenarios.LoadScenario(data)
+	if err != nil {
+		return nil, fmt.Errorf("example %s is invalid: %w", name, err)
+	}
+
+	example := &Example{Name: name, Title: scenario.Basics.Name, Description: scenario.Basics.Description}
+	for _, goal := range scenario.Goals {
+		if !slices.Contains(example.Goals, goal.Type) {
+			example.Goals = append(example.Goals, goal.Type)
+		}
+	}
+	for _, agent := range scenario.Agents {
+		if !slices.Contains(example.Characters, agent.Character) {
+			example.Characters = append(example.Characters, agent.Character)
+		}
+	}
+	slices.Sort(example.Goals)
+	slices.Sort(example.Characters)
+	return example, nil
+}
+
+// Install writes the named examples, or all of them if none are named, into
+// a config directory as scenarios/<name>.toml, with the characters they use
+// as characters/<name>.toml. Files that already exist are skipped unless
+// forced, so installing again never loses changes made to them.
+func Install(configDir string, names []string, opts InstallOptions) (*InstallResult, error) {
+	var examples []Example
+	if len(names) == 0 {
+		all, err := List()
+		if err != nil {
+			return nil, err
+		}
+		examples = all
+	}
+	for _, name := range names {
+		example, err := load(name)
+		if err != nil {
+			return nil, err
+		}
+		examples = append(examples, *example)
+	}
+
+	// Files to write, from their bundled path to their installed one
+	var sources []string
+	for _, example := range examples {
+		sources = append(sources, "scenarios/"+example.Name+".toml")
+		for _, character := range example.Characters {
+			if source := "characters/" + character + ".toml"; !slices.Contains(sources, source) {
+				sources = append(sources, source)
+			}
+		}
+	}
+
+	result := &InstallResult{}
+	for _, source := range sources {
+		data, err := FS.ReadFile(source)
+		if err != nil {
+			return nil, fmt.Errorf("example file %s is missing: %w", source, err)
+		}
+		if opts.Model != "" && strings.HasPrefix(source, "scenarios/") {
+			data = bytes.ReplaceAll(data, []byte(fmt.Sprintf("model = %q", Model)), []byte(fmt.Sprintf("model = %q", opts.Model)))
+		}
+
+		target := path.Join(configDir, source)
+		if _, err := os.Stat(target); err == nil && !opts.Force {
+			result.Skipped = append(result.Skipped, target)
+			continue
+		} else if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+		if err := os.MkdirAll(path.Dir(target), 0755); err != nil {
+			return nil, err
+		}
+		if err := os.WriteFile(target, data, 0644); err != nil {
+			return nil, err
+		}
+		result.Written = append(result.Written, target)
+	}
+	return result, nil
+}
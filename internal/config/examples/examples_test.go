@@ -0,0 +1,67 @@
+package examples
+
+import (
+	"os"
+	"path"
+	"testing"
+
+	"github.com/poiesic/wonda/internal/scenarios"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestList(t *testing.T) {
+	examples, err := List()
+	require.NoError(t, err)
+	require.NotEmpty(t, examples)
+
+	for _, example := range examples {
+		assert.NotEmpty(t, example.Title, example.Name)
+		for _, character := range example.Characters {
+			data, err := FS.ReadFile("characters/" + character + ".toml")
+			require.NoError(t, err, "%s uses character %s", example.Name, character)
+			loaded, err := scenarios.LoadCharacter(data)
+			require.NoError(t, err, character)
+			assert.NoError(t, loaded.Validate(), character)
+		}
+	}
+}
+
+func TestInstall(t *testing.T) {
+	t.Run("installs a scenario and its characters", func(t *testing.T) {
+		configDir := t.TempDir()
+		result, err := Install(configDir, []string{"movie-night"}, InstallOptions{Model: "my-model"})
+		require.NoError(t, err)
+		assert.Len(t, result.Written, 5, "the scenario and its four characters")
+		assert.Empty(t, result.Skipped)
+
+		scenario, err := scenarios.LoadScenarioFromFile(path.Join(configDir, "scenarios", "movie-night.toml"))
+		require.NoError(t, err)
+		assert.Equal(t, "my-model", scenario.Basics.Defaults.Model)
+		assert.FileExists(t, path.Join(configDir, "characters", "mediator.toml"))
+	})
+
+	t.Run("keeps existing files unless forced", func(t *testing.T) {
+		configDir := t.TempDir()
+		edited := path.Join(configDir, "characters", "pragmatist.toml")
+		require.NoError(t, os.MkdirAll(path.Dir(edited), 0755))
+		require.NoError(t, os.WriteFile(edited, []byte("mine"), 0644))
+
+		result, err := Install(configDir, nil, InstallOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, []string{edited}, result.Skipped)
+		data, err := os.ReadFile(edited)
+		require.NoError(t, err)
+		assert.Equal(t, "mine", string(data))
+
+		result, err = Install(configDir, []string{"dinner-plans"}, InstallOptions{Force: true})
+		require.NoError(t, err)
+		assert.Contains(t, result.Written, edited)
+		assert.Empty(t, result.Skipped)
+	})
+
+	t.Run("refuses unknown examples", func(t *testing.T) {
+		_, err := Install(t.TempDir(), []string{"nope"}, InstallOptions{})
+		assert.ErrorContains(t, err, "example not found: nope")
+	})
+}
@@ -5,6 +5,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/pelletier/go-toml/v2"
 )
@@ -33,12 +34,151 @@ type ThinkingParserConfig struct {
 	FieldPath string `toml:"field_path,omitempty"`
 }
 
+// SamplingConfig holds sampling and guided decoding options for models served
+// by self-hosted vLLM or TGI providers.
+type SamplingConfig struct {
+	TopK   int `toml:"top_k,omitempty"`   // vLLM only: sample from the k most likely tokens
+	BestOf int `toml:"best_of,omitempty"` // vLLM only: generate this many candidates and return the best
+
+	// Constrains output to a grammar: EBNF for vLLM, a regular expression for TGI
+	GuidedGrammar string `toml:"guided_grammar,omitempty"`
+	// Constrains output to JSON matching the offered tool schemas, for open
+	// models the server can't parse native tool calls from
+	GuidedTools bool `toml:"guided_tools,omitempty"`
+}
+
+// Validate checks the sampling options are consistent.
+func (s *SamplingConfig) Validate() error {
+	if s.TopK < 0 {
+		return fmt.Errorf("top_k must not be negative")
+	}
+	if s.BestOf < 0 {
+		return fmt.Errorf("best_of must not be negative")
+	}
+	if s.GuidedGrammar != "" && s.GuidedTools {
+		return fmt.Errorf("guided_grammar and guided_tools can't both be set")
+	}
+	return nil
+}
+
+// Generation holds the standard generation parameters sent with a model's
+// requests. Unset fields leave the provider's defaults.
+type Generation struct {
+	Temperature      *float64 `toml:"temperature,omitempty"`       // Optional: sampling temperature, 0 to 2
+	TopP             *float64 `toml:"top_p,omitempty"`             // Optional: nucleus sampling probability mass, above 0 up to 1
+	MaxTokens        int      `toml:"max_tokens,omitempty"`        // Optional: most tokens to generate per response
+	FrequencyPenalty *float64 `toml:"frequency_penalty,omitempty"` // Optional: penalty for repeated tokens, -2 to 2 (OpenAI-compatible only)
+	Stop             []string `toml:"stop,omitempty"`              // Optional: sequences that end the response
+}
+
+// IsZero reports whether no parameter is set.
+func (g Generation) IsZero() bool {
+	return g.Temperature == nil && g.TopP == nil && g.MaxTokens == 0 && g.FrequencyPenalty == nil && len(g.Stop) == 0
+}
+
+// Merge returns the parameters with those set in override taking their place.
+func (g Generation) Merge(override Generation) Generation {
+	if override.Temperature != nil {
+		g.Temperature = override.Temperature
+	}
+	if override.TopP != nil {
+		g.TopP = override.TopP
+	}
+	if override.MaxTokens != 0 {
+		g.MaxTokens = override.MaxTokens
+	}
+	if override.FrequencyPenalty != nil {
+		g.FrequencyPenalty = override.FrequencyPenalty
+	}
+	if len(override.Stop) > 0 {
+		g.Stop = override.Stop
+	}
+	return g
+}
+
+// Validate checks the parameters are in range.
+func (g Generation) Validate() error {
+	if g.Temperature != nil && (*g.Temperature < 0 || *g.Temperature > 2) {
+		return fmt.Errorf("temperature must be between 0 and 2 (got %g)", *g.Temperature)
+	}
+	if g.TopP != nil && (*g.TopP <= 0 || *g.TopP > 1) {
+		return fmt.Errorf("top_p must be above 0 and at most 1 (got %g)", *g.TopP)
+	}
+	if g.MaxTokens < 0 {
+		return fmt.Errorf("max_tokens must not be negative")
+	}
+	if g.FrequencyPenalty != nil && (*g.FrequencyPenalty < -2 || *g.FrequencyPenalty > 2) {
+		return fmt.Errorf("frequency_penalty must be between -2 and 2 (got %g)", *g.FrequencyPenalty)
+	}
+	for _, stop := range g.Stop {
+		if stop == "" {
+			return fmt.Errorf("stop sequences must not be empty")
+		}
+	}
+	return nil
+}
+
+// LlamaServerConfig runs a local GGUF model with llama.cpp's llama-server, which
+// Wonda starts for the run, registers as the model's provider and shuts down afterwards.
+type LlamaServerConfig struct {
+	ModelPath      string   `toml:"model_path"`                // Path to the GGUF file; ~ expands to the home directory
+	Binary         string   `toml:"binary,omitempty"`          // Optional: llama-server executable (default: llama-server on the PATH)
+	ContextSize    int      `toml:"context_size,omitempty"`    // Optional: context window in tokens (default: the model's)
+	GPULayers      *int     `toml:"gpu_layers,omitempty"`      // Optional: layers to offload to the GPU (default: llama-server's)
+	Args           []string `toml:"args,omitempty"`            // Optional: extra command line arguments
+	StartupTimeout string   `toml:"startup_timeout,omitempty"` // Optional: how long to wait for the model to load (default "5m")
+}
+
+// DefaultLlamaServerStartupTimeout bounds how long a llama-server may take to load its model.
+const DefaultLlamaServerStartupTimeout = 5 * time.Minute
+
+// Validate checks the llama-server configuration.
+func (l *LlamaServerConfig) Validate() error {
+	if l.ModelPath == "" {
+		return fmt.Errorf("llama_server requires model_path")
+	}
+	if l.ContextSize < 0 {
+		return fmt.Errorf("llama_server context_size must not be negative")
+	}
+	if l.GPULayers != nil && *l.GPULayers < 0 {
+		return fmt.Errorf("llama_server gpu_layers must not be negative")
+	}
+	if l.StartupTimeout != "" {
+		if _, err := time.ParseDuration(l.StartupTimeout); err != nil {
+			return fmt.Errorf("invalid llama_server startup_timeout '%s': %w", l.StartupTimeout, err)
+		}
+	}
+	return nil
+}
+
+// Timeout returns how long to wait for the server to load its model.
+func (l *LlamaServerConfig) Timeout() time.Duration {
+	if timeout, err := time.ParseDuration(l.StartupTimeout); err == nil && timeout > 0 {
+		return timeout
+	}
+	return DefaultLlamaServerStartupTimeout
+}
+
 // Model represents a language model configuration.
 type Model struct {
 	Version        string                `toml:"version"`                   // Configuration version
 	Name           string                `toml:"name"`                      // API model identifier (e.g., "claude-3-5-sonnet-20241022")
 	Provider       string                `toml:"provider"`                  // Reference to provider name from providers.toml
 	ThinkingParser *ThinkingParserConfig `toml:"thinking_parser,omitempty"` // Optional: auto-detected if nil
+	InputCost      float64               `toml:"input_cost,omitempty"`      // Optional: price per million input tokens (for usage stats)
+	OutputCost     float64               `toml:"output_cost,omitempty"`     // Optional: price per million output tokens (for usage stats)
+	Sampling       *SamplingConfig       `toml:"sampling,omitempty"`        // Optional: vLLM/TGI sampling and guided decoding
+	LlamaServer    *LlamaServerConfig    `toml:"llama_server,omitempty"`    // Optional: run a local GGUF model instead of using a provider
+
+	// Optional: temperature, top_p, max_tokens, frequency_penalty and stop,
+	// sent with every request (default: the provider's)
+	Generation
+}
+
+// Cost returns the price of a request from the configured per-million-token prices.
+// Models without prices cost nothing.
+func (m *Model) Cost(inputTokens, outputTokens int) float64 {
+	return (float64(inputTokens)*m.InputCost + float64(outputTokens)*m.OutputCost) / 1_000_000
 }
 
 // NewModel creates an empty Model configuration.
@@ -106,6 +246,38 @@ func LoadModelsFromDir(dirPath string) (map[string]*Model, error) {
 	return models, nil
 }
 
+// SetThinkingParser returns a model configuration file with its
+// [thinking_parser] table replaced by parser, or added if it has none. The
+// rest of the file, comments included, is kept as it is.
+func SetThinkingParser(data []byte, parser *ThinkingParserConfig) ([]byte, error) {
+	var kept []string
+	inTable := false
+	for _, line := range strings.Split(string(data), "\n") {
+		header, _, _ := strings.Cut(strings.TrimSpace(line), "#")
+		if header = strings.TrimSpace(header); strings.HasPrefix(header, "[") {
+			inTable = header == "[thinking_parser]"
+		}
+		if !inTable {
+			kept = append(kept, line)
+		}
+	}
+
+	table, err := toml.Marshal(struct {
+		ThinkingParser *ThinkingParserConfig `toml:"thinking_parser"`
+	}{parser})
+	if err != nil {
+		return nil, err
+	}
+	updated := []byte(strings.TrimRight(strings.Join(kept, "\n"), "\n") + "\n\n" + string(table))
+
+	// A parser set some other way, such as an inline table, would now be defined twice
+	model := NewModel()
+	if err := toml.Unmarshal(updated, model); err != nil {
+		return nil, fmt.Errorf("failed to replace the thinking parser: %w", err)
+	}
+	return updated, nil
+}
+
 // autoDetectThinkingParser determines the appropriate thinking parser based on model name patterns.
 func autoDetectThinkingParser(modelName string) *ThinkingParserConfig {
 	lower := strings.ToLower(modelName)
@@ -155,14 +327,33 @@ func (m *Model) Validate() error {
 	if m.Name == "" {
 		return fmt.Errorf("model name is required")
 	}
-	if m.Provider == "" {
+	if m.Provider == "" && m.LlamaServer == nil {
 		return fmt.Errorf("model provider is required")
 	}
+	if m.LlamaServer != nil {
+		if m.Provider != "" {
+			return fmt.Errorf("model provider can't be set with llama_server, which provides the model itself")
+		}
+		if err := m.LlamaServer.Validate(); err != nil {
+			return err
+		}
+	}
+	if m.InputCost < 0 || m.OutputCost < 0 {
+		return fmt.Errorf("model costs must not be negative")
+	}
+	if err := m.Generation.Validate(); err != nil {
+		return fmt.Errorf("model %w", err)
+	}
 	if m.ThinkingParser != nil {
 		if err := m.ThinkingParser.Validate(); err != nil {
 			return fmt.Errorf("invalid thinking parser config: %w", err)
 		}
 	}
+	if m.Sampling != nil {
+		if err := m.Sampling.Validate(); err != nil {
+			return fmt.Errorf("invalid sampling config: %w", err)
+		}
+	}
 	return nil
 }
 
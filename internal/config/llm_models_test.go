@@ -172,6 +172,16 @@ func TestModelValidate(t *testing.T) {
 		assert.Contains(t, err.Error(), "invalid thinking parser config")
 	})
 
+	t.Run("validates sampling config", func(t *testing.T) {
+		model := &Model{
+			Name:     "test-model",
+			Provider: "test-provider",
+			Sampling: &SamplingConfig{GuidedGrammar: "root ::= \"yes\"", GuidedTools: true},
+		}
+		err := model.Validate()
+		assert.ErrorContains(t, err, "invalid sampling config")
+	})
+
 	t.Run("allows nil thinking parser", func(t *testing.T) {
 		model := &Model{
 			Name:     "test-model",
@@ -182,6 +192,53 @@ func TestModelValidate(t *testing.T) {
 	})
 }
 
+func TestGeneration(t *testing.T) {
+	t.Run("loads flat from the model file", func(t *testing.T) {
+		model, err := LoadModel([]byte(`
+version = "1.0.0"
+name = "gpt-4"
+provider = "openai"
+temperature = 0.7
+top_p = 0.9
+max_tokens = 512
+frequency_penalty = 0.5
+stop = ["END"]
+`))
+		require.NoError(t, err)
+		assert.InDelta(t, 0.7, *model.Temperature, 1e-9)
+		assert.InDelta(t, 0.9, *model.TopP, 1e-9)
+		assert.Equal(t, 512, model.MaxTokens)
+		assert.InDelta(t, 0.5, *model.FrequencyPenalty, 1e-9)
+		assert.Equal(t, []string{"END"}, model.Stop)
+	})
+
+	t.Run("merges overrides over the set parameters", func(t *testing.T) {
+		temperature, topP, override := 0.7, 0.9, 0.0
+		base := Generation{Temperature: &temperature, TopP: &topP, MaxTokens: 512}
+		merged := base.Merge(Generation{Temperature: &override, Stop: []string{"END"}})
+		assert.Equal(t, 0.0, *merged.Temperature)
+		assert.Equal(t, 0.9, *merged.TopP)
+		assert.Equal(t, 512, merged.MaxTokens)
+		assert.Equal(t, []string{"END"}, merged.Stop)
+		assert.Equal(t, 0.7, *base.Temperature, "the base is left alone")
+		assert.True(t, Generation{}.IsZero())
+		assert.False(t, merged.IsZero())
+	})
+
+	high, low := 2.5, -3.0
+	for name, gen := range map[string]Generation{
+		"temperature":       {Temperature: &high},
+		"top_p":             {TopP: &high},
+		"max_tokens":        {MaxTokens: -1},
+		"frequency_penalty": {FrequencyPenalty: &low},
+		"stop":              {Stop: []string{""}},
+	} {
+		t.Run("rejects out of range "+name, func(t *testing.T) {
+			assert.ErrorContains(t, gen.Validate(), name)
+		})
+	}
+}
+
 func TestThinkingParserConfigValidate(t *testing.T) {
 	t.Run("validates none type", func(t *testing.T) {
 		config := &ThinkingParserConfig{
@@ -342,3 +399,45 @@ func TestAutoDetectThinkingParser(t *testing.T) {
 		})
 	}
 }
+
+func TestSetThinkingParser(t *testing.T) {
+	parser := &ThinkingParserConfig{Type: ThinkingParserInBand, StartDelimiter: "<think>", EndDelimiter: "</think>"}
+
+	t.Run("replaces the table and keeps the rest", func(t *testing.T) {
+		data := []byte(`# Local reasoning model
+version = "1.0.0"
+name = "qwq"
+provider = "local"
+
+[thinking_parser] # guessed
+type = "out_of_band"
+field_path = "reasoning"
+
+[sampling]
+top_k = 40
+`)
+		updated, err := SetThinkingParser(data, parser)
+		require.NoError(t, err)
+		assert.Contains(t, string(updated), "# Local reasoning model")
+		assert.NotContains(t, string(updated), "field_path")
+
+		model, err := LoadModel(updated)
+		require.NoError(t, err)
+		assert.Equal(t, parser, model.ThinkingParser)
+		require.NotNil(t, model.Sampling)
+		assert.Equal(t, 40, model.Sampling.TopK)
+	})
+
+	t.Run("adds the table", func(t *testing.T) {
+		updated, err := SetThinkingParser([]byte("version = \"1.0.0\"\nname = \"m\"\nprovider = \"p\"\n"), parser)
+		require.NoError(t, err)
+		model, err := LoadModel(updated)
+		require.NoError(t, err)
+		assert.Equal(t, parser, model.ThinkingParser)
+	})
+
+	t.Run("rejects a parser it can't replace", func(t *testing.T) {
+		_, err := SetThinkingParser([]byte("version = \"1.0.0\"\nthinking_parser = { type = \"none\" }\n"), parser)
+		assert.Error(t, err)
+	})
+}
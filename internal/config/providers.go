@@ -5,6 +5,7 @@ import (
 	"os"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/pelletier/go-toml/v2"
 )
@@ -14,11 +15,101 @@ import (
 // alphanumeric characters, dashes, and underscores.
 var validProviderName = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9_-]*$`)
 
+// Provider types. An empty type is detected from the provider's name and URL.
+const (
+	ProviderTypeOpenAI    = "openai"
+	ProviderTypeAnthropic = "anthropic"
+	ProviderTypeVLLM      = "vllm" // Self-hosted vLLM server
+	ProviderTypeTGI       = "tgi"  // Self-hosted Text Generation Inference server
+)
+
 // Provider represents a single LLM provider configuration.
 type Provider struct {
 	Name    string  `toml:"-"`
+	Type    string  `toml:"type"`     // Optional: "openai", "anthropic", "vllm" or "tgi"; detected if empty
 	BaseURL string  `toml:"base_url"` // Base URL for the provider's API endpoint
 	APIKey  *string `toml:"api_key"`  // Optional: If nil, falls back to <PROVIDER_NAME>_API_KEY env var (uppercase, dashes/spaces → underscores)
+	// Optional: provider exposes an OpenAI-compatible /moderations endpoint for guardrails
+	Moderation bool `toml:"moderation"`
+
+	// Optional: retrying requests that fail with a network error or a transient status
+	MaxRetries    *int   `toml:"max_retries,omitempty"`     // Retries after the first attempt (default 3; 0 disables retrying)
+	RetryOnStatus []int  `toml:"retry_on_status,omitempty"` // HTTP statuses to retry (default 429, 500, 502, 503, 504)
+	Backoff       string `toml:"backoff,omitempty"`         // Delay before the first retry, doubled for each one after (default "1s")
+}
+
+// Retry defaults for providers that don't configure retrying.
+const (
+	DefaultMaxRetries = 3
+	DefaultBackoff    = time.Second
+)
+
+// DefaultRetryOnStatus are the statuses retried by default: rate limiting and
+// server errors that usually clear up on their own.
+var DefaultRetryOnStatus = []int{429, 500, 502, 503, 504}
+
+// RetryPolicy is how requests to a provider are retried after transient failures.
+type RetryPolicy struct {
+	MaxRetries    int           // Retries after the first attempt; 0 disables retrying
+	RetryOnStatus []int         // HTTP statuses worth retrying
+	Backoff       time.Duration // Delay before the first retry, doubled for each one after
+}
+
+// RetryPolicy returns the provider's retry settings, with defaults filled in.
+func (p *Provider) RetryPolicy() RetryPolicy {
+	policy := RetryPolicy{
+		MaxRetries:    DefaultMaxRetries,
+		RetryOnStatus: DefaultRetryOnStatus,
+		Backoff:       DefaultBackoff,
+	}
+	if p.MaxRetries != nil {
+		policy.MaxRetries = *p.MaxRetries
+	}
+	if len(p.RetryOnStatus) > 0 {
+		policy.RetryOnStatus = p.RetryOnStatus
+	}
+	if backoff, err := time.ParseDuration(p.Backoff); err == nil {
+		policy.Backoff = backoff
+	}
+	return policy
+}
+
+// validateRetry checks the provider's retry settings.
+func (p *Provider) validateRetry() error {
+	if p.MaxRetries != nil && *p.MaxRetries < 0 {
+		return fmt.Errorf("provider '%s': max_retries must not be negative (got %d)", p.Name, *p.MaxRetries)
+	}
+	for _, status := range p.RetryOnStatus {
+		if status < 400 || status > 599 {
+			return fmt.Errorf("provider '%s': retry_on_status must hold 4xx or 5xx statuses (got %d)", p.Name, status)
+		}
+	}
+	if p.Backoff != "" {
+		backoff, err := time.ParseDuration(p.Backoff)
+		if err != nil {
+			return fmt.Errorf("provider '%s': invalid backoff '%s': %w", p.Name, p.Backoff, err)
+		}
+		if backoff <= 0 {
+			return fmt.Errorf("provider '%s': backoff must be positive (got %s)", p.Name, p.Backoff)
+		}
+	}
+	return nil
+}
+
+// SelfHosted reports whether the provider is a vLLM or TGI server, which
+// accept sampling and guided decoding extensions on their OpenAI-compatible API.
+func (p *Provider) SelfHosted() bool {
+	return p.Type == ProviderTypeVLLM || p.Type == ProviderTypeTGI
+}
+
+// validateType checks the provider type is one Wonda knows how to talk to.
+func (p *Provider) validateType() error {
+	switch p.Type {
+	case "", ProviderTypeOpenAI, ProviderTypeAnthropic, ProviderTypeVLLM, ProviderTypeTGI:
+		return nil
+	default:
+		return fmt.Errorf("provider '%s': unknown type '%s' (must be openai, anthropic, vllm or tgi)", p.Name, p.Type)
+	}
 }
 
 // LoadFromEnvironment validates the provider name and loads the API key from
@@ -78,14 +169,16 @@ func (p *Provider) LoadFromEnvironment() error {
 //   - Must start with an alphabetic character (a-z, A-Z)
 //   - Can contain alphanumeric characters, dashes, and underscores
 type Providers struct {
-	Version   string               `toml:"version"` // Configuration version
-	Providers map[string]*Provider `toml:"providers"`
+	Version      string                  `toml:"version"` // Configuration version
+	Providers    map[string]*Provider    `toml:"providers"`
+	VectorStores map[string]*VectorStore `toml:"vector_stores"` // Optional: external databases memory stores can be kept in
 }
 
 // NewProviders creates an empty Providers configuration.
 func NewProviders() *Providers {
 	return &Providers{
-		Providers: make(map[string]*Provider),
+		Providers:    make(map[string]*Provider),
+		VectorStores: make(map[string]*VectorStore),
 	}
 }
 
@@ -106,6 +199,18 @@ func LoadProviders(data []byte) (*Providers, error) {
 		if err := provider.LoadFromEnvironment(); err != nil {
 			return nil, err
 		}
+		if err := provider.validateType(); err != nil {
+			return nil, err
+		}
+		if err := provider.validateRetry(); err != nil {
+			return nil, err
+		}
+	}
+	for name, store := range p.VectorStores {
+		store.Name = name
+		if err := store.Validate(); err != nil {
+			return nil, err
+		}
 	}
 	return p, nil
 }
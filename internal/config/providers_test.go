@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"testing"
+	"time"
 
 	"github.com/pelletier/go-toml/v2"
 	"github.com/stretchr/testify/assert"
@@ -562,3 +563,72 @@ base_url = "https://example.com"
 		assert.Equal(t, "test", providers.Providers["test"].Name)
 	})
 }
+
+func TestProviderRetryPolicy(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		provider := &Provider{Name: "openai"}
+		require.NoError(t, provider.validateRetry())
+
+		policy := provider.RetryPolicy()
+		assert.Equal(t, DefaultMaxRetries, policy.MaxRetries)
+		assert.Equal(t, DefaultRetryOnStatus, policy.RetryOnStatus)
+		assert.Equal(t, DefaultBackoff, policy.Backoff)
+	})
+
+	t.Run("configured", func(t *testing.T) {
+		var provider Provider
+		require.NoError(t, toml.Unmarshal([]byte(`
+max_retries = 0
+retry_on_status = [429]
+backoff = "250ms"
+`), &provider))
+		require.NoError(t, provider.validateRetry())
+
+		policy := provider.RetryPolicy()
+		assert.Equal(t, 0, policy.MaxRetries)
+		assert.Equal(t, []int{429}, policy.RetryOnStatus)
+		assert.Equal(t, 250*time.Millisecond, policy.Backoff)
+	})
+
+	t.Run("rejects invalid settings", func(t *testing.T) {
+		negative := -1
+		for _, provider := range []*Provider{
+			{Name: "p", MaxRetries: &negative},
+			{Name: "p", RetryOnStatus: []int{200}},
+			{Name: "p", Backoff: "soon"},
+			{Name: "p", Backoff: "0s"},
+		} {
+			assert.Error(t, provider.validateRetry())
+		}
+	})
+}
+
+func TestLoadVectorStores(t *testing.T) {
+	t.Setenv("SHARED_QDRANT_API_KEY", "from-env")
+	providers, err := LoadProviders([]byte(`
+version = "1.0.0"
+
+[vector_stores.shared-qdrant]
+type = "qdrant"
+url = "http://localhost:6333"
+
+[vector_stores.chroma]
+type = "chroma"
+url = "http://localhost:8000"
+tenant = "team"
+`))
+	require.NoError(t, err)
+	require.Len(t, providers.VectorStores, 2)
+	assert.Equal(t, "shared-qdrant", providers.VectorStores["shared-qdrant"].Name)
+	assert.Equal(t, "from-env", providers.VectorStores["shared-qdrant"].Key())
+	assert.Equal(t, "", providers.VectorStores["chroma"].Key())
+
+	for _, store := range []string{
+		`type = "pinecone"` + "\n" + `url = "http://localhost"`,
+		`type = "qdrant"`,
+		`type = "qdrant"` + "\n" + `url = "http://localhost"` + "\n" + `tenant = "team"`,
+	} {
+		_, err := LoadProviders([]byte("version = \"1.0.0\"\n\n[vector_stores.db]\n" + store + "\n"))
+		assert.Error(t, err, store)
+	}
+}
@@ -0,0 +1,61 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Vector store types
+const (
+	VectorStoreQdrant = "qdrant"
+	VectorStoreChroma = "chroma"
+)
+
+// VectorStore is an external vector database that memory stores can be kept
+// in instead of a file, for large campaigns or several users sharing a server.
+type VectorStore struct {
+	Name     string  `toml:"-"`
+	Type     string  `toml:"type"`     // "qdrant" or "chroma"
+	URL      string  `toml:"url"`      // Base URL of the database's HTTP API
+	APIKey   *string `toml:"api_key"`  // Optional: If nil, falls back to <NAME>_API_KEY env var, as for providers
+	Tenant   string  `toml:"tenant"`   // Optional: Chroma tenant (default "default_tenant")
+	Database string  `toml:"database"` // Optional: Chroma database (default "default_database")
+}
+
+// Validate checks the vector store's name, type and URL, and loads its API key
+// from the environment if the configuration doesn't set one.
+func (v *VectorStore) Validate() error {
+	if !validProviderName.MatchString(v.Name) {
+		return fmt.Errorf("invalid vector store name '%s': must start with alphabetic character and contain only alphanumeric, dash, or underscore characters", v.Name)
+	}
+	switch v.Type {
+	case VectorStoreQdrant, VectorStoreChroma:
+	case "":
+		return fmt.Errorf("vector store '%s': type is required (qdrant or chroma)", v.Name)
+	default:
+		return fmt.Errorf("vector store '%s': unknown type '%s' (must be qdrant or chroma)", v.Name, v.Type)
+	}
+	if v.URL == "" {
+		return fmt.Errorf("vector store '%s': url is required", v.Name)
+	}
+	if (v.Tenant != "" || v.Database != "") && v.Type != VectorStoreChroma {
+		return fmt.Errorf("vector store '%s': tenant and database only apply to chroma", v.Name)
+	}
+
+	if v.APIKey == nil {
+		envName := strings.ToUpper(strings.ReplaceAll(v.Name, "-", "_")) + "_API_KEY"
+		if value := os.Getenv(envName); value != "" {
+			v.APIKey = &value
+		}
+	}
+	return nil
+}
+
+// Key returns the vector store's API key, or "" if it has none.
+func (v *VectorStore) Key() string {
+	if v.APIKey == nil {
+		return ""
+	}
+	return *v.APIKey
+}
@@ -0,0 +1,291 @@
+// Package dashboard serves a live web view of a running simulation: a page
+// that follows the run over a WebSocket feed of its turns, dialogue,
+// proposals, votes and goal completions.
+package dashboard
+
+import (
+	"cmp"
+	"context"
+	_ "embed"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/poiesic/wonda/internal/chronicle"
+	"github.com/poiesic/wonda/internal/scenarios"
+	"github.com/poiesic/wonda/internal/simulations"
+)
+
+//go:embed dashboard.html
+var page []byte
+
+// feedBuffer is how many messages a slow viewer may fall behind before it is
+// dropped; its page reconnects and catches up from the history.
+const feedBuffer = 256
+
+// writeTimeout bounds how long a viewer may take to accept a message.
+const writeTimeout = 5 * time.Second
+
+// Message types on the feed.
+const (
+	MessageRun     = "run"     // The run started; Run describes it
+	MessageTurn    = "turn"    // A turn started
+	MessageEvent   = "event"   // An agent did something; Event is the chronicle event
+	MessagePartial = "partial" // An utterance is streaming in; Text is everything said so far
+	MessageGoal    = "goal"    // A goal was completed or failed
+	MessageEnd     = "end"     // The run ended; Text holds the error, if any
+)
+
+// Message is one update on the feed, sent to viewers as JSON.
+type Message struct {
+	Type       string                    `json:"type"`
+	Turn       int                       `json:"turn,omitempty"`
+	Run        *Run                      `json:"run,omitempty"`
+	Event      *chronicle.Event          `json:"event,omitempty"`
+	AgentName  string                    `json:"agent_name,omitempty"` // For partials
+	Text       string                    `json:"text,omitempty"`
+	Completion *chronicle.GoalCompletion `json:"completion,omitempty"`
+}
+
+// Run describes the simulation a dashboard follows.
+type Run struct {
+	SimulationID string    `json:"simulation_id"`
+	Scenario     string    `json:"scenario"`
+	Location     string    `json:"location"`
+	MaxTurns     int       `json:"max_turns"`
+	Agents       []string  `json:"agents"` // In turn order
+	Goals        []RunGoal `json:"goals"`
+
+	Characters map[string]chronicle.CharacterProfile `json:"characters,omitempty"` // Profiles by agent name; portrait files are served under /portraits/
+}
+
+// RunGoal is a goal of the run.
+type RunGoal struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// Server publishes a run's updates to every connected viewer. Viewers that
+// connect late are sent everything published so far first.
+type Server struct {
+	mu        sync.Mutex
+	history   [][]byte
+	feeds     map[chan []byte]struct{}
+	portraits map[string]string // Portrait files by agent name
+	closed    bool
+	viewers   sync.WaitGroup // Feeds still being written
+}
+
+// New creates a dashboard server.
+func New() *Server {
+	return &Server{
+		feeds:     make(map[chan []byte]struct{}),
+		portraits: make(map[string]string),
+	}
+}
+
+// Handler serves the dashboard page at /, the feed at /ws, and the agents'
+// portrait files at /portraits/<agent>.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write(page)
+	})
+	mux.HandleFunc("GET /ws", s.serveFeed)
+	mux.HandleFunc("GET /portraits/{agent}", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		path, ok := s.portraits[r.PathValue("agent")]
+		s.mu.Unlock()
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		http.ServeFile(w, r, path)
+	})
+	return mux
+}
+
+// Watch publishes a simulation's updates as it runs. Call it after the
+// simulation is initialized (and resumed) and before it starts.
+func (s *Server) Watch(sim *simulations.Simulation) {
+	run := &Run{
+		SimulationID: sim.ID.String(),
+		Scenario:     sim.Scenario.Basics.Name,
+		Location:     sim.Scenario.Basics.Location,
+		MaxTurns:     sim.MaxTurns(),
+		Agents:       slices.Clone(sim.TurnOrder),
+	}
+	for name, goal := range sim.Scenario.Goals {
+		run.Goals = append(run.Goals, RunGoal{Name: name, Description: goal.Description})
+	}
+	slices.SortFunc(run.Goals, func(a, b RunGoal) int {
+		return cmp.Compare(a.Name, b.Name)
+	})
+	for _, name := range run.Agents {
+		agent := sim.Agents[name]
+		if agent == nil || agent.Character == nil || agent.Character.Presentation == nil {
+			continue
+		}
+		p := agent.Character.Presentation
+		profile := chronicle.CharacterProfile{Pronouns: p.Pronouns, Age: p.Age, Portrait: p.Portrait, Bio: p.Bio}
+		if p.Portrait != "" && !scenarios.PortraitIsURL(p.Portrait) {
+			s.mu.Lock()
+			s.portraits[name] = p.Portrait
+			s.mu.Unlock()
+			profile.Portrait = "/portraits/" + url.PathEscape(name)
+		}
+		if run.Characters == nil {
+			run.Characters = make(map[string]chronicle.CharacterProfile)
+		}
+		run.Characters[name] = profile
+	}
+	s.Publish(Message{Type: MessageRun, Run: run})
+
+	sim.OnTurnStart(func(ctx context.Context, turn int) {
+		s.Publish(Message{Type: MessageTurn, Turn: turn})
+	})
+	sim.OnAgentAction(func(ctx context.Context, turn int, event chronicle.Event) {
+		s.Publish(Message{Type: MessageEvent, Turn: turn, Event: &event})
+	})
+	sim.OnPartialUtterance(func(ctx context.Context, turn int, agentName, text string) {
+		s.Publish(Message{Type: MessagePartial, Turn: turn, AgentName: agentName, Text: text})
+	})
+	sim.OnGoalComplete(func(ctx context.Context, turn int, completion chronicle.GoalCompletion) {
+		s.Publish(Message{Type: MessageGoal, Turn: turn, Completion: &completion})
+	})
+}
+
+// Publish sends a message to every viewer and keeps it for later ones. It
+// never blocks: viewers too far behind are dropped.
+func (s *Server) Publish(msg Message) {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		slog.Warn("failed to encode dashboard message", "type", msg.Type, "error", err)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return
+	}
+	// Partials are superseded by the event that completes them
+	if msg.Type != MessagePartial {
+		s.history = append(s.history, data)
+	}
+	for feed := range s.feeds {
+		select {
+		case feed <- data:
+		default:
+			slog.Warn("dashboard viewer fell behind, dropping it")
+			delete(s.feeds, feed)
+			close(feed)
+		}
+	}
+}
+
+// Close ends every viewer's feed, once they have been sent what was
+// published. Messages published afterwards are dropped.
+func (s *Server) Close() {
+	s.mu.Lock()
+	s.closed = true
+	for feed := range s.feeds {
+		close(feed)
+	}
+	clear(s.feeds)
+	s.mu.Unlock()
+	s.viewers.Wait()
+}
+
+// subscribe returns the messages published so far and a feed of the ones to
+// come, closed when the server closes or the viewer falls behind.
+func (s *Server) subscribe() ([][]byte, chan []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	history := slices.Clone(s.history)
+	feed := make(chan []byte, feedBuffer)
+	if s.closed {
+		close(feed)
+	} else {
+		s.feeds[feed] = struct{}{}
+	}
+	return history, feed
+}
+
+// unsubscribe stops sending to a feed that is still open.
+func (s *Server) unsubscribe(feed chan []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.feeds[feed]; ok {
+		delete(s.feeds, feed)
+		close(feed)
+	}
+}
+
+// serveFeed upgrades a request to a WebSocket and sends it the history and
+// then live messages until the feed ends or the viewer goes away.
+func (s *Server) serveFeed(w http.ResponseWriter, r *http.Request) {
+	conn, rw, err := upgrade(w, r)
+	if err != nil {
+		slog.Debug("dashboard handshake failed", "error", err)
+		return
+	}
+	defer conn.Close()
+
+	history, feed := s.subscribe()
+	defer s.unsubscribe(feed)
+	s.viewers.Add(1)
+	defer s.viewers.Done()
+
+	var writeMu sync.Mutex
+	send := func(opcode byte, payload []byte) error {
+		writeMu.Lock()
+		defer writeMu.Unlock()
+		conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+		return writeFrame(rw.Writer, opcode, payload)
+	}
+
+	// Answer pings and closes from the viewer
+	gone := make(chan struct{})
+	go func() {
+		defer close(gone)
+		for {
+			opcode, payload, err := readFrame(rw.Reader)
+			if err != nil {
+				return
+			}
+			switch opcode {
+			case opPing:
+				send(opPong, payload)
+			case opClose:
+				send(opClose, closeNormal)
+				return
+			}
+		}
+	}()
+
+	for _, data := range history {
+		if err := send(opText, data); err != nil {
+			return
+		}
+	}
+	for {
+		select {
+		case data, ok := <-feed:
+			if !ok {
+				send(opClose, closeNormal)
+				return
+			}
+			if err := send(opText, data); err != nil {
+				return
+			}
+		case <-gone:
+			return
+		}
+	}
+}
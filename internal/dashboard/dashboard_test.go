@@ -0,0 +1,124 @@
+package dashboard
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/poiesic/wonda/internal/chronicle"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAcceptKey(t *testing.T) {
+	// The example from RFC 6455
+	assert.Equal(t, "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=", acceptKey("dGhlIHNhbXBsZSBub25jZQ=="))
+}
+
+func TestFrames(t *testing.T) {
+	for _, size := range []int{0, 125, 126, 70000} {
+		t.Run(fmt.Sprint(size), func(t *testing.T) {
+			payload := []byte(strings.Repeat("x", size))
+			var buf strings.Builder
+			w := bufio.NewWriter(&buf)
+			require.NoError(t, writeFrame(w, opText, payload))
+
+			if size > maxClientFrame {
+				_, _, err := readFrame(bufio.NewReader(strings.NewReader(buf.String())))
+				assert.ErrorContains(t, err, "too large")
+				return
+			}
+			opcode, got, err := readFrame(bufio.NewReader(strings.NewReader(buf.String())))
+			require.NoError(t, err)
+			assert.Equal(t, byte(opText), opcode)
+			assert.Equal(t, payload, got)
+		})
+	}
+
+	t.Run("unmasks client frames", func(t *testing.T) {
+		mask := []byte{1, 2, 3, 4}
+		frame := []byte{0x80 | opPing, 0x80 | 2, mask[0], mask[1], mask[2], mask[3], 'h' ^ mask[0], 'i' ^ mask[1]}
+		opcode, payload, err := readFrame(bufio.NewReader(strings.NewReader(string(frame))))
+		require.NoError(t, err)
+		assert.Equal(t, byte(opPing), opcode)
+		assert.Equal(t, "hi", string(payload))
+	})
+}
+
+// dial opens a WebSocket to a test server's feed.
+func dial(t *testing.T, server *httptest.Server) *bufio.Reader {
+	conn, err := net.Dial("tcp", server.Listener.Addr().String())
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	fmt.Fprintf(conn, "GET /ws HTTP/1.1\r\nHost: test\r\nUpgrade: websocket\r\nConnection: keep-alive, Upgrade\r\nSec-WebSocket-Key: dGhlIHNhbXBsZSBub25jZQ==\r\nSec-WebSocket-Version: 13\r\n\r\n")
+	r := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(r, nil)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusSwitchingProtocols, resp.StatusCode)
+	assert.Equal(t, "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=", resp.Header.Get("Sec-WebSocket-Accept"))
+	return r
+}
+
+// next reads the next message from a feed.
+func next(t *testing.T, r *bufio.Reader) Message {
+	opcode, payload, err := readFrame(r)
+	require.NoError(t, err)
+	require.Equal(t, byte(opText), opcode)
+	var msg Message
+	require.NoError(t, json.Unmarshal(payload, &msg))
+	return msg
+}
+
+func TestServer(t *testing.T) {
+	web := New()
+	server := httptest.NewServer(web.Handler())
+	defer server.Close()
+
+	t.Run("serves the page", func(t *testing.T) {
+		resp, err := http.Get(server.URL)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "text/html; charset=utf-8", resp.Header.Get("Content-Type"))
+	})
+
+	t.Run("rejects plain requests to the feed", func(t *testing.T) {
+		resp, err := http.Get(server.URL + "/ws")
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	web.Publish(Message{Type: MessageRun, Run: &Run{Scenario: "Dinner", Agents: []string{"Alice", "Bob"}}})
+	web.Publish(Message{Type: MessageTurn, Turn: 1})
+	web.Publish(Message{Type: MessagePartial, Turn: 1, AgentName: "Alice", Text: "Let's"})
+
+	feed := dial(t, server)
+
+	t.Run("replays history without partials", func(t *testing.T) {
+		assert.Equal(t, "Dinner", next(t, feed).Run.Scenario)
+		assert.Equal(t, Message{Type: MessageTurn, Turn: 1}, next(t, feed))
+	})
+
+	t.Run("sends live messages", func(t *testing.T) {
+		web.Publish(Message{Type: MessageEvent, Turn: 1, Event: &chronicle.Event{AgentName: "Alice", Dialogue: "Let's eat."}})
+		msg := next(t, feed)
+		assert.Equal(t, MessageEvent, msg.Type)
+		assert.Equal(t, "Let's eat.", msg.Event.Dialogue)
+	})
+
+	t.Run("closes feeds when the run ends", func(t *testing.T) {
+		web.Publish(Message{Type: MessageEnd})
+		web.Close()
+		assert.Equal(t, MessageEnd, next(t, feed).Type)
+		opcode, _, err := readFrame(feed)
+		require.NoError(t, err)
+		assert.Equal(t, byte(opClose), opcode)
+	})
+}
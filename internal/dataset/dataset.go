@@ -0,0 +1,286 @@
+// Package dataset builds research datasets from simulation chronicles.
+// Each example pairs what an agent could see (recent conversation, persona)
+// with the action it took, suitable for fine-tuning or analysis.
+package dataset
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/poiesic/wonda/internal/chronicle"
+	"github.com/poiesic/wonda/internal/scenarios"
+)
+
+// Optional fields that can be selected for export.
+// The agent and the action taken are always included.
+const (
+	FieldScenario  = "scenario"
+	FieldSetting   = "setting"
+	FieldTurn      = "turn"
+	FieldPersona   = "persona"
+	FieldContext   = "context"
+	FieldReasoning = "reasoning"
+)
+
+// AllFields lists every optional field.
+var AllFields = []string{FieldScenario, FieldSetting, FieldTurn, FieldPersona, FieldContext, FieldReasoning}
+
+// DefaultFields are exported when no fields are selected.
+var DefaultFields = []string{FieldPersona, FieldContext}
+
+// Options controls how examples are built.
+type Options struct {
+	Fields      []string          // Optional fields to include (default DefaultFields)
+	ContextSize int               // Number of preceding messages in the context (default 10)
+	Personas    map[string]string // Persona summaries by agent name
+	Anonymize   bool              // Replace agent names with "Agent 1", "Agent 2", ...
+	Scrub       bool              // Replace emails, phone numbers, and similar PII with placeholders
+
+	Agents         []string // Only build examples of these agents, by their names in the chronicle (default: all)
+	MinConsistency float64  // Only build examples of agents whose PersonaConsistency is at least this
+}
+
+// Example is one dataset record.
+type Example struct {
+	Scenario string   `json:"scenario,omitempty"`
+	Setting  string   `json:"setting,omitempty"`
+	Turn     int      `json:"turn,omitempty"`
+	Agent    string   `json:"agent"`
+	Persona  string   `json:"persona,omitempty"`
+	Context  []string `json:"context,omitempty"`
+	Action   Action   `json:"action"`
+}
+
+// Action is what the agent did.
+type Action struct {
+	Type      string   `json:"type"` // dialogue, action, monologue, pass
+	Text      string   `json:"text,omitempty"`
+	Reasoning string   `json:"reasoning,omitempty"`
+	Proposals []string `json:"proposals,omitempty"`
+	Votes     []string `json:"votes,omitempty"` // Formatted as proposal_id:choice
+}
+
+// ValidateFields checks that every field name is known.
+func ValidateFields(fields []string) error {
+	for _, field := range fields {
+		known := false
+		for _, candidate := range AllFields {
+			if field == candidate {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return fmt.Errorf("unknown field '%s' (use %s)", field, strings.Join(AllFields, ", "))
+		}
+	}
+	return nil
+}
+
+// Build converts one chronicle into dataset examples.
+func Build(metadata *chronicle.Metadata, turns []chronicle.Turn, opts Options) []Example {
+	fields := opts.Fields
+	if len(fields) == 0 {
+		fields = DefaultFields
+	}
+	include := make(map[string]bool, len(fields))
+	for _, field := range fields {
+		include[field] = true
+	}
+	contextSize := opts.ContextSize
+	if contextSize <= 0 {
+		contextSize = 10
+	}
+
+	clean := newCleaner(turns, opts)
+	selected := selectAgents(turns, opts)
+
+	var examples []Example
+	var history []string
+	for _, turn := range turns {
+		for _, event := range turn.Events {
+			if event.Dialogue == "" && len(event.Proposals) == 0 && len(event.Votes) == 0 {
+				continue
+			}
+			// Refused responses aren't in-character examples
+			if event.Refusal != nil {
+				continue
+			}
+
+			eventType := event.Type
+			if eventType == "" {
+				eventType = "dialogue"
+			}
+
+			// Monologue and whispers are private, so they never become context for later examples
+			public := event.Dialogue != "" && eventType != "monologue" && eventType != "whisper" && eventType != "pass"
+
+			// Agents left out still speak in the context of those selected
+			if !selected(event.AgentName) {
+				if public {
+					history = append(history, formatMessage(clean(event.AgentName), eventType, clean(event.Dialogue)))
+				}
+				continue
+			}
+
+			example := Example{
+				Agent: clean(event.AgentName),
+				Action: Action{
+					Type: eventType,
+					Text: clean(event.Dialogue),
+				},
+			}
+			for _, proposal := range event.Proposals {
+				example.Action.Proposals = append(example.Action.Proposals, clean(proposal))
+			}
+			for _, vote := range event.Votes {
+				example.Action.Votes = append(example.Action.Votes, vote.ProposalID+":"+vote.Choice)
+			}
+
+			if include[FieldScenario] {
+				example.Scenario = clean(metadata.Scenario)
+			}
+			if include[FieldSetting] {
+				example.Setting = clean(setting(metadata))
+			}
+			if include[FieldTurn] {
+				example.Turn = turn.Number
+			}
+			if include[FieldPersona] {
+				example.Persona = clean(opts.Personas[event.AgentName])
+			}
+			if include[FieldContext] && len(history) > 0 {
+				start := max(0, len(history)-contextSize)
+				example.Context = append([]string(nil), history[start:]...)
+			}
+			if include[FieldReasoning] {
+				example.Action.Reasoning = clean(event.Reasoning)
+			}
+			examples = append(examples, example)
+
+			if public {
+				history = append(history, formatMessage(example.Agent, eventType, example.Action.Text))
+			}
+		}
+	}
+	return examples
+}
+
+// selectAgents returns whether an agent's examples are built: they must be
+// named in opts.Agents, if any, and have stayed in character often enough.
+// Agents with nothing scored, such as those who only passed, count as
+// consistent.
+func selectAgents(turns []chronicle.Turn, opts Options) func(string) bool {
+	scores := PersonaConsistency(turns)
+	return func(agent string) bool {
+		if len(opts.Agents) > 0 && !slices.Contains(opts.Agents, agent) {
+			return false
+		}
+		if score, ok := scores[agent]; ok && score < opts.MinConsistency {
+			return false
+		}
+		return true
+	}
+}
+
+// PersonaSummary describes a character's public persona in one line.
+// Internal details (background, secrets) are left out.
+func PersonaSummary(character *scenarios.Character) string {
+	if character == nil || character.External == nil {
+		return ""
+	}
+	ext := character.External
+	parts := []string{ext.Archetype + ": " + ext.Description}
+	if ext.CommunicationStyle != "" {
+		parts = append(parts, "Communication style: "+ext.CommunicationStyle)
+	}
+	if len(ext.PositiveTraits) > 0 {
+		parts = append(parts, "Strengths: "+strings.Join(ext.PositiveTraits, ", "))
+	}
+	if len(ext.NegativeTraits) > 0 {
+		parts = append(parts, "Flaws: "+strings.Join(ext.NegativeTraits, ", "))
+	}
+	return strings.Join(parts, ". ")
+}
+
+// setting describes where and when the scene takes place.
+func setting(metadata *chronicle.Metadata) string {
+	parts := []string{}
+	for _, part := range []string{metadata.Location, metadata.Time, metadata.Atmosphere} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, "; ")
+}
+
+// formatMessage renders an earlier event as a context line.
+func formatMessage(agent, eventType, text string) string {
+	if eventType == "action" {
+		return fmt.Sprintf("%s *%s*", agent, text)
+	}
+	return fmt.Sprintf("%s: %s", agent, text)
+}
+
+// newCleaner returns a function applying anonymization and PII scrubbing to text.
+func newCleaner(turns []chronicle.Turn, opts Options) func(string) string {
+	var replacer *strings.Replacer
+	if opts.Anonymize {
+		replacer = pseudonyms(turns)
+	}
+	return func(text string) string {
+		if replacer != nil {
+			text = replacer.Replace(text)
+		}
+		if opts.Scrub {
+			text = Scrub(text)
+		}
+		return text
+	}
+}
+
+// pseudonyms maps agent names to "Agent N" in order of first appearance.
+func pseudonyms(turns []chronicle.Turn) *strings.Replacer {
+	var names []string
+	seen := make(map[string]bool)
+	addName := func(name string) {
+		if name != "" && !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	for _, turn := range turns {
+		for _, event := range turn.Events {
+			addName(event.AgentName)
+		}
+		for _, completion := range turn.GoalCompletions {
+			addName(completion.ProposedBy)
+			addName(completion.CompletedBy)
+		}
+	}
+
+	aliases := make(map[string]string, len(names))
+	for i, name := range names {
+		aliases[name] = fmt.Sprintf("Agent %d", i+1)
+	}
+
+	// Replace longer names first so "Ann" doesn't clobber part of "Annabel",
+	// and first names on their own ("Alex" for "Alex Chen")
+	type pair struct{ from, to string }
+	var pairs []pair
+	for _, name := range names {
+		pairs = append(pairs, pair{name, aliases[name]})
+		if first, _, ok := strings.Cut(name, " "); ok && !seen[first] {
+			pairs = append(pairs, pair{first, aliases[name]})
+		}
+	}
+	sort.SliceStable(pairs, func(i, j int) bool { return len(pairs[i].from) > len(pairs[j].from) })
+
+	args := make([]string, 0, len(pairs)*2)
+	for _, p := range pairs {
+		args = append(args, p.from, p.to)
+	}
+	return strings.NewReplacer(args...)
+}
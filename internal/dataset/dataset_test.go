@@ -0,0 +1,144 @@
+package dataset
+
+import (
+	"testing"
+
+	"github.com/poiesic/wonda/internal/chronicle"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testChronicle() (*chronicle.Metadata, []chronicle.Turn) {
+	metadata := &chronicle.Metadata{Type: "metadata", Scenario: "Dinner", Location: "Cafe", Time: "evening"}
+	turns := []chronicle.Turn{{
+		Type:   "turn",
+		Number: 1,
+		Events: []chronicle.Event{
+			{AgentName: "Alex Chen", Type: "dialogue", Dialogue: "Hi Jordan, email me at alex@example.com or call 555-123-4567", Reasoning: "be friendly"},
+			{AgentName: "Jordan", Type: "monologue", Dialogue: "Alex is hiding something"},
+			{AgentName: "Jordan", Dialogue: "Sure, Alex", Proposals: []string{"Bella's"}},
+		},
+	}}
+	return metadata, turns
+}
+
+func TestBuild(t *testing.T) {
+	t.Run("anonymizes names and scrubs PII", func(t *testing.T) {
+		metadata, turns := testChronicle()
+		examples := Build(metadata, turns, Options{Anonymize: true, Scrub: true})
+		require.Len(t, examples, 3)
+
+		assert.Equal(t, "Agent 1", examples[0].Agent)
+		assert.Equal(t, "Hi Agent 2, email me at [email] or call [phone]", examples[0].Action.Text)
+		assert.Equal(t, "Agent 1 is hiding something", examples[1].Action.Text)
+		assert.Equal(t, "Sure, Agent 1", examples[2].Action.Text)
+	})
+
+	t.Run("keeps monologue out of context", func(t *testing.T) {
+		metadata, turns := testChronicle()
+		examples := Build(metadata, turns, Options{})
+		require.Len(t, examples, 3)
+
+		assert.Empty(t, examples[0].Context)
+		assert.Len(t, examples[2].Context, 1)
+		assert.Equal(t, "Alex Chen: Hi Jordan, email me at alex@example.com or call 555-123-4567", examples[2].Context[0])
+	})
+
+	t.Run("includes only selected fields", func(t *testing.T) {
+		metadata, turns := testChronicle()
+		examples := Build(metadata, turns, Options{
+			Fields:   []string{FieldTurn, FieldSetting, FieldReasoning},
+			Personas: map[string]string{"Alex Chen": "Chef: loves food"},
+		})
+		require.Len(t, examples, 3)
+
+		assert.Equal(t, 1, examples[0].Turn)
+		assert.Equal(t, "Cafe; evening", examples[0].Setting)
+		assert.Equal(t, "be friendly", examples[0].Action.Reasoning)
+		assert.Empty(t, examples[0].Persona)
+		assert.Empty(t, examples[0].Scenario)
+		assert.Nil(t, examples[2].Context)
+	})
+
+	t.Run("rejects unknown fields", func(t *testing.T) {
+		assert.NoError(t, ValidateFields([]string{FieldPersona, FieldContext}))
+		assert.Error(t, ValidateFields([]string{"secrets"}))
+	})
+}
+
+func TestFormat(t *testing.T) {
+	example := Example{
+		Agent:   "Jordan",
+		Persona: "Chef: loves food",
+		Context: []string{"Alex Chen: Where should we eat?"},
+		Action:  Action{Type: "dialogue", Text: "Sure, Alex", Proposals: []string{"Bella's"}},
+	}
+
+	t.Run("openai", func(t *testing.T) {
+		record, ok := Format(example, FormatOpenAI)
+		require.True(t, ok)
+		messages := record.(OpenAIRecord).Messages
+		require.Len(t, messages, 3)
+		assert.Equal(t, ChatMessage{Role: "system", Content: "You are Jordan, Chef: loves food."}, messages[0])
+		assert.Equal(t, "Alex Chen: Where should we eat?\n\nWhat do you say or do?", messages[1].Content)
+		assert.Equal(t, ChatMessage{Role: "assistant", Content: "Sure, Alex\nI propose: Bella's"}, messages[2])
+	})
+
+	t.Run("anthropic keeps the system prompt out of the messages", func(t *testing.T) {
+		record, ok := Format(example, FormatAnthropic)
+		require.True(t, ok)
+		anthropic := record.(AnthropicRecord)
+		assert.Equal(t, "You are Jordan, Chef: loves food.", anthropic.System)
+		require.Len(t, anthropic.Messages, 2)
+		assert.Equal(t, "user", anthropic.Messages[0].Role)
+		assert.Equal(t, "assistant", anthropic.Messages[1].Role)
+	})
+
+	t.Run("sharegpt", func(t *testing.T) {
+		record, ok := Format(example, FormatShareGPT)
+		require.True(t, ok)
+		conversation := record.(ShareGPTRecord).Conversations
+		require.Len(t, conversation, 3)
+		assert.Equal(t, []string{"system", "human", "gpt"}, []string{conversation[0].From, conversation[1].From, conversation[2].From})
+	})
+
+	t.Run("skips examples with nothing to say", func(t *testing.T) {
+		_, ok := Format(Example{Agent: "Jordan", Action: Action{Type: "dialogue", Votes: []string{"p1:yes"}}}, FormatOpenAI)
+		assert.False(t, ok)
+	})
+
+	t.Run("rejects unknown formats", func(t *testing.T) {
+		assert.NoError(t, ValidateFormat(FormatShareGPT))
+		assert.Error(t, ValidateFormat("alpaca"))
+	})
+}
+
+func TestPersonaConsistency(t *testing.T) {
+	turns := []chronicle.Turn{{
+		Number: 1,
+		Events: []chronicle.Event{
+			{AgentName: "Alex", Dialogue: "Let's eat"},
+			{AgentName: "Alex", Dialogue: "As an AI, I don't eat"},
+			{AgentName: "Jordan", Dialogue: "Sounds good"},
+			{AgentName: "Jordan", Type: "action", Dialogue: "shrugs", Fallback: "timeout"},
+			{AgentName: "Jordan", Dialogue: "Bella's?"},
+			{AgentName: "Jordan", Type: "pass"},
+		},
+	}}
+
+	scores := PersonaConsistency(turns)
+	assert.InDelta(t, 0.5, scores["Alex"], 0.001)
+	assert.InDelta(t, 2.0/3.0, scores["Jordan"], 0.001)
+
+	t.Run("filters agents by score and name", func(t *testing.T) {
+		examples := Build(&chronicle.Metadata{}, turns, Options{MinConsistency: 0.6})
+		require.Len(t, examples, 3)
+		for _, example := range examples {
+			assert.Equal(t, "Jordan", example.Agent)
+		}
+		assert.Equal(t, []string{"Alex: Let's eat", "Alex: As an AI, I don't eat"}, examples[0].Context)
+
+		examples = Build(&chronicle.Metadata{}, turns, Options{Agents: []string{"Alex"}})
+		require.Len(t, examples, 2)
+	})
+}
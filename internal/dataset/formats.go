@@ -0,0 +1,186 @@
+package dataset
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/poiesic/wonda/internal/chronicle"
+)
+
+// Output formats. Wonda's own format is the Example record; the others are
+// the fine-tuning formats providers and training tools accept.
+const (
+	FormatWonda     = "wonda"
+	FormatOpenAI    = "openai"    // OpenAI chat fine-tuning JSONL
+	FormatAnthropic = "anthropic" // Anthropic system and messages
+	FormatShareGPT  = "sharegpt"  // ShareGPT conversations
+)
+
+// AllFormats lists every output format.
+var AllFormats = []string{FormatWonda, FormatOpenAI, FormatAnthropic, FormatShareGPT}
+
+// ValidateFormat checks that a format name is known.
+func ValidateFormat(format string) error {
+	for _, candidate := range AllFormats {
+		if format == candidate {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown format '%s' (use %s)", format, strings.Join(AllFormats, ", "))
+}
+
+// ChatMessage is a message in the OpenAI and Anthropic formats.
+type ChatMessage struct {
+	Role    string `json:"role"` // system, user or assistant
+	Content string `json:"content"`
+}
+
+// OpenAIRecord is one OpenAI chat fine-tuning example.
+type OpenAIRecord struct {
+	Messages []ChatMessage `json:"messages"`
+}
+
+// AnthropicRecord is one Anthropic fine-tuning example. The system prompt
+// is kept out of the messages, as the Messages API expects.
+type AnthropicRecord struct {
+	System   string        `json:"system,omitempty"`
+	Messages []ChatMessage `json:"messages"`
+}
+
+// ShareGPTTurn is one turn of a ShareGPT conversation.
+type ShareGPTTurn struct {
+	From  string `json:"from"` // system, human or gpt
+	Value string `json:"value"`
+}
+
+// ShareGPTRecord is one ShareGPT conversation.
+type ShareGPTRecord struct {
+	Conversations []ShareGPTTurn `json:"conversations"`
+}
+
+// Format converts an example to a record in the given format. The agent's
+// persona and the setting become the system prompt, the conversation so far
+// the user message, and the action the reply to learn. Examples with nothing
+// to say, such as bare votes, have no chat record and report false.
+func Format(example Example, format string) (any, bool) {
+	if format == FormatWonda || format == "" {
+		return example, true
+	}
+
+	reply := reply(example)
+	if reply == "" {
+		return nil, false
+	}
+	system := systemPrompt(example)
+	user := userPrompt(example)
+
+	switch format {
+	case FormatOpenAI:
+		var messages []ChatMessage
+		if system != "" {
+			messages = append(messages, ChatMessage{Role: "system", Content: system})
+		}
+		messages = append(messages,
+			ChatMessage{Role: "user", Content: user},
+			ChatMessage{Role: "assistant", Content: reply})
+		return OpenAIRecord{Messages: messages}, true
+	case FormatAnthropic:
+		return AnthropicRecord{
+			System: system,
+			Messages: []ChatMessage{
+				{Role: "user", Content: user},
+				{Role: "assistant", Content: reply},
+			},
+		}, true
+	case FormatShareGPT:
+		var conversation []ShareGPTTurn
+		if system != "" {
+			conversation = append(conversation, ShareGPTTurn{From: "system", Value: system})
+		}
+		conversation = append(conversation,
+			ShareGPTTurn{From: "human", Value: user},
+			ShareGPTTurn{From: "gpt", Value: reply})
+		return ShareGPTRecord{Conversations: conversation}, true
+	}
+	return nil, false
+}
+
+// systemPrompt tells the model who it plays and where.
+func systemPrompt(example Example) string {
+	var parts []string
+	if example.Persona != "" {
+		parts = append(parts, fmt.Sprintf("You are %s, %s.", example.Agent, example.Persona))
+	} else {
+		parts = append(parts, fmt.Sprintf("You are %s.", example.Agent))
+	}
+	if example.Scenario != "" {
+		parts = append(parts, "Scene: "+example.Scenario+".")
+	}
+	if example.Setting != "" {
+		parts = append(parts, "Setting: "+example.Setting+".")
+	}
+	return strings.Join(parts, " ")
+}
+
+// userPrompt gives the conversation the agent is answering.
+func userPrompt(example Example) string {
+	if len(example.Context) == 0 {
+		return "The scene begins. What do you say or do?"
+	}
+	return strings.Join(example.Context, "\n") + "\n\nWhat do you say or do?"
+}
+
+// reply renders the agent's action as the response to learn.
+func reply(example Example) string {
+	var lines []string
+	if example.Action.Text != "" {
+		lines = append(lines, formatReply(example.Action.Type, example.Action.Text))
+	}
+	for _, proposal := range example.Action.Proposals {
+		lines = append(lines, "I propose: "+proposal)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// formatReply renders an action in the agent's own voice.
+func formatReply(eventType, text string) string {
+	switch eventType {
+	case "action":
+		return "*" + text + "*"
+	case "monologue":
+		return "(" + text + ")"
+	}
+	return text
+}
+
+// outOfCharacter matches dialogue in which a model speaks as itself rather
+// than as its character.
+var outOfCharacter = regexp.MustCompile(`(?i)\b(as an ai|i'?m an ai|i am an ai|language model|ai assistant|as a character in this (scene|simulation|role-?play))\b`)
+
+// PersonaConsistency scores how well each agent of a chronicle stayed in
+// character, from 0 to 1: the share of their events that weren't refusals,
+// fallback actions standing in for a failed model, or dialogue that broke
+// character. Agents without events are left out.
+func PersonaConsistency(turns []chronicle.Turn) map[string]float64 {
+	total := make(map[string]int)
+	consistent := make(map[string]int)
+	for _, turn := range turns {
+		for _, event := range turn.Events {
+			if event.AgentName == "" || event.Type == "pass" {
+				continue
+			}
+			total[event.AgentName]++
+			if event.Refusal != nil || event.Type == "refusal" || event.Fallback != "" || outOfCharacter.MatchString(event.Dialogue) {
+				continue
+			}
+			consistent[event.AgentName]++
+		}
+	}
+
+	scores := make(map[string]float64, len(total))
+	for agent, count := range total {
+		scores[agent] = float64(consistent[agent]) / float64(count)
+	}
+	return scores
+}
@@ -0,0 +1,29 @@
+package dataset
+
+import "regexp"
+
+// scrubRule replaces one kind of personally identifiable information.
+type scrubRule struct {
+	pattern     *regexp.Regexp
+	placeholder string
+}
+
+// scrubRules are applied in order; more specific patterns come first so a URL
+// containing an email address, for example, is scrubbed as a URL.
+var scrubRules = []scrubRule{
+	{regexp.MustCompile(`(?i)\bhttps?://[^\s]+`), "[url]"},
+	{regexp.MustCompile(`(?i)\b[a-z0-9._%+\-]+@[a-z0-9.\-]+\.[a-z]{2,}\b`), "[email]"},
+	{regexp.MustCompile(`\b\d{1,3}(?:\.\d{1,3}){3}\b`), "[ip]"},
+	{regexp.MustCompile(`\b\d{3}-\d{2}-\d{4}\b`), "[ssn]"},
+	{regexp.MustCompile(`\b(?:\d[ \-]?){12,15}\d\b`), "[card]"},
+	{regexp.MustCompile(`(?:\+\d{1,3}[ .\-]?)?\(?\b\d{3}\)?[ .\-]?\d{3}[ .\-]?\d{4}\b`), "[phone]"},
+}
+
+// Scrub replaces emails, URLs, IP addresses, social security, card, and phone
+// numbers in text with bracketed placeholders such as "[email]".
+func Scrub(text string) string {
+	for _, rule := range scrubRules {
+		text = rule.pattern.ReplaceAllString(text, rule.placeholder)
+	}
+	return text
+}
@@ -0,0 +1,209 @@
+package expr
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrDivisionByZero is returned when an expression divides by zero.
+var ErrDivisionByZero = errors.New("division by zero")
+
+// value is the result of evaluating a node: a number or a boolean.
+type value struct {
+	isBool bool
+	n      float64
+	b      bool
+}
+
+type node interface {
+	// check reports whether the node produces a boolean, or a type error.
+	check() (bool, error)
+	eval(variables map[string]float64) (value, error)
+}
+
+type numberNode struct {
+	value float64
+}
+
+func (n *numberNode) check() (bool, error) {
+	return false, nil
+}
+
+func (n *numberNode) eval(map[string]float64) (value, error) {
+	return value{n: n.value}, nil
+}
+
+type boolNode struct {
+	value bool
+}
+
+func (n *boolNode) check() (bool, error) {
+	return true, nil
+}
+
+func (n *boolNode) eval(map[string]float64) (value, error) {
+	return value{isBool: true, b: n.value}, nil
+}
+
+// variableNode is a number variable, or a flag, true when its value isn't zero.
+type variableNode struct {
+	name   string
+	isBool bool
+}
+
+func (n *variableNode) check() (bool, error) {
+	return n.isBool, nil
+}
+
+func (n *variableNode) eval(variables map[string]float64) (value, error) {
+	v, ok := variables[n.name]
+	if !ok {
+		return value{}, fmt.Errorf("variable %q has no value", n.name)
+	}
+	if n.isBool {
+		return value{isBool: true, b: v != 0}, nil
+	}
+	return value{n: v}, nil
+}
+
+type unaryNode struct {
+	op      string
+	operand node
+}
+
+func (n *unaryNode) check() (bool, error) {
+	isBool, err := n.operand.check()
+	if err != nil {
+		return false, err
+	}
+	if n.op == "!" && !isBool {
+		return false, fmt.Errorf("'!' needs true or false, not a number")
+	}
+	if n.op == "-" && isBool {
+		return false, fmt.Errorf("'-' needs a number, not true or false")
+	}
+	return isBool, nil
+}
+
+func (n *unaryNode) eval(variables map[string]float64) (value, error) {
+	operand, err := n.operand.eval(variables)
+	if err != nil {
+		return value{}, err
+	}
+	switch n.op {
+	case "!":
+		if !operand.isBool {
+			return value{}, fmt.Errorf("'!' needs true or false, not a number")
+		}
+		return value{isBool: true, b: !operand.b}, nil
+	default: // "-"
+		if operand.isBool {
+			return value{}, fmt.Errorf("'-' needs a number, not true or false")
+		}
+		return value{n: -operand.n}, nil
+	}
+}
+
+type binaryNode struct {
+	op          string
+	left, right node
+}
+
+func (n *binaryNode) check() (bool, error) {
+	leftBool, err := n.left.check()
+	if err != nil {
+		return false, err
+	}
+	rightBool, err := n.right.check()
+	if err != nil {
+		return false, err
+	}
+	switch n.op {
+	case "&&", "||":
+		if !leftBool || !rightBool {
+			return false, fmt.Errorf("'%s' needs true or false, not a number", n.op)
+		}
+		return true, nil
+	case "==", "!=":
+		if leftBool != rightBool {
+			return false, fmt.Errorf("'%s' cannot compare a number with true or false", n.op)
+		}
+		return true, nil
+	case "<", "<=", ">", ">=":
+		if leftBool || rightBool {
+			return false, fmt.Errorf("'%s' needs numbers, not true or false", n.op)
+		}
+		return true, nil
+	default:
+		if leftBool || rightBool {
+			return false, fmt.Errorf("'%s' needs numbers, not true or false", n.op)
+		}
+		return false, nil
+	}
+}
+
+func (n *binaryNode) eval(variables map[string]float64) (value, error) {
+	left, err := n.left.eval(variables)
+	if err != nil {
+		return value{}, err
+	}
+
+	// Logical operators short-circuit
+	if n.op == "&&" || n.op == "||" {
+		if !left.isBool {
+			return value{}, fmt.Errorf("'%s' needs true or false, not a number", n.op)
+		}
+		if (n.op == "&&" && !left.b) || (n.op == "||" && left.b) {
+			return left, nil
+		}
+		right, err := n.right.eval(variables)
+		if err != nil {
+			return value{}, err
+		}
+		if !right.isBool {
+			return value{}, fmt.Errorf("'%s' needs true or false, not a number", n.op)
+		}
+		return right, nil
+	}
+
+	right, err := n.right.eval(variables)
+	if err != nil {
+		return value{}, err
+	}
+
+	// Equality works on two values of the same type
+	if n.op == "==" || n.op == "!=" {
+		if left.isBool != right.isBool {
+			return value{}, fmt.Errorf("'%s' cannot compare a number with true or false", n.op)
+		}
+		equal := left.n == right.n && left.b == right.b
+		return value{isBool: true, b: equal == (n.op == "==")}, nil
+	}
+
+	if left.isBool || right.isBool {
+		return value{}, fmt.Errorf("'%s' needs numbers, not true or false", n.op)
+	}
+	switch n.op {
+	case "+":
+		return value{n: left.n + right.n}, nil
+	case "-":
+		return value{n: left.n - right.n}, nil
+	case "*":
+		return value{n: left.n * right.n}, nil
+	case "/":
+		if right.n == 0 {
+			return value{}, ErrDivisionByZero
+		}
+		return value{n: left.n / right.n}, nil
+	case "<":
+		return value{isBool: true, b: left.n < right.n}, nil
+	case "<=":
+		return value{isBool: true, b: left.n <= right.n}, nil
+	case ">":
+		return value{isBool: true, b: left.n > right.n}, nil
+	case ">=":
+		return value{isBool: true, b: left.n >= right.n}, nil
+	default:
+		return value{}, fmt.Errorf("unknown operator '%s'", n.op)
+	}
+}
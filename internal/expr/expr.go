@@ -0,0 +1,344 @@
+// Package expr implements a small, side-effect free expression language used by
+// scenario authors for rules such as `yes >= 0.6 * assigned && no <= 1`.
+//
+// Expressions support numeric literals, variables, parentheses, the arithmetic
+// operators + - * /, the comparisons < <= > >= == !=, and the logical operators
+// && || !. Numbers and booleans are distinct types; mixing them is an error.
+// Variables are numbers, or with CompileFlags, flags that are true or false.
+package expr
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Expr is a compiled expression.
+type Expr struct {
+	source     string
+	root       node
+	referenced []string
+}
+
+// String returns the expression's source text.
+func (e *Expr) String() string {
+	return e.source
+}
+
+// Compile parses source and checks that it only references the given variables
+// and that it produces a boolean.
+func Compile(source string, variables []string) (*Expr, error) {
+	return compile(source, variables, false)
+}
+
+// CompileFlags is Compile for expressions whose variables are all true or
+// false, such as `restaurant && (dessert || drinks)`. Evaluate them with Holds.
+func CompileFlags(source string, flags []string) (*Expr, error) {
+	return compile(source, flags, true)
+}
+
+// compile parses source with variables of one type: numbers, or flags.
+func compile(source string, variables []string, flags bool) (*Expr, error) {
+	p := &parser{source: source, variables: make(map[string]bool, len(variables)), flags: flags}
+	for _, name := range variables {
+		p.variables[name] = true
+	}
+	if err := p.tokenize(); err != nil {
+		return nil, err
+	}
+
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokenEOF {
+		return nil, fmt.Errorf("unexpected %q at position %d", tok.text, tok.pos)
+	}
+
+	isBool, err := root.check()
+	if err != nil {
+		return nil, err
+	}
+	if !isBool {
+		return nil, fmt.Errorf("expression must produce true or false, not a number")
+	}
+
+	return &Expr{source: source, root: root, referenced: p.referenced}, nil
+}
+
+// Variables returns the variables the expression references, in the order
+// they first appear.
+func (e *Expr) Variables() []string {
+	return e.referenced
+}
+
+// Bool evaluates the expression with the given variable values.
+func (e *Expr) Bool(variables map[string]float64) (bool, error) {
+	result, err := e.root.eval(variables)
+	if err != nil {
+		return false, fmt.Errorf("evaluating %q: %w", e.source, err)
+	}
+	if !result.isBool {
+		return false, fmt.Errorf("evaluating %q: expected true or false, got a number", e.source)
+	}
+	return result.b, nil
+}
+
+// Holds evaluates an expression compiled with CompileFlags with the given flag values.
+func (e *Expr) Holds(flags map[string]bool) (bool, error) {
+	variables := make(map[string]float64, len(flags))
+	for name, set := range flags {
+		if set {
+			variables[name] = 1
+		} else {
+			variables[name] = 0
+		}
+	}
+	return e.Bool(variables)
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenNumber
+	tokenIdent
+	tokenOperator
+	tokenLParen
+	tokenRParen
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+// operators lists the recognized operators, longest first so "<=" wins over "<".
+var operators = []string{"&&", "||", "<=", ">=", "==", "!=", "<", ">", "+", "-", "*", "/", "!"}
+
+type parser struct {
+	source     string
+	variables  map[string]bool
+	flags      bool     // Variables are true or false rather than numbers
+	referenced []string // Variables referenced, in order of first appearance
+	tokens     []token
+	pos        int
+}
+
+func (p *parser) tokenize() error {
+	src := p.source
+	for i := 0; i < len(src); {
+		c := rune(src[i])
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case c == '(':
+			p.tokens = append(p.tokens, token{kind: tokenLParen, text: "(", pos: i})
+			i++
+		case c == ')':
+			p.tokens = append(p.tokens, token{kind: tokenRParen, text: ")", pos: i})
+			i++
+		case unicode.IsDigit(c) || c == '.':
+			start := i
+			for i < len(src) && (unicode.IsDigit(rune(src[i])) || src[i] == '.') {
+				i++
+			}
+			p.tokens = append(p.tokens, token{kind: tokenNumber, text: src[start:i], pos: start})
+		case unicode.IsLetter(c) || c == '_':
+			start := i
+			for i < len(src) && (unicode.IsLetter(rune(src[i])) || unicode.IsDigit(rune(src[i])) || src[i] == '_') {
+				i++
+			}
+			p.tokens = append(p.tokens, token{kind: tokenIdent, text: src[start:i], pos: start})
+		default:
+			matched := false
+			for _, op := range operators {
+				if strings.HasPrefix(src[i:], op) {
+					p.tokens = append(p.tokens, token{kind: tokenOperator, text: op, pos: i})
+					i += len(op)
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return fmt.Errorf("unexpected character %q at position %d", c, i)
+			}
+		}
+	}
+	p.tokens = append(p.tokens, token{kind: tokenEOF, text: "end of expression", pos: len(src)})
+	return nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokenEOF {
+		p.pos++
+	}
+	return tok
+}
+
+// acceptOperator consumes the next token if it is one of ops.
+func (p *parser) acceptOperator(ops ...string) (string, bool) {
+	tok := p.peek()
+	if tok.kind != tokenOperator {
+		return "", false
+	}
+	for _, op := range ops {
+		if tok.text == op {
+			p.pos++
+			return op, true
+		}
+	}
+	return "", false
+}
+
+// Grammar, lowest precedence first:
+//
+//	or         = and { "||" and }
+//	and        = comparison { "&&" comparison }
+//	comparison = sum [ ("<" | "<=" | ">" | ">=" | "==" | "!=") sum ]
+//	sum        = product { ("+" | "-") product }
+//	product    = unary { ("*" | "/") unary }
+//	unary      = ("!" | "-") unary | primary
+//	primary    = number | identifier | "(" or ")"
+
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if _, ok := p.acceptOperator("||"); !ok {
+			return left, nil
+		}
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryNode{op: "||", left: left, right: right}
+	}
+}
+
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseComparison()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if _, ok := p.acceptOperator("&&"); !ok {
+			return left, nil
+		}
+		right, err := p.parseComparison()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryNode{op: "&&", left: left, right: right}
+	}
+}
+
+func (p *parser) parseComparison() (node, error) {
+	left, err := p.parseSum()
+	if err != nil {
+		return nil, err
+	}
+	op, ok := p.acceptOperator("<", "<=", ">", ">=", "==", "!=")
+	if !ok {
+		return left, nil
+	}
+	right, err := p.parseSum()
+	if err != nil {
+		return nil, err
+	}
+	return &binaryNode{op: op, left: left, right: right}, nil
+}
+
+func (p *parser) parseSum() (node, error) {
+	left, err := p.parseProduct()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.acceptOperator("+", "-")
+		if !ok {
+			return left, nil
+		}
+		right, err := p.parseProduct()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryNode{op: op, left: left, right: right}
+	}
+}
+
+func (p *parser) parseProduct() (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.acceptOperator("*", "/")
+		if !ok {
+			return left, nil
+		}
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryNode{op: op, left: left, right: right}
+	}
+}
+
+func (p *parser) parseUnary() (node, error) {
+	if op, ok := p.acceptOperator("!", "-"); ok {
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &unaryNode{op: op, operand: operand}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (node, error) {
+	tok := p.next()
+	switch tok.kind {
+	case tokenNumber:
+		value, err := strconv.ParseFloat(tok.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q at position %d", tok.text, tok.pos)
+		}
+		return &numberNode{value: value}, nil
+	case tokenIdent:
+		switch tok.text {
+		case "true":
+			return &boolNode{value: true}, nil
+		case "false":
+			return &boolNode{value: false}, nil
+		}
+		if !p.variables[tok.text] {
+			return nil, fmt.Errorf("unknown variable %q at position %d", tok.text, tok.pos)
+		}
+		if !slices.Contains(p.referenced, tok.text) {
+			p.referenced = append(p.referenced, tok.text)
+		}
+		return &variableNode{name: tok.text, isBool: p.flags}, nil
+	case tokenLParen:
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.kind != tokenRParen {
+			return nil, fmt.Errorf("expected ')' at position %d, found %q", closing.pos, closing.text)
+		}
+		return inner, nil
+	default:
+		return nil, fmt.Errorf("unexpected %q at position %d", tok.text, tok.pos)
+	}
+}
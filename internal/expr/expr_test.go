@@ -0,0 +1,107 @@
+package expr
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var voteVariables = []string{"yes", "no", "voted", "pending", "assigned"}
+
+func TestCompile(t *testing.T) {
+	t.Run("evaluates vote rules", func(t *testing.T) {
+		rule, err := Compile("yes >= 0.6 * assigned && no <= 1", voteVariables)
+		require.NoError(t, err)
+
+		cases := []struct {
+			yes, no, assigned float64
+			want              bool
+		}{
+			{yes: 3, no: 1, assigned: 5, want: true},
+			{yes: 2, no: 1, assigned: 5, want: false},
+			{yes: 3, no: 2, assigned: 5, want: false},
+		}
+		for _, c := range cases {
+			got, err := rule.Bool(map[string]float64{
+				"yes": c.yes, "no": c.no, "voted": c.yes + c.no,
+				"pending": c.assigned - c.yes - c.no, "assigned": c.assigned,
+			})
+			require.NoError(t, err)
+			assert.Equal(t, c.want, got, "yes=%v no=%v assigned=%v", c.yes, c.no, c.assigned)
+		}
+	})
+
+	t.Run("respects precedence and grouping", func(t *testing.T) {
+		vars := map[string]float64{"yes": 2, "no": 0, "voted": 2, "pending": 1, "assigned": 3}
+
+		rule, err := Compile("yes + no * 2 == 2 || !(pending > 0)", voteVariables)
+		require.NoError(t, err)
+		got, err := rule.Bool(vars)
+		require.NoError(t, err)
+		assert.True(t, got)
+
+		rule, err = Compile("(yes + no) * 2 == 2", voteVariables)
+		require.NoError(t, err)
+		got, err = rule.Bool(vars)
+		require.NoError(t, err)
+		assert.False(t, got)
+	})
+
+	t.Run("rejects invalid expressions", func(t *testing.T) {
+		for _, source := range []string{
+			"",
+			"yes >=",
+			"abstain > 0",
+			"yes + no",
+			"yes && no",
+			"(yes > 1",
+			"yes > 1 true",
+			"yes = 1",
+			"!yes",
+			"no > 5 && yes",
+		} {
+			_, err := Compile(source, voteVariables)
+			assert.Error(t, err, source)
+		}
+	})
+
+	t.Run("reports division by zero at evaluation", func(t *testing.T) {
+		rule, err := Compile("yes / assigned > 0.5", voteVariables)
+		require.NoError(t, err)
+		_, err = rule.Bool(map[string]float64{"yes": 1, "assigned": 0})
+		assert.ErrorIs(t, err, ErrDivisionByZero)
+	})
+}
+
+func TestCompileFlags(t *testing.T) {
+	goals := []string{"restaurant", "dessert", "drinks"}
+
+	t.Run("evaluates flags", func(t *testing.T) {
+		rule, err := CompileFlags("restaurant && (dessert || !drinks)", goals)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"restaurant", "dessert", "drinks"}, rule.Variables())
+
+		cases := []struct {
+			restaurant, dessert, drinks bool
+			want                        bool
+		}{
+			{restaurant: true, dessert: true, drinks: true, want: true},
+			{restaurant: true, dessert: false, drinks: false, want: true},
+			{restaurant: true, dessert: false, drinks: true, want: false},
+			{restaurant: false, dessert: true, drinks: true, want: false},
+		}
+		for _, c := range cases {
+			got, err := rule.Holds(map[string]bool{"restaurant": c.restaurant, "dessert": c.dessert, "drinks": c.drinks})
+			require.NoError(t, err)
+			assert.Equal(t, c.want, got, "%+v", c)
+		}
+	})
+
+	t.Run("rejects arithmetic on flags", func(t *testing.T) {
+		for _, source := range []string{"restaurant + dessert", "restaurant > 0", "restaurant == 1", "dinner && dessert"} {
+			_, err := CompileFlags(source, goals[:2])
+			assert.Error(t, err, source)
+		}
+	})
+}
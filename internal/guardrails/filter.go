@@ -0,0 +1,184 @@
+package guardrails
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/poiesic/wonda/internal/config"
+)
+
+// Violation describes content flagged by a filter.
+type Violation struct {
+	Filter string   // Name of the filter that flagged the content
+	Reason string   // Human-readable reason (pattern or moderation categories)
+	Spans  [][2]int // Byte ranges of the flagged content; empty means the whole text
+}
+
+// Filter checks a piece of agent output against a content policy.
+type Filter interface {
+	// Name identifies the filter in logs and violation reports.
+	Name() string
+	// Check returns the violations found in text, if any.
+	Check(ctx context.Context, text string) ([]Violation, error)
+}
+
+// RegexFilter flags text matching any of a list of regular expressions.
+type RegexFilter struct {
+	patterns []*regexp.Regexp
+}
+
+// NewRegexFilter compiles the given patterns into a filter.
+func NewRegexFilter(patterns []string) (*RegexFilter, error) {
+	compiled := make([]*regexp.Regexp, 0, len(patterns))
+	for _, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid guardrail pattern %q: %w", pattern, err)
+		}
+		compiled = append(compiled, re)
+	}
+	return &RegexFilter{patterns: compiled}, nil
+}
+
+// Name implements Filter.
+func (f *RegexFilter) Name() string {
+	return "regex"
+}
+
+// Check implements Filter. Each matching pattern produces one violation
+// covering all of its matches.
+func (f *RegexFilter) Check(ctx context.Context, text string) ([]Violation, error) {
+	var violations []Violation
+	for _, re := range f.patterns {
+		matches := re.FindAllStringIndex(text, -1)
+		if len(matches) == 0 {
+			continue
+		}
+		spans := make([][2]int, 0, len(matches))
+		for _, m := range matches {
+			// Skip empty matches, they have nothing to redact
+			if m[0] == m[1] {
+				continue
+			}
+			spans = append(spans, [2]int{m[0], m[1]})
+		}
+		if len(spans) == 0 {
+			continue
+		}
+		violations = append(violations, Violation{
+			Filter: f.Name(),
+			Reason: re.String(),
+			Spans:  spans,
+		})
+	}
+	return violations, nil
+}
+
+// ModerationFilter sends text to a provider's OpenAI-compatible /moderations endpoint.
+// Flagged text is treated as a violation of the whole text.
+type ModerationFilter struct {
+	provider string
+	baseURL  string
+	apiKey   string
+}
+
+// NewModerationFilter creates a moderation filter for a provider.
+func NewModerationFilter(provider *config.Provider) (*ModerationFilter, error) {
+	if provider == nil {
+		return nil, fmt.Errorf("provider cannot be nil")
+	}
+	if !provider.Moderation {
+		return nil, fmt.Errorf("provider %s does not have moderation enabled", provider.Name)
+	}
+
+	apiKey := ""
+	if provider.APIKey != nil {
+		apiKey = *provider.APIKey
+	}
+
+	return &ModerationFilter{
+		provider: provider.Name,
+		baseURL:  provider.BaseURL,
+		apiKey:   apiKey,
+	}, nil
+}
+
+// Name implements Filter.
+func (f *ModerationFilter) Name() string {
+	return "moderation:" + f.provider
+}
+
+// Check implements Filter.
+func (f *ModerationFilter) Check(ctx context.Context, text string) ([]Violation, error) {
+	jsonBody, err := json.Marshal(map[string]interface{}{"input": text})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	// baseURL already includes /v1, just append the endpoint
+	url := strings.TrimRight(f.baseURL, "/") + "/moderations"
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(jsonBody))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	httpReq.Header.Set("Content-Type", "application/json")
+	if f.apiKey != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+f.apiKey)
+	}
+
+	httpResp, err := http.DefaultClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("http request failed: %w", err)
+	}
+	defer httpResp.Body.Close()
+
+	respBody, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if httpResp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("moderation api error (status %d): %s", httpResp.StatusCode, string(respBody))
+	}
+
+	var resp struct {
+		Results []struct {
+			Flagged    bool            `json:"flagged"`
+			Categories map[string]bool `json:"categories"`
+		} `json:"results"`
+	}
+	if err := json.Unmarshal(respBody, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	var violations []Violation
+	for _, result := range resp.Results {
+		if !result.Flagged {
+			continue
+		}
+		var categories []string
+		for category, flagged := range result.Categories {
+			if flagged {
+				categories = append(categories, category)
+			}
+		}
+		sort.Strings(categories)
+		reason := "flagged"
+		if len(categories) > 0 {
+			reason = strings.Join(categories, ", ")
+		}
+		violations = append(violations, Violation{
+			Filter: f.Name(),
+			Reason: reason,
+		})
+	}
+	return violations, nil
+}
@@ -0,0 +1,133 @@
+package guardrails
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Policy actions taken when agent output violates a filter
+const (
+	ActionRedact     = "redact"     // Replace flagged content with RedactedText
+	ActionRegenerate = "regenerate" // Ask the model for a new response
+	ActionHalt       = "halt"       // Stop the simulation
+)
+
+// RedactedText replaces flagged content when redacting.
+const RedactedText = "[redacted]"
+
+// ErrHalted is returned when a halt policy stops the simulation.
+var ErrHalted = errors.New("guardrail halted simulation")
+
+// Guard runs agent output through a set of filters and reports what to do about it.
+type Guard struct {
+	filters          []Filter
+	action           string
+	maxRegenerations int
+}
+
+// NewGuard creates a guard applying action to output flagged by any of filters.
+// An empty action defaults to ActionRedact.
+func NewGuard(action string, maxRegenerations int, filters ...Filter) (*Guard, error) {
+	if action == "" {
+		action = ActionRedact
+	}
+	switch action {
+	case ActionRedact, ActionRegenerate, ActionHalt:
+	default:
+		return nil, fmt.Errorf("unknown guardrail action: %s", action)
+	}
+	if maxRegenerations < 0 {
+		return nil, fmt.Errorf("max regenerations must not be negative (got %d)", maxRegenerations)
+	}
+
+	return &Guard{
+		filters:          filters,
+		action:           action,
+		maxRegenerations: maxRegenerations,
+	}, nil
+}
+
+// Action returns the policy action for flagged output.
+func (g *Guard) Action() string {
+	return g.action
+}
+
+// MaxRegenerations returns how many times flagged output may be regenerated
+// before it is redacted instead.
+func (g *Guard) MaxRegenerations() int {
+	return g.maxRegenerations
+}
+
+// Review is the result of checking one piece of text.
+type Review struct {
+	Text       string // Original text
+	Violations []Violation
+}
+
+// Flagged reports whether any filter flagged the text.
+func (r Review) Flagged() bool {
+	return len(r.Violations) > 0
+}
+
+// Reasons returns a summary of the violations for logs and regeneration prompts.
+func (r Review) Reasons() string {
+	reasons := make([]string, len(r.Violations))
+	for i, v := range r.Violations {
+		reasons[i] = fmt.Sprintf("%s: %s", v.Filter, v.Reason)
+	}
+	return strings.Join(reasons, "; ")
+}
+
+// Redacted returns the text with flagged spans replaced by RedactedText.
+// A violation without spans redacts the whole text.
+func (r Review) Redacted() string {
+	var spans [][2]int
+	for _, v := range r.Violations {
+		if len(v.Spans) == 0 {
+			return RedactedText
+		}
+		spans = append(spans, v.Spans...)
+	}
+	if len(spans) == 0 {
+		return r.Text
+	}
+
+	// Merge overlapping spans so each region is redacted once
+	sort.Slice(spans, func(i, j int) bool { return spans[i][0] < spans[j][0] })
+	var b strings.Builder
+	last := 0
+	for i := 0; i < len(spans); {
+		start, end := spans[i][0], spans[i][1]
+		for i++; i < len(spans) && spans[i][0] <= end; i++ {
+			if spans[i][1] > end {
+				end = spans[i][1]
+			}
+		}
+		b.WriteString(r.Text[last:start])
+		b.WriteString(RedactedText)
+		last = end
+	}
+	b.WriteString(r.Text[last:])
+	return b.String()
+}
+
+// Check runs text through every filter.
+// Empty text is never flagged.
+func (g *Guard) Check(ctx context.Context, text string) (Review, error) {
+	review := Review{Text: text}
+	if strings.TrimSpace(text) == "" {
+		return review, nil
+	}
+
+	for _, filter := range g.filters {
+		violations, err := filter.Check(ctx, text)
+		if err != nil {
+			return review, fmt.Errorf("guardrail filter %s failed: %w", filter.Name(), err)
+		}
+		review.Violations = append(review.Violations, violations...)
+	}
+	return review, nil
+}
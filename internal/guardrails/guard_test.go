@@ -0,0 +1,80 @@
+package guardrails
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/poiesic/wonda/internal/config"
+)
+
+func TestGuard(t *testing.T) {
+	t.Run("redacts regex matches", func(t *testing.T) {
+		filter, err := NewRegexFilter([]string{`(?i)\bdarn\b`, `\d{3}-\d{4}`})
+		require.NoError(t, err)
+		guard, err := NewGuard("", 0, filter)
+		require.NoError(t, err)
+		assert.Equal(t, ActionRedact, guard.Action())
+
+		review, err := guard.Check(context.Background(), "Darn it, call 555-1234. Darn!")
+		require.NoError(t, err)
+		assert.True(t, review.Flagged())
+		assert.Equal(t, "[redacted] it, call [redacted]. [redacted]!", review.Redacted())
+	})
+
+	t.Run("passes clean text", func(t *testing.T) {
+		filter, err := NewRegexFilter([]string{`forbidden`})
+		require.NoError(t, err)
+		guard, err := NewGuard(ActionHalt, 0, filter)
+		require.NoError(t, err)
+
+		review, err := guard.Check(context.Background(), "Let's get pizza.")
+		require.NoError(t, err)
+		assert.False(t, review.Flagged())
+	})
+
+	t.Run("rejects invalid pattern", func(t *testing.T) {
+		_, err := NewRegexFilter([]string{`(`})
+		assert.Error(t, err)
+	})
+
+	t.Run("rejects unknown action", func(t *testing.T) {
+		_, err := NewGuard("ignore", 0)
+		assert.Error(t, err)
+	})
+
+	t.Run("moderation flag redacts whole text", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/moderations", r.URL.Path)
+			assert.Equal(t, "Bearer test-key", r.Header.Get("Authorization"))
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"results": []map[string]interface{}{
+					{"flagged": true, "categories": map[string]bool{"harassment": true, "violence": false}},
+				},
+			})
+		}))
+		defer server.Close()
+
+		apiKey := "test-key"
+		filter, err := NewModerationFilter(&config.Provider{Name: "openai", BaseURL: server.URL, APIKey: &apiKey, Moderation: true})
+		require.NoError(t, err)
+		guard, err := NewGuard(ActionRedact, 0, filter)
+		require.NoError(t, err)
+
+		review, err := guard.Check(context.Background(), "You are awful.")
+		require.NoError(t, err)
+		require.True(t, review.Flagged())
+		assert.Equal(t, "moderation:openai: harassment", review.Reasons())
+		assert.Equal(t, RedactedText, review.Redacted())
+	})
+
+	t.Run("moderation requires provider support", func(t *testing.T) {
+		_, err := NewModerationFilter(&config.Provider{Name: "anthropic", BaseURL: "http://test"})
+		assert.Error(t, err)
+	})
+}
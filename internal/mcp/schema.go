@@ -0,0 +1,288 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// argumentProblem is one way tool arguments don't match the tool's input schema.
+type argumentProblem struct {
+	Path    string // Argument at fault, e.g. "ranking[2]"; "" for the arguments as a whole
+	Message string
+}
+
+// argumentError reports every problem with a tool call's arguments, worded so
+// the model can correct the call on its next try.
+type argumentError struct {
+	tool     string
+	problems []argumentProblem
+}
+
+func (e *argumentError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "invalid arguments for %s:", e.tool)
+	for _, problem := range e.problems {
+		if problem.Path == "" {
+			fmt.Fprintf(&b, "\n- %s", problem.Message)
+		} else {
+			fmt.Fprintf(&b, "\n- %s: %s", problem.Path, problem.Message)
+		}
+	}
+	fmt.Fprintf(&b, "\nFix these and call %s again.", e.tool)
+	return b.String()
+}
+
+// validateArguments checks tool arguments against a tool's input schema. It
+// understands the parts of JSON Schema tools use: type, properties, required,
+// additionalProperties, items, enum, anyOf, minimum and maximum, minLength and
+// maxLength, and minItems and maxItems. Other keywords are ignored. A null
+// optional argument counts as left out, since models often send one.
+func validateArguments(schema map[string]interface{}, arguments map[string]interface{}) []argumentProblem {
+	if len(schema) == 0 {
+		return nil
+	}
+	if arguments == nil {
+		arguments = map[string]interface{}{}
+	}
+	var problems []argumentProblem
+	validateValue(schema, arguments, "", &problems)
+	return problems
+}
+
+// validateValue checks one value against its schema, adding any problems found.
+func validateValue(schema map[string]interface{}, value interface{}, path string, problems *[]argumentProblem) {
+	add := func(format string, args ...interface{}) {
+		*problems = append(*problems, argumentProblem{Path: path, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if options := schemaList(schema["anyOf"]); len(options) > 0 {
+		if !matchesAny(options, value, path) {
+			add("matches none of the allowed forms")
+		}
+		return
+	}
+
+	if types := schemaTypes(schema["type"]); len(types) > 0 {
+		actual := jsonType(value)
+		if !typeAllowed(types, actual, value) {
+			add("must be %s (got %s)", strings.Join(types, " or "), actual)
+			return
+		}
+	}
+
+	if options := schemaList(schema["enum"]); len(options) > 0 {
+		found := false
+		for _, option := range options {
+			if fmt.Sprint(option) == fmt.Sprint(value) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			quoted := make([]string, len(options))
+			for i, option := range options {
+				quoted[i] = fmt.Sprintf("%q", fmt.Sprint(option))
+			}
+			add("must be one of %s (got %q)", strings.Join(quoted, ", "), fmt.Sprint(value))
+			return
+		}
+	}
+
+	switch jsonType(value) {
+	case "string":
+		length := len([]rune(reflect.ValueOf(value).String()))
+		if limit, ok := schemaNumber(schema["minLength"]); ok && float64(length) < limit {
+			add("must be at least %v characters", limit)
+		}
+		if limit, ok := schemaNumber(schema["maxLength"]); ok && float64(length) > limit {
+			add("must be at most %v characters", limit)
+		}
+	case "number", "integer":
+		n, _ := number(value)
+		if limit, ok := schemaNumber(schema["minimum"]); ok && n < limit {
+			add("must be at least %v (got %v)", limit, n)
+		}
+		if limit, ok := schemaNumber(schema["maximum"]); ok && n > limit {
+			add("must be at most %v (got %v)", limit, n)
+		}
+	case "array":
+		items := reflect.ValueOf(value)
+		if limit, ok := schemaNumber(schema["minItems"]); ok && float64(items.Len()) < limit {
+			add("must have at least %v items", limit)
+		}
+		if limit, ok := schemaNumber(schema["maxItems"]); ok && float64(items.Len()) > limit {
+			add("must have at most %v items", limit)
+		}
+		if itemSchema, ok := schema["items"].(map[string]interface{}); ok {
+			for i := 0; i < items.Len(); i++ {
+				validateValue(itemSchema, items.Index(i).Interface(), fmt.Sprintf("%s[%d]", path, i), problems)
+			}
+		}
+	case "object":
+		validateObject(schema, value, path, problems)
+	}
+}
+
+// validateObject checks an object's required, declared and additional properties.
+func validateObject(schema map[string]interface{}, value interface{}, path string, problems *[]argumentProblem) {
+	fields := make(map[string]interface{})
+	iter := reflect.ValueOf(value).MapRange()
+	for iter.Next() {
+		fields[iter.Key().String()] = iter.Value().Interface()
+	}
+	properties, _ := schema["properties"].(map[string]interface{})
+
+	for _, name := range schemaList(schema["required"]) {
+		key := fmt.Sprint(name)
+		if field, ok := fields[key]; !ok || field == nil {
+			*problems = append(*problems, argumentProblem{Path: joinPath(path, key), Message: "is required"})
+		}
+	}
+
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		field := fields[key]
+		if field == nil {
+			continue
+		}
+		if propertySchema, ok := properties[key].(map[string]interface{}); ok {
+			validateValue(propertySchema, field, joinPath(path, key), problems)
+			continue
+		}
+		if _, declared := properties[key]; declared {
+			continue
+		}
+		switch additional := schema["additionalProperties"].(type) {
+		case bool:
+			if !additional {
+				*problems = append(*problems, argumentProblem{Path: joinPath(path, key), Message: "is not a known argument"})
+			}
+		case map[string]interface{}:
+			validateValue(additional, field, joinPath(path, key), problems)
+		}
+	}
+}
+
+// matchesAny reports whether a value satisfies at least one of the schemas.
+func matchesAny(options []interface{}, value interface{}, path string) bool {
+	for _, option := range options {
+		optionSchema, ok := option.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		var problems []argumentProblem
+		validateValue(optionSchema, value, path, &problems)
+		if len(problems) == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// joinPath names a property within an argument path.
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// jsonType names the JSON type of a decoded value.
+func jsonType(value interface{}) string {
+	if value == nil {
+		return "null"
+	}
+	if _, ok := number(value); ok {
+		return "number"
+	}
+	switch reflect.ValueOf(value).Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	case reflect.Map:
+		if reflect.TypeOf(value).Key().Kind() == reflect.String {
+			return "object"
+		}
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+// typeAllowed reports whether a value of a JSON type satisfies the schema types.
+// Integers are numbers with no fractional part.
+func typeAllowed(types []string, actual string, value interface{}) bool {
+	for _, allowed := range types {
+		if allowed == actual {
+			return true
+		}
+		if allowed == "integer" && actual == "number" {
+			n, _ := number(value)
+			if n == math.Trunc(n) && !math.IsInf(n, 0) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// number converts a numeric value to float64.
+func number(value interface{}) (float64, bool) {
+	if n, ok := value.(json.Number); ok {
+		f, err := n.Float64()
+		return f, err == nil
+	}
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), true
+	}
+	return 0, false
+}
+
+// schemaTypes reads a schema's type keyword, a name or a list of names.
+func schemaTypes(keyword interface{}) []string {
+	if name, ok := keyword.(string); ok {
+		return []string{name}
+	}
+	var types []string
+	for _, name := range schemaList(keyword) {
+		types = append(types, fmt.Sprint(name))
+	}
+	return types
+}
+
+// schemaList reads a list keyword such as enum or required, which schemas
+// written in Go may hold as any kind of slice.
+func schemaList(keyword interface{}) []interface{} {
+	v := reflect.ValueOf(keyword)
+	if keyword == nil || v.Kind() != reflect.Slice {
+		return nil
+	}
+	list := make([]interface{}, v.Len())
+	for i := range list {
+		list[i] = v.Index(i).Interface()
+	}
+	return list
+}
+
+// schemaNumber reads a numeric keyword such as minimum or maxItems.
+func schemaNumber(keyword interface{}) (float64, bool) {
+	if keyword == nil {
+		return 0, false
+	}
+	return number(keyword)
+}
@@ -0,0 +1,104 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateArguments(t *testing.T) {
+	schema := map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"goal_name": map[string]interface{}{"type": "string"},
+			"vote":      map[string]interface{}{"type": "string", "enum": []string{"yes", "no"}},
+			"change":    map[string]interface{}{"type": "integer", "minimum": -3, "maximum": 3},
+			"ranking":   map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}},
+			"allocation": map[string]interface{}{
+				"type":                 "object",
+				"additionalProperties": map[string]interface{}{"type": "number"},
+			},
+		},
+		"required": []string{"goal_name", "vote"},
+	}
+
+	tests := []struct {
+		name      string
+		arguments map[string]interface{}
+		want      []argumentProblem
+	}{
+		{
+			name:      "valid",
+			arguments: map[string]interface{}{"goal_name": "dinner", "vote": "yes", "change": 2.0, "ranking": []interface{}{"p1", "p2"}},
+		},
+		{
+			name:      "null optional arguments count as left out",
+			arguments: map[string]interface{}{"goal_name": "dinner", "vote": "no", "change": nil},
+		},
+		{
+			name:      "unknown arguments are allowed by default",
+			arguments: map[string]interface{}{"goal_name": "dinner", "vote": "no", "mood": "grumpy"},
+		},
+		{
+			name:      "missing required arguments",
+			arguments: map[string]interface{}{"goal_name": nil},
+			want:      []argumentProblem{{"goal_name", "is required"}, {"vote", "is required"}},
+		},
+		{
+			name:      "wrong types",
+			arguments: map[string]interface{}{"goal_name": 7.0, "vote": "yes", "change": 1.5, "ranking": "p1"},
+			want: []argumentProblem{
+				{"change", "must be integer (got number)"},
+				{"goal_name", "must be string (got number)"},
+				{"ranking", "must be array (got string)"},
+			},
+		},
+		{
+			name:      "enums, bounds and nested values",
+			arguments: map[string]interface{}{"goal_name": "dinner", "vote": "maybe", "change": 5, "ranking": []interface{}{"p1", 2.0}, "allocation": map[string]interface{}{"ops": "half"}},
+			want: []argumentProblem{
+				{"allocation.ops", "must be number (got string)"},
+				{"change", "must be at most 3 (got 5)"},
+				{"ranking[1]", "must be string (got number)"},
+				{"vote", `must be one of "yes", "no" (got "maybe")`},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, validateArguments(schema, tt.arguments))
+		})
+	}
+}
+
+func TestExecuteToolValidatesArguments(t *testing.T) {
+	server := NewServer("test", "1.0.0")
+	called := false
+	server.RegisterTool(&Tool{
+		Name: "speak",
+		InputSchema: map[string]interface{}{
+			"type":       "object",
+			"properties": map[string]interface{}{"message": map[string]interface{}{"type": "string"}},
+			"required":   []string{"message"},
+		},
+		Handler: func(ctx context.Context, arguments map[string]interface{}) (interface{}, error) {
+			called = true
+			return "ok", nil
+		},
+		EndsTurn: true,
+	})
+
+	result := server.ExecuteTool(context.Background(), &ToolCall{ID: "1", Name: "speak", Arguments: map[string]interface{}{"message": 42.0}})
+	assert.True(t, result.IsError)
+	assert.False(t, result.EndsTurn, "the model gets to correct the call")
+	assert.False(t, called)
+	assert.Equal(t, "invalid arguments for speak:\n- message: must be string (got number)\nFix these and call speak again.", result.Content)
+
+	result = server.ExecuteTool(context.Background(), &ToolCall{ID: "2", Name: "speak", Arguments: map[string]interface{}{"message": "Hi"}})
+	require.False(t, result.IsError)
+	assert.True(t, result.EndsTurn)
+	assert.True(t, called)
+}
@@ -51,7 +51,8 @@ func (s *Server) GetTool(name string) (*Tool, error) {
 	return tool, nil
 }
 
-// ExecuteTool executes a tool with the given arguments.
+// ExecuteTool executes a tool with the given arguments, once they have been
+// checked against the tool's input schema.
 func (s *Server) ExecuteTool(ctx context.Context, toolCall *ToolCall) *ToolResult {
 	tool, err := s.GetTool(toolCall.Name)
 	if err != nil {
@@ -63,6 +64,17 @@ func (s *Server) ExecuteTool(ctx context.Context, toolCall *ToolCall) *ToolResul
 		}
 	}
 
+	// Arguments that don't match the schema never reach the handler, and don't
+	// end the turn, so the model can correct the call and try again
+	if problems := validateArguments(tool.InputSchema, toolCall.Arguments); len(problems) > 0 {
+		return &ToolResult{
+			ToolCallID: toolCall.ID,
+			Content:    (&argumentError{tool: tool.Name, problems: problems}).Error(),
+			IsError:    true,
+			EndsTurn:   false,
+		}
+	}
+
 	result, err := tool.Handler(ctx, toolCall.Arguments)
 	if err != nil {
 		return &ToolResult{
@@ -0,0 +1,101 @@
+package simulation
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/poiesic/wonda/internal/expr"
+)
+
+// allocationTolerance absorbs floating point error when comparing allocation totals.
+const allocationTolerance = 1e-9
+
+// AllocationRules describe how an allocation goal's resource may be divided.
+type AllocationRules struct {
+	Resource       string       // What is being divided (e.g. "dollars")
+	Total          float64      // Amount to divide
+	Recipients     []string     // Who or what gets a share, in display order
+	Constraints    []*expr.Expr // Rules over the shares (by recipient name) and "total"
+	AllowRemainder bool         // Part of the total may be left unallocated
+}
+
+// Check returns the reasons an allocation breaks the rules; none means it is valid.
+// Recipients missing from the allocation get nothing.
+func (r *AllocationRules) Check(allocation map[string]float64) []string {
+	var violations []string
+
+	known := make(map[string]bool, len(r.Recipients))
+	for _, recipient := range r.Recipients {
+		known[recipient] = true
+	}
+	for recipient := range allocation {
+		if !known[recipient] {
+			violations = append(violations, fmt.Sprintf("unknown recipient: %s", recipient))
+		}
+	}
+
+	variables := map[string]float64{"total": r.Total}
+	sum := 0.0
+	for _, recipient := range r.Recipients {
+		share := allocation[recipient]
+		if share < 0 {
+			violations = append(violations, fmt.Sprintf("%s's share must not be negative", recipient))
+		}
+		variables[recipient] = share
+		sum += share
+	}
+
+	switch {
+	case sum > r.Total+allocationTolerance:
+		violations = append(violations, fmt.Sprintf("allocates %s %s but only %s are available", formatAmount(sum), r.Resource, formatAmount(r.Total)))
+	case !r.AllowRemainder && sum < r.Total-allocationTolerance:
+		violations = append(violations, fmt.Sprintf("allocates %s %s but all %s must be allocated", formatAmount(sum), r.Resource, formatAmount(r.Total)))
+	}
+
+	for _, constraint := range r.Constraints {
+		satisfied, err := constraint.Bool(variables)
+		if err != nil {
+			slog.Warn("allocation constraint failed", "constraint", constraint.String(), "error", err)
+			satisfied = false
+		}
+		if !satisfied {
+			violations = append(violations, fmt.Sprintf("breaks constraint: %s", constraint.String()))
+		}
+	}
+	return violations
+}
+
+// Format describes an allocation in recipient order, e.g. "Alice: 60, Bob: 40 (dollars)".
+func (r *AllocationRules) Format(allocation map[string]float64) string {
+	parts := make([]string, 0, len(r.Recipients))
+	for _, recipient := range r.Recipients {
+		parts = append(parts, fmt.Sprintf("%s: %s", recipient, formatAmount(allocation[recipient])))
+	}
+	return fmt.Sprintf("%s (%s)", strings.Join(parts, ", "), r.Resource)
+}
+
+// ParseAllocation converts a tool argument into shares by recipient.
+func ParseAllocation(argument interface{}) (map[string]float64, error) {
+	raw, ok := argument.(map[string]interface{})
+	if !ok || len(raw) == 0 {
+		return nil, fmt.Errorf("allocation is required and must map each recipient to their share")
+	}
+
+	allocation := make(map[string]float64, len(raw))
+	for recipient, value := range raw {
+		share, ok := value.(float64)
+		if !ok || math.IsNaN(share) || math.IsInf(share, 0) {
+			return nil, fmt.Errorf("share for %s must be a number", recipient)
+		}
+		allocation[recipient] = share
+	}
+	return allocation, nil
+}
+
+// formatAmount prints whole amounts without a decimal point.
+func formatAmount(amount float64) string {
+	return strconv.FormatFloat(amount, 'f', -1, 64)
+}
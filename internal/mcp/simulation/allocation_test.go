@@ -0,0 +1,132 @@
+package simulation
+
+import (
+	"testing"
+
+	"github.com/poiesic/wonda/internal/expr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAllocationGoal(t *testing.T) {
+	newAllocationWorld := func(t *testing.T) *WorldState {
+		constraint, err := expr.Compile("agent0 >= 20", []string{"total", "agent0", "agent1"})
+		require.NoError(t, err)
+
+		world := newTestWorld(2)
+		goal := NewInteractiveGoal("budget", "Split the budget", "allocation", 1)
+		goal.Allocation = &AllocationRules{
+			Resource:    "dollars",
+			Total:       100,
+			Recipients:  []string{"agent0", "agent1"},
+			Constraints: []*expr.Expr{constraint},
+		}
+		world.AddGoal(goal)
+		return world
+	}
+
+	propose := func(world *WorldState, allocation map[string]interface{}) (interface{}, error) {
+		return NewProposeSolutionTool(world).Handler(agentContext("agent0"), map[string]interface{}{
+			"goal_name":  "budget",
+			"allocation": allocation,
+			"comment":    "Let's split it like this.",
+		})
+	}
+
+	t.Run("refuses allocations that break the rules", func(t *testing.T) {
+		world := newAllocationWorld(t)
+
+		_, err := propose(world, map[string]interface{}{"agent0": 10.0, "agent1": 90.0})
+		assert.ErrorContains(t, err, "agent0 >= 20")
+		_, err = propose(world, map[string]interface{}{"agent0": 50.0, "agent1": 40.0})
+		assert.ErrorContains(t, err, "all 100 must be allocated")
+		_, err = propose(world, map[string]interface{}{"agent0": 50.0, "nobody": 50.0})
+		assert.ErrorContains(t, err, "unknown recipient")
+		assert.Empty(t, world.Snapshot().PendingDialogue, "refused proposals say nothing")
+	})
+
+	t.Run("completes when a valid allocation is accepted", func(t *testing.T) {
+		world := newAllocationWorld(t)
+
+		result, err := propose(world, map[string]interface{}{"agent0": 60.0, "agent1": 40.0})
+		require.NoError(t, err)
+		proposalID := result.(map[string]interface{})["proposal_id"].(string)
+
+		_, err = NewVoteOnProposalTool(world).Handler(agentContext("agent1"), map[string]interface{}{
+			"goal_name":   "budget",
+			"proposal_id": proposalID,
+			"vote":        "yes",
+			"comment":     "Fine by me.",
+		})
+		require.NoError(t, err)
+
+		goal := world.Snapshot().Goals["budget"]
+		assert.Equal(t, GoalCompleted, goal.Status)
+		assert.Equal(t, map[string]float64{"agent0": 60, "agent1": 40}, goal.Proposals[proposalID].Allocation)
+		assert.Equal(t, "agent0: 60, agent1: 40 (dollars)", goal.Proposals[proposalID].Description)
+	})
+}
+
+func TestAllocationRules(t *testing.T) {
+	constraint, err := expr.Compile("engineering >= 0.4 * total", []string{"total", "marketing", "engineering"})
+	require.NoError(t, err)
+	rules := &AllocationRules{
+		Resource:    "dollars",
+		Total:       100,
+		Recipients:  []string{"marketing", "engineering"},
+		Constraints: []*expr.Expr{constraint},
+	}
+
+	tests := []struct {
+		name           string
+		allocation     map[string]float64
+		allowRemainder bool
+		want           []string
+	}{
+		{name: "valid", allocation: map[string]float64{"marketing": 60, "engineering": 40}},
+		{name: "fractional shares", allocation: map[string]float64{"marketing": 33.3, "engineering": 66.7}},
+		{
+			name:       "over-allocation",
+			allocation: map[string]float64{"marketing": 70, "engineering": 50},
+			want:       []string{"allocates 120 dollars but only 100 are available"},
+		},
+		{
+			name:           "over-allocation with a remainder allowed",
+			allocation:     map[string]float64{"marketing": 70, "engineering": 50},
+			allowRemainder: true,
+			want:           []string{"allocates 120 dollars but only 100 are available"},
+		},
+		{
+			name:       "under-allocation",
+			allocation: map[string]float64{"marketing": 10, "engineering": 50},
+			want:       []string{"allocates 60 dollars but all 100 must be allocated"},
+		},
+		{
+			name:           "remainder allowed",
+			allocation:     map[string]float64{"marketing": 10, "engineering": 50},
+			allowRemainder: true,
+		},
+		{
+			name:       "unknown recipient",
+			allocation: map[string]float64{"marketing": 50, "engineering": 40, "support": 10},
+			want:       []string{"unknown recipient: support", "allocates 90 dollars but all 100 must be allocated"},
+		},
+		{
+			name:       "negative share",
+			allocation: map[string]float64{"marketing": -20, "engineering": 120},
+			want:       []string{"marketing's share must not be negative"},
+		},
+		{
+			name:       "missing recipients get nothing",
+			allocation: map[string]float64{"marketing": 100},
+			want:       []string{"breaks constraint: engineering >= 0.4 * total"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rules.AllowRemainder = tt.allowRemainder
+			assert.Equal(t, tt.want, rules.Check(tt.allocation))
+		})
+	}
+}
@@ -0,0 +1,141 @@
+package simulation
+
+import (
+	"fmt"
+	"time"
+)
+
+// WorldCheckpoint is the progress a world has made, in a form that can be
+// serialized and restored onto a fresh world built from the same scenario.
+// Goal rules (consensus expressions, allocation constraints) aren't part of it;
+// they are rebuilt from the scenario, and only what agents changed is carried over.
+type WorldCheckpoint struct {
+	Turn            int
+	Elapsed         time.Duration // Run time at the end of the turn, so deadlines carry over
+	Atmosphere      string
+	ReasoningShared bool
+	Agents          []AgentInWorld
+	Conversation    []ConversationMessage
+	Whispers        []ConversationMessage
+	Objects         []WorldObject
+	Goals           []GoalProgress
+	Commitments     []Commitment
+	Relationships   []RelationshipProgress
+}
+
+// GoalProgress is the part of a goal that changes as the simulation runs.
+type GoalProgress struct {
+	Name        string
+	Status      GoalStatus
+	CompletedAt int
+	Proposals   map[string]*Proposal
+	Confidence  float64
+	Assessment  string
+	Completions map[string]*IndividualCompletion
+}
+
+// RelationshipProgress is a relationship and whether it changed during the simulation.
+type RelationshipProgress struct {
+	Relationship
+	Changed bool `json:"changed,omitempty"`
+}
+
+// Checkpoint returns the world's progress at the end of the current turn.
+func (w *WorldState) Checkpoint() WorldCheckpoint {
+	snapshot := w.Snapshot()
+
+	checkpoint := WorldCheckpoint{
+		Turn:            snapshot.CurrentTurn,
+		Elapsed:         snapshot.Elapsed,
+		Atmosphere:      snapshot.Atmosphere,
+		ReasoningShared: snapshot.ReasoningShared,
+		Conversation:    snapshot.ConversationHistory,
+		Whispers:        snapshot.Whispers,
+		Commitments:     snapshot.Commitments,
+	}
+	for _, agent := range snapshot.Agents {
+		checkpoint.Agents = append(checkpoint.Agents, *agent)
+	}
+	for _, object := range snapshot.Objects {
+		checkpoint.Objects = append(checkpoint.Objects, *object)
+	}
+	for _, goal := range snapshot.Goals {
+		checkpoint.Goals = append(checkpoint.Goals, GoalProgress{
+			Name:        goal.Name,
+			Status:      goal.Status,
+			CompletedAt: goal.CompletedAt,
+			Proposals:   goal.Proposals,
+			Confidence:  goal.Confidence,
+			Assessment:  goal.Assessment,
+			Completions: goal.Completions,
+		})
+	}
+	for _, rel := range snapshot.Relationships {
+		checkpoint.Relationships = append(checkpoint.Relationships, RelationshipProgress{
+			Relationship: *rel,
+			Changed:      rel.Changed,
+		})
+	}
+	return checkpoint
+}
+
+// Restore applies a checkpoint to a world whose agents and goals have been set
+// up from the same scenario. Agents and goals the world doesn't know are an error,
+// since the scenario must have changed since the checkpoint was taken.
+func (w *WorldState) Restore(checkpoint WorldCheckpoint) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for _, agent := range checkpoint.Agents {
+		if _, ok := w.Agents[agent.Name]; !ok {
+			return fmt.Errorf("checkpoint agent %s is not in the scenario", agent.Name)
+		}
+	}
+	for _, progress := range checkpoint.Goals {
+		if _, ok := w.Goals[progress.Name]; !ok {
+			return fmt.Errorf("checkpoint goal %s is not in the scenario", progress.Name)
+		}
+	}
+	for _, object := range checkpoint.Objects {
+		if _, ok := w.Objects[object.Name]; !ok {
+			return fmt.Errorf("checkpoint object %s is not in the scenario", object.Name)
+		}
+	}
+
+	w.CurrentTurn = checkpoint.Turn
+	w.Elapsed = checkpoint.Elapsed
+	if checkpoint.Atmosphere != "" {
+		w.Atmosphere = checkpoint.Atmosphere
+	}
+	w.ReasoningShared = checkpoint.ReasoningShared
+	for _, agent := range checkpoint.Agents {
+		restored := agent
+		w.Agents[agent.Name] = &restored
+	}
+	w.ConversationHistory = append([]ConversationMessage(nil), checkpoint.Conversation...)
+	w.Whispers = append([]ConversationMessage(nil), checkpoint.Whispers...)
+	for _, object := range checkpoint.Objects {
+		restored := object
+		w.Objects[object.Name] = &restored
+	}
+	for _, progress := range checkpoint.Goals {
+		goal := w.Goals[progress.Name]
+		goal.Status = progress.Status
+		goal.CompletedAt = progress.CompletedAt
+		goal.Proposals = progress.Proposals
+		if goal.Proposals == nil {
+			goal.Proposals = make(map[string]*Proposal)
+		}
+		goal.Confidence = progress.Confidence
+		goal.Assessment = progress.Assessment
+		goal.Completions = progress.Completions
+	}
+	w.Commitments = append([]Commitment(nil), checkpoint.Commitments...)
+	w.Relationships = make(map[relationshipKey]*Relationship, len(checkpoint.Relationships))
+	for _, progress := range checkpoint.Relationships {
+		rel := progress.Relationship
+		rel.Changed = progress.Changed
+		w.Relationships[relationshipKey{rel.From, rel.To}] = &rel
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+package simulation
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/poiesic/wonda/internal/mcp"
+	"github.com/poiesic/wonda/internal/runtime"
+)
+
+// NewListCommitmentsTool creates the list_commitments MCP tool.
+// Allows agents to review what the group has agreed to so far.
+func NewListCommitmentsTool(world *WorldState) *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "list_commitments",
+		Description: "List the agreements the group has made so far, who agreed, and who objected",
+		InputSchema: map[string]interface{}{
+			"type":       "object",
+			"properties": map[string]interface{}{},
+			"required":   []string{},
+		},
+		Handler: func(ctx context.Context, arguments map[string]interface{}) (interface{}, error) {
+			agentName, _ := ctx.Value(runtime.AgentNameKey).(string)
+
+			snapshot := world.Snapshot()
+			commitments := make([]map[string]interface{}, 0, len(snapshot.Commitments))
+			for _, commitment := range snapshot.Commitments {
+				commitments = append(commitments, map[string]interface{}{
+					"goal":         commitment.Goal,
+					"description":  commitment.Description,
+					"proposed_by":  commitment.ProposedBy,
+					"agreed":       commitment.Agreed,
+					"objected":     commitment.Objected,
+					"agreed_at":    commitment.AgreedAt,
+					"you_agreed":   slices.Contains(commitment.Agreed, agentName),
+					"status":       commitment.Status,
+					"fulfilled_by": commitment.FulfilledBy,
+				})
+			}
+			return map[string]interface{}{
+				"commitments":  commitments,
+				"current_turn": snapshot.CurrentTurn,
+			}, nil
+		},
+	}
+}
+
+// NewFulfillCommitmentTool creates the fulfill_commitment MCP tool.
+// Allows agents to report that the group followed through on an agreement.
+func NewFulfillCommitmentTool(world *WorldState) *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "fulfill_commitment",
+		Description: "Report that an agreement the group made has been carried out",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"goal_name": map[string]interface{}{
+					"type":        "string",
+					"description": "The goal whose agreement was carried out",
+				},
+				"comment": map[string]interface{}{
+					"type":        "string",
+					"description": "What you say about how the agreement was carried out",
+				},
+			},
+			"required": []string{"goal_name", "comment"},
+		},
+		Handler: func(ctx context.Context, arguments map[string]interface{}) (interface{}, error) {
+			agentName, ok := ctx.Value(runtime.AgentNameKey).(string)
+			if !ok || agentName == "" {
+				return nil, fmt.Errorf("agent_name not found in context")
+			}
+			goalName, ok := arguments["goal_name"].(string)
+			if !ok || goalName == "" {
+				return nil, fmt.Errorf("goal_name is required")
+			}
+			comment, ok := arguments["comment"].(string)
+			if !ok || comment == "" {
+				return nil, fmt.Errorf("comment is required")
+			}
+
+			commitment, err := world.FulfillCommitment(goalName, agentName)
+			if err != nil {
+				return nil, err
+			}
+			world.AddPendingDialogue(agentName, comment, MessageTypeDialogue)
+
+			return map[string]interface{}{
+				"success": true,
+				"message": fmt.Sprintf("The agreement to %s has been carried out", commitment.Description),
+			}, nil
+		},
+	}
+}
+
+// FulfillCommitment marks the open commitment made for a goal as fulfilled by an agent.
+func (w *WorldState) FulfillCommitment(goalName, agentName string) (Commitment, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for i := range w.Commitments {
+		commitment := &w.Commitments[i]
+		if commitment.Goal != goalName {
+			continue
+		}
+		if commitment.Status != CommitmentOpen {
+			return *commitment, fmt.Errorf("the commitment for goal %s is already %s", goalName, commitment.Status)
+		}
+		commitment.Status = CommitmentFulfilled
+		commitment.FulfilledBy = agentName
+		commitment.ResolvedAt = w.CurrentTurn
+		return *commitment, nil
+	}
+	return Commitment{}, fmt.Errorf("no commitment was made for goal %s", goalName)
+}
+
+// ExpireCommitments marks every open commitment as expired at the given turn,
+// returning the commitments that expired.
+func (w *WorldState) ExpireCommitments(turn int) []Commitment {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	var expired []Commitment
+	for i := range w.Commitments {
+		commitment := &w.Commitments[i]
+		if commitment.Status == CommitmentOpen {
+			commitment.Status = CommitmentExpired
+			commitment.ResolvedAt = turn
+			expired = append(expired, *commitment)
+		}
+	}
+	return expired
+}
@@ -0,0 +1,91 @@
+package simulation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommitments(t *testing.T) {
+	newCommitmentWorld := func() *WorldState {
+		world := newTestWorld(3)
+		world.AddCommitment(Commitment{
+			Goal:        "dinner",
+			Description: "Dinner at Luigi's",
+			ProposedBy:  "agent0",
+			Agreed:      []string{"agent0", "agent1"},
+			Objected:    []string{"agent2"},
+			AgreedAt:    1,
+		})
+		world.SetTurn(2)
+		return world
+	}
+	fulfill := func(world *WorldState, agentName string, arguments map[string]interface{}) (interface{}, error) {
+		return NewFulfillCommitmentTool(world).Handler(agentContext(agentName), arguments)
+	}
+
+	t.Run("records commitments as open", func(t *testing.T) {
+		world := newCommitmentWorld()
+
+		result, err := NewListCommitmentsTool(world).Handler(agentContext("agent2"), map[string]interface{}{})
+		require.NoError(t, err)
+		listed := result.(map[string]interface{})["commitments"].([]map[string]interface{})
+		require.Len(t, listed, 1)
+		assert.Equal(t, "Dinner at Luigi's", listed[0]["description"])
+		assert.Equal(t, CommitmentOpen, listed[0]["status"])
+		assert.Equal(t, false, listed[0]["you_agreed"])
+	})
+
+	t.Run("fulfills a commitment", func(t *testing.T) {
+		world := newCommitmentWorld()
+
+		result, err := fulfill(world, "agent1", map[string]interface{}{"goal_name": "dinner", "comment": "I booked the table."})
+		require.NoError(t, err)
+		assert.Equal(t, "The agreement to Dinner at Luigi's has been carried out", result.(map[string]interface{})["message"])
+
+		snapshot := world.Snapshot()
+		commitment := snapshot.Commitments[0]
+		assert.Equal(t, CommitmentFulfilled, commitment.Status)
+		assert.Equal(t, "agent1", commitment.FulfilledBy)
+		assert.Equal(t, 2, commitment.ResolvedAt)
+		require.Len(t, snapshot.PendingDialogue, 1)
+		assert.Equal(t, "I booked the table.", snapshot.PendingDialogue[0].Content)
+
+		_, err = fulfill(world, "agent0", map[string]interface{}{"goal_name": "dinner", "comment": "Done."})
+		assert.ErrorContains(t, err, "the commitment for goal dinner is already fulfilled")
+		assert.Equal(t, "agent1", world.Snapshot().Commitments[0].FulfilledBy)
+	})
+
+	t.Run("refuses to fulfill unknown commitments", func(t *testing.T) {
+		world := newCommitmentWorld()
+
+		_, err := fulfill(world, "agent0", map[string]interface{}{"goal_name": "dessert", "comment": "Done."})
+		assert.ErrorContains(t, err, "no commitment was made for goal dessert")
+		_, err = fulfill(world, "agent0", map[string]interface{}{"goal_name": "dinner"})
+		assert.ErrorContains(t, err, "comment is required")
+		assert.Equal(t, CommitmentOpen, world.Snapshot().Commitments[0].Status)
+		assert.Empty(t, world.Snapshot().PendingDialogue)
+	})
+
+	t.Run("expires open commitments", func(t *testing.T) {
+		world := newCommitmentWorld()
+		world.AddCommitment(Commitment{Goal: "movie", Description: "See the new thriller", AgreedAt: 2})
+		_, err := fulfill(world, "agent0", map[string]interface{}{"goal_name": "movie", "comment": "Tickets bought."})
+		require.NoError(t, err)
+
+		expired := world.ExpireCommitments(5)
+		require.Len(t, expired, 1)
+		assert.Equal(t, "dinner", expired[0].Goal)
+
+		commitments := world.Snapshot().Commitments
+		assert.Equal(t, CommitmentExpired, commitments[0].Status)
+		assert.Equal(t, 5, commitments[0].ResolvedAt)
+		assert.Equal(t, CommitmentFulfilled, commitments[1].Status, "fulfilled commitments don't expire")
+		assert.Equal(t, 2, commitments[1].ResolvedAt)
+
+		assert.Empty(t, world.ExpireCommitments(6), "commitments expire once")
+		_, err = fulfill(world, "agent0", map[string]interface{}{"goal_name": "dinner", "comment": "Late, but done."})
+		assert.ErrorContains(t, err, "already expired")
+	})
+}
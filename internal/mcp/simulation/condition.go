@@ -0,0 +1,108 @@
+package simulation
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/poiesic/wonda/internal/mcp"
+	"github.com/poiesic/wonda/internal/runtime"
+)
+
+// ConditionChange records a change to an agent's condition and what caused it.
+type ConditionChange struct {
+	AgentName string
+	Before    int
+	After     int
+	Cause     string
+}
+
+// SetCondition sets an agent's starting condition without recording a change.
+func (w *WorldState) SetCondition(name string, condition int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if agent, ok := w.Agents[name]; ok {
+		agent.Condition = min(max(condition, 0), 100)
+	}
+}
+
+// Condition returns an agent's condition (0-100).
+func (w *WorldState) Condition(name string) int {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	if agent, ok := w.Agents[name]; ok {
+		return agent.Condition
+	}
+	return 0
+}
+
+// AdjustCondition changes an agent's condition by delta, clamped to 0-100,
+// and records the change for the simulation to chronicle.
+// It returns the new condition.
+func (w *WorldState) AdjustCondition(name string, delta int, cause string) int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	agent, ok := w.Agents[name]
+	if !ok {
+		return 0
+	}
+	before := agent.Condition
+	agent.Condition = min(max(before+delta, 0), 100)
+	if agent.Condition != before {
+		w.PendingConditionChanges = append(w.PendingConditionChanges, ConditionChange{
+			AgentName: name,
+			Before:    before,
+			After:     agent.Condition,
+			Cause:     cause,
+		})
+	}
+	return agent.Condition
+}
+
+// TakeConditionChanges returns the condition changes since the last call and clears them.
+func (w *WorldState) TakeConditionChanges() []ConditionChange {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	changes := w.PendingConditionChanges
+	w.PendingConditionChanges = nil
+	return changes
+}
+
+// RestResult contains confirmation of a rest.
+type RestResult struct {
+	Success   bool   `json:"success"`
+	Message   string `json:"message"`
+	Condition int    `json:"condition"`
+}
+
+// NewRestTool creates the rest() MCP tool.
+// Resting ends the agent's turn as a pass and restores some condition.
+func NewRestTool(world *WorldState, recovery int) *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "rest",
+		Description: "Take a moment to catch your breath instead of speaking. You sit this turn out but recover some of your strength.",
+		EndsTurn:    true,
+		InputSchema: map[string]interface{}{
+			"type":       "object",
+			"properties": map[string]interface{}{},
+		},
+		Handler: func(ctx context.Context, arguments map[string]interface{}) (interface{}, error) {
+			agentName, ok := ctx.Value(runtime.AgentNameKey).(string)
+			if !ok || agentName == "" {
+				return nil, fmt.Errorf("agent_name not found in context")
+			}
+
+			condition := world.AdjustCondition(agentName, recovery, "rested")
+			world.AddPendingDialogue(agentName, "Resting.", MessageTypePass)
+
+			return &RestResult{
+				Success:   true,
+				Message:   "You rest for a moment",
+				Condition: condition,
+			}, nil
+		},
+	}
+}
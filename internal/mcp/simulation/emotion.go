@@ -0,0 +1,128 @@
+package simulation
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/poiesic/wonda/internal/mcp"
+	"github.com/poiesic/wonda/internal/runtime"
+)
+
+// Emotions are the emotions agents can feel.
+var Emotions = []string{"neutral", "happy", "sad", "angry", "afraid"}
+
+// maxEmotionShift caps how far an agent's emotional intensity can move in one update.
+const maxEmotionShift = 4
+
+// SetEmotion sets an agent's starting emotional state without recording a cause.
+func (w *WorldState) SetEmotion(name, emotion string, intensity int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if agent, ok := w.Agents[name]; ok {
+		agent.Emotion = emotion
+		agent.EmotionIntensity = min(max(intensity, 0), 10)
+	}
+}
+
+// Emotion returns an agent's emotion, its intensity (0-10) and what last
+// changed it, or "" for the cause if nothing has.
+func (w *WorldState) Emotion(name string) (string, int, string) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	if agent, ok := w.Agents[name]; ok {
+		return agent.Emotion, agent.EmotionIntensity, agent.EmotionCause
+	}
+	return "", 0, ""
+}
+
+// UpdateEmotion changes how an agent feels and records why. Intensity is
+// clamped to 0-10, and may move at most a few points from where it was so
+// moods shift rather than flip.
+func (w *WorldState) UpdateEmotion(name, emotion string, intensity int, cause string) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	agent, ok := w.Agents[name]
+	if !ok {
+		return 0, fmt.Errorf("agent %s not found in world", name)
+	}
+	if !slices.Contains(Emotions, emotion) {
+		return 0, fmt.Errorf("unknown emotion: %s (use one of %s)", emotion, strings.Join(Emotions, ", "))
+	}
+
+	intensity = min(max(intensity, 0), 10)
+	intensity = min(max(intensity, agent.EmotionIntensity-maxEmotionShift), agent.EmotionIntensity+maxEmotionShift)
+	agent.Emotion = emotion
+	agent.EmotionIntensity = intensity
+	agent.EmotionCause = cause
+	return intensity, nil
+}
+
+// UpdateEmotionResult confirms an agent's new emotional state.
+type UpdateEmotionResult struct {
+	Success   bool   `json:"success"`
+	Emotion   string `json:"emotion"`
+	Intensity int    `json:"intensity"`
+	Message   string `json:"message"`
+}
+
+// NewUpdateEmotionTool creates the update_emotion() MCP tool.
+// This tool allows agents to say how what just happened made them feel.
+func NewUpdateEmotionTool(world *WorldState) *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "update_emotion",
+		Description: "Record how you feel now, when something that happened or was said changed your mood. Your feelings carry into your next turns. Intensity moves at most 4 points at a time.",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"emotion": map[string]interface{}{
+					"type":        "string",
+					"enum":        Emotions,
+					"description": "How you feel",
+				},
+				"intensity": map[string]interface{}{
+					"type":        "integer",
+					"minimum":     0,
+					"maximum":     10,
+					"description": "How strongly you feel it, from 0 (barely) to 10 (overwhelmingly)",
+				},
+				"reason": map[string]interface{}{
+					"type":        "string",
+					"description": "What made you feel this way, in a few words",
+				},
+			},
+			"required": []string{"emotion", "intensity", "reason"},
+		},
+		Handler: func(ctx context.Context, arguments map[string]interface{}) (interface{}, error) {
+			agentName, ok := ctx.Value(runtime.AgentNameKey).(string)
+			if !ok || agentName == "" {
+				return nil, fmt.Errorf("agent_name not found in context")
+			}
+
+			emotion, ok := arguments["emotion"].(string)
+			if !ok || emotion == "" {
+				return nil, fmt.Errorf("emotion parameter is required and must be a string")
+			}
+			intensity, ok := arguments["intensity"].(float64)
+			if !ok {
+				return nil, fmt.Errorf("intensity parameter is required and must be a number")
+			}
+			reason, _ := arguments["reason"].(string)
+
+			applied, err := world.UpdateEmotion(agentName, emotion, int(intensity), strings.TrimSpace(reason))
+			if err != nil {
+				return nil, err
+			}
+			return &UpdateEmotionResult{
+				Success:   true,
+				Emotion:   emotion,
+				Intensity: applied,
+				Message:   fmt.Sprintf("You feel %s (%d/10)", emotion, applied),
+			}, nil
+		},
+	}
+}
@@ -0,0 +1,64 @@
+package simulation
+
+import (
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+)
+
+// ForbiddenOutcome is an outcome a scenario rules out for a goal, such as a
+// venue the characters can't go to. Proposals describing it are refused, and
+// it can never be accepted.
+type ForbiddenOutcome struct {
+	Phrases []string       // Forbidden if a proposal mentions any of these, ignoring case
+	Pattern *regexp.Regexp // Forbidden if a proposal matches (nil if unset)
+	Reason  string         // Why, told to agents who propose it
+}
+
+// Forbids reports whether a proposal's description describes the outcome.
+func (f *ForbiddenOutcome) Forbids(description string) bool {
+	lower := strings.ToLower(description)
+	for _, phrase := range f.Phrases {
+		if strings.Contains(lower, strings.ToLower(phrase)) {
+			return true
+		}
+	}
+	return f.Pattern != nil && f.Pattern.MatchString(description)
+}
+
+// ForbiddenReason returns why a proposal's description is ruled out for the
+// goal, or "" if it isn't.
+func (g *InteractiveGoal) ForbiddenReason(description string) string {
+	for _, outcome := range g.Forbidden {
+		if outcome.Forbids(description) {
+			return outcome.Reason
+		}
+	}
+	return ""
+}
+
+// checkForbidden refuses a proposal describing a forbidden outcome, telling
+// the agent why so they can propose something else.
+func (g *InteractiveGoal) checkForbidden(description string) error {
+	if reason := g.ForbiddenReason(description); reason != "" {
+		return fmt.Errorf("%q is ruled out: %s - propose something else", description, reason)
+	}
+	return nil
+}
+
+// EnforceForbidden rejects an accepted proposal that describes a forbidden
+// outcome, so no vote or consensus can settle on one. It returns false if the
+// proposal was rejected.
+func (g *InteractiveGoal) EnforceForbidden(p *Proposal, turn int) bool {
+	if p.Status != ProposalAccepted {
+		return true
+	}
+	if reason := g.ForbiddenReason(p.Description); reason != "" {
+		slog.Warn("accepted proposal is a forbidden outcome", "goal", g.Name, "proposal", p.ID, "reason", reason)
+		p.Status = ProposalRejected
+		p.ResolvedAt = turn
+		return false
+	}
+	return true
+}
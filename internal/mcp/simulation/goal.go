@@ -1,6 +1,16 @@
 package simulation
 
-import "fmt"
+import (
+	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/poiesic/wonda/internal/expr"
+)
 
 // GoalStatus represents the current state of a goal.
 type GoalStatus string
@@ -25,13 +35,54 @@ const (
 type InteractiveGoal struct {
 	Name        string
 	Description string
-	Type        string // "consensus", "individual", "majority"
+	Type        string // "consensus", "judged", "allocation", "majority", "weighted", "ranked", "negotiation"
 	Status      GoalStatus
 	Priority    int
 
 	// For consensus goals
 	Proposals   map[string]*Proposal
-	CompletedAt int // Turn number when completed
+	CompletedAt int        // Turn number when completed
+	Consensus   *expr.Expr // Acceptance rule over vote counts; nil means unanimous
+	Quorum      float64    // Without a rule, share of participants voting yes that accepts a proposal; 0 means unanimous
+
+	// For judged goals
+	Criteria   []string // Rubric the judge checks the transcript against
+	Threshold  float64  // Judge confidence needed to complete the goal
+	Confidence float64  // Judge's latest confidence that the criteria are met
+	Assessment string   // Judge's latest explanation
+
+	// For allocation goals (nil otherwise)
+	Allocation *AllocationRules
+
+	// For majority and weighted vote goals (nil otherwise)
+	Voting *VotingRules
+
+	// For negotiation goals (nil otherwise)
+	Negotiation *NegotiationRules
+
+	// For individual goals: each agent's completion, by agent name
+	Completions map[string]*IndividualCompletion
+
+	// Turn by which the goal must be completed, or it fails (0 if none)
+	MaxTurns int
+
+	// Run time within which the goal must be completed, or it fails (0 if none)
+	Deadline time.Duration
+
+	// Outcomes the scenario rules out; proposals describing them are refused
+	Forbidden []*ForbiddenOutcome
+
+	// Agents who may propose and vote; empty means every agent but observers
+	Assigned []string
+
+	// Topics the goal is about, used to boost related memories
+	Tags []string
+
+	// Each agent's ranking of the proposals (IDs, most preferred first) in a
+	// ranked-choice vote, whether that vote decided the goal, and its count
+	Rankings     map[string][]string
+	RankedChoice bool
+	Runoff       []RunoffRound
 }
 
 // Proposal represents a proposed solution to a goal.
@@ -42,7 +93,11 @@ type Proposal struct {
 	ProposedAt  int
 	Status      ProposalStatus
 	Votes       map[string]*Vote
-	ResolvedAt  int // Turn when status changed from pending
+	ResolvedAt  int                // Turn when status changed from pending
+	Allocation  map[string]float64 // Shares by recipient, for allocation goals
+	Counters    string             // Proposal this one counters, for negotiation goals
+	Amount      *float64           // The offer's term, for negotiation goals that name one
+	TieBreak    *TieBreak          // How a tied vote on it was broken, for voting goals with a tie-break policy
 }
 
 // Vote represents an agent's vote on a proposal.
@@ -64,6 +119,60 @@ func NewInteractiveGoal(name, description, goalType string, priority int) *Inter
 	}
 }
 
+// clone returns a deep copy of the goal, its proposals, their votes, and individual completions.
+func (g *InteractiveGoal) clone() *InteractiveGoal {
+	copied := *g
+	copied.Assigned = append([]string(nil), g.Assigned...)
+	copied.Tags = append([]string(nil), g.Tags...)
+	copied.Forbidden = append([]*ForbiddenOutcome(nil), g.Forbidden...)
+	if g.Rankings != nil {
+		copied.Rankings = make(map[string][]string, len(g.Rankings))
+		for agentName, ranking := range g.Rankings {
+			copied.Rankings[agentName] = append([]string(nil), ranking...)
+		}
+	}
+	if g.Runoff != nil {
+		copied.Runoff = make([]RunoffRound, len(g.Runoff))
+		for i, round := range g.Runoff {
+			copied.Runoff[i] = RunoffRound{Counts: maps.Clone(round.Counts), Eliminated: round.Eliminated}
+		}
+	}
+	if g.Completions != nil {
+		copied.Completions = make(map[string]*IndividualCompletion, len(g.Completions))
+		for agentName, completion := range g.Completions {
+			c := *completion
+			copied.Completions[agentName] = &c
+		}
+	}
+	if g.Voting != nil {
+		voting := *g.Voting
+		voting.Weights = maps.Clone(g.Voting.Weights)
+		voting.Order = append([]string(nil), g.Voting.Order...)
+		copied.Voting = &voting
+	}
+	if g.Negotiation != nil {
+		negotiation := *g.Negotiation
+		negotiation.Sides = maps.Clone(g.Negotiation.Sides)
+		copied.Negotiation = &negotiation
+	}
+	copied.Proposals = make(map[string]*Proposal, len(g.Proposals))
+	for id, proposal := range g.Proposals {
+		p := *proposal
+		p.Allocation = maps.Clone(proposal.Allocation)
+		if proposal.TieBreak != nil {
+			tieBreak := *proposal.TieBreak
+			p.TieBreak = &tieBreak
+		}
+		p.Votes = make(map[string]*Vote, len(proposal.Votes))
+		for agentName, vote := range proposal.Votes {
+			v := *vote
+			p.Votes[agentName] = &v
+		}
+		copied.Proposals[id] = &p
+	}
+	return &copied
+}
+
 // AddProposal adds a new proposal to this goal.
 func (g *InteractiveGoal) AddProposal(agentName, description string, turn int) string {
 	proposalID := fmt.Sprintf("proposal_%d", len(g.Proposals)+1)
@@ -98,21 +207,110 @@ func (g *InteractiveGoal) Vote(proposalID, agentName, choice string, turn int) e
 	return nil
 }
 
-// EvaluateProposal checks if a proposal should be accepted or rejected.
-// For consensus goals, all agents must vote yes for acceptance.
-func (p *Proposal) EvaluateStatus(totalAgents int, turn int) {
+// EvaluateProposal checks if a proposal should be accepted or rejected under
+// the goal's rules: a vote for voting goals, both sides' agreement for
+// negotiations, otherwise its consensus rule.
+func (g *InteractiveGoal) EvaluateProposal(p *Proposal, participants []string, turn int) {
+	if g.Voting != nil {
+		g.Voting.Evaluate(p, participants, turn)
+		return
+	}
+	if g.Negotiation != nil {
+		g.Negotiation.Evaluate(p, turn)
+		return
+	}
+	if g.Consensus == nil && g.Quorum > 0 && g.Quorum < 1 {
+		p.evaluateQuorum(len(participants), turn, g.Quorum)
+		return
+	}
+	p.EvaluateStatus(len(participants), turn, g.Consensus)
+}
+
+// evaluateQuorum accepts a proposal as soon as the share of participants
+// voting yes reaches the quorum, and rejects it once so many have voted no
+// that it never can.
+func (p *Proposal) evaluateQuorum(totalAgents int, turn int, quorum float64) {
+	if p.Status != ProposalPending || totalAgents == 0 {
+		return
+	}
+
+	yesVotes, noVotes := p.countVotes()
+	if p.Support(totalAgents) >= quorum {
+		p.Status = ProposalAccepted
+		p.ResolvedAt = turn
+	} else if float64(totalAgents-noVotes)/float64(totalAgents) < quorum || yesVotes+noVotes >= totalAgents {
+		p.Status = ProposalRejected
+		p.ResolvedAt = turn
+	}
+}
+
+// Support returns the share of a goal's participants who voted yes on the proposal.
+func (p *Proposal) Support(totalAgents int) float64 {
+	if totalAgents == 0 {
+		return 0
+	}
+	yesVotes, _ := p.countVotes()
+	return float64(yesVotes) / float64(totalAgents)
+}
+
+// EvaluateStatus checks if a proposal should be accepted or rejected.
+// Without a rule, all agents must vote yes for acceptance. With a rule, the proposal
+// is accepted as soon as the rule holds and rejected once everyone has voted.
+func (p *Proposal) EvaluateStatus(totalAgents int, turn int, rule *expr.Expr) {
 	if p.Status != ProposalPending {
 		return
 	}
 
+	if rule != nil {
+		p.evaluateRule(totalAgents, turn, rule)
+		return
+	}
+
 	// Check if all agents have voted
 	if len(p.Votes) < totalAgents {
 		return
 	}
 
 	// Count votes
-	yesVotes := 0
-	noVotes := 0
+	yesVotes, noVotes := p.countVotes()
+
+	// Determine outcome (unanimous yes required)
+	if yesVotes == totalAgents {
+		p.Status = ProposalAccepted
+		p.ResolvedAt = turn
+	} else if noVotes > 0 {
+		p.Status = ProposalRejected
+		p.ResolvedAt = turn
+	}
+}
+
+// evaluateRule resolves a proposal using a goal's consensus rule.
+func (p *Proposal) evaluateRule(totalAgents int, turn int, rule *expr.Expr) {
+	yesVotes, noVotes := p.countVotes()
+	accepted, err := rule.Bool(map[string]float64{
+		"yes":      float64(yesVotes),
+		"no":       float64(noVotes),
+		"voted":    float64(yesVotes + noVotes),
+		"pending":  float64(totalAgents - yesVotes - noVotes),
+		"assigned": float64(totalAgents),
+	})
+	if err != nil {
+		// A rule that can't be evaluated (e.g. division by zero) doesn't accept
+		slog.Warn("consensus rule failed", "proposal", p.ID, "error", err)
+		accepted = false
+	}
+
+	if accepted {
+		p.Status = ProposalAccepted
+		p.ResolvedAt = turn
+	} else if len(p.Votes) >= totalAgents {
+		p.Status = ProposalRejected
+		p.ResolvedAt = turn
+	}
+}
+
+// countVotes returns the number of yes and no votes.
+func (p *Proposal) countVotes() (yesVotes, noVotes int) {
 	for _, vote := range p.Votes {
 		switch vote.Choice {
 		case "yes":
@@ -121,15 +319,38 @@ func (p *Proposal) EvaluateStatus(totalAgents int, turn int) {
 			noVotes++
 		}
 	}
+	return yesVotes, noVotes
+}
 
-	// Determine outcome (unanimous yes required)
-	if yesVotes == totalAgents {
-		p.Status = ProposalAccepted
-		p.ResolvedAt = turn
-	} else if noVotes > 0 {
+// AddAllocationProposal adds a proposed division of an allocation goal's resource.
+// Allocations that break the goal's rules are refused with the reasons why.
+func (g *InteractiveGoal) AddAllocationProposal(agentName string, allocation map[string]float64, turn int) (string, error) {
+	if g.Allocation == nil {
+		return "", fmt.Errorf("goal %s does not take allocations", g.Name)
+	}
+	if violations := g.Allocation.Check(allocation); len(violations) > 0 {
+		return "", fmt.Errorf("invalid allocation: %s", strings.Join(violations, "; "))
+	}
+
+	proposalID := g.AddProposal(agentName, g.Allocation.Format(allocation), turn)
+	g.Proposals[proposalID].Allocation = allocation
+	return proposalID, nil
+}
+
+// EnforceAllocation rejects an accepted allocation proposal that breaks the
+// goal's rules, so acceptance requires both consensus and valid constraints.
+// It returns false if the proposal was rejected.
+func (g *InteractiveGoal) EnforceAllocation(p *Proposal, turn int) bool {
+	if g.Allocation == nil || p.Status != ProposalAccepted {
+		return true
+	}
+	if violations := g.Allocation.Check(p.Allocation); len(violations) > 0 {
+		slog.Warn("accepted allocation breaks constraints", "goal", g.Name, "proposal", p.ID, "violations", violations)
 		p.Status = ProposalRejected
 		p.ResolvedAt = turn
+		return false
 	}
+	return true
 }
 
 // WithdrawProposal marks a proposal as withdrawn.
@@ -172,3 +393,148 @@ func (g *InteractiveGoal) CheckConsensus(turn int) bool {
 	}
 	return false
 }
+
+// Judged reports whether the goal is completed by a judge rather than by consensus.
+func (g *InteractiveGoal) Judged() bool {
+	return len(g.Criteria) > 0
+}
+
+// RecordJudgment stores the judge's latest verdict on a pending judged goal.
+// The goal completes when the confidence reaches its threshold; returns true if it did.
+func (g *InteractiveGoal) RecordJudgment(turn int, confidence float64, assessment string) bool {
+	if g.Status != GoalPending {
+		return false
+	}
+
+	g.Confidence = confidence
+	g.Assessment = assessment
+	if confidence < g.Threshold {
+		return false
+	}
+
+	g.Status = GoalCompleted
+	g.CompletedAt = turn
+	slog.Info("goal completed by judgment", "goal", g.Name, "confidence", confidence, "threshold", g.Threshold)
+	return true
+}
+
+// ExpireGoals fails the pending goals whose turn limit is the given turn or
+// earlier, and returns their names, sorted.
+func (w *WorldState) ExpireGoals(turn int) []string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	var expired []string
+	for name, goal := range w.Goals {
+		if goal.Status != GoalPending || goal.MaxTurns == 0 || goal.MaxTurns > turn {
+			continue
+		}
+		goal.fail(turn)
+		expired = append(expired, name)
+	}
+	sort.Strings(expired)
+	return expired
+}
+
+// ExpireDeadlines fails the pending goals whose deadline the run time has
+// reached, on the given turn, and returns their names, sorted.
+func (w *WorldState) ExpireDeadlines(turn int) []string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	var expired []string
+	for name, goal := range w.Goals {
+		if remaining, ok := goal.TimeRemaining(w.Elapsed); goal.Status != GoalPending || !ok || remaining > 0 {
+			continue
+		}
+		goal.fail(turn)
+		expired = append(expired, name)
+	}
+	sort.Strings(expired)
+	return expired
+}
+
+// TimeRemaining returns how much of the goal's deadline is left once the run
+// has taken the given time, or false if the goal has no deadline.
+func (g *InteractiveGoal) TimeRemaining(elapsed time.Duration) (time.Duration, bool) {
+	if g.Deadline <= 0 {
+		return 0, false
+	}
+	return max(g.Deadline-elapsed, 0), true
+}
+
+// fail marks the goal failed on the given turn, rejecting its pending proposals.
+func (g *InteractiveGoal) fail(turn int) {
+	g.Status = GoalFailed
+	g.CompletedAt = turn
+	for _, proposal := range g.Proposals {
+		if proposal.Status == ProposalPending {
+			proposal.Status = ProposalRejected
+			proposal.ResolvedAt = turn
+		}
+	}
+}
+
+// ProposalsAwaitingVote counts the pending proposals on open goals that an
+// agent hasn't voted on yet. Ranked-choice goals' proposals are ranked, not
+// voted on, so they don't count, nor do tied proposals reopened for next turn.
+func (w *WorldState) ProposalsAwaitingVote(agentName string) int {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	count := 0
+	for _, goal := range w.Goals {
+		if goal.Status != GoalPending {
+			continue
+		}
+		if !w.CanDecide(goal, agentName) || goal.Ranked() {
+			continue
+		}
+		for _, proposal := range goal.Proposals {
+			if proposal.Status != ProposalPending || proposal.Reopened(w.CurrentTurn) {
+				continue
+			}
+			if _, voted := proposal.Votes[agentName]; !voted {
+				count++
+			}
+		}
+	}
+	return count
+}
+
+// GoalParticipants returns the agents who may propose and vote on a goal,
+// sorted by name. The caller must own the world (a snapshot) or hold its lock.
+func (w *WorldState) GoalParticipants(goal *InteractiveGoal) []string {
+	if len(goal.Assigned) > 0 {
+		participants := append([]string(nil), goal.Assigned...)
+		sort.Strings(participants)
+		return participants
+	}
+
+	participants := make([]string, 0, len(w.Agents))
+	for name, agent := range w.Agents {
+		if !agent.Observer {
+			participants = append(participants, name)
+		}
+	}
+	sort.Strings(participants)
+	return participants
+}
+
+// CanSee reports whether list_goals and view_goal show an agent a goal. Agents
+// see the goals they decide; observers, who decide none, watch them all, as do
+// callers who aren't agents in the scene.
+// The caller must own the world (a snapshot) or hold its lock.
+func (w *WorldState) CanSee(goal *InteractiveGoal, agentName string) bool {
+	agent, ok := w.Agents[agentName]
+	if !ok || agent.Observer {
+		return true
+	}
+	return w.CanDecide(goal, agentName)
+}
+
+// CanDecide reports whether an agent may propose and vote on a goal.
+// The caller must own the world (a snapshot) or hold its lock.
+func (w *WorldState) CanDecide(goal *InteractiveGoal, agentName string) bool {
+	return slices.Contains(w.GoalParticipants(goal), agentName)
+}
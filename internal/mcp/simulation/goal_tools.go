@@ -3,6 +3,8 @@ package simulation
 import (
 	"context"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/poiesic/wonda/internal/mcp"
 	"github.com/poiesic/wonda/internal/runtime"
@@ -13,30 +15,86 @@ import (
 func NewListGoalsTool(world *WorldState) *mcp.Tool {
 	return &mcp.Tool{
 		Name:        "list_goals",
-		Description: "List all available goals you can work toward",
+		Description: "List the goals you can work toward",
 		InputSchema: map[string]interface{}{
 			"type":       "object",
 			"properties": map[string]interface{}{},
 			"required":   []string{},
 		},
 		Handler: func(ctx context.Context, arguments map[string]interface{}) (interface{}, error) {
-			goals := make([]map[string]interface{}, 0, len(world.Goals))
-			for _, goal := range world.Goals {
-				goals = append(goals, map[string]interface{}{
-					"name":        goal.Name,
-					"description": goal.Description,
-					"status":      string(goal.Status),
-					"priority":    goal.Priority,
-				})
-			}
-			return map[string]interface{}{
-				"goals":        goals,
-				"current_turn": world.CurrentTurn,
-			}, nil
+			agentName, _ := ctx.Value(runtime.AgentNameKey).(string)
+
+			var result map[string]interface{}
+			world.View(func(w *WorldState) {
+				goals := make([]map[string]interface{}, 0, len(w.Goals))
+				for _, goal := range w.Goals {
+					if !w.CanSee(goal, agentName) {
+						continue
+					}
+					entry := map[string]interface{}{
+						"name":        goal.Name,
+						"description": goal.Description,
+						"status":      string(goal.Status),
+						"priority":    goal.Priority,
+						"you_decide":  w.CanDecide(goal, agentName),
+					}
+					if goal.Individual() {
+						_, done := goal.Completions[agentName]
+						entry["individual"] = true
+						entry["you_completed"] = done
+					}
+					if goal.MaxTurns > 0 {
+						entry["due_by_turn"] = goal.MaxTurns
+					}
+					if remaining, ok := goal.TimeRemaining(w.Elapsed); ok {
+						entry["time_remaining"] = formatRemaining(remaining)
+					}
+					goals = append(goals, entry)
+				}
+				result = map[string]interface{}{
+					"goals":        goals,
+					"current_turn": w.CurrentTurn,
+				}
+			})
+			return result, nil
 		},
 	}
 }
 
+// formatOffers renders a negotiation's history for view_goal, oldest offer first.
+func formatOffers(offers []Offer) []map[string]interface{} {
+	formatted := make([]map[string]interface{}, 0, len(offers))
+	for _, offer := range offers {
+		entry := map[string]interface{}{
+			"id":          offer.ProposalID,
+			"side":        offer.Side,
+			"proposed_by": offer.ProposedBy,
+			"turn":        offer.Turn,
+			"terms":       offer.Terms,
+			"status":      string(offer.Status),
+		}
+		if offer.Amount != nil {
+			entry["amount"] = *offer.Amount
+		}
+		if offer.Counters != "" {
+			entry["counters"] = offer.Counters
+		}
+		if offer.Concession > 0 {
+			entry["concession"] = offer.Concession
+		}
+		formatted = append(formatted, entry)
+	}
+	return formatted
+}
+
+// formatRemaining renders the time left before a goal's deadline to the second.
+func formatRemaining(remaining time.Duration) string {
+	return remaining.Round(time.Second).String()
+}
+
+// goalDiscussionSize is the number of recent messages about a goal view_goal shows.
+const goalDiscussionSize = 10
+
 // NewViewGoalTool creates the view_goal MCP tool.
 // Allows agents to check the current status of goals, proposals, and votes.
 func NewViewGoalTool(world *WorldState) *mcp.Tool {
@@ -59,10 +117,18 @@ func NewViewGoalTool(world *WorldState) *mcp.Tool {
 				return nil, fmt.Errorf("goal_name is required")
 			}
 
-			goal, ok := world.Goals[goalName]
+			// Work from a snapshot so the response is consistent while other agents vote
+			snapshot := world.Snapshot()
+			goal, ok := snapshot.Goals[goalName]
 			if !ok {
 				return nil, fmt.Errorf("goal not found: %s", goalName)
 			}
+			if agentName, ok := ctx.Value(runtime.AgentNameKey).(string); ok && agentName != "" {
+				if !snapshot.CanSee(goal, agentName) {
+					return nil, fmt.Errorf("%s is not your goal - it's for %s to decide", goalName, strings.Join(snapshot.GoalParticipants(goal), ", "))
+				}
+				world.SetFocus(agentName, goalName)
+			}
 
 			// Separate proposals by status
 			pending := []map[string]interface{}{}
@@ -83,6 +149,18 @@ func NewViewGoalTool(world *WorldState) *mcp.Tool {
 					"proposed_at": proposal.ProposedAt,
 					"votes":       votes,
 				}
+				if proposal.Allocation != nil {
+					formatted["allocation"] = proposal.Allocation
+				}
+				if proposal.Amount != nil {
+					formatted["amount"] = *proposal.Amount
+				}
+				if proposal.Counters != "" {
+					formatted["counters"] = proposal.Counters
+				}
+				if proposal.TieBreak != nil {
+					formatted["tie_break"] = proposal.TieBreak.Reason
+				}
 
 				switch proposal.Status {
 				case ProposalPending:
@@ -99,17 +177,90 @@ func NewViewGoalTool(world *WorldState) *mcp.Tool {
 				}
 			}
 
-			return map[string]interface{}{
+			result := map[string]interface{}{
 				"name":                goal.Name,
 				"description":         goal.Description,
 				"status":              string(goal.Status),
 				"priority":            goal.Priority,
-				"current_turn":        world.CurrentTurn,
+				"current_turn":        snapshot.CurrentTurn,
 				"pending_proposals":   pending,
 				"accepted_proposals":  accepted,
 				"rejected_proposals":  rejected,
 				"withdrawn_proposals": withdrawn,
-			}, nil
+			}
+			if goal.Individual() {
+				result["completed_by"] = goal.CompletedBy()
+			}
+			if goal.MaxTurns > 0 {
+				result["due_by_turn"] = goal.MaxTurns
+			}
+			if goal.Negotiation != nil {
+				result["sides"] = goal.Negotiation.Sides
+				if agentName, ok := ctx.Value(runtime.AgentNameKey).(string); ok {
+					if side := goal.Negotiation.SideOf(agentName); side != "" {
+						result["your_side"] = side
+					}
+				}
+				if goal.Negotiation.Term != "" {
+					result["term"] = goal.Negotiation.Term
+				}
+				result["offers"] = formatOffers(goal.Offers())
+			}
+			if goal.Voting != nil && goal.Voting.TieBreak != "" {
+				result["tie_break"] = tieBreakRule(goal.Voting)
+			}
+			if goal.Ranked() {
+				result["decided_by"] = "ranking the proposals once there are two or more to choose between"
+			}
+			if goal.Consensus == nil && goal.Quorum > 0 && goal.Quorum < 1 {
+				result["yes_votes_needed"] = fmt.Sprintf("%.0f%% of those deciding", goal.Quorum*100)
+			}
+			if remaining, ok := goal.TimeRemaining(snapshot.Elapsed); ok {
+				result["deadline"] = goal.Deadline.String()
+				result["time_remaining"] = formatRemaining(remaining)
+			}
+			if messages := snapshot.GetGoalDiscussion(goalName, goalDiscussionSize); len(messages) > 0 {
+				discussion := make([]string, 0, len(messages))
+				for _, msg := range messages {
+					discussion = append(discussion, fmt.Sprintf("%s: %s", msg.AgentName, msg.Content))
+				}
+				result["discussion"] = discussion
+			}
+			if len(goal.Forbidden) > 0 {
+				ruledOut := make([]string, 0, len(goal.Forbidden))
+				for _, outcome := range goal.Forbidden {
+					ruledOut = append(ruledOut, outcome.Reason)
+				}
+				result["ruled_out"] = ruledOut
+			}
+			if goal.Judged() {
+				result["success_criteria"] = goal.Criteria
+				result["progress"] = goal.Assessment
+			}
+			if rules := goal.Allocation; rules != nil {
+				constraints := make([]string, 0, len(rules.Constraints))
+				for _, constraint := range rules.Constraints {
+					constraints = append(constraints, constraint.String())
+				}
+				result["allocation_rules"] = map[string]interface{}{
+					"resource":        rules.Resource,
+					"total":           rules.Total,
+					"recipients":      rules.Recipients,
+					"constraints":     constraints,
+					"allow_remainder": rules.AllowRemainder,
+				}
+			}
+			if rules := goal.Voting; rules != nil {
+				weights := make(map[string]float64)
+				for _, agentName := range snapshot.GoalParticipants(goal) {
+					weights[agentName] = rules.Weight(agentName)
+				}
+				result["voting_rules"] = map[string]interface{}{
+					"threshold": rules.Threshold,
+					"weights":   weights,
+				}
+			}
+			return result, nil
 		},
 	}
 }
@@ -119,7 +270,7 @@ func NewViewGoalTool(world *WorldState) *mcp.Tool {
 func NewProposeSolutionTool(world *WorldState) *mcp.Tool {
 	return &mcp.Tool{
 		Name:        "propose_solution",
-		Description: "Propose ONE specific solution for a goal with an in-character pitch. Each proposal must be a single, concrete choice - not a list of options.",
+		Description: "Propose ONE specific solution for a goal with an in-character pitch. Each proposal must be a single, concrete choice - not a list of options. For goals that divide a resource, propose an allocation instead of a solution.",
 		EndsTurn:    true,
 		InputSchema: map[string]interface{}{
 			"type": "object",
@@ -132,12 +283,21 @@ func NewProposeSolutionTool(world *WorldState) *mcp.Tool {
 					"type":        "string",
 					"description": "Your proposed solution - must be ONE specific choice (e.g., 'Bella's Italian Restaurant'), NOT multiple options or alternatives",
 				},
+				"allocation": map[string]interface{}{
+					"type":                 "object",
+					"additionalProperties": map[string]interface{}{"type": "number"},
+					"description":          "For goals that divide a resource: each recipient's share (e.g., {\"marketing\": 600, \"engineering\": 400}). Check view_goal for the recipients, total, and constraints.",
+				},
+				"amount": map[string]interface{}{
+					"type":        "number",
+					"description": "For negotiations over a number (see the term in view_goal): the amount you are offering",
+				},
 				"comment": map[string]interface{}{
 					"type":        "string",
 					"description": "What you SAY out loud as you propose this - an in-character pitch for your idea. Sell it, explain what makes it good, be persuasive and authentic. EXAMPLES: \"How about we hit up The Skyline Lounge? Best cocktails in the city and the view is killer.\" or \"I'm thinking Bella's - intimate, great food, and the owner owes me a favor.\"",
 				},
 			},
-			"required": []string{"goal_name", "solution", "comment"},
+			"required": []string{"goal_name", "comment"},
 		},
 		Handler: func(ctx context.Context, arguments map[string]interface{}) (interface{}, error) {
 			agentName, ok := ctx.Value(runtime.AgentNameKey).(string)
@@ -150,46 +310,90 @@ func NewProposeSolutionTool(world *WorldState) *mcp.Tool {
 				return nil, fmt.Errorf("goal_name is required")
 			}
 
-			solution, ok := arguments["solution"].(string)
-			if !ok || solution == "" {
-				return nil, fmt.Errorf("solution is required and must be a string")
-			}
+			solution, _ := arguments["solution"].(string)
 
 			comment, ok := arguments["comment"].(string)
 			if !ok || comment == "" {
 				return nil, fmt.Errorf("comment is required - you must say something as you propose")
 			}
 
-			goal, ok := world.Goals[goalName]
-			if !ok {
-				return nil, fmt.Errorf("goal not found: %s", goalName)
-			}
+			var proposalID, message string
+			err := world.Update(func(w *WorldState) error {
+				goal, ok := w.Goals[goalName]
+				if !ok {
+					return fmt.Errorf("goal not found: %s", goalName)
+				}
 
-			if goal.Status != GoalPending {
-				return nil, fmt.Errorf("cannot propose solutions to %s goals", goal.Status)
-			}
+				if goal.Status != GoalPending {
+					return fmt.Errorf("cannot propose solutions to %s goals", goal.Status)
+				}
+				// Even a refused attempt shows what the agent is thinking about
+				w.setFocus(agentName, goalName)
 
-			// Check if agent already has a proposal for this goal this turn
-			for _, proposal := range goal.Proposals {
-				if proposal.ProposedBy == agentName && proposal.ProposedAt == world.CurrentTurn {
-					return nil, fmt.Errorf("you already proposed a solution for this goal this turn")
+				if !w.CanDecide(goal, agentName) {
+					return fmt.Errorf("you have no say in %s - you can still speak your mind", goalName)
+				}
+				if goal.Individual() {
+					return fmt.Errorf("%s is your own goal - use complete_goal once you have achieved it", goalName)
 				}
-			}
 
-			// Add comment to pending dialogue (will be captured by simulation)
-			world.AddPendingDialogue(agentName, comment, MessageTypeDialogue)
+				// Check if agent already has a proposal for this goal this turn
+				for _, proposal := range goal.Proposals {
+					if proposal.ProposedBy == agentName && proposal.ProposedAt == w.CurrentTurn {
+						return fmt.Errorf("you already proposed a solution for this goal this turn")
+					}
+				}
+
+				if goal.Allocation != nil {
+					allocation, err := ParseAllocation(arguments["allocation"])
+					if err != nil {
+						return err
+					}
+					if err := goal.checkForbidden(goal.Allocation.Format(allocation)); err != nil {
+						return err
+					}
+					if proposalID, err = goal.AddAllocationProposal(agentName, allocation, w.CurrentTurn); err != nil {
+						return err
+					}
+					solution = goal.Proposals[proposalID].Description
+				} else {
+					if solution == "" {
+						return fmt.Errorf("solution is required and must be a string")
+					}
+					amount, err := offerAmount(goal, arguments)
+					if err != nil {
+						return err
+					}
+					if err := goal.checkForbidden(solution); err != nil {
+						return err
+					}
+					proposalID = goal.AddProposal(agentName, solution, w.CurrentTurn)
+					goal.Proposals[proposalID].Amount = amount
+				}
 
-			proposalID := goal.AddProposal(agentName, solution, world.CurrentTurn)
+				// Add comment to pending dialogue (will be captured by simulation)
+				w.addProposalDialogue(agentName, comment, goalName, proposalID, solution)
 
-			// Auto-vote yes on own proposal (agents always support their own proposals)
-			if err := goal.Vote(proposalID, agentName, "yes", world.CurrentTurn); err != nil {
-				return nil, fmt.Errorf("failed to auto-vote on proposal: %w", err)
+				// Auto-vote yes on own proposal (agents always support their own proposals);
+				// ranked-choice goals' proposals are ranked instead
+				if goal.Ranked() {
+					message = fmt.Sprintf("Proposed: %s (everyone will rank it against the other proposals)", solution)
+					return nil
+				}
+				if err := goal.Vote(proposalID, agentName, "yes", w.CurrentTurn); err != nil {
+					return fmt.Errorf("failed to auto-vote on proposal: %w", err)
+				}
+				message = fmt.Sprintf("Proposed: %s (auto-voted yes)", solution)
+				return nil
+			})
+			if err != nil {
+				return nil, err
 			}
 
 			return map[string]interface{}{
 				"success":     true,
 				"proposal_id": proposalID,
-				"message":     fmt.Sprintf("Proposed: %s (auto-voted yes)", solution),
+				"message":     message,
 			}, nil
 		},
 	}
@@ -255,51 +459,74 @@ func NewVoteOnProposalTool(world *WorldState) *mcp.Tool {
 				return nil, fmt.Errorf("comment is required - you must say something as you vote")
 			}
 
-			goal, ok := world.Goals[goalName]
-			if !ok {
-				return nil, fmt.Errorf("goal not found: %s", goalName)
+			result := map[string]interface{}{
+				"success": true,
+				"message": fmt.Sprintf("Voted %s on proposal", vote),
 			}
 
-			if goal.Status != GoalPending {
-				return nil, fmt.Errorf("cannot vote on %s goals", goal.Status)
-			}
+			err := world.Update(func(w *WorldState) error {
+				goal, ok := w.Goals[goalName]
+				if !ok {
+					return fmt.Errorf("goal not found: %s", goalName)
+				}
 
-			proposal, ok := goal.Proposals[proposalID]
-			if !ok {
-				return nil, fmt.Errorf("proposal not found: %s", proposalID)
-			}
+				if goal.Status != GoalPending {
+					return fmt.Errorf("cannot vote on %s goals", goal.Status)
+				}
+				// Even a refused attempt shows what the agent is thinking about
+				w.setFocus(agentName, goalName)
 
-			// Check if agent already voted on this proposal
-			if _, hasVoted := proposal.Votes[agentName]; hasVoted {
-				return nil, fmt.Errorf("you already voted on this proposal")
-			}
+				if !w.CanDecide(goal, agentName) {
+					return fmt.Errorf("you have no say in %s - you can still speak your mind", goalName)
+				}
 
-			// Add comment to pending dialogue (will be captured by simulation)
-			world.AddPendingDialogue(agentName, comment, MessageTypeDialogue)
+				if goal.Ranked() {
+					return fmt.Errorf("%s is settled by ranking its proposals, not voting on them - you'll rank them once there's a choice to make", goalName)
+				}
 
-			// Record vote
-			if err := goal.Vote(proposalID, agentName, vote, world.CurrentTurn); err != nil {
-				return nil, err
-			}
+				proposal, ok := goal.Proposals[proposalID]
+				if !ok {
+					return fmt.Errorf("proposal not found: %s", proposalID)
+				}
 
-			// Evaluate proposal status
-			proposal.EvaluateStatus(len(world.Agents), world.CurrentTurn)
+				if proposal.Reopened(w.CurrentTurn) {
+					return fmt.Errorf("the vote on %s tied - it is held again next turn, after more discussion", proposalID)
+				}
 
-			result := map[string]interface{}{
-				"success": true,
-				"message": fmt.Sprintf("Voted %s on proposal", vote),
-			}
+				// Check if agent already voted on this proposal
+				if _, hasVoted := proposal.Votes[agentName]; hasVoted {
+					return fmt.Errorf("you already voted on this proposal")
+				}
+
+				// Add comment to pending dialogue (will be captured by simulation)
+				w.addVoteDialogue(agentName, comment, goalName, proposalID, vote)
 
-			// Check outcome
-			switch proposal.Status {
-			case ProposalAccepted:
-				goal.CheckConsensus(world.CurrentTurn)
-				result["outcome"] = "accepted"
-				result["message"] = "Proposal accepted! Goal completed."
-				result["goal_completed"] = true
-			case ProposalRejected:
-				result["outcome"] = "rejected"
-				result["message"] = "Proposal rejected. You can propose alternatives."
+				// Record vote
+				if err := goal.Vote(proposalID, agentName, vote, w.CurrentTurn); err != nil {
+					return err
+				}
+
+				// Evaluate proposal status; accepted proposals must also satisfy the goal's
+				// allocation constraints and avoid forbidden outcomes
+				goal.EvaluateProposal(proposal, w.GoalParticipants(goal), w.CurrentTurn)
+				goal.EnforceAllocation(proposal, w.CurrentTurn)
+				goal.EnforceForbidden(proposal, w.CurrentTurn)
+
+				// Check outcome
+				switch proposal.Status {
+				case ProposalAccepted:
+					goal.CheckConsensus(w.CurrentTurn)
+					result["outcome"] = "accepted"
+					result["message"] = "Proposal accepted! Goal completed."
+					result["goal_completed"] = true
+				case ProposalRejected:
+					result["outcome"] = "rejected"
+					result["message"] = "Proposal rejected. You can propose alternatives."
+				}
+				return nil
+			})
+			if err != nil {
+				return nil, err
 			}
 
 			return result, nil
@@ -343,12 +570,14 @@ func NewWithdrawProposalTool(world *WorldState) *mcp.Tool {
 				return nil, fmt.Errorf("proposal_id is required")
 			}
 
-			goal, ok := world.Goals[goalName]
-			if !ok {
-				return nil, fmt.Errorf("goal not found: %s", goalName)
-			}
-
-			if err := goal.WithdrawProposal(proposalID, agentName, world.CurrentTurn); err != nil {
+			err := world.Update(func(w *WorldState) error {
+				goal, ok := w.Goals[goalName]
+				if !ok {
+					return fmt.Errorf("goal not found: %s", goalName)
+				}
+				return goal.WithdrawProposal(proposalID, agentName, w.CurrentTurn)
+			})
+			if err != nil {
 				return nil, err
 			}
 
@@ -359,3 +588,74 @@ func NewWithdrawProposalTool(world *WorldState) *mcp.Tool {
 		},
 	}
 }
+
+// NewCompleteGoalTool creates the complete_goal MCP tool.
+// Allows an agent to mark their own individual goal complete.
+func NewCompleteGoalTool(world *WorldState) *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "complete_goal",
+		Description: "Mark one of your individual goals complete once you have achieved it. Only for goals list_goals marks as individual; shared goals are decided by proposals and votes.",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"goal_name": map[string]interface{}{
+					"type":        "string",
+					"description": "Name of the goal",
+				},
+				"summary": map[string]interface{}{
+					"type":        "string",
+					"description": "How you achieved the goal (e.g., 'Got Sam to admit he took the money')",
+				},
+			},
+			"required": []string{"goal_name", "summary"},
+		},
+		Handler: func(ctx context.Context, arguments map[string]interface{}) (interface{}, error) {
+			agentName, ok := ctx.Value(runtime.AgentNameKey).(string)
+			if !ok || agentName == "" {
+				return nil, fmt.Errorf("agent_name not found in context")
+			}
+
+			goalName, ok := arguments["goal_name"].(string)
+			if !ok {
+				return nil, fmt.Errorf("goal_name is required")
+			}
+
+			summary, ok := arguments["summary"].(string)
+			if !ok || summary == "" {
+				return nil, fmt.Errorf("summary is required - say how you achieved the goal")
+			}
+
+			result := map[string]interface{}{
+				"goal":   goalName,
+				"status": "completed",
+			}
+			err := world.Update(func(w *WorldState) error {
+				goal, ok := w.Goals[goalName]
+				if !ok {
+					return fmt.Errorf("goal not found: %s", goalName)
+				}
+
+				if goal.Status != GoalPending {
+					return fmt.Errorf("cannot complete %s goals", goal.Status)
+				}
+				w.setFocus(agentName, goalName)
+
+				if !w.CanDecide(goal, agentName) {
+					return fmt.Errorf("%s is not your goal", goalName)
+				}
+
+				goalCompleted, err := goal.CompleteFor(agentName, summary, w.GoalParticipants(goal), w.CurrentTurn)
+				if err != nil {
+					return err
+				}
+				result["goal_completed"] = goalCompleted
+				return nil
+			})
+			if err != nil {
+				return nil, err
+			}
+
+			return result, nil
+		},
+	}
+}
@@ -0,0 +1,58 @@
+package simulation
+
+import (
+	"fmt"
+	"sort"
+)
+
+// IndividualCompletion records an agent completing their part of an individual goal.
+type IndividualCompletion struct {
+	AgentName   string
+	Summary     string // What the agent says they did
+	CompletedAt int
+}
+
+// Individual reports whether each participant pursues the goal on their own
+// and marks it complete for themselves.
+func (g *InteractiveGoal) Individual() bool {
+	return g.Type == "individual"
+}
+
+// CompleteFor marks an individual goal complete for one agent. The goal as a
+// whole completes once every participant has completed it; returns true if it did.
+func (g *InteractiveGoal) CompleteFor(agentName, summary string, participants []string, turn int) (bool, error) {
+	if !g.Individual() {
+		return false, fmt.Errorf("goal %s is not an individual goal", g.Name)
+	}
+	if _, done := g.Completions[agentName]; done {
+		return false, fmt.Errorf("you already completed %s", g.Name)
+	}
+
+	if g.Completions == nil {
+		g.Completions = make(map[string]*IndividualCompletion)
+	}
+	g.Completions[agentName] = &IndividualCompletion{
+		AgentName:   agentName,
+		Summary:     summary,
+		CompletedAt: turn,
+	}
+
+	for _, participant := range participants {
+		if _, done := g.Completions[participant]; !done {
+			return false, nil
+		}
+	}
+	g.Status = GoalCompleted
+	g.CompletedAt = turn
+	return true, nil
+}
+
+// CompletedBy returns the agents who have completed an individual goal, sorted by name.
+func (g *InteractiveGoal) CompletedBy() []string {
+	agents := make([]string, 0, len(g.Completions))
+	for agentName := range g.Completions {
+		agents = append(agents, agentName)
+	}
+	sort.Strings(agents)
+	return agents
+}
@@ -11,7 +11,7 @@ import (
 
 // NewQuerySelfTool creates the query_self MCP tool.
 // Returns core identity information about the agent.
-func NewQuerySelfTool(store *memory.Store) *mcp.Tool {
+func NewQuerySelfTool(store *memory.Store, search SearchOptions) *mcp.Tool {
 	return &mcp.Tool{
 		Name:        "query_self",
 		Description: "Retrieve your core identity - who you are, your personality, background",
@@ -35,19 +35,20 @@ func NewQuerySelfTool(store *memory.Store) *mcp.Tool {
 					Type:     "character",
 					Category: "identity",
 				},
-				5,
+				search.topK(5),
 			)
 			if err != nil {
 				return nil, err
 			}
+			results = search.relevant(results)
 
 			// Format results
 			memories := make([]map[string]interface{}, len(results))
 			for i, mem := range results {
-				memories[i] = map[string]interface{}{
+				memories[i] = citable(ctx, mem, map[string]interface{}{
 					"content":   mem.Content,
 					"relevance": mem.Score,
-				}
+				})
 			}
 
 			return map[string]interface{}{
@@ -58,7 +59,7 @@ func NewQuerySelfTool(store *memory.Store) *mcp.Tool {
 }
 
 // NewQueryBackgroundTool creates the query_background MCP tool.
-func NewQueryBackgroundTool(store *memory.Store) *mcp.Tool {
+func NewQueryBackgroundTool(store *memory.Store, search SearchOptions) *mcp.Tool {
 	return &mcp.Tool{
 		Name:        "query_background",
 		Description: "Retrieve your personal history and background",
@@ -81,18 +82,19 @@ func NewQueryBackgroundTool(store *memory.Store) *mcp.Tool {
 					Type:     "character",
 					Category: "background",
 				},
-				5,
+				search.topK(5),
 			)
 			if err != nil {
 				return nil, err
 			}
+			results = search.relevant(results)
 
 			memories := make([]map[string]interface{}, len(results))
 			for i, mem := range results {
-				memories[i] = map[string]interface{}{
+				memories[i] = citable(ctx, mem, map[string]interface{}{
 					"content":   mem.Content,
 					"relevance": mem.Score,
-				}
+				})
 			}
 
 			return map[string]interface{}{
@@ -103,7 +105,7 @@ func NewQueryBackgroundTool(store *memory.Store) *mcp.Tool {
 }
 
 // NewQueryCommunicationStyleTool creates the query_communication_style MCP tool.
-func NewQueryCommunicationStyleTool(store *memory.Store) *mcp.Tool {
+func NewQueryCommunicationStyleTool(store *memory.Store, search SearchOptions) *mcp.Tool {
 	return &mcp.Tool{
 		Name:        "query_communication_style",
 		Description: "Learn how you communicate and interact with others",
@@ -126,18 +128,19 @@ func NewQueryCommunicationStyleTool(store *memory.Store) *mcp.Tool {
 					Type:     "character",
 					Category: "communication",
 				},
-				3,
+				search.topK(3),
 			)
 			if err != nil {
 				return nil, err
 			}
+			results = search.relevant(results)
 
 			memories := make([]map[string]interface{}, len(results))
 			for i, mem := range results {
-				memories[i] = map[string]interface{}{
+				memories[i] = citable(ctx, mem, map[string]interface{}{
 					"content":   mem.Content,
 					"relevance": mem.Score,
-				}
+				})
 			}
 
 			return map[string]interface{}{
@@ -148,7 +151,7 @@ func NewQueryCommunicationStyleTool(store *memory.Store) *mcp.Tool {
 }
 
 // NewQuerySceneTool creates the query_scene MCP tool.
-func NewQuerySceneTool(store *memory.Store) *mcp.Tool {
+func NewQuerySceneTool(store *memory.Store, search SearchOptions) *mcp.Tool {
 	return &mcp.Tool{
 		Name:        "query_scene",
 		Description: "Understand where you are and the current atmosphere",
@@ -164,18 +167,19 @@ func NewQuerySceneTool(store *memory.Store) *mcp.Tool {
 				memory.Filter{
 					Type: "scene",
 				},
-				5,
+				search.topK(5),
 			)
 			if err != nil {
 				return nil, err
 			}
+			results = search.relevant(results)
 
 			memories := make([]map[string]interface{}, len(results))
 			for i, mem := range results {
-				memories[i] = map[string]interface{}{
+				memories[i] = citable(ctx, mem, map[string]interface{}{
 					"content":   mem.Content,
 					"relevance": mem.Score,
-				}
+				})
 			}
 
 			return map[string]interface{}{
@@ -186,7 +190,7 @@ func NewQuerySceneTool(store *memory.Store) *mcp.Tool {
 }
 
 // NewQueryCharacterTool creates the query_character MCP tool.
-func NewQueryCharacterTool(store *memory.Store) *mcp.Tool {
+func NewQueryCharacterTool(store *memory.Store, search SearchOptions) *mcp.Tool {
 	return &mcp.Tool{
 		Name:        "query_character",
 		Description: "Learn about another agent in the simulation",
@@ -222,18 +226,19 @@ func NewQueryCharacterTool(store *memory.Store) *mcp.Tool {
 					Type:  "character_knowledge",
 					About: targetName,
 				},
-				3,
+				search.topK(3),
 			)
 			if err != nil {
 				return nil, err
 			}
+			results = search.relevant(results)
 
 			memories := make([]map[string]interface{}, len(results))
 			for i, mem := range results {
-				memories[i] = map[string]interface{}{
+				memories[i] = citable(ctx, mem, map[string]interface{}{
 					"content":   mem.Content,
 					"relevance": mem.Score,
-				}
+				})
 			}
 
 			return map[string]interface{}{
@@ -245,10 +250,14 @@ func NewQueryCharacterTool(store *memory.Store) *mcp.Tool {
 }
 
 // NewQueryMemoryTool creates the query_memory MCP tool for flexible episodic search.
-func NewQueryMemoryTool(store *memory.Store) *mcp.Tool {
+func NewQueryMemoryTool(store *memory.Store, search SearchOptions) *mcp.Tool {
+	description := "Search your memories of what has happened during the simulation"
+	if search.WorkingFrom != nil {
+		description = "Search your memories of what happened earlier in the simulation. What was said in the last few turns is already in your working memory - you needn't search for it"
+	}
 	return &mcp.Tool{
 		Name:        "query_memory",
-		Description: "Search your memories of what has happened during the simulation",
+		Description: description,
 		InputSchema: map[string]interface{}{
 			"type": "object",
 			"properties": map[string]interface{}{
@@ -256,6 +265,10 @@ func NewQueryMemoryTool(store *memory.Store) *mcp.Tool {
 					"type":        "string",
 					"description": "What you want to remember (e.g., 'what did [other agent] say about the goal?')",
 				},
+				"language": map[string]interface{}{
+					"type":        "string",
+					"description": "Only recall things said in this language (ISO 639-1 code, e.g. 'es'). Optional.",
+				},
 			},
 			"required": []string{"query"},
 		},
@@ -271,21 +284,68 @@ func NewQueryMemoryTool(store *memory.Store) *mcp.Tool {
 				return nil, fmt.Errorf("failed to embed query: %w", err)
 			}
 
-			results := store.Search(
+			// Memories about the goal being worked on rank higher
+			goalTags, _ := ctx.Value(runtime.GoalTagsKey).([]string)
+			results := store.SearchBoosted(
 				ctx,
 				embedding,
 				memory.Filter{
-					Type: "episodic",
+					Type:     "episodic",
+					MaxTurn:  search.longTermBefore(),
+					Language: retrievalLanguage(ctx, store, arguments),
 				},
-				5,
+				search.topK(searchLimit(ctx, 5)),
+				goalTags,
 			)
+			results = search.relevant(results)
 
 			memories := make([]map[string]interface{}, len(results))
 			for i, mem := range results {
-				memories[i] = map[string]interface{}{
+				memories[i] = citable(ctx, mem, map[string]interface{}{
 					"content":   mem.Content,
 					"relevance": mem.Score,
 					"turn":      mem.Metadata["turn"],
+				})
+			}
+
+			// Include the agent's own memories of commitments the group made
+			// and of whispers only they were party to
+			if agentName, ok := ctx.Value(runtime.AgentNameKey).(string); ok && agentName != "" {
+				whispers := store.Search(
+					ctx,
+					embedding,
+					memory.Filter{
+						Agent:    agentName,
+						Type:     "whisper",
+						Language: retrievalLanguage(ctx, store, arguments),
+					},
+					3,
+				)
+				for _, mem := range search.relevant(whispers) {
+					memories = append(memories, citable(ctx, mem, map[string]interface{}{
+						"content":   mem.Content,
+						"relevance": mem.Score,
+						"turn":      mem.Metadata["turn"],
+						"private":   true,
+					}))
+				}
+
+				commitments := store.Search(
+					ctx,
+					embedding,
+					memory.Filter{
+						Agent: agentName,
+						Type:  "commitment",
+					},
+					3,
+				)
+				for _, mem := range search.relevant(commitments) {
+					memories = append(memories, citable(ctx, mem, map[string]interface{}{
+						"content":    mem.Content,
+						"relevance":  mem.Score,
+						"turn":       mem.Metadata["turn"],
+						"commitment": true,
+					}))
 				}
 			}
 
@@ -296,3 +356,124 @@ func NewQueryMemoryTool(store *memory.Store) *mcp.Tool {
 		},
 	}
 }
+
+// NewQueryKnowledgeTool creates the query_knowledge MCP tool for searching
+// documents ingested for the scenario, such as briefings or case files.
+func NewQueryKnowledgeTool(store *memory.Store, search SearchOptions) *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "query_knowledge",
+		Description: "Look something up in the documents everyone in the scene has read, such as briefings or case files",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"query": map[string]interface{}{
+					"type":        "string",
+					"description": "What you want to look up (e.g., 'what does the report say about the budget?')",
+				},
+			},
+			"required": []string{"query"},
+		},
+		Handler: func(ctx context.Context, arguments map[string]interface{}) (interface{}, error) {
+			query, ok := arguments["query"].(string)
+			if !ok || query == "" {
+				return nil, fmt.Errorf("query parameter is required")
+			}
+
+			embedding, err := store.Embed(ctx, query)
+			if err != nil {
+				return nil, fmt.Errorf("failed to embed query: %w", err)
+			}
+
+			results := store.Search(ctx, embedding, memory.Filter{Type: "knowledge"}, search.topK(searchLimit(ctx, 5)))
+			results = search.relevant(results)
+
+			passages := make([]map[string]interface{}, len(results))
+			for i, mem := range results {
+				passages[i] = citable(ctx, mem, map[string]interface{}{
+					"content":   mem.Content,
+					"relevance": mem.Score,
+					"source":    mem.Metadata["source"],
+				})
+			}
+
+			return map[string]interface{}{
+				"query":    query,
+				"passages": passages,
+			}, nil
+		},
+	}
+}
+
+// SearchOptions tunes what a memory tool returns. The zero value keeps the tool's defaults.
+type SearchOptions struct {
+	TopK         int      // Most results returned; 0 uses the tool's default
+	MinRelevance *float64 // Results scoring below this are dropped; nil keeps every result
+
+	// WorkingFrom, if set, returns the first turn agents hold in working
+	// memory; query_memory leaves out what happened from then on.
+	WorkingFrom func() int
+}
+
+// longTermBefore returns the last turn query_memory searches, or 0 to search them all.
+func (o SearchOptions) longTermBefore() int {
+	if o.WorkingFrom == nil {
+		return 0
+	}
+	// A filter's MaxTurn of 0 means no limit, so leave nothing out until
+	// there is something older than working memory
+	return max(o.WorkingFrom()-1, 0)
+}
+
+// topK returns how many results to search for.
+func (o SearchOptions) topK(defaultLimit int) int {
+	if o.TopK > 0 {
+		return o.TopK
+	}
+	return defaultLimit
+}
+
+// relevant drops results scoring below the minimum relevance, keeping their order.
+func (o SearchOptions) relevant(results []memory.Memory) []memory.Memory {
+	if o.MinRelevance == nil {
+		return results
+	}
+	kept := results[:0]
+	for _, mem := range results {
+		if float64(mem.Score) >= *o.MinRelevance {
+			kept = append(kept, mem)
+		}
+	}
+	return kept
+}
+
+// citable adds a memory's ID to a tool result entry when the agent is asked to
+// cite the memories behind what it says.
+func citable(ctx context.Context, mem memory.Memory, entry map[string]interface{}) map[string]interface{} {
+	if cite, _ := ctx.Value(runtime.CiteMemoriesKey).(bool); cite {
+		entry["id"] = mem.ID
+	}
+	return entry
+}
+
+// searchLimit returns how many results a search returns: the run's setting if
+// it has one, otherwise the tool's default.
+func searchLimit(ctx context.Context, defaultLimit int) int {
+	if limit, ok := ctx.Value(runtime.MemoryResultsKey).(int); ok && limit > 0 {
+		return limit
+	}
+	return defaultLimit
+}
+
+// retrievalLanguage returns the language episodic searches are limited to: the
+// one requested, or else the agent's own when the embedder can't compare text
+// across languages. Empty means any language.
+func retrievalLanguage(ctx context.Context, store *memory.Store, arguments map[string]interface{}) string {
+	if language, ok := arguments["language"].(string); ok && language != "" {
+		return language
+	}
+	if store.Options().CrossLingual {
+		return ""
+	}
+	language, _ := ctx.Value(runtime.AgentLanguageKey).(string)
+	return language
+}
@@ -0,0 +1,264 @@
+package simulation
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/poiesic/wonda/internal/mcp"
+	"github.com/poiesic/wonda/internal/runtime"
+)
+
+// Place is a location in the scene, connected to its neighbours by exits.
+type Place struct {
+	Name        string
+	Description string
+	Exits       []string // Places reachable from here, sorted
+}
+
+// AddPlace adds a place to the scene. Its exits connect it both ways to the
+// places they lead to, which are added too if they aren't yet.
+func (w *WorldState) AddPlace(name, description string, exits []string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.place(name).Description = description
+	for _, exit := range exits {
+		w.connect(name, exit)
+		w.connect(exit, name)
+	}
+}
+
+// place returns the named place, adding it if it doesn't exist. The caller
+// holds the write lock.
+func (w *WorldState) place(name string) *Place {
+	place, ok := w.Places[name]
+	if !ok {
+		place = &Place{Name: name}
+		w.Places[name] = place
+	}
+	return place
+}
+
+// connect adds an exit from one place to another. The caller holds the write lock.
+func (w *WorldState) connect(from, to string) {
+	place := w.place(from)
+	if !slices.Contains(place.Exits, to) {
+		place.Exits = append(place.Exits, to)
+		sort.Strings(place.Exits)
+	}
+}
+
+// Position returns where an agent is, or "" if they aren't in the world.
+func (w *WorldState) Position(agentName string) string {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.position(agentName)
+}
+
+// position is Position for callers already holding the lock.
+func (w *WorldState) position(agentName string) string {
+	if agent, ok := w.Agents[agentName]; ok {
+		return agent.Position
+	}
+	return ""
+}
+
+// MoveTo moves an agent through an exit to a neighbouring place. The move is
+// added to the pending dialogue as an action so the simulation chronicles it.
+func (w *WorldState) MoveTo(agentName, destination string) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	agent, ok := w.Agents[agentName]
+	if !ok {
+		return fmt.Errorf("agent %s not found in world", agentName)
+	}
+	if len(w.Places) == 0 {
+		return fmt.Errorf("there is nowhere else to go - everyone is in the same place")
+	}
+	if destination == agent.Position {
+		return fmt.Errorf("you are already at %s", destination)
+	}
+	if _, ok := w.Places[destination]; !ok {
+		return fmt.Errorf("unknown location: %s", destination)
+	}
+	here, ok := w.Places[agent.Position]
+	if !ok || !slices.Contains(here.Exits, destination) {
+		exits := "none"
+		if ok && len(here.Exits) > 0 {
+			exits = strings.Join(here.Exits, ", ")
+		}
+		return fmt.Errorf("you can't get to %s from here (exits: %s)", destination, exits)
+	}
+
+	agent.Position = destination
+	w.addPendingDialogue(agentName, fmt.Sprintf("goes to %s", destination), MessageTypeAction)
+	return nil
+}
+
+// GetHeardMessages returns a copy of the last N messages an agent heard. In a
+// scene with places, agents hear only what was said where they are now.
+func (w *WorldState) GetHeardMessages(agentName string, limit int) []ConversationMessage {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	if len(w.Places) == 0 {
+		start := 0
+		if limit > 0 && limit < len(w.ConversationHistory) {
+			start = len(w.ConversationHistory) - limit
+		}
+		return append([]ConversationMessage(nil), w.ConversationHistory[start:]...)
+	}
+
+	here := w.position(agentName)
+	var heard []ConversationMessage
+	for _, msg := range w.ConversationHistory {
+		if msg.Position == here {
+			heard = append(heard, msg)
+		}
+	}
+	if limit > 0 && limit < len(heard) {
+		heard = heard[len(heard)-limit:]
+	}
+	return heard
+}
+
+// agentsAt returns the visible agents at a place, sorted, leaving out one agent.
+// The caller holds the lock.
+func (w *WorldState) agentsAt(position, except string) []string {
+	agents := []string{}
+	for name, agent := range w.Agents {
+		if name != except && agent.Position == position && agent.Visible {
+			agents = append(agents, name)
+		}
+	}
+	sort.Strings(agents)
+	return agents
+}
+
+// MoveToResult contains confirmation of a move.
+type MoveToResult struct {
+	Success  bool     `json:"success"`
+	Location string   `json:"location"`
+	Here     []string `json:"here"` // Agents already there
+	Message  string   `json:"message"`
+}
+
+// NewMoveToTool creates the move_to() MCP tool.
+// This tool allows agents to walk to a neighbouring location.
+func NewMoveToTool(world *WorldState) *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "move_to",
+		Description: "Walk to a neighbouring location. Only agents in the same location as you hear what you say, and you only hear them. Use look_around to see where you can go.",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"location": map[string]interface{}{
+					"type":        "string",
+					"description": "Name of the location to go to; must be one of the exits where you are",
+				},
+			},
+			"required": []string{"location"},
+		},
+		Handler: func(ctx context.Context, arguments map[string]interface{}) (interface{}, error) {
+			// Get agent name from context
+			agentName, ok := ctx.Value(runtime.AgentNameKey).(string)
+			if !ok || agentName == "" {
+				return nil, fmt.Errorf("agent_name not found in context")
+			}
+
+			destination, ok := arguments["location"].(string)
+			if !ok || destination == "" {
+				return nil, fmt.Errorf("location parameter is required and must be a string")
+			}
+
+			if err := world.MoveTo(agentName, destination); err != nil {
+				return nil, err
+			}
+
+			var here []string
+			world.View(func(w *WorldState) {
+				here = w.agentsAt(destination, agentName)
+			})
+			return &MoveToResult{
+				Success:  true,
+				Location: destination,
+				Here:     here,
+				Message:  fmt.Sprintf("You go to %s", destination),
+			}, nil
+		},
+	}
+}
+
+// ExitView is a neighbouring location as seen from where an agent stands.
+type ExitView struct {
+	Location string   `json:"location"`
+	Agents   []string `json:"agents"` // Agents visible there
+}
+
+// LookAroundResult describes an agent's location and the ways out of it.
+type LookAroundResult struct {
+	Location    string     `json:"location"`
+	Description string     `json:"description,omitempty"`
+	Here        []string   `json:"here"`              // Other agents in the same location
+	Objects     []string   `json:"objects,omitempty"` // Objects lying here
+	Exits       []ExitView `json:"exits"`
+}
+
+// NewLookAroundTool creates the look_around() MCP tool.
+// This tool allows agents to see where they are, who is with them, and where they can go.
+func NewLookAroundTool(world *WorldState) *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "look_around",
+		Description: "Look around: see where you are, who is here with you, and which locations you can move to and who is there",
+		InputSchema: map[string]interface{}{
+			"type":       "object",
+			"properties": map[string]interface{}{},
+			"required":   []string{},
+		},
+		Handler: func(ctx context.Context, arguments map[string]interface{}) (interface{}, error) {
+			// Get agent name from context
+			agentName, ok := ctx.Value(runtime.AgentNameKey).(string)
+			if !ok || agentName == "" {
+				return nil, fmt.Errorf("agent_name not found in context")
+			}
+
+			var result *LookAroundResult
+			var err error
+			world.View(func(w *WorldState) {
+				agent, ok := w.Agents[agentName]
+				if !ok {
+					err = fmt.Errorf("agent %s not found in world", agentName)
+					return
+				}
+				result = &LookAroundResult{
+					Location: agent.Position,
+					Here:     w.agentsAt(agent.Position, agentName),
+					Exits:    []ExitView{},
+				}
+				var lying []*WorldObject
+				for _, object := range w.Objects {
+					if w.withinReach(agent, object) {
+						lying = append(lying, object)
+					}
+				}
+				if len(lying) > 0 {
+					result.Objects = describeObjects(lying)
+				}
+				if place, ok := w.Places[agent.Position]; ok {
+					result.Description = place.Description
+					for _, exit := range place.Exits {
+						result.Exits = append(result.Exits, ExitView{Location: exit, Agents: w.agentsAt(exit, "")})
+					}
+				}
+			})
+			if err != nil {
+				return nil, err
+			}
+			return result, nil
+		},
+	}
+}
@@ -0,0 +1,256 @@
+package simulation
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/poiesic/wonda/internal/mcp"
+	"github.com/poiesic/wonda/internal/runtime"
+)
+
+// NegotiationRules describe the two sides of a negotiation goal, such as a
+// buyer and a seller.
+type NegotiationRules struct {
+	Sides map[string][]string // Agents on each side, by side name
+	Term  string              // Optional: the number every offer names, such as "price"
+}
+
+// SideOf returns the side an agent negotiates for, or "" if they're on neither.
+func (r *NegotiationRules) SideOf(agentName string) string {
+	for side, agents := range r.Sides {
+		for _, member := range agents {
+			if member == agentName {
+				return side
+			}
+		}
+	}
+	return ""
+}
+
+// Evaluate resolves an offer: it is accepted once every agent on both sides
+// has voted yes, and rejected as soon as anyone votes no.
+func (r *NegotiationRules) Evaluate(p *Proposal, turn int) {
+	if p.Status != ProposalPending {
+		return
+	}
+	accepted := true
+	for _, agents := range r.Sides {
+		for _, agentName := range agents {
+			vote, ok := p.Votes[agentName]
+			switch {
+			case ok && vote.Choice == "no":
+				p.Status = ProposalRejected
+				p.ResolvedAt = turn
+				return
+			case !ok:
+				accepted = false
+			}
+		}
+	}
+	if accepted {
+		p.Status = ProposalAccepted
+		p.ResolvedAt = turn
+	}
+}
+
+// Offer is one offer in a negotiation's history.
+type Offer struct {
+	ProposalID string
+	Side       string
+	ProposedBy string
+	Turn       int
+	Terms      string
+	Amount     *float64
+	Counters   string  // The offer this one answered, if it was a counter-proposal
+	Concession float64 // How far the side moved its amount from its previous offer; 0 for its first
+	Status     ProposalStatus
+}
+
+// Offers returns a negotiation's offers in the order they were made, with how
+// far each side conceded from one offer to its next.
+func (g *InteractiveGoal) Offers() []Offer {
+	if g.Negotiation == nil {
+		return nil
+	}
+	proposals := make([]*Proposal, 0, len(g.Proposals))
+	for _, proposal := range g.Proposals {
+		proposals = append(proposals, proposal)
+	}
+	sort.Slice(proposals, func(i, j int) bool {
+		return ProposalNumber(proposals[i].ID) < ProposalNumber(proposals[j].ID)
+	})
+
+	last := make(map[string]*float64)
+	offers := make([]Offer, 0, len(proposals))
+	for _, proposal := range proposals {
+		offer := Offer{
+			ProposalID: proposal.ID,
+			Side:       g.Negotiation.SideOf(proposal.ProposedBy),
+			ProposedBy: proposal.ProposedBy,
+			Turn:       proposal.ProposedAt,
+			Terms:      proposal.Description,
+			Amount:     proposal.Amount,
+			Counters:   proposal.Counters,
+			Status:     proposal.Status,
+		}
+		if proposal.Amount != nil {
+			if previous := last[offer.Side]; previous != nil {
+				offer.Concession = math.Abs(*proposal.Amount - *previous)
+			}
+			last[offer.Side] = proposal.Amount
+		}
+		offers = append(offers, offer)
+	}
+	return offers
+}
+
+// AddCounterProposal answers a pending offer from the other side with a new
+// one: the agent's no vote rejects the offer countered, and the new offer
+// records which it answers.
+func (g *InteractiveGoal) AddCounterProposal(agentName, description, counters string, amount *float64, turn int) (string, error) {
+	if g.Negotiation == nil {
+		return "", fmt.Errorf("%s isn't a negotiation - propose a solution instead", g.Name)
+	}
+	side := g.Negotiation.SideOf(agentName)
+	parent, ok := g.Proposals[counters]
+	if !ok {
+		return "", fmt.Errorf("proposal not found: %s", counters)
+	}
+	if parent.Status != ProposalPending {
+		return "", fmt.Errorf("%s is %s - you can only counter an offer still on the table", counters, parent.Status)
+	}
+	if g.Negotiation.SideOf(parent.ProposedBy) == side {
+		return "", fmt.Errorf("%s is your own side's offer - vote on it, or propose a different one", counters)
+	}
+
+	if err := g.Vote(counters, agentName, "no", turn); err != nil {
+		return "", err
+	}
+	g.Negotiation.Evaluate(parent, turn)
+
+	proposalID := g.AddProposal(agentName, description, turn)
+	g.Proposals[proposalID].Counters = counters
+	g.Proposals[proposalID].Amount = amount
+	return proposalID, nil
+}
+
+// offerAmount reads the amount an offer on a negotiation goal names. Goals
+// with a term require one; it is ignored for other goals.
+func offerAmount(goal *InteractiveGoal, arguments map[string]interface{}) (*float64, error) {
+	if goal.Negotiation == nil || goal.Negotiation.Term == "" {
+		return nil, nil
+	}
+	amount, ok := arguments["amount"].(float64)
+	if !ok {
+		return nil, fmt.Errorf("amount is required - the %s you are offering", goal.Negotiation.Term)
+	}
+	return &amount, nil
+}
+
+// NewCounterProposalTool creates the counter_proposal MCP tool.
+// Allows agents in a negotiation to answer the other side's offer with their own.
+func NewCounterProposalTool(world *WorldState) *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "counter_proposal",
+		Description: "In a negotiation, turn down the other side's offer and make a counter-offer in its place. The negotiation is settled when both sides accept the same offer.",
+		EndsTurn:    true,
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"goal_name": map[string]interface{}{
+					"type":        "string",
+					"description": "Name of the negotiation goal",
+				},
+				"proposal_id": map[string]interface{}{
+					"type":        "string",
+					"description": "ID of the other side's offer you are countering (from view_goal)",
+				},
+				"solution": map[string]interface{}{
+					"type":        "string",
+					"description": "Your counter-offer - the complete terms you would agree to",
+				},
+				"amount": map[string]interface{}{
+					"type":        "number",
+					"description": "For negotiations over a number (see the term in view_goal): the amount you are offering",
+				},
+				"comment": map[string]interface{}{
+					"type":        "string",
+					"description": "What you SAY out loud as you counter - an in-character statement of why their offer won't do and what you'd take instead",
+				},
+			},
+			"required": []string{"goal_name", "proposal_id", "solution", "comment"},
+		},
+		Handler: func(ctx context.Context, arguments map[string]interface{}) (interface{}, error) {
+			agentName, ok := ctx.Value(runtime.AgentNameKey).(string)
+			if !ok || agentName == "" {
+				return nil, fmt.Errorf("agent_name not found in context")
+			}
+
+			goalName, ok := arguments["goal_name"].(string)
+			if !ok {
+				return nil, fmt.Errorf("goal_name is required")
+			}
+			counters, ok := arguments["proposal_id"].(string)
+			if !ok {
+				return nil, fmt.Errorf("proposal_id is required")
+			}
+			solution, ok := arguments["solution"].(string)
+			if !ok || solution == "" {
+				return nil, fmt.Errorf("solution is required and must be a string")
+			}
+			comment, ok := arguments["comment"].(string)
+			if !ok || comment == "" {
+				return nil, fmt.Errorf("comment is required - you must say something as you counter")
+			}
+
+			var proposalID string
+			err := world.Update(func(w *WorldState) error {
+				goal, ok := w.Goals[goalName]
+				if !ok {
+					return fmt.Errorf("goal not found: %s", goalName)
+				}
+				if goal.Status != GoalPending {
+					return fmt.Errorf("cannot counter offers on %s goals", goal.Status)
+				}
+				// Even a refused attempt shows what the agent is thinking about
+				w.setFocus(agentName, goalName)
+
+				if !w.CanDecide(goal, agentName) {
+					return fmt.Errorf("you have no say in %s - you can still speak your mind", goalName)
+				}
+				for _, proposal := range goal.Proposals {
+					if proposal.ProposedBy == agentName && proposal.ProposedAt == w.CurrentTurn {
+						return fmt.Errorf("you already made an offer for this goal this turn")
+					}
+				}
+				amount, err := offerAmount(goal, arguments)
+				if err != nil {
+					return err
+				}
+				if err := goal.checkForbidden(solution); err != nil {
+					return err
+				}
+
+				proposalID, err = goal.AddCounterProposal(agentName, solution, counters, amount, w.CurrentTurn)
+				if err != nil {
+					return err
+				}
+				w.addProposalDialogue(agentName, comment, goalName, proposalID, solution)
+
+				// Agents always support their own offers
+				return goal.Vote(proposalID, agentName, "yes", w.CurrentTurn)
+			})
+			if err != nil {
+				return nil, err
+			}
+
+			return map[string]interface{}{
+				"success":     true,
+				"proposal_id": proposalID,
+				"message":     fmt.Sprintf("Turned down %s and offered: %s (auto-voted yes)", counters, solution),
+			}, nil
+		},
+	}
+}
@@ -0,0 +1,102 @@
+package simulation
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNegotiationGoal(t *testing.T) {
+	newNegotiation := func() *WorldState {
+		world := newTestWorld(3)
+		goal := NewInteractiveGoal("sale", "Agree a price for the car", "negotiation", 1)
+		goal.Negotiation = &NegotiationRules{
+			Sides: map[string][]string{"buyer": {"agent0"}, "seller": {"agent1", "agent2"}},
+			Term:  "price",
+		}
+		world.AddGoal(goal)
+		return world
+	}
+	propose := func(world *WorldState, agentName string, amount float64) (string, error) {
+		result, err := NewProposeSolutionTool(world).Handler(agentContext(agentName), map[string]interface{}{
+			"goal_name": "sale",
+			"solution":  fmt.Sprintf("The car for $%.0f", amount),
+			"amount":    amount,
+			"comment":   "Here's my offer",
+		})
+		if err != nil {
+			return "", err
+		}
+		return result.(map[string]interface{})["proposal_id"].(string), nil
+	}
+	counter := func(world *WorldState, agentName, proposalID string, amount float64) (string, error) {
+		result, err := NewCounterProposalTool(world).Handler(agentContext(agentName), map[string]interface{}{
+			"goal_name":   "sale",
+			"proposal_id": proposalID,
+			"solution":    fmt.Sprintf("The car for $%.0f", amount),
+			"amount":      amount,
+			"comment":     "I can't do that",
+		})
+		if err != nil {
+			return "", err
+		}
+		return result.(map[string]interface{})["proposal_id"].(string), nil
+	}
+	vote := func(world *WorldState, agentName, proposalID string) error {
+		_, err := NewVoteOnProposalTool(world).Handler(agentContext(agentName), map[string]interface{}{
+			"goal_name":   "sale",
+			"proposal_id": proposalID,
+			"vote":        "yes",
+			"comment":     "Deal",
+		})
+		return err
+	}
+
+	t.Run("offers name the term", func(t *testing.T) {
+		world := newNegotiation()
+		_, err := NewProposeSolutionTool(world).Handler(agentContext("agent0"), map[string]interface{}{
+			"goal_name": "sale",
+			"solution":  "A fair price",
+			"comment":   "Let's talk",
+		})
+		assert.ErrorContains(t, err, "amount is required - the price")
+	})
+
+	t.Run("counter-offers answer the other side", func(t *testing.T) {
+		world := newNegotiation()
+		opening, err := propose(world, "agent1", 9000)
+		require.NoError(t, err)
+
+		_, err = counter(world, "agent2", opening, 8500)
+		assert.ErrorContains(t, err, "your own side's offer")
+
+		world.SetTurn(2)
+		answer, err := counter(world, "agent0", opening, 7000)
+		require.NoError(t, err)
+		goal := world.Snapshot().Goals["sale"]
+		assert.Equal(t, ProposalRejected, goal.Proposals[opening].Status)
+		assert.Equal(t, opening, goal.Proposals[answer].Counters)
+
+		_, err = counter(world, "agent1", opening, 8800)
+		assert.ErrorContains(t, err, "only counter an offer still on the table")
+
+		world.SetTurn(3)
+		final, err := counter(world, "agent1", answer, 8000)
+		require.NoError(t, err)
+
+		offers := world.Snapshot().Goals["sale"].Offers()
+		require.Len(t, offers, 3)
+		assert.Equal(t, "seller", offers[2].Side)
+		assert.Equal(t, 1000.0, offers[2].Concession, "the seller came down from 9000")
+		assert.Zero(t, offers[1].Concession, "the buyer's first offer")
+
+		require.NoError(t, vote(world, "agent2", final))
+		assert.Equal(t, GoalPending, world.Snapshot().Goals["sale"].Status, "the buyer hasn't accepted")
+		require.NoError(t, vote(world, "agent0", final))
+		goal = world.Snapshot().Goals["sale"]
+		assert.Equal(t, GoalCompleted, goal.Status)
+		assert.Equal(t, 8000.0, *goal.Proposals[final].Amount)
+	})
+}
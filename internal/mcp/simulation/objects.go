@@ -0,0 +1,288 @@
+package simulation
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/poiesic/wonda/internal/mcp"
+	"github.com/poiesic/wonda/internal/runtime"
+)
+
+// WorldObject is a thing in the scene. It either lies somewhere or is held by
+// an agent, who carries it wherever they go.
+type WorldObject struct {
+	Name        string
+	Description string // What agents see at a glance
+	Details     string // What inspecting it reveals
+	Position    string // Where it lies, when no one holds it; "" in a scene without places
+	Holder      string // Agent holding it, or ""
+	Fixed       bool   // Can't be picked up
+}
+
+// AddObject adds an object to the scene.
+func (w *WorldState) AddObject(object WorldObject) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.Objects[object.Name] = &object
+}
+
+// withinReach reports whether an object lies where an agent can pick it up:
+// anywhere in a scene without places, otherwise where the agent is. The
+// caller holds the lock.
+func (w *WorldState) withinReach(agent *AgentInWorld, object *WorldObject) bool {
+	return object.Holder == "" && (len(w.Places) == 0 || object.Position == agent.Position)
+}
+
+// inSight reports whether an agent can see an object: it is theirs, within
+// reach, or held by someone visible nearby. The caller holds the lock.
+func (w *WorldState) inSight(agent *AgentInWorld, object *WorldObject) bool {
+	if object.Holder == agent.Name || w.withinReach(agent, object) {
+		return true
+	}
+	holder, ok := w.Agents[object.Holder]
+	return ok && holder.Position == agent.Position && holder.Visible
+}
+
+// describeObjects lists objects as "name: description", sorted by name.
+func describeObjects(objects []*WorldObject) []string {
+	sort.Slice(objects, func(i, j int) bool { return objects[i].Name < objects[j].Name })
+	described := make([]string, 0, len(objects))
+	for _, object := range objects {
+		described = append(described, fmt.Sprintf("%s: %s", object.Name, object.Description))
+	}
+	return described
+}
+
+// Inventory describes the objects an agent holds.
+func (w *WorldState) Inventory(agentName string) []string {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	var held []*WorldObject
+	for _, object := range w.Objects {
+		if object.Holder == agentName {
+			held = append(held, object)
+		}
+	}
+	return describeObjects(held)
+}
+
+// ObjectsInReach describes the objects lying where an agent can pick them up.
+func (w *WorldState) ObjectsInReach(agentName string) []string {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	agent, ok := w.Agents[agentName]
+	if !ok {
+		return nil
+	}
+	var lying []*WorldObject
+	for _, object := range w.Objects {
+		if w.withinReach(agent, object) {
+			lying = append(lying, object)
+		}
+	}
+	return describeObjects(lying)
+}
+
+// PickUp gives an agent an object lying within their reach. It is added to the
+// pending dialogue as an action so the simulation chronicles it.
+func (w *WorldState) PickUp(agentName, objectName string) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	agent, ok := w.Agents[agentName]
+	if !ok {
+		return fmt.Errorf("agent %s not found in world", agentName)
+	}
+	object, ok := w.Objects[objectName]
+	switch {
+	case !ok || !w.inSight(agent, object):
+		return fmt.Errorf("there is no %s here", objectName)
+	case object.Holder == agentName:
+		return fmt.Errorf("you already have the %s", objectName)
+	case object.Holder != "":
+		return fmt.Errorf("%s has the %s - ask them for it", object.Holder, objectName)
+	case object.Fixed:
+		return fmt.Errorf("the %s can't be picked up", objectName)
+	}
+
+	object.Holder = agentName
+	object.Position = ""
+	w.addPendingDialogue(agentName, fmt.Sprintf("picks up the %s", objectName), MessageTypeAction)
+	return nil
+}
+
+// Give hands an object from one agent to another nearby. It is added to the
+// pending dialogue as an action so the simulation chronicles it.
+func (w *WorldState) Give(from, to, objectName string) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	giver, ok := w.Agents[from]
+	if !ok {
+		return fmt.Errorf("agent %s not found in world", from)
+	}
+	recipient, ok := w.Agents[to]
+	if !ok {
+		return fmt.Errorf("agent %s not found in world", to)
+	}
+	object, ok := w.Objects[objectName]
+	if !ok || object.Holder != from {
+		return fmt.Errorf("you don't have a %s", objectName)
+	}
+	if to == from {
+		return fmt.Errorf("you already have the %s", objectName)
+	}
+	if recipient.Position != giver.Position || !recipient.Visible {
+		return fmt.Errorf("%s is not close enough to hand anything to", to)
+	}
+
+	object.Holder = to
+	w.addPendingDialogue(from, fmt.Sprintf("gives the %s to %s", objectName, to), MessageTypeAction)
+	return nil
+}
+
+// ObjectResult describes an object an agent looked at, picked up or gave.
+type ObjectResult struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Details     string `json:"details,omitempty"`
+	HeldBy      string `json:"held_by,omitempty"`
+	Fixed       bool   `json:"fixed,omitempty"`
+	Message     string `json:"message,omitempty"`
+}
+
+// NewPickUpTool creates the pick_up() MCP tool.
+// This tool allows agents to take an object lying near them.
+func NewPickUpTool(world *WorldState) *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "pick_up",
+		Description: "Pick up an object lying near you, so you carry it wherever you go. Objects other agents hold can only be given to you.",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"object": map[string]interface{}{
+					"type":        "string",
+					"description": "Name of the object to pick up",
+				},
+			},
+			"required": []string{"object"},
+		},
+		Handler: func(ctx context.Context, arguments map[string]interface{}) (interface{}, error) {
+			agentName, ok := ctx.Value(runtime.AgentNameKey).(string)
+			if !ok || agentName == "" {
+				return nil, fmt.Errorf("agent_name not found in context")
+			}
+			objectName, ok := arguments["object"].(string)
+			if !ok || objectName == "" {
+				return nil, fmt.Errorf("object parameter is required and must be a string")
+			}
+
+			if err := world.PickUp(agentName, objectName); err != nil {
+				return nil, err
+			}
+			return &ObjectResult{
+				Name:    objectName,
+				HeldBy:  agentName,
+				Message: fmt.Sprintf("You pick up the %s", objectName),
+			}, nil
+		},
+	}
+}
+
+// NewGiveTool creates the give() MCP tool.
+// This tool allows agents to hand an object they hold to a nearby agent.
+func NewGiveTool(world *WorldState) *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "give",
+		Description: "Hand an object you're holding to an agent near you",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"object": map[string]interface{}{
+					"type":        "string",
+					"description": "Name of the object to give",
+				},
+				"recipient": map[string]interface{}{
+					"type":        "string",
+					"description": "Name of the agent to give it to",
+				},
+			},
+			"required": []string{"object", "recipient"},
+		},
+		Handler: func(ctx context.Context, arguments map[string]interface{}) (interface{}, error) {
+			agentName, ok := ctx.Value(runtime.AgentNameKey).(string)
+			if !ok || agentName == "" {
+				return nil, fmt.Errorf("agent_name not found in context")
+			}
+			objectName, ok := arguments["object"].(string)
+			if !ok || objectName == "" {
+				return nil, fmt.Errorf("object parameter is required and must be a string")
+			}
+			recipient, ok := arguments["recipient"].(string)
+			if !ok || recipient == "" {
+				return nil, fmt.Errorf("recipient parameter is required and must be a string")
+			}
+
+			if err := world.Give(agentName, recipient, objectName); err != nil {
+				return nil, err
+			}
+			return &ObjectResult{
+				Name:    objectName,
+				HeldBy:  recipient,
+				Message: fmt.Sprintf("You give the %s to %s", objectName, recipient),
+			}, nil
+		},
+	}
+}
+
+// NewInspectObjectTool creates the inspect_object() MCP tool.
+// This tool allows agents to take a close look at an object they can see.
+func NewInspectObjectTool(world *WorldState) *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "inspect_object",
+		Description: "Take a close look at an object you're holding, one near you, or one someone near you is holding",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"object": map[string]interface{}{
+					"type":        "string",
+					"description": "Name of the object to inspect",
+				},
+			},
+			"required": []string{"object"},
+		},
+		Handler: func(ctx context.Context, arguments map[string]interface{}) (interface{}, error) {
+			agentName, ok := ctx.Value(runtime.AgentNameKey).(string)
+			if !ok || agentName == "" {
+				return nil, fmt.Errorf("agent_name not found in context")
+			}
+			objectName, ok := arguments["object"].(string)
+			if !ok || objectName == "" {
+				return nil, fmt.Errorf("object parameter is required and must be a string")
+			}
+
+			var result *ObjectResult
+			world.View(func(w *WorldState) {
+				agent, ok := w.Agents[agentName]
+				object, found := w.Objects[objectName]
+				if !ok || !found || !w.inSight(agent, object) {
+					return
+				}
+				result = &ObjectResult{
+					Name:        object.Name,
+					Description: object.Description,
+					Details:     object.Details,
+					HeldBy:      object.Holder,
+					Fixed:       object.Fixed,
+				}
+			})
+			if result == nil {
+				return nil, fmt.Errorf("there is no %s here", objectName)
+			}
+			return result, nil
+		},
+	}
+}
@@ -0,0 +1,51 @@
+package simulation
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/poiesic/wonda/internal/mcp"
+	"github.com/poiesic/wonda/internal/runtime"
+)
+
+// PassTurnResult contains confirmation of a pass.
+type PassTurnResult struct {
+	Success bool   `json:"success"`
+	Message string `json:"message"`
+}
+
+// NewPassTurnTool creates the pass_turn() MCP tool.
+// This tool lets agents with nothing to add end their turn without speaking.
+func NewPassTurnTool(world *WorldState) *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "pass_turn",
+		Description: "Let your turn go by without speaking or acting. Use this when you genuinely have nothing to add right now - others will carry on and you can join in later.",
+		EndsTurn:    true,
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"reason": map[string]interface{}{
+					"type":        "string",
+					"description": "Optional: why you're holding back, in first person. EXAMPLES: \"I've said my piece\" or \"I want to hear what Sam thinks first\"",
+				},
+			},
+		},
+		Handler: func(ctx context.Context, arguments map[string]interface{}) (interface{}, error) {
+			// Get agent name from context
+			agentName, ok := ctx.Value(runtime.AgentNameKey).(string)
+			if !ok || agentName == "" {
+				return nil, fmt.Errorf("agent_name not found in context")
+			}
+
+			reason, _ := arguments["reason"].(string)
+
+			// Add pass to pending dialogue (will be captured by simulation)
+			world.AddPendingDialogue(agentName, reason, MessageTypePass)
+
+			return &PassTurnResult{
+				Success: true,
+				Message: "You pass for now",
+			}, nil
+		},
+	}
+}
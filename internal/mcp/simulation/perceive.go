@@ -14,7 +14,12 @@ type PerceptionResult struct {
 	Atmosphere     string   `json:"atmosphere"`
 	Position       string   `json:"your_position"`
 	NearbyAgents   []string `json:"nearby_agents"`
+	Exits          []string `json:"exits,omitempty"`        // Locations you can move to, when the scene has several
+	Inventory      []string `json:"inventory,omitempty"`    // Objects you hold
+	ObjectsHere    []string `json:"objects_here,omitempty"` // Objects lying within reach
 	RecentMessages []string `json:"recent_messages"`
+	Whispers       []string `json:"whispers,omitempty"` // Private messages to and from you
+	AmbientEvents  []string `json:"ambient_events,omitempty"`
 }
 
 // NewPerceiveTool creates the perceive() MCP tool.
@@ -35,28 +40,55 @@ func NewPerceiveTool(world *WorldState) *mcp.Tool {
 				return nil, fmt.Errorf("agent_name not found in context")
 			}
 
+			// Observe a consistent copy of the world
+			snapshot := world.Snapshot()
+
 			// Get agent's position
-			agent, ok := world.Agents[agentName]
+			agent, ok := snapshot.Agents[agentName]
 			if !ok {
 				return nil, fmt.Errorf("agent %s not found in world", agentName)
 			}
 
 			// Find nearby agents
-			nearbyAgents := world.GetNearbyAgents(agentName)
+			nearbyAgents := snapshot.GetNearbyAgents(agentName)
 
-			// Get recent conversation (last 5 messages)
+			// Get recent conversation within earshot (last 5 messages)
 			recentMessages := make([]string, 0)
-			messages := world.GetRecentMessages(5)
+			messages := snapshot.GetHeardMessages(agentName, 5)
 			for _, msg := range messages {
+				if snapshot.ReasoningShared && msg.Rationale != "" {
+					recentMessages = append(recentMessages, fmt.Sprintf("%s: %s (reasoning: %s)", msg.AgentName, msg.Content, msg.Rationale))
+					continue
+				}
 				recentMessages = append(recentMessages, fmt.Sprintf("%s: %s", msg.AgentName, msg.Content))
 			}
 
+			// Get recent whispers only this agent was party to (last 5)
+			var whispers []string
+			for _, msg := range snapshot.GetRecentWhispers(agentName, 5) {
+				if msg.AgentName == agentName {
+					whispers = append(whispers, fmt.Sprintf("You (whispering to %s): %s", msg.Recipient, msg.Content))
+				} else {
+					whispers = append(whispers, fmt.Sprintf("%s (whispering to you): %s", msg.AgentName, msg.Content))
+				}
+			}
+
+			var exits []string
+			if place, ok := snapshot.Places[agent.Position]; ok {
+				exits = place.Exits
+			}
+
 			return &PerceptionResult{
-				Location:       world.Location,
-				Atmosphere:     world.Atmosphere,
+				Location:       snapshot.Location,
+				Atmosphere:     snapshot.Atmosphere,
 				Position:       agent.Position,
 				NearbyAgents:   nearbyAgents,
+				Exits:          exits,
+				Inventory:      snapshot.Inventory(agentName),
+				ObjectsHere:    snapshot.ObjectsInReach(agentName),
 				RecentMessages: recentMessages,
+				Whispers:       whispers,
+				AmbientEvents:  snapshot.AmbientEvents,
 			}, nil
 		},
 	}
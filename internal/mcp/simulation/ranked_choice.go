@@ -0,0 +1,319 @@
+package simulation
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/poiesic/wonda/internal/mcp"
+	"github.com/poiesic/wonda/internal/runtime"
+)
+
+// PhaseRanking is the phase in which the deciding agents rank a goal's
+// proposals and an instant-runoff count picks one: how ranked-choice goals
+// are decided, and the last resort of other goals on their final turn.
+const PhaseRanking Phase = "ranking"
+
+// Ranked reports whether the goal is decided by ranking its proposals rather
+// than by yes/no votes.
+func (g *InteractiveGoal) Ranked() bool {
+	return g.Type == "ranked"
+}
+
+// Support returns the largest share of the participants' backing that any
+// proposal has at the end of a turn: yes votes over participants, by voting
+// weight for majority and weighted vote goals. Proposals still pending count,
+// as do those rejected that turn, since they show how close the agents came.
+func (g *InteractiveGoal) Support(participants []string, turn int) float64 {
+	weight := func(agentName string) float64 {
+		if g.Voting != nil {
+			return g.Voting.Weight(agentName)
+		}
+		return 1
+	}
+	total := 0.0
+	for _, agentName := range participants {
+		total += weight(agentName)
+	}
+	if total == 0 {
+		return 0
+	}
+
+	best := 0.0
+	for _, proposal := range g.Proposals {
+		if proposal.Status != ProposalPending && (proposal.Status == ProposalWithdrawn || proposal.ResolvedAt != turn) {
+			continue
+		}
+		yes := 0.0
+		for _, agentName := range participants {
+			if vote, ok := proposal.Votes[agentName]; ok && vote.Choice == "yes" {
+				yes += weight(agentName)
+			}
+		}
+		best = max(best, yes/total)
+	}
+	return best
+}
+
+// SupportNeeded returns the share of the participants' backing a proposal
+// needs to be accepted: the threshold of a vote, the smallest share of yes
+// votes that satisfies a consensus rule once everyone has voted, or all of
+// them for unanimous agreement.
+func (g *InteractiveGoal) SupportNeeded(participants int) float64 {
+	if g.Voting != nil {
+		return max(g.Voting.Threshold, 0.5)
+	}
+	if g.Consensus == nil || participants == 0 {
+		return 1
+	}
+	for yes := 1; yes <= participants; yes++ {
+		accepted, err := g.Consensus.Bool(map[string]float64{
+			"yes":      float64(yes),
+			"no":       float64(participants - yes),
+			"voted":    float64(participants),
+			"pending":  0,
+			"assigned": float64(participants),
+		})
+		if err == nil && accepted {
+			return float64(yes) / float64(participants)
+		}
+	}
+	return 1
+}
+
+// RankedChoiceCandidates returns the proposals a goal's ranked-choice vote
+// chooses between, in the order they were made: every proposal not withdrawn,
+// rejected ones included, that satisfies the goal's allocation rules and
+// isn't a forbidden outcome.
+func (g *InteractiveGoal) RankedChoiceCandidates() []*Proposal {
+	var candidates []*Proposal
+	for _, proposal := range g.Proposals {
+		if proposal.Status == ProposalWithdrawn {
+			continue
+		}
+		if g.Allocation != nil && len(g.Allocation.Check(proposal.Allocation)) > 0 {
+			continue
+		}
+		if g.ForbiddenReason(proposal.Description) != "" {
+			continue
+		}
+		candidates = append(candidates, proposal)
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		return ProposalNumber(candidates[i].ID) < ProposalNumber(candidates[j].ID)
+	})
+	return candidates
+}
+
+// ProposalNumber returns the sequence number in a proposal ID, so proposal_10
+// sorts after proposal_9.
+func ProposalNumber(id string) int {
+	n, err := strconv.Atoi(id[strings.LastIndex(id, "_")+1:])
+	if err != nil {
+		return math.MaxInt
+	}
+	return n
+}
+
+// RunoffRound is one round of an instant-runoff count: the ballots counting
+// for each candidate still standing, and the candidate eliminated, if the
+// round didn't decide the count.
+type RunoffRound struct {
+	Counts     map[string]int
+	Eliminated string
+}
+
+// InstantRunoff counts ranked ballots: each round every ballot counts for its
+// highest-ranked candidate still standing, a candidate with a majority of
+// those ballots wins, and otherwise the candidate with the fewest is
+// eliminated (the later in candidates on a tie). Ballots whose candidates
+// have all been eliminated drop out. It returns false if no ballot ranks any
+// candidate.
+func InstantRunoff(candidates []string, ballots [][]string) (string, bool) {
+	winner, _, ok := CountRunoff(candidates, ballots)
+	return winner, ok
+}
+
+// CountRunoff counts ranked ballots as InstantRunoff does, also returning the
+// tally of each round.
+func CountRunoff(candidates []string, ballots [][]string) (string, []RunoffRound, bool) {
+	var rounds []RunoffRound
+	standing := append([]string(nil), candidates...)
+	for len(standing) > 0 {
+		counts := make(map[string]int, len(standing))
+		for _, candidate := range standing {
+			counts[candidate] = 0
+		}
+		counted := 0
+		for _, ballot := range ballots {
+			for _, choice := range ballot {
+				if slices.Contains(standing, choice) {
+					counts[choice]++
+					counted++
+					break
+				}
+			}
+		}
+		if counted == 0 {
+			return "", rounds, false
+		}
+		rounds = append(rounds, RunoffRound{Counts: counts})
+
+		fewest := standing[0]
+		for _, candidate := range standing {
+			if 2*counts[candidate] > counted {
+				return candidate, rounds, true
+			}
+			if counts[candidate] <= counts[fewest] {
+				fewest = candidate
+			}
+		}
+		if len(standing) == 1 {
+			return standing[0], rounds, true
+		}
+		rounds[len(rounds)-1].Eliminated = fewest
+		standing = slices.DeleteFunc(standing, func(candidate string) bool { return candidate == fewest })
+	}
+	return "", rounds, false
+}
+
+// ResolveRankedChoice settles a pending goal by instant-runoff over its
+// agents' rankings: the winning proposal is accepted, the others still
+// pending are rejected, the goal is completed, and the count's rounds are
+// kept. It returns the winner, or nil if no one ranked the goal's proposals.
+func (w *WorldState) ResolveRankedChoice(goalName string, turn int) (*Proposal, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	goal, ok := w.Goals[goalName]
+	if !ok {
+		return nil, fmt.Errorf("goal not found: %s", goalName)
+	}
+	if goal.Status != GoalPending {
+		return nil, fmt.Errorf("cannot resolve %s goal %s", goal.Status, goalName)
+	}
+
+	var candidates []string
+	for _, proposal := range goal.RankedChoiceCandidates() {
+		candidates = append(candidates, proposal.ID)
+	}
+	var ballots [][]string
+	for _, agentName := range w.GoalParticipants(goal) {
+		if ballot, ok := goal.Rankings[agentName]; ok {
+			ballots = append(ballots, ballot)
+		}
+	}
+	winnerID, rounds, ok := CountRunoff(candidates, ballots)
+	if !ok {
+		return nil, nil
+	}
+
+	winner := goal.Proposals[winnerID]
+	winner.Status = ProposalAccepted
+	winner.ResolvedAt = turn
+	goal.RankedChoice = true
+	goal.Runoff = rounds
+	goal.CheckConsensus(turn)
+	copied := *winner
+	return &copied, nil
+}
+
+// NewRankProposalsTool creates the rank_proposals MCP tool.
+// Allows agents to rank a goal's proposals in a ranked-choice vote.
+func NewRankProposalsTool(world *WorldState) *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "rank_proposals",
+		Description: "Rank a goal's proposals from most to least preferred. The proposal most agents prefer after eliminating the least popular ones is adopted.",
+		EndsTurn:    true,
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"goal_name": map[string]interface{}{
+					"type":        "string",
+					"description": "Name of the goal",
+				},
+				"ranking": map[string]interface{}{
+					"type":        "array",
+					"items":       map[string]interface{}{"type": "string"},
+					"description": "Proposal IDs, most preferred first. Leave out any you could never accept.",
+				},
+				"comment": map[string]interface{}{
+					"type":        "string",
+					"description": "What you SAY out loud as you make your choice - an in-character statement about which option you'd pick and why",
+				},
+			},
+			"required": []string{"goal_name", "ranking", "comment"},
+		},
+		Handler: func(ctx context.Context, arguments map[string]interface{}) (interface{}, error) {
+			agentName, ok := ctx.Value(runtime.AgentNameKey).(string)
+			if !ok || agentName == "" {
+				return nil, fmt.Errorf("agent_name not found in context")
+			}
+
+			goalName, ok := arguments["goal_name"].(string)
+			if !ok {
+				return nil, fmt.Errorf("goal_name is required")
+			}
+			items, ok := arguments["ranking"].([]interface{})
+			if !ok || len(items) == 0 {
+				return nil, fmt.Errorf("ranking is required and must list at least one proposal ID")
+			}
+			comment, ok := arguments["comment"].(string)
+			if !ok || comment == "" {
+				return nil, fmt.Errorf("comment is required - you must say something as you choose")
+			}
+
+			err := world.Update(func(w *WorldState) error {
+				goal, ok := w.Goals[goalName]
+				if !ok {
+					return fmt.Errorf("goal not found: %s", goalName)
+				}
+				if goal.Status != GoalPending {
+					return fmt.Errorf("cannot rank proposals for %s goals", goal.Status)
+				}
+				w.setFocus(agentName, goalName)
+				if !w.CanDecide(goal, agentName) {
+					return fmt.Errorf("you have no say in %s - you can still speak your mind", goalName)
+				}
+
+				var candidates []string
+				for _, proposal := range goal.RankedChoiceCandidates() {
+					candidates = append(candidates, proposal.ID)
+				}
+				ranking := make([]string, 0, len(items))
+				for _, item := range items {
+					id, ok := item.(string)
+					if !ok {
+						return fmt.Errorf("ranking must list proposal IDs")
+					}
+					if !slices.Contains(candidates, id) {
+						return fmt.Errorf("%s is not one of the proposals to rank (%s)", id, strings.Join(candidates, ", "))
+					}
+					if slices.Contains(ranking, id) {
+						return fmt.Errorf("%s is ranked more than once", id)
+					}
+					ranking = append(ranking, id)
+				}
+
+				if goal.Rankings == nil {
+					goal.Rankings = make(map[string][]string)
+				}
+				goal.Rankings[agentName] = ranking
+				w.addPendingDialogue(agentName, comment, MessageTypeDialogue)
+				return nil
+			})
+			if err != nil {
+				return nil, err
+			}
+
+			return map[string]interface{}{
+				"success": true,
+				"message": "Ranking recorded",
+			}, nil
+		},
+	}
+}
@@ -0,0 +1,115 @@
+package simulation
+
+import (
+	"testing"
+
+	"github.com/poiesic/wonda/internal/expr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRankedChoice(t *testing.T) {
+	t.Run("instant runoff eliminates the least popular", func(t *testing.T) {
+		candidates := []string{"a", "b", "c"}
+		ballots := [][]string{{"a", "c"}, {"a"}, {"b", "c"}, {"b"}, {"c", "b"}}
+		winner, ok := InstantRunoff(candidates, ballots)
+		require.True(t, ok)
+		assert.Equal(t, "b", winner, "c is eliminated and its ballot moves to b")
+
+		_, ok = InstantRunoff(candidates, [][]string{{"d"}})
+		assert.False(t, ok)
+	})
+
+	t.Run("counting keeps each round's tally", func(t *testing.T) {
+		ballots := [][]string{{"a", "c"}, {"a"}, {"b", "c"}, {"b"}, {"c", "b"}}
+		winner, rounds, ok := CountRunoff([]string{"a", "b", "c"}, ballots)
+		require.True(t, ok)
+		assert.Equal(t, "b", winner)
+		assert.Equal(t, []RunoffRound{
+			{Counts: map[string]int{"a": 2, "b": 2, "c": 1}, Eliminated: "c"},
+			{Counts: map[string]int{"a": 2, "b": 3}},
+		}, rounds)
+	})
+
+	t.Run("support counts open proposals and those rejected this turn", func(t *testing.T) {
+		participants := []string{"agent0", "agent1", "agent2", "agent3"}
+		goal := NewInteractiveGoal("dinner", "Pick a restaurant", "consensus", 1)
+		diner := goal.AddProposal("agent0", "The diner", 1)
+		require.NoError(t, goal.Vote(diner, "agent0", "yes", 1))
+		require.NoError(t, goal.Vote(diner, "agent1", "yes", 1))
+		goal.Proposals[diner].Status = ProposalRejected
+		goal.Proposals[diner].ResolvedAt = 1
+		cafe := goal.AddProposal("agent2", "The cafe", 2)
+		require.NoError(t, goal.Vote(cafe, "agent2", "yes", 2))
+
+		assert.Equal(t, 0.5, goal.Support(participants, 1))
+		assert.Equal(t, 0.25, goal.Support(participants, 2))
+		assert.Equal(t, 1.0, goal.SupportNeeded(4), "unanimous without a rule")
+
+		rule, err := expr.Compile("yes > no", []string{"yes", "no", "voted", "pending", "assigned"})
+		require.NoError(t, err)
+		goal.Consensus = rule
+		assert.Equal(t, 0.75, goal.SupportNeeded(4))
+	})
+
+	t.Run("rankings settle the goal", func(t *testing.T) {
+		world := newTestWorld(3)
+		world.Update(func(w *WorldState) error {
+			goal := w.Goals["dinner"]
+			diner := goal.AddProposal("agent0", "The diner", 1)
+			goal.Proposals[diner].Status = ProposalRejected
+			goal.AddProposal("agent1", "The cafe", 1)
+			return nil
+		})
+		tool := NewRankProposalsTool(world)
+		assert.True(t, tool.EndsTurn)
+
+		rank := func(agentName string, ranking ...interface{}) error {
+			_, err := tool.Handler(agentContext(agentName), map[string]interface{}{
+				"goal_name": "dinner",
+				"ranking":   ranking,
+				"comment":   "If I must choose",
+			})
+			return err
+		}
+		assert.ErrorContains(t, rank("agent0", "proposal_3"), "not one of the proposals")
+		assert.ErrorContains(t, rank("agent0", "proposal_1", "proposal_1"), "more than once")
+		require.NoError(t, rank("agent0", "proposal_1", "proposal_2"))
+		require.NoError(t, rank("agent1", "proposal_2"))
+		require.NoError(t, rank("agent2", "proposal_1"))
+
+		winner, err := world.ResolveRankedChoice("dinner", 3)
+		require.NoError(t, err)
+		require.NotNil(t, winner)
+		assert.Equal(t, "proposal_1", winner.ID)
+
+		goal := world.Snapshot().Goals["dinner"]
+		assert.Equal(t, GoalCompleted, goal.Status)
+		assert.True(t, goal.RankedChoice)
+		assert.Equal(t, ProposalRejected, goal.Proposals["proposal_2"].Status)
+		assert.Equal(t, []RunoffRound{{Counts: map[string]int{"proposal_1": 2, "proposal_2": 1}}}, goal.Runoff)
+	})
+
+	t.Run("ranked-choice goals take rankings instead of votes", func(t *testing.T) {
+		world := newTestWorld(2)
+		world.AddGoal(NewInteractiveGoal("movie", "Pick a movie", "ranked", 1))
+
+		result, err := NewProposeSolutionTool(world).Handler(agentContext("agent0"), map[string]interface{}{
+			"goal_name": "movie",
+			"solution":  "The comedy",
+			"comment":   "Something light?",
+		})
+		require.NoError(t, err)
+		assert.Contains(t, result.(map[string]interface{})["message"], "rank it")
+		assert.Empty(t, world.Snapshot().Goals["movie"].Proposals["proposal_1"].Votes, "no yes vote for the proposer")
+		assert.Zero(t, world.ProposalsAwaitingVote("agent1"))
+
+		_, err = NewVoteOnProposalTool(world).Handler(agentContext("agent1"), map[string]interface{}{
+			"goal_name":   "movie",
+			"proposal_id": "proposal_1",
+			"vote":        "yes",
+			"comment":     "Sure",
+		})
+		assert.ErrorContains(t, err, "settled by ranking")
+	})
+}
@@ -0,0 +1,218 @@
+package simulation
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/poiesic/wonda/internal/campaigns"
+)
+
+// Relationship is how one agent feels about another. Value (affinity) and
+// Trust range from campaigns.MinRelationship (bitter enemies, no trust at all)
+// to campaigns.MaxRelationship (devoted allies, complete trust); Familiarity
+// from 0 (strangers) to campaigns.MaxFamiliarity.
+type Relationship struct {
+	From        string `json:"from"`
+	To          string `json:"to"`
+	Value       int    `json:"value"`
+	Trust       int    `json:"trust,omitempty"`
+	Familiarity int    `json:"familiarity,omitempty"`
+	Reason      string `json:"reason,omitempty"` // Why the value last changed
+	Changed     bool   `json:"-"`                // Changed during this simulation
+}
+
+// relationshipKey identifies the relationship from one agent to another.
+type relationshipKey struct {
+	from, to string
+}
+
+// SetRelationship records a relationship carried in from outside the simulation,
+// such as an earlier scenario of the same campaign.
+func (w *WorldState) SetRelationship(rel Relationship) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	rel.Value = clampRelationship(rel.Value)
+	rel.Trust = clampRelationship(rel.Trust)
+	rel.Familiarity = clampFamiliarity(rel.Familiarity)
+	w.Relationships[relationshipKey{rel.From, rel.To}] = &rel
+}
+
+// RelationshipBetween returns how from feels about to; strangers are neutral
+// on every count.
+func (w *WorldState) RelationshipBetween(from, to string) Relationship {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.relationshipBetween(from, to)
+}
+
+// relationshipBetween is RelationshipBetween for callers that hold the lock.
+func (w *WorldState) relationshipBetween(from, to string) Relationship {
+	if rel := w.Relationships[relationshipKey{from, to}]; rel != nil {
+		return *rel
+	}
+	return Relationship{From: from, To: to}
+}
+
+// adjustRelationship changes how much from likes to by delta and trusts them
+// by trustDelta. The caller must hold the world lock.
+func (w *WorldState) adjustRelationship(from, to string, delta, trustDelta int, reason string) (Relationship, error) {
+	if from == to {
+		return Relationship{}, fmt.Errorf("cannot adjust your relationship with yourself")
+	}
+	if _, ok := w.Agents[to]; !ok {
+		return Relationship{}, fmt.Errorf("unknown agent: %s", to)
+	}
+
+	key := relationshipKey{from, to}
+	rel := w.Relationships[key]
+	if rel == nil {
+		rel = &Relationship{From: from, To: to}
+		w.Relationships[key] = rel
+	}
+	rel.Value = clampRelationship(rel.Value + delta)
+	rel.Trust = clampRelationship(rel.Trust + trustDelta)
+	rel.Reason = reason
+	rel.Changed = true
+	return *rel, nil
+}
+
+// RelationshipList returns every relationship, sorted by from and to.
+func (w *WorldState) RelationshipList() []Relationship {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.relationshipList()
+}
+
+// relationshipList is RelationshipList for callers that own the world or hold its lock.
+func (w *WorldState) relationshipList() []Relationship {
+	result := make([]Relationship, 0, len(w.Relationships))
+	for _, rel := range w.Relationships {
+		result = append(result, *rel)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].From != result[j].From {
+			return result[i].From < result[j].From
+		}
+		return result[i].To < result[j].To
+	})
+	return result
+}
+
+// GrowFamiliarity lets agents who heard each other talk this turn get to know
+// each other a little better: each listener's familiarity with each speaker
+// they heard rises by one, however much was said. Call it at the end of a turn.
+func (w *WorldState) GrowFamiliarity() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	heard := make(map[relationshipKey]bool)
+	for _, msg := range w.ConversationHistory {
+		if msg.Turn != w.CurrentTurn {
+			continue
+		}
+		switch msg.Type {
+		case MessageTypeDialogue:
+			for name := range w.Agents {
+				if name != msg.AgentName && (len(w.Places) == 0 || w.position(name) == msg.Position) {
+					heard[relationshipKey{name, msg.AgentName}] = true
+				}
+			}
+		case MessageTypeWhisper:
+			heard[relationshipKey{msg.Recipient, msg.AgentName}] = true
+			heard[relationshipKey{msg.AgentName, msg.Recipient}] = true
+		}
+	}
+
+	for key := range heard {
+		if _, ok := w.Agents[key.from]; !ok || key.from == key.to {
+			continue
+		}
+		rel := w.Relationships[key]
+		if rel == nil {
+			rel = &Relationship{From: key.from, To: key.to}
+			w.Relationships[key] = rel
+		}
+		if rel.Familiarity < campaigns.MaxFamiliarity {
+			rel.Familiarity++
+			rel.Changed = true
+		}
+	}
+}
+
+// RelationshipNotes describes in words how an agent feels about each other
+// agent they have a relationship with, for their prompts.
+func (w *WorldState) RelationshipNotes(agentName string) []string {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	var notes []string
+	for _, rel := range w.relationshipList() {
+		if rel.From != agentName || (rel.Value == 0 && rel.Trust == 0 && rel.Familiarity == 0) {
+			continue
+		}
+		note := fmt.Sprintf("%s: %s, %s, %s", rel.To, describeAffinity(rel.Value), describeTrust(rel.Trust), describeFamiliarity(rel.Familiarity))
+		if rel.Reason != "" {
+			note += fmt.Sprintf(" (%s)", rel.Reason)
+		}
+		notes = append(notes, note)
+	}
+	return notes
+}
+
+// describeAffinity puts how much one agent likes another into words.
+func describeAffinity(value int) string {
+	switch {
+	case value <= -7:
+		return "an enemy"
+	case value <= -3:
+		return "someone you dislike"
+	case value < 0:
+		return "someone you're wary of"
+	case value == 0:
+		return "someone you feel neutral about"
+	case value < 3:
+		return "someone you like"
+	case value < 7:
+		return "a friend"
+	default:
+		return "a close ally"
+	}
+}
+
+// describeTrust puts how much one agent trusts another into words.
+func describeTrust(trust int) string {
+	switch {
+	case trust <= -5:
+		return "not to be trusted at all"
+	case trust < 0:
+		return "not entirely trustworthy"
+	case trust == 0:
+		return "trust not yet earned"
+	case trust < 5:
+		return "fairly trustworthy"
+	default:
+		return "completely trustworthy"
+	}
+}
+
+// describeFamiliarity puts how well one agent knows another into words.
+func describeFamiliarity(familiarity int) string {
+	switch {
+	case familiarity == 0:
+		return "a stranger"
+	case familiarity < 4:
+		return "barely known"
+	case familiarity < 8:
+		return "known fairly well"
+	default:
+		return "known very well"
+	}
+}
+
+func clampRelationship(value int) int {
+	return min(max(value, campaigns.MinRelationship), campaigns.MaxRelationship)
+}
+
+func clampFamiliarity(value int) int {
+	return min(max(value, 0), campaigns.MaxFamiliarity)
+}
@@ -0,0 +1,174 @@
+package simulation
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/poiesic/wonda/internal/campaigns"
+	"github.com/poiesic/wonda/internal/mcp"
+	"github.com/poiesic/wonda/internal/runtime"
+)
+
+// maxRelationshipChange limits how far one event can move a relationship.
+const maxRelationshipChange = 3
+
+// NewViewRelationshipsTool creates the view_relationships MCP tool.
+// Allows agents to recall how they feel about the others present, including
+// feelings carried over from earlier scenarios of a campaign.
+func NewViewRelationshipsTool(world *WorldState) *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "view_relationships",
+		Description: fmt.Sprintf("Recall how you feel about each other person present, from %d (bitter enemy) to %d (devoted ally); 0 is neutral. Also shows how far you trust them (%d to %d) and how well you know them (0 to %d)", campaigns.MinRelationship, campaigns.MaxRelationship, campaigns.MinRelationship, campaigns.MaxRelationship, campaigns.MaxFamiliarity),
+		InputSchema: map[string]interface{}{
+			"type":       "object",
+			"properties": map[string]interface{}{},
+			"required":   []string{},
+		},
+		Handler: func(ctx context.Context, arguments map[string]interface{}) (interface{}, error) {
+			agentName, ok := ctx.Value(runtime.AgentNameKey).(string)
+			if !ok || agentName == "" {
+				return nil, fmt.Errorf("agent_name not found in context")
+			}
+
+			snapshot := world.Snapshot()
+			relationships := []map[string]interface{}{}
+			for _, p := range snapshot.participation() {
+				if p.Name == agentName {
+					continue
+				}
+				rel := snapshot.relationshipBetween(agentName, p.Name)
+				entry := map[string]interface{}{
+					"name":        p.Name,
+					"value":       rel.Value,
+					"trust":       rel.Trust,
+					"familiarity": rel.Familiarity,
+				}
+				if rel.Reason != "" {
+					entry["reason"] = rel.Reason
+				}
+				relationships = append(relationships, entry)
+			}
+			return map[string]interface{}{"relationships": relationships}, nil
+		},
+	}
+}
+
+// NewQueryRelationshipTool creates the query_relationship MCP tool.
+// Allows agents to recall in detail where they stand with one person.
+func NewQueryRelationshipTool(world *WorldState) *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "query_relationship",
+		Description: "Recall where you stand with one person: how much you like and trust them, how well you know them, and why",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"name": map[string]interface{}{
+					"type":        "string",
+					"description": "The person to think about",
+				},
+			},
+			"required": []string{"name"},
+		},
+		Handler: func(ctx context.Context, arguments map[string]interface{}) (interface{}, error) {
+			agentName, ok := ctx.Value(runtime.AgentNameKey).(string)
+			if !ok || agentName == "" {
+				return nil, fmt.Errorf("agent_name not found in context")
+			}
+
+			name, ok := arguments["name"].(string)
+			if !ok || name == "" {
+				return nil, fmt.Errorf("name parameter is required and must be a string")
+			}
+			if name == agentName {
+				return nil, fmt.Errorf("you have no relationship with yourself")
+			}
+
+			var rel Relationship
+			known := false
+			world.View(func(w *WorldState) {
+				_, known = w.Agents[name]
+				rel = w.relationshipBetween(agentName, name)
+			})
+			if !known {
+				return nil, fmt.Errorf("unknown agent: %s", name)
+			}
+			result := map[string]interface{}{
+				"name":        name,
+				"affinity":    rel.Value,
+				"trust":       rel.Trust,
+				"familiarity": rel.Familiarity,
+				"summary":     fmt.Sprintf("%s is %s, %s, %s", name, describeAffinity(rel.Value), describeTrust(rel.Trust), describeFamiliarity(rel.Familiarity)),
+			}
+			if rel.Reason != "" {
+				result["reason"] = rel.Reason
+			}
+			return result, nil
+		},
+	}
+}
+
+// NewAdjustRelationshipTool creates the adjust_relationship MCP tool.
+// Allows agents to record that something in the scene changed how they feel about someone.
+func NewAdjustRelationshipTool(world *WorldState) *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "adjust_relationship",
+		Description: "Record that something that just happened changed how you feel about someone. Use this for real shifts - a betrayal, a kindness, a slight - not every exchange.",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"name": map[string]interface{}{
+					"type":        "string",
+					"description": "The person whose standing with you changed",
+				},
+				"change": map[string]interface{}{
+					"type":        "integer",
+					"description": fmt.Sprintf("How much warmer (positive) or colder (negative) you feel, from -%d to %d", maxRelationshipChange, maxRelationshipChange),
+				},
+				"trust": map[string]interface{}{
+					"type":        "integer",
+					"description": fmt.Sprintf("How much more (positive) or less (negative) you trust them, from -%d to %d", maxRelationshipChange, maxRelationshipChange),
+				},
+				"reason": map[string]interface{}{
+					"type":        "string",
+					"description": "What happened, in a few words",
+				},
+			},
+			"required": []string{"name", "reason"},
+		},
+		Handler: func(ctx context.Context, arguments map[string]interface{}) (interface{}, error) {
+			agentName, ok := ctx.Value(runtime.AgentNameKey).(string)
+			if !ok || agentName == "" {
+				return nil, fmt.Errorf("agent_name not found in context")
+			}
+
+			name, ok := arguments["name"].(string)
+			if !ok || name == "" {
+				return nil, fmt.Errorf("name parameter is required and must be a string")
+			}
+			change, _ := arguments["change"].(float64)
+			trust, _ := arguments["trust"].(float64)
+			if change == 0 && trust == 0 {
+				return nil, fmt.Errorf("change or trust is required and must be a non-zero number")
+			}
+			reason, _ := arguments["reason"].(string)
+			delta := min(max(int(change), -maxRelationshipChange), maxRelationshipChange)
+			trustDelta := min(max(int(trust), -maxRelationshipChange), maxRelationshipChange)
+
+			var rel Relationship
+			err := world.Update(func(w *WorldState) error {
+				var err error
+				rel, err = w.adjustRelationship(agentName, name, delta, trustDelta, reason)
+				return err
+			})
+			if err != nil {
+				return nil, err
+			}
+			return map[string]interface{}{
+				"success": true,
+				"name":    name,
+				"value":   rel.Value,
+				"trust":   rel.Trust,
+			}, nil
+		},
+	}
+}
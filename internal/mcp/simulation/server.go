@@ -21,15 +21,31 @@ func NewSimulationServer(world *WorldState) *mcp.Server {
 	// Register perception and action tools
 	server.RegisterTool(NewPerceiveTool(world))
 	server.RegisterTool(NewSpeakTool(world))
+	server.RegisterTool(NewWhisperTool(world))
 	server.RegisterTool(NewNarrateActionTool(world))
 	server.RegisterTool(NewInternalMonologueTool(world))
+	server.RegisterTool(NewPassTurnTool(world))
+	server.RegisterTool(NewUpdateEmotionTool(world))
 
 	// Register goal interaction tools
 	server.RegisterTool(NewListGoalsTool(world))
 	server.RegisterTool(NewViewGoalTool(world))
 	server.RegisterTool(NewProposeSolutionTool(world))
 	server.RegisterTool(NewVoteOnProposalTool(world))
+	server.RegisterTool(NewCounterProposalTool(world))
+	server.RegisterTool(NewRankProposalsTool(world))
 	server.RegisterTool(NewWithdrawProposalTool(world))
+	server.RegisterTool(NewCompleteGoalTool(world))
+	server.RegisterTool(NewListCommitmentsTool(world))
+	server.RegisterTool(NewFulfillCommitmentTool(world))
+
+	// Register relationship tools
+	server.RegisterTool(NewViewRelationshipsTool(world))
+	server.RegisterTool(NewQueryRelationshipTool(world))
+	server.RegisterTool(NewAdjustRelationshipTool(world))
+
+	// Register simulation status tools
+	server.RegisterTool(NewSimulationStatusTool(world))
 
 	return server
 }
@@ -3,6 +3,7 @@ package simulation
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/poiesic/wonda/internal/mcp"
 	"github.com/poiesic/wonda/internal/runtime"
@@ -28,6 +29,14 @@ func NewSpeakTool(world *WorldState) *mcp.Tool {
 					"type":        "string",
 					"description": "The exact words you're saying out loud. ONLY include spoken dialogue - no narration of actions, no stage directions, no meta-commentary about what you'll do or say. GOOD EXAMPLES: \"How about we grab dinner at that Italian place?\" or \"I don't know, seems pretty far from here.\" BAD EXAMPLES: \"I'll order a drink\" (narration), \"I'm going to tell him...\" (meta-narration), \"So here's my vote\" (breaking character)",
 				},
+				"goal": map[string]interface{}{
+					"type":        "string",
+					"description": "Name of the goal you're talking about, if any. Leave it out to keep talking about the goal you last looked at.",
+				},
+				"rationale": map[string]interface{}{
+					"type":        "string",
+					"description": "Optional: one short sentence on why you're saying this, as you'd sum it up yourself",
+				},
 			},
 			"required": []string{"message"},
 		},
@@ -44,8 +53,22 @@ func NewSpeakTool(world *WorldState) *mcp.Tool {
 				return nil, fmt.Errorf("message parameter is required and must be a string")
 			}
 
+			// Talking about a goal makes it the agent's focus, which threads the message under it
+			if goalName, _ := arguments["goal"].(string); goalName != "" {
+				if err := world.Update(func(w *WorldState) error {
+					if _, ok := w.Goals[goalName]; !ok {
+						return fmt.Errorf("goal not found: %s", goalName)
+					}
+					w.setFocus(agentName, goalName)
+					return nil
+				}); err != nil {
+					return nil, err
+				}
+			}
+
 			// Add message to world conversation history
-			world.AddMessage(agentName, message, "", MessageTypeDialogue)
+			rationale, _ := arguments["rationale"].(string)
+			world.AddSpokenMessage(agentName, message, strings.TrimSpace(rationale))
 
 			return &SpeakResult{
 				Success: true,
@@ -54,3 +77,59 @@ func NewSpeakTool(world *WorldState) *mcp.Tool {
 		},
 	}
 }
+
+// WhisperResult contains confirmation of a whisper.
+type WhisperResult struct {
+	Success   bool   `json:"success"`
+	Recipient string `json:"recipient"`
+	Message   string `json:"message"`
+}
+
+// NewWhisperTool creates the whisper() MCP tool.
+// This tool allows agents to send a private message that only one nearby agent hears.
+func NewWhisperTool(world *WorldState) *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "whisper",
+		Description: "Say something privately to one nearby agent. Only they will hear it; nobody else learns what was said. You can still speak out loud afterwards.",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"recipient": map[string]interface{}{
+					"type":        "string",
+					"description": "Name of the agent you're whispering to",
+				},
+				"message": map[string]interface{}{
+					"type":        "string",
+					"description": "The exact words you're whispering. ONLY include spoken dialogue - no narration of actions or stage directions.",
+				},
+			},
+			"required": []string{"recipient", "message"},
+		},
+		Handler: func(ctx context.Context, arguments map[string]interface{}) (interface{}, error) {
+			// Get agent name from context
+			agentName, ok := ctx.Value(runtime.AgentNameKey).(string)
+			if !ok || agentName == "" {
+				return nil, fmt.Errorf("agent_name not found in context")
+			}
+
+			recipient, ok := arguments["recipient"].(string)
+			if !ok || recipient == "" {
+				return nil, fmt.Errorf("recipient parameter is required and must be a string")
+			}
+			message, ok := arguments["message"].(string)
+			if !ok || strings.TrimSpace(message) == "" {
+				return nil, fmt.Errorf("message parameter is required and must be a string")
+			}
+
+			if err := world.Whisper(agentName, recipient, message); err != nil {
+				return nil, err
+			}
+
+			return &WhisperResult{
+				Success:   true,
+				Recipient: recipient,
+				Message:   fmt.Sprintf("You whispered to %s: %s", recipient, message),
+			}, nil
+		},
+	}
+}
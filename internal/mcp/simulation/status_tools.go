@@ -0,0 +1,98 @@
+package simulation
+
+import (
+	"context"
+	"sort"
+
+	"github.com/poiesic/wonda/internal/mcp"
+	"github.com/poiesic/wonda/internal/runtime"
+)
+
+// NewSimulationStatusTool creates the simulation_status MCP tool.
+// Allows agents to check the turn, phase, remaining turn budget, and who has participated.
+func NewSimulationStatusTool(world *WorldState) *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "simulation_status",
+		Description: "Check the current turn and phase, how many turns remain, and how much each person has spoken, proposed, and voted",
+		InputSchema: map[string]interface{}{
+			"type":       "object",
+			"properties": map[string]interface{}{},
+			"required":   []string{},
+		},
+		Handler: func(ctx context.Context, arguments map[string]interface{}) (interface{}, error) {
+			agentName, _ := ctx.Value(runtime.AgentNameKey).(string)
+
+			snapshot := world.Snapshot()
+			participation := snapshot.participation()
+
+			notYetSpoken := []string{}
+			for _, p := range participation {
+				if p.SpokeThisTurn == 0 && p.Name != agentName {
+					notYetSpoken = append(notYetSpoken, p.Name)
+				}
+			}
+
+			status := map[string]interface{}{
+				"current_turn":             snapshot.CurrentTurn,
+				"phase":                    string(snapshot.Phase),
+				"participation":            participation,
+				"not_yet_spoken_this_turn": notYetSpoken,
+			}
+			if snapshot.MaxTurns > 0 {
+				status["max_turns"] = snapshot.MaxTurns
+				status["turns_remaining"] = max(snapshot.MaxTurns-snapshot.CurrentTurn, 0)
+			}
+			return status, nil
+		},
+	}
+}
+
+// Participation counts how much an agent has taken part in the simulation.
+type Participation struct {
+	Name          string `json:"name"`
+	Spoke         int    `json:"spoke"`           // Things said aloud
+	SpokeThisTurn int    `json:"spoke_this_turn"` // Things said aloud this turn
+	Proposals     int    `json:"proposals"`       // Proposals made
+	Votes         int    `json:"votes"`           // Votes cast
+}
+
+// participation counts each agent's messages, proposals, and votes, sorted by name.
+// The caller must own the world (a snapshot) or hold its lock.
+func (w *WorldState) participation() []Participation {
+	byAgent := make(map[string]*Participation, len(w.Agents))
+	get := func(name string) *Participation {
+		if byAgent[name] == nil {
+			byAgent[name] = &Participation{Name: name}
+		}
+		return byAgent[name]
+	}
+	for name := range w.Agents {
+		get(name)
+	}
+
+	for _, msg := range w.ConversationHistory {
+		if msg.Type != MessageTypeDialogue || msg.Content == "" {
+			continue
+		}
+		p := get(msg.AgentName)
+		p.Spoke++
+		if msg.Turn == w.CurrentTurn {
+			p.SpokeThisTurn++
+		}
+	}
+	for _, goal := range w.Goals {
+		for _, proposal := range goal.Proposals {
+			get(proposal.ProposedBy).Proposals++
+			for voter := range proposal.Votes {
+				get(voter).Votes++
+			}
+		}
+	}
+
+	result := make([]Participation, 0, len(byAgent))
+	for _, p := range byAgent {
+		result = append(result, *p)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result
+}
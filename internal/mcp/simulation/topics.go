@@ -0,0 +1,86 @@
+package simulation
+
+import (
+	"slices"
+	"sort"
+)
+
+// SetFocus records the goal an agent is working on.
+func (w *WorldState) SetFocus(agentName, goalName string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.setFocus(agentName, goalName)
+}
+
+// setFocus records the goal an agent is working on; the caller holds the write lock.
+func (w *WorldState) setFocus(agentName, goalName string) {
+	if agent, ok := w.Agents[agentName]; ok {
+		agent.Focus = goalName
+	}
+}
+
+// DiscussedGoal returns the goal an agent's words are taken to be about: the
+// goal they last viewed, proposed to or voted on, or else the only pending goal
+// they can decide. It returns "" when neither tells.
+func (w *WorldState) DiscussedGoal(agentName string) string {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.discussedGoal(agentName)
+}
+
+// discussedGoal is DiscussedGoal for callers already holding the lock.
+func (w *WorldState) discussedGoal(agentName string) string {
+	agent, ok := w.Agents[agentName]
+	if !ok {
+		return ""
+	}
+	if _, ok := w.Goals[agent.Focus]; ok {
+		return agent.Focus
+	}
+
+	only := ""
+	for name, goal := range w.Goals {
+		if goal.Status != GoalPending || !w.CanDecide(goal, agentName) {
+			continue
+		}
+		if only != "" {
+			return ""
+		}
+		only = name
+	}
+	return only
+}
+
+// FocusTags returns the tags of the goal an agent is working on: the pending
+// goal they last viewed, proposed to or voted on, or else every pending goal
+// they can decide. The tags are sorted and distinct.
+func (w *WorldState) FocusTags(agentName string) []string {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	agent, ok := w.Agents[agentName]
+	if !ok {
+		return nil
+	}
+	if goal, ok := w.Goals[agent.Focus]; ok && goal.Status == GoalPending {
+		return sortedTags(goal.Tags)
+	}
+
+	var tags []string
+	for _, goal := range w.Goals {
+		if goal.Status == GoalPending && w.CanDecide(goal, agentName) {
+			tags = append(tags, goal.Tags...)
+		}
+	}
+	return sortedTags(tags)
+}
+
+// sortedTags returns the distinct tags in sorted order, or nil if there are none.
+func sortedTags(tags []string) []string {
+	if len(tags) == 0 {
+		return nil
+	}
+	sorted := append([]string(nil), tags...)
+	sort.Strings(sorted)
+	return slices.Compact(sorted)
+}
@@ -0,0 +1,246 @@
+package simulation
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+	"slices"
+	"strings"
+)
+
+// votingTolerance absorbs floating point error when comparing vote shares.
+const votingTolerance = 1e-9
+
+// VotingRules describe how a majority or weighted vote goal's proposals are decided.
+type VotingRules struct {
+	Threshold float64            // Share of the total voting weight a proposal needs
+	Weights   map[string]float64 // Voting weight by agent; agents not listed count once
+
+	// How a vote split evenly is decided; "" rejects the proposal
+	TieBreak    string
+	Facilitator string   // For the facilitator policy: the agent whose vote decides
+	Order       []string // For the priority policy: agents, highest priority first
+	Seed        int64    // For the coin_flip policy: seed making the flips reproducible
+}
+
+// Tie-break policies for votes split evenly.
+const (
+	// TieBreakFacilitator decides a tie by the facilitator's vote.
+	TieBreakFacilitator = "facilitator"
+	// TieBreakPriority decides a tie for the proposer if they outrank everyone who voted against them.
+	TieBreakPriority = "priority"
+	// TieBreakCoinFlip decides a tie by a seeded coin flip.
+	TieBreakCoinFlip = "coin_flip"
+	// TieBreakExtend reopens a tied proposal for a vote the next turn, rejecting it if it ties again.
+	TieBreakExtend = "extend"
+)
+
+// TieBreak records how a tied vote on a proposal was broken.
+type TieBreak struct {
+	Policy  string
+	Turn    int
+	Outcome string // accepted, rejected or extended
+	Reason  string
+}
+
+// Reopened reports whether a tied proposal is waiting to be voted on again,
+// after the turn its tie extended deliberation.
+func (p *Proposal) Reopened(turn int) bool {
+	return p.TieBreak != nil && p.TieBreak.Outcome == "extended" && p.TieBreak.Turn == turn
+}
+
+// Weight returns an agent's voting weight.
+func (r *VotingRules) Weight(agentName string) float64 {
+	if weight, ok := r.Weights[agentName]; ok {
+		return weight
+	}
+	return 1
+}
+
+// Evaluate resolves a proposal once the vote is decided. Shares are taken of
+// the participants' total weight, so a proposal can pass before everyone has
+// voted. Passing needs both the threshold share and more than half the weight,
+// so a tie never passes. The proposal is rejected as soon as it can no longer
+// pass even if every remaining participant votes yes. With a tie-break
+// policy, a vote that ends split evenly is decided by the policy instead, and
+// a proposal that can still tie waits for every vote until voting closes.
+func (r *VotingRules) Evaluate(p *Proposal, participants []string, turn int) {
+	if p.Status != ProposalPending {
+		return
+	}
+
+	total, yes, no := r.tally(p, participants)
+	switch {
+	case r.passes(yes, total):
+		p.Status = ProposalAccepted
+		p.ResolvedAt = turn
+	case r.TieBreak != "" && r.canTie(total-no, total):
+		if yes+no >= total-votingTolerance {
+			r.breakTie(p, turn)
+		}
+	case !r.passes(total-no, total):
+		p.Status = ProposalRejected
+		p.ResolvedAt = turn
+	}
+}
+
+// Close ends the turn's voting on a proposal. With a tie-break policy, a
+// proposal still pending that can at best tie is rejected, as it would have
+// been without the policy: only ties everyone has voted on are broken.
+func (r *VotingRules) Close(p *Proposal, participants []string, turn int) {
+	if p.Status != ProposalPending || r.TieBreak == "" {
+		return
+	}
+
+	total, _, no := r.tally(p, participants)
+	if r.passes(total-no, total) {
+		return
+	}
+	p.Status = ProposalRejected
+	p.ResolvedAt = turn
+	p.TieBreak = &TieBreak{Policy: r.TieBreak, Turn: turn, Outcome: string(ProposalRejected), Reason: "voting closed before everyone voted, and the vote could at best have tied"}
+}
+
+// tally returns the participants' total voting weight and the weight of the
+// yes and no votes cast on a proposal.
+func (r *VotingRules) tally(p *Proposal, participants []string) (total, yes, no float64) {
+	for _, agentName := range participants {
+		weight := r.Weight(agentName)
+		total += weight
+		if vote, ok := p.Votes[agentName]; ok {
+			switch vote.Choice {
+			case "yes":
+				yes += weight
+			case "no":
+				no += weight
+			}
+		}
+	}
+	return total, yes, no
+}
+
+// CloseVoting ends the turn's voting on the open voting goals (see
+// VotingRules.Close).
+func (w *WorldState) CloseVoting(turn int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for _, goal := range w.Goals {
+		if goal.Status != GoalPending || goal.Voting == nil {
+			continue
+		}
+		participants := w.GoalParticipants(goal)
+		for _, proposal := range goal.Proposals {
+			goal.Voting.Close(proposal, participants, turn)
+		}
+	}
+}
+
+// canTie reports whether yes votes of the given weight split the vote evenly,
+// or better, and would carry the proposal if the tie were broken its way.
+func (r *VotingRules) canTie(yes, total float64) bool {
+	return total > 0 &&
+		yes >= r.Threshold*total-votingTolerance &&
+		2*yes >= total-votingTolerance
+}
+
+// breakTie decides a proposal whose vote is split evenly by the tie-break policy.
+func (r *VotingRules) breakTie(p *Proposal, turn int) {
+	var passes bool
+	var reason string
+	switch r.TieBreak {
+	case TieBreakFacilitator:
+		vote, ok := p.Votes[r.Facilitator]
+		passes = ok && vote.Choice == "yes"
+		reason = fmt.Sprintf("%s breaks ties and voted %s", r.Facilitator, choiceOf(vote, ok))
+	case TieBreakPriority:
+		passes, reason = r.breakTieByPriority(p)
+	case TieBreakCoinFlip:
+		// Seeding with the proposal too gives every tie its own flip
+		h := fnv.New64a()
+		h.Write([]byte(p.ID))
+		passes = rand.New(rand.NewSource(r.Seed^int64(h.Sum64()))).Intn(2) == 0
+		side := "tails"
+		if passes {
+			side = "heads"
+		}
+		reason = fmt.Sprintf("a coin flip (seed %d) came up %s", r.Seed, side)
+	case TieBreakExtend:
+		if p.TieBreak == nil {
+			// Everyone votes again next turn, after another round of talk
+			p.Votes = make(map[string]*Vote)
+			p.TieBreak = &TieBreak{Policy: r.TieBreak, Turn: turn, Outcome: "extended", Reason: "the vote tied, so it is held again next turn"}
+			return
+		}
+		reason = "the vote tied again after an extra turn of deliberation"
+	}
+
+	p.Status = ProposalRejected
+	if passes {
+		p.Status = ProposalAccepted
+	}
+	p.ResolvedAt = turn
+	p.TieBreak = &TieBreak{Policy: r.TieBreak, Turn: turn, Outcome: string(p.Status), Reason: reason}
+}
+
+// breakTieByPriority decides a tie by rank in the priority order: the
+// proposal passes when its proposer outranks everyone who voted against it.
+// Agents not in the order rank below everyone in it.
+func (r *VotingRules) breakTieByPriority(p *Proposal) (bool, string) {
+	rank := func(agentName string) int {
+		if i := slices.Index(r.Order, agentName); i >= 0 {
+			return i
+		}
+		return len(r.Order)
+	}
+
+	// The highest-ranked agent on the other side, by name for a stable reason
+	opponent := ""
+	for agentName, vote := range p.Votes {
+		if vote.Choice != "no" {
+			continue
+		}
+		if opponent == "" || rank(agentName) < rank(opponent) || (rank(agentName) == rank(opponent) && agentName < opponent) {
+			opponent = agentName
+		}
+	}
+
+	switch {
+	case opponent == "" || rank(p.ProposedBy) < rank(opponent):
+		return true, fmt.Sprintf("%s proposed it and outranks everyone who voted against it", p.ProposedBy)
+	case rank(opponent) == len(r.Order):
+		return false, fmt.Sprintf("neither %s, who proposed it, nor anyone who voted against it has priority", p.ProposedBy)
+	default:
+		return false, fmt.Sprintf("%s voted against it and outranks %s, who proposed it", opponent, p.ProposedBy)
+	}
+}
+
+// tieBreakRule describes how a voting goal's ties are broken, for view_goal.
+func tieBreakRule(r *VotingRules) string {
+	switch r.TieBreak {
+	case TieBreakFacilitator:
+		return fmt.Sprintf("a tied vote goes the way %s voted", r.Facilitator)
+	case TieBreakPriority:
+		return fmt.Sprintf("a tied proposal passes if its proposer outranks everyone who voted against it, in the order %s", strings.Join(r.Order, ", "))
+	case TieBreakCoinFlip:
+		return "a tied vote is decided by a coin flip"
+	case TieBreakExtend:
+		return "a tied vote is held again the next turn, and rejected if it ties again"
+	}
+	return ""
+}
+
+// choiceOf returns a vote's choice, or "nothing" if there was no vote.
+func choiceOf(vote *Vote, ok bool) string {
+	if !ok {
+		return "nothing"
+	}
+	return vote.Choice
+}
+
+// passes reports whether yes votes of the given weight carry a proposal.
+func (r *VotingRules) passes(yes, total float64) bool {
+	return total > 0 &&
+		yes >= r.Threshold*total-votingTolerance &&
+		2*yes > total+votingTolerance
+}
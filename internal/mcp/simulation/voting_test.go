@@ -0,0 +1,181 @@
+package simulation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTieBreaks(t *testing.T) {
+	participants := []string{"agent0", "agent1", "agent2", "agent3"}
+	tied := map[string]string{"agent0": "yes", "agent1": "yes", "agent2": "no", "agent3": "no"}
+
+	vote := func(rules *VotingRules, votes map[string]string) *Proposal {
+		goal := NewInteractiveGoal("vote", "Hold a vote", "majority", 1)
+		goal.Voting = rules
+		proposalID := goal.AddProposal("agent0", "Go to the diner", 1)
+		proposal := goal.Proposals[proposalID]
+		for agentName, choice := range votes {
+			require.NoError(t, goal.Vote(proposalID, agentName, choice, 1))
+			goal.EvaluateProposal(proposal, participants, 1)
+		}
+		return proposal
+	}
+
+	t.Run("waits for every vote while a tie is possible", func(t *testing.T) {
+		rules := &VotingRules{Threshold: 0.5, TieBreak: TieBreakCoinFlip}
+		proposal := vote(rules, map[string]string{"agent2": "no", "agent3": "no"})
+		assert.Equal(t, ProposalPending, proposal.Status)
+		assert.Nil(t, proposal.TieBreak)
+	})
+
+	t.Run("the facilitator decides", func(t *testing.T) {
+		proposal := vote(&VotingRules{Threshold: 0.5, TieBreak: TieBreakFacilitator, Facilitator: "agent1"}, tied)
+		assert.Equal(t, ProposalAccepted, proposal.Status)
+		assert.Equal(t, &TieBreak{Policy: TieBreakFacilitator, Turn: 1, Outcome: "accepted", Reason: "agent1 breaks ties and voted yes"}, proposal.TieBreak)
+	})
+
+	t.Run("the highest-priority proposer wins", func(t *testing.T) {
+		tests := []struct {
+			name   string
+			order  []string
+			want   ProposalStatus
+			reason string
+		}{
+			{name: "proposer outranks the other side", order: []string{"agent1", "agent0", "agent3"}, want: ProposalAccepted, reason: "agent0 proposed it and outranks everyone who voted against it"},
+			{name: "the other side outranks the proposer", order: []string{"agent3", "agent0"}, want: ProposalRejected, reason: "agent3 voted against it and outranks agent0, who proposed it"},
+			{name: "proposer unlisted", order: []string{"agent1", "agent2"}, want: ProposalRejected, reason: "agent2 voted against it and outranks agent0, who proposed it"},
+			{name: "no one on either side listed", order: []string{"agent1"}, want: ProposalRejected, reason: "neither agent0, who proposed it, nor anyone who voted against it has priority"},
+			{name: "proposer outranks only unlisted opponents", order: []string{"agent0"}, want: ProposalAccepted, reason: "agent0 proposed it and outranks everyone who voted against it"},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				proposal := vote(&VotingRules{Threshold: 0.5, TieBreak: TieBreakPriority, Order: tt.order}, tied)
+				assert.Equal(t, tt.want, proposal.Status)
+				assert.Equal(t, &TieBreak{Policy: TieBreakPriority, Turn: 1, Outcome: string(tt.want), Reason: tt.reason}, proposal.TieBreak)
+			})
+		}
+	})
+
+	t.Run("a seeded coin flip is reproducible", func(t *testing.T) {
+		rules := &VotingRules{Threshold: 0.5, TieBreak: TieBreakCoinFlip, Seed: 42}
+		first := vote(rules, tied)
+		assert.NotEqual(t, ProposalPending, first.Status)
+		assert.Equal(t, first.Status, vote(rules, tied).Status)
+		assert.Contains(t, first.TieBreak.Reason, "a coin flip (seed 42)")
+	})
+
+	t.Run("extending holds the vote again next turn", func(t *testing.T) {
+		proposal := vote(&VotingRules{Threshold: 0.5, TieBreak: TieBreakExtend}, tied)
+		assert.Equal(t, ProposalPending, proposal.Status)
+		assert.Empty(t, proposal.Votes, "everyone votes again")
+		assert.True(t, proposal.Reopened(1))
+		assert.False(t, proposal.Reopened(2))
+
+		rules := &VotingRules{Threshold: 0.5, TieBreak: TieBreakExtend}
+		for agentName, choice := range tied {
+			proposal.Votes[agentName] = &Vote{AgentName: agentName, Choice: choice, VotedAt: 2}
+		}
+		rules.Evaluate(proposal, participants, 2)
+		assert.Equal(t, ProposalRejected, proposal.Status)
+		assert.Equal(t, "rejected", proposal.TieBreak.Outcome)
+	})
+
+	t.Run("closing the vote rejects a proposal that can at best tie", func(t *testing.T) {
+		rules := &VotingRules{Threshold: 0.5, TieBreak: TieBreakCoinFlip}
+		proposal := vote(rules, map[string]string{"agent1": "yes", "agent2": "no", "agent3": "no"})
+		require.Equal(t, ProposalPending, proposal.Status)
+
+		rules.Close(proposal, participants, 1)
+		assert.Equal(t, ProposalRejected, proposal.Status)
+		assert.Equal(t, 1, proposal.ResolvedAt)
+		assert.Equal(t, &TieBreak{Policy: TieBreakCoinFlip, Turn: 1, Outcome: "rejected", Reason: "voting closed before everyone voted, and the vote could at best have tied"}, proposal.TieBreak)
+	})
+
+	t.Run("closing the vote leaves proposals that can still pass", func(t *testing.T) {
+		tests := []struct {
+			name  string
+			rules *VotingRules
+			votes map[string]string
+		}{
+			{name: "short of half the no votes", rules: &VotingRules{Threshold: 0.5, TieBreak: TieBreakCoinFlip}, votes: map[string]string{"agent2": "no"}},
+			{name: "reopened for a second vote", rules: &VotingRules{Threshold: 0.5, TieBreak: TieBreakExtend}, votes: tied},
+			{name: "no tie-break policy", rules: &VotingRules{Threshold: 0.5}, votes: map[string]string{"agent2": "no"}},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				proposal := vote(tt.rules, tt.votes)
+				tt.rules.Close(proposal, participants, 1)
+				assert.Equal(t, ProposalPending, proposal.Status)
+			})
+		}
+	})
+
+	t.Run("closing the world's votes", func(t *testing.T) {
+		world := NewWorldState("Town hall", "")
+		for _, name := range participants {
+			world.AddAgent(name, "hall")
+		}
+		open := NewInteractiveGoal("vote", "Hold a vote", "majority", 1)
+		open.Voting = &VotingRules{Threshold: 0.5, TieBreak: TieBreakFacilitator, Facilitator: "agent0"}
+		proposalID := open.AddProposal("agent0", "Go to the diner", 1)
+		for _, agentName := range []string{"agent2", "agent3"} {
+			require.NoError(t, open.Vote(proposalID, agentName, "no", 1))
+		}
+		world.AddGoal(open)
+
+		world.CloseVoting(1)
+		closed := world.Snapshot().Goals["vote"].Proposals[proposalID]
+		assert.Equal(t, ProposalRejected, closed.Status)
+		assert.Equal(t, "rejected", closed.TieBreak.Outcome)
+	})
+}
+
+func TestVotingGoals(t *testing.T) {
+	participants := []string{"agent0", "agent1", "agent2", "agent3"}
+
+	evaluate := func(rules *VotingRules, votes map[string]string) ProposalStatus {
+		goal := NewInteractiveGoal("vote", "Hold a vote", "majority", 1)
+		goal.Voting = rules
+		proposalID := goal.AddProposal("agent0", "Go to the diner", 1)
+		for agentName, choice := range votes {
+			require.NoError(t, goal.Vote(proposalID, agentName, choice, 1))
+		}
+		proposal := goal.Proposals[proposalID]
+		goal.EvaluateProposal(proposal, participants, 1)
+		return proposal.Status
+	}
+
+	t.Run("majority passes before everyone votes", func(t *testing.T) {
+		rules := &VotingRules{Threshold: 0.5}
+		assert.Equal(t, ProposalPending, evaluate(rules, map[string]string{"agent0": "yes", "agent1": "yes"}))
+		assert.Equal(t, ProposalAccepted, evaluate(rules, map[string]string{"agent0": "yes", "agent1": "yes", "agent2": "yes"}))
+	})
+
+	t.Run("a tie fails", func(t *testing.T) {
+		rules := &VotingRules{Threshold: 0.5}
+		votes := map[string]string{"agent0": "yes", "agent1": "yes", "agent2": "no", "agent3": "no"}
+		assert.Equal(t, ProposalRejected, evaluate(rules, votes))
+	})
+
+	t.Run("rejects once the threshold is out of reach", func(t *testing.T) {
+		rules := &VotingRules{Threshold: 0.75}
+		assert.Equal(t, ProposalPending, evaluate(rules, map[string]string{"agent0": "yes", "agent1": "no"}))
+		assert.Equal(t, ProposalRejected, evaluate(rules, map[string]string{"agent1": "no", "agent2": "no"}))
+	})
+
+	t.Run("weights decide the vote", func(t *testing.T) {
+		rules := &VotingRules{Threshold: 0.5, Weights: map[string]float64{"agent0": 3}}
+		assert.Equal(t, ProposalAccepted, evaluate(rules, map[string]string{"agent0": "yes", "agent1": "yes"}))
+		assert.Equal(t, ProposalRejected, evaluate(rules, map[string]string{"agent0": "no", "agent1": "no"}))
+	})
+
+	t.Run("a weighted tie fails", func(t *testing.T) {
+		rules := &VotingRules{Threshold: 0.5, Weights: map[string]float64{"agent0": 3, "agent1": 0.5, "agent2": 0.5, "agent3": 2}}
+		votes := map[string]string{"agent0": "yes", "agent1": "no", "agent2": "no", "agent3": "no"}
+		assert.Equal(t, ProposalRejected, evaluate(rules, votes))
+	})
+}
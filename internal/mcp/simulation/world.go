@@ -1,8 +1,24 @@
 package simulation
 
+import (
+	"crypto/sha256"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
 // WorldState represents the shared simulation world that all agents exist in.
 // This is an MCP resource that tools can read from and modify.
+//
+// WorldState is safe for concurrent use through its methods. Code that reads or
+// modifies several fields together (goals, proposals, votes) must do so inside
+// View or Update; readers that only need a consistent picture can take a
+// Snapshot instead. Methods must not be called from inside View or Update.
 type WorldState struct {
+	mu sync.RWMutex
+
 	// Location is the primary scene location
 	Location string
 
@@ -12,27 +28,98 @@ type WorldState struct {
 	// Agents tracks all agents and their positions
 	Agents map[string]*AgentInWorld
 
+	// Places are the locations agents can move between; empty when the scene
+	// is a single place, where everyone hears everything
+	Places map[string]*Place
+
+	// Objects are the things in the scene, lying somewhere or held by an agent
+	Objects map[string]*WorldObject
+
 	// ConversationHistory stores all messages
 	ConversationHistory []ConversationMessage
 
+	// Whispers stores private messages, each heard only by its recipient
+	Whispers []ConversationMessage
+
 	// Goals tracks interactive goals that agents can work toward
 	Goals map[string]*InteractiveGoal
 
+	// Commitments is the ledger of agreements reached by accepting proposals
+	Commitments []Commitment
+
+	// Relationships tracks how each agent feels about the others
+	Relationships map[relationshipKey]*Relationship
+
 	// CurrentTurn tracks which turn we're on
 	CurrentTurn int
 
+	// MaxTurns is the turn budget of the simulation (0 if unlimited)
+	MaxTurns int
+
+	// Elapsed is how long the simulation has been running, for goal deadlines
+	Elapsed time.Duration
+
+	// Phase is the part of the turn in progress
+	Phase Phase
+
+	// ReasoningShared shows agents the reasons others give for what they say
+	ReasoningShared bool
+
+	// AmbientEvents are the environmental events happening this turn
+	AmbientEvents []string
+
 	// PendingDialogue buffers dialogue from tool calls (vote comments, proposal comments)
 	// This is cleared after each agent's turn
 	PendingDialogue []ConversationMessage
+
+	// PendingConditionChanges buffers condition changes until the simulation chronicles them
+	PendingConditionChanges []ConditionChange
 }
 
 // AgentInWorld represents an agent's presence in the world.
 type AgentInWorld struct {
-	Name     string
-	Position string // Sublocation (e.g., "coffee_table", "doorway")
-	Visible  bool   // Can this agent be perceived by others?
+	Name      string
+	Position  string // Sublocation (e.g., "coffee_table", "doorway")
+	Visible   bool   // Can this agent be perceived by others?
+	Observer  bool   // Watches and comments but takes no part in deciding goals
+	Condition int    // Health and energy, 0-100
+	Focus     string // Goal the agent last viewed, proposed to or voted on
+
+	Emotion          string // How the agent feels, one of Emotions
+	EmotionIntensity int    // How strongly, 0-10
+	EmotionCause     string // What last changed how the agent feels
+}
+
+// CommitmentStatus tracks whether the agents followed through on a commitment.
+type CommitmentStatus string
+
+const (
+	CommitmentOpen      CommitmentStatus = "open"
+	CommitmentFulfilled CommitmentStatus = "fulfilled"
+	CommitmentExpired   CommitmentStatus = "expired" // The run ended before anyone fulfilled it
+)
+
+// Commitment is an agreement the agents reached when a proposal was accepted.
+type Commitment struct {
+	Goal        string           `json:"goal"`
+	Description string           `json:"description"`
+	ProposedBy  string           `json:"proposed_by"`
+	Agreed      []string         `json:"agreed"`             // Agents who voted yes
+	Objected    []string         `json:"objected,omitempty"` // Agents who voted no
+	AgreedAt    int              `json:"agreed_at"`          // Turn number
+	Status      CommitmentStatus `json:"status"`
+	FulfilledBy string           `json:"fulfilled_by,omitempty"` // Agent who reported it fulfilled
+	ResolvedAt  int              `json:"resolved_at,omitempty"`  // Turn it was fulfilled or expired
 }
 
+// Phase is a part of a turn.
+type Phase string
+
+const (
+	PhaseDeliberation Phase = "deliberation"
+	PhaseVoting       Phase = "voting"
+)
+
 // MessageType represents the type of message in the conversation.
 type MessageType string
 
@@ -40,6 +127,8 @@ const (
 	MessageTypeDialogue  MessageType = "dialogue"
 	MessageTypeAction    MessageType = "action"
 	MessageTypeMonologue MessageType = "monologue"
+	MessageTypePass      MessageType = "pass"    // Content holds the optional reason
+	MessageTypeWhisper   MessageType = "whisper" // Heard only by the Recipient
 )
 
 // ConversationMessage represents a message in the conversation history.
@@ -48,6 +137,19 @@ type ConversationMessage struct {
 	Content   string
 	Thinking  string
 	Type      MessageType
+	Turn      int // Turn the message was recorded in
+
+	// Set on proposal and vote comments
+	ProposalID string // Proposal made or voted on
+	Proposal   string // Proposal made, in words, for proposal comments
+	Vote       string // Choice, for vote comments
+
+	// Set on whispers
+	Recipient string // Only agent who hears the message
+
+	Goal      string // Goal the message is about; "" when it can't be told
+	Position  string // Where the speaker was; only agents there hear it when the scene has places
+	Rationale string // Reason the speaker gave for saying it, if any; never raw thinking
 }
 
 // NewWorldState creates a new world state.
@@ -56,24 +158,199 @@ func NewWorldState(location, atmosphere string) *WorldState {
 		Location:            location,
 		Atmosphere:          atmosphere,
 		Agents:              make(map[string]*AgentInWorld),
+		Places:              make(map[string]*Place),
+		Objects:             make(map[string]*WorldObject),
 		ConversationHistory: make([]ConversationMessage, 0),
 		Goals:               make(map[string]*InteractiveGoal),
+		Relationships:       make(map[relationshipKey]*Relationship),
 		CurrentTurn:         0,
 	}
 }
 
+// View calls fn with the world locked for reading.
+func (w *WorldState) View(fn func(w *WorldState)) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	fn(w)
+}
+
+// Update calls fn with the world locked for writing and returns its error.
+func (w *WorldState) Update(fn func(w *WorldState) error) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return fn(w)
+}
+
+// Snapshot returns a deep copy of the world that can be read without locking.
+func (w *WorldState) Snapshot() *WorldState {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	snapshot := &WorldState{
+		Location:            w.Location,
+		Atmosphere:          w.Atmosphere,
+		Agents:              make(map[string]*AgentInWorld, len(w.Agents)),
+		Places:              make(map[string]*Place, len(w.Places)),
+		Objects:             make(map[string]*WorldObject, len(w.Objects)),
+		ConversationHistory: append([]ConversationMessage(nil), w.ConversationHistory...),
+		Whispers:            append([]ConversationMessage(nil), w.Whispers...),
+		Goals:               make(map[string]*InteractiveGoal, len(w.Goals)),
+		Commitments:         append([]Commitment(nil), w.Commitments...),
+		Relationships:       make(map[relationshipKey]*Relationship, len(w.Relationships)),
+		CurrentTurn:         w.CurrentTurn,
+		MaxTurns:            w.MaxTurns,
+		Elapsed:             w.Elapsed,
+		Phase:               w.Phase,
+		ReasoningShared:     w.ReasoningShared,
+		AmbientEvents:       append([]string(nil), w.AmbientEvents...),
+		PendingDialogue:     append([]ConversationMessage(nil), w.PendingDialogue...),
+
+		PendingConditionChanges: append([]ConditionChange(nil), w.PendingConditionChanges...),
+	}
+	for name, agent := range w.Agents {
+		copied := *agent
+		snapshot.Agents[name] = &copied
+	}
+	for name, place := range w.Places {
+		copied := *place
+		copied.Exits = append([]string(nil), place.Exits...)
+		snapshot.Places[name] = &copied
+	}
+	for name, object := range w.Objects {
+		copied := *object
+		snapshot.Objects[name] = &copied
+	}
+	for name, goal := range w.Goals {
+		snapshot.Goals[name] = goal.clone()
+	}
+	for key, rel := range w.Relationships {
+		copied := *rel
+		snapshot.Relationships[key] = &copied
+	}
+	return snapshot
+}
+
+// Turn returns the current turn.
+func (w *WorldState) Turn() int {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.CurrentTurn
+}
+
+// SetTurn advances the world to the given turn.
+func (w *WorldState) SetTurn(turn int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.CurrentTurn = turn
+}
+
+// SetMaxTurns sets the simulation's turn budget.
+func (w *WorldState) SetMaxTurns(maxTurns int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.MaxTurns = maxTurns
+}
+
+// SetElapsed records how long the simulation has been running.
+func (w *WorldState) SetElapsed(elapsed time.Duration) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.Elapsed = elapsed
+}
+
+// RunTime returns how long the simulation has been running.
+func (w *WorldState) RunTime() time.Duration {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.Elapsed
+}
+
+// SetPhase records the part of the turn in progress.
+func (w *WorldState) SetPhase(phase Phase) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.Phase = phase
+}
+
+// SetAmbientEvents replaces the environmental events for the current turn.
+func (w *WorldState) SetAmbientEvents(events []string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.AmbientEvents = events
+}
+
+// InjectEvent adds an event to the current turn's ambient events, for
+// happenings brought into the scene from outside it, such as by a director.
+func (w *WorldState) InjectEvent(description string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.AmbientEvents = append(slices.Clip(w.AmbientEvents), description)
+}
+
+// SetLocation changes the scene location agents perceive, as when the
+// scenario moves on to a new act.
+func (w *WorldState) SetLocation(location string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.Location = location
+}
+
+// SetAtmosphere changes the environmental feel agents perceive.
+func (w *WorldState) SetAtmosphere(atmosphere string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.Atmosphere = atmosphere
+}
+
+// AddCommitment records an agreement in the commitments ledger.
+// A commitment without a status is open.
+func (w *WorldState) AddCommitment(commitment Commitment) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if commitment.Status == "" {
+		commitment.Status = CommitmentOpen
+	}
+	w.Commitments = append(w.Commitments, commitment)
+}
+
+// AddGoal registers an interactive goal, replacing any goal with the same name.
+func (w *WorldState) AddGoal(goal *InteractiveGoal) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.Goals[goal.Name] = goal
+}
+
 // AddAgent registers an agent in the world.
 func (w *WorldState) AddAgent(name, position string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
 	w.Agents[name] = &AgentInWorld{
-		Name:     name,
-		Position: position,
-		Visible:  true,
+		Name:      name,
+		Position:  position,
+		Visible:   true,
+		Condition: 100,
+	}
+}
+
+// SetObserver marks an agent as an observer, who can speak and remember but
+// can't propose or vote on goals.
+func (w *WorldState) SetObserver(name string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if agent, ok := w.Agents[name]; ok {
+		agent.Observer = true
 	}
 }
 
-// AddMessage records a message in the conversation history.
-func (w *WorldState) AddMessage(agentName, content, thinking string, msgType MessageType) {
-	w.ConversationHistory = append(w.ConversationHistory, ConversationMessage{
+// AddMessage records a message in the conversation history and reports whether
+// it did. Blank messages are dropped, as are messages repeating something the
+// agent already said this turn (ignoring case and spacing).
+func (w *WorldState) AddMessage(agentName, content, thinking string, msgType MessageType) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.addMessage(ConversationMessage{
 		AgentName: agentName,
 		Content:   content,
 		Thinking:  thinking,
@@ -81,25 +358,245 @@ func (w *WorldState) AddMessage(agentName, content, thinking string, msgType Mes
 	})
 }
 
+// AddSpokenMessage records dialogue along with the reason the agent gave for
+// it, and reports whether it did, as AddMessage does.
+func (w *WorldState) AddSpokenMessage(agentName, content, rationale string) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.addMessage(ConversationMessage{
+		AgentName: agentName,
+		Content:   content,
+		Type:      MessageTypeDialogue,
+		Rationale: rationale,
+	})
+}
+
+// addMessage stamps a message with the turn, goal and position and adds it to
+// the conversation history, unless it is blank or repeats the agent. The
+// caller holds the write lock.
+func (w *WorldState) addMessage(msg ConversationMessage) bool {
+	if strings.TrimSpace(msg.Content) == "" || w.saidThisTurn(msg.AgentName, contentHash(msg.Content)) {
+		return false
+	}
+	msg.Turn = w.CurrentTurn
+	msg.Goal = w.discussedGoal(msg.AgentName)
+	msg.Position = w.position(msg.AgentName)
+	w.ConversationHistory = append(w.ConversationHistory, msg)
+	return true
+}
+
+// SetReasoningShared sets whether agents see the reasons others give for what they say.
+func (w *WorldState) SetReasoningShared(shared bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.ReasoningShared = shared
+}
+
+// RationaleFor returns the reason an agent gave this turn for saying something
+// (ignoring case and spacing), or "" if they gave none.
+func (w *WorldState) RationaleFor(agentName, content string) string {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	hash := contentHash(content)
+	for i := len(w.ConversationHistory) - 1; i >= 0; i-- {
+		msg := w.ConversationHistory[i]
+		if msg.Turn != w.CurrentTurn {
+			break
+		}
+		if msg.AgentName == agentName && contentHash(msg.Content) == hash {
+			return msg.Rationale
+		}
+	}
+	return ""
+}
+
+// contentHash identifies what a message says, regardless of case and spacing.
+func contentHash(content string) [sha256.Size]byte {
+	normalized := strings.Join(strings.Fields(strings.ToLower(content)), " ")
+	return sha256.Sum256([]byte(normalized))
+}
+
+// saidThisTurn reports whether the agent's messages in the conversation history
+// this turn include content with the given hash. The caller holds the lock.
+func (w *WorldState) saidThisTurn(agentName string, hash [sha256.Size]byte) bool {
+	for i := len(w.ConversationHistory) - 1; i >= 0; i-- {
+		msg := w.ConversationHistory[i]
+		if msg.Turn != w.CurrentTurn {
+			break
+		}
+		if msg.AgentName == agentName && contentHash(msg.Content) == hash {
+			return true
+		}
+	}
+	return false
+}
+
+// LastSpeaker returns the author of the most recent message, or "" if there is none.
+func (w *WorldState) LastSpeaker() string {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	if len(w.ConversationHistory) == 0 {
+		return ""
+	}
+	return w.ConversationHistory[len(w.ConversationHistory)-1].AgentName
+}
+
 // AddPendingDialogue adds dialogue from a tool call (e.g., vote comment, proposal comment).
 // This will be captured by the simulation and cleared after the agent's turn.
 func (w *WorldState) AddPendingDialogue(agentName, content string, msgType MessageType) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.addPendingDialogue(agentName, content, msgType)
+}
+
+// addPendingDialogue appends to the pending dialogue buffer; the caller holds the write lock.
+func (w *WorldState) addPendingDialogue(agentName, content string, msgType MessageType) {
 	w.PendingDialogue = append(w.PendingDialogue, ConversationMessage{
 		AgentName: agentName,
 		Content:   content,
 		Thinking:  "",
 		Type:      msgType,
+		Turn:      w.CurrentTurn,
+		Goal:      w.discussedGoal(agentName),
+	})
+}
+
+// addProposalDialogue adds a proposal comment to the pending dialogue, linked
+// to the goal and the proposal. The caller must hold the lock.
+func (w *WorldState) addProposalDialogue(agentName, content, goalName, proposalID, proposal string) {
+	w.addPendingDialogue(agentName, content, MessageTypeDialogue)
+	msg := &w.PendingDialogue[len(w.PendingDialogue)-1]
+	msg.Goal = goalName
+	msg.ProposalID = proposalID
+	msg.Proposal = proposal
+}
+
+// addVoteDialogue adds a vote comment to the pending dialogue, linked to the
+// goal, the proposal and the choice. The caller must hold the lock.
+func (w *WorldState) addVoteDialogue(agentName, content, goalName, proposalID, vote string) {
+	w.addPendingDialogue(agentName, content, MessageTypeDialogue)
+	msg := &w.PendingDialogue[len(w.PendingDialogue)-1]
+	msg.Goal = goalName
+	msg.ProposalID = proposalID
+	msg.Vote = vote
+}
+
+// Whisper sends a private message from one agent to another nearby, keeping it
+// out of the shared conversation. The whisper is also added to the pending
+// dialogue so the simulation can chronicle it.
+func (w *WorldState) Whisper(from, to, content string) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	sender, ok := w.Agents[from]
+	if !ok {
+		return fmt.Errorf("agent %s not found in world", from)
+	}
+	recipient, ok := w.Agents[to]
+	if !ok {
+		return fmt.Errorf("agent %s not found in world", to)
+	}
+	if to == from {
+		return fmt.Errorf("cannot whisper to yourself")
+	}
+	if recipient.Position != sender.Position || !recipient.Visible {
+		return fmt.Errorf("%s is not close enough to whisper to", to)
+	}
+
+	w.Whispers = append(w.Whispers, ConversationMessage{
+		AgentName: from,
+		Content:   content,
+		Type:      MessageTypeWhisper,
+		Turn:      w.CurrentTurn,
+		Recipient: to,
 	})
+	w.addPendingDialogue(from, content, MessageTypeWhisper)
+	w.PendingDialogue[len(w.PendingDialogue)-1].Recipient = to
+	return nil
+}
+
+// GetRecentWhispers returns a copy of the last N whispers an agent sent or received.
+func (w *WorldState) GetRecentWhispers(agentName string, limit int) []ConversationMessage {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	var whispers []ConversationMessage
+	for _, msg := range w.Whispers {
+		if msg.AgentName == agentName || msg.Recipient == agentName {
+			whispers = append(whispers, msg)
+		}
+	}
+	if limit > 0 && limit < len(whispers) {
+		whispers = whispers[len(whispers)-limit:]
+	}
+	return whispers
 }
 
 // ClearPendingDialogue clears the pending dialogue buffer.
 // Called by the simulation after capturing dialogue events.
 func (w *WorldState) ClearPendingDialogue() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.PendingDialogue = nil
+}
+
+// TakePendingDialogue returns the pending dialogue buffer, consolidated, and clears it.
+func (w *WorldState) TakePendingDialogue() []ConversationMessage {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	pending := w.consolidatePendingDialogue()
 	w.PendingDialogue = nil
+	return pending
+}
+
+// consolidatePendingDialogue makes each agent's pending dialogue distinct while
+// keeping its order. An entry repeating an earlier one of the same type is merged
+// into it, keeping whichever proposal or vote either was linked to; entries linked
+// to different proposals or votes stay apart. Dialogue the agent already spoke
+// aloud this turn is dropped, or just its words if it carries a proposal or vote.
+// The caller holds the lock.
+func (w *WorldState) consolidatePendingDialogue() []ConversationMessage {
+	var consolidated []ConversationMessage
+	for _, msg := range w.PendingDialogue {
+		hash := contentHash(msg.Content)
+		i := slices.IndexFunc(consolidated, func(prev ConversationMessage) bool {
+			return prev.AgentName == msg.AgentName && prev.Type == msg.Type && prev.Recipient == msg.Recipient &&
+				contentHash(prev.Content) == hash && mergeableDialogue(prev, msg)
+		})
+		if i >= 0 {
+			if consolidated[i].ProposalID == "" {
+				consolidated[i].ProposalID = msg.ProposalID
+				consolidated[i].Proposal = msg.Proposal
+				consolidated[i].Vote = msg.Vote
+			}
+			continue
+		}
+
+		if msg.Type == MessageTypeDialogue && w.saidThisTurn(msg.AgentName, hash) {
+			if msg.ProposalID == "" {
+				continue
+			}
+			msg.Content = ""
+		}
+		consolidated = append(consolidated, msg)
+	}
+	return consolidated
+}
+
+// mergeableDialogue reports whether two pending entries with the same words can
+// become one: at most one of them is linked to a proposal or vote, or both to the same.
+func mergeableDialogue(a, b ConversationMessage) bool {
+	return a.ProposalID == "" || b.ProposalID == "" || (a.ProposalID == b.ProposalID && a.Vote == b.Vote)
 }
 
 // GetNearbyAgents returns all agents at the same position as the querying agent.
 func (w *WorldState) GetNearbyAgents(agentName string) []string {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
 	queryAgent, ok := w.Agents[agentName]
 	if !ok {
 		return []string{}
@@ -118,11 +615,32 @@ func (w *WorldState) GetNearbyAgents(agentName string) []string {
 	return nearby
 }
 
-// GetRecentMessages returns the last N messages from conversation history.
+// GetRecentMessages returns a copy of the last N messages from conversation history.
 func (w *WorldState) GetRecentMessages(limit int) []ConversationMessage {
-	if limit <= 0 || limit > len(w.ConversationHistory) {
-		return w.ConversationHistory
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	start := 0
+	if limit > 0 && limit < len(w.ConversationHistory) {
+		start = len(w.ConversationHistory) - limit
+	}
+	return append([]ConversationMessage(nil), w.ConversationHistory[start:]...)
+}
+
+// GetGoalDiscussion returns a copy of the last N messages in the conversation
+// history about a goal.
+func (w *WorldState) GetGoalDiscussion(goalName string, limit int) []ConversationMessage {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	var discussion []ConversationMessage
+	for _, msg := range w.ConversationHistory {
+		if msg.Goal == goalName {
+			discussion = append(discussion, msg)
+		}
+	}
+	if limit > 0 && limit < len(discussion) {
+		discussion = discussion[len(discussion)-limit:]
 	}
-	start := len(w.ConversationHistory) - limit
-	return w.ConversationHistory[start:]
+	return discussion
 }
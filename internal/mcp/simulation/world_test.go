@@ -0,0 +1,1002 @@
+package simulation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/poiesic/wonda/internal/memory"
+	"github.com/poiesic/wonda/internal/runtime"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// These tests exercise WorldState from many goroutines; run them with
+// `go test -race` to catch unsynchronized access.
+
+func newTestWorld(agents int) *WorldState {
+	world := NewWorldState("cafe", "quiet")
+	for i := 0; i < agents; i++ {
+		world.AddAgent(fmt.Sprintf("agent%d", i), "table")
+	}
+	world.AddGoal(NewInteractiveGoal("dinner", "Pick a restaurant", "consensus", 1))
+	world.SetTurn(1)
+	return world
+}
+
+func agentContext(name string) context.Context {
+	return context.WithValue(context.Background(), runtime.AgentNameKey, name)
+}
+
+func TestWorldStateConcurrentAccess(t *testing.T) {
+	t.Run("concurrent tool calls keep goals consistent", func(t *testing.T) {
+		const agents = 16
+		world := newTestWorld(agents)
+		propose := NewProposeSolutionTool(world)
+		view := NewViewGoalTool(world)
+		perceive := NewPerceiveTool(world)
+		speak := NewSpeakTool(world)
+
+		var wg sync.WaitGroup
+		for i := 0; i < agents; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				ctx := agentContext(fmt.Sprintf("agent%d", i))
+				_, err := propose.Handler(ctx, map[string]interface{}{
+					"goal_name": "dinner",
+					"solution":  fmt.Sprintf("place %d", i),
+					"comment":   "how about it?",
+				})
+				assert.NoError(t, err)
+				_, err = view.Handler(ctx, map[string]interface{}{"goal_name": "dinner"})
+				assert.NoError(t, err)
+				_, err = perceive.Handler(ctx, map[string]interface{}{})
+				assert.NoError(t, err)
+				_, err = speak.Handler(ctx, map[string]interface{}{"message": "hello"})
+				assert.NoError(t, err)
+			}(i)
+		}
+		wg.Wait()
+
+		snapshot := world.Snapshot()
+		assert.Len(t, snapshot.Goals["dinner"].Proposals, agents)
+		assert.Len(t, snapshot.ConversationHistory, agents)
+		assert.Len(t, world.TakePendingDialogue(), agents)
+		assert.Empty(t, world.TakePendingDialogue())
+	})
+
+	t.Run("concurrent votes resolve a proposal once", func(t *testing.T) {
+		const agents = 8
+		world := newTestWorld(agents)
+		_, err := NewProposeSolutionTool(world).Handler(agentContext("agent0"), map[string]interface{}{
+			"goal_name": "dinner",
+			"solution":  "Bella's",
+			"comment":   "Bella's?",
+		})
+		require.NoError(t, err)
+
+		vote := NewVoteOnProposalTool(world)
+		var wg sync.WaitGroup
+		for i := 1; i < agents; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				_, err := vote.Handler(agentContext(fmt.Sprintf("agent%d", i)), map[string]interface{}{
+					"goal_name":   "dinner",
+					"proposal_id": "proposal_1",
+					"vote":        "yes",
+					"comment":     "sure",
+				})
+				assert.NoError(t, err)
+			}(i)
+		}
+		wg.Wait()
+
+		goal := world.Snapshot().Goals["dinner"]
+		assert.Equal(t, GoalCompleted, goal.Status)
+		assert.Len(t, goal.Proposals["proposal_1"].Votes, agents)
+	})
+
+	t.Run("snapshot is isolated from later changes", func(t *testing.T) {
+		world := newTestWorld(2)
+		snapshot := world.Snapshot()
+
+		_, err := NewProposeSolutionTool(world).Handler(agentContext("agent0"), map[string]interface{}{
+			"goal_name": "dinner",
+			"solution":  "Bella's",
+			"comment":   "Bella's?",
+		})
+		require.NoError(t, err)
+		world.SetTurn(2)
+
+		assert.Empty(t, snapshot.Goals["dinner"].Proposals)
+		assert.Equal(t, 1, snapshot.CurrentTurn)
+		assert.Equal(t, 2, world.Turn())
+	})
+}
+
+func TestDialogueDeduplication(t *testing.T) {
+	t.Run("drops repeats of what the agent said this turn", func(t *testing.T) {
+		world := newTestWorld(2)
+		assert.True(t, world.AddMessage("agent0", "How about Bella's?", "", MessageTypeDialogue))
+		assert.True(t, world.AddMessage("agent1", "How about Bella's?", "", MessageTypeDialogue))
+		assert.False(t, world.AddMessage("agent0", "  how about   bella's? ", "", MessageTypeDialogue))
+		assert.False(t, world.AddMessage("agent0", " ", "", MessageTypeDialogue))
+
+		// Saying it again next turn is new dialogue
+		world.SetTurn(2)
+		assert.True(t, world.AddMessage("agent0", "How about Bella's?", "", MessageTypeDialogue))
+		assert.Len(t, world.Snapshot().ConversationHistory, 3)
+	})
+
+	t.Run("consolidates pending dialogue", func(t *testing.T) {
+		world := newTestWorld(2)
+		world.AddMessage("agent0", "Bella's it is.", "", MessageTypeDialogue)
+
+		propose := NewProposeSolutionTool(world)
+		_, err := propose.Handler(agentContext("agent0"), map[string]interface{}{
+			"goal_name": "dinner",
+			"solution":  "Bella's",
+			"comment":   "Bella's it is.",
+		})
+		require.NoError(t, err)
+		world.AddPendingDialogue("agent0", "Let's not wait.", MessageTypeDialogue)
+		world.AddPendingDialogue("agent0", "let's not wait.", MessageTypeDialogue)
+		world.AddPendingDialogue("agent1", "Let's not wait.", MessageTypeDialogue)
+
+		pending := world.TakePendingDialogue()
+		require.Len(t, pending, 3)
+
+		// The proposal comment repeated what agent0 said aloud, so only the proposal is left
+		assert.Equal(t, "proposal_1", pending[0].ProposalID)
+		assert.Empty(t, pending[0].Content)
+		assert.Equal(t, "agent0", pending[1].AgentName)
+		assert.Equal(t, "Let's not wait.", pending[1].Content)
+		assert.Equal(t, "agent1", pending[2].AgentName)
+		assert.Empty(t, world.TakePendingDialogue())
+	})
+}
+
+func TestSimulationStatusTool(t *testing.T) {
+	t.Run("reports turn budget, phase, and participation", func(t *testing.T) {
+		world := newTestWorld(3)
+		world.SetMaxTurns(10)
+		world.SetTurn(2)
+		world.SetPhase(PhaseDeliberation)
+		world.AddMessage("agent1", "hello", "", MessageTypeDialogue)
+		_, err := NewProposeSolutionTool(world).Handler(agentContext("agent1"), map[string]interface{}{
+			"goal_name": "dinner",
+			"solution":  "Bella's",
+			"comment":   "Bella's?",
+		})
+		require.NoError(t, err)
+
+		result, err := NewSimulationStatusTool(world).Handler(agentContext("agent0"), map[string]interface{}{})
+		require.NoError(t, err)
+		status := result.(map[string]interface{})
+
+		assert.Equal(t, 2, status["current_turn"])
+		assert.Equal(t, "deliberation", status["phase"])
+		assert.Equal(t, 8, status["turns_remaining"])
+		assert.Equal(t, []string{"agent2"}, status["not_yet_spoken_this_turn"])
+
+		participation := status["participation"].([]Participation)
+		require.Len(t, participation, 3)
+		assert.Equal(t, Participation{Name: "agent1", Spoke: 1, SpokeThisTurn: 1, Proposals: 1, Votes: 1}, participation[1])
+	})
+}
+
+func TestPassTurnTool(t *testing.T) {
+	t.Run("records the pass and ends the turn", func(t *testing.T) {
+		world := newTestWorld(2)
+		tool := NewPassTurnTool(world)
+		assert.True(t, tool.EndsTurn)
+
+		_, err := tool.Handler(agentContext("agent0"), map[string]interface{}{"reason": "I've said my piece"})
+		require.NoError(t, err)
+		_, err = tool.Handler(agentContext("agent1"), map[string]interface{}{})
+		require.NoError(t, err)
+
+		pending := world.TakePendingDialogue()
+		require.Len(t, pending, 2)
+		assert.Equal(t, MessageTypePass, pending[0].Type)
+		assert.Equal(t, "I've said my piece", pending[0].Content)
+		assert.Equal(t, MessageTypePass, pending[1].Type)
+		assert.Empty(t, pending[1].Content)
+	})
+}
+
+func TestGoalThreads(t *testing.T) {
+	world := newTestWorld(2)
+	speak := NewSpeakTool(world)
+	say := func(agentName string, arguments map[string]interface{}) {
+		t.Helper()
+		_, err := speak.Handler(agentContext(agentName), arguments)
+		require.NoError(t, err)
+	}
+
+	// With one goal, dialogue is taken to be about it
+	say("agent0", map[string]interface{}{"message": "Pizza?"})
+	world.AddGoal(NewInteractiveGoal("budget", "Agree a budget", "consensus", 1))
+
+	// With two, it follows the speaker's focus or the goal they name
+	say("agent0", map[string]interface{}{"message": "What can we afford?"})
+	say("agent1", map[string]interface{}{"message": "Twenty each.", "goal": "budget"})
+	say("agent1", map[string]interface{}{"message": "So, pizza?"})
+	world.SetFocus("agent0", "dinner")
+	say("agent0", map[string]interface{}{"message": "Pizza it is."})
+
+	_, err := speak.Handler(agentContext("agent0"), map[string]interface{}{"message": "Hm.", "goal": "nope"})
+	assert.ErrorContains(t, err, "goal not found: nope")
+
+	var goals []string
+	for _, msg := range world.GetRecentMessages(0) {
+		goals = append(goals, msg.Goal)
+	}
+	assert.Equal(t, []string{"dinner", "", "budget", "budget", "dinner"}, goals)
+
+	result, err := NewViewGoalTool(world).Handler(agentContext("agent1"), map[string]interface{}{"goal_name": "dinner"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"agent0: Pizza?", "agent0: Pizza it is."}, result.(map[string]interface{})["discussion"])
+
+	_, err = NewProposeSolutionTool(world).Handler(agentContext("agent0"), map[string]interface{}{
+		"goal_name": "budget", "solution": "Twenty each", "comment": "Twenty each, then.",
+	})
+	require.NoError(t, err)
+	pending := world.TakePendingDialogue()
+	require.Len(t, pending, 1)
+	assert.Equal(t, "budget", pending[0].Goal)
+}
+
+func TestMovement(t *testing.T) {
+	world := NewWorldState("house", "quiet")
+	world.AddPlace("kitchen", "Pots everywhere", []string{"hall"})
+	world.AddPlace("hall", "A long hall", []string{"study"})
+	world.AddPlace("study", "Books", nil)
+	world.AddAgent("alice", "kitchen")
+	world.AddAgent("bob", "kitchen")
+	world.AddAgent("carol", "study")
+	world.SetTurn(1)
+
+	result, err := NewLookAroundTool(world).Handler(agentContext("alice"), map[string]interface{}{})
+	require.NoError(t, err)
+	assert.Equal(t, &LookAroundResult{
+		Location:    "kitchen",
+		Description: "Pots everywhere",
+		Here:        []string{"bob"},
+		Exits:       []ExitView{{Location: "hall", Agents: []string{}}},
+	}, result)
+
+	speak := NewSpeakTool(world)
+	_, err = speak.Handler(agentContext("alice"), map[string]interface{}{"message": "Psst, Bob."})
+	require.NoError(t, err)
+	_, err = speak.Handler(agentContext("carol"), map[string]interface{}{"message": "Anyone there?"})
+	require.NoError(t, err)
+
+	perceive := NewPerceiveTool(world)
+	perception, err := perceive.Handler(agentContext("bob"), map[string]interface{}{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"alice: Psst, Bob."}, perception.(*PerceptionResult).RecentMessages, "only speech where you are is heard")
+	assert.Equal(t, []string{"hall"}, perception.(*PerceptionResult).Exits)
+
+	moveTo := NewMoveToTool(world)
+	_, err = moveTo.Handler(agentContext("bob"), map[string]interface{}{"location": "study"})
+	assert.ErrorContains(t, err, "can't get to study from here (exits: hall)")
+	for _, location := range []string{"hall", "study"} {
+		_, err = moveTo.Handler(agentContext("bob"), map[string]interface{}{"location": location})
+		require.NoError(t, err)
+	}
+	assert.Equal(t, []string{"bob"}, world.GetNearbyAgents("carol"))
+
+	perception, err = perceive.Handler(agentContext("bob"), map[string]interface{}{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"carol: Anyone there?"}, perception.(*PerceptionResult).RecentMessages)
+
+	pending := world.TakePendingDialogue()
+	require.Len(t, pending, 2)
+	assert.Equal(t, MessageTypeAction, pending[1].Type)
+	assert.Equal(t, "goes to study", pending[1].Content)
+
+	_, err = NewMoveToTool(newTestWorld(1)).Handler(agentContext("agent0"), map[string]interface{}{"location": "bar"})
+	assert.ErrorContains(t, err, "nowhere else to go")
+}
+
+func TestObjects(t *testing.T) {
+	world := NewWorldState("house", "quiet")
+	world.AddPlace("kitchen", "", []string{"study"})
+	world.AddAgent("alice", "kitchen")
+	world.AddAgent("bob", "kitchen")
+	world.AddAgent("carol", "study")
+	world.AddObject(WorldObject{Name: "key", Description: "A brass key", Details: "Stamped 'cellar'", Position: "kitchen"})
+	world.AddObject(WorldObject{Name: "safe", Description: "A steel safe", Position: "kitchen", Fixed: true})
+	world.AddObject(WorldObject{Name: "map", Description: "A torn map", Position: "study"})
+	world.SetTurn(1)
+
+	pickUp := NewPickUpTool(world)
+	give := NewGiveTool(world)
+	inspect := NewInspectObjectTool(world)
+
+	_, err := pickUp.Handler(agentContext("alice"), map[string]interface{}{"object": "map"})
+	assert.ErrorContains(t, err, "there is no map here", "objects elsewhere are out of reach")
+	_, err = pickUp.Handler(agentContext("alice"), map[string]interface{}{"object": "safe"})
+	assert.ErrorContains(t, err, "can't be picked up")
+	_, err = pickUp.Handler(agentContext("alice"), map[string]interface{}{"object": "key"})
+	require.NoError(t, err)
+	_, err = pickUp.Handler(agentContext("bob"), map[string]interface{}{"object": "key"})
+	assert.ErrorContains(t, err, "alice has the key")
+
+	result, err := inspect.Handler(agentContext("bob"), map[string]interface{}{"object": "key"})
+	require.NoError(t, err)
+	assert.Equal(t, &ObjectResult{Name: "key", Description: "A brass key", Details: "Stamped 'cellar'", HeldBy: "alice"}, result)
+
+	_, err = give.Handler(agentContext("alice"), map[string]interface{}{"object": "key", "recipient": "carol"})
+	assert.ErrorContains(t, err, "carol is not close enough")
+	_, err = give.Handler(agentContext("alice"), map[string]interface{}{"object": "key", "recipient": "bob"})
+	require.NoError(t, err)
+	assert.Empty(t, world.Inventory("alice"))
+	assert.Equal(t, []string{"key: A brass key"}, world.Inventory("bob"))
+	assert.Equal(t, []string{"safe: A steel safe"}, world.ObjectsInReach("bob"))
+
+	// Held objects go where their holder goes
+	require.NoError(t, world.MoveTo("bob", "study"))
+	_, err = inspect.Handler(agentContext("alice"), map[string]interface{}{"object": "key"})
+	assert.ErrorContains(t, err, "there is no key here")
+
+	var actions []string
+	for _, msg := range world.TakePendingDialogue() {
+		actions = append(actions, msg.Content)
+	}
+	assert.Equal(t, []string{"picks up the key", "gives the key to bob", "goes to study"}, actions)
+
+	restored := NewWorldState("house", "quiet")
+	restored.AddObject(WorldObject{Name: "key", Description: "A brass key", Position: "kitchen"})
+	restored.AddObject(WorldObject{Name: "safe", Description: "A steel safe", Position: "kitchen", Fixed: true})
+	restored.AddObject(WorldObject{Name: "map", Description: "A torn map", Position: "study"})
+	for _, name := range []string{"alice", "bob", "carol"} {
+		restored.AddAgent(name, "kitchen")
+	}
+	require.NoError(t, restored.Restore(world.Checkpoint()))
+	assert.Equal(t, "bob", restored.Objects["key"].Holder)
+}
+
+func TestSharedReasoning(t *testing.T) {
+	world := newTestWorld(2)
+	_, err := NewSpeakTool(world).Handler(agentContext("agent0"), map[string]interface{}{
+		"message":   "Let's do the noodle place.",
+		"rationale": "It's the only place open late.",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "It's the only place open late.", world.RationaleFor("agent0", "let's do the  noodle place."))
+	assert.Empty(t, world.RationaleFor("agent1", "Let's do the noodle place."))
+
+	perceive := NewPerceiveTool(world)
+	result, err := perceive.Handler(agentContext("agent1"), map[string]interface{}{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"agent0: Let's do the noodle place."}, result.(*PerceptionResult).RecentMessages, "reasons stay private by default")
+
+	world.SetReasoningShared(true)
+	result, err = perceive.Handler(agentContext("agent1"), map[string]interface{}{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"agent0: Let's do the noodle place. (reasoning: It's the only place open late.)"}, result.(*PerceptionResult).RecentMessages)
+
+	restored := newTestWorld(2)
+	require.NoError(t, restored.Restore(world.Checkpoint()))
+	assert.True(t, restored.Snapshot().ReasoningShared)
+}
+
+func TestWhisperTool(t *testing.T) {
+	t.Run("only the recipient hears it", func(t *testing.T) {
+		world := newTestWorld(3)
+		tool := NewWhisperTool(world)
+		assert.False(t, tool.EndsTurn)
+
+		_, err := tool.Handler(agentContext("agent0"), map[string]interface{}{
+			"recipient": "agent1",
+			"message":   "Back me on the noodle place.",
+		})
+		require.NoError(t, err)
+		assert.Empty(t, world.GetRecentMessages(0), "whispers stay out of the shared conversation")
+
+		pending := world.TakePendingDialogue()
+		require.Len(t, pending, 1)
+		assert.Equal(t, MessageTypeWhisper, pending[0].Type)
+		assert.Equal(t, "agent1", pending[0].Recipient)
+
+		perceive := NewPerceiveTool(world)
+		result, err := perceive.Handler(agentContext("agent1"), map[string]interface{}{})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"agent0 (whispering to you): Back me on the noodle place."}, result.(*PerceptionResult).Whispers)
+
+		result, err = perceive.Handler(agentContext("agent0"), map[string]interface{}{})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"You (whispering to agent1): Back me on the noodle place."}, result.(*PerceptionResult).Whispers)
+
+		result, err = perceive.Handler(agentContext("agent2"), map[string]interface{}{})
+		require.NoError(t, err)
+		assert.Empty(t, result.(*PerceptionResult).Whispers)
+	})
+
+	t.Run("needs a nearby recipient other than the sender", func(t *testing.T) {
+		world := newTestWorld(2)
+		world.AddAgent("outside", "doorway")
+		tool := NewWhisperTool(world)
+
+		for recipient, problem := range map[string]string{
+			"agent0":  "cannot whisper to yourself",
+			"nobody":  "agent nobody not found",
+			"outside": "outside is not close enough",
+		} {
+			_, err := tool.Handler(agentContext("agent0"), map[string]interface{}{"recipient": recipient, "message": "Psst"})
+			assert.ErrorContains(t, err, problem)
+		}
+		assert.Empty(t, world.TakePendingDialogue())
+	})
+
+	t.Run("survives a checkpoint", func(t *testing.T) {
+		world := newTestWorld(2)
+		require.NoError(t, world.Whisper("agent0", "agent1", "Psst"))
+
+		restored := newTestWorld(2)
+		require.NoError(t, restored.Restore(world.Checkpoint()))
+		assert.Equal(t, world.GetRecentWhispers("agent1", 5), restored.GetRecentWhispers("agent1", 5))
+	})
+}
+
+func TestCondition(t *testing.T) {
+	t.Run("adjustments clamp and are recorded", func(t *testing.T) {
+		world := newTestWorld(2)
+		world.SetCondition("agent0", 30)
+		assert.Empty(t, world.TakeConditionChanges(), "setting the starting condition isn't a change")
+
+		assert.Equal(t, 0, world.AdjustCondition("agent0", -50, "storm"))
+		assert.Equal(t, 100, world.AdjustCondition("agent1", 10, "storm"), "already at full condition")
+
+		changes := world.TakeConditionChanges()
+		require.Len(t, changes, 1)
+		assert.Equal(t, ConditionChange{AgentName: "agent0", Before: 30, After: 0, Cause: "storm"}, changes[0])
+		assert.Empty(t, world.TakeConditionChanges())
+	})
+
+	t.Run("resting passes the turn and recovers condition", func(t *testing.T) {
+		world := newTestWorld(2)
+		world.SetCondition("agent0", 20)
+		tool := NewRestTool(world, 15)
+		assert.True(t, tool.EndsTurn)
+
+		result, err := tool.Handler(agentContext("agent0"), map[string]interface{}{})
+		require.NoError(t, err)
+		assert.Equal(t, 35, result.(*RestResult).Condition)
+		assert.Equal(t, 35, world.Condition("agent0"))
+
+		pending := world.TakePendingDialogue()
+		require.Len(t, pending, 1)
+		assert.Equal(t, MessageTypePass, pending[0].Type)
+		changes := world.TakeConditionChanges()
+		require.Len(t, changes, 1)
+		assert.Equal(t, "rested", changes[0].Cause)
+	})
+}
+
+func TestFocusTags(t *testing.T) {
+	world := newTestWorld(2)
+	world.Update(func(w *WorldState) error {
+		w.Goals["dinner"].Tags = []string{"food", "budget"}
+		return nil
+	})
+	lunch := NewInteractiveGoal("lunch", "Where should we have lunch?", "consensus", 1)
+	lunch.Tags = []string{"food", "timing"}
+	world.AddGoal(lunch)
+
+	assert.Equal(t, []string{"budget", "food", "timing"}, world.FocusTags("agent0"), "every pending goal without a focus")
+
+	_, err := NewViewGoalTool(world).Handler(agentContext("agent0"), map[string]interface{}{"goal_name": "lunch"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"food", "timing"}, world.FocusTags("agent0"))
+	assert.Equal(t, []string{"budget", "food", "timing"}, world.FocusTags("agent1"))
+
+	world.Update(func(w *WorldState) error {
+		w.Goals["lunch"].Status = GoalCompleted
+		return nil
+	})
+	assert.Equal(t, []string{"budget", "food"}, world.FocusTags("agent0"), "completed goals lose the focus")
+}
+
+func TestProposalsAwaitingVote(t *testing.T) {
+	t.Run("counts pending proposals the agent hasn't voted on", func(t *testing.T) {
+		world := newTestWorld(2)
+		assert.Equal(t, 0, world.ProposalsAwaitingVote("agent1"))
+
+		_, err := NewProposeSolutionTool(world).Handler(agentContext("agent0"), map[string]interface{}{
+			"goal_name": "dinner",
+			"solution":  "Bella's",
+			"comment":   "Bella's?",
+		})
+		require.NoError(t, err)
+
+		// The proposer supports their own proposal
+		assert.Equal(t, 0, world.ProposalsAwaitingVote("agent0"))
+		assert.Equal(t, 1, world.ProposalsAwaitingVote("agent1"))
+
+		_, err = NewVoteOnProposalTool(world).Handler(agentContext("agent1"), map[string]interface{}{
+			"goal_name":   "dinner",
+			"proposal_id": "proposal_1",
+			"vote":        "no",
+			"comment":     "Too far.",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 0, world.ProposalsAwaitingVote("agent1"))
+	})
+}
+
+func TestExpireGoals(t *testing.T) {
+	world := newTestWorld(2)
+	world.AddGoal(NewInteractiveGoal("dessert", "Pick a dessert", "consensus", 2))
+	deadline := NewInteractiveGoal("taxi", "Book a taxi home", "consensus", 3)
+	deadline.MaxTurns = 2
+	world.AddGoal(deadline)
+
+	_, err := NewProposeSolutionTool(world).Handler(agentContext("agent0"), map[string]interface{}{
+		"goal_name": "taxi",
+		"solution":  "City Cabs",
+		"comment":   "City Cabs?",
+	})
+	require.NoError(t, err)
+
+	// Nothing expires before its turn limit, and goals without one never do
+	assert.Empty(t, world.ExpireGoals(1))
+	assert.Equal(t, []string{"taxi"}, world.ExpireGoals(2))
+	assert.Empty(t, world.ExpireGoals(3))
+
+	goals := world.Snapshot().Goals
+	goal := goals["taxi"]
+	assert.Equal(t, GoalFailed, goal.Status)
+	assert.Equal(t, 2, goal.CompletedAt)
+	assert.Equal(t, ProposalRejected, goal.Proposals["proposal_1"].Status)
+	assert.Equal(t, 0, world.ProposalsAwaitingVote("agent1"))
+
+	assert.Equal(t, GoalPending, goals["dessert"].Status)
+}
+
+func TestExpireDeadlines(t *testing.T) {
+	world := newTestWorld(2)
+	world.AddGoal(NewInteractiveGoal("dessert", "Pick a dessert", "consensus", 2))
+	bomb := NewInteractiveGoal("bomb", "Defuse the bomb", "consensus", 1)
+	bomb.Deadline = 5 * time.Minute
+	world.AddGoal(bomb)
+
+	world.SetElapsed(4 * time.Minute)
+	assert.Empty(t, world.ExpireDeadlines(1))
+
+	// Agents are told the time left
+	result, err := NewViewGoalTool(world).Handler(agentContext("agent0"), map[string]interface{}{"goal_name": "bomb"})
+	require.NoError(t, err)
+	assert.Equal(t, "5m0s", result.(map[string]interface{})["deadline"])
+	assert.Equal(t, "1m0s", result.(map[string]interface{})["time_remaining"])
+
+	// Goals without a deadline never expire by time
+	world.SetElapsed(5 * time.Minute)
+	assert.Equal(t, []string{"bomb"}, world.ExpireDeadlines(2))
+	assert.Empty(t, world.ExpireDeadlines(3))
+
+	goals := world.Snapshot().Goals
+	assert.Equal(t, GoalFailed, goals["bomb"].Status)
+	assert.Equal(t, 2, goals["bomb"].CompletedAt)
+	assert.Equal(t, GoalPending, goals["dessert"].Status)
+
+	// Run time carries over checkpoints
+	restored := newTestWorld(2)
+	restored.AddGoal(NewInteractiveGoal("dessert", "Pick a dessert", "consensus", 2))
+	restored.AddGoal(NewInteractiveGoal("bomb", "Defuse the bomb", "consensus", 1))
+	require.NoError(t, restored.Restore(world.Checkpoint()))
+	assert.Equal(t, 5*time.Minute, restored.RunTime())
+}
+
+func TestForbiddenOutcomes(t *testing.T) {
+	newForbiddenWorld := func() *WorldState {
+		world := newTestWorld(2)
+		goal := NewInteractiveGoal("venue", "Pick a venue", "consensus", 1)
+		goal.Forbidden = []*ForbiddenOutcome{
+			{Phrases: []string{"Blue Moon"}, Reason: "the Blue Moon closed last month"},
+			{Pattern: regexp.MustCompile(`(?i)\bbar\b`), Reason: "agent1 doesn't drink"},
+		}
+		world.AddGoal(goal)
+		return world
+	}
+	propose := func(world *WorldState, solution string) (interface{}, error) {
+		return NewProposeSolutionTool(world).Handler(agentContext("agent0"), map[string]interface{}{
+			"goal_name": "venue",
+			"solution":  solution,
+			"comment":   "How about this?",
+		})
+	}
+
+	t.Run("refuses forbidden proposals with the reason", func(t *testing.T) {
+		world := newForbiddenWorld()
+
+		_, err := propose(world, "Dinner at the blue moon")
+		assert.ErrorContains(t, err, "the Blue Moon closed last month")
+		_, err = propose(world, "The bar on 5th")
+		assert.ErrorContains(t, err, "agent1 doesn't drink")
+		assert.Empty(t, world.Snapshot().Goals["venue"].Proposals)
+		assert.Empty(t, world.Snapshot().PendingDialogue, "refused proposals say nothing")
+
+		_, err = propose(world, "Barney's Diner")
+		assert.NoError(t, err)
+	})
+
+	t.Run("a forbidden proposal can't be accepted", func(t *testing.T) {
+		world := newForbiddenWorld()
+		goal := world.Snapshot().Goals["venue"]
+		proposalID := goal.AddProposal("agent0", "The Blue Moon", 1)
+		proposal := goal.Proposals[proposalID]
+		proposal.Status = ProposalAccepted
+
+		assert.False(t, goal.EnforceForbidden(proposal, 1))
+		assert.Equal(t, ProposalRejected, proposal.Status)
+	})
+}
+
+func TestObservers(t *testing.T) {
+	propose := func(world *WorldState, agent string) (interface{}, error) {
+		return NewProposeSolutionTool(world).Handler(agentContext(agent), map[string]interface{}{
+			"goal_name": "dinner",
+			"solution":  "Bella's",
+			"comment":   "Bella's?",
+		})
+	}
+	vote := func(world *WorldState, agent string) (interface{}, error) {
+		return NewVoteOnProposalTool(world).Handler(agentContext(agent), map[string]interface{}{
+			"goal_name":   "dinner",
+			"proposal_id": "proposal_1",
+			"vote":        "yes",
+			"comment":     "Sure.",
+		})
+	}
+
+	t.Run("observers can't propose or vote and don't count toward consensus", func(t *testing.T) {
+		world := newTestWorld(3)
+		world.SetObserver("agent2")
+
+		_, err := propose(world, "agent2")
+		assert.ErrorContains(t, err, "no say")
+
+		_, err = propose(world, "agent0")
+		require.NoError(t, err)
+		_, err = vote(world, "agent2")
+		assert.ErrorContains(t, err, "no say")
+		assert.Equal(t, 0, world.ProposalsAwaitingVote("agent2"))
+
+		result, err := vote(world, "agent1")
+		require.NoError(t, err)
+		assert.Equal(t, "accepted", result.(map[string]interface{})["outcome"])
+	})
+
+	t.Run("only assigned agents decide assigned goals", func(t *testing.T) {
+		world := newTestWorld(3)
+		world.Update(func(w *WorldState) error {
+			w.Goals["dinner"].Assigned = []string{"agent1", "agent0"}
+			return nil
+		})
+
+		world.View(func(w *WorldState) {
+			assert.Equal(t, []string{"agent0", "agent1"}, w.GoalParticipants(w.Goals["dinner"]))
+		})
+		_, err := propose(world, "agent2")
+		assert.ErrorContains(t, err, "no say")
+		_, err = propose(world, "agent1")
+		require.NoError(t, err)
+	})
+
+	t.Run("agents only see the goals they decide", func(t *testing.T) {
+		world := newTestWorld(4)
+		world.AddGoal(NewInteractiveGoal("wine", "Pick the wine", "consensus", 2))
+		world.SetObserver("agent3")
+		world.Update(func(w *WorldState) error {
+			w.Goals["wine"].Assigned = []string{"agent0"}
+			return nil
+		})
+		listed := func(agent string) []string {
+			result, err := NewListGoalsTool(world).Handler(agentContext(agent), map[string]interface{}{})
+			require.NoError(t, err)
+			var names []string
+			for _, goal := range result.(map[string]interface{})["goals"].([]map[string]interface{}) {
+				names = append(names, goal["name"].(string))
+			}
+			sort.Strings(names)
+			return names
+		}
+
+		assert.Equal(t, []string{"dinner", "wine"}, listed("agent0"))
+		assert.Equal(t, []string{"dinner"}, listed("agent1"))
+		assert.Equal(t, []string{"dinner", "wine"}, listed("agent3"), "observers watch every goal")
+
+		view := NewViewGoalTool(world)
+		_, err := view.Handler(agentContext("agent1"), map[string]interface{}{"goal_name": "wine"})
+		assert.ErrorContains(t, err, "it's for agent0 to decide")
+		_, err = view.Handler(agentContext("agent3"), map[string]interface{}{"goal_name": "wine"})
+		assert.NoError(t, err)
+	})
+}
+
+func TestRelationshipTools(t *testing.T) {
+	t.Run("adjusts from carried-over values", func(t *testing.T) {
+		world := newTestWorld(2)
+		world.SetRelationship(Relationship{From: "agent0", To: "agent1", Value: -2, Reason: "old grudge"})
+
+		result, err := NewAdjustRelationshipTool(world).Handler(agentContext("agent0"), map[string]interface{}{
+			"name":   "agent1",
+			"change": float64(5),
+			"reason": "paid for dinner",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 1, result.(map[string]interface{})["value"], "change is limited per event")
+
+		rels := world.RelationshipList()
+		require.Len(t, rels, 1)
+		assert.True(t, rels[0].Changed)
+		assert.Equal(t, "paid for dinner", rels[0].Reason)
+	})
+
+	t.Run("rejects unknown agents and yourself", func(t *testing.T) {
+		world := newTestWorld(2)
+		adjust := NewAdjustRelationshipTool(world)
+
+		_, err := adjust.Handler(agentContext("agent0"), map[string]interface{}{"name": "nobody", "change": float64(1)})
+		assert.Error(t, err)
+		_, err = adjust.Handler(agentContext("agent0"), map[string]interface{}{"name": "agent0", "change": float64(1)})
+		assert.Error(t, err)
+	})
+
+	t.Run("shows neutral for people without a history", func(t *testing.T) {
+		world := newTestWorld(3)
+		world.SetRelationship(Relationship{From: "agent0", To: "agent2", Value: 4})
+
+		result, err := NewViewRelationshipsTool(world).Handler(agentContext("agent0"), map[string]interface{}{})
+		require.NoError(t, err)
+		rels := result.(map[string]interface{})["relationships"].([]map[string]interface{})
+		require.Len(t, rels, 2)
+		assert.Equal(t, 0, rels[0]["value"])
+		assert.Equal(t, 4, rels[1]["value"])
+	})
+
+	t.Run("adjusts trust apart from affinity", func(t *testing.T) {
+		world := newTestWorld(2)
+		result, err := NewAdjustRelationshipTool(world).Handler(agentContext("agent0"), map[string]interface{}{
+			"name":   "agent1",
+			"trust":  float64(-2),
+			"reason": "lied about the bill",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 0, result.(map[string]interface{})["value"])
+		assert.Equal(t, -2, result.(map[string]interface{})["trust"])
+	})
+
+	t.Run("describes one relationship", func(t *testing.T) {
+		world := newTestWorld(2)
+		world.SetRelationship(Relationship{From: "agent0", To: "agent1", Value: 5, Trust: -3, Familiarity: 9, Reason: "old rivals"})
+
+		result, err := NewQueryRelationshipTool(world).Handler(agentContext("agent0"), map[string]interface{}{"name": "agent1"})
+		require.NoError(t, err)
+		rel := result.(map[string]interface{})
+		assert.Equal(t, -3, rel["trust"])
+		assert.Equal(t, "agent1 is a friend, not entirely trustworthy, known very well", rel["summary"])
+		assert.Equal(t, []string{"agent1: a friend, not entirely trustworthy, known very well (old rivals)"}, world.RelationshipNotes("agent0"))
+
+		_, err = NewQueryRelationshipTool(world).Handler(agentContext("agent0"), map[string]interface{}{"name": "nobody"})
+		assert.Error(t, err)
+	})
+
+	t.Run("grows familiarity between agents who talked", func(t *testing.T) {
+		world := newTestWorld(3)
+		world.AddMessage("agent0", "Bella's?", "", MessageTypeDialogue)
+		world.AddMessage("agent0", "Again?", "", MessageTypeDialogue)
+		world.AddMessage("agent1", "Hmm.", "", MessageTypeMonologue)
+		world.GrowFamiliarity()
+
+		assert.Equal(t, 1, world.RelationshipBetween("agent1", "agent0").Familiarity, "once per turn however much was said")
+		assert.Equal(t, 1, world.RelationshipBetween("agent2", "agent0").Familiarity)
+		assert.Equal(t, 0, world.RelationshipBetween("agent0", "agent1").Familiarity, "thinking isn't talking")
+	})
+}
+
+func TestUpdateEmotionTool(t *testing.T) {
+	t.Run("shifts mood gradually and records why", func(t *testing.T) {
+		world := newTestWorld(1)
+		world.SetEmotion("agent0", "neutral", 5)
+
+		result, err := NewUpdateEmotionTool(world).Handler(agentContext("agent0"), map[string]interface{}{
+			"emotion":   "angry",
+			"intensity": float64(10),
+			"reason":    "my proposal was mocked",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 9, result.(*UpdateEmotionResult).Intensity, "intensity moves a few points at a time")
+
+		emotion, intensity, cause := world.Emotion("agent0")
+		assert.Equal(t, "angry", emotion)
+		assert.Equal(t, 9, intensity)
+		assert.Equal(t, "my proposal was mocked", cause)
+	})
+
+	t.Run("rejects unknown emotions", func(t *testing.T) {
+		world := newTestWorld(1)
+		_, err := NewUpdateEmotionTool(world).Handler(agentContext("agent0"), map[string]interface{}{
+			"emotion":   "smug",
+			"intensity": float64(3),
+		})
+		assert.ErrorContains(t, err, "unknown emotion")
+	})
+}
+
+func TestQuorum(t *testing.T) {
+	participants := []string{"agent0", "agent1", "agent2", "agent3"}
+
+	evaluate := func(quorum float64, votes map[string]string) *Proposal {
+		goal := NewInteractiveGoal("dinner", "Pick a restaurant", "consensus", 1)
+		goal.Quorum = quorum
+		proposalID := goal.AddProposal("agent0", "Go to the diner", 1)
+		for agentName, choice := range votes {
+			require.NoError(t, goal.Vote(proposalID, agentName, choice, 1))
+		}
+		proposal := goal.Proposals[proposalID]
+		goal.EvaluateProposal(proposal, participants, 1)
+		return proposal
+	}
+
+	t.Run("accepts once enough vote yes", func(t *testing.T) {
+		proposal := evaluate(0.75, map[string]string{"agent0": "yes", "agent1": "yes"})
+		assert.Equal(t, ProposalPending, proposal.Status)
+
+		proposal = evaluate(0.75, map[string]string{"agent0": "yes", "agent1": "yes", "agent2": "yes", "agent3": "no"})
+		assert.Equal(t, ProposalAccepted, proposal.Status)
+		assert.Equal(t, 0.75, proposal.Support(len(participants)))
+	})
+
+	t.Run("rejects once the quorum is out of reach", func(t *testing.T) {
+		assert.Equal(t, ProposalPending, evaluate(0.75, map[string]string{"agent1": "no"}).Status)
+		assert.Equal(t, ProposalRejected, evaluate(0.75, map[string]string{"agent1": "no", "agent2": "no"}).Status)
+	})
+
+	t.Run("a full quorum is unanimous", func(t *testing.T) {
+		votes := map[string]string{"agent0": "yes", "agent1": "yes", "agent2": "yes"}
+		assert.Equal(t, ProposalPending, evaluate(1, votes).Status)
+	})
+}
+
+func TestIndividualGoal(t *testing.T) {
+	newIndividualWorld := func() *WorldState {
+		world := newTestWorld(3)
+		goal := NewInteractiveGoal("confession", "Get a confession", "individual", 1)
+		goal.Assigned = []string{"agent0", "agent1"}
+		world.AddGoal(goal)
+		return world
+	}
+
+	complete := func(world *WorldState, agentName string) (interface{}, error) {
+		return NewCompleteGoalTool(world).Handler(agentContext(agentName), map[string]interface{}{
+			"goal_name": "confession",
+			"summary":   "Sam admitted it over coffee.",
+		})
+	}
+
+	t.Run("tracks completion per agent", func(t *testing.T) {
+		world := newIndividualWorld()
+
+		result, err := complete(world, "agent0")
+		require.NoError(t, err)
+		assert.Equal(t, false, result.(map[string]interface{})["goal_completed"])
+
+		goal := world.Snapshot().Goals["confession"]
+		assert.Equal(t, GoalPending, goal.Status)
+		assert.Equal(t, []string{"agent0"}, goal.CompletedBy())
+		assert.Equal(t, "Sam admitted it over coffee.", goal.Completions["agent0"].Summary)
+
+		_, err = complete(world, "agent0")
+		assert.ErrorContains(t, err, "already completed")
+	})
+
+	t.Run("completes once every assigned agent has", func(t *testing.T) {
+		world := newIndividualWorld()
+
+		_, err := complete(world, "agent0")
+		require.NoError(t, err)
+		result, err := complete(world, "agent1")
+		require.NoError(t, err)
+		assert.Equal(t, true, result.(map[string]interface{})["goal_completed"])
+
+		goal := world.Snapshot().Goals["confession"]
+		assert.Equal(t, GoalCompleted, goal.Status)
+		assert.Equal(t, 1, goal.CompletedAt)
+	})
+
+	t.Run("refuses unassigned agents and proposals", func(t *testing.T) {
+		world := newIndividualWorld()
+
+		_, err := complete(world, "agent2")
+		assert.ErrorContains(t, err, "not your goal")
+
+		_, err = NewProposeSolutionTool(world).Handler(agentContext("agent0"), map[string]interface{}{
+			"goal_name": "confession",
+			"solution":  "Confront Sam",
+			"comment":   "Let's just ask him.",
+		})
+		assert.ErrorContains(t, err, "complete_goal")
+
+		_, err = NewCompleteGoalTool(world).Handler(agentContext("agent0"), map[string]interface{}{
+			"goal_name": "dinner",
+			"summary":   "We picked a place.",
+		})
+		assert.ErrorContains(t, err, "not an individual goal")
+	})
+}
+
+func TestWorldCheckpoint(t *testing.T) {
+	world := newTestWorld(2)
+	world.AddMessage("agent0", "How about the noodle place?", "", MessageTypeDialogue)
+	_, err := NewProposeSolutionTool(world).Handler(agentContext("agent0"), map[string]interface{}{
+		"goal_name": "dinner",
+		"solution":  "Noodles",
+		"comment":   "Noodles?",
+	})
+	require.NoError(t, err)
+	_, err = NewVoteOnProposalTool(world).Handler(agentContext("agent1"), map[string]interface{}{
+		"goal_name":   "dinner",
+		"proposal_id": "proposal_1",
+		"vote":        "yes",
+		"comment":     "Fine by me.",
+	})
+	require.NoError(t, err)
+	_, err = NewAdjustRelationshipTool(world).Handler(agentContext("agent0"), map[string]interface{}{
+		"name":   "agent1",
+		"change": float64(1),
+		"reason": "agreed quickly",
+	})
+	require.NoError(t, err)
+
+	// Round trip through JSON, as the checkpoint file does
+	data, err := json.Marshal(world.Checkpoint())
+	require.NoError(t, err)
+	var checkpoint WorldCheckpoint
+	require.NoError(t, json.Unmarshal(data, &checkpoint))
+
+	t.Run("restores progress onto a fresh world", func(t *testing.T) {
+		restored := newTestWorld(2)
+		require.NoError(t, restored.Restore(checkpoint))
+
+		before, after := world.Snapshot(), restored.Snapshot()
+		assert.Equal(t, before.CurrentTurn, after.CurrentTurn)
+		assert.Equal(t, before.ConversationHistory, after.ConversationHistory)
+		assert.Equal(t, before.Commitments, after.Commitments)
+		assert.Equal(t, before.Goals["dinner"].Status, after.Goals["dinner"].Status)
+		assert.Equal(t, before.Goals["dinner"].Proposals, after.Goals["dinner"].Proposals)
+		assert.Equal(t, world.RelationshipList(), restored.RelationshipList())
+		assert.True(t, restored.RelationshipList()[0].Changed)
+	})
+
+	t.Run("rejects goals the scenario no longer has", func(t *testing.T) {
+		restored := NewWorldState("cafe", "quiet")
+		restored.AddAgent("agent0", "table")
+		restored.AddAgent("agent1", "table")
+		assert.ErrorContains(t, restored.Restore(checkpoint), "goal dinner is not in the scenario")
+	})
+}
+
+func TestSearchOptions(t *testing.T) {
+	results := []memory.Memory{{ID: "a", Score: 0.9}, {ID: "b", Score: 0.4}, {ID: "c", Score: 0.1}}
+
+	assert.Equal(t, 5, SearchOptions{}.topK(5))
+	assert.Equal(t, 8, SearchOptions{TopK: 8}.topK(5))
+	assert.Len(t, SearchOptions{}.relevant(append([]memory.Memory(nil), results...)), 3)
+
+	minRelevance := 0.4
+	kept := SearchOptions{MinRelevance: &minRelevance}.relevant(append([]memory.Memory(nil), results...))
+	require.Len(t, kept, 2)
+	assert.Equal(t, "a", kept[0].ID)
+	assert.Equal(t, "b", kept[1].ID)
+}
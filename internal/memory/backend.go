@@ -0,0 +1,106 @@
+package memory
+
+import "context"
+
+// Backend holds a store's memories and the embeddings it has computed.
+// The default backend keeps them for a single run; a FileBackend keeps them on
+// disk, so they survive between runs and can be shared by simulations.
+type Backend interface {
+	// Add stores a memory, unless one with the same ID is already stored.
+	Add(mem Memory) error
+	// Get returns the memory with the given ID.
+	Get(id string) (Memory, bool)
+	// Memories returns the stored memories in the order they were added.
+	// Callers must not modify the returned slice.
+	Memories() []Memory
+	// Replace sets the memories searched from now on, as when resuming a run.
+	Replace(memories []Memory) error
+	// Embedding returns the cached embedding of a text, if it has one.
+	Embedding(text string) ([]float32, bool)
+	// AddEmbedding caches the embedding of a text.
+	AddEmbedding(text string, embedding []float32) error
+	// Close releases the backend's resources.
+	Close() error
+}
+
+// Searcher is a Backend that searches memories itself, such as an external
+// vector database holding more memories than are worth loading. The store
+// scores the candidates it returns, so they need only be roughly ranked.
+type Searcher interface {
+	// Search returns up to limit memories nearest the query that match the
+	// filter's agent, type, category and about, and were recorded by run or
+	// by no run in particular.
+	Search(ctx context.Context, query []float32, filter Filter, run string, limit int) ([]Memory, error)
+}
+
+// memoryBackend keeps memories and embeddings in memory for a single run.
+type memoryBackend struct {
+	memories   []Memory
+	index      map[string]int // Position in memories by ID
+	embeddings map[string][]float32
+}
+
+// newMemoryBackend creates an empty in-memory backend.
+func newMemoryBackend() *memoryBackend {
+	return &memoryBackend{
+		memories:   make([]Memory, 0),
+		index:      make(map[string]int),
+		embeddings: make(map[string][]float32),
+	}
+}
+
+// Add implements Backend.
+func (b *memoryBackend) Add(mem Memory) error {
+	b.add(mem)
+	return nil
+}
+
+// add stores a memory and reports whether it was new.
+func (b *memoryBackend) add(mem Memory) bool {
+	if _, ok := b.index[mem.ID]; ok {
+		return false
+	}
+	b.index[mem.ID] = len(b.memories)
+	b.memories = append(b.memories, mem)
+	return true
+}
+
+// Get implements Backend.
+func (b *memoryBackend) Get(id string) (Memory, bool) {
+	if i, ok := b.index[id]; ok {
+		return b.memories[i], true
+	}
+	return Memory{}, false
+}
+
+// Memories implements Backend.
+func (b *memoryBackend) Memories() []Memory {
+	return b.memories
+}
+
+// Replace implements Backend.
+func (b *memoryBackend) Replace(memories []Memory) error {
+	b.memories = make([]Memory, 0, len(memories))
+	b.index = make(map[string]int, len(memories))
+	for _, mem := range memories {
+		b.add(mem)
+	}
+	return nil
+}
+
+// Embedding implements Backend.
+func (b *memoryBackend) Embedding(text string) ([]float32, bool) {
+	embedding, ok := b.embeddings[text]
+	return embedding, ok
+}
+
+// AddEmbedding implements Backend.
+func (b *memoryBackend) AddEmbedding(text string, embedding []float32) error {
+	b.embeddings[text] = embedding
+	return nil
+}
+
+// Close implements Backend.
+func (b *memoryBackend) Close() error {
+	return nil
+}
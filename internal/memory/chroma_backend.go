@@ -0,0 +1,203 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+
+	"github.com/poiesic/wonda/internal/config"
+)
+
+// chromaSpaces maps metrics to Chroma's HNSW distance spaces.
+var chromaSpaces = map[Metric]string{
+	MetricCosine:    "cosine",
+	MetricDot:       "ip",
+	MetricEuclidean: "l2",
+}
+
+// chromaModelKey is the collection metadata recording the embedding model.
+const chromaModelKey = "wonda_model"
+
+// ChromaBackend keeps memories in a Chroma collection, through its v2 REST API.
+type ChromaBackend struct {
+	remoteBackend
+	collectionPath string // API path of the collection
+}
+
+// openChromaBackend opens a Chroma collection, creating it if it doesn't
+// exist. An existing collection must hold embeddings from the same model.
+func openChromaBackend(store *config.VectorStore, collection, model string, metric Metric) (*ChromaBackend, error) {
+	headers := map[string]string{}
+	if key := store.Key(); key != "" {
+		headers["Authorization"] = "Bearer " + key
+	}
+	tenant, database := store.Tenant, store.Database
+	if tenant == "" {
+		tenant = "default_tenant"
+	}
+	if database == "" {
+		database = "default_database"
+	}
+	b := &ChromaBackend{remoteBackend: newRemoteBackend(store, headers)}
+
+	collectionsPath := fmt.Sprintf("/api/v2/tenants/%s/databases/%s/collections", url.PathEscape(tenant), url.PathEscape(database))
+	request := map[string]interface{}{
+		"name":          collection,
+		"get_or_create": true,
+		"metadata":      map[string]string{"hnsw:space": chromaSpaces[metric], chromaModelKey: model},
+	}
+	var resp struct {
+		ID       string                 `json:"id"`
+		Metadata map[string]interface{} `json:"metadata"`
+	}
+	if err := b.call(context.Background(), "POST", collectionsPath, request, &resp); err != nil {
+		return nil, fmt.Errorf("failed to open collection %s: %w", collection, err)
+	}
+	if stored, ok := resp.Metadata[chromaModelKey]; ok && stored != model {
+		return nil, fmt.Errorf("vector store '%s': collection %s holds embeddings from model %v, not %s", store.Name, collection, stored, model)
+	}
+	b.collectionPath = collectionsPath + "/" + url.PathEscape(resp.ID)
+	return b, nil
+}
+
+// upsert writes memories to the collection. Every memory gets a run entry,
+// empty for memories from no run in particular, since Chroma can only filter
+// on entries that exist.
+func (b *ChromaBackend) upsert(memories []Memory) error {
+	if len(memories) == 0 {
+		return nil
+	}
+	ids := make([]string, len(memories))
+	embeddings := make([][]float32, len(memories))
+	documents := make([]string, len(memories))
+	metadatas := make([]map[string]string, len(memories))
+	for i, mem := range memories {
+		ids[i] = mem.ID
+		embeddings[i] = mem.Embedding
+		documents[i] = mem.Content
+		metadata := make(map[string]string, len(mem.Metadata)+1)
+		for key, value := range mem.Metadata {
+			metadata[key] = value
+		}
+		metadata["run"] = mem.Metadata["run"]
+		metadatas[i] = metadata
+	}
+	request := map[string]interface{}{
+		"ids":        ids,
+		"embeddings": embeddings,
+		"documents":  documents,
+		"metadatas":  metadatas,
+	}
+	return b.call(context.Background(), "POST", b.collectionPath+"/upsert", request, nil)
+}
+
+// chromaMemory builds a memory from one result of a get or query.
+func chromaMemory(id string, document *string, metadata map[string]interface{}, embedding []float32) Memory {
+	mem := Memory{ID: id, Embedding: embedding, Metadata: make(map[string]string, len(metadata))}
+	if document != nil {
+		mem.Content = *document
+	}
+	for key, value := range metadata {
+		mem.Metadata[key] = fmt.Sprint(value)
+	}
+	if mem.Metadata["run"] == "" {
+		delete(mem.Metadata, "run")
+	}
+	return mem
+}
+
+// Add implements Backend.
+func (b *ChromaBackend) Add(mem Memory) error {
+	if !b.memoryBackend.add(mem) {
+		return nil
+	}
+	return b.upsert([]Memory{mem})
+}
+
+// Replace implements Backend. The collection keeps every memory it holds;
+// the memories are written again in case it lacks any.
+func (b *ChromaBackend) Replace(memories []Memory) error {
+	b.memoryBackend.Replace(memories)
+	return b.upsert(memories)
+}
+
+// Get implements Backend, looking in the collection for memories other runs added.
+func (b *ChromaBackend) Get(id string) (Memory, bool) {
+	if mem, ok := b.memoryBackend.Get(id); ok {
+		return mem, true
+	}
+	request := map[string]interface{}{
+		"ids":     []string{id},
+		"include": []string{"documents", "metadatas", "embeddings"},
+	}
+	var resp struct {
+		IDs        []string                 `json:"ids"`
+		Documents  []*string                `json:"documents"`
+		Metadatas  []map[string]interface{} `json:"metadatas"`
+		Embeddings [][]float32              `json:"embeddings"`
+	}
+	if err := b.call(context.Background(), "POST", b.collectionPath+"/get", request, &resp); err != nil || len(resp.IDs) == 0 {
+		return Memory{}, false
+	}
+	return chromaMemory(resp.IDs[0], at(resp.Documents, 0), at(resp.Metadatas, 0), at(resp.Embeddings, 0)), true
+}
+
+// Search implements Searcher.
+func (b *ChromaBackend) Search(ctx context.Context, query []float32, filter Filter, run string, limit int) ([]Memory, error) {
+	request := map[string]interface{}{
+		"query_embeddings": [][]float32{query},
+		"n_results":        limit,
+		"include":          []string{"documents", "metadatas", "embeddings"},
+	}
+
+	exact := exactFilter(filter)
+	keys := make([]string, 0, len(exact))
+	for key := range exact {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var clauses []interface{}
+	for _, key := range keys {
+		clauses = append(clauses, map[string]interface{}{key: map[string]string{"$eq": exact[key]}})
+	}
+	if run != "" {
+		clauses = append(clauses, map[string]interface{}{"run": map[string][]string{"$in": {run, ""}}})
+	}
+	switch len(clauses) {
+	case 0:
+	case 1:
+		request["where"] = clauses[0]
+	default:
+		request["where"] = map[string]interface{}{"$and": clauses}
+	}
+
+	var resp struct {
+		IDs        [][]string                 `json:"ids"`
+		Documents  [][]*string                `json:"documents"`
+		Metadatas  [][]map[string]interface{} `json:"metadatas"`
+		Embeddings [][][]float32              `json:"embeddings"`
+	}
+	if err := b.call(ctx, "POST", b.collectionPath+"/query", request, &resp); err != nil {
+		return nil, err
+	}
+	if len(resp.IDs) == 0 {
+		return nil, nil
+	}
+	documents, metadatas, embeddings := at(resp.Documents, 0), at(resp.Metadatas, 0), at(resp.Embeddings, 0)
+	memories := make([]Memory, len(resp.IDs[0]))
+	for i, id := range resp.IDs[0] {
+		memories[i] = chromaMemory(id, at(documents, i), at(metadatas, i), at(embeddings, i))
+	}
+	return memories, nil
+}
+
+// at returns the i-th element of a result list, or the zero value if the
+// list is short, as lists left out of a response are.
+func at[T any](list []T, i int) T {
+	var zero T
+	if i >= len(list) {
+		return zero
+	}
+	return list[i]
+}
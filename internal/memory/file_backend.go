@@ -0,0 +1,167 @@
+package memory
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// fileHeader is the first line of a store file. It records the settings the
+// stored embeddings were computed under, since they can't be mixed.
+type fileHeader struct {
+	Model     string `json:"model"`
+	Metric    Metric `json:"metric"`
+	Normalize bool   `json:"normalize"`
+}
+
+// fileRecord is a line after the header: a memory, or a cached embedding.
+type fileRecord struct {
+	Memory    *Memory   `json:"memory,omitempty"`
+	Text      string    `json:"text,omitempty"`
+	Embedding []float32 `json:"embedding,omitempty"`
+}
+
+// FileBackend keeps memories and embeddings in a JSONL file, so they survive
+// between runs. Everything in the file is loaded when it is opened, and what is
+// added later is appended, so simulations using the same file share memories
+// and don't embed the same text twice.
+type FileBackend struct {
+	*memoryBackend
+	path string
+	file *os.File
+}
+
+// OpenFileBackend opens the store file at path, creating it if needed, for
+// embeddings computed by model under opts. An existing file must have been
+// created with the same model and similarity settings.
+func OpenFileBackend(path, model string, opts StoreOptions) (*FileBackend, error) {
+	header := fileHeader{Model: model, Metric: opts.Metric, Normalize: opts.Normalize}
+	if header.Metric == "" {
+		header.Metric = MetricCosine
+	}
+
+	backend := &FileBackend{memoryBackend: newMemoryBackend(), path: path}
+	if err := backend.load(header); err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create memory store directory: %w", err)
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open memory store: %w", err)
+	}
+	backend.file = file
+
+	// A new file starts with its header
+	if info, err := file.Stat(); err == nil && info.Size() == 0 {
+		if err := backend.append(header); err != nil {
+			file.Close()
+			return nil, err
+		}
+	}
+	return backend, nil
+}
+
+// load reads an existing store file, checking its header against the one
+// expected. A missing file is an empty store.
+func (b *FileBackend) load(expected fileHeader) error {
+	file, err := os.Open(b.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to open memory store: %w", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	if !scanner.Scan() {
+		return scanner.Err() // Empty file
+	}
+	var header fileHeader
+	if err := json.Unmarshal(scanner.Bytes(), &header); err != nil {
+		return fmt.Errorf("invalid memory store %s: %w", b.path, err)
+	}
+	if header != expected {
+		return fmt.Errorf("memory store %s holds embeddings from model %s (metric=%s, normalize=%t), not %s (metric=%s, normalize=%t)",
+			b.path, header.Model, header.Metric, header.Normalize, expected.Model, expected.Metric, expected.Normalize)
+	}
+
+	for line := 2; scanner.Scan(); line++ {
+		var record fileRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return fmt.Errorf("invalid memory store %s at line %d: %w", b.path, line, err)
+		}
+		if record.Memory != nil {
+			b.memoryBackend.add(*record.Memory)
+		} else if record.Text != "" {
+			b.memoryBackend.AddEmbedding(record.Text, record.Embedding)
+		}
+	}
+	return scanner.Err()
+}
+
+// append writes one line to the store file.
+func (b *FileBackend) append(v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to marshal memory store record: %w", err)
+	}
+	if _, err := b.file.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write memory store: %w", err)
+	}
+	return nil
+}
+
+// Path returns the store file's path.
+func (b *FileBackend) Path() string {
+	return b.path
+}
+
+// Add implements Backend, appending new memories to the file.
+func (b *FileBackend) Add(mem Memory) error {
+	if !b.memoryBackend.add(mem) {
+		return nil
+	}
+	return b.append(fileRecord{Memory: &mem})
+}
+
+// Replace implements Backend. The file keeps every memory it holds for later
+// runs; memories it lacks are appended.
+func (b *FileBackend) Replace(memories []Memory) error {
+	stored := b.memoryBackend.index
+	b.memoryBackend.Replace(memories)
+	for _, mem := range memories {
+		if _, ok := stored[mem.ID]; ok {
+			continue
+		}
+		if err := b.append(fileRecord{Memory: &mem}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AddEmbedding implements Backend, appending new embeddings to the file.
+func (b *FileBackend) AddEmbedding(text string, embedding []float32) error {
+	if _, ok := b.memoryBackend.Embedding(text); ok {
+		return nil
+	}
+	b.memoryBackend.AddEmbedding(text, embedding)
+	return b.append(fileRecord{Text: text, Embedding: embedding})
+}
+
+// Close implements Backend. It is safe to call more than once.
+func (b *FileBackend) Close() error {
+	if b.file == nil {
+		return nil
+	}
+	err := b.file.Close()
+	b.file = nil
+	return err
+}
@@ -0,0 +1,150 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingEmbedder embeds text as its length and counts the calls made.
+type countingEmbedder struct {
+	calls int
+}
+
+func (e *countingEmbedder) Embed(ctx context.Context, text string) ([]float32, error) {
+	e.calls++
+	return []float32{float32(len(text)), 1}, nil
+}
+
+func TestFileBackend(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "memory", "shared.jsonl")
+	opts := DefaultStoreOptions()
+	ctx := context.Background()
+
+	open := func(embedder Embedder, run string) *Store {
+		backend, err := OpenFileBackend(path, "test-model", opts)
+		require.NoError(t, err)
+		runOpts := opts
+		runOpts.Run = run
+		store, err := NewStoreWithBackend(embedder, runOpts, backend)
+		require.NoError(t, err)
+		return store
+	}
+	seed := func(store *Store) string {
+		embedding, err := store.Embed(ctx, "what is my background?")
+		require.NoError(t, err)
+		return store.Add(Memory{
+			Content:   "Grew up by the sea.",
+			Embedding: embedding,
+			Metadata:  map[string]string{"agent": "alice", "type": "character"},
+		})
+	}
+
+	// The first run embeds and stores its memories
+	embedder := &countingEmbedder{}
+	store := open(embedder, "run-1")
+	seedID := seed(store)
+	store.Add(Memory{
+		Content:   "alice said: hello",
+		Embedding: []float32{1, 1},
+		Metadata:  map[string]string{"type": "episodic", "run": "run-1"},
+	})
+	assert.Equal(t, 1, embedder.calls)
+	require.NoError(t, store.Close())
+	require.NoError(t, store.Close(), "closing twice is harmless")
+
+	// A later run reuses the embedding, and seeding again keeps the stored memory
+	embedder = &countingEmbedder{}
+	store = open(embedder, "run-2")
+	defer store.Close()
+	assert.Equal(t, 2, store.Count())
+	assert.Equal(t, seedID, seed(store))
+	assert.Equal(t, 0, embedder.calls)
+	assert.Equal(t, 2, store.Count())
+
+	// Other runs' memories are kept but not searched
+	results := store.Search(ctx, []float32{1, 1}, Filter{}, 10)
+	require.Len(t, results, 1)
+	assert.Equal(t, seedID, results[0].ID)
+
+	// Embeddings from another model can't share the file
+	_, err := OpenFileBackend(path, "other-model", opts)
+	assert.ErrorContains(t, err, "test-model")
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"model":"test-model"`)
+}
+
+func TestStoreConcurrentSeeding(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "shared.jsonl")
+	opts := DefaultStoreOptions()
+	ctx := context.Background()
+
+	backend, err := OpenFileBackend(path, "test-model", opts)
+	require.NoError(t, err)
+	store, err := NewStoreWithBackend(lengthEmbedder{}, opts, backend)
+	require.NoError(t, err)
+
+	// Agents are seeded in parallel, sharing queries and the file
+	var wg sync.WaitGroup
+	for agent := range 8 {
+		wg.Go(func() {
+			for i := range 10 {
+				embedding, err := store.Embed(ctx, fmt.Sprintf("question %d", i))
+				assert.NoError(t, err)
+				store.Add(Memory{
+					Content:   fmt.Sprintf("answer %d", i),
+					Embedding: embedding,
+					Metadata:  map[string]string{"agent": fmt.Sprint(agent)},
+				})
+			}
+		})
+	}
+	wg.Wait()
+	assert.Equal(t, 80, store.Count())
+	require.NoError(t, store.Close())
+
+	// Every memory made it to the file intact
+	backend, err = OpenFileBackend(path, "test-model", opts)
+	require.NoError(t, err)
+	defer backend.Close()
+	assert.Len(t, backend.Memories(), 80)
+}
+
+func TestStoreConcurrentRuns(t *testing.T) {
+	store, err := NewStoreWithOptions(lengthEmbedder{}, DefaultStoreOptions())
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	// Runs are switched while agents remember and search
+	var wg sync.WaitGroup
+	for agent := range 4 {
+		wg.Go(func() {
+			for i := range 10 {
+				run := fmt.Sprintf("run-%d", i%2)
+				store.SetRun(run)
+				store.Add(Memory{
+					Content:   fmt.Sprintf("agent %d memory %d", agent, i),
+					Embedding: []float32{float32(i), 1},
+					Metadata:  map[string]string{"run": run},
+				})
+				store.Search(ctx, []float32{1, 1}, Filter{}, 5)
+				assert.NotEmpty(t, store.Options().Run)
+			}
+		})
+	}
+	wg.Wait()
+	assert.Equal(t, 40, store.Count())
+
+	store.SetRun("run-0")
+	for _, mem := range store.Search(ctx, []float32{1, 1}, Filter{}, 40) {
+		assert.Equal(t, "run-0", mem.Metadata["run"])
+	}
+}
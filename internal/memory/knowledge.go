@@ -0,0 +1,206 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+	"unicode/utf8"
+)
+
+// KnowledgeDir is the name of the ingested document directory in the config directory.
+const KnowledgeDir = "knowledge"
+
+// ScopeScene shares ingested knowledge with every agent in the scene.
+const ScopeScene = "scene"
+
+// knowledgeChunkSize is the maximum length of an ingested chunk in characters.
+const knowledgeChunkSize = 500
+
+// KnowledgeBase holds the documents ingested for a scenario, chunked and
+// embedded ahead of its runs.
+type KnowledgeBase struct {
+	Scenario string           `json:"scenario"`
+	Model    string           `json:"model"` // Embedding model the chunks were embedded with
+	Chunks   []KnowledgeChunk `json:"chunks"`
+}
+
+// KnowledgeChunk is one embedded piece of an ingested document.
+type KnowledgeChunk struct {
+	Source    string    `json:"source"`  // Base name of the document
+	Section   string    `json:"section"` // Nearest Markdown heading, if any
+	Scope     string    `json:"scope"`
+	Text      string    `json:"text"`
+	Embedding []float32 `json:"embedding"`
+}
+
+// KnowledgePath returns the knowledge base path for a scenario file name
+// (without the .toml extension).
+func KnowledgePath(configDir, scenario string) string {
+	return path.Join(configDir, KnowledgeDir, scenario+".json")
+}
+
+// LoadKnowledge reads a scenario's knowledge base.
+// A scenario without ingested documents returns an empty knowledge base.
+func LoadKnowledge(configDir, scenario string) (*KnowledgeBase, error) {
+	kb := &KnowledgeBase{Scenario: scenario}
+	data, err := os.ReadFile(KnowledgePath(configDir, scenario))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return kb, nil
+		}
+		return nil, fmt.Errorf("failed to read knowledge for scenario %s: %w", scenario, err)
+	}
+	if err := json.Unmarshal(data, kb); err != nil {
+		return nil, fmt.Errorf("failed to parse knowledge for scenario %s: %w", scenario, err)
+	}
+	return kb, nil
+}
+
+// Save writes the knowledge base to the config directory.
+func (kb *KnowledgeBase) Save(configDir string) error {
+	if err := os.MkdirAll(path.Join(configDir, KnowledgeDir), 0744); err != nil {
+		return fmt.Errorf("failed to create knowledge directory: %w", err)
+	}
+
+	data, err := json.Marshal(kb)
+	if err != nil {
+		return fmt.Errorf("failed to marshal knowledge: %w", err)
+	}
+	if err := os.WriteFile(KnowledgePath(configDir, kb.Scenario), data, 0644); err != nil {
+		return fmt.Errorf("failed to write knowledge: %w", err)
+	}
+	return nil
+}
+
+// Ingest chunks and embeds a document, replacing anything previously
+// ingested from a document with the same name. It returns the number of chunks.
+func (kb *KnowledgeBase) Ingest(ctx context.Context, embedder Embedder, model, source, text, scope string) (int, error) {
+	if scope != ScopeScene {
+		return 0, fmt.Errorf("unsupported scope '%s': only '%s' is supported", scope, ScopeScene)
+	}
+	if kb.Model != "" && kb.Model != model && len(kb.Chunks) > 0 {
+		return 0, fmt.Errorf("knowledge for scenario %s was embedded with %s, not %s; remove %s to re-ingest",
+			kb.Scenario, kb.Model, model, kb.Scenario+".json")
+	}
+	kb.Model = model
+
+	var chunks []KnowledgeChunk
+	for _, section := range splitSections(text) {
+		for _, piece := range chunkSection(section.text) {
+			embedding, err := embedder.Embed(ctx, piece)
+			if err != nil {
+				return 0, fmt.Errorf("failed to embed %s: %w", source, err)
+			}
+			chunks = append(chunks, KnowledgeChunk{
+				Source:    source,
+				Section:   section.heading,
+				Scope:     scope,
+				Text:      piece,
+				Embedding: embedding,
+			})
+		}
+	}
+	if len(chunks) == 0 {
+		return 0, fmt.Errorf("%s has no text to ingest", source)
+	}
+
+	kept := kb.Chunks[:0]
+	for _, chunk := range kb.Chunks {
+		if chunk.Source != source {
+			kept = append(kept, chunk)
+		}
+	}
+	kb.Chunks = append(kept, chunks...)
+	return len(chunks), nil
+}
+
+// SeedKnowledge adds a scenario's ingested documents to the store as shared
+// scene knowledge. Chunks embedded with a different model than the store's are
+// embedded again.
+func SeedKnowledge(ctx context.Context, store *Store, kb *KnowledgeBase, model string) error {
+	for _, chunk := range kb.Chunks {
+		embedding := chunk.Embedding
+		if kb.Model != model {
+			var err error
+			if embedding, err = store.Embed(ctx, chunk.Text); err != nil {
+				return fmt.Errorf("failed to embed knowledge from %s: %w", chunk.Source, err)
+			}
+		}
+
+		content := chunk.Text
+		if chunk.Section != "" {
+			content = fmt.Sprintf("%s: %s", chunk.Section, chunk.Text)
+		}
+		store.Add(Memory{
+			Content:   content,
+			Embedding: embedding,
+			Metadata: map[string]string{
+				"type":     "knowledge",
+				"category": chunk.Scope,
+				"source":   chunk.Source,
+			},
+		})
+	}
+	return nil
+}
+
+// chunkSection splits a section into chunks of at most knowledgeChunkSize
+// characters, breaking sentences too long for one chunk between words.
+func chunkSection(text string) []string {
+	var chunks []string
+	for _, chunk := range ChunkText(text, knowledgeChunkSize) {
+		for len(chunk) > knowledgeChunkSize {
+			cut := strings.LastIndex(chunk[:knowledgeChunkSize+1], " ")
+			if cut <= 0 {
+				// No space to break at, so break between runes
+				cut = knowledgeChunkSize
+				for !utf8.RuneStart(chunk[cut]) {
+					cut--
+				}
+			}
+			chunks = append(chunks, chunk[:cut])
+			chunk = strings.TrimSpace(chunk[cut:])
+		}
+		chunks = append(chunks, chunk)
+	}
+	return chunks
+}
+
+// documentSection is a run of text under one Markdown heading.
+type documentSection struct {
+	heading string
+	text    string
+}
+
+// splitSections splits a document at Markdown headings and blank lines, so
+// chunks don't straddle sections. Plain text is a single untitled section.
+func splitSections(text string) []documentSection {
+	var sections []documentSection
+	heading := ""
+	var paragraph []string
+
+	flush := func() {
+		if len(paragraph) > 0 {
+			sections = append(sections, documentSection{heading: heading, text: strings.Join(paragraph, " ")})
+			paragraph = nil
+		}
+	}
+
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(line, "#"):
+			flush()
+			heading = strings.TrimSpace(strings.TrimLeft(line, "#"))
+		case line == "":
+			flush()
+		default:
+			paragraph = append(paragraph, line)
+		}
+	}
+	flush()
+	return sections
+}
@@ -0,0 +1,203 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// failingEmbedder fails every embedding.
+type failingEmbedder struct{}
+
+func (failingEmbedder) Embed(ctx context.Context, text string) ([]float32, error) {
+	return nil, errors.New("model unavailable")
+}
+
+func TestSplitSections(t *testing.T) {
+	text := `Opening line
+continues here.
+
+# Suspects
+The butler was home.
+
+The maid was out.
+## Motive
+  Money.  `
+
+	assert.Equal(t, []documentSection{
+		{heading: "", text: "Opening line continues here."},
+		{heading: "Suspects", text: "The butler was home."},
+		{heading: "Suspects", text: "The maid was out."},
+		{heading: "Motive", text: "Money."},
+	}, splitSections(text))
+
+	assert.Empty(t, splitSections(""))
+	assert.Empty(t, splitSections("\n  \n# Heading only\n"))
+}
+
+func TestKnowledgeIngest(t *testing.T) {
+	ctx := context.Background()
+	ingest := func(t *testing.T, kb *KnowledgeBase, source, text string) []KnowledgeChunk {
+		count, err := kb.Ingest(ctx, lengthEmbedder{}, "test-model", source, text, ScopeScene)
+		require.NoError(t, err)
+		var chunks []KnowledgeChunk
+		for _, chunk := range kb.Chunks {
+			if chunk.Source == source {
+				chunks = append(chunks, chunk)
+			}
+		}
+		assert.Len(t, chunks, count)
+		return chunks
+	}
+
+	t.Run("chunks at headings and blank lines", func(t *testing.T) {
+		kb := &KnowledgeBase{Scenario: "heist"}
+		chunks := ingest(t, kb, "briefing.md", "# Target\nThe vault.\n\nIt opens at nine.\n# Crew\nFour of us.")
+
+		require.Len(t, chunks, 3)
+		assert.Equal(t, KnowledgeChunk{Source: "briefing.md", Section: "Target", Scope: ScopeScene, Text: "The vault.", Embedding: []float32{10, 1}}, chunks[0])
+		assert.Equal(t, "It opens at nine.", chunks[1].Text)
+		assert.Equal(t, "Target", chunks[1].Section)
+		assert.Equal(t, "Four of us.", chunks[2].Text)
+		assert.Equal(t, "Crew", chunks[2].Section)
+		assert.Equal(t, "test-model", kb.Model)
+	})
+
+	t.Run("splits long sections at sentences", func(t *testing.T) {
+		sentence := "The guard changes shifts at the north gate every hour. "
+		kb := &KnowledgeBase{Scenario: "heist"}
+		chunks := ingest(t, kb, "notes.txt", strings.Repeat(sentence, 30))
+
+		require.Len(t, chunks, 4)
+		var texts []string
+		for _, chunk := range chunks {
+			assert.LessOrEqual(t, len(chunk.Text), knowledgeChunkSize)
+			assert.True(t, strings.HasSuffix(chunk.Text, "every hour."), "chunks end at a sentence")
+			texts = append(texts, chunk.Text)
+		}
+		assert.Equal(t, strings.TrimSpace(strings.Repeat(sentence, 30)), strings.Join(texts, " "), "no text is lost")
+	})
+
+	t.Run("splits oversized sentences between words", func(t *testing.T) {
+		sentence := strings.TrimSpace(strings.Repeat("and then the lights went out ", 40)) + "."
+		kb := &KnowledgeBase{Scenario: "heist"}
+		chunks := ingest(t, kb, "statement.txt", sentence)
+
+		require.Len(t, chunks, 3)
+		var texts []string
+		for _, chunk := range chunks {
+			assert.LessOrEqual(t, len(chunk.Text), knowledgeChunkSize)
+			assert.False(t, strings.HasPrefix(chunk.Text, " ") || strings.HasSuffix(chunk.Text, " "))
+			texts = append(texts, chunk.Text)
+		}
+		assert.Equal(t, sentence, strings.Join(texts, " "))
+	})
+
+	t.Run("splits oversized words between runes", func(t *testing.T) {
+		word := strings.Repeat("é", 400)
+		kb := &KnowledgeBase{Scenario: "heist"}
+		chunks := ingest(t, kb, "noise.txt", word)
+
+		require.Len(t, chunks, 2)
+		assert.Equal(t, strings.Repeat("é", 250), chunks[0].Text)
+		assert.Equal(t, strings.Repeat("é", 150), chunks[1].Text)
+	})
+
+	t.Run("rejects empty documents", func(t *testing.T) {
+		kb := &KnowledgeBase{Scenario: "heist"}
+		ingest(t, kb, "briefing.md", "The vault.")
+
+		for _, text := range []string{"", "  \n\n", "# Heading only\n\n## Another\n"} {
+			_, err := kb.Ingest(ctx, lengthEmbedder{}, "test-model", "briefing.md", text, ScopeScene)
+			assert.ErrorContains(t, err, "briefing.md has no text to ingest")
+		}
+		require.Len(t, kb.Chunks, 1, "a failed ingest keeps the earlier chunks")
+		assert.Equal(t, "The vault.", kb.Chunks[0].Text)
+	})
+
+	t.Run("replaces chunks from the same document", func(t *testing.T) {
+		kb := &KnowledgeBase{Scenario: "heist"}
+		ingest(t, kb, "briefing.md", "The vault.\n\nThe guard.")
+		ingest(t, kb, "map.md", "North gate.")
+		ingest(t, kb, "briefing.md", "The new vault.")
+
+		var texts []string
+		for _, chunk := range kb.Chunks {
+			texts = append(texts, chunk.Text)
+		}
+		assert.Equal(t, []string{"North gate.", "The new vault."}, texts)
+	})
+
+	t.Run("returns errors", func(t *testing.T) {
+		kb := &KnowledgeBase{Scenario: "heist"}
+		_, err := kb.Ingest(ctx, lengthEmbedder{}, "test-model", "briefing.md", "The vault.", "agent")
+		assert.ErrorContains(t, err, "unsupported scope 'agent'")
+
+		_, err = kb.Ingest(ctx, failingEmbedder{}, "test-model", "briefing.md", "The vault.", ScopeScene)
+		assert.ErrorContains(t, err, "failed to embed briefing.md: model unavailable")
+
+		ingest(t, kb, "briefing.md", "The vault.")
+		_, err = kb.Ingest(ctx, lengthEmbedder{}, "other-model", "map.md", "North gate.", ScopeScene)
+		assert.ErrorContains(t, err, "was embedded with test-model, not other-model")
+	})
+}
+
+func TestKnowledgeSaveAndLoad(t *testing.T) {
+	configDir := t.TempDir()
+
+	kb, err := LoadKnowledge(configDir, "heist")
+	require.NoError(t, err)
+	assert.Equal(t, &KnowledgeBase{Scenario: "heist"}, kb, "scenarios without documents have no knowledge")
+
+	_, err = kb.Ingest(context.Background(), lengthEmbedder{}, "test-model", "briefing.md", "# Target\nThe vault.", ScopeScene)
+	require.NoError(t, err)
+	require.NoError(t, kb.Save(configDir))
+
+	loaded, err := LoadKnowledge(configDir, "heist")
+	require.NoError(t, err)
+	assert.Equal(t, kb, loaded)
+}
+
+func TestSeedKnowledge(t *testing.T) {
+	ctx := context.Background()
+	kb := &KnowledgeBase{Scenario: "heist", Model: "test-model", Chunks: []KnowledgeChunk{
+		{Source: "briefing.md", Section: "Target", Scope: ScopeScene, Text: "The vault.", Embedding: []float32{7, 7}},
+		{Source: "notes.txt", Scope: ScopeScene, Text: "Four of us.", Embedding: []float32{7, 7}},
+	}}
+
+	t.Run("seeds chunks as scene knowledge", func(t *testing.T) {
+		store := NewStore(lengthEmbedder{})
+		require.NoError(t, SeedKnowledge(ctx, store, kb, "test-model"))
+
+		memories := store.Search(ctx, []float32{1, 1}, Filter{Type: "knowledge"}, 10)
+		require.Len(t, memories, 2)
+		contents := map[string]string{}
+		for _, mem := range memories {
+			contents[mem.Metadata["source"]] = mem.Content
+			assert.Equal(t, ScopeScene, mem.Metadata["category"])
+			assert.Equal(t, []float32{7, 7}, mem.Embedding, "chunks keep their embedding")
+		}
+		assert.Equal(t, map[string]string{"briefing.md": "Target: The vault.", "notes.txt": "Four of us."}, contents)
+	})
+
+	t.Run("embeds chunks again for a different model", func(t *testing.T) {
+		store := NewStore(lengthEmbedder{})
+		require.NoError(t, SeedKnowledge(ctx, store, kb, "other-model"))
+
+		memories := store.Search(ctx, []float32{1, 1}, Filter{Type: "knowledge"}, 10)
+		require.Len(t, memories, 2)
+		for _, mem := range memories {
+			assert.Equal(t, []float32{float32(len(strings.TrimPrefix(mem.Content, "Target: "))), 1}, mem.Embedding)
+		}
+	})
+
+	t.Run("returns embedding errors", func(t *testing.T) {
+		store := NewStore(failingEmbedder{})
+		err := SeedKnowledge(ctx, store, kb, "other-model")
+		assert.ErrorContains(t, err, "failed to embed knowledge from briefing.md")
+	})
+}
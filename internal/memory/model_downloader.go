@@ -36,8 +36,13 @@ func NewModelDownloader(cacheDir string, modelURL string) *ModelDownloader {
 	if modelURL == "" {
 		modelURL = DefaultModelURL
 	}
+	return NewModelDownloaderFor(cacheDir, ModelDirName, modelURL)
+}
 
-	modelDir := filepath.Join(cacheDir, ModelDirName)
+// NewModelDownloaderFor creates a downloader for a model other than the default.
+// modelName is the directory the model's archive extracts to in the cache.
+func NewModelDownloaderFor(cacheDir, modelName, modelURL string) *ModelDownloader {
+	modelDir := filepath.Join(cacheDir, modelName)
 
 	return &ModelDownloader{
 		modelURL:     modelURL,
@@ -41,6 +41,25 @@ func NewONNXEmbedderWithDownload(cacheDir, modelURL string) (*ONNXEmbedder, erro
 	return NewONNXEmbedder(modelDir)
 }
 
+// NewONNXEmbedderForModel creates an ONNX embedder for a configured model,
+// downloading it if needed. modelName is the model's directory in the cache and
+// dimensions is the size of its vectors. The model must take input_ids and
+// attention_mask and produce last_hidden_state, like gtr-t5-base.
+func NewONNXEmbedderForModel(cacheDir, modelName, modelURL string, dimensions int) (*ONNXEmbedder, error) {
+	downloader := NewModelDownloaderFor(cacheDir, modelName, modelURL)
+	modelDir, err := downloader.EnsureModelAvailable()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get model: %w", err)
+	}
+
+	embedder, err := NewONNXEmbedder(modelDir)
+	if err != nil {
+		return nil, err
+	}
+	embedder.dimensions = dimensions
+	return embedder, nil
+}
+
 // NewONNXEmbedder creates a new ONNX embedder.
 // modelDir should point to the directory containing model.onnx and tokenizer.json files.
 func NewONNXEmbedder(modelDir string) (*ONNXEmbedder, error) {
@@ -0,0 +1,186 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+
+	"github.com/poiesic/wonda/internal/config"
+)
+
+// qdrantDistances maps metrics to Qdrant's vector distances.
+var qdrantDistances = map[Metric]string{
+	MetricCosine:    "Cosine",
+	MetricDot:       "Dot",
+	MetricEuclidean: "Euclid",
+}
+
+// qdrantPoint is a memory as a Qdrant point: its content and metadata are the payload.
+type qdrantPoint struct {
+	ID      string        `json:"id"`
+	Vector  []float32     `json:"vector"`
+	Payload qdrantPayload `json:"payload"`
+}
+
+type qdrantPayload struct {
+	Content  string            `json:"content"`
+	Metadata map[string]string `json:"metadata"`
+}
+
+// memory converts the point back to a memory.
+func (p qdrantPoint) memory() Memory {
+	return Memory{ID: p.ID, Content: p.Payload.Content, Embedding: p.Vector, Metadata: p.Payload.Metadata}
+}
+
+// QdrantBackend keeps memories in a Qdrant collection, through its REST API.
+type QdrantBackend struct {
+	remoteBackend
+	collection string // Collection path segment, escaped
+}
+
+// openQdrantBackend opens a Qdrant collection, creating it if it doesn't
+// exist. An existing collection must hold vectors of the same size and distance.
+func openQdrantBackend(store *config.VectorStore, collection string, dimensions int, metric Metric) (*QdrantBackend, error) {
+	headers := map[string]string{}
+	if key := store.Key(); key != "" {
+		headers["api-key"] = key
+	}
+	b := &QdrantBackend{
+		remoteBackend: newRemoteBackend(store, headers),
+		collection:    url.PathEscape(collection),
+	}
+
+	ctx := context.Background()
+	var info struct {
+		Result struct {
+			Config struct {
+				Params struct {
+					Vectors struct {
+						Size     int    `json:"size"`
+						Distance string `json:"distance"`
+					} `json:"vectors"`
+				} `json:"params"`
+			} `json:"config"`
+		} `json:"result"`
+	}
+	err := b.call(ctx, "GET", "/collections/"+b.collection, nil, &info)
+	switch {
+	case errors.Is(err, errNotFound):
+		create := map[string]interface{}{
+			"vectors": map[string]interface{}{"size": dimensions, "distance": qdrantDistances[metric]},
+		}
+		if err := b.call(ctx, "PUT", "/collections/"+b.collection, create, nil); err != nil {
+			return nil, fmt.Errorf("failed to create collection %s: %w", collection, err)
+		}
+	case err != nil:
+		return nil, err
+	default:
+		vectors := info.Result.Config.Params.Vectors
+		if vectors.Size != dimensions || vectors.Distance != qdrantDistances[metric] {
+			return nil, fmt.Errorf("vector store '%s': collection %s holds %d-dimension %s vectors, not %d-dimension %s",
+				store.Name, collection, vectors.Size, vectors.Distance, dimensions, qdrantDistances[metric])
+		}
+	}
+	return b, nil
+}
+
+// upsert writes memories to the collection.
+func (b *QdrantBackend) upsert(memories []Memory) error {
+	if len(memories) == 0 {
+		return nil
+	}
+	points := make([]qdrantPoint, len(memories))
+	for i, mem := range memories {
+		points[i] = qdrantPoint{ID: mem.ID, Vector: mem.Embedding, Payload: qdrantPayload{Content: mem.Content, Metadata: mem.Metadata}}
+	}
+	return b.call(context.Background(), "PUT", "/collections/"+b.collection+"/points?wait=true",
+		map[string]interface{}{"points": points}, nil)
+}
+
+// Add implements Backend.
+func (b *QdrantBackend) Add(mem Memory) error {
+	if !b.memoryBackend.add(mem) {
+		return nil
+	}
+	return b.upsert([]Memory{mem})
+}
+
+// Replace implements Backend. The collection keeps every memory it holds;
+// the memories are written again in case it lacks any.
+func (b *QdrantBackend) Replace(memories []Memory) error {
+	b.memoryBackend.Replace(memories)
+	return b.upsert(memories)
+}
+
+// Get implements Backend, looking in the collection for memories other runs added.
+func (b *QdrantBackend) Get(id string) (Memory, bool) {
+	if mem, ok := b.memoryBackend.Get(id); ok {
+		return mem, true
+	}
+	var resp struct {
+		Result []qdrantPoint `json:"result"`
+	}
+	request := map[string]interface{}{"ids": []string{id}, "with_payload": true, "with_vector": true}
+	if err := b.call(context.Background(), "POST", "/collections/"+b.collection+"/points", request, &resp); err != nil || len(resp.Result) == 0 {
+		return Memory{}, false
+	}
+	return resp.Result[0].memory(), true
+}
+
+// Search implements Searcher.
+func (b *QdrantBackend) Search(ctx context.Context, query []float32, filter Filter, run string, limit int) ([]Memory, error) {
+	request := map[string]interface{}{
+		"vector":       query,
+		"limit":        limit,
+		"with_payload": true,
+		"with_vector":  true,
+	}
+
+	var must []interface{}
+	for key, value := range exactFilter(filter) {
+		must = append(must, map[string]interface{}{"key": "metadata." + key, "match": map[string]string{"value": value}})
+	}
+	qdrantFilter := map[string]interface{}{}
+	if len(must) > 0 {
+		qdrantFilter["must"] = must
+	}
+	if run != "" {
+		qdrantFilter["should"] = []interface{}{
+			map[string]interface{}{"key": "metadata.run", "match": map[string]string{"value": run}},
+			map[string]interface{}{"is_empty": map[string]string{"key": "metadata.run"}},
+		}
+	}
+	if len(qdrantFilter) > 0 {
+		request["filter"] = qdrantFilter
+	}
+
+	var resp struct {
+		Result []qdrantPoint `json:"result"`
+	}
+	if err := b.call(ctx, "POST", "/collections/"+b.collection+"/points/search", request, &resp); err != nil {
+		return nil, err
+	}
+	memories := make([]Memory, len(resp.Result))
+	for i, point := range resp.Result {
+		memories[i] = point.memory()
+	}
+	return memories, nil
+}
+
+// exactFilter returns the filter's exact metadata matches, which a vector
+// database can apply itself.
+func exactFilter(filter Filter) map[string]string {
+	exact := make(map[string]string)
+	for key, value := range map[string]string{
+		"agent":    filter.Agent,
+		"type":     filter.Type,
+		"category": filter.Category,
+		"about":    filter.About,
+	} {
+		if value != "" {
+			exact[key] = value
+		}
+	}
+	return exact
+}
@@ -0,0 +1,123 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/poiesic/wonda/internal/config"
+)
+
+// fakeQdrant serves enough of Qdrant's REST API for one collection. Searches
+// return every point in the order stored, as the store re-ranks them anyway.
+type fakeQdrant struct {
+	mu      sync.Mutex
+	created map[string]interface{}
+	points  []qdrantPoint
+	filters []interface{} // Filters searches were made with
+}
+
+func (f *fakeQdrant) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var body map[string]json.RawMessage
+	json.NewDecoder(r.Body).Decode(&body)
+	switch {
+	case r.Method == "GET" && r.URL.Path == "/collections/harbor":
+		if f.created == nil {
+			http.NotFound(w, r)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"result": map[string]interface{}{
+			"config": map[string]interface{}{"params": map[string]interface{}{"vectors": f.created}},
+		}})
+	case r.Method == "PUT" && r.URL.Path == "/collections/harbor":
+		var create struct {
+			Vectors map[string]interface{} `json:"vectors"`
+		}
+		json.Unmarshal(mustJSON(body), &create)
+		f.created = create.Vectors
+		w.Write([]byte(`{"result":true}`))
+	case r.Method == "PUT" && r.URL.Path == "/collections/harbor/points":
+		var points []qdrantPoint
+		json.Unmarshal(body["points"], &points)
+		f.points = append(f.points, points...)
+		w.Write([]byte(`{"result":{}}`))
+	case r.Method == "POST" && r.URL.Path == "/collections/harbor/points/search":
+		var filter interface{}
+		json.Unmarshal(body["filter"], &filter)
+		f.filters = append(f.filters, filter)
+		json.NewEncoder(w).Encode(map[string]interface{}{"result": f.points})
+	case r.Method == "POST" && r.URL.Path == "/collections/harbor/points":
+		var ids []string
+		json.Unmarshal(body["ids"], &ids)
+		var found []qdrantPoint
+		for _, point := range f.points {
+			if len(ids) > 0 && point.ID == ids[0] {
+				found = append(found, point)
+			}
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"result": found})
+	default:
+		http.Error(w, "unexpected request "+r.Method+" "+r.URL.Path, http.StatusBadRequest)
+	}
+}
+
+func mustJSON(v interface{}) []byte {
+	data, _ := json.Marshal(v)
+	return data
+}
+
+func TestQdrantBackend(t *testing.T) {
+	fake := &fakeQdrant{}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	ctx := context.Background()
+	vectorStore := &config.VectorStore{Name: "qdrant", Type: config.VectorStoreQdrant, URL: server.URL}
+	open := func(run string) *Store {
+		backend, err := OpenVectorStore(vectorStore, "harbor", "test-model", 2, DefaultStoreOptions())
+		require.NoError(t, err)
+		opts := DefaultStoreOptions()
+		opts.Run = run
+		store, err := NewStoreWithBackend(&countingEmbedder{}, opts, backend)
+		require.NoError(t, err)
+		return store
+	}
+
+	// The first run creates the collection and writes its memories to it
+	first := open("run-1")
+	seedID := first.Add(Memory{Content: "The harbor floods in spring.", Embedding: []float32{1, 0}, Metadata: map[string]string{"type": "scene"}})
+	first.Add(Memory{Content: "alice said: hello", Embedding: []float32{1, 0}, Metadata: map[string]string{"type": "episodic", "run": "run-1"}})
+	first.Add(Memory{Content: "The harbor floods in spring.", Embedding: []float32{1, 0}, Metadata: map[string]string{"type": "scene"}})
+	assert.Equal(t, map[string]interface{}{"size": 2.0, "distance": "Cosine"}, fake.created)
+	assert.Len(t, fake.points, 2, "the same memory is written once")
+	require.NoError(t, first.Close())
+
+	// A later run finds shared memories in the collection, but not the first run's episodes
+	second := open("run-2")
+	defer second.Close()
+	assert.Equal(t, 0, second.Count(), "only memories added this run are counted")
+	results := second.Search(ctx, []float32{1, 0}, Filter{Type: "scene"}, 5)
+	require.Len(t, results, 1)
+	assert.Equal(t, seedID, results[0].ID)
+	assert.Empty(t, second.Search(ctx, []float32{1, 0}, Filter{Type: "episodic"}, 5))
+	filter := string(mustJSON(fake.filters[0]))
+	assert.True(t, strings.Contains(filter, `"metadata.type"`) && strings.Contains(filter, `"run-2"`), filter)
+
+	mem, ok := second.Get(seedID)
+	require.True(t, ok)
+	assert.Equal(t, "The harbor floods in spring.", mem.Content)
+
+	// Collections can't mix vector sizes
+	_, err := OpenVectorStore(vectorStore, "harbor", "test-model", 768, DefaultStoreOptions())
+	assert.ErrorContains(t, err, "2-dimension")
+}
@@ -0,0 +1,110 @@
+package memory
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/poiesic/wonda/internal/config"
+)
+
+// remoteTimeout bounds each request to an external vector database.
+const remoteTimeout = 30 * time.Second
+
+// OpenVectorStore opens a collection in an external vector database as a
+// store backend, creating it if needed for embeddings of the given model and
+// dimensions under opts. An existing collection must be compatible.
+func OpenVectorStore(store *config.VectorStore, collection, model string, dimensions int, opts StoreOptions) (Backend, error) {
+	metric := opts.Metric
+	if metric == "" {
+		metric = MetricCosine
+	}
+	switch store.Type {
+	case config.VectorStoreQdrant:
+		return openQdrantBackend(store, collection, dimensions, metric)
+	case config.VectorStoreChroma:
+		return openChromaBackend(store, collection, model, metric)
+	default:
+		return nil, fmt.Errorf("vector store '%s': unknown type '%s'", store.Name, store.Type)
+	}
+}
+
+// remoteBackend is what an external vector database backend keeps in the
+// process: the memories added through it this run, which are what the store
+// counts and checkpoints, and the embeddings it computed. Searches go to the
+// database, which holds every run's memories.
+type remoteBackend struct {
+	*memoryBackend
+	name    string // Vector store name, for errors
+	client  *http.Client
+	baseURL string
+	headers map[string]string
+}
+
+// newRemoteBackend creates the in-process part of a vector store backend.
+func newRemoteBackend(store *config.VectorStore, headers map[string]string) remoteBackend {
+	return remoteBackend{
+		memoryBackend: newMemoryBackend(),
+		name:          store.Name,
+		client:        &http.Client{Timeout: remoteTimeout},
+		baseURL:       strings.TrimRight(store.URL, "/"),
+		headers:       headers,
+	}
+}
+
+// call makes a JSON request to the database, decoding the response into out
+// (if not nil). It returns errNotFound for a 404 response.
+func (r *remoteBackend) call(ctx context.Context, method, path string, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request: %w", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, r.baseURL+path, reader)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range r.headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("vector store '%s': request failed: %w", r.name, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return errNotFound
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("vector store '%s': %s %s returned status %d: %s", r.name, method, path, resp.StatusCode, string(respBody))
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("vector store '%s': failed to parse response: %w", r.name, err)
+	}
+	return nil
+}
+
+// errNotFound is returned by call for a 404 response.
+var errNotFound = fmt.Errorf("not found")
+
+// Close implements Backend.
+func (r *remoteBackend) Close() error {
+	r.client.CloseIdleConnections()
+	return nil
+}
@@ -150,107 +150,87 @@ func SeedOtherCharacter(ctx context.Context, store *Store, agentName string, tar
 	return nil
 }
 
+// Setting is where and when a scene takes place: what agents find when they
+// look at the scene.
+type Setting struct {
+	Location    string
+	Time        string
+	Atmosphere  string
+	Description string
+}
+
+// settingMemory is a part of a setting as a scene memory: how it is written,
+// its category, and the queries that find it.
+type settingMemory struct {
+	content  string
+	category string
+	queries  []string
+}
+
 // SeedScenario pre-seeds the memory store with scenario context.
 // This information is shared across all agents.
 func SeedScenario(ctx context.Context, store *Store, scenario *scenarios.Scenario) error {
-	// Location
-	if scenario.Basics.Location != "" {
-		locationQueries := []string{
-			"where am I?",
-			"what is the location?",
-			"describe the scene",
-		}
-
-		for _, query := range locationQueries {
-			embedding, err := store.Embed(ctx, query)
-			if err != nil {
-				return fmt.Errorf("failed to embed location query: %w", err)
-			}
+	return SeedSetting(ctx, store, Setting{
+		Location:    scenario.Basics.Location,
+		Time:        scenario.Basics.TOD,
+		Atmosphere:  scenario.Basics.Atmosphere,
+		Description: scenario.Basics.Description,
+	}, nil)
+}
 
-			store.Add(Memory{
-				Content:   fmt.Sprintf("Location: %s", scenario.Basics.Location),
-				Embedding: embedding,
-				Metadata: map[string]string{
-					"type":       "scene",
-					"category":   "location",
-					"indexed_by": query,
-				},
-			})
-		}
+// SeedSetting seeds scene memories for the parts of a setting that are set,
+// shared across all agents. The extra metadata, such as the act the setting
+// belongs to, is added to each memory.
+func SeedSetting(ctx context.Context, store *Store, setting Setting, extra map[string]string) error {
+	var parts []settingMemory
+	if setting.Location != "" {
+		parts = append(parts, settingMemory{
+			content:  fmt.Sprintf("Location: %s", setting.Location),
+			category: "location",
+			queries:  []string{"where am I?", "what is the location?", "describe the scene"},
+		})
 	}
-
-	// Atmosphere
-	if scenario.Basics.Atmosphere != "" {
-		atmosphereQueries := []string{
-			"what's the atmosphere?",
-			"what's the mood?",
-			"describe the atmosphere",
-		}
-
-		for _, query := range atmosphereQueries {
-			embedding, err := store.Embed(ctx, query)
-			if err != nil {
-				return fmt.Errorf("failed to embed atmosphere query: %w", err)
-			}
-
-			store.Add(Memory{
-				Content:   fmt.Sprintf("Atmosphere: %s", scenario.Basics.Atmosphere),
-				Embedding: embedding,
-				Metadata: map[string]string{
-					"type":       "scene",
-					"category":   "atmosphere",
-					"indexed_by": query,
-				},
-			})
-		}
+	if setting.Atmosphere != "" {
+		parts = append(parts, settingMemory{
+			content:  fmt.Sprintf("Atmosphere: %s", setting.Atmosphere),
+			category: "atmosphere",
+			queries:  []string{"what's the atmosphere?", "what's the mood?", "describe the atmosphere"},
+		})
 	}
-
-	// Time of Day
-	if scenario.Basics.TOD != "" {
-		timeQueries := []string{
-			"what time is it?",
-			"when is this happening?",
-		}
-
-		for _, query := range timeQueries {
-			embedding, err := store.Embed(ctx, query)
-			if err != nil {
-				return fmt.Errorf("failed to embed time query: %w", err)
-			}
-
-			store.Add(Memory{
-				Content:   fmt.Sprintf("Time: %s", scenario.Basics.TOD),
-				Embedding: embedding,
-				Metadata: map[string]string{
-					"type":       "scene",
-					"category":   "time",
-					"indexed_by": query,
-				},
-			})
-		}
+	if setting.Time != "" {
+		parts = append(parts, settingMemory{
+			content:  fmt.Sprintf("Time: %s", setting.Time),
+			category: "time",
+			queries:  []string{"what time is it?", "when is this happening?"},
+		})
+	}
+	if setting.Description != "" {
+		parts = append(parts, settingMemory{
+			content:  setting.Description,
+			category: "context",
+			queries:  []string{"what is happening?", "what's the situation?"},
+		})
 	}
 
-	// Scenario description/context
-	if scenario.Basics.Description != "" {
-		contextQueries := []string{
-			"what is happening?",
-			"what's the situation?",
-		}
-
-		for _, query := range contextQueries {
+	for _, part := range parts {
+		for _, query := range part.queries {
 			embedding, err := store.Embed(ctx, query)
 			if err != nil {
-				return fmt.Errorf("failed to embed context query: %w", err)
+				return fmt.Errorf("failed to embed %s query: %w", part.category, err)
 			}
 
+			metadata := map[string]string{
+				"type":       "scene",
+				"category":   part.category,
+				"indexed_by": query,
+			}
+			for key, value := range extra {
+				metadata[key] = value
+			}
 			store.Add(Memory{
-				Content:   scenario.Basics.Description,
+				Content:   part.content,
 				Embedding: embedding,
-				Metadata: map[string]string{
-					"type":       "scene",
-					"category":   "context",
-					"indexed_by": query,
-				},
+				Metadata:  metadata,
 			})
 		}
 	}
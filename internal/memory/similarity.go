@@ -0,0 +1,138 @@
+package memory
+
+import (
+	"fmt"
+	"math"
+)
+
+// Metric selects how the store scores a memory against a query embedding.
+type Metric string
+
+// Supported similarity metrics
+const (
+	MetricCosine    Metric = "cosine"    // Angle between vectors (default)
+	MetricDot       Metric = "dot"       // Raw dot product; expects normalized vectors
+	MetricEuclidean Metric = "euclidean" // Distance, scored as 1/(1+d) so higher is closer
+)
+
+// ParseMetric converts a configuration string to a Metric.
+// An empty string selects MetricCosine.
+func ParseMetric(s string) (Metric, error) {
+	switch Metric(s) {
+	case "":
+		return MetricCosine, nil
+	case MetricCosine, MetricDot, MetricEuclidean:
+		return Metric(s), nil
+	default:
+		return "", fmt.Errorf("unknown similarity metric '%s' (use cosine, dot, or euclidean)", s)
+	}
+}
+
+// StoreOptions configures how a Store compares embeddings.
+type StoreOptions struct {
+	Metric    Metric // Similarity metric used by Search
+	Normalize bool   // L2-normalize embeddings when added and queries when searched
+
+	// Language tags memories added without a "language" entry in their metadata (empty leaves them untagged)
+	Language string
+	// CrossLingual reports that the embedder compares text across languages,
+	// so retrieval need not be limited to the searcher's language
+	CrossLingual bool
+	// Run limits searches to memories recorded by this run, tagged with a
+	// "run" entry in their metadata, and memories from no run in particular,
+	// such as seeded ones, when a persistent store holds other runs' memories
+	// (empty searches them all)
+	Run string
+}
+
+// DefaultStoreOptions returns cosine similarity without normalization.
+func DefaultStoreOptions() StoreOptions {
+	return StoreOptions{Metric: MetricCosine}
+}
+
+// NormalizedEmbedder is implemented by embedders whose output vectors are already unit length.
+type NormalizedEmbedder interface {
+	Normalized() bool
+}
+
+// DimensionedEmbedder is implemented by embedders that report their vector size.
+type DimensionedEmbedder interface {
+	Dimensions() int
+}
+
+// ValidateOptions checks that the options make sense for the embedder.
+// Dot product on vectors that are neither normalized by the store nor by the
+// embedder ranks by magnitude rather than meaning, so it is rejected.
+func ValidateOptions(opts StoreOptions, embedder Embedder) error {
+	if _, err := ParseMetric(string(opts.Metric)); err != nil {
+		return err
+	}
+
+	if opts.Metric == MetricDot && !opts.Normalize {
+		normalized, ok := embedder.(NormalizedEmbedder)
+		if !ok || !normalized.Normalized() {
+			return fmt.Errorf("dot product similarity requires normalized vectors: set normalize = true or use cosine")
+		}
+	}
+
+	return nil
+}
+
+// similarity scores b against a using the given metric. Higher is more similar.
+func similarity(metric Metric, a, b []float32) float32 {
+	switch metric {
+	case MetricDot:
+		return dotProduct(a, b)
+	case MetricEuclidean:
+		return euclideanSimilarity(a, b)
+	default:
+		return cosineSimilarity(a, b)
+	}
+}
+
+// dotProduct computes the dot product of two vectors.
+func dotProduct(a, b []float32) float32 {
+	if len(a) != len(b) {
+		return 0
+	}
+
+	var sum float32
+	for i := range a {
+		sum += a[i] * b[i]
+	}
+	return sum
+}
+
+// euclideanSimilarity converts the Euclidean distance between two vectors
+// into a similarity in (0, 1], where identical vectors score 1.
+func euclideanSimilarity(a, b []float32) float32 {
+	if len(a) != len(b) {
+		return 0
+	}
+
+	var sum float64
+	for i := range a {
+		d := float64(a[i] - b[i])
+		sum += d * d
+	}
+	return float32(1 / (1 + math.Sqrt(sum)))
+}
+
+// normalize returns a copy of v scaled to unit length.
+// Zero vectors are returned unchanged.
+func normalize(v []float32) []float32 {
+	var norm float64
+	for _, x := range v {
+		norm += float64(x) * float64(x)
+	}
+	if norm == 0 {
+		return v
+	}
+
+	norm = math.Sqrt(norm)
+	out := make([]float32, len(v))
+	for i, x := range v {
+		out[i] = float32(float64(x) / norm)
+	}
+	return out
+}
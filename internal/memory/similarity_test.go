@@ -0,0 +1,164 @@
+package memory
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// lengthEmbedder embeds text as its length.
+type lengthEmbedder struct{}
+
+func (lengthEmbedder) Embed(ctx context.Context, text string) ([]float32, error) {
+	return []float32{float32(len(text)), 1}, nil
+}
+
+// unitEmbedder reports that its vectors are already normalized.
+type unitEmbedder struct{ lengthEmbedder }
+
+func (unitEmbedder) Normalized() bool { return true }
+
+func TestParseMetric(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    Metric
+		wantErr bool
+	}{
+		{input: "", want: MetricCosine},
+		{input: "cosine", want: MetricCosine},
+		{input: "dot", want: MetricDot},
+		{input: "euclidean", want: MetricEuclidean},
+		{input: "manhattan", wantErr: true},
+		{input: "Cosine", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseMetric(tt.input)
+			if tt.wantErr {
+				assert.ErrorContains(t, err, "unknown similarity metric")
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestValidateOptions(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     StoreOptions
+		embedder Embedder
+		wantErr  string
+	}{
+		{name: "cosine", opts: StoreOptions{Metric: MetricCosine}, embedder: lengthEmbedder{}},
+		{name: "euclidean", opts: StoreOptions{Metric: MetricEuclidean}, embedder: lengthEmbedder{}},
+		{name: "dot with normalize", opts: StoreOptions{Metric: MetricDot, Normalize: true}, embedder: lengthEmbedder{}},
+		{name: "dot on a normalized embedder", opts: StoreOptions{Metric: MetricDot}, embedder: unitEmbedder{}},
+		{name: "dot without normalize", opts: StoreOptions{Metric: MetricDot}, embedder: lengthEmbedder{}, wantErr: "requires normalized vectors"},
+		{name: "unknown metric", opts: StoreOptions{Metric: "manhattan"}, embedder: lengthEmbedder{}, wantErr: "unknown similarity metric"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateOptions(tt.opts, tt.embedder)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+
+			_, err = NewStoreWithOptions(tt.embedder, tt.opts)
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestEuclideanSimilarity(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b []float32
+		want float32
+	}{
+		{name: "identical", a: []float32{1, 2}, b: []float32{1, 2}, want: 1},
+		{name: "distance 1", a: []float32{0, 0}, b: []float32{1, 0}, want: 0.5},
+		{name: "distance 3", a: []float32{0, 0}, b: []float32{0, 3}, want: 0.25},
+		{name: "mismatched lengths", a: []float32{1}, b: []float32{1, 2}, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.InDelta(t, tt.want, euclideanSimilarity(tt.a, tt.b), 1e-6)
+		})
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name string
+		in   []float32
+		want []float32
+	}{
+		{name: "scales to unit length", in: []float32{3, 4}, want: []float32{0.6, 0.8}},
+		{name: "keeps unit vectors", in: []float32{0, 1}, want: []float32{0, 1}},
+		{name: "leaves zero vectors alone", in: []float32{0, 0}, want: []float32{0, 0}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			in := append([]float32(nil), tt.in...)
+			got := normalize(in)
+			require.Len(t, got, len(tt.want))
+			for i := range tt.want {
+				assert.InDelta(t, tt.want[i], got[i], 1e-6)
+			}
+			assert.Equal(t, tt.in, in, "the input isn't modified")
+		})
+	}
+}
+
+func TestSearchMetrics(t *testing.T) {
+	// Each metric ranks these differently against the query [1, 0]: cosine
+	// by direction, a raw dot product by magnitude too, and Euclidean by distance
+	embeddings := map[string][]float32{
+		"far":      {10, 0},
+		"near":     {1, 0.1},
+		"diagonal": {3, 3},
+		"across":   {0, 1},
+	}
+
+	tests := []struct {
+		name     string
+		opts     StoreOptions
+		embedder Embedder
+		want     []string
+	}{
+		{name: "cosine", opts: StoreOptions{Metric: MetricCosine}, embedder: lengthEmbedder{}, want: []string{"far", "near", "diagonal", "across"}},
+		{name: "dot", opts: StoreOptions{Metric: MetricDot}, embedder: unitEmbedder{}, want: []string{"far", "diagonal", "near", "across"}},
+		{name: "normalized dot", opts: StoreOptions{Metric: MetricDot, Normalize: true}, embedder: lengthEmbedder{}, want: []string{"far", "near", "diagonal", "across"}},
+		{name: "euclidean", opts: StoreOptions{Metric: MetricEuclidean}, embedder: lengthEmbedder{}, want: []string{"near", "across", "diagonal", "far"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store, err := NewStoreWithOptions(tt.embedder, tt.opts)
+			require.NoError(t, err)
+			for id, embedding := range embeddings {
+				store.Add(Memory{ID: id, Content: id, Embedding: append([]float32(nil), embedding...)})
+			}
+
+			results := store.Search(context.Background(), []float32{1, 0}, Filter{}, 10)
+			ids := make([]string, len(results))
+			for i, mem := range results {
+				ids[i] = mem.ID
+			}
+			assert.Equal(t, tt.want, ids)
+			for i := 1; i < len(results); i++ {
+				assert.GreaterOrEqual(t, results[i-1].Score, results[i].Score)
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package memory
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+)
+
+// Snapshot is a serializable copy of a store's memories together with the
+// similarity settings their embeddings were stored under.
+type Snapshot struct {
+	Metric    Metric   `json:"metric"`
+	Normalize bool     `json:"normalize"`
+	Memories  []Memory `json:"memories"`
+	Archived  []string `json:"archived,omitempty"` // IDs of memories no longer searched
+}
+
+// Snapshot returns a copy of the store's contents and similarity settings.
+func (s *Store) Snapshot() Snapshot {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	memories := make([]Memory, len(s.backend.Memories()))
+	copy(memories, s.backend.Memories())
+	archived := make([]string, 0, len(s.archived))
+	for id := range s.archived {
+		archived = append(archived, id)
+	}
+	sort.Strings(archived)
+
+	return Snapshot{
+		Metric:    s.options.Metric,
+		Normalize: s.options.Normalize,
+		Memories:  memories,
+		Archived:  archived,
+	}
+}
+
+// Restore replaces the store's memories, and which are archived, with those
+// from a snapshot.
+// The snapshot must have been taken with the same similarity settings,
+// otherwise stored embeddings would be scored inconsistently. A persistent
+// backend keeps the memories it already holds for later runs.
+func (s *Store) Restore(snapshot Snapshot) error {
+	metric, err := ParseMetric(string(snapshot.Metric))
+	if err != nil {
+		return fmt.Errorf("invalid snapshot: %w", err)
+	}
+	opts := s.Options()
+	if metric != opts.Metric || snapshot.Normalize != opts.Normalize {
+		return fmt.Errorf("snapshot settings (metric=%s, normalize=%t) do not match store (metric=%s, normalize=%t)",
+			metric, snapshot.Normalize, opts.Metric, opts.Normalize)
+	}
+
+	memories := make([]Memory, len(snapshot.Memories))
+	copy(memories, snapshot.Memories)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.archived = make(map[string]bool, len(snapshot.Archived))
+	for _, id := range snapshot.Archived {
+		s.archived[id] = true
+	}
+	return s.backend.Replace(memories)
+}
+
+// SaveSnapshot writes the store's snapshot to a JSON file.
+func (s *Store) SaveSnapshot(path string) error {
+	data, err := json.Marshal(s.Snapshot())
+	if err != nil {
+		return fmt.Errorf("failed to marshal snapshot: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	return nil
+}
+
+// LoadSnapshot restores the store from a JSON snapshot file.
+func (s *Store) LoadSnapshot(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read snapshot: %w", err)
+	}
+
+	var snapshot Snapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return fmt.Errorf("failed to parse snapshot: %w", err)
+	}
+	return s.Restore(snapshot)
+}
@@ -3,53 +3,225 @@ package memory
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"math"
 	"sort"
+	"strings"
+	"sync"
 
 	"github.com/google/uuid"
 )
 
-// Store manages memory storage and retrieval.
+// Store manages memory storage and retrieval. It is safe for concurrent use,
+// so agents can be seeded in parallel.
 type Store struct {
-	memories []Memory
+	mu       sync.RWMutex // Guards the backend, options and archived
+	backend  Backend
 	embedder Embedder
+	options  StoreOptions
+	archived map[string]bool // IDs of memories no longer searched, such as compacted ones
 }
 
+// memoryNamespace namespaces the IDs derived for memories added without one.
+var memoryNamespace = uuid.NewSHA1(uuid.NameSpaceURL, []byte("https://github.com/poiesic/wonda/memory"))
+
 // NewStore creates a new memory store with the given embedder.
+// It uses cosine similarity without normalization.
 func NewStore(embedder Embedder) *Store {
 	return &Store{
-		memories: make([]Memory, 0),
+		backend:  newMemoryBackend(),
 		embedder: embedder,
+		options:  DefaultStoreOptions(),
 	}
 }
 
+// NewStoreWithOptions creates a memory store with the given similarity options.
+// The options are validated against the embedder.
+func NewStoreWithOptions(embedder Embedder, opts StoreOptions) (*Store, error) {
+	return NewStoreWithBackend(embedder, opts, newMemoryBackend())
+}
+
+// NewStoreWithBackend creates a memory store that keeps its memories and
+// embeddings in the given backend, such as a FileBackend shared between runs.
+// The options are validated against the embedder.
+func NewStoreWithBackend(embedder Embedder, opts StoreOptions, backend Backend) (*Store, error) {
+	if opts.Metric == "" {
+		opts.Metric = MetricCosine
+	}
+	if err := ValidateOptions(opts, embedder); err != nil {
+		return nil, err
+	}
+
+	return &Store{
+		backend:  backend,
+		embedder: embedder,
+		options:  opts,
+	}, nil
+}
+
+// Options returns the store's similarity options.
+func (s *Store) Options() StoreOptions {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.options
+}
+
+// SetRun sets the run searches are limited to (see StoreOptions.Run).
+func (s *Store) SetRun(run string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.options.Run = run
+}
+
+// Close releases the store's backend.
+func (s *Store) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.backend.Close()
+}
+
 // Add adds a new memory to the store.
+// Memories added without an ID get one derived from their content and
+// metadata, so adding the same memory again, as when a persistent store is
+// seeded by another run, keeps the one already stored.
 func (s *Store) Add(mem Memory) string {
-	// Generate ID if not provided
-	if mem.ID == "" {
-		mem.ID = uuid.New().String()
-	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
 
 	// Ensure metadata map exists
 	if mem.Metadata == nil {
 		mem.Metadata = make(map[string]string)
 	}
+	if s.options.Language != "" && mem.Metadata["language"] == "" {
+		mem.Metadata["language"] = s.options.Language
+	}
 
-	s.memories = append(s.memories, mem)
+	// Generate ID if not provided
+	if mem.ID == "" {
+		mem.ID = memoryID(mem)
+	}
+
+	if s.options.Normalize {
+		mem.Embedding = normalize(mem.Embedding)
+	}
+
+	if err := s.backend.Add(mem); err != nil {
+		// The memory is still searched this run
+		slog.Warn("failed to store memory", "id", mem.ID, "error", err)
+	}
 	return mem.ID
 }
 
-// Embed generates an embedding for the given text.
+// memoryID derives a memory's ID from its content and metadata.
+func memoryID(mem Memory) string {
+	keys := make([]string, 0, len(mem.Metadata))
+	for key := range mem.Metadata {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	b.WriteString(mem.Content)
+	for _, key := range keys {
+		fmt.Fprintf(&b, "\x00%s=%s", key, mem.Metadata[key])
+	}
+	return uuid.NewSHA1(memoryNamespace, []byte(b.String())).String()
+}
+
+// Get returns the memory with the given ID.
+func (s *Store) Get(id string) (Memory, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.backend.Get(id)
+}
+
+// Archive stops the memories with the given IDs from being searched, as when
+// they have been compacted into a summary. They stay in the backend, and
+// snapshots record that they are archived.
+func (s *Store) Archive(ids ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.archived == nil {
+		s.archived = make(map[string]bool)
+	}
+	for _, id := range ids {
+		s.archived[id] = true
+	}
+}
+
+// Archived reports whether the memory with the given ID is archived.
+func (s *Store) Archived(id string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.archived[id]
+}
+
+// Memories returns the memories matching the filter that aren't archived, in
+// the order they were added.
+func (s *Store) Memories(filter Filter) []Memory {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	memories := make([]Memory, 0)
+	for _, mem := range s.unarchived(s.backend.Memories()) {
+		if filter.Matches(&mem) && s.options.inRun(&mem) {
+			memories = append(memories, mem)
+		}
+	}
+	return memories
+}
+
+// unarchived returns the memories that aren't archived. The caller must hold
+// the lock.
+func (s *Store) unarchived(memories []Memory) []Memory {
+	if len(s.archived) == 0 {
+		return memories
+	}
+	kept := make([]Memory, 0, len(memories))
+	for _, mem := range memories {
+		if !s.archived[mem.ID] {
+			kept = append(kept, mem)
+		}
+	}
+	return kept
+}
+
+// Embed generates an embedding for the given text, reusing the one the
+// backend cached if the text was embedded before.
 func (s *Store) Embed(ctx context.Context, text string) ([]float32, error) {
-	return s.embedder.Embed(ctx, text)
+	s.mu.RLock()
+	embedding, ok := s.backend.Embedding(text)
+	s.mu.RUnlock()
+	if ok {
+		return embedding, nil
+	}
+
+	// Embedding is the slow part, so it runs unlocked
+	embedding, err := s.embedder.Embed(ctx, text)
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.backend.AddEmbedding(text, embedding); err != nil {
+		slog.Warn("failed to cache embedding", "error", err)
+	}
+	return embedding, nil
 }
 
 // Search performs vector similarity search with filtering.
 func (s *Store) Search(ctx context.Context, queryEmbedding []float32, filter Filter, topK int) []Memory {
+	return s.SearchBoosted(ctx, queryEmbedding, filter, topK, nil)
+}
+
+// SearchBoosted performs vector similarity search with filtering, ranking
+// memories that share any of the tags TagBoost higher.
+func (s *Store) SearchBoosted(ctx context.Context, queryEmbedding []float32, filter Filter, topK int, tags []string) []Memory {
+	opts := s.Options()
+
 	// 1. Filter by metadata
 	candidates := make([]Memory, 0)
-	for _, mem := range s.memories {
-		if filter.Matches(&mem) {
+	for _, mem := range s.searchable(ctx, queryEmbedding, filter, topK) {
+		if filter.Matches(&mem) && opts.inRun(&mem) {
 			candidates = append(candidates, mem)
 		}
 	}
@@ -58,15 +230,22 @@ func (s *Store) Search(ctx context.Context, queryEmbedding []float32, filter Fil
 		return []Memory{}
 	}
 
-	// 2. Compute cosine similarity scores
+	// 2. Compute similarity scores with the configured metric
 	type scoredMemory struct {
 		memory Memory
 		score  float32
 	}
 
+	if opts.Normalize {
+		queryEmbedding = normalize(queryEmbedding)
+	}
+
 	scored := make([]scoredMemory, len(candidates))
 	for i, mem := range candidates {
-		score := cosineSimilarity(queryEmbedding, mem.Embedding)
+		score := similarity(opts.Metric, queryEmbedding, mem.Embedding)
+		if len(tags) > 0 && mem.SharesTag(tags) {
+			score += TagBoost
+		}
 		scored[i] = scoredMemory{
 			memory: mem,
 			score:  score,
@@ -93,6 +272,40 @@ func (s *Store) Search(ctx context.Context, queryEmbedding []float32, filter Fil
 	return results
 }
 
+// searchCandidates is how many times the requested results a searching
+// backend is asked for, leaving room for the filtering and tag boosts it
+// doesn't apply.
+const searchCandidates = 4
+
+// searchable returns the memories a search scores: every memory, or the
+// nearest candidates when the backend searches for itself, less archived ones.
+func (s *Store) searchable(ctx context.Context, queryEmbedding []float32, filter Filter, topK int) []Memory {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	searcher, ok := s.backend.(Searcher)
+	if !ok {
+		return s.unarchived(s.backend.Memories())
+	}
+
+	query := queryEmbedding
+	if s.options.Normalize {
+		query = normalize(query)
+	}
+	memories, err := searcher.Search(ctx, query, filter, s.options.Run, topK*searchCandidates)
+	if err != nil {
+		// Searches don't fail; the agent finds nothing this time
+		slog.Warn("memory search failed", "error", err)
+		return nil
+	}
+	return s.unarchived(memories)
+}
+
+// inRun reports whether a memory is searched in the options' run.
+func (o StoreOptions) inRun(mem *Memory) bool {
+	run := mem.Metadata["run"]
+	return o.Run == "" || run == "" || run == o.Run
+}
+
 // SearchByCanonicalQuery searches using a fixed text query.
 // This is used for pre-seeded memories indexed under specific queries.
 func (s *Store) SearchByCanonicalQuery(ctx context.Context, query string, filter Filter, topK int) ([]Memory, error) {
@@ -108,13 +321,17 @@ func (s *Store) SearchByCanonicalQuery(ctx context.Context, query string, filter
 
 // Count returns the total number of memories in the store.
 func (s *Store) Count() int {
-	return len(s.memories)
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.backend.Memories())
 }
 
 // CountByFilter returns the number of memories matching the filter.
 func (s *Store) CountByFilter(filter Filter) int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
 	count := 0
-	for _, mem := range s.memories {
+	for _, mem := range s.backend.Memories() {
 		if filter.Matches(&mem) {
 			count++
 		}
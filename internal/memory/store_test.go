@@ -0,0 +1,43 @@
+package memory
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStoreArchive(t *testing.T) {
+	ctx := context.Background()
+	store := NewStore(lengthEmbedder{})
+	add := func(content string) string {
+		embedding, err := store.Embed(ctx, content)
+		require.NoError(t, err)
+		return store.Add(Memory{Content: content, Embedding: embedding, Metadata: map[string]string{"type": "episodic"}})
+	}
+	kept := add("Alice said: pizza")
+	archived := add("Alice said: pizza again")
+
+	store.Archive(archived)
+	assert.True(t, store.Archived(archived))
+	assert.False(t, store.Archived(kept))
+
+	filter := Filter{Type: "episodic"}
+	results := store.Search(ctx, []float32{1, 1}, filter, 10)
+	require.Len(t, results, 1)
+	assert.Equal(t, kept, results[0].ID)
+	memories := store.Memories(filter)
+	require.Len(t, memories, 1)
+	assert.Equal(t, kept, memories[0].ID)
+
+	// Snapshots keep archived memories, and restore them archived
+	snapshot := store.Snapshot()
+	assert.Len(t, snapshot.Memories, 2)
+	assert.Equal(t, []string{archived}, snapshot.Archived)
+
+	restored := NewStore(lengthEmbedder{})
+	require.NoError(t, restored.Restore(snapshot))
+	assert.True(t, restored.Archived(archived))
+	assert.Len(t, restored.Memories(filter), 1)
+}
@@ -0,0 +1,35 @@
+package memory
+
+import (
+	"slices"
+	"strings"
+)
+
+// TagsKey is the metadata key holding a memory's topic tags, comma separated.
+const TagsKey = "tags"
+
+// TagBoost is added to the score of memories sharing a tag with a boosted search.
+const TagBoost float32 = 0.1
+
+// JoinTags formats tags for a memory's metadata.
+func JoinTags(tags []string) string {
+	return strings.Join(tags, ",")
+}
+
+// Tags returns a memory's topic tags.
+func (m *Memory) Tags() []string {
+	if m.Metadata[TagsKey] == "" {
+		return nil
+	}
+	return strings.Split(m.Metadata[TagsKey], ",")
+}
+
+// SharesTag reports whether the memory has any of the given tags.
+func (m *Memory) SharesTag(tags []string) bool {
+	for _, tag := range m.Tags() {
+		if slices.Contains(tags, tag) {
+			return true
+		}
+	}
+	return false
+}
@@ -19,6 +19,7 @@ type Filter struct {
 	About    string // For character_knowledge, who the memory is about
 	MinTurn  int    // Minimum turn number (0 = no filter)
 	MaxTurn  int    // Maximum turn number (0 = no filter)
+	Language string // Filter by language code; memories without a language always match
 }
 
 // Matches returns true if the memory matches all non-empty filter criteria.
@@ -39,6 +40,12 @@ func (f *Filter) Matches(m *Memory) bool {
 		return false
 	}
 
+	if f.Language != "" {
+		if language := m.Metadata["language"]; language != "" && language != f.Language {
+			return false
+		}
+	}
+
 	// Turn filtering (if metadata has "turn" field)
 	if turnStr, ok := m.Metadata["turn"]; ok {
 		// Parse turn number
@@ -3,6 +3,12 @@ package prompts
 import (
 	"embed"
 	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/template"
 )
 
 // FS contains all prompt template files embedded at build time.
@@ -26,3 +32,70 @@ func GetPrompt(name string) (string, error) {
 	}
 	return string(content), nil
 }
+
+// OverrideDir is the directory in the config directory whose *_prompt.md files
+// replace the embedded templates of the same name for every scenario.
+const OverrideDir = "prompts"
+
+// Overridable names the prompts agents are given, which scenarios and the
+// config directory may replace. The prompts for judges, the director and
+// memory compaction are fixed, since dry runs recognize them.
+var Overridable = []string{"agent_turn", "deliberation_turn1", "deliberation_other", "voting"}
+
+// Library is the prompt templates a simulation uses: the embedded ones, some
+// of which may be overridden.
+type Library struct {
+	overrides map[string]string
+}
+
+// NewLibrary creates a library in which overrides, by prompt name, replace the
+// embedded templates. Every override must name an overridable prompt and parse
+// as a template.
+func NewLibrary(overrides map[string]string) (*Library, error) {
+	for name, content := range overrides {
+		if !slices.Contains(Overridable, name) {
+			return nil, fmt.Errorf("prompt '%s' can't be overridden (overridable: %s)", name, strings.Join(Overridable, ", "))
+		}
+		if _, err := template.New(name).Parse(content); err != nil {
+			return nil, fmt.Errorf("invalid override for prompt '%s': %w", name, err)
+		}
+	}
+	return &Library{overrides: overrides}, nil
+}
+
+// LoadOverrides reads the *_prompt.md files in dir by prompt name. A missing
+// directory has no overrides.
+func LoadOverrides(dir string) (map[string]string, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*_prompt.md"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list prompt overrides: %w", err)
+	}
+	overrides := make(map[string]string, len(files))
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read prompt override: %w", err)
+		}
+		overrides[strings.TrimSuffix(filepath.Base(file), "_prompt.md")] = string(content)
+	}
+	return overrides, nil
+}
+
+// Overridden returns the names of the prompts the library overrides, sorted.
+func (l *Library) Overridden() []string {
+	if l == nil {
+		return nil
+	}
+	return slices.Sorted(maps.Keys(l.overrides))
+}
+
+// Get retrieves a prompt template by name, preferring the library's override.
+// A nil library has only the embedded templates.
+func (l *Library) Get(name string) (string, error) {
+	if l != nil {
+		if content, ok := l.overrides[name]; ok {
+			return content, nil
+		}
+	}
+	return GetPrompt(name)
+}
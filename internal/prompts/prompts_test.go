@@ -0,0 +1,64 @@
+package prompts
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLibrary(t *testing.T) {
+	t.Run("prefers overrides over embedded prompts", func(t *testing.T) {
+		library, err := NewLibrary(map[string]string{"voting": "Vote, {{.Name}}."})
+		require.NoError(t, err)
+
+		got, err := library.Get("voting")
+		require.NoError(t, err)
+		assert.Equal(t, "Vote, {{.Name}}.", got)
+
+		embedded, err := GetPrompt("agent_turn")
+		require.NoError(t, err)
+		got, err = library.Get("agent_turn")
+		require.NoError(t, err)
+		assert.Equal(t, embedded, got)
+		assert.Equal(t, []string{"voting"}, library.Overridden())
+	})
+
+	t.Run("nil library uses embedded prompts", func(t *testing.T) {
+		var library *Library
+		got, err := library.Get("voting")
+		require.NoError(t, err)
+		assert.NotEmpty(t, got)
+		assert.Empty(t, library.Overridden())
+	})
+
+	t.Run("rejects prompts that can't be overridden", func(t *testing.T) {
+		_, err := NewLibrary(map[string]string{"director": "Direct."})
+		assert.ErrorContains(t, err, "can't be overridden")
+	})
+
+	t.Run("rejects overrides that don't parse", func(t *testing.T) {
+		_, err := NewLibrary(map[string]string{"voting": "{{.Name"})
+		assert.ErrorContains(t, err, "invalid override for prompt 'voting'")
+	})
+}
+
+func TestLoadOverrides(t *testing.T) {
+	t.Run("reads prompt files by name", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "voting_prompt.md"), []byte("Vote."), 0o644))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.md"), []byte("ignored"), 0o644))
+
+		overrides, err := LoadOverrides(dir)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"voting": "Vote."}, overrides)
+	})
+
+	t.Run("missing directory has no overrides", func(t *testing.T) {
+		overrides, err := LoadOverrides(filepath.Join(t.TempDir(), "prompts"))
+		require.NoError(t, err)
+		assert.Empty(t, overrides)
+	})
+}
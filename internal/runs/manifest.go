@@ -0,0 +1,103 @@
+// Package runs records a manifest for each simulation run so results can be
+// traced back to the exact scenario definition that produced them.
+package runs
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Dir is the name of the run manifest directory in the config directory.
+const Dir = "runs"
+
+// Manifest describes one simulation run.
+type Manifest struct {
+	SimulationID string    `json:"simulation_id"`
+	ScenarioFile string    `json:"scenario_file"` // File name in the scenarios directory
+	ScenarioName string    `json:"scenario_name"`
+	StartTime    time.Time `json:"start_time"`
+	Chronicle    string    `json:"chronicle,omitempty"`
+	Outcomes     string    `json:"outcomes,omitempty"`
+	Scenario     string    `json:"scenario"` // Scenario TOML exactly as it was run
+}
+
+// ManifestDir returns the run manifest directory for a config directory.
+func ManifestDir(configDir string) string {
+	return path.Join(configDir, Dir)
+}
+
+// ManifestPath returns the path of a run's manifest.
+func ManifestPath(configDir, simulationID string) string {
+	return path.Join(ManifestDir(configDir), simulationID+".json")
+}
+
+// Save writes a manifest to the run manifest directory, creating it if needed.
+func Save(configDir string, manifest Manifest) error {
+	dir := ManifestDir(configDir)
+	if err := os.MkdirAll(dir, 0744); err != nil {
+		return fmt.Errorf("failed to create run manifest directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal run manifest: %w", err)
+	}
+
+	manifestPath := ManifestPath(configDir, manifest.SimulationID)
+	if err := os.WriteFile(manifestPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write run manifest: %w", err)
+	}
+	return nil
+}
+
+// List returns every run manifest in the config directory, newest first.
+func List(configDir string) ([]Manifest, error) {
+	dir := ManifestDir(configDir)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var manifests []Manifest
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		data, err := os.ReadFile(path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		var manifest Manifest
+		if err := json.Unmarshal(data, &manifest); err != nil {
+			return nil, fmt.Errorf("%s: %w", entry.Name(), err)
+		}
+		manifests = append(manifests, manifest)
+	}
+
+	sort.SliceStable(manifests, func(i, j int) bool {
+		return manifests[i].StartTime.After(manifests[j].StartTime)
+	})
+	return manifests, nil
+}
+
+// Latest returns the most recent manifest for a scenario file, or nil if it has never been run.
+func Latest(configDir, scenarioFile string) (*Manifest, error) {
+	manifests, err := List(configDir)
+	if err != nil {
+		return nil, err
+	}
+	for _, manifest := range manifests {
+		if manifest.ScenarioFile == scenarioFile {
+			return &manifest, nil
+		}
+	}
+	return nil, nil
+}
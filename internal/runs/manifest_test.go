@@ -0,0 +1,63 @@
+package runs
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestList(t *testing.T) {
+	started := time.Date(2025, time.March, 3, 12, 0, 0, 0, time.UTC)
+
+	t.Run("returns nothing without a runs directory", func(t *testing.T) {
+		manifests, err := List(t.TempDir())
+		require.NoError(t, err)
+		assert.Empty(t, manifests)
+
+		latest, err := Latest(t.TempDir(), "dinner.toml")
+		require.NoError(t, err)
+		assert.Nil(t, latest)
+	})
+
+	t.Run("lists manifests newest first", func(t *testing.T) {
+		configDir := t.TempDir()
+		for _, manifest := range []Manifest{
+			{SimulationID: "first", ScenarioFile: "dinner.toml", StartTime: started},
+			{SimulationID: "third", ScenarioFile: "lunch.toml", StartTime: started.Add(2 * time.Hour)},
+			{SimulationID: "second", ScenarioFile: "dinner.toml", StartTime: started.Add(time.Hour)},
+		} {
+			require.NoError(t, Save(configDir, manifest))
+		}
+		require.NoError(t, os.WriteFile(ManifestPath(configDir, "notes")+".txt", []byte("not a manifest"), 0644))
+		require.NoError(t, os.Mkdir(ManifestPath(configDir, "archive"), 0755))
+
+		manifests, err := List(configDir)
+		require.NoError(t, err)
+		var ids []string
+		for _, manifest := range manifests {
+			ids = append(ids, manifest.SimulationID)
+		}
+		assert.Equal(t, []string{"third", "second", "first"}, ids)
+
+		latest, err := Latest(configDir, "dinner.toml")
+		require.NoError(t, err)
+		require.NotNil(t, latest)
+		assert.Equal(t, "second", latest.SimulationID)
+
+		latest, err = Latest(configDir, "breakfast.toml")
+		require.NoError(t, err)
+		assert.Nil(t, latest)
+	})
+
+	t.Run("returns error for invalid manifests", func(t *testing.T) {
+		configDir := t.TempDir()
+		require.NoError(t, Save(configDir, Manifest{SimulationID: "first", StartTime: started}))
+		require.NoError(t, os.WriteFile(ManifestPath(configDir, "broken"), []byte("{"), 0644))
+
+		_, err := List(configDir)
+		assert.ErrorContains(t, err, "broken.json")
+	})
+}
@@ -8,4 +8,19 @@ type contextKey string
 const (
 	// AgentNameKey is the context key for storing the current agent's name.
 	AgentNameKey contextKey = "agent_name"
+
+	// AgentLanguageKey is the context key for the language the current agent speaks, if set.
+	AgentLanguageKey contextKey = "agent_language"
+
+	// CiteMemoriesKey is the context key set when agents are asked to cite the
+	// memories behind what they say, so memory tools include memory IDs.
+	CiteMemoriesKey contextKey = "cite_memories"
+
+	// GoalTagsKey is the context key for the tags of the goal the current agent
+	// is working on, used to boost related memories.
+	GoalTagsKey contextKey = "goal_tags"
+
+	// MemoryResultsKey is the context key for how many results memory and
+	// knowledge searches return, if not the tools' default.
+	MemoryResultsKey contextKey = "memory_results"
 )
@@ -0,0 +1,50 @@
+package scenarios
+
+import "fmt"
+
+// Act is a later part of the scenario that takes place in a new setting, such
+// as the morning after or the drive home. From the act's turn on, agents find
+// its setting when they look at the scene; what they knew of the setting
+// before becomes a memory of how things were.
+type Act struct {
+	Name        string `toml:"name"`        // Optional: what the act is called, for logs
+	Turn        int    `toml:"turn"`        // Turn the act begins on
+	Location    string `toml:"location"`    // Optional: where the act takes place (default: where the last one did)
+	TOD         string `toml:"time"`        // Optional: when the act takes place
+	Atmosphere  string `toml:"atmosphere"`  // Optional: how the act feels
+	Description string `toml:"description"` // Optional: what has changed, announced to everyone as the act begins
+}
+
+// validateActs checks that acts begin after the first turn, in order, within
+// the scenario's turns, and each change something.
+func validateActs(acts []*Act, maxTurns int) error {
+	for i, act := range acts {
+		if act.Turn < 2 {
+			return fmt.Errorf("act %d: turn must be at least 2 (got %d), since the scenario itself is the first act", i+1, act.Turn)
+		}
+		if i > 0 && act.Turn <= acts[i-1].Turn {
+			return fmt.Errorf("act %d: turn %d must come after the previous act's turn %d", i+1, act.Turn, acts[i-1].Turn)
+		}
+		if maxTurns > 0 && act.Turn > maxTurns {
+			return fmt.Errorf("act %d: turn %d is beyond the scenario's max_turns %d", i+1, act.Turn, maxTurns)
+		}
+		if act.Location == "" && act.TOD == "" && act.Atmosphere == "" && act.Description == "" {
+			return fmt.Errorf("act %d: needs a location, time, atmosphere or description", i+1)
+		}
+	}
+	return nil
+}
+
+// ActAt returns the act under way on a turn and its number, counting the
+// scenario's own setting as act 1. It returns nil and 1 before any act begins.
+func (s *Scenario) ActAt(turn int) (*Act, int) {
+	var current *Act
+	number := 1
+	for i, act := range s.Acts {
+		if act.Turn > turn {
+			break
+		}
+		current, number = act, i+2
+	}
+	return current, number
+}
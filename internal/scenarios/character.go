@@ -3,7 +3,9 @@ package scenarios
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 	"slices"
+	"strings"
 
 	"github.com/pelletier/go-toml/v2"
 	"github.com/poiesic/wonda/internal/config"
@@ -24,10 +26,25 @@ type InternalCharacterInfo struct {
 	Secrets       []string `toml:"secrets"`
 }
 
+// CharacterPresentation describes how exports and the dashboard show a
+// character. It never reaches prompts.
+type CharacterPresentation struct {
+	Pronouns string `toml:"pronouns"` // Optional: e.g. "she/her"
+	Age      int    `toml:"age"`      // Optional: 0 when not given
+	Portrait string `toml:"portrait"` // Optional: image file, relative to the character file, or URL
+	Bio      string `toml:"bio"`      // Optional: one or two sentences for readers (max 300 characters)
+}
+
 type Character struct {
-	External *ExternalCharacterInfo `toml:"external"`
-	Internal *InternalCharacterInfo `toml:"internal"`
-	Version  string                 `toml:"version"`
+	External     *ExternalCharacterInfo `toml:"external"`
+	Internal     *InternalCharacterInfo `toml:"internal"`
+	Presentation *CharacterPresentation `toml:"presentation"` // Optional: shown to readers, not agents
+	Version      string                 `toml:"version"`
+}
+
+// PortraitIsURL reports whether a portrait is a URL rather than a file path.
+func PortraitIsURL(portrait string) bool {
+	return strings.Contains(portrait, "://") || strings.HasPrefix(portrait, "data:")
 }
 
 func NewCharacter() *Character {
@@ -65,6 +82,12 @@ func LoadCharacterFromFile(path string) (*Character, error) {
 	if err := character.Validate(); err != nil {
 		return nil, fmt.Errorf("character validation failed: %w", err)
 	}
+	// Portrait files are found next to the character, wherever it is loaded from
+	if p := character.Presentation; p != nil && p.Portrait != "" && !PortraitIsURL(p.Portrait) && !filepath.IsAbs(p.Portrait) {
+		if dir, err := filepath.Abs(filepath.Dir(path)); err == nil {
+			p.Portrait = filepath.Join(dir, p.Portrait)
+		}
+	}
 	return character, nil
 }
 
@@ -101,6 +124,19 @@ func (c *Character) Validate() error {
 		return fmt.Errorf("internal.background must be at most 2000 characters (got %d)", len(c.Internal.Background))
 	}
 
+	// Presentation validations
+	if p := c.Presentation; p != nil {
+		if len(p.Pronouns) > 30 {
+			return fmt.Errorf("presentation.pronouns must be at most 30 characters (got %d)", len(p.Pronouns))
+		}
+		if p.Age < 0 || p.Age > 200 {
+			return fmt.Errorf("presentation.age must be between 0 and 200 (got %d)", p.Age)
+		}
+		if len(p.Bio) > 300 {
+			return fmt.Errorf("presentation.bio must be at most 300 characters (got %d)", len(p.Bio))
+		}
+	}
+
 	return nil
 }
 
@@ -130,5 +166,13 @@ func (c *Character) Same(other *Character) bool {
 		return false
 	}
 
+	// Compare presentation
+	if (c.Presentation == nil) != (other.Presentation == nil) {
+		return false
+	}
+	if c.Presentation != nil && *c.Presentation != *other.Presentation {
+		return false
+	}
+
 	return true
 }
@@ -12,15 +12,17 @@ func TestCharacterMarshalTOML(t *testing.T) {
 	t.Run("fully populated character", func(t *testing.T) {
 		char := &Character{
 			Version: "1.0.0",
-			Basics: &BasicCharacterInformation{
+			External: &ExternalCharacterInfo{
 				Archetype:          "The Mentor",
 				Description:        "A wise and experienced guide",
-				Background:         "Former warrior turned teacher",
 				CommunicationStyle: "Patient and thoughtful",
-				DecisionStyle:      "Deliberate and principled",
-				Traits:             []string{"wise", "patient", "experienced"},
-				Skills:             []string{"teaching", "combat", "philosophy"},
-				Values:             []string{"knowledge", "honor", "duty"},
+				PositiveTraits:     []string{"wise", "patient", "experienced"},
+				NegativeTraits:     []string{"knowledge", "honor", "duty"},
+				UniqueSkills:       []string{"teaching", "combat", "philosophy"},
+			},
+			Internal: &InternalCharacterInfo{
+				Background:    "Former warrior turned teacher",
+				DecisionStyle: "Deliberate and principled",
 			},
 		}
 
@@ -37,22 +39,24 @@ func TestCharacterMarshalTOML(t *testing.T) {
 		assert.Contains(t, result, "background = 'Former warrior turned teacher'")
 		assert.Contains(t, result, "communication_style = 'Patient and thoughtful'")
 		assert.Contains(t, result, "decision_style = 'Deliberate and principled'")
-		assert.Contains(t, result, "traits = ['wise', 'patient', 'experienced']")
-		assert.Contains(t, result, "skills = ['teaching', 'combat', 'philosophy']")
-		assert.Contains(t, result, "values = ['knowledge', 'honor', 'duty']")
+		assert.Contains(t, result, "positive_traits = ['wise', 'patient', 'experienced']")
+		assert.Contains(t, result, "unique_skills = ['teaching', 'combat', 'philosophy']")
+		assert.Contains(t, result, "negative_traits = ['knowledge', 'honor', 'duty']")
 	})
 
 	t.Run("character with empty strings", func(t *testing.T) {
 		char := &Character{
-			Basics: &BasicCharacterInformation{
+			External: &ExternalCharacterInfo{
 				Archetype:          "",
 				Description:        "",
-				Background:         "",
 				CommunicationStyle: "",
-				DecisionStyle:      "",
-				Traits:             []string{},
-				Skills:             []string{},
-				Values:             []string{},
+				PositiveTraits:     []string{},
+				NegativeTraits:     []string{},
+				UniqueSkills:       []string{},
+			},
+			Internal: &InternalCharacterInfo{
+				Background:    "",
+				DecisionStyle: "",
 			},
 		}
 
@@ -62,22 +66,24 @@ func TestCharacterMarshalTOML(t *testing.T) {
 
 		result := string(buf)
 		assert.Contains(t, result, "archetype = ''")
-		assert.Contains(t, result, "traits = []")
-		assert.Contains(t, result, "skills = []")
-		assert.Contains(t, result, "values = []")
+		assert.Contains(t, result, "positive_traits = []")
+		assert.Contains(t, result, "unique_skills = []")
+		assert.Contains(t, result, "negative_traits = []")
 	})
 
 	t.Run("character with nil slices", func(t *testing.T) {
 		char := &Character{
-			Basics: &BasicCharacterInformation{
+			External: &ExternalCharacterInfo{
 				Archetype:          "The Hero",
 				Description:        "A brave adventurer",
-				Background:         "Unknown origins",
 				CommunicationStyle: "Direct",
-				DecisionStyle:      "Impulsive",
-				Traits:             nil,
-				Skills:             nil,
-				Values:             nil,
+				PositiveTraits:     nil,
+				NegativeTraits:     nil,
+				UniqueSkills:       nil,
+			},
+			Internal: &InternalCharacterInfo{
+				Background:    "Unknown origins",
+				DecisionStyle: "Impulsive",
 			},
 		}
 
@@ -89,15 +95,17 @@ func TestCharacterMarshalTOML(t *testing.T) {
 	t.Run("character with special characters", func(t *testing.T) {
 		char := &Character{
 			Version: "2.1.3",
-			Basics: &BasicCharacterInformation{
+			External: &ExternalCharacterInfo{
 				Archetype:          "The \"Mysterious\" One",
 				Description:        "A character with\nnewlines and\ttabs",
-				Background:         "Background with 'quotes' and \"double quotes\"",
 				CommunicationStyle: "Complex: uses symbols & punctuation!",
-				DecisionStyle:      "Strategic (always thinking)",
-				Traits:             []string{"clever", "mysterious", "enigmatic"},
-				Skills:             []string{"stealth", "deception"},
-				Values:             []string{"freedom", "truth"},
+				PositiveTraits:     []string{"clever", "mysterious", "enigmatic"},
+				NegativeTraits:     []string{"freedom", "truth"},
+				UniqueSkills:       []string{"stealth", "deception"},
+			},
+			Internal: &InternalCharacterInfo{
+				Background:    "Background with 'quotes' and \"double quotes\"",
+				DecisionStyle: "Strategic (always thinking)",
 			},
 		}
 
@@ -114,8 +122,9 @@ func TestCharacterMarshalTOML(t *testing.T) {
 
 	t.Run("character with version only", func(t *testing.T) {
 		char := &Character{
-			Version: "1.2.3",
-			Basics:  &BasicCharacterInformation{},
+			Version:  "1.2.3",
+			External: &ExternalCharacterInfo{},
+			Internal: &InternalCharacterInfo{},
 		}
 
 		buf, err := toml.Marshal(char)
@@ -132,135 +141,145 @@ func TestCharacterUnmarshalTOML(t *testing.T) {
 		tomlData := `
 version = "1.0.0"
 
-[basics]
+[external]
 archetype = "The Villain"
 description = "A formidable antagonist"
-background = "Once a hero, now corrupted"
 communication_style = "Intimidating and commanding"
+positive_traits = ["cunning", "powerful", "ruthless"]
+negative_traits = ["power", "control", "revenge"]
+unique_skills = ["strategy", "manipulation", "dark magic"]
+
+[internal]
+background = "Once a hero, now corrupted"
 decision_style = "Ruthless and calculated"
-traits = ["cunning", "powerful", "ruthless"]
-skills = ["strategy", "manipulation", "dark magic"]
-values = ["power", "control", "revenge"]
 `
 
 		var char Character
 		err := toml.Unmarshal([]byte(tomlData), &char)
 		require.NoError(t, err)
-		require.NotNil(t, char.Basics)
+		require.NotNil(t, char.External)
 
 		assert.Equal(t, "1.0.0", char.Version)
-		assert.Equal(t, "The Villain", char.Basics.Archetype)
-		assert.Equal(t, "A formidable antagonist", char.Basics.Description)
-		assert.Equal(t, "Once a hero, now corrupted", char.Basics.Background)
-		assert.Equal(t, "Intimidating and commanding", char.Basics.CommunicationStyle)
-		assert.Equal(t, "Ruthless and calculated", char.Basics.DecisionStyle)
-		assert.Equal(t, []string{"cunning", "powerful", "ruthless"}, char.Basics.Traits)
-		assert.Equal(t, []string{"strategy", "manipulation", "dark magic"}, char.Basics.Skills)
-		assert.Equal(t, []string{"power", "control", "revenge"}, char.Basics.Values)
+		assert.Equal(t, "The Villain", char.External.Archetype)
+		assert.Equal(t, "A formidable antagonist", char.External.Description)
+		assert.Equal(t, "Once a hero, now corrupted", char.Internal.Background)
+		assert.Equal(t, "Intimidating and commanding", char.External.CommunicationStyle)
+		assert.Equal(t, "Ruthless and calculated", char.Internal.DecisionStyle)
+		assert.Equal(t, []string{"cunning", "powerful", "ruthless"}, char.External.PositiveTraits)
+		assert.Equal(t, []string{"strategy", "manipulation", "dark magic"}, char.External.UniqueSkills)
+		assert.Equal(t, []string{"power", "control", "revenge"}, char.External.NegativeTraits)
 	})
 
 	t.Run("minimal TOML", func(t *testing.T) {
 		tomlData := `
-[basics]
+[external]
 archetype = "The Simple One"
 description = ""
-background = ""
 communication_style = ""
+
+[internal]
+background = ""
 decision_style = ""
 `
 
 		var char Character
 		err := toml.Unmarshal([]byte(tomlData), &char)
 		require.NoError(t, err)
-		require.NotNil(t, char.Basics)
+		require.NotNil(t, char.External)
 
-		assert.Equal(t, "The Simple One", char.Basics.Archetype)
-		assert.Empty(t, char.Basics.Description)
-		assert.Empty(t, char.Basics.Background)
-		assert.Nil(t, char.Basics.Traits)
-		assert.Nil(t, char.Basics.Skills)
-		assert.Nil(t, char.Basics.Values)
+		assert.Equal(t, "The Simple One", char.External.Archetype)
+		assert.Empty(t, char.External.Description)
+		assert.Empty(t, char.Internal.Background)
+		assert.Nil(t, char.External.PositiveTraits)
+		assert.Nil(t, char.External.UniqueSkills)
+		assert.Nil(t, char.External.NegativeTraits)
 	})
 
 	t.Run("TOML with empty arrays", func(t *testing.T) {
 		tomlData := `
-[basics]
+[external]
 archetype = "The Lone Wolf"
 description = "Works alone"
-background = "Mysterious past"
 communication_style = "Minimal"
+positive_traits = []
+negative_traits = []
+unique_skills = []
+
+[internal]
+background = "Mysterious past"
 decision_style = "Independent"
-traits = []
-skills = []
-values = []
 `
 
 		var char Character
 		err := toml.Unmarshal([]byte(tomlData), &char)
 		require.NoError(t, err)
-		require.NotNil(t, char.Basics)
+		require.NotNil(t, char.External)
 
-		assert.Equal(t, "The Lone Wolf", char.Basics.Archetype)
-		assert.Empty(t, char.Basics.Traits)
-		assert.Empty(t, char.Basics.Skills)
-		assert.Empty(t, char.Basics.Values)
+		assert.Equal(t, "The Lone Wolf", char.External.Archetype)
+		assert.Empty(t, char.External.PositiveTraits)
+		assert.Empty(t, char.External.UniqueSkills)
+		assert.Empty(t, char.External.NegativeTraits)
 	})
 
 	t.Run("TOML with single element arrays", func(t *testing.T) {
 		tomlData := `
-[basics]
+[external]
 archetype = "The Specialist"
 description = "Focused on one thing"
-background = "Dedicated training"
 communication_style = "Technical"
+positive_traits = ["focused"]
+negative_traits = ["mastery"]
+unique_skills = ["swordsmanship"]
+
+[internal]
+background = "Dedicated training"
 decision_style = "Expert"
-traits = ["focused"]
-skills = ["swordsmanship"]
-values = ["mastery"]
 `
 
 		var char Character
 		err := toml.Unmarshal([]byte(tomlData), &char)
 		require.NoError(t, err)
-		require.NotNil(t, char.Basics)
+		require.NotNil(t, char.External)
 
-		assert.Equal(t, []string{"focused"}, char.Basics.Traits)
-		assert.Equal(t, []string{"swordsmanship"}, char.Basics.Skills)
-		assert.Equal(t, []string{"mastery"}, char.Basics.Values)
+		assert.Equal(t, []string{"focused"}, char.External.PositiveTraits)
+		assert.Equal(t, []string{"swordsmanship"}, char.External.UniqueSkills)
+		assert.Equal(t, []string{"mastery"}, char.External.NegativeTraits)
 	})
 
 	t.Run("TOML with multiline strings", func(t *testing.T) {
 		tomlData := `
-[basics]
+[external]
 archetype = "The Storyteller"
 description = """
 A wandering bard who tells tales
 of heroes and legends from ages past.
 Known throughout the land."""
+communication_style = "Eloquent and engaging"
+positive_traits = ["charismatic", "creative"]
+negative_traits = ["art", "truth"]
+unique_skills = ["storytelling", "music"]
+
+[internal]
 background = """
 Born in a small village.
 Traveled the world.
 Returned home."""
-communication_style = "Eloquent and engaging"
 decision_style = "Intuitive"
-traits = ["charismatic", "creative"]
-skills = ["storytelling", "music"]
-values = ["art", "truth"]
 `
 
 		var char Character
 		err := toml.Unmarshal([]byte(tomlData), &char)
 		require.NoError(t, err)
-		require.NotNil(t, char.Basics)
+		require.NotNil(t, char.External)
 
-		assert.Equal(t, "The Storyteller", char.Basics.Archetype)
-		assert.Contains(t, char.Basics.Description, "wandering bard")
-		assert.Contains(t, char.Basics.Background, "small village")
+		assert.Equal(t, "The Storyteller", char.External.Archetype)
+		assert.Contains(t, char.External.Description, "wandering bard")
+		assert.Contains(t, char.Internal.Background, "small village")
 	})
 
 	t.Run("invalid TOML", func(t *testing.T) {
 		tomlData := `
-[basics
+[external
 archetype = "Broken"
 `
 
@@ -271,7 +290,7 @@ archetype = "Broken"
 
 	t.Run("TOML with wrong types", func(t *testing.T) {
 		tomlData := `
-[basics]
+[external]
 archetype = 123
 description = "Valid"
 `
@@ -283,9 +302,9 @@ description = "Valid"
 
 	t.Run("TOML with array type mismatch", func(t *testing.T) {
 		tomlData := `
-[basics]
+[external]
 archetype = "The Confused"
-traits = [1, 2, 3]
+positive_traits = [1, 2, 3]
 `
 
 		var char Character
@@ -295,42 +314,46 @@ traits = [1, 2, 3]
 
 	t.Run("TOML without version field", func(t *testing.T) {
 		tomlData := `
-[basics]
+[external]
 archetype = "The Unversioned"
 description = "A character without a version"
-background = ""
 communication_style = "Plain"
+
+[internal]
+background = ""
 decision_style = "Simple"
 `
 
 		var char Character
 		err := toml.Unmarshal([]byte(tomlData), &char)
 		require.NoError(t, err)
-		require.NotNil(t, char.Basics)
+		require.NotNil(t, char.External)
 
 		assert.Equal(t, "", char.Version)
-		assert.Equal(t, "The Unversioned", char.Basics.Archetype)
+		assert.Equal(t, "The Unversioned", char.External.Archetype)
 	})
 
 	t.Run("TOML with version field", func(t *testing.T) {
 		tomlData := `
 version = "2.0.1"
 
-[basics]
+[external]
 archetype = "The Versioned"
 description = "A character with a version"
-background = ""
 communication_style = "Modern"
+
+[internal]
+background = ""
 decision_style = "Updated"
 `
 
 		var char Character
 		err := toml.Unmarshal([]byte(tomlData), &char)
 		require.NoError(t, err)
-		require.NotNil(t, char.Basics)
+		require.NotNil(t, char.External)
 
 		assert.Equal(t, "2.0.1", char.Version)
-		assert.Equal(t, "The Versioned", char.Basics.Archetype)
+		assert.Equal(t, "The Versioned", char.External.Archetype)
 	})
 }
 
@@ -338,15 +361,17 @@ func TestCharacterRoundTrip(t *testing.T) {
 	t.Run("marshal and unmarshal preserves data", func(t *testing.T) {
 		original := &Character{
 			Version: "1.0.0",
-			Basics: &BasicCharacterInformation{
+			External: &ExternalCharacterInfo{
 				Archetype:          "The Guardian",
 				Description:        "Protector of the realm",
-				Background:         "Sworn to defend",
 				CommunicationStyle: "Firm but fair",
-				DecisionStyle:      "Protective and cautious",
-				Traits:             []string{"brave", "loyal", "steadfast"},
-				Skills:             []string{"defense", "tactics", "leadership"},
-				Values:             []string{"duty", "protection", "sacrifice"},
+				PositiveTraits:     []string{"brave", "loyal", "steadfast"},
+				NegativeTraits:     []string{"duty", "protection", "sacrifice"},
+				UniqueSkills:       []string{"defense", "tactics", "leadership"},
+			},
+			Internal: &InternalCharacterInfo{
+				Background:    "Sworn to defend",
+				DecisionStyle: "Protective and cautious",
 			},
 		}
 
@@ -366,15 +391,17 @@ func TestCharacterRoundTrip(t *testing.T) {
 
 	t.Run("round trip with empty values", func(t *testing.T) {
 		original := &Character{
-			Basics: &BasicCharacterInformation{
+			External: &ExternalCharacterInfo{
 				Archetype:          "",
 				Description:        "",
-				Background:         "",
 				CommunicationStyle: "",
-				DecisionStyle:      "",
-				Traits:             []string{},
-				Skills:             []string{},
-				Values:             []string{},
+				PositiveTraits:     []string{},
+				NegativeTraits:     []string{},
+				UniqueSkills:       []string{},
+			},
+			Internal: &InternalCharacterInfo{
+				Background:    "",
+				DecisionStyle: "",
 			},
 		}
 
@@ -390,15 +417,17 @@ func TestCharacterRoundTrip(t *testing.T) {
 
 	t.Run("round trip with partial data", func(t *testing.T) {
 		original := &Character{
-			Basics: &BasicCharacterInformation{
+			External: &ExternalCharacterInfo{
 				Archetype:          "The Wanderer",
 				Description:        "No fixed home",
-				Background:         "",
 				CommunicationStyle: "",
-				DecisionStyle:      "",
-				Traits:             []string{"adventurous"},
-				Skills:             nil,
-				Values:             []string{},
+				PositiveTraits:     []string{"adventurous"},
+				NegativeTraits:     []string{},
+				UniqueSkills:       nil,
+			},
+			Internal: &InternalCharacterInfo{
+				Background:    "",
+				DecisionStyle: "",
 			},
 		}
 
@@ -415,15 +444,17 @@ func TestCharacterRoundTrip(t *testing.T) {
 	t.Run("multiple round trips preserve data", func(t *testing.T) {
 		original := &Character{
 			Version: "3.2.1",
-			Basics: &BasicCharacterInformation{
+			External: &ExternalCharacterInfo{
 				Archetype:          "The Sage",
 				Description:        "Ancient wisdom keeper",
-				Background:         "Centuries of study",
 				CommunicationStyle: "Cryptic and profound",
-				DecisionStyle:      "Measured and wise",
-				Traits:             []string{"wise", "ancient", "mystical"},
-				Skills:             []string{"prophecy", "ancient languages", "meditation"},
-				Values:             []string{"knowledge", "balance", "truth"},
+				PositiveTraits:     []string{"wise", "ancient", "mystical"},
+				NegativeTraits:     []string{"knowledge", "balance", "truth"},
+				UniqueSkills:       []string{"prophecy", "ancient languages", "meditation"},
+			},
+			Internal: &InternalCharacterInfo{
+				Background:    "Centuries of study",
+				DecisionStyle: "Measured and wise",
 			},
 		}
 
@@ -447,7 +478,7 @@ func TestNewCharacter(t *testing.T) {
 	t.Run("creates valid character", func(t *testing.T) {
 		char := NewCharacter()
 		require.NotNil(t, char)
-		require.NotNil(t, char.Basics)
+		require.NotNil(t, char.External)
 	})
 
 	t.Run("can marshal new character", func(t *testing.T) {
@@ -459,21 +490,23 @@ func TestNewCharacter(t *testing.T) {
 
 	t.Run("can unmarshal into new character", func(t *testing.T) {
 		tomlData := `
-[basics]
+[external]
 archetype = "Test"
 description = "Test character"
-background = "Test background"
 communication_style = "Test style"
+positive_traits = ["test"]
+negative_traits = ["quality"]
+unique_skills = ["testing"]
+
+[internal]
+background = "Test background"
 decision_style = "Test decisions"
-traits = ["test"]
-skills = ["testing"]
-values = ["quality"]
 `
 
 		char := NewCharacter()
 		err := toml.Unmarshal([]byte(tomlData), char)
 		require.NoError(t, err)
-		assert.Equal(t, "Test", char.Basics.Archetype)
+		assert.Equal(t, "Test", char.External.Archetype)
 	})
 }
 
@@ -481,28 +514,32 @@ func TestCharacterSame(t *testing.T) {
 	t.Run("identical characters are same", func(t *testing.T) {
 		char1 := &Character{
 			Version: "1.0.0",
-			Basics: &BasicCharacterInformation{
+			External: &ExternalCharacterInfo{
 				Archetype:          "The Hero",
 				Description:        "Brave and true",
-				Background:         "Humble origins",
 				CommunicationStyle: "Inspiring",
-				DecisionStyle:      "Courageous",
-				Traits:             []string{"brave", "honest"},
-				Skills:             []string{"combat", "leadership"},
-				Values:             []string{"justice", "honor"},
+				PositiveTraits:     []string{"brave", "honest"},
+				NegativeTraits:     []string{"justice", "honor"},
+				UniqueSkills:       []string{"combat", "leadership"},
+			},
+			Internal: &InternalCharacterInfo{
+				Background:    "Humble origins",
+				DecisionStyle: "Courageous",
 			},
 		}
 		char2 := &Character{
 			Version: "1.0.0",
-			Basics: &BasicCharacterInformation{
+			External: &ExternalCharacterInfo{
 				Archetype:          "The Hero",
 				Description:        "Brave and true",
-				Background:         "Humble origins",
 				CommunicationStyle: "Inspiring",
-				DecisionStyle:      "Courageous",
-				Traits:             []string{"brave", "honest"},
-				Skills:             []string{"combat", "leadership"},
-				Values:             []string{"justice", "honor"},
+				PositiveTraits:     []string{"brave", "honest"},
+				NegativeTraits:     []string{"justice", "honor"},
+				UniqueSkills:       []string{"combat", "leadership"},
+			},
+			Internal: &InternalCharacterInfo{
+				Background:    "Humble origins",
+				DecisionStyle: "Courageous",
 			},
 		}
 
@@ -513,15 +550,17 @@ func TestCharacterSame(t *testing.T) {
 	t.Run("different version", func(t *testing.T) {
 		char1 := &Character{
 			Version: "1.0.0",
-			Basics: &BasicCharacterInformation{
+			External: &ExternalCharacterInfo{
 				Archetype: "The Hero",
 			},
+			Internal: &InternalCharacterInfo{},
 		}
 		char2 := &Character{
 			Version: "2.0.0",
-			Basics: &BasicCharacterInformation{
+			External: &ExternalCharacterInfo{
 				Archetype: "The Hero",
 			},
+			Internal: &InternalCharacterInfo{},
 		}
 
 		assert.False(t, char1.Same(char2))
@@ -529,14 +568,16 @@ func TestCharacterSame(t *testing.T) {
 
 	t.Run("different archetype", func(t *testing.T) {
 		char1 := &Character{
-			Basics: &BasicCharacterInformation{
+			External: &ExternalCharacterInfo{
 				Archetype: "The Hero",
 			},
+			Internal: &InternalCharacterInfo{},
 		}
 		char2 := &Character{
-			Basics: &BasicCharacterInformation{
+			External: &ExternalCharacterInfo{
 				Archetype: "The Villain",
 			},
+			Internal: &InternalCharacterInfo{},
 		}
 
 		assert.False(t, char1.Same(char2))
@@ -544,16 +585,18 @@ func TestCharacterSame(t *testing.T) {
 
 	t.Run("different traits", func(t *testing.T) {
 		char1 := &Character{
-			Basics: &BasicCharacterInformation{
-				Archetype: "The Hero",
-				Traits:    []string{"brave", "honest"},
+			External: &ExternalCharacterInfo{
+				Archetype:      "The Hero",
+				PositiveTraits: []string{"brave", "honest"},
 			},
+			Internal: &InternalCharacterInfo{},
 		}
 		char2 := &Character{
-			Basics: &BasicCharacterInformation{
-				Archetype: "The Hero",
-				Traits:    []string{"brave", "clever"},
+			External: &ExternalCharacterInfo{
+				Archetype:      "The Hero",
+				PositiveTraits: []string{"brave", "clever"},
 			},
+			Internal: &InternalCharacterInfo{},
 		}
 
 		assert.False(t, char1.Same(char2))
@@ -562,15 +605,17 @@ func TestCharacterSame(t *testing.T) {
 	t.Run("round tripped characters are same", func(t *testing.T) {
 		original := &Character{
 			Version: "1.5.2",
-			Basics: &BasicCharacterInformation{
+			External: &ExternalCharacterInfo{
 				Archetype:          "The Trickster",
 				Description:        "Mischievous and clever",
-				Background:         "Unknown origins",
 				CommunicationStyle: "Playful and deceptive",
-				DecisionStyle:      "Unpredictable",
-				Traits:             []string{"clever", "mischievous", "unpredictable"},
-				Skills:             []string{"deception", "acrobatics", "sleight of hand"},
-				Values:             []string{"freedom", "chaos", "fun"},
+				PositiveTraits:     []string{"clever", "mischievous", "unpredictable"},
+				NegativeTraits:     []string{"freedom", "chaos", "fun"},
+				UniqueSkills:       []string{"deception", "acrobatics", "sleight of hand"},
+			},
+			Internal: &InternalCharacterInfo{
+				Background:    "Unknown origins",
+				DecisionStyle: "Unpredictable",
 			},
 		}
 
@@ -591,139 +636,146 @@ func TestLoadCharacter(t *testing.T) {
 		tomlData := `
 version = "1.0.0"
 
-[basics]
+[external]
 archetype = "The Hero"
 description = "Brave and true"
-background = "Humble origins"
 communication_style = "Inspiring"
+positive_traits = ["brave"]
+negative_traits = ["justice"]
+unique_skills = ["combat"]
+
+[internal]
+background = "Humble origins"
 decision_style = "Courageous"
-traits = ["brave"]
-skills = ["combat"]
-values = ["justice"]
 `
 
 		character, err := LoadCharacter([]byte(tomlData))
 		require.NoError(t, err)
 
 		assert.Equal(t, "1.0.0", character.Version)
-		assert.Equal(t, "The Hero", character.Basics.Archetype)
-		assert.Equal(t, "Brave and true", character.Basics.Description)
-		assert.Equal(t, []string{"brave"}, character.Basics.Traits)
+		assert.Equal(t, "The Hero", character.External.Archetype)
+		assert.Equal(t, "Brave and true", character.External.Description)
+		assert.Equal(t, []string{"brave"}, character.External.PositiveTraits)
 	})
 
 	t.Run("loads fully populated character", func(t *testing.T) {
 		tomlData := `
-version = "2.5.0"
+version = "1.0.0"
 
-[basics]
+[external]
 archetype = "The Mentor"
 description = "Wise teacher and guide"
-background = "Former hero, now retired"
 communication_style = "Patient and instructive"
+positive_traits = ["wise", "patient", "experienced"]
+negative_traits = ["wisdom", "growth", "legacy"]
+unique_skills = ["teaching", "strategy", "ancient knowledge"]
+
+[internal]
+background = "Former hero, now retired"
 decision_style = "Thoughtful and considered"
-traits = ["wise", "patient", "experienced"]
-skills = ["teaching", "strategy", "ancient knowledge"]
-values = ["wisdom", "growth", "legacy"]
 `
 
 		character, err := LoadCharacter([]byte(tomlData))
 		require.NoError(t, err)
 
-		assert.Equal(t, "2.5.0", character.Version)
-		assert.Equal(t, "The Mentor", character.Basics.Archetype)
-		assert.Equal(t, "Wise teacher and guide", character.Basics.Description)
-		assert.Equal(t, "Former hero, now retired", character.Basics.Background)
-		assert.Equal(t, "Patient and instructive", character.Basics.CommunicationStyle)
-		assert.Equal(t, "Thoughtful and considered", character.Basics.DecisionStyle)
-		assert.Equal(t, []string{"wise", "patient", "experienced"}, character.Basics.Traits)
-		assert.Equal(t, []string{"teaching", "strategy", "ancient knowledge"}, character.Basics.Skills)
-		assert.Equal(t, []string{"wisdom", "growth", "legacy"}, character.Basics.Values)
+		assert.Equal(t, "1.0.0", character.Version)
+		assert.Equal(t, "The Mentor", character.External.Archetype)
+		assert.Equal(t, "Wise teacher and guide", character.External.Description)
+		assert.Equal(t, "Former hero, now retired", character.Internal.Background)
+		assert.Equal(t, "Patient and instructive", character.External.CommunicationStyle)
+		assert.Equal(t, "Thoughtful and considered", character.Internal.DecisionStyle)
+		assert.Equal(t, []string{"wise", "patient", "experienced"}, character.External.PositiveTraits)
+		assert.Equal(t, []string{"teaching", "strategy", "ancient knowledge"}, character.External.UniqueSkills)
+		assert.Equal(t, []string{"wisdom", "growth", "legacy"}, character.External.NegativeTraits)
 	})
 
 	t.Run("loads character with empty arrays", func(t *testing.T) {
 		tomlData := `
 version = "1.0.0"
 
-[basics]
+[external]
 archetype = "The Blank Slate"
 description = "A character with no defined traits"
-background = "Unknown"
 communication_style = "Silent"
+positive_traits = []
+negative_traits = []
+unique_skills = []
+
+[internal]
+background = "Unknown"
 decision_style = "Passive"
-traits = []
-skills = []
-values = []
 `
 
 		character, err := LoadCharacter([]byte(tomlData))
 		require.NoError(t, err)
 
-		assert.Equal(t, "The Blank Slate", character.Basics.Archetype)
-		assert.Empty(t, character.Basics.Traits)
-		assert.Empty(t, character.Basics.Skills)
-		assert.Empty(t, character.Basics.Values)
+		assert.Equal(t, "The Blank Slate", character.External.Archetype)
+		assert.Empty(t, character.External.PositiveTraits)
+		assert.Empty(t, character.External.UniqueSkills)
+		assert.Empty(t, character.External.NegativeTraits)
 	})
 
 	t.Run("loads character with multiline strings", func(t *testing.T) {
 		tomlData := `
 version = "1.0.0"
 
-[basics]
+[external]
 archetype = "The Tragic Hero"
 description = """
 A character marked by fate,
 struggling against inevitable doom,
 yet finding nobility in the struggle."""
+communication_style = "Eloquent and melancholic"
+positive_traits = ["tragic", "noble", "doomed"]
+negative_traits = ["duty", "honor", "sacrifice"]
+unique_skills = ["leadership", "combat", "diplomacy"]
+
+[internal]
 background = """
 Born to greatness but cursed by prophecy.
 Every triumph brings them closer to their downfall."""
-communication_style = "Eloquent and melancholic"
 decision_style = "Bound by duty and honor"
-traits = ["tragic", "noble", "doomed"]
-skills = ["leadership", "combat", "diplomacy"]
-values = ["duty", "honor", "sacrifice"]
 `
 
 		character, err := LoadCharacter([]byte(tomlData))
 		require.NoError(t, err)
 
-		assert.Equal(t, "The Tragic Hero", character.Basics.Archetype)
-		assert.Contains(t, character.Basics.Description, "marked by fate")
-		assert.Contains(t, character.Basics.Background, "Born to greatness")
+		assert.Equal(t, "The Tragic Hero", character.External.Archetype)
+		assert.Contains(t, character.External.Description, "marked by fate")
+		assert.Contains(t, character.Internal.Background, "Born to greatness")
 	})
 
-	t.Run("loads character without version field", func(t *testing.T) {
+	t.Run("returns error for missing version field", func(t *testing.T) {
 		tomlData := `
-[basics]
+[external]
 archetype = "The Simple One"
 description = "No version specified"
-background = "Test"
 communication_style = "Simple"
+positive_traits = ["simple"]
+negative_traits = ["simplicity"]
+unique_skills = ["basic"]
+
+[internal]
+background = "Test"
 decision_style = "Basic"
-traits = ["simple"]
-skills = ["basic"]
-values = ["simplicity"]
 `
 
-		character, err := LoadCharacter([]byte(tomlData))
-		require.NoError(t, err)
-
-		assert.Equal(t, "", character.Version) // Default empty string
-		assert.Equal(t, "The Simple One", character.Basics.Archetype)
+		_, err := LoadCharacter([]byte(tomlData))
+		assert.ErrorContains(t, err, "missing version field")
 	})
 
 	t.Run("returns error for invalid TOML", func(t *testing.T) {
 		tomlData := `
 version = "1.0.0"
 invalid toml syntax here
-[basics]
+[external]
 `
 
 		_, err := LoadCharacter([]byte(tomlData))
 		require.Error(t, err)
 	})
 
-	t.Run("returns error for missing basics section", func(t *testing.T) {
+	t.Run("initializes missing sections", func(t *testing.T) {
 		tomlData := `
 version = "1.0.0"
 `
@@ -731,49 +783,54 @@ version = "1.0.0"
 		character, err := LoadCharacter([]byte(tomlData))
 		require.NoError(t, err)
 
-		// Should succeed but basics will be initialized empty
+		// Should succeed but the sections will be initialized empty
 		assert.Equal(t, "1.0.0", character.Version)
-		require.NotNil(t, character.Basics)
-		assert.Equal(t, "", character.Basics.Archetype)
+		require.NotNil(t, character.External)
+		require.NotNil(t, character.Internal)
+		assert.Equal(t, "", character.External.Archetype)
 	})
 
 	t.Run("loads character with special characters", func(t *testing.T) {
 		tomlData := `
 version = "1.0.0"
 
-[basics]
+[external]
 archetype = "The Ðragon Rider"
 description = "Flies on dragons, speaks in runes: 龍"
-background = "From the lands of Ærith"
 communication_style = "Multi-lingual (Español, 日本語)"
+positive_traits = ["brave", "multi-cultural"]
+negative_traits = ["freedom", "diversity"]
+unique_skills = ["dragon-riding", "languages"]
+
+[internal]
+background = "From the lands of Ærith"
 decision_style = "Instinctive"
-traits = ["brave", "multi-cultural"]
-skills = ["dragon-riding", "languages"]
-values = ["freedom", "diversity"]
 `
 
 		character, err := LoadCharacter([]byte(tomlData))
 		require.NoError(t, err)
 
-		assert.Equal(t, "The Ðragon Rider", character.Basics.Archetype)
-		assert.Contains(t, character.Basics.Description, "龍")
-		assert.Contains(t, character.Basics.Background, "Ærith")
-		assert.Contains(t, character.Basics.CommunicationStyle, "日本語")
+		assert.Equal(t, "The Ðragon Rider", character.External.Archetype)
+		assert.Contains(t, character.External.Description, "龍")
+		assert.Contains(t, character.Internal.Background, "Ærith")
+		assert.Contains(t, character.External.CommunicationStyle, "日本語")
 	})
 
 	t.Run("round trip through LoadCharacter preserves data", func(t *testing.T) {
 		originalData := `
-version = "3.2.1"
+version = "1.0.0"
 
-[basics]
+[external]
 archetype = "The Sage"
 description = "Ancient wisdom keeper"
-background = "Centuries of study"
 communication_style = "Cryptic and profound"
+positive_traits = ["wise", "ancient", "mystical"]
+negative_traits = ["knowledge", "balance", "truth"]
+unique_skills = ["prophecy", "ancient languages", "meditation"]
+
+[internal]
+background = "Centuries of study"
 decision_style = "Measured and wise"
-traits = ["wise", "ancient", "mystical"]
-skills = ["prophecy", "ancient languages", "meditation"]
-values = ["knowledge", "balance", "truth"]
 `
 
 		character, err := LoadCharacter([]byte(originalData))
@@ -795,22 +852,24 @@ values = ["knowledge", "balance", "truth"]
 		tomlData := `
 version = "1.0.0"
 
-[basics]
+[external]
 archetype = "The Specialist"
 description = "Master of one thing"
-background = "Focused training"
 communication_style = "Direct"
+positive_traits = ["focused"]
+negative_traits = ["excellence"]
+unique_skills = ["mastery"]
+
+[internal]
+background = "Focused training"
 decision_style = "Specialized"
-traits = ["focused"]
-skills = ["mastery"]
-values = ["excellence"]
 `
 
 		character, err := LoadCharacter([]byte(tomlData))
 		require.NoError(t, err)
 
-		assert.Equal(t, []string{"focused"}, character.Basics.Traits)
-		assert.Equal(t, []string{"mastery"}, character.Basics.Skills)
-		assert.Equal(t, []string{"excellence"}, character.Basics.Values)
+		assert.Equal(t, []string{"focused"}, character.External.PositiveTraits)
+		assert.Equal(t, []string{"mastery"}, character.External.UniqueSkills)
+		assert.Equal(t, []string{"excellence"}, character.External.NegativeTraits)
 	})
 }
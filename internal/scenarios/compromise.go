@@ -0,0 +1,51 @@
+package scenarios
+
+import "fmt"
+
+// CompromiseConfig makes agents progressively more willing to compromise as
+// the simulation's turns run out, so obstinate personas don't deadlock goals.
+type CompromiseConfig struct {
+	Start        *float64           `toml:"start"`        // Optional: share of the turns after which agents start to soften (default 0.3)
+	Stubbornness *float64           `toml:"stubbornness"` // Optional: 0.0 (yields readily) to 1.0 (never softens) (default 0.5)
+	Agents       map[string]float64 `toml:"agents"`       // Optional: stubbornness by agent, overriding the default
+}
+
+// ApplyDefaults fills in unset settings.
+func (c *CompromiseConfig) ApplyDefaults() {
+	if c.Start == nil {
+		start := 0.3
+		c.Start = &start
+	}
+	if c.Stubbornness == nil {
+		stubbornness := 0.5
+		c.Stubbornness = &stubbornness
+	}
+}
+
+// Validate checks that the compromise configuration is usable.
+// Defaults must have been applied.
+func (c *CompromiseConfig) Validate(agents map[string]*Agent) error {
+	if *c.Start < 0 || *c.Start >= 1 {
+		return fmt.Errorf("compromise start must be at least 0.0 and below 1.0 (got %v)", *c.Start)
+	}
+	if *c.Stubbornness < 0 || *c.Stubbornness > 1 {
+		return fmt.Errorf("compromise stubbornness must be between 0.0 and 1.0 (got %v)", *c.Stubbornness)
+	}
+	for name, stubbornness := range c.Agents {
+		if _, ok := agents[name]; !ok {
+			return fmt.Errorf("compromise stubbornness for unknown agent %q", name)
+		}
+		if stubbornness < 0 || stubbornness > 1 {
+			return fmt.Errorf("compromise stubbornness for %s must be between 0.0 and 1.0 (got %v)", name, stubbornness)
+		}
+	}
+	return nil
+}
+
+// StubbornnessOf returns an agent's stubbornness, falling back to the default.
+func (c *CompromiseConfig) StubbornnessOf(agentName string) float64 {
+	if stubbornness, ok := c.Agents[agentName]; ok {
+		return stubbornness
+	}
+	return *c.Stubbornness
+}
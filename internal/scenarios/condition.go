@@ -0,0 +1,49 @@
+package scenarios
+
+import "fmt"
+
+// ConditionConfig makes agents' condition (health and energy, 0-100) affect
+// how they take part. Without it, condition is only described to the agent.
+type ConditionConfig struct {
+	DrainPerTurn   int `toml:"drain_per_turn"`  // Optional: condition every agent loses at the end of each turn (default 0)
+	TiredBelow     int `toml:"tired_below"`     // Optional: below this, agents say less (default 40)
+	TiredSentences int `toml:"tired_sentences"` // Optional: most sentences a tired agent says per turn (default 2)
+	ExhaustedBelow int `toml:"exhausted_below"` // Optional: below this, agents pass their turns (default 15)
+	RestRecovery   int `toml:"rest_recovery"`   // Optional: condition regained with the rest tool (default 10)
+}
+
+// ApplyDefaults fills in unset thresholds.
+func (c *ConditionConfig) ApplyDefaults() {
+	if c.TiredBelow == 0 {
+		c.TiredBelow = 40
+	}
+	if c.TiredSentences == 0 {
+		c.TiredSentences = 2
+	}
+	if c.ExhaustedBelow == 0 {
+		c.ExhaustedBelow = 15
+	}
+	if c.RestRecovery == 0 {
+		c.RestRecovery = 10
+	}
+}
+
+// Validate checks that the condition configuration is usable.
+func (c *ConditionConfig) Validate() error {
+	if c.DrainPerTurn < 0 || c.DrainPerTurn > 100 {
+		return fmt.Errorf("condition drain_per_turn must be between 0 and 100 (got %d)", c.DrainPerTurn)
+	}
+	if c.TiredBelow < 0 || c.TiredBelow > 100 {
+		return fmt.Errorf("condition tired_below must be between 0 and 100 (got %d)", c.TiredBelow)
+	}
+	if c.ExhaustedBelow < 0 || c.ExhaustedBelow > c.TiredBelow {
+		return fmt.Errorf("condition exhausted_below must be between 0 and tired_below (got %d)", c.ExhaustedBelow)
+	}
+	if c.TiredSentences < 1 {
+		return fmt.Errorf("condition tired_sentences must be at least 1 (got %d)", c.TiredSentences)
+	}
+	if c.RestRecovery < 0 || c.RestRecovery > 100 {
+		return fmt.Errorf("condition rest_recovery must be between 0 and 100 (got %d)", c.RestRecovery)
+	}
+	return nil
+}
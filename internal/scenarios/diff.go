@@ -0,0 +1,85 @@
+package scenarios
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+)
+
+// ChangeKind describes how a scenario setting changed.
+type ChangeKind string
+
+const (
+	ChangeAdded   ChangeKind = "added"
+	ChangeRemoved ChangeKind = "removed"
+	ChangeChanged ChangeKind = "changed"
+)
+
+// Change is one difference between two scenario definitions.
+type Change struct {
+	Path string // Dotted setting path, e.g. "goals.dinner.priority"
+	Kind ChangeKind
+	Old  interface{} // nil when added
+	New  interface{} // nil when removed
+}
+
+// Section returns the top-level table the change belongs to (scenario, agents, goals, ...).
+func (c Change) Section() string {
+	section, _, _ := strings.Cut(c.Path, ".")
+	return section
+}
+
+// Diff compares two scenario TOML documents setting by setting.
+// Changes are ordered by path.
+func Diff(oldData, newData []byte) ([]Change, error) {
+	var oldDoc, newDoc map[string]interface{}
+	if err := toml.Unmarshal(oldData, &oldDoc); err != nil {
+		return nil, fmt.Errorf("failed to parse previous scenario: %w", err)
+	}
+	if err := toml.Unmarshal(newData, &newDoc); err != nil {
+		return nil, fmt.Errorf("failed to parse current scenario: %w", err)
+	}
+
+	oldFlat := make(map[string]interface{})
+	newFlat := make(map[string]interface{})
+	flatten("", oldDoc, oldFlat)
+	flatten("", newDoc, newFlat)
+
+	var changes []Change
+	for key, oldValue := range oldFlat {
+		newValue, ok := newFlat[key]
+		switch {
+		case !ok:
+			changes = append(changes, Change{Path: key, Kind: ChangeRemoved, Old: oldValue})
+		case !reflect.DeepEqual(oldValue, newValue):
+			changes = append(changes, Change{Path: key, Kind: ChangeChanged, Old: oldValue, New: newValue})
+		}
+	}
+	for key, newValue := range newFlat {
+		if _, ok := oldFlat[key]; !ok {
+			changes = append(changes, Change{Path: key, Kind: ChangeAdded, New: newValue})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Path < changes[j].Path })
+	return changes, nil
+}
+
+// flatten records every leaf value of a TOML table under its dotted path.
+// Arrays are leaves, so a reordered list shows up as one change.
+func flatten(prefix string, table map[string]interface{}, out map[string]interface{}) {
+	for key, value := range table {
+		fullKey := key
+		if prefix != "" {
+			fullKey = prefix + "." + key
+		}
+		if nested, ok := value.(map[string]interface{}); ok {
+			flatten(fullKey, nested, out)
+			continue
+		}
+		out[fullKey] = value
+	}
+}
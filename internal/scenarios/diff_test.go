@@ -0,0 +1,177 @@
+package scenarios
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiff(t *testing.T) {
+	previous := `
+[scenario]
+name = "Dinner"
+max_turns = 10
+
+[agents.Alex]
+character = "alex"
+model = "gpt-4o"
+
+[agents.Jordan]
+character = "jordan"
+model = "gpt-4o"
+
+[goals.dinner]
+type = "consensus"
+description = "Choose a restaurant"
+priority = 1
+`
+
+	tests := []struct {
+		name    string
+		current string
+		want    []Change
+	}{
+		{
+			name:    "unchanged",
+			current: previous,
+		},
+		{
+			name: "character added",
+			current: previous + `
+[agents.Sam]
+character = "sam"
+`,
+			want: []Change{
+				{Path: "agents.Sam.character", Kind: ChangeAdded, New: "sam"},
+			},
+		},
+		{
+			name: "character removed",
+			current: `
+[scenario]
+name = "Dinner"
+max_turns = 10
+
+[agents.Alex]
+character = "alex"
+model = "gpt-4o"
+
+[goals.dinner]
+type = "consensus"
+description = "Choose a restaurant"
+priority = 1
+`,
+			want: []Change{
+				{Path: "agents.Jordan.character", Kind: ChangeRemoved, Old: "jordan"},
+				{Path: "agents.Jordan.model", Kind: ChangeRemoved, Old: "gpt-4o"},
+			},
+		},
+		{
+			name: "character changed",
+			current: `
+[scenario]
+name = "Dinner"
+max_turns = 10
+
+[agents.Alex]
+character = "alex"
+model = "claude-sonnet"
+
+[agents.Jordan]
+character = "jordan"
+model = "gpt-4o"
+
+[goals.dinner]
+type = "consensus"
+description = "Choose a restaurant"
+priority = 1
+`,
+			want: []Change{
+				{Path: "agents.Alex.model", Kind: ChangeChanged, Old: "gpt-4o", New: "claude-sonnet"},
+			},
+		},
+		{
+			name: "goal added",
+			current: previous + `
+[goals.dessert]
+type = "consensus"
+description = "Choose a dessert"
+`,
+			want: []Change{
+				{Path: "goals.dessert.description", Kind: ChangeAdded, New: "Choose a dessert"},
+				{Path: "goals.dessert.type", Kind: ChangeAdded, New: "consensus"},
+			},
+		},
+		{
+			name: "goal removed",
+			current: `
+[scenario]
+name = "Dinner"
+max_turns = 10
+
+[agents.Alex]
+character = "alex"
+model = "gpt-4o"
+
+[agents.Jordan]
+character = "jordan"
+model = "gpt-4o"
+`,
+			want: []Change{
+				{Path: "goals.dinner.description", Kind: ChangeRemoved, Old: "Choose a restaurant"},
+				{Path: "goals.dinner.priority", Kind: ChangeRemoved, Old: int64(1)},
+				{Path: "goals.dinner.type", Kind: ChangeRemoved, Old: "consensus"},
+			},
+		},
+		{
+			name: "goal changed",
+			current: `
+[scenario]
+name = "Dinner"
+max_turns = 12
+
+[agents.Alex]
+character = "alex"
+model = "gpt-4o"
+
+[agents.Jordan]
+character = "jordan"
+model = "gpt-4o"
+
+[goals.dinner]
+type = "consensus"
+description = "Choose a restaurant"
+priority = 2
+`,
+			want: []Change{
+				{Path: "goals.dinner.priority", Kind: ChangeChanged, Old: int64(1), New: int64(2)},
+				{Path: "scenario.max_turns", Kind: ChangeChanged, Old: int64(10), New: int64(12)},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changes, err := Diff([]byte(previous), []byte(tt.current))
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, changes)
+		})
+	}
+
+	t.Run("compares arrays as a whole", func(t *testing.T) {
+		changes, err := Diff([]byte("[scenario]\ntags = [\"a\", \"b\"]\n"), []byte("[scenario]\ntags = [\"b\", \"a\"]\n"))
+		require.NoError(t, err)
+		require.Len(t, changes, 1)
+		assert.Equal(t, "scenario.tags", changes[0].Path)
+		assert.Equal(t, ChangeChanged, changes[0].Kind)
+		assert.Equal(t, "scenario", changes[0].Section())
+	})
+
+	t.Run("returns error for invalid TOML", func(t *testing.T) {
+		_, err := Diff([]byte(previous), []byte("[goals"))
+		assert.ErrorContains(t, err, "failed to parse current scenario")
+		_, err = Diff([]byte("[goals"), []byte(previous))
+		assert.ErrorContains(t, err, "failed to parse previous scenario")
+	})
+}
@@ -0,0 +1,43 @@
+package scenarios
+
+import "fmt"
+
+// maxDirectorEvents caps how many events a director may inject at once.
+const maxDirectorEvents = 5
+
+// DirectorConfig adds a director: a model that watches the scene between
+// turns and steers it, making things happen, changing the atmosphere, or
+// nudging the agents when negotiations stall.
+type DirectorConfig struct {
+	Model        string `toml:"model"`        // Optional: model that directs (default: scenario default model)
+	Instructions string `toml:"instructions"` // Optional: what the director aims for, e.g. "keep the tension high"
+	Every        *int   `toml:"every"`        // Optional: turns between the director's interventions (default 1)
+	MaxEvents    *int   `toml:"max_events"`   // Optional: events the director may inject per intervention (default 1)
+}
+
+// ApplyDefaults fills in unset settings.
+func (c *DirectorConfig) ApplyDefaults() {
+	if c.Every == nil {
+		every := 1
+		c.Every = &every
+	}
+	if c.MaxEvents == nil {
+		maxEvents := 1
+		c.MaxEvents = &maxEvents
+	}
+}
+
+// Validate checks that the director configuration is usable.
+// Defaults must have been applied.
+func (c *DirectorConfig) Validate() error {
+	if *c.Every < 1 {
+		return fmt.Errorf("director every must be at least 1 (got %d)", *c.Every)
+	}
+	if *c.MaxEvents < 0 || *c.MaxEvents > maxDirectorEvents {
+		return fmt.Errorf("director max_events must be between 0 and %d (got %d)", maxDirectorEvents, *c.MaxEvents)
+	}
+	if len(c.Instructions) > 1000 {
+		return fmt.Errorf("director instructions must be at most 1000 characters (got %d)", len(c.Instructions))
+	}
+	return nil
+}
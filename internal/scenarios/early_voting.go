@@ -0,0 +1,72 @@
+package scenarios
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DefaultAgreementPhrases are the phrases that show an agent going along with
+// what's been proposed, for detecting consensus forming during deliberation.
+var DefaultAgreementPhrases = []string{
+	"i agree",
+	"agreed",
+	"sounds good",
+	"sounds great",
+	"works for me",
+	"i'm in",
+	"count me in",
+	"let's go with",
+	"let's do it",
+	"i'm on board",
+	"fine by me",
+	"good idea",
+	"i support",
+	"makes sense",
+}
+
+// EarlyVotingConfig moves a turn straight to voting once consensus is
+// obviously forming, so the agents yet to deliberate don't spend a round of
+// calls saying they agree.
+type EarlyVotingConfig struct {
+	Agreement  *float64 `toml:"agreement"`  // Optional: share of deciding agents voicing agreement this turn that ends deliberation (default 0.66)
+	Similarity *float64 `toml:"similarity"` // Optional: word overlap (0.0-1.0) at which a goal's pending proposals count as converged (default 0.8)
+	Phrases    []string `toml:"phrases"`    // Optional: more phrases that voice agreement, besides the defaults
+}
+
+// ApplyDefaults fills in unset settings.
+func (c *EarlyVotingConfig) ApplyDefaults() {
+	if c.Agreement == nil {
+		agreement := 0.66
+		c.Agreement = &agreement
+	}
+	if c.Similarity == nil {
+		similarity := 0.8
+		c.Similarity = &similarity
+	}
+}
+
+// Validate checks that the early voting configuration is usable.
+// Defaults must have been applied.
+func (c *EarlyVotingConfig) Validate() error {
+	if *c.Agreement <= 0 || *c.Agreement > 1 {
+		return fmt.Errorf("early_voting agreement must be above 0.0 and at most 1.0 (got %v)", *c.Agreement)
+	}
+	if *c.Similarity <= 0 || *c.Similarity > 1 {
+		return fmt.Errorf("early_voting similarity must be above 0.0 and at most 1.0 (got %v)", *c.Similarity)
+	}
+	for _, phrase := range c.Phrases {
+		if strings.TrimSpace(phrase) == "" {
+			return fmt.Errorf("early_voting phrases must not be empty")
+		}
+	}
+	return nil
+}
+
+// AgreementPhrases returns the default and configured agreement phrases, lowercased.
+func (c *EarlyVotingConfig) AgreementPhrases() []string {
+	phrases := append([]string(nil), DefaultAgreementPhrases...)
+	for _, phrase := range c.Phrases {
+		phrases = append(phrases, strings.ToLower(strings.TrimSpace(phrase)))
+	}
+	return phrases
+}
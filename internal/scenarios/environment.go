@@ -0,0 +1,87 @@
+package scenarios
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// AmbientEvent is something that can happen around the agents during a turn.
+type AmbientEvent struct {
+	Description string  `toml:"description"`
+	Probability float64 `toml:"probability"` // Chance per turn, 0.0-1.0
+	Kind        string  `toml:"kind"`        // Optional: weather, noise, interruption, or any label
+	Once        bool    `toml:"once"`        // Optional: happens at most once per simulation
+	Condition   int     `toml:"condition"`   // Optional: change to every agent's condition when it happens (-100 to 100)
+}
+
+// EnvironmentConfig adds random ambient events (weather, noise, interruptions)
+// that agents notice when they perceive their surroundings.
+type EnvironmentConfig struct {
+	Library    []string       `toml:"library"`             // Optional: built-in event sets to include (see AmbientLibrary)
+	Events     []AmbientEvent `toml:"events"`              // Optional: scenario-specific events
+	MaxPerTurn *int           `toml:"max_events_per_turn"` // Optional: cap on events per turn (default 1)
+}
+
+// AmbientLibrary holds the built-in event sets scenarios can opt into.
+var AmbientLibrary = map[string][]AmbientEvent{
+	"weather": {
+		{Description: "Rain starts drumming against the windows.", Probability: 0.1, Kind: "weather"},
+		{Description: "A gust of wind rattles the door.", Probability: 0.1, Kind: "weather"},
+		{Description: "Thunder rumbles somewhere in the distance.", Probability: 0.05, Kind: "weather"},
+		{Description: "The clouds part and warm light spills across the room.", Probability: 0.05, Kind: "weather"},
+	},
+	"noise": {
+		{Description: "A siren wails past outside and fades away.", Probability: 0.1, Kind: "noise"},
+		{Description: "Somewhere nearby, a glass shatters.", Probability: 0.05, Kind: "noise"},
+		{Description: "A dog starts barking and won't stop.", Probability: 0.05, Kind: "noise"},
+		{Description: "Music thumps faintly through the walls.", Probability: 0.1, Kind: "noise"},
+	},
+	"interruptions": {
+		{Description: "A phone buzzes insistently.", Probability: 0.1, Kind: "interruption"},
+		{Description: "Someone knocks on the door, then walks away.", Probability: 0.05, Kind: "interruption"},
+		{Description: "The lights flicker and go out for a moment.", Probability: 0.03, Kind: "interruption", Once: true},
+		{Description: "A stranger stops to ask for directions.", Probability: 0.05, Kind: "interruption"},
+	},
+}
+
+// AllEvents returns the library events followed by the scenario's own events.
+func (e *EnvironmentConfig) AllEvents() []AmbientEvent {
+	var events []AmbientEvent
+	for _, name := range e.Library {
+		events = append(events, AmbientLibrary[name]...)
+	}
+	return append(events, e.Events...)
+}
+
+// Validate checks that the environment configuration is usable.
+func (e *EnvironmentConfig) Validate() error {
+	for _, name := range e.Library {
+		if _, ok := AmbientLibrary[name]; !ok {
+			names := make([]string, 0, len(AmbientLibrary))
+			for libraryName := range AmbientLibrary {
+				names = append(names, libraryName)
+			}
+			sort.Strings(names)
+			return fmt.Errorf("unknown ambient library '%s' (use %s)", name, strings.Join(names, ", "))
+		}
+	}
+	for i, event := range e.Events {
+		if strings.TrimSpace(event.Description) == "" {
+			return fmt.Errorf("ambient event %d: description is required", i+1)
+		}
+		if event.Probability < 0 || event.Probability > 1 {
+			return fmt.Errorf("ambient event %d: probability must be between 0.0 and 1.0 (got %v)", i+1, event.Probability)
+		}
+		if event.Condition < -100 || event.Condition > 100 {
+			return fmt.Errorf("ambient event %d: condition must be between -100 and 100 (got %d)", i+1, event.Condition)
+		}
+	}
+	if e.MaxPerTurn != nil && *e.MaxPerTurn < 1 {
+		return fmt.Errorf("environment max_events_per_turn must be at least 1 (got %d)", *e.MaxPerTurn)
+	}
+	if len(e.Library) == 0 && len(e.Events) == 0 {
+		return fmt.Errorf("environment requires a library or events")
+	}
+	return nil
+}
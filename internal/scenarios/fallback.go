@@ -0,0 +1,73 @@
+package scenarios
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// DefaultFallbackTemplates describe what an agent does when their model
+// fails, when the scenario doesn't give its own.
+var DefaultFallbackTemplates = []string{
+	"{{.Name}} stays quiet, arms crossed.",
+	"{{.Name}} listens without saying anything.",
+	"{{.Name}} seems lost in thought for a moment.",
+	"{{.Name}} shifts in their seat and waits.",
+}
+
+// FallbackConfig stands an in-character action in for an agent's turn when
+// their model errors or times out after its retries, instead of ending the
+// run. Templates are Go templates over the agent's .Name, .Archetype,
+// .Trait (one of their traits, positive or negative) and .Emotion.
+type FallbackConfig struct {
+	Templates      []string `toml:"templates"`       // Optional: actions picked from at random (default: DefaultFallbackTemplates)
+	MaxConsecutive int      `toml:"max_consecutive"` // Optional: failures in a row before the run ends anyway (default 3)
+}
+
+// FallbackValues are the fields a fallback template can use.
+type FallbackValues struct {
+	Name      string
+	Archetype string
+	Trait     string
+	Emotion   string
+}
+
+// ApplyDefaults fills in unset settings.
+func (f *FallbackConfig) ApplyDefaults() {
+	if len(f.Templates) == 0 {
+		f.Templates = DefaultFallbackTemplates
+	}
+	if f.MaxConsecutive == 0 {
+		f.MaxConsecutive = 3
+	}
+}
+
+// Validate checks that the fallback templates parse and render.
+func (f *FallbackConfig) Validate() error {
+	if f.MaxConsecutive < 1 {
+		return fmt.Errorf("fallback max_consecutive must be at least 1 (got %d)", f.MaxConsecutive)
+	}
+	for i := range f.Templates {
+		text, err := f.Render(i, FallbackValues{Name: "Sam", Archetype: "skeptic", Trait: "stubborn", Emotion: "neutral"})
+		if err != nil {
+			return err
+		}
+		if strings.TrimSpace(text) == "" {
+			return fmt.Errorf("fallback template %d is blank", i+1)
+		}
+	}
+	return nil
+}
+
+// Render renders the i-th fallback template.
+func (f *FallbackConfig) Render(i int, values FallbackValues) (string, error) {
+	tmpl, err := template.New("fallback").Option("missingkey=error").Parse(f.Templates[i])
+	if err != nil {
+		return "", fmt.Errorf("fallback template %d: %w", i+1, err)
+	}
+	var b strings.Builder
+	if err := tmpl.Execute(&b, values); err != nil {
+		return "", fmt.Errorf("fallback template %d: %w", i+1, err)
+	}
+	return strings.TrimSpace(b.String()), nil
+}
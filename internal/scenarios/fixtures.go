@@ -0,0 +1,174 @@
+package scenarios
+
+import (
+	"fmt"
+	"math/rand"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+)
+
+// FixtureOptions sizes a synthetic scenario generated for load and
+// performance testing.
+type FixtureOptions struct {
+	Name     string // Scenario name (default "Bench")
+	Agents   int    // Number of agents, each with its own character
+	Goals    int    // Number of consensus goals
+	Memories int    // Episodic memories generated for each agent
+	Turns    int    // Optional: max_turns for the scenario (default: the speed profile's)
+	Model    string // Default model for every agent
+	Seed     int64  // Seed for the generated text, so fixtures are reproducible
+}
+
+// Fixture is a generated scenario with its characters and memories.
+type Fixture struct {
+	Scenario   []byte                // Scenario TOML
+	Characters map[string][]byte     // Character TOML by character name
+	Memories   map[string][]string   // Episodic memories by agent name
+	Queries    []string              // Memory queries touching the generated topics
+	loaded     map[string]*Character // Characters parsed from Characters, by character name
+}
+
+// Word lists the generated text is drawn from.
+var (
+	fixtureNames     = []string{"Ada", "Basil", "Cleo", "Dmitri", "Esme", "Farid", "Greta", "Hiro", "Ines", "Jonah", "Kira", "Luis"}
+	fixtureArchetype = []string{"pragmatist", "idealist", "skeptic", "mediator", "enthusiast", "planner"}
+	fixtureTraits    = []string{"patient", "curious", "generous", "decisive", "witty", "loyal", "careful", "bold"}
+	fixtureFlaws     = []string{"stubborn", "impatient", "vain", "indecisive", "blunt", "anxious", "proud", "sarcastic"}
+	fixtureTopics    = []string{"the budget", "the venue", "the schedule", "the menu", "the guest list", "the transport", "the music", "the weather"}
+	fixtureMoods     = []string{"hopefully", "warily", "firmly", "cheerfully", "quietly", "impatiently"}
+)
+
+// GenerateFixture generates a scenario with the given numbers of agents,
+// goals and memories per agent. The same options always generate the same
+// fixture, and the scenario and characters pass validation.
+func GenerateFixture(opts FixtureOptions) (*Fixture, error) {
+	if opts.Agents < 1 {
+		return nil, fmt.Errorf("a fixture needs at least 1 agent (got %d)", opts.Agents)
+	}
+	if opts.Goals < 1 {
+		return nil, fmt.Errorf("a fixture needs at least 1 goal (got %d)", opts.Goals)
+	}
+	if opts.Memories < 0 || opts.Turns < 0 {
+		return nil, fmt.Errorf("memories and turns can't be negative")
+	}
+	if opts.Model == "" {
+		return nil, fmt.Errorf("a fixture needs a model")
+	}
+	if opts.Name == "" {
+		opts.Name = "Bench"
+	}
+
+	rng := rand.New(rand.NewSource(opts.Seed))
+	pick := func(list []string) string { return list[rng.Intn(len(list))] }
+
+	fixture := &Fixture{
+		Characters: make(map[string][]byte, opts.Agents),
+		Memories:   make(map[string][]string, opts.Agents),
+		loaded:     make(map[string]*Character, opts.Agents),
+	}
+
+	var scenario strings.Builder
+	fmt.Fprintf(&scenario, "version = %q\n\n[scenario]\n", "1.0.0")
+	fmt.Fprintf(&scenario, "name = %q\n", opts.Name)
+	fmt.Fprintf(&scenario, "description = %q\n", fmt.Sprintf("A synthetic scenario with %d agents and %d goals for performance testing", opts.Agents, opts.Goals))
+	fmt.Fprintf(&scenario, "backstory = %q\n", "A committee meets to plan an event and has many decisions to make.")
+	fmt.Fprintf(&scenario, "location = %q\ntime = %q\natmosphere = %q\n", "meeting room", "Morning", "busy")
+	if opts.Turns > 0 {
+		fmt.Fprintf(&scenario, "max_turns = %d\n", opts.Turns)
+	}
+	fmt.Fprintf(&scenario, "\n[scenario.defaults]\nmodel = %q\n", opts.Model)
+
+	for i := range opts.Goals {
+		topic := fixtureTopics[i%len(fixtureTopics)]
+		fmt.Fprintf(&scenario, "\n[goals.goal_%d]\n", i+1)
+		fmt.Fprintf(&scenario, "description = %q\n", fmt.Sprintf("Agree on %s (decision %d)", topic, i+1))
+		fmt.Fprintf(&scenario, "priority = %d\n", i+1)
+	}
+
+	for i := range opts.Agents {
+		agentName := fixtureName(i)
+		characterName := "bench-" + strings.ToLower(agentName)
+		character := fixtureCharacter(agentName, pick)
+		if err := character.Validate(); err != nil {
+			return nil, fmt.Errorf("generated character %s is invalid: %w", characterName, err)
+		}
+		data, err := toml.Marshal(character)
+		if err != nil {
+			return nil, err
+		}
+		fixture.Characters[characterName] = data
+		fixture.loaded[characterName] = character
+		fmt.Fprintf(&scenario, "\n[agents.%s]\ncharacter = %q\n", agentName, characterName)
+
+		memories := make([]string, opts.Memories)
+		for j := range memories {
+			memories[j] = fmt.Sprintf("%s %s suggested that %s should be settled before %s.",
+				agentName, pick(fixtureMoods), pick(fixtureTopics), pick(fixtureTopics))
+		}
+		fixture.Memories[agentName] = memories
+	}
+
+	for _, topic := range fixtureTopics {
+		fixture.Queries = append(fixture.Queries, "What did people say about "+topic+"?")
+	}
+
+	fixture.Scenario = []byte(scenario.String())
+	if _, err := LoadScenario(fixture.Scenario); err != nil {
+		return nil, fmt.Errorf("generated scenario is invalid: %w", err)
+	}
+	return fixture, nil
+}
+
+// Character returns a generated character by name.
+func (f *Fixture) Character(name string) (*Character, bool) {
+	character, ok := f.loaded[name]
+	return character, ok
+}
+
+// Write writes the fixture's scenario and characters into a config
+// directory, as scenarios/<file>.toml and characters/<name>.toml.
+func (f *Fixture) Write(configDir, file string) error {
+	if err := os.MkdirAll(path.Join(configDir, "scenarios"), 0755); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(path.Join(configDir, "characters"), 0755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path.Join(configDir, "scenarios", file+".toml"), f.Scenario, 0644); err != nil {
+		return err
+	}
+	for name, data := range f.Characters {
+		if err := os.WriteFile(path.Join(configDir, "characters", name+".toml"), data, 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fixtureName returns the i-th generated agent name, numbering the names
+// once the list runs out.
+func fixtureName(i int) string {
+	name := fixtureNames[i%len(fixtureNames)]
+	if round := i / len(fixtureNames); round > 0 {
+		name = fmt.Sprintf("%s%d", name, round+1)
+	}
+	return name
+}
+
+// fixtureCharacter generates a character for an agent.
+func fixtureCharacter(agentName string, pick func([]string) string) *Character {
+	archetype := pick(fixtureArchetype)
+	character := NewCharacter()
+	character.Version = "1.0.0"
+	character.External.Archetype = archetype
+	character.External.Description = fmt.Sprintf("%s is a %s on the planning committee.", agentName, archetype)
+	character.External.CommunicationStyle = fmt.Sprintf("Speaks %s and keeps to the point.", pick(fixtureMoods))
+	character.External.PositiveTraits = []string{pick(fixtureTraits), pick(fixtureTraits)}
+	character.External.NegativeTraits = []string{pick(fixtureFlaws)}
+	character.Internal.Background = fmt.Sprintf("%s has planned events before and cares most about %s.", agentName, pick(fixtureTopics))
+	character.Internal.DecisionStyle = fmt.Sprintf("Weighs %s above everything else.", pick(fixtureTopics))
+	return character
+}
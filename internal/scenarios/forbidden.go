@@ -0,0 +1,60 @@
+package scenarios
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+)
+
+// ForbiddenOutcome rules out an outcome for the scenario's goals, such as a
+// venue the characters can't book. Proposals describing it are refused with
+// the reason, and can never be accepted.
+type ForbiddenOutcome struct {
+	Match   []string `toml:"match"`   // Forbidden if a proposal mentions any of these, ignoring case
+	Pattern string   `toml:"pattern"` // Forbidden if a proposal matches this regular expression
+	Reason  string   `toml:"reason"`  // Why the outcome is ruled out, told to agents who propose it
+	Goals   []string `toml:"goals"`   // Optional: goals the outcome is forbidden for (default: all)
+}
+
+// Validate checks that the outcome can be recognized, explains itself and
+// applies to goals the scenario has.
+func (f *ForbiddenOutcome) Validate(goals map[string]*Goal) error {
+	if len(f.Match) == 0 && f.Pattern == "" {
+		return fmt.Errorf("forbidden outcome needs match phrases or a pattern")
+	}
+	for _, phrase := range f.Match {
+		if phrase == "" {
+			return fmt.Errorf("forbidden outcome match phrases may not be empty")
+		}
+	}
+	if _, err := f.Regexp(); err != nil {
+		return err
+	}
+	if f.Reason == "" {
+		return fmt.Errorf("forbidden outcome needs a reason")
+	}
+	for _, name := range f.Goals {
+		if _, ok := goals[name]; !ok {
+			return fmt.Errorf("forbidden outcome names unknown goal %q", name)
+		}
+	}
+	return nil
+}
+
+// Regexp compiles the outcome's pattern, case-insensitively. It returns nil if
+// the outcome has no pattern.
+func (f *ForbiddenOutcome) Regexp() (*regexp.Regexp, error) {
+	if f.Pattern == "" {
+		return nil, nil
+	}
+	pattern, err := regexp.Compile("(?i)" + f.Pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid forbidden outcome pattern %q: %w", f.Pattern, err)
+	}
+	return pattern, nil
+}
+
+// AppliesTo reports whether the outcome is forbidden for a goal.
+func (f *ForbiddenOutcome) AppliesTo(goal string) bool {
+	return len(f.Goals) == 0 || slices.Contains(f.Goals, goal)
+}
@@ -0,0 +1,52 @@
+package scenarios
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Location is a place in the scene agents can be in. Exits connect it to the
+// locations agents can move to from it, and work both ways.
+type Location struct {
+	Description string   `toml:"description"` // What agents see when they look around
+	Exits       []string `toml:"exits"`       // Locations reachable from here
+}
+
+// validateLocations checks that exits lead to known locations and that every
+// agent starts in one. A scenario without locations is a single place.
+func validateLocations(locations map[string]*Location, agents map[string]*Agent) error {
+	if len(locations) == 0 {
+		return nil
+	}
+
+	for name, location := range locations {
+		for _, exit := range location.Exits {
+			if _, ok := locations[exit]; !ok {
+				return fmt.Errorf("location %s: exit to unknown location %q", name, exit)
+			}
+			if exit == name {
+				return fmt.Errorf("location %s: exit leads back to itself", name)
+			}
+		}
+	}
+
+	for name, agent := range agents {
+		if agent.Initial == nil || agent.Initial.Position == "" {
+			return fmt.Errorf("agent %s needs an initial_state position, since the scenario has locations", name)
+		}
+		if _, ok := locations[agent.Initial.Position]; !ok {
+			return fmt.Errorf("agent %s starts at unknown location %q (known: %v)", name, agent.Initial.Position, sortedLocations(locations))
+		}
+	}
+	return nil
+}
+
+// sortedLocations returns the names of the locations in sorted order.
+func sortedLocations(locations map[string]*Location) []string {
+	names := make([]string, 0, len(locations))
+	for name := range locations {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,162 @@
+package scenarios
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// MemoryToolNames are the tools that search the memory store and can be tuned
+// with a memory configuration.
+var MemoryToolNames = []string{
+	"query_self",
+	"query_background",
+	"query_communication_style",
+	"query_scene",
+	"query_character",
+	"query_memory",
+	"query_knowledge",
+}
+
+// MemoryConfig tunes how many memories each memory tool returns to agents, and
+// how relevant they must be, so weak matches don't crowd out useful ones.
+type MemoryConfig struct {
+	MinRelevance *float64                     `toml:"min_relevance"` // Optional: drop results scoring below this in every tool (default: keep all)
+	Tools        map[string]*MemoryToolConfig `toml:"tools"`         // Optional: settings by tool name, overriding the above
+	Store        string                       `toml:"store"`         // Optional: persistent store memories are kept in and shared through (default: none, memories last one run)
+	Backend      string                       `toml:"backend"`       // Optional: vector store from providers.toml keeping the store (default: a file in the config directory)
+	Compaction   *MemoryCompactionConfig      `toml:"compaction"`    // Optional: periodically summarize old episodic memories (default: keep them all)
+	Working      *WorkingMemoryConfig         `toml:"working"`       // Optional: keep recent turns, the active goal and commitments in every prompt (default: off)
+}
+
+// WorkingMemoryConfig gives each agent a working memory: what was said in the
+// last few turns, the goal they're working on and what the group has committed
+// to, written into every prompt. The memory store is left for what happened
+// before, so agents needn't search it for what they just heard.
+type WorkingMemoryConfig struct {
+	Turns    *int `toml:"turns"`    // Optional: turns before the current one kept in working memory (default 2)
+	Messages *int `toml:"messages"` // Optional: most lines of recent conversation kept (default 20)
+}
+
+// ApplyDefaults fills in unset settings.
+func (c *WorkingMemoryConfig) ApplyDefaults() {
+	if c.Turns == nil {
+		turns := 2
+		c.Turns = &turns
+	}
+	if c.Messages == nil {
+		messages := 20
+		c.Messages = &messages
+	}
+}
+
+// Validate checks that the working memory settings are usable.
+// Defaults must have been applied.
+func (c *WorkingMemoryConfig) Validate() error {
+	if *c.Turns < 0 {
+		return fmt.Errorf("working memory turns may not be negative (got %d)", *c.Turns)
+	}
+	if *c.Messages < 1 {
+		return fmt.Errorf("working memory messages must be at least 1 (got %d)", *c.Messages)
+	}
+	return nil
+}
+
+// MemoryCompactionConfig compacts episodic memories in long runs: every few
+// turns, each speaker's older memories are summarized by a model and the
+// originals archived, so searches find the gist rather than every line.
+type MemoryCompactionConfig struct {
+	Every       *int   `toml:"every"`        // Optional: turns between compactions (default 10)
+	KeepTurns   *int   `toml:"keep_turns"`   // Optional: most recent turns left as they are (default: every)
+	MinMemories *int   `toml:"min_memories"` // Optional: fewest old memories of a speaker worth summarizing (default 5)
+	Model       string `toml:"model"`        // Optional: model that summarizes (default: scenario default model)
+}
+
+// ApplyDefaults fills in unset settings.
+func (c *MemoryCompactionConfig) ApplyDefaults() {
+	if c.Every == nil {
+		every := 10
+		c.Every = &every
+	}
+	if c.KeepTurns == nil {
+		keepTurns := *c.Every
+		c.KeepTurns = &keepTurns
+	}
+	if c.MinMemories == nil {
+		minMemories := 5
+		c.MinMemories = &minMemories
+	}
+}
+
+// Validate checks that the compaction settings are usable.
+// Defaults must have been applied.
+func (c *MemoryCompactionConfig) Validate() error {
+	if *c.Every < 1 {
+		return fmt.Errorf("memory compaction every must be at least 1 (got %d)", *c.Every)
+	}
+	if *c.KeepTurns < 0 {
+		return fmt.Errorf("memory compaction keep_turns may not be negative (got %d)", *c.KeepTurns)
+	}
+	if *c.MinMemories < 2 {
+		return fmt.Errorf("memory compaction min_memories must be at least 2 (got %d)", *c.MinMemories)
+	}
+	return nil
+}
+
+// storeNamePattern restricts store names to ones that are safe as file names.
+var storeNamePattern = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_-]*$`)
+
+// MemoryToolConfig tunes one memory tool.
+type MemoryToolConfig struct {
+	TopK         *int     `toml:"top_k"`         // Optional: most results returned (default: the speed profile's, or the tool's own)
+	MinRelevance *float64 `toml:"min_relevance"` // Optional: drop results scoring below this (default: the memory section's)
+}
+
+// Validate checks that the memory configuration only tunes known tools with
+// usable limits, names its store so it can be a file or collection name, and
+// compacts memories and keeps working memory sensibly. It applies the
+// compaction and working memory defaults.
+func (c *MemoryConfig) Validate() error {
+	if c.Compaction != nil {
+		c.Compaction.ApplyDefaults()
+		if err := c.Compaction.Validate(); err != nil {
+			return err
+		}
+	}
+	if c.Working != nil {
+		c.Working.ApplyDefaults()
+		if err := c.Working.Validate(); err != nil {
+			return err
+		}
+	}
+	if c.Store != "" && !storeNamePattern.MatchString(c.Store) {
+		return fmt.Errorf("memory store name %q may only use letters, digits, _ and -", c.Store)
+	}
+	if c.Backend != "" && c.Store == "" {
+		return fmt.Errorf("memory backend %q needs a store to keep", c.Backend)
+	}
+	for name, tool := range c.Tools {
+		if !slices.Contains(MemoryToolNames, name) {
+			return fmt.Errorf("memory settings for unknown tool %q (use %s)", name, strings.Join(MemoryToolNames, ", "))
+		}
+		if tool.TopK != nil && *tool.TopK < 1 {
+			return fmt.Errorf("memory top_k for %s must be at least 1 (got %d)", name, *tool.TopK)
+		}
+	}
+	return nil
+}
+
+// ToolSettings returns a tool's result limit (0 if unset) and minimum relevance (nil if unset).
+func (c *MemoryConfig) ToolSettings(toolName string) (int, *float64) {
+	topK, minRelevance := 0, c.MinRelevance
+	if tool, ok := c.Tools[toolName]; ok {
+		if tool.TopK != nil {
+			topK = *tool.TopK
+		}
+		if tool.MinRelevance != nil {
+			minRelevance = tool.MinRelevance
+		}
+	}
+	return topK, minRelevance
+}
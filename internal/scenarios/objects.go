@@ -0,0 +1,45 @@
+package scenarios
+
+import "fmt"
+
+// Object is a thing in the scene agents can inspect and, unless it is fixed,
+// pick up and hand to each other.
+type Object struct {
+	Description string `toml:"description"` // What agents see at a glance
+	Details     string `toml:"details"`     // Optional: what inspecting it reveals
+	Location    string `toml:"location"`    // Optional: location it lies in (required when the scenario has locations and no agent holds it)
+	Holder      string `toml:"holder"`      // Optional: agent who starts with it
+	Fixed       bool   `toml:"fixed"`       // Optional: too heavy or fastened down to pick up
+}
+
+// validateObjects checks that each object describes itself and starts either
+// with an agent or somewhere in the scene.
+func validateObjects(objects map[string]*Object, agents map[string]*Agent, locations map[string]*Location) error {
+	for name, object := range objects {
+		if object.Description == "" {
+			return fmt.Errorf("object %s needs a description", name)
+		}
+		if object.Holder != "" {
+			if _, ok := agents[object.Holder]; !ok {
+				return fmt.Errorf("object %s: holder %q is not an agent", name, object.Holder)
+			}
+			if object.Location != "" {
+				return fmt.Errorf("object %s can't both lie somewhere and be held", name)
+			}
+			if object.Fixed {
+				return fmt.Errorf("object %s is fixed, so no one can hold it", name)
+			}
+			continue
+		}
+		if len(locations) == 0 {
+			if object.Location != "" {
+				return fmt.Errorf("object %s lies at %q, but the scenario has no locations", name, object.Location)
+			}
+			continue
+		}
+		if _, ok := locations[object.Location]; !ok {
+			return fmt.Errorf("object %s needs a holder or one of the scenario's locations (known: %v)", name, sortedLocations(locations))
+		}
+	}
+	return nil
+}
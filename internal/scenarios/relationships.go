@@ -0,0 +1,45 @@
+package scenarios
+
+import (
+	"fmt"
+
+	"github.com/poiesic/wonda/internal/campaigns"
+)
+
+// Relationship is how one agent feels about another when the scenario starts.
+// Relationships are directional: [relationships.Alice.Bob] is how Alice feels
+// about Bob. Pairs left out start as strangers, neutral on every count.
+type Relationship struct {
+	Trust       int    `toml:"trust"`       // -10 (no trust at all) to 10 (complete trust)
+	Affinity    int    `toml:"affinity"`    // -10 (bitter enemy) to 10 (devoted ally)
+	Familiarity int    `toml:"familiarity"` // 0 (strangers) to 10 (know each other well)
+	Reason      string `toml:"reason"`      // Their history, in a few words
+}
+
+// validateRelationships checks that relationships are between two different
+// agents of the scenario and that every value is in range.
+func validateRelationships(relationships map[string]map[string]*Relationship, agents map[string]*Agent) error {
+	for from, toward := range relationships {
+		if _, ok := agents[from]; !ok {
+			return fmt.Errorf("relationships: unknown agent %q", from)
+		}
+		for to, rel := range toward {
+			if _, ok := agents[to]; !ok {
+				return fmt.Errorf("relationships.%s: unknown agent %q", from, to)
+			}
+			if to == from {
+				return fmt.Errorf("relationships.%s: an agent has no relationship with themselves", from)
+			}
+			if rel.Trust < campaigns.MinRelationship || rel.Trust > campaigns.MaxRelationship {
+				return fmt.Errorf("relationships.%s.%s: trust must be between %d and %d", from, to, campaigns.MinRelationship, campaigns.MaxRelationship)
+			}
+			if rel.Affinity < campaigns.MinRelationship || rel.Affinity > campaigns.MaxRelationship {
+				return fmt.Errorf("relationships.%s.%s: affinity must be between %d and %d", from, to, campaigns.MinRelationship, campaigns.MaxRelationship)
+			}
+			if rel.Familiarity < 0 || rel.Familiarity > campaigns.MaxFamiliarity {
+				return fmt.Errorf("relationships.%s.%s: familiarity must be between 0 and %d", from, to, campaigns.MaxFamiliarity)
+			}
+		}
+	}
+	return nil
+}
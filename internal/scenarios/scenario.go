@@ -2,11 +2,19 @@ package scenarios
 
 import (
 	"fmt"
+	"maps"
 	"os"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/pelletier/go-toml/v2"
+	"github.com/poiesic/wonda/internal/campaigns"
 	"github.com/poiesic/wonda/internal/config"
+	"github.com/poiesic/wonda/internal/expr"
+	"github.com/poiesic/wonda/internal/prompts"
 )
 
 // Duration wraps time.Duration to provide human-readable TOML marshaling/unmarshaling.
@@ -41,13 +49,60 @@ type Goal struct {
 	Assignment          []string  `toml:"assignment"`
 	Type                string    `toml:"type"`
 	Deadline            *Duration `toml:"deadline"`
+	MaxTurns            int       `toml:"max_turns"` // Optional: turn by which the goal must be completed, or it fails
 	CompletionThreshold *float64  `toml:"completion_threshold"`
 	// ConsensusGoal specific fields
 	ConsensusThreshold *float64 `toml:"consensus_threshold"`
+	Consensus          string   `toml:"consensus"` // Optional: rule deciding when a proposal is accepted (default unanimous)
 	Tags               []string `toml:"tags"`
+	// WeightedVoteGoal specific fields
+	Weights map[string]float64 `toml:"weights"` // Optional: voting weight by agent (default 1 for agents not listed)
+	// MajorityGoal and WeightedVoteGoal tie-breaking
+	TieBreak      string   `toml:"tie_break"`       // Optional: how a vote split evenly is decided (default: the proposal is rejected)
+	Facilitator   string   `toml:"facilitator"`     // For tie_break = "facilitator": the agent whose vote decides ties
+	TieBreakOrder []string `toml:"tie_break_order"` // For tie_break = "priority": agents, highest priority first
+	TieBreakSeed  *int64   `toml:"tie_break_seed"`  // For tie_break = "coin_flip": seed making flips reproducible (default 0)
+	// JudgedGoal specific fields
+	Criteria   []string `toml:"criteria"`    // Rubric a judge model checks the transcript against
+	JudgeModel string   `toml:"judge_model"` // Optional: model that judges progress (default: scenario default model)
+	// AllocationGoal specific fields
+	Resource       string   `toml:"resource"`        // What is being divided (e.g. "dollars", "seats")
+	Total          float64  `toml:"total"`           // Amount of the resource to divide
+	Recipients     []string `toml:"recipients"`      // Optional: who or what gets a share (default: assigned agents, or all agents)
+	Constraints    []string `toml:"constraints"`     // Optional: rules every allocation must satisfy, over the shares and total
+	AllowRemainder bool     `toml:"allow_remainder"` // Optional: allocations may leave part of the total unallocated
+	// NegotiationGoal specific fields
+	Sides map[string][]string `toml:"sides"` // The two sides negotiating, each a list of agents, by side name
+	Term  string              `toml:"term"`  // Optional: the number every offer names, such as "price"
 	// Future goal types would add their specific fields here
 }
 
+// Goal types
+const (
+	// GoalTypeConsensus goals complete when agents accept a proposal.
+	GoalTypeConsensus = "ConsensusGoal"
+	// GoalTypeJudged goals complete when a judge model finds the transcript meets their criteria.
+	GoalTypeJudged = "JudgedGoal"
+	// GoalTypeAllocation goals complete when agents accept a division of a resource that satisfies its constraints.
+	GoalTypeAllocation = "AllocationGoal"
+	// GoalTypeMajority goals complete when a proposal wins the threshold share of the votes.
+	GoalTypeMajority = "MajorityGoal"
+	// GoalTypeWeightedVote goals complete when a proposal wins the threshold share of the agents' voting weight.
+	GoalTypeWeightedVote = "WeightedVoteGoal"
+	// GoalTypeIndividual goals are pursued by each assigned agent on their own, who marks them complete.
+	GoalTypeIndividual = "IndividualGoal"
+	// GoalTypeRankedChoice goals complete when the agents' rankings of the proposals pick one by instant-runoff.
+	GoalTypeRankedChoice = "RankedChoiceGoal"
+	// GoalTypeNegotiation goals complete when both sides of a negotiation accept the same offer.
+	GoalTypeNegotiation = "NegotiationGoal"
+)
+
+// DefaultVotingThreshold is the share of the vote majority and weighted vote goals need by default.
+const DefaultVotingThreshold = 0.5
+
+// AllocationTotalVariable is the variable allocation constraints use for the goal's total.
+const AllocationTotalVariable = "total"
+
 type InitialState struct {
 	Position         string `toml:"position"`
 	Condition        int    `toml:"condition"`
@@ -60,10 +115,213 @@ type ScenarioDefaults struct {
 }
 
 type Agent struct {
-	Name      string        `toml:"-"`
-	Character string        `toml:"character"`
-	Model     string        `toml:"model"` // Optional: override default model for this agent
-	Initial   *InitialState `toml:"-"`
+	Name       string          `toml:"-"`
+	Character  string          `toml:"character"`
+	Model      string          `toml:"model"`      // Optional: override default model for this agent
+	Ensemble   *EnsembleConfig `toml:"ensemble"`   // Optional: sample several responses per turn and pick one
+	Language   string          `toml:"language"`   // Optional: language this agent speaks (default: the scenario's)
+	Observer   bool            `toml:"observer"`   // Optional: speaks and remembers but can't propose or vote on goals
+	Controller string          `toml:"controller"` // Optional: "llm" (default) or "human" to play the agent from the terminal
+	Initial    *InitialState   `toml:"-"`
+
+	// Optional: temperature, top_p, max_tokens, frequency_penalty and stop
+	// to use over the model's
+	config.Generation
+}
+
+// Agent controllers.
+const (
+	ControllerLLM   = "llm"
+	ControllerHuman = "human"
+)
+
+// Human reports whether a person plays the agent instead of a model.
+func (a *Agent) Human() bool {
+	return a.Controller == ControllerHuman
+}
+
+// validateController checks the agent's controller, and that human agents
+// aren't given model settings they would never use.
+func (a *Agent) validateController() error {
+	switch a.Controller {
+	case "", ControllerLLM:
+		return nil
+	case ControllerHuman:
+	default:
+		return fmt.Errorf("unknown controller: %s (use '%s' or '%s')", a.Controller, ControllerLLM, ControllerHuman)
+	}
+	if a.Model != "" {
+		return fmt.Errorf("human agents don't use a model")
+	}
+	if a.Ensemble != nil {
+		return fmt.Errorf("human agents can't have an ensemble")
+	}
+	if !a.Generation.IsZero() {
+		return fmt.Errorf("human agents don't use generation parameters")
+	}
+	return nil
+}
+
+// EnsembleConfig configures self-consistency sampling for an agent.
+// Each turn is generated several times (by the agent's model or a list of models)
+// and a selector picks the response that is actually executed.
+type EnsembleConfig struct {
+	Samples    int      `toml:"samples"`     // Samples per model (default 3 with no models listed, otherwise 1)
+	Models     []string `toml:"models"`      // Optional: model names to sample from (default: the agent's model)
+	Selector   string   `toml:"selector"`    // "vote" (default) or "judge"
+	JudgeModel string   `toml:"judge_model"` // Model that picks the winner when selector is "judge"
+}
+
+// Validate checks that the ensemble configuration is usable.
+func (e *EnsembleConfig) Validate() error {
+	if e.Samples < 0 {
+		return fmt.Errorf("ensemble samples must not be negative (got %d)", e.Samples)
+	}
+	switch e.Selector {
+	case "", "vote":
+	case "judge":
+		if e.JudgeModel == "" {
+			return fmt.Errorf("ensemble selector 'judge' requires judge_model")
+		}
+	default:
+		return fmt.Errorf("unknown ensemble selector: %s (use 'vote' or 'judge')", e.Selector)
+	}
+	return nil
+}
+
+// GuardrailsConfig configures content policy filtering of agent output
+// (dialogue, actions, thoughts, proposals and vote comments) before it reaches the world.
+type GuardrailsConfig struct {
+	Patterns         []string `toml:"patterns"`          // Regular expressions matching disallowed content
+	Moderation       bool     `toml:"moderation"`        // Optional: also check output with the agent provider's moderation endpoint
+	Action           string   `toml:"action"`            // "redact" (default), "regenerate", or "halt"
+	MaxRegenerations *int     `toml:"max_regenerations"` // Optional: regenerations before falling back to redaction (default 2)
+}
+
+// Validate checks that the guardrails configuration is usable.
+func (g *GuardrailsConfig) Validate() error {
+	for _, pattern := range g.Patterns {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return fmt.Errorf("invalid guardrail pattern %q: %w", pattern, err)
+		}
+	}
+	switch g.Action {
+	case "", "redact", "regenerate", "halt":
+	default:
+		return fmt.Errorf("unknown guardrail action: %s (use 'redact', 'regenerate', or 'halt')", g.Action)
+	}
+	if g.MaxRegenerations != nil && *g.MaxRegenerations < 0 {
+		return fmt.Errorf("guardrail max_regenerations must not be negative (got %d)", *g.MaxRegenerations)
+	}
+	if len(g.Patterns) == 0 && !g.Moderation {
+		return fmt.Errorf("guardrails require patterns or moderation")
+	}
+	return nil
+}
+
+// Judged reports whether the goal is completed by a judge rather than by consensus.
+func (g *Goal) Judged() bool {
+	return g.Type == GoalTypeJudged
+}
+
+// Threshold returns the judge confidence needed to complete the goal (default 1.0).
+func (g *Goal) Threshold() float64 {
+	if g.CompletionThreshold == nil {
+		return 1.0
+	}
+	return *g.CompletionThreshold
+}
+
+// Quorum returns the share of a consensus or allocation goal's deciding agents
+// whose yes votes accept a proposal, from completion_threshold. It returns 0,
+// meaning unanimous, when the goal has no threshold or decides some other way.
+func (g *Goal) Quorum() float64 {
+	if g.CompletionThreshold == nil || g.Consensus != "" || g.Judged() || g.Voting() || g.Individual() || g.RankedChoice() || g.Negotiation() {
+		return 0
+	}
+	return *g.CompletionThreshold
+}
+
+// validateQuorum checks that a completion_threshold on a goal decided by
+// votes is the only thing deciding acceptance, and needs someone to vote yes.
+func (g *Goal) validateQuorum() error {
+	if g.CompletionThreshold == nil || g.Judged() || g.Individual() {
+		return nil
+	}
+	if g.Voting() {
+		return fmt.Errorf("%s uses consensus_threshold for the share of the vote needed, not completion_threshold", g.Type)
+	}
+	if g.Consensus != "" {
+		return fmt.Errorf("completion_threshold and a consensus rule can't both decide acceptance")
+	}
+	if *g.CompletionThreshold == 0 {
+		return fmt.Errorf("completion_threshold must be above 0.0 for goals decided by votes")
+	}
+	return nil
+}
+
+// validateJudging checks the fields used by judged goals.
+func (g *Goal) validateJudging() error {
+	if g.CompletionThreshold != nil && (*g.CompletionThreshold < 0 || *g.CompletionThreshold > 1) {
+		return fmt.Errorf("completion_threshold must be between 0.0 and 1.0 (got %v)", *g.CompletionThreshold)
+	}
+	if !g.Judged() {
+		return nil
+	}
+	if len(g.Criteria) == 0 {
+		return fmt.Errorf("%s requires at least one criterion", GoalTypeJudged)
+	}
+	for i, criterion := range g.Criteria {
+		if strings.TrimSpace(criterion) == "" {
+			return fmt.Errorf("criterion %d is empty", i+1)
+		}
+	}
+	return nil
+}
+
+// RefusalsConfig controls how model refusals and provider content filtering are handled.
+// Refusals are always detected and recorded; this section enables retrying them.
+type RefusalsConfig struct {
+	Retries *int `toml:"retries"` // Optional: retries with a softened prompt after a refusal (default 1)
+}
+
+// Validate checks that the refusals configuration is usable.
+func (r *RefusalsConfig) Validate() error {
+	if r.Retries != nil && *r.Retries < 0 {
+		return fmt.Errorf("refusal retries must not be negative (got %d)", *r.Retries)
+	}
+	return nil
+}
+
+// ConsensusVariables are the vote counts a goal's consensus rule can reference:
+// yes and no votes cast, voted (yes + no), pending (not yet voted), and assigned (eligible voters).
+var ConsensusVariables = []string{"yes", "no", "voted", "pending", "assigned"}
+
+// ConsensusRule compiles the goal's consensus expression.
+// It returns nil when the goal uses the default unanimous rule.
+func (g *Goal) ConsensusRule() (*expr.Expr, error) {
+	if g.Consensus == "" {
+		return nil, nil
+	}
+	rule, err := expr.Compile(g.Consensus, ConsensusVariables)
+	if err != nil {
+		return nil, fmt.Errorf("invalid consensus rule %q: %w", g.Consensus, err)
+	}
+	return rule, nil
+}
+
+// SuccessCriteria compiles the scenario's success expression, in which each
+// goal name is true when the goal completed. It returns nil when the scenario
+// has none.
+func (s *Scenario) SuccessCriteria() (*expr.Expr, error) {
+	if s.Basics == nil || s.Basics.Success == "" {
+		return nil, nil
+	}
+	criteria, err := expr.CompileFlags(s.Basics.Success, slices.Sorted(maps.Keys(s.Goals)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid success criteria %q: %w", s.Basics.Success, err)
+	}
+	return criteria, nil
 }
 
 type BasicScenarioInformation struct {
@@ -75,7 +333,12 @@ type BasicScenarioInformation struct {
 	TOD         string            `toml:"time"`
 	Atmosphere  string            `toml:"atmosphere"`
 	MaxRuntime  Duration          `toml:"max_runtime"`
+	MaxTurns    int               `toml:"max_turns"` // Optional: turns before the simulation ends (default: the speed profile's)
 	Defaults    *ScenarioDefaults `toml:"defaults"`
+	Campaign    string            `toml:"campaign"`  // Optional: campaign whose relationships carry across scenarios
+	Language    string            `toml:"language"`  // Optional: language the scenario is played in (ISO 639-1, default English)
+	Embedding   string            `toml:"embedding"` // Optional: embedding from providers.toml used for memories
+	Success     string            `toml:"success"`   // Optional: expression over goal names deciding whether the run succeeded
 }
 
 type Scenario struct {
@@ -84,6 +347,24 @@ type Scenario struct {
 	Agents        map[string]*Agent         `toml:"agents"`
 	InitialStates map[string]*InitialState  `toml:"initial_state"`
 	Goals         map[string]*Goal          `toml:"goals"`
+	Guardrails    *GuardrailsConfig         `toml:"guardrails"`   // Optional: content policy filtering
+	Environment   *EnvironmentConfig        `toml:"environment"`  // Optional: random ambient events
+	Refusals      *RefusalsConfig           `toml:"refusals"`     // Optional: retry model refusals
+	Fallback      *FallbackConfig           `toml:"fallback"`     // Optional: in-character actions when a model fails
+	Condition     *ConditionConfig          `toml:"condition"`    // Optional: condition affects participation
+	Compromise    *CompromiseConfig         `toml:"compromise"`   // Optional: agents soften as turns run out
+	Memory        *MemoryConfig             `toml:"memory"`       // Optional: result limits and relevance thresholds for memory tools
+	Forbidden     []*ForbiddenOutcome       `toml:"forbidden"`    // Optional: outcomes no goal may settle on
+	EarlyVoting   *EarlyVotingConfig        `toml:"early_voting"` // Optional: vote as soon as consensus is obviously forming
+	TurnBudget    *TurnBudgetConfig         `toml:"turn_budget"`  // Optional: act on goals projected to run out of turns
+	Director      *DirectorConfig           `toml:"director"`     // Optional: a model that steers the scene between turns
+	Locations     map[string]*Location      `toml:"locations"`    // Optional: places agents can move between
+	Objects       map[string]*Object        `toml:"objects"`      // Optional: things agents can inspect, pick up and give
+	Acts          []*Act                    `toml:"acts"`         // Optional: later parts of the scenario in new settings, by the turn they begin
+	Transparency  *TransparencyConfig       `toml:"transparency"` // Optional: whether agents see the reasons others give
+	Prompts       map[string]string         `toml:"prompts"`      // Optional: files, relative to the config directory, replacing prompt templates by name
+
+	Relationships map[string]map[string]*Relationship `toml:"relationships"` // Optional: how agents feel about each other at the start
 }
 
 func NewScenario() *Scenario {
@@ -95,12 +376,367 @@ func NewScenario() *Scenario {
 	}
 }
 
+// Allocation reports whether the goal divides a resource among recipients.
+func (g *Goal) Allocation() bool {
+	return g.Type == GoalTypeAllocation
+}
+
+// AllocationConstraints compiles the goal's allocation constraints.
+// Constraints can reference each recipient's share by name and the goal's total.
+func (g *Goal) AllocationConstraints() ([]*expr.Expr, error) {
+	variables := append([]string{AllocationTotalVariable}, g.Recipients...)
+	constraints := make([]*expr.Expr, 0, len(g.Constraints))
+	for _, source := range g.Constraints {
+		constraint, err := expr.Compile(source, variables)
+		if err != nil {
+			return nil, fmt.Errorf("invalid allocation constraint %q: %w", source, err)
+		}
+		constraints = append(constraints, constraint)
+	}
+	return constraints, nil
+}
+
+// validateMaxTurns checks that the goal's turn limit falls within the scenario's, if it has one.
+func (g *Goal) validateMaxTurns(scenarioMaxTurns int) error {
+	if g.MaxTurns < 0 {
+		return fmt.Errorf("max_turns must be at least 1 (got %d)", g.MaxTurns)
+	}
+	if scenarioMaxTurns > 0 && g.MaxTurns > scenarioMaxTurns {
+		return fmt.Errorf("max_turns %d is beyond the scenario's max_turns %d", g.MaxTurns, scenarioMaxTurns)
+	}
+	return nil
+}
+
+// Individual reports whether each assigned agent pursues the goal on their own.
+func (g *Goal) Individual() bool {
+	return g.Type == GoalTypeIndividual
+}
+
+// validateIndividual checks that an individual goal isn't given consensus settings.
+func (g *Goal) validateIndividual() error {
+	if !g.Individual() {
+		return nil
+	}
+	if g.Consensus != "" {
+		return fmt.Errorf("%s is completed by its agents, not a consensus rule", GoalTypeIndividual)
+	}
+	return nil
+}
+
+// RankedChoice reports whether proposals on the goal are decided by the agents ranking them.
+func (g *Goal) RankedChoice() bool {
+	return g.Type == GoalTypeRankedChoice
+}
+
+// validateRankedChoice checks that a ranked-choice goal isn't given settings
+// for yes/no votes.
+func (g *Goal) validateRankedChoice() error {
+	if !g.RankedChoice() {
+		return nil
+	}
+	if g.Consensus != "" {
+		return fmt.Errorf("%s is decided by ranking its proposals, not a consensus rule", GoalTypeRankedChoice)
+	}
+	if g.CompletionThreshold != nil {
+		return fmt.Errorf("%s is decided by ranking its proposals, not completion_threshold", GoalTypeRankedChoice)
+	}
+	return nil
+}
+
+// Negotiation reports whether the goal is a negotiation between two sides.
+func (g *Goal) Negotiation() bool {
+	return g.Type == GoalTypeNegotiation
+}
+
+// validateNegotiation checks the fields used by negotiation goals: two sides
+// of known agents who aren't observers, with no one on both. The goal is
+// assigned to the sides' agents, which an explicit assignment must match.
+func (g *Goal) validateNegotiation(agents map[string]*Agent) error {
+	if !g.Negotiation() {
+		if len(g.Sides) > 0 || g.Term != "" {
+			return fmt.Errorf("sides and term require type %s", GoalTypeNegotiation)
+		}
+		return nil
+	}
+	if len(g.Sides) != 2 {
+		return fmt.Errorf("%s requires exactly two sides (got %d)", GoalTypeNegotiation, len(g.Sides))
+	}
+	if g.Consensus != "" || g.CompletionThreshold != nil {
+		return fmt.Errorf("%s is settled when both sides accept an offer, not by a consensus rule or completion_threshold", GoalTypeNegotiation)
+	}
+
+	var members []string
+	sideOf := make(map[string]string)
+	for _, side := range slices.Sorted(maps.Keys(g.Sides)) {
+		if len(g.Sides[side]) == 0 {
+			return fmt.Errorf("side %q has no agents", side)
+		}
+		for _, name := range g.Sides[side] {
+			agent, ok := agents[name]
+			if !ok {
+				return fmt.Errorf("side %q has unknown agent %q", side, name)
+			}
+			if agent.Observer {
+				return fmt.Errorf("side %q has observer %q; observers can't negotiate", side, name)
+			}
+			if other, ok := sideOf[name]; ok {
+				return fmt.Errorf("agent %q is on side %q and side %q", name, other, side)
+			}
+			sideOf[name] = side
+			members = append(members, name)
+		}
+	}
+
+	slices.Sort(members)
+	if len(g.Assignment) == 0 {
+		g.Assignment = members
+		return nil
+	}
+	if assigned := slices.Sorted(slices.Values(g.Assignment)); !slices.Equal(assigned, members) {
+		return fmt.Errorf("assignment must list the agents on the sides (%s)", strings.Join(members, ", "))
+	}
+	return nil
+}
+
+// Voting reports whether proposals on the goal are decided by a (possibly weighted) vote.
+func (g *Goal) Voting() bool {
+	return g.Type == GoalTypeMajority || g.Type == GoalTypeWeightedVote
+}
+
+// VotingThreshold returns the share of the vote a proposal needs on a voting goal (default 0.5).
+func (g *Goal) VotingThreshold() float64 {
+	if g.ConsensusThreshold == nil {
+		return DefaultVotingThreshold
+	}
+	return *g.ConsensusThreshold
+}
+
+// validateVoting checks the fields used by majority and weighted vote goals.
+func (g *Goal) validateVoting(agents map[string]*Agent) error {
+	if len(g.Weights) > 0 && g.Type != GoalTypeWeightedVote {
+		return fmt.Errorf("weights require type %s", GoalTypeWeightedVote)
+	}
+	if g.TieBreak != "" && !g.Voting() {
+		return fmt.Errorf("tie_break requires type %s or %s", GoalTypeMajority, GoalTypeWeightedVote)
+	}
+	if g.TieBreak == "" && (g.Facilitator != "" || len(g.TieBreakOrder) > 0 || g.TieBreakSeed != nil) {
+		return fmt.Errorf("facilitator, tie_break_order and tie_break_seed need a tie_break policy")
+	}
+	if !g.Voting() {
+		return nil
+	}
+	if threshold := g.VotingThreshold(); threshold < 0.5 || threshold > 1 {
+		return fmt.Errorf("%s consensus_threshold must be between 0.5 and 1.0 (got %v)", g.Type, threshold)
+	}
+	if g.Consensus != "" {
+		return fmt.Errorf("%s is decided by consensus_threshold, not a consensus rule", g.Type)
+	}
+	for name, weight := range g.Weights {
+		agent, ok := agents[name]
+		if !ok {
+			return fmt.Errorf("weight for unknown agent %q", name)
+		}
+		if agent.Observer {
+			return fmt.Errorf("weight for observer %q; observers can't vote", name)
+		}
+		if weight <= 0 {
+			return fmt.Errorf("%s's weight must be positive (got %v)", name, weight)
+		}
+	}
+	return g.validateTieBreak(agents)
+}
+
+// TieBreakPolicies are the ways a voting goal's ties can be broken.
+var TieBreakPolicies = []string{"facilitator", "priority", "coin_flip", "extend"}
+
+// CoinFlipSeed returns the seed for coin flips breaking ties (default 0).
+func (g *Goal) CoinFlipSeed() int64 {
+	if g.TieBreakSeed == nil {
+		return 0
+	}
+	return *g.TieBreakSeed
+}
+
+// validateTieBreak checks a voting goal's tie-break policy and the settings it uses.
+func (g *Goal) validateTieBreak(agents map[string]*Agent) error {
+	if g.TieBreak == "" {
+		return nil
+	}
+	if !slices.Contains(TieBreakPolicies, g.TieBreak) {
+		return fmt.Errorf("unknown tie_break %q (use %s)", g.TieBreak, strings.Join(TieBreakPolicies, ", "))
+	}
+	if g.VotingThreshold() > DefaultVotingThreshold {
+		return fmt.Errorf("tie_break needs a consensus_threshold of %v; a tied vote can't reach %v", DefaultVotingThreshold, g.VotingThreshold())
+	}
+	if (g.Facilitator != "") != (g.TieBreak == "facilitator") {
+		return fmt.Errorf("facilitator is set with, and only with, tie_break = \"facilitator\"")
+	}
+	if (len(g.TieBreakOrder) > 0) != (g.TieBreak == "priority") {
+		return fmt.Errorf("tie_break_order is set with, and only with, tie_break = \"priority\"")
+	}
+	if g.TieBreakSeed != nil && g.TieBreak != "coin_flip" {
+		return fmt.Errorf("tie_break_seed only applies to tie_break = \"coin_flip\"")
+	}
+
+	decides := func(name string) error {
+		agent, ok := agents[name]
+		if !ok {
+			return fmt.Errorf("unknown agent %q", name)
+		}
+		if agent.Observer {
+			return fmt.Errorf("observer %q can't vote", name)
+		}
+		if len(g.Assignment) > 0 && !slices.Contains(g.Assignment, name) {
+			return fmt.Errorf("%q isn't assigned to the goal", name)
+		}
+		return nil
+	}
+	if g.Facilitator != "" {
+		if err := decides(g.Facilitator); err != nil {
+			return fmt.Errorf("facilitator: %w", err)
+		}
+	}
+	seen := make(map[string]bool, len(g.TieBreakOrder))
+	for _, name := range g.TieBreakOrder {
+		if err := decides(name); err != nil {
+			return fmt.Errorf("tie_break_order: %w", err)
+		}
+		if seen[name] {
+			return fmt.Errorf("tie_break_order lists %q more than once", name)
+		}
+		seen[name] = true
+	}
+	return nil
+}
+
+// validateAssignment checks that a goal is assigned to known agents who aren't
+// observers. An assignment of ["all"] is cleared, leaving the goal to everyone.
+func (g *Goal) validateAssignment(agents map[string]*Agent) error {
+	if len(g.Assignment) == 1 && g.Assignment[0] == "all" {
+		g.Assignment = nil
+		return nil
+	}
+	seen := make(map[string]bool, len(g.Assignment))
+	for _, name := range g.Assignment {
+		agent, ok := agents[name]
+		if !ok {
+			return fmt.Errorf("assigned to unknown agent %q", name)
+		}
+		if agent.Observer {
+			return fmt.Errorf("assigned to observer %q; observers can't propose or vote", name)
+		}
+		if seen[name] {
+			return fmt.Errorf("agent %q is assigned more than once", name)
+		}
+		seen[name] = true
+	}
+	return nil
+}
+
+// validateAllocation checks the fields used by allocation goals, defaulting
+// recipients to the assigned agents, or else all agents but observers.
+func (g *Goal) validateAllocation(agents map[string]*Agent) error {
+	if !g.Allocation() {
+		return nil
+	}
+	if g.Total <= 0 {
+		return fmt.Errorf("%s requires a positive total (got %v)", GoalTypeAllocation, g.Total)
+	}
+	if g.Resource == "" {
+		g.Resource = "units"
+	}
+
+	if len(g.Recipients) == 0 {
+		g.Recipients = append([]string(nil), g.Assignment...)
+	}
+	if len(g.Recipients) == 0 {
+		for name, agent := range agents {
+			if !agent.Observer {
+				g.Recipients = append(g.Recipients, name)
+			}
+		}
+		sort.Strings(g.Recipients)
+	}
+
+	seen := make(map[string]bool, len(g.Recipients))
+	for _, recipient := range g.Recipients {
+		if strings.TrimSpace(recipient) == "" {
+			return fmt.Errorf("allocation recipients must not be empty")
+		}
+		if recipient == AllocationTotalVariable {
+			return fmt.Errorf("%q is reserved and can't be an allocation recipient", AllocationTotalVariable)
+		}
+		if seen[recipient] {
+			return fmt.Errorf("duplicate allocation recipient: %s", recipient)
+		}
+		seen[recipient] = true
+	}
+
+	_, err := g.AllocationConstraints()
+	return err
+}
+
+// AgentLanguage returns the language an agent speaks: its own, or else the scenario's.
+func (s *Scenario) AgentLanguage(agentName string) string {
+	if agent, ok := s.Agents[agentName]; ok && agent.Language != "" {
+		return agent.Language
+	}
+	return s.Basics.Language
+}
+
+// Observers returns the names of the agents who observe rather than decide goals, sorted.
+func (s *Scenario) Observers() []string {
+	var observers []string
+	for name, agent := range s.Agents {
+		if agent.Observer {
+			observers = append(observers, name)
+		}
+	}
+	sort.Strings(observers)
+	return observers
+}
+
+// Humans returns the names of the agents people play, sorted.
+func (s *Scenario) Humans() []string {
+	var humans []string
+	for name, agent := range s.Agents {
+		if agent.Human() {
+			humans = append(humans, name)
+		}
+	}
+	sort.Strings(humans)
+	return humans
+}
+
 // LoadScenario creates and populates a Scenario from TOML data.
 // It performs post-processing to set implicit fields and defaults:
 //   - Agent.Name is set from the map key
 //   - Agent.Initial is linked to the corresponding InitialState
 //   - Goal.Name is set from the map key
+//   - Goal.Consensus is validated when present, as are JudgedGoal criteria
+//   - CompletionThreshold may not be combined with a consensus rule, and voting goals use ConsensusThreshold instead
+//   - AllocationGoal totals and constraints are validated and Recipients defaults to the assigned, or all, agents
+//   - MajorityGoal and WeightedVoteGoal thresholds, weights and tie-break policies are validated
+//   - RankedChoiceGoal must not have a consensus rule or completion_threshold
+//   - NegotiationGoal sides are validated and become its assignment
+//   - IndividualGoal may not have a consensus rule
+//   - Agent.Ensemble is validated when present
+//   - Guardrails are validated when present and MaxRegenerations defaults to 2
+//   - Environment is validated when present and MaxPerTurn defaults to 1
+//   - Refusals are validated when present and Retries defaults to 1
+//   - Fallback templates default when present and are validated
+//   - Condition thresholds default when present and are validated
+//   - Compromise start and stubbornness default when present and are validated
+//   - Memory tool settings are validated when present, and compaction and working memory settings default and are validated
+//   - Forbidden outcomes need a reason, a valid match or pattern, and known goals
+//   - Campaign is validated when present
+//   - Scenario and agent languages are validated when present
+//   - Goal assignments must name agents who aren't observers, and not every agent may observe
 //   - MaxRuntime defaults to "30m" if not specified
+//   - MaxTurns may not be negative, and goal turn limits must fall within it
+//   - Acts begin after the first turn, in order, within MaxTurns
+//   - Success criteria may only reference goals
+//   - Prompt overrides must name overridable prompts and a file
 func LoadScenario(data []byte) (*Scenario, error) {
 	s := NewScenario()
 	if err := toml.Unmarshal(data, s); err != nil {
@@ -117,17 +753,221 @@ func LoadScenario(data []byte) (*Scenario, error) {
 		s.Basics.MaxRuntime = Duration(30 * time.Minute)
 	}
 
+	if s.Basics.MaxTurns < 0 {
+		return nil, fmt.Errorf("max_turns must be at least 1 (got %d)", s.Basics.MaxTurns)
+	}
+
+	// Validate campaign name
+	if s.Basics.Campaign != "" {
+		if err := campaigns.ValidateName(s.Basics.Campaign); err != nil {
+			return nil, err
+		}
+	}
+
+	// Validate languages
+	if s.Basics.Language != "" {
+		if err := config.ValidateLanguage(s.Basics.Language); err != nil {
+			return nil, err
+		}
+	}
+
 	// Set agent names and link initial states
 	for name, agent := range s.Agents {
 		agent.Name = name
+		if agent.Language != "" {
+			if err := config.ValidateLanguage(agent.Language); err != nil {
+				return nil, fmt.Errorf("agent %s: %w", name, err)
+			}
+		}
 		if initialState, exists := s.InitialStates[name]; exists {
 			agent.Initial = initialState
 		}
+		if err := agent.validateController(); err != nil {
+			return nil, fmt.Errorf("agent %s: %w", name, err)
+		}
+		if err := agent.Generation.Validate(); err != nil {
+			return nil, fmt.Errorf("agent %s: %w", name, err)
+		}
+		if agent.Ensemble != nil {
+			if err := agent.Ensemble.Validate(); err != nil {
+				return nil, fmt.Errorf("agent %s: %w", name, err)
+			}
+		}
 	}
 
-	// Set goal names
+	// Validate the location graph
+	if err := validateLocations(s.Locations, s.Agents); err != nil {
+		return nil, err
+	}
+
+	// Validate objects
+	if err := validateObjects(s.Objects, s.Agents, s.Locations); err != nil {
+		return nil, err
+	}
+
+	// Validate starting relationships
+	if err := validateRelationships(s.Relationships, s.Agents); err != nil {
+		return nil, err
+	}
+
+	// Validate guardrails
+	if s.Guardrails != nil {
+		if err := s.Guardrails.Validate(); err != nil {
+			return nil, err
+		}
+		if s.Guardrails.MaxRegenerations == nil {
+			maxRegenerations := 2
+			s.Guardrails.MaxRegenerations = &maxRegenerations
+		}
+	}
+
+	// Validate environment
+	if s.Environment != nil {
+		if err := s.Environment.Validate(); err != nil {
+			return nil, err
+		}
+		if s.Environment.MaxPerTurn == nil {
+			maxPerTurn := 1
+			s.Environment.MaxPerTurn = &maxPerTurn
+		}
+	}
+
+	// Validate condition mechanics
+	if s.Condition != nil {
+		s.Condition.ApplyDefaults()
+		if err := s.Condition.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	// Validate the compromise schedule
+	if s.Compromise != nil {
+		s.Compromise.ApplyDefaults()
+		if err := s.Compromise.Validate(s.Agents); err != nil {
+			return nil, err
+		}
+	}
+
+	// Validate memory tool settings
+	if s.Memory != nil {
+		if err := s.Memory.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	// Validate refusal handling
+	if s.Refusals != nil {
+		if err := s.Refusals.Validate(); err != nil {
+			return nil, err
+		}
+		if s.Refusals.Retries == nil {
+			retries := 1
+			s.Refusals.Retries = &retries
+		}
+	}
+
+	// Validate early voting
+	if s.EarlyVoting != nil {
+		s.EarlyVoting.ApplyDefaults()
+		if err := s.EarlyVoting.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	// Validate the turn budget
+	if s.TurnBudget != nil {
+		s.TurnBudget.ApplyDefaults()
+		if err := s.TurnBudget.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	// Validate fallback templates
+	if s.Fallback != nil {
+		s.Fallback.ApplyDefaults()
+		if err := s.Fallback.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	// Validate the director
+	if s.Director != nil {
+		s.Director.ApplyDefaults()
+		if err := s.Director.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	// Validate transparency
+	if s.Transparency != nil {
+		if err := s.Transparency.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	// Validate prompt overrides; the files are read when the simulation starts
+	for name, file := range s.Prompts {
+		if !slices.Contains(prompts.Overridable, name) {
+			return nil, fmt.Errorf("prompt %s can't be overridden (overridable: %s)", name, strings.Join(prompts.Overridable, ", "))
+		}
+		if file == "" {
+			return nil, fmt.Errorf("prompt %s override needs a file", name)
+		}
+	}
+
+	// Validate forbidden outcomes
+	for i, outcome := range s.Forbidden {
+		if err := outcome.Validate(s.Goals); err != nil {
+			return nil, fmt.Errorf("forbidden outcome %d: %w", i+1, err)
+		}
+	}
+
+	// Goals need someone to decide them
+	if len(s.Goals) > 0 && len(s.Agents) > 0 && len(s.Observers()) == len(s.Agents) {
+		return nil, fmt.Errorf("every agent is an observer, so no one can decide the goals")
+	}
+
+	// Set goal names and validate assignments, consensus rules, judging criteria, allocations and voting
 	for name, goal := range s.Goals {
 		goal.Name = name
+		if err := goal.validateAssignment(s.Agents); err != nil {
+			return nil, fmt.Errorf("goal %s: %w", name, err)
+		}
+		if _, err := goal.ConsensusRule(); err != nil {
+			return nil, fmt.Errorf("goal %s: %w", name, err)
+		}
+		if err := goal.validateJudging(); err != nil {
+			return nil, fmt.Errorf("goal %s: %w", name, err)
+		}
+		if err := goal.validateAllocation(s.Agents); err != nil {
+			return nil, fmt.Errorf("goal %s: %w", name, err)
+		}
+		if err := goal.validateVoting(s.Agents); err != nil {
+			return nil, fmt.Errorf("goal %s: %w", name, err)
+		}
+		if err := goal.validateIndividual(); err != nil {
+			return nil, fmt.Errorf("goal %s: %w", name, err)
+		}
+		if err := goal.validateRankedChoice(); err != nil {
+			return nil, fmt.Errorf("goal %s: %w", name, err)
+		}
+		if err := goal.validateNegotiation(s.Agents); err != nil {
+			return nil, fmt.Errorf("goal %s: %w", name, err)
+		}
+		if err := goal.validateQuorum(); err != nil {
+			return nil, fmt.Errorf("goal %s: %w", name, err)
+		}
+		if err := goal.validateMaxTurns(s.Basics.MaxTurns); err != nil {
+			return nil, fmt.Errorf("goal %s: %w", name, err)
+		}
+	}
+
+	if err := validateActs(s.Acts, s.Basics.MaxTurns); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.SuccessCriteria(); err != nil {
+		return nil, err
 	}
 
 	return s, nil
@@ -53,8 +53,7 @@ func TestScenarioMarshalTOML(t *testing.T) {
 		scenario.Basics.Location = "Test Location"
 		scenario.Basics.TOD = "12:00 PM"
 		scenario.Basics.Defaults = &ScenarioDefaults{
-			Provider: "anthropic",
-			Model:    "claude-3-5-sonnet-20241022",
+			Model: "claude-3-5-sonnet-20241022",
 		}
 
 		scenario.Agents["agent1"] = &Agent{
@@ -73,7 +72,6 @@ func TestScenarioMarshalTOML(t *testing.T) {
 		require.NotEmpty(t, buf)
 
 		result := string(buf)
-		assert.Contains(t, result, "provider = 'anthropic'")
 		assert.Contains(t, result, "model = 'claude-3-5-sonnet-20241022'")
 	})
 
@@ -85,8 +83,7 @@ func TestScenarioMarshalTOML(t *testing.T) {
 		scenario.Basics.Location = "Test Location"
 		scenario.Basics.TOD = "12:00 PM"
 		scenario.Basics.Defaults = &ScenarioDefaults{
-			Provider: "anthropic",
-			Model:    "claude-3-5-sonnet-20241022",
+			Model: "claude-3-5-sonnet-20241022",
 		}
 
 		scenario.Agents["agent1"] = &Agent{
@@ -95,7 +92,6 @@ func TestScenarioMarshalTOML(t *testing.T) {
 
 		scenario.Agents["agent2"] = &Agent{
 			Character: "enthusiast",
-			Provider:  "ollama",
 			Model:     "llama3.1:8b",
 		}
 
@@ -112,7 +108,6 @@ func TestScenarioMarshalTOML(t *testing.T) {
 
 		result := string(buf)
 		assert.Contains(t, result, "[agents.agent2]")
-		assert.Contains(t, result, "provider = 'ollama'")
 		assert.Contains(t, result, "model = 'llama3.1:8b'")
 	})
 
@@ -201,8 +196,7 @@ func TestScenarioMarshalTOML(t *testing.T) {
 		maxRuntime := Duration(30 * time.Minute)
 		scenario.Basics.MaxRuntime = maxRuntime
 		scenario.Basics.Defaults = &ScenarioDefaults{
-			Provider: "anthropic",
-			Model:    "claude-3-5-sonnet-20241022",
+			Model: "claude-3-5-sonnet-20241022",
 		}
 
 		scenario.Agents["agent1"] = &Agent{
@@ -288,7 +282,6 @@ location = "Test Location"
 time = "12:00 PM"
 
 [scenario.defaults]
-provider = "anthropic"
 model = "claude-3-5-sonnet-20241022"
 
 [agents.agent1]
@@ -306,7 +299,6 @@ type = "ConsensusGoal"
 		require.NoError(t, err)
 
 		require.NotNil(t, scenario.Basics.Defaults)
-		assert.Equal(t, "anthropic", scenario.Basics.Defaults.Provider)
 		assert.Equal(t, "claude-3-5-sonnet-20241022", scenario.Basics.Defaults.Model)
 	})
 
@@ -321,7 +313,6 @@ location = "Test Location"
 time = "12:00 PM"
 
 [scenario.defaults]
-provider = "anthropic"
 model = "claude-3-5-sonnet-20241022"
 
 [agents.agent1]
@@ -329,7 +320,6 @@ character = "pragmatist"
 
 [agents.agent2]
 character = "enthusiast"
-provider = "ollama"
 model = "llama3.1:8b"
 
 [goals.goal1]
@@ -345,12 +335,10 @@ type = "ConsensusGoal"
 
 		require.Contains(t, scenario.Agents, "agent1")
 		assert.Equal(t, "pragmatist", scenario.Agents["agent1"].Character)
-		assert.Equal(t, "", scenario.Agents["agent1"].Provider)
 		assert.Equal(t, "", scenario.Agents["agent1"].Model)
 
 		require.Contains(t, scenario.Agents, "agent2")
 		assert.Equal(t, "enthusiast", scenario.Agents["agent2"].Character)
-		assert.Equal(t, "ollama", scenario.Agents["agent2"].Provider)
 		assert.Equal(t, "llama3.1:8b", scenario.Agents["agent2"].Model)
 	})
 
@@ -445,7 +433,6 @@ atmosphere = "Tense and urgent"
 max_runtime = "30m"
 
 [scenario.defaults]
-provider = "anthropic"
 model = "claude-3-5-sonnet-20241022"
 
 [agents.agent1]
@@ -472,7 +459,6 @@ tags = ["test", "comprehensive"]
 		assert.Equal(t, Duration(30*time.Minute), scenario.Basics.MaxRuntime)
 
 		require.NotNil(t, scenario.Basics.Defaults)
-		assert.Equal(t, "anthropic", scenario.Basics.Defaults.Provider)
 
 		require.Contains(t, scenario.Goals, "goal1")
 		goal := scenario.Goals["goal1"]
@@ -675,8 +661,7 @@ func TestScenarioRoundTrip(t *testing.T) {
 		original.Basics.Atmosphere = "Tense and urgent"
 		original.Basics.MaxRuntime = Duration(30 * time.Minute)
 		original.Basics.Defaults = &ScenarioDefaults{
-			Provider: "anthropic",
-			Model:    "claude-3-5-sonnet-20241022",
+			Model: "claude-3-5-sonnet-20241022",
 		}
 
 		original.Agents["agent1"] = &Agent{
@@ -685,7 +670,6 @@ func TestScenarioRoundTrip(t *testing.T) {
 
 		original.Agents["agent2"] = &Agent{
 			Character: "enthusiast",
-			Provider:  "ollama",
 			Model:     "llama3.1:8b",
 		}
 
@@ -728,12 +712,10 @@ func TestScenarioRoundTrip(t *testing.T) {
 
 		// Verify defaults
 		require.NotNil(t, decoded.Basics.Defaults)
-		assert.Equal(t, original.Basics.Defaults.Provider, decoded.Basics.Defaults.Provider)
 		assert.Equal(t, original.Basics.Defaults.Model, decoded.Basics.Defaults.Model)
 
 		// Verify agents
 		assert.Len(t, decoded.Agents, len(original.Agents))
-		assert.Equal(t, original.Agents["agent2"].Provider, decoded.Agents["agent2"].Provider)
 		assert.Equal(t, original.Agents["agent2"].Model, decoded.Agents["agent2"].Model)
 
 		// Verify initial states
@@ -1005,7 +987,6 @@ atmosphere = "Tense and urgent"
 max_runtime = "30m"
 
 [scenario.defaults]
-provider = "anthropic"
 model = "claude-3-5-sonnet-20241022"
 
 [agents.agent1]
@@ -1013,7 +994,6 @@ character = "pragmatist"
 
 [agents.agent2]
 character = "enthusiast"
-provider = "ollama"
 model = "llama3.1:8b"
 
 [initial_state.agent1]
@@ -1044,13 +1024,11 @@ tags = ["test", "comprehensive"]
 
 		// Verify defaults
 		require.NotNil(t, scenario.Basics.Defaults)
-		assert.Equal(t, "anthropic", scenario.Basics.Defaults.Provider)
 
 		// Verify agents with names set
 		assert.Len(t, scenario.Agents, 2)
 		assert.Equal(t, "agent1", scenario.Agents["agent1"].Name)
 		assert.Equal(t, "agent2", scenario.Agents["agent2"].Name)
-		assert.Equal(t, "ollama", scenario.Agents["agent2"].Provider)
 
 		// Verify initial state linked
 		require.NotNil(t, scenario.Agents["agent1"].Initial)
@@ -1177,3 +1155,346 @@ type = "ConsensusGoal"
 		assert.Equal(t, Duration(5*time.Minute), scenario.Basics.MaxRuntime)
 	})
 }
+
+// goalScenario returns a scenario for Alex and Jordan with one goal, named
+// "budget", whose settings follow its table header.
+func goalScenario(goal string) []byte {
+	return []byte(`
+version = "1.0.0"
+
+[scenario]
+name = "Goal Test"
+description = "Test goal validation"
+location = "Test Location"
+time = "12:00 PM"
+
+[agents.Alex]
+character = "pragmatist"
+
+[agents.Jordan]
+character = "idealist"
+
+[goals.budget]
+description = "Split the budget"
+priority = 1
+` + goal)
+}
+
+func TestLoadScenarioAllocation(t *testing.T) {
+	t.Run("defaults the resource and recipients", func(t *testing.T) {
+		scenario, err := LoadScenario(goalScenario(`type = "AllocationGoal"
+total = 100
+`))
+		require.NoError(t, err)
+
+		goal := scenario.Goals["budget"]
+		assert.True(t, goal.Allocation())
+		assert.Equal(t, "units", goal.Resource)
+		assert.Equal(t, []string{"Alex", "Jordan"}, goal.Recipients)
+	})
+
+	t.Run("defaults recipients to the assigned agents", func(t *testing.T) {
+		scenario, err := LoadScenario(goalScenario(`type = "AllocationGoal"
+total = 100
+assignment = ["Jordan"]
+`))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Jordan"}, scenario.Goals["budget"].Recipients)
+	})
+
+	t.Run("compiles constraints over the recipients and total", func(t *testing.T) {
+		scenario, err := LoadScenario(goalScenario(`type = "AllocationGoal"
+total = 10000
+resource = "dollars"
+recipients = ["marketing", "engineering"]
+constraints = ["engineering >= 0.4 * total", "marketing >= 1000"]
+`))
+		require.NoError(t, err)
+
+		constraints, err := scenario.Goals["budget"].AllocationConstraints()
+		require.NoError(t, err)
+		require.Len(t, constraints, 2)
+		satisfied, err := constraints[0].Bool(map[string]float64{"total": 10000, "marketing": 5000, "engineering": 5000})
+		require.NoError(t, err)
+		assert.True(t, satisfied)
+	})
+
+	tests := []struct {
+		name    string
+		goal    string
+		wantErr string
+	}{
+		{
+			name:    "missing total",
+			goal:    "type = \"AllocationGoal\"\n",
+			wantErr: "requires a positive total (got 0)",
+		},
+		{
+			name:    "negative total",
+			goal:    "type = \"AllocationGoal\"\ntotal = -50\n",
+			wantErr: "requires a positive total (got -50)",
+		},
+		{
+			name:    "constraint on an unknown recipient",
+			goal:    "type = \"AllocationGoal\"\ntotal = 100\nrecipients = [\"marketing\", \"engineering\"]\nconstraints = [\"support >= 10\"]\n",
+			wantErr: `unknown variable "support"`,
+		},
+		{
+			name:    "invalid constraint",
+			goal:    "type = \"AllocationGoal\"\ntotal = 100\nconstraints = [\"Alex >=\"]\n",
+			wantErr: `invalid allocation constraint "Alex >="`,
+		},
+		{
+			name:    "duplicate recipient",
+			goal:    "type = \"AllocationGoal\"\ntotal = 100\nrecipients = [\"marketing\", \"marketing\"]\n",
+			wantErr: "duplicate allocation recipient: marketing",
+		},
+		{
+			name:    "empty recipient",
+			goal:    "type = \"AllocationGoal\"\ntotal = 100\nrecipients = [\"marketing\", \" \"]\n",
+			wantErr: "allocation recipients must not be empty",
+		},
+		{
+			name:    "reserved recipient",
+			goal:    "type = \"AllocationGoal\"\ntotal = 100\nrecipients = [\"marketing\", \"total\"]\n",
+			wantErr: `"total" is reserved`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadScenario(goalScenario(tt.goal))
+			assert.ErrorContains(t, err, "goal budget")
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestLoadScenarioNegotiation(t *testing.T) {
+	t.Run("assigns the goal to the sides", func(t *testing.T) {
+		scenario, err := LoadScenario(goalScenario(`type = "NegotiationGoal"
+term = "price"
+sides = { buyer = ["Jordan"], seller = ["Alex"] }
+`))
+		require.NoError(t, err)
+
+		goal := scenario.Goals["budget"]
+		assert.True(t, goal.Negotiation())
+		assert.Equal(t, []string{"Alex", "Jordan"}, goal.Assignment)
+		assert.Zero(t, goal.Quorum())
+	})
+
+	t.Run("accepts an assignment matching the sides", func(t *testing.T) {
+		_, err := LoadScenario(goalScenario(`type = "NegotiationGoal"
+sides = { buyer = ["Jordan"], seller = ["Alex"] }
+assignment = ["Jordan", "Alex"]
+`))
+		assert.NoError(t, err)
+	})
+
+	const observer = `
+[agents.Sam]
+character = "skeptic"
+observer = true
+`
+	tests := []struct {
+		name    string
+		goal    string
+		wantErr string
+	}{
+		{
+			name:    "sides on another goal type",
+			goal:    "sides = { buyer = [\"Jordan\"], seller = [\"Alex\"] }\n",
+			wantErr: "sides and term require type NegotiationGoal",
+		},
+		{
+			name:    "term on another goal type",
+			goal:    "term = \"price\"\n",
+			wantErr: "sides and term require type NegotiationGoal",
+		},
+		{
+			name:    "no sides",
+			goal:    "type = \"NegotiationGoal\"\n",
+			wantErr: "requires exactly two sides (got 0)",
+		},
+		{
+			name:    "one side",
+			goal:    "type = \"NegotiationGoal\"\nsides = { buyer = [\"Jordan\", \"Alex\"] }\n",
+			wantErr: "requires exactly two sides (got 1)",
+		},
+		{
+			name:    "three sides",
+			goal:    "type = \"NegotiationGoal\"\nsides = { buyer = [\"Jordan\"], seller = [\"Alex\"], broker = [\"Alex\"] }\n",
+			wantErr: "requires exactly two sides (got 3)",
+		},
+		{
+			name:    "consensus rule",
+			goal:    "type = \"NegotiationGoal\"\nsides = { buyer = [\"Jordan\"], seller = [\"Alex\"] }\nconsensus = \"yes >= 1\"\n",
+			wantErr: "not by a consensus rule or completion_threshold",
+		},
+		{
+			name:    "completion threshold",
+			goal:    "type = \"NegotiationGoal\"\nsides = { buyer = [\"Jordan\"], seller = [\"Alex\"] }\ncompletion_threshold = 0.5\n",
+			wantErr: "not by a consensus rule or completion_threshold",
+		},
+		{
+			name:    "empty side",
+			goal:    "type = \"NegotiationGoal\"\nsides = { buyer = [], seller = [\"Alex\"] }\n",
+			wantErr: `side "buyer" has no agents`,
+		},
+		{
+			name:    "unknown agent",
+			goal:    "type = \"NegotiationGoal\"\nsides = { buyer = [\"Jordan\"], seller = [\"Casey\"] }\n",
+			wantErr: `side "seller" has unknown agent "Casey"`,
+		},
+		{
+			name:    "observer",
+			goal:    "type = \"NegotiationGoal\"\nsides = { buyer = [\"Jordan\"], seller = [\"Alex\", \"Sam\"] }\n" + observer,
+			wantErr: `side "seller" has observer "Sam"; observers can't negotiate`,
+		},
+		{
+			name:    "agent on both sides",
+			goal:    "type = \"NegotiationGoal\"\nsides = { buyer = [\"Jordan\", \"Alex\"], seller = [\"Alex\"] }\n",
+			wantErr: `agent "Alex" is on side "buyer" and side "seller"`,
+		},
+		{
+			name:    "assignment leaves out a side",
+			goal:    "type = \"NegotiationGoal\"\nsides = { buyer = [\"Jordan\"], seller = [\"Alex\"] }\nassignment = [\"Jordan\"]\n",
+			wantErr: "assignment must list the agents on the sides (Alex, Jordan)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadScenario(goalScenario(tt.goal))
+			assert.ErrorContains(t, err, "goal budget")
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestLoadScenarioTieBreak(t *testing.T) {
+	t.Run("accepts every policy with its settings", func(t *testing.T) {
+		for _, settings := range []string{
+			"tie_break = \"facilitator\"\nfacilitator = \"Alex\"\n",
+			"tie_break = \"priority\"\ntie_break_order = [\"Jordan\", \"Alex\"]\n",
+			"tie_break = \"coin_flip\"\ntie_break_seed = 7\n",
+			"tie_break = \"coin_flip\"\n",
+			"tie_break = \"extend\"\n",
+		} {
+			scenario, err := LoadScenario(goalScenario("type = \"MajorityGoal\"\n" + settings))
+			require.NoError(t, err, settings)
+			assert.NotEmpty(t, scenario.Goals["budget"].TieBreak)
+		}
+	})
+
+	t.Run("seeds coin flips", func(t *testing.T) {
+		scenario, err := LoadScenario(goalScenario("type = \"WeightedVoteGoal\"\ntie_break = \"coin_flip\"\ntie_break_seed = 7\n"))
+		require.NoError(t, err)
+		assert.Equal(t, int64(7), scenario.Goals["budget"].CoinFlipSeed())
+
+		scenario, err = LoadScenario(goalScenario("type = \"MajorityGoal\"\ntie_break = \"coin_flip\"\n"))
+		require.NoError(t, err)
+		assert.Zero(t, scenario.Goals["budget"].CoinFlipSeed())
+	})
+
+	const observer = `
+[agents.Sam]
+character = "skeptic"
+observer = true
+`
+	tests := []struct {
+		name    string
+		goal    string
+		wantErr string
+	}{
+		{
+			name:    "not a voting goal",
+			goal:    "type = \"ConsensusGoal\"\ntie_break = \"coin_flip\"\n",
+			wantErr: "tie_break requires type MajorityGoal or WeightedVoteGoal",
+		},
+		{
+			name:    "settings without a policy",
+			goal:    "type = \"MajorityGoal\"\nfacilitator = \"Alex\"\n",
+			wantErr: "facilitator, tie_break_order and tie_break_seed need a tie_break policy",
+		},
+		{
+			name:    "seed without a policy",
+			goal:    "type = \"MajorityGoal\"\ntie_break_seed = 7\n",
+			wantErr: "need a tie_break policy",
+		},
+		{
+			name:    "unknown policy",
+			goal:    "type = \"MajorityGoal\"\ntie_break = \"arm_wrestle\"\n",
+			wantErr: `unknown tie_break "arm_wrestle" (use facilitator, priority, coin_flip, extend)`,
+		},
+		{
+			name:    "threshold above half",
+			goal:    "type = \"MajorityGoal\"\nconsensus_threshold = 0.66\ntie_break = \"extend\"\n",
+			wantErr: "tie_break needs a consensus_threshold of 0.5; a tied vote can't reach 0.66",
+		},
+		{
+			name:    "facilitator needs a facilitator",
+			goal:    "type = \"MajorityGoal\"\ntie_break = \"facilitator\"\n",
+			wantErr: `facilitator is set with, and only with, tie_break = "facilitator"`,
+		},
+		{
+			name:    "facilitator with another policy",
+			goal:    "type = \"MajorityGoal\"\ntie_break = \"extend\"\nfacilitator = \"Alex\"\n",
+			wantErr: `facilitator is set with, and only with, tie_break = "facilitator"`,
+		},
+		{
+			name:    "priority needs ranks",
+			goal:    "type = \"MajorityGoal\"\ntie_break = \"priority\"\n",
+			wantErr: `tie_break_order is set with, and only with, tie_break = "priority"`,
+		},
+		{
+			name:    "ranks with another policy",
+			goal:    "type = \"MajorityGoal\"\ntie_break = \"coin_flip\"\ntie_break_order = [\"Alex\"]\n",
+			wantErr: `tie_break_order is set with, and only with, tie_break = "priority"`,
+		},
+		{
+			name:    "seed with another policy",
+			goal:    "type = \"MajorityGoal\"\ntie_break = \"extend\"\ntie_break_seed = 7\n",
+			wantErr: `tie_break_seed only applies to tie_break = "coin_flip"`,
+		},
+		{
+			name:    "unknown facilitator",
+			goal:    "type = \"MajorityGoal\"\ntie_break = \"facilitator\"\nfacilitator = \"Casey\"\n",
+			wantErr: `facilitator: unknown agent "Casey"`,
+		},
+		{
+			name:    "observer facilitator",
+			goal:    "type = \"MajorityGoal\"\ntie_break = \"facilitator\"\nfacilitator = \"Sam\"\n" + observer,
+			wantErr: `facilitator: observer "Sam" can't vote`,
+		},
+		{
+			name:    "unassigned facilitator",
+			goal:    "type = \"MajorityGoal\"\nassignment = [\"Alex\"]\ntie_break = \"facilitator\"\nfacilitator = \"Jordan\"\n",
+			wantErr: `facilitator: "Jordan" isn't assigned to the goal`,
+		},
+		{
+			name:    "unknown agent ranked",
+			goal:    "type = \"MajorityGoal\"\ntie_break = \"priority\"\ntie_break_order = [\"Alex\", \"Casey\"]\n",
+			wantErr: `tie_break_order: unknown agent "Casey"`,
+		},
+		{
+			name:    "observer ranked",
+			goal:    "type = \"WeightedVoteGoal\"\ntie_break = \"priority\"\ntie_break_order = [\"Sam\"]\n" + observer,
+			wantErr: `tie_break_order: observer "Sam" can't vote`,
+		},
+		{
+			name:    "agent ranked twice",
+			goal:    "type = \"MajorityGoal\"\ntie_break = \"priority\"\ntie_break_order = [\"Alex\", \"Jordan\", \"Alex\"]\n",
+			wantErr: `tie_break_order lists "Alex" more than once`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadScenario(goalScenario(tt.goal))
+			assert.ErrorContains(t, err, "goal budget")
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
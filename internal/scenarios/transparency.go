@@ -0,0 +1,33 @@
+package scenarios
+
+import "fmt"
+
+// Reasoning visibilities for TransparencyConfig.
+const (
+	// ReasoningPrivate keeps the reasons agents give for what they say to the chronicle.
+	ReasoningPrivate = "private"
+	// ReasoningShared shows the reasons agents give to the agents who hear them.
+	ReasoningShared = "shared"
+)
+
+// TransparencyConfig sets whether agents see the reasons others give for
+// what they say, for experiments on transparency in group decisions. The
+// reasons are short summaries agents choose to give when they speak; raw
+// model thinking is never shared.
+type TransparencyConfig struct {
+	Reasoning string `toml:"reasoning"` // Optional: "private" (default) or "shared"
+}
+
+// Validate checks that the reasoning visibility is known.
+func (c *TransparencyConfig) Validate() error {
+	switch c.Reasoning {
+	case "", ReasoningPrivate, ReasoningShared:
+		return nil
+	}
+	return fmt.Errorf("unknown transparency reasoning: %s (use '%s' or '%s')", c.Reasoning, ReasoningPrivate, ReasoningShared)
+}
+
+// ReasoningShared reports whether the scenario shows agents the reasons others give.
+func (s *Scenario) ReasoningShared() bool {
+	return s.Transparency != nil && s.Transparency.Reasoning == ReasoningShared
+}
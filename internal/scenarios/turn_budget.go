@@ -0,0 +1,40 @@
+package scenarios
+
+import "fmt"
+
+// TurnBudgetConfig watches how quickly agents converge on each goal and acts
+// when a goal won't be settled in the turns left, instead of letting the
+// simulation end with it silently unresolved.
+type TurnBudgetConfig struct {
+	PredictAfter *int  `toml:"predict_after"` // Optional: turns of votes to watch before projecting (default 2)
+	Urgency      *bool `toml:"urgency"`       // Optional: warn agents, more firmly as time runs out, about goals projected to miss their deadline (default true)
+	RankedChoice *bool `toml:"ranked_choice"` // Optional: settle goals projected to miss their deadline by a ranked-choice vote on their final turn (default true)
+}
+
+// ApplyDefaults fills in unset settings.
+func (c *TurnBudgetConfig) ApplyDefaults() {
+	if c.PredictAfter == nil {
+		predictAfter := 2
+		c.PredictAfter = &predictAfter
+	}
+	if c.Urgency == nil {
+		urgency := true
+		c.Urgency = &urgency
+	}
+	if c.RankedChoice == nil {
+		rankedChoice := true
+		c.RankedChoice = &rankedChoice
+	}
+}
+
+// Validate checks that the turn budget configuration is usable.
+// Defaults must have been applied.
+func (c *TurnBudgetConfig) Validate() error {
+	if *c.PredictAfter < 2 {
+		return fmt.Errorf("turn_budget predict_after must be at least 2, to see a trend (got %d)", *c.PredictAfter)
+	}
+	if !*c.Urgency && !*c.RankedChoice {
+		return fmt.Errorf("turn_budget needs urgency or ranked_choice enabled")
+	}
+	return nil
+}
@@ -0,0 +1,172 @@
+// Package signing signs simulation artifacts with an ed25519 key so published
+// results can be shown to be unmodified.
+package signing
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+	"time"
+)
+
+// KeyFile is the name of the signing key in the config directory.
+// Runs are only signed when it exists.
+const KeyFile = "signing.key"
+
+// SignatureExt is appended to an artifact's path to name its signature file.
+const SignatureExt = ".sig"
+
+// Signature records an ed25519 signature over an artifact's exact bytes.
+type Signature struct {
+	File      string    `json:"file"`       // Base name of the signed file
+	SHA256    string    `json:"sha256"`     // Hex digest of the signed file
+	PublicKey string    `json:"public_key"` // Base64 ed25519 public key of the signer
+	Signature string    `json:"signature"`  // Base64 ed25519 signature over the file's bytes
+	SignedAt  time.Time `json:"signed_at"`
+}
+
+// KeyPath returns the signing key path for a config directory.
+func KeyPath(configDir string) string {
+	return path.Join(configDir, KeyFile)
+}
+
+// GenerateKey creates a signing key in the config directory.
+// An existing key is only replaced when overwrite is set.
+func GenerateKey(configDir string, overwrite bool) (ed25519.PublicKey, error) {
+	keyPath := KeyPath(configDir)
+	if _, err := os.Stat(keyPath); err == nil && !overwrite {
+		return nil, fmt.Errorf("signing key %s already exists", keyPath)
+	}
+
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate signing key: %w", err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode signing key: %w", err)
+	}
+	data := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+	if err := os.WriteFile(keyPath, data, 0600); err != nil {
+		return nil, fmt.Errorf("failed to write signing key: %w", err)
+	}
+	return publicKey, nil
+}
+
+// LoadKey reads the config directory's signing key, or returns nil if there is none.
+func LoadKey(configDir string) (ed25519.PrivateKey, error) {
+	keyPath := KeyPath(configDir)
+	data, err := os.ReadFile(keyPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read signing key: %w", err)
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("%s: not a PEM encoded key", keyPath)
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", keyPath, err)
+	}
+	privateKey, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("%s: not an ed25519 key", keyPath)
+	}
+	return privateKey, nil
+}
+
+// EncodePublicKey returns the base64 form of a public key used in signature
+// files and accepted by ParsePublicKey.
+func EncodePublicKey(publicKey ed25519.PublicKey) string {
+	return base64.StdEncoding.EncodeToString(publicKey)
+}
+
+// ParsePublicKey decodes a base64 public key.
+func ParsePublicKey(encoded string) (ed25519.PublicKey, error) {
+	data, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil || len(data) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("invalid public key: expected %d base64 encoded bytes", ed25519.PublicKeySize)
+	}
+	return ed25519.PublicKey(data), nil
+}
+
+// SignFile signs a file and writes the signature alongside it.
+// It returns the signature file's path.
+func SignFile(key ed25519.PrivateKey, filePath string) (string, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", filePath, err)
+	}
+
+	digest := sha256.Sum256(data)
+	signature := Signature{
+		File:      path.Base(filePath),
+		SHA256:    hex.EncodeToString(digest[:]),
+		PublicKey: EncodePublicKey(key.Public().(ed25519.PublicKey)),
+		Signature: base64.StdEncoding.EncodeToString(ed25519.Sign(key, data)),
+		SignedAt:  time.Now().UTC(),
+	}
+	encoded, err := json.MarshalIndent(signature, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal signature: %w", err)
+	}
+
+	signaturePath := filePath + SignatureExt
+	if err := os.WriteFile(signaturePath, encoded, 0644); err != nil {
+		return "", fmt.Errorf("failed to write signature: %w", err)
+	}
+	return signaturePath, nil
+}
+
+// VerifyFile checks a file against the signature file alongside it.
+// With a trusted key, the signature must also have been made by that key;
+// without one, it only shows the file is unchanged since whoever holds the
+// embedded public key signed it.
+func VerifyFile(filePath string, trusted ed25519.PublicKey) (*Signature, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", filePath, err)
+	}
+	encoded, err := os.ReadFile(filePath + SignatureExt)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("%s is not signed (no %s file)", filePath, SignatureExt)
+		}
+		return nil, fmt.Errorf("failed to read signature: %w", err)
+	}
+
+	var signature Signature
+	if err := json.Unmarshal(encoded, &signature); err != nil {
+		return nil, fmt.Errorf("failed to parse signature: %w", err)
+	}
+	publicKey, err := ParsePublicKey(signature.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+	if trusted != nil && !publicKey.Equal(trusted) {
+		return &signature, fmt.Errorf("signed by a different key (%s)", signature.PublicKey)
+	}
+
+	digest := sha256.Sum256(data)
+	if hex.EncodeToString(digest[:]) != signature.SHA256 {
+		return &signature, fmt.Errorf("file has been modified since it was signed")
+	}
+	rawSignature, err := base64.StdEncoding.DecodeString(signature.Signature)
+	if err != nil || !ed25519.Verify(publicKey, data, rawSignature) {
+		return &signature, fmt.Errorf("signature is invalid")
+	}
+	return &signature, nil
+}
@@ -0,0 +1,60 @@
+package signing
+
+import (
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSigning(t *testing.T) {
+	t.Run("returns no key when none is configured", func(t *testing.T) {
+		key, err := LoadKey(t.TempDir())
+		require.NoError(t, err)
+		assert.Nil(t, key)
+	})
+
+	t.Run("refuses to replace an existing key", func(t *testing.T) {
+		dir := t.TempDir()
+		_, err := GenerateKey(dir, false)
+		require.NoError(t, err)
+		_, err = GenerateKey(dir, false)
+		assert.ErrorContains(t, err, "already exists")
+	})
+
+	t.Run("verifies signed files and detects changes", func(t *testing.T) {
+		dir := t.TempDir()
+		publicKey, err := GenerateKey(dir, false)
+		require.NoError(t, err)
+		key, err := LoadKey(dir)
+		require.NoError(t, err)
+
+		filePath := path.Join(dir, "chronicle.jsonl")
+		require.NoError(t, os.WriteFile(filePath, []byte(`{"type":"metadata"}`+"\n"), 0644))
+		signaturePath, err := SignFile(key, filePath)
+		require.NoError(t, err)
+		assert.Equal(t, filePath+SignatureExt, signaturePath)
+
+		signature, err := VerifyFile(filePath, publicKey)
+		require.NoError(t, err)
+		assert.Equal(t, "chronicle.jsonl", signature.File)
+
+		otherKey, err := GenerateKey(t.TempDir(), false)
+		require.NoError(t, err)
+		_, err = VerifyFile(filePath, otherKey)
+		assert.ErrorContains(t, err, "different key")
+
+		require.NoError(t, os.WriteFile(filePath, []byte(`{"type":"metadata","edited":true}`+"\n"), 0644))
+		_, err = VerifyFile(filePath, nil)
+		assert.ErrorContains(t, err, "modified")
+	})
+
+	t.Run("reports unsigned files", func(t *testing.T) {
+		filePath := path.Join(t.TempDir(), "outcomes.json")
+		require.NoError(t, os.WriteFile(filePath, []byte("{}"), 0644))
+		_, err := VerifyFile(filePath, nil)
+		assert.ErrorContains(t, err, "not signed")
+	})
+}
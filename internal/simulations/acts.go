@@ -0,0 +1,126 @@
+package simulations
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strconv"
+
+	"github.com/poiesic/wonda/internal/memory"
+	"github.com/poiesic/wonda/internal/scenarios"
+)
+
+// beginAct moves the scene on to the act beginning this turn, if one does.
+// Agents perceive its location and atmosphere from now on, its description is
+// announced to everyone, and the scene memories it replaces become history.
+func (s *Simulation) beginAct(ctx context.Context, turn int) {
+	act, number := s.Scenario.ActAt(turn)
+	if act == nil || act.Turn != turn {
+		return
+	}
+	slog.Info("act beginning", "act", number, "name", act.Name, "location", act.Location)
+
+	if act.Location != "" {
+		s.World.SetLocation(act.Location)
+	}
+	if act.Atmosphere != "" {
+		s.World.SetAtmosphere(act.Atmosphere)
+	}
+	if act.Description != "" {
+		s.currentAmbient = append(s.currentAmbient, act.Description)
+		s.World.InjectEvent(act.Description)
+	}
+	if err := s.ageSceneMemories(ctx, turn, act, number); err != nil {
+		slog.Warn("failed to update scene memories", "act", number, "error", err)
+	}
+}
+
+// resumeActs restores the location of the act under way on a resumed turn,
+// which checkpoints don't carry; memories and the atmosphere are restored
+// with the rest of the run.
+func (s *Simulation) resumeActs(turn int) {
+	for _, act := range s.Scenario.Acts {
+		if act.Turn <= turn && act.Location != "" {
+			s.World.SetLocation(act.Location)
+		}
+	}
+}
+
+// actCategories returns the scene memory categories an act's setting replaces.
+func actCategories(act *scenarios.Act) []string {
+	var categories []string
+	if act.Location != "" {
+		categories = append(categories, "location")
+	}
+	if act.TOD != "" {
+		categories = append(categories, "time")
+	}
+	if act.Atmosphere != "" {
+		categories = append(categories, "atmosphere")
+	}
+	if act.Description != "" {
+		categories = append(categories, "context")
+	}
+	return categories
+}
+
+// ageSceneMemories keeps query_scene on the current setting as an act begins.
+// Scene memories of what the act replaces are archived, and each is kept once
+// as an episodic memory of how things were before, so agents can still
+// recall it; the act's setting is then seeded in their place.
+func (s *Simulation) ageSceneMemories(ctx context.Context, turn int, act *scenarios.Act, number int) error {
+	if s.MemoryStore == nil {
+		return nil
+	}
+	categories := actCategories(act)
+
+	var stale []string
+	remembered := make(map[string]bool)
+	for _, mem := range s.MemoryStore.Memories(memory.Filter{Type: "scene"}) {
+		if !slices.Contains(categories, mem.Metadata["category"]) {
+			continue
+		}
+		stale = append(stale, mem.ID)
+		if remembered[mem.Content] {
+			continue
+		}
+		remembered[mem.Content] = true
+		fromAct := mem.Metadata["act"]
+		if fromAct == "" {
+			fromAct = "1"
+		}
+
+		content := fmt.Sprintf("Before turn %d: %s", turn, mem.Content)
+		embedding, err := s.MemoryStore.Embed(ctx, content)
+		if err != nil {
+			return fmt.Errorf("failed to embed scene history: %w", err)
+		}
+		s.MemoryStore.Add(memory.Memory{
+			Content:   content,
+			Embedding: embedding,
+			Metadata: map[string]string{
+				"type":     "episodic",
+				"category": "scene",
+				"turn":     strconv.Itoa(turn - 1),
+				"act":      fromAct,
+				"run":      s.ID.String(),
+			},
+		})
+	}
+
+	// Archived last, so a failed seeding leaves the old setting searchable
+	setting := memory.Setting{
+		Location:    act.Location,
+		Time:        act.TOD,
+		Atmosphere:  act.Atmosphere,
+		Description: act.Description,
+	}
+	extra := map[string]string{"act": strconv.Itoa(number), "run": s.ID.String()}
+	if err := memory.SeedSetting(ctx, s.MemoryStore, setting, extra); err != nil {
+		return err
+	}
+	s.MemoryStore.Archive(stale...)
+	slog.Debug("scene memories aged", "act", number, "archived", len(stale), "history", len(remembered))
+	return nil
+}
@@ -0,0 +1,75 @@
+package simulations
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/oklog/ulid/v2"
+	mcpsim "github.com/poiesic/wonda/internal/mcp/simulation"
+	"github.com/poiesic/wonda/internal/memory"
+	"github.com/poiesic/wonda/internal/scenarios"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBeginAct(t *testing.T) {
+	ctx := context.Background()
+	newSim := func(t *testing.T) *Simulation {
+		scenario := &scenarios.Scenario{
+			Basics: &scenarios.BasicScenarioInformation{
+				Location:   "The dinner party",
+				TOD:        "8 PM",
+				Atmosphere: "Festive",
+			},
+			Acts: []*scenarios.Act{
+				{Turn: 3, Location: "The parking lot", Description: "The party is over."},
+			},
+		}
+		sim := &Simulation{
+			ID:          ulid.Make(),
+			Scenario:    scenario,
+			World:       mcpsim.NewWorldState(scenario.Basics.Location, scenario.Basics.Atmosphere),
+			MemoryStore: memory.NewStore(lengthEmbedder{}),
+		}
+		require.NoError(t, memory.SeedScenario(ctx, sim.MemoryStore, scenario))
+		return sim
+	}
+	sceneContents := func(sim *Simulation) []string {
+		var contents []string
+		for _, mem := range sim.MemoryStore.Memories(memory.Filter{Type: "scene"}) {
+			if !slices.Contains(contents, mem.Content) {
+				contents = append(contents, mem.Content)
+			}
+		}
+		return contents
+	}
+
+	t.Run("nothing changes between acts", func(t *testing.T) {
+		sim := newSim(t)
+		sim.beginAct(ctx, 2)
+		assert.Equal(t, []string{"Location: The dinner party", "Atmosphere: Festive", "Time: 8 PM"}, sceneContents(sim))
+		assert.Empty(t, sim.currentAmbient)
+	})
+
+	t.Run("replaces the scene the act changes", func(t *testing.T) {
+		sim := newSim(t)
+		sim.beginAct(ctx, 3)
+
+		assert.Equal(t, []string{"Atmosphere: Festive", "Time: 8 PM", "Location: The parking lot", "The party is over."}, sceneContents(sim))
+		assert.Equal(t, "The parking lot", sim.World.Snapshot().Location)
+		assert.Equal(t, []string{"The party is over."}, sim.currentAmbient)
+
+		history := sim.MemoryStore.Memories(memory.Filter{Type: "episodic", Category: "scene"})
+		require.Len(t, history, 1, "one memory of the old location, however many queries found it")
+		assert.Equal(t, "Before turn 3: Location: The dinner party", history[0].Content)
+		assert.Equal(t, "2", history[0].Metadata["turn"])
+		assert.Equal(t, "1", history[0].Metadata["act"])
+	})
+
+	t.Run("restores the location on resume", func(t *testing.T) {
+		sim := newSim(t)
+		sim.resumeActs(4)
+		assert.Equal(t, "The parking lot", sim.World.Snapshot().Location)
+	})
+}
@@ -5,8 +5,11 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
 	"text/template"
 
+	"github.com/poiesic/wonda/internal/config"
+	"github.com/poiesic/wonda/internal/guardrails"
 	"github.com/poiesic/wonda/internal/mcp"
 	"github.com/poiesic/wonda/internal/prompts"
 	"github.com/poiesic/wonda/internal/scenarios"
@@ -44,6 +47,28 @@ type Agent struct {
 	// Configuration
 	Model    string
 	Provider string
+
+	// Generation parameters the agent's requests set over its model's
+	Generation config.Generation
+
+	// Content policy applied to output before it is executed (nil disables)
+	Guard *guardrails.Guard
+
+	// Stream receives the message and thinking so far while a response streams in (nil disables).
+	// Agents with guardrails never stream, since output must pass the policy first.
+	Stream func(message, thinking string)
+
+	// Times to retry a refused response with a softened prompt
+	RefusalRetries int
+
+	// Human agents are played by a person, whose words are never taken for refusals
+	Human bool
+
+	// LLM calls the agent may make in one turn while using tools
+	MaxToolIterations int
+
+	// Prompt templates, with the run's overrides (nil uses the embedded ones)
+	Prompts *prompts.Library
 }
 
 // NewAgent creates a new agent from a character definition and LLM client.
@@ -112,21 +137,51 @@ func (a *Agent) Think(ctx context.Context, situation string, sceneCtx *SceneCont
 		{Role: "user", Content: systemPrompt},
 	}
 
-	// Tool execution loop - max 50 iterations to allow for complex workflows like voting
-	maxIterations := 50
+	// Tool execution loop - enough iterations to allow for complex workflows like voting
+	maxIterations := a.MaxToolIterations
+	if maxIterations <= 0 {
+		maxIterations = DefaultSpeedProfile().MaxToolIterations
+	}
+	// Ensemble candidates from every step of the loop, for the chronicle
+	var candidates []EnsembleCandidate
+	// Refusals from every step of the loop, for the chronicle
+	var refusals []Refusal
+	// Tools executed at every step of the loop, for the chronicle
+	var invocations []ToolInvocation
 	for iteration := 0; iteration < maxIterations; iteration++ {
 		// Call LLM
 		req := ChatRequest{
-			Messages: messages,
-			Model:    a.Model,
-			Tools:    tools,
+			Messages:   messages,
+			Model:      a.Model,
+			Tools:      tools,
+			Generation: a.Generation,
 		}
 
-		response, err := a.Client.Chat(ctx, req)
+		response, err := a.chat(ctx, req)
 		if err != nil {
 			return ChatResponse{}, fmt.Errorf("LLM call failed: %w", err)
 		}
 
+		// Check output against content policy before anything is executed
+		if a.Guard != nil {
+			response, err = a.applyGuardrails(ctx, req, response)
+			if err != nil {
+				return ChatResponse{}, err
+			}
+		}
+		// Retry responses the model refused or the provider filtered
+		response, err = a.handleRefusals(ctx, req, response)
+		if err != nil {
+			return ChatResponse{}, err
+		}
+		refusals = append(refusals, response.Refusals...)
+		response.Refusals = refusals
+
+		candidates = append(candidates, response.Candidates...)
+		response.Candidates = candidates
+		response.Invocations = invocations
+		response.Iterations = iteration + 1
+
 		// If no tool calls, we're done
 		if len(response.ToolCalls) == 0 {
 			return response, nil
@@ -150,6 +205,11 @@ func (a *Agent) Think(ctx context.Context, situation string, sceneCtx *SceneCont
 				Arguments: toolCall.Arguments,
 			}
 			result := executor.ExecuteTool(ctx, mcpToolCall)
+			invocation := ToolInvocation{Call: toolCall}
+			if result.IsError {
+				invocation.Error = fmt.Sprint(result.Content)
+			}
+			invocations = append(invocations, invocation)
 
 			// Check if this tool ends the turn
 			if result.EndsTurn {
@@ -180,6 +240,7 @@ func (a *Agent) Think(ctx context.Context, situation string, sceneCtx *SceneCont
 
 		// If a turn-ending tool was called, stop the loop
 		if turnEnded {
+			response.Invocations = invocations
 			return response, nil
 		}
 	}
@@ -190,12 +251,27 @@ func (a *Agent) Think(ctx context.Context, situation string, sceneCtx *SceneCont
 	}, fmt.Errorf("maximum tool execution iterations (%d) reached", maxIterations)
 }
 
+// chat sends a request to the agent's LLM, streaming the message when possible.
+func (a *Agent) chat(ctx context.Context, req ChatRequest) (ChatResponse, error) {
+	streaming, ok := a.Client.(StreamingClient)
+	if !ok || a.Stream == nil || a.Guard != nil {
+		return a.Client.Chat(ctx, req)
+	}
+
+	var message, thinking strings.Builder
+	return streaming.ChatStream(ctx, req, func(delta StreamDelta) {
+		message.WriteString(delta.Message)
+		thinking.WriteString(delta.Thinking)
+		a.Stream(message.String(), thinking.String())
+	})
+}
+
 // buildPrompt creates the full prompt using the template system.
-// The prompt template is loaded from the prompts package.
+// The prompt template is loaded from the agent's prompt library.
 // If sceneCtx is provided (typically on turn 1), it includes scene information.
 func (a *Agent) buildPrompt(situation string, sceneCtx *SceneContext) (string, error) {
 	// Get prompt template
-	promptTemplate, err := prompts.GetPrompt("agent_turn")
+	promptTemplate, err := a.Prompts.Get("agent_turn")
 	if err != nil {
 		return "", fmt.Errorf("failed to load agent turn prompt: %w", err)
 	}
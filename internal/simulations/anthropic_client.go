@@ -3,8 +3,12 @@ package simulations
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
+	"net/http"
+	neturl "net/url"
+	"strings"
 
 	anthropic "github.com/liushuangls/go-anthropic/v2"
 
@@ -17,8 +21,15 @@ type AnthropicClient struct {
 	model   *config.Model
 	parser  ResponseParser
 	modelID string
+	baseURL string
+	apiKey  string
+
+	httpClient *http.Client // Retries transient failures per the provider's policy
 }
 
+// anthropicBaseURL is Anthropic's API endpoint, used when the provider doesn't override it.
+const anthropicBaseURL = "https://api.anthropic.com/v1"
+
 // newAnthropicClient creates a new Anthropic client.
 func newAnthropicClient(provider *config.Provider, model *config.Model, parser ResponseParser) (*AnthropicClient, error) {
 	// Get API key
@@ -29,11 +40,15 @@ func newAnthropicClient(provider *config.Provider, model *config.Model, parser R
 
 	// Create Anthropic client
 	// Note: Only override base URL if it's different from the default
+	httpClient := newHTTPClient(provider)
 	opts := []anthropic.ClientOption{
 		anthropic.WithAPIVersion(anthropic.APIVersion20230601),
+		anthropic.WithHTTPClient(httpClient),
 	}
+	baseURL := anthropicBaseURL
 	if provider.BaseURL != "" && provider.BaseURL != "https://api.anthropic.com" {
 		opts = append(opts, anthropic.WithBaseURL(provider.BaseURL))
+		baseURL = provider.BaseURL
 	}
 	client := anthropic.NewClient(apiKey, opts...)
 
@@ -42,9 +57,32 @@ func newAnthropicClient(provider *config.Provider, model *config.Model, parser R
 		model:   model,
 		parser:  parser,
 		modelID: model.Name,
+		baseURL: baseURL,
+		apiKey:  apiKey,
+
+		httpClient: httpClient,
 	}, nil
 }
 
+// CheckModel looks the model up on Anthropic's models endpoint.
+func (c *AnthropicClient) CheckModel(ctx context.Context) error {
+	url := strings.TrimRight(c.baseURL, "/") + "/models/" + neturl.PathEscape(c.modelID)
+	httpReq, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("x-api-key", c.apiKey)
+	httpReq.Header.Set("anthropic-version", string(anthropic.APIVersion20230601))
+
+	if _, err := doPreflightRequest(httpReq); err != nil {
+		if errors.Is(err, errPreflightNotFound) {
+			return fmt.Errorf("model %s is not available from the provider", c.modelID)
+		}
+		return err
+	}
+	return nil
+}
+
 // Chat sends a chat completion request to Anthropic's API.
 func (c *AnthropicClient) Chat(ctx context.Context, req ChatRequest) (ChatResponse, error) {
 	// Convert messages to Anthropic format
@@ -85,16 +123,24 @@ func (c *AnthropicClient) Chat(ctx context.Context, req ChatRequest) (ChatRespon
 	}
 
 	// Create message request
+	gen := requestGeneration(c.model, req)
 	msgReq := anthropic.MessagesRequest{
-		Model:     anthropic.Model(modelID),
-		Messages:  messages,
-		MaxTokens: 4096, // Default max tokens
+		Model:         anthropic.Model(modelID),
+		Messages:      messages,
+		MaxTokens:     anthropicMaxTokens(gen),
+		StopSequences: gen.Stop,
 	}
 
 	// Add system prompt if present
 	if systemPrompt != "" {
 		msgReq.System = systemPrompt
 	}
+	if gen.Temperature != nil {
+		msgReq.SetTemperature(float32(*gen.Temperature))
+	}
+	if gen.TopP != nil {
+		msgReq.SetTopP(float32(*gen.TopP))
+	}
 
 	// Add tools if provided
 	if len(req.Tools) > 0 {
@@ -177,5 +223,10 @@ func (c *AnthropicClient) Chat(ctx context.Context, req ChatRequest) (ChatRespon
 		Message:   content,
 		Thinking:  thinking,
 		ToolCalls: toolCalls,
+		Usage: Usage{
+			InputTokens:  resp.Usage.InputTokens,
+			OutputTokens: resp.Usage.OutputTokens,
+		},
+		FinishReason: string(resp.StopReason),
 	}, nil
 }
@@ -0,0 +1,241 @@
+package simulations
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// anthropicAPIVersion is the Messages API version streamed requests are made
+// against, the same one the SDK client is created with.
+const anthropicAPIVersion = "2023-06-01"
+
+// anthropicStreamEvent is one server-sent event of a streamed Messages response.
+type anthropicStreamEvent struct {
+	Type    string `json:"type"`
+	Index   int    `json:"index"`
+	Message *struct {
+		Usage struct {
+			InputTokens int `json:"input_tokens"`
+		} `json:"usage"`
+	} `json:"message"`
+	ContentBlock *struct {
+		Type string `json:"type"`
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"content_block"`
+	Delta *struct {
+		Type        string `json:"type"`
+		Text        string `json:"text"`
+		Thinking    string `json:"thinking"`
+		PartialJSON string `json:"partial_json"`
+		StopReason  string `json:"stop_reason"`
+	} `json:"delta"`
+	Usage *struct {
+		OutputTokens int `json:"output_tokens"`
+	} `json:"usage"`
+	Error *struct {
+		Type    string `json:"type"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// ChatStream implements StreamingClient, streaming text and extended thinking.
+// Thinking delimiters can't be separated from partial content, so models with
+// an in-band parser fall back to Chat and deliver no deltas.
+func (c *AnthropicClient) ChatStream(ctx context.Context, req ChatRequest, onDelta func(delta StreamDelta)) (ChatResponse, error) {
+	if _, isNoOp := c.parser.(*NoOpParser); !isNoOp {
+		return c.Chat(ctx, req)
+	}
+
+	modelID := req.Model
+	if modelID == "" {
+		modelID = c.modelID
+	}
+	reqBody, err := anthropicStreamRequest(req, modelID)
+	if err != nil {
+		return ChatResponse{}, err
+	}
+	applyAnthropicGeneration(reqBody, requestGeneration(c.model, req))
+
+	jsonBody, err := json.Marshal(reqBody)
+	if err != nil {
+		return ChatResponse{}, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	url := strings.TrimRight(c.baseURL, "/") + "/messages"
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(jsonBody))
+	if err != nil {
+		return ChatResponse{}, fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Accept", "text/event-stream")
+	httpReq.Header.Set("x-api-key", c.apiKey)
+	httpReq.Header.Set("anthropic-version", anthropicAPIVersion)
+
+	httpResp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return ChatResponse{}, fmt.Errorf("http request failed: %w", err)
+	}
+	defer httpResp.Body.Close()
+
+	if httpResp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(httpResp.Body)
+		return ChatResponse{}, fmt.Errorf("anthropic api error (status %d): %s", httpResp.StatusCode, string(respBody))
+	}
+
+	return readAnthropicStream(httpResp.Body, onDelta)
+}
+
+// anthropicStreamRequest builds a streamed Messages request body, converting
+// messages the same way Chat does.
+func anthropicStreamRequest(req ChatRequest, modelID string) (map[string]interface{}, error) {
+	var systemPrompt string
+	messages := make([]map[string]interface{}, 0, len(req.Messages))
+	for _, msg := range req.Messages {
+		switch msg.Role {
+		case "system":
+			if systemPrompt != "" {
+				systemPrompt += "\n\n"
+			}
+			systemPrompt += msg.Content
+		case "user", "assistant", "tool":
+			// Anthropic expects tool results as user messages
+			if msg.Content == "" {
+				continue
+			}
+			role := msg.Role
+			if role == "tool" {
+				role = "user"
+			}
+			messages = append(messages, map[string]interface{}{"role": role, "content": msg.Content})
+		default:
+			return nil, fmt.Errorf("unsupported message role: %s", msg.Role)
+		}
+	}
+
+	reqBody := map[string]interface{}{
+		"model":      modelID,
+		"messages":   messages,
+		"max_tokens": anthropicDefaultMaxTokens,
+		"stream":     true,
+	}
+	if systemPrompt != "" {
+		reqBody["system"] = systemPrompt
+	}
+	if len(req.Tools) > 0 {
+		tools := make([]map[string]interface{}, 0, len(req.Tools))
+		for _, toolDef := range req.Tools {
+			if fn, ok := toolDef["function"].(map[string]interface{}); ok {
+				tools = append(tools, map[string]interface{}{
+					"name":         fn["name"],
+					"description":  fn["description"],
+					"input_schema": fn["parameters"],
+				})
+			}
+		}
+		reqBody["tools"] = tools
+	}
+	return reqBody, nil
+}
+
+// readAnthropicStream assembles a response from a Messages event stream,
+// passing each piece of text and thinking to onDelta as it is read.
+func readAnthropicStream(body io.Reader, onDelta func(delta StreamDelta)) (ChatResponse, error) {
+	var content, thinking strings.Builder
+	var response ChatResponse
+	toolCalls := make(map[int]*streamedToolCall) // Tool use blocks by index
+	var toolOrder []int
+
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		data, ok := strings.CutPrefix(strings.TrimSpace(scanner.Text()), "data:")
+		if !ok {
+			continue // Event names, blank separators and comments
+		}
+
+		var event anthropicStreamEvent
+		if err := json.Unmarshal([]byte(strings.TrimSpace(data)), &event); err != nil {
+			return ChatResponse{}, fmt.Errorf("failed to parse stream event: %w", err)
+		}
+
+		switch event.Type {
+		case "message_start":
+			if event.Message != nil {
+				response.Usage.InputTokens = event.Message.Usage.InputTokens
+			}
+		case "content_block_start":
+			if event.ContentBlock == nil {
+				continue
+			}
+			blockType := event.ContentBlock.Type
+			switch {
+			case blockType == "text" && content.Len() > 0:
+				content.WriteString("\n")
+			case blockType == "thinking" && thinking.Len() > 0:
+				thinking.WriteString("\n\n")
+			case blockType == "tool_use":
+				toolCalls[event.Index] = &streamedToolCall{id: event.ContentBlock.ID, name: event.ContentBlock.Name}
+				toolOrder = append(toolOrder, event.Index)
+			}
+		case "content_block_delta":
+			if event.Delta == nil {
+				continue
+			}
+			switch event.Delta.Type {
+			case "text_delta":
+				content.WriteString(event.Delta.Text)
+				if onDelta != nil {
+					onDelta(StreamDelta{Message: event.Delta.Text})
+				}
+			case "thinking_delta":
+				thinking.WriteString(event.Delta.Thinking)
+				if onDelta != nil {
+					onDelta(StreamDelta{Thinking: event.Delta.Thinking})
+				}
+			case "input_json_delta":
+				if call := toolCalls[event.Index]; call != nil {
+					call.arguments.WriteString(event.Delta.PartialJSON)
+				}
+			}
+		case "message_delta":
+			if event.Delta != nil && event.Delta.StopReason != "" {
+				response.FinishReason = event.Delta.StopReason
+			}
+			if event.Usage != nil {
+				response.Usage.OutputTokens = event.Usage.OutputTokens
+			}
+		case "error":
+			if event.Error != nil {
+				return ChatResponse{}, fmt.Errorf("anthropic api error: %s: %s", event.Error.Type, event.Error.Message)
+			}
+			return ChatResponse{}, fmt.Errorf("anthropic api error")
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return ChatResponse{}, fmt.Errorf("failed to read stream: %w", err)
+	}
+
+	response.Message = content.String()
+	response.Thinking = thinking.String()
+	for _, index := range toolOrder {
+		call := toolCalls[index]
+		var args map[string]interface{}
+		if err := json.Unmarshal([]byte(call.arguments.String()), &args); err != nil {
+			// If parsing fails, use empty args
+			args = make(map[string]interface{})
+		}
+		response.ToolCalls = append(response.ToolCalls, ToolCall{
+			ID:        call.id,
+			Name:      call.name,
+			Arguments: args,
+		})
+	}
+	return response, nil
+}
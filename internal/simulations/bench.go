@@ -0,0 +1,152 @@
+package simulations
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"maps"
+	"os"
+	"path"
+	"slices"
+	"time"
+
+	"github.com/poiesic/wonda/internal/config"
+	"github.com/poiesic/wonda/internal/memory"
+	"github.com/poiesic/wonda/internal/scenarios"
+)
+
+// BenchOptions sizes a benchmark run over a generated scenario.
+type BenchOptions struct {
+	scenarios.FixtureOptions
+	Queries int // Memory searches timed in the retrieval stage
+}
+
+// BenchStage is the time one stage of a benchmark took.
+type BenchStage struct {
+	Name     string
+	Count    int // Operations in the stage (agents, memories, queries or turns)
+	Duration time.Duration
+}
+
+// PerOp returns the stage's mean time per operation.
+func (s BenchStage) PerOp() time.Duration {
+	if s.Count == 0 {
+		return 0
+	}
+	return s.Duration / time.Duration(s.Count)
+}
+
+// BenchReport holds the timings of a benchmark run.
+type BenchReport struct {
+	Options BenchOptions
+	Stages  []BenchStage
+	Turns   []time.Duration // Duration of each turn played
+}
+
+// RunBench generates a scenario of the given size and times loading it,
+// initializing a dry-run simulation, seeding and searching episodic memories,
+// and playing turns against the mock LLM client. Providers, models and the
+// embedding come from the config directory; everything the run writes goes
+// to a temporary directory that is removed afterwards.
+func RunBench(ctx context.Context, configDir string, opts BenchOptions) (*BenchReport, error) {
+	if opts.Model == "" {
+		models, err := config.LoadModelsFromDir(path.Join(configDir, "models"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to load models: %w", err)
+		}
+		if len(models) == 0 {
+			return nil, fmt.Errorf("no models configured; add one or name one to benchmark with")
+		}
+		opts.Model = slices.Sorted(maps.Keys(models))[0]
+	}
+	report := &BenchReport{Options: opts}
+	stage := func(name string, count int, start time.Time) {
+		report.Stages = append(report.Stages, BenchStage{Name: name, Count: count, Duration: time.Since(start)})
+	}
+
+	// Generate
+	start := time.Now()
+	fixture, err := scenarios.GenerateFixture(opts.FixtureOptions)
+	if err != nil {
+		return nil, err
+	}
+	stage("generate", opts.Agents, start)
+
+	// Load, as a run does from files
+	start = time.Now()
+	scenario, err := scenarios.LoadScenario(fixture.Scenario)
+	if err != nil {
+		return nil, err
+	}
+	characters := make(map[string]*scenarios.Character, len(fixture.Characters))
+	for name, data := range fixture.Characters {
+		character, err := scenarios.LoadCharacter(data)
+		if err != nil {
+			return nil, fmt.Errorf("character %s: %w", name, err)
+		}
+		if err := character.Validate(); err != nil {
+			return nil, fmt.Errorf("character %s: %w", name, err)
+		}
+		characters[name] = character
+	}
+	stage("load", opts.Agents, start)
+
+	outputDir, err := os.MkdirTemp("", "wonda-bench-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(outputDir)
+
+	// Initialize: embedding, scene and character seeding, agents
+	sim := NewSimulation(scenario, configDir)
+	sim.DryRun = &MockScript{Seed: opts.Seed}
+	sim.Characters = characters
+	sim.OutputDir = outputDir
+	start = time.Now()
+	if err := sim.Initialize(ctx); err != nil {
+		return nil, fmt.Errorf("failed to initialize simulation: %w", err)
+	}
+	defer sim.Close()
+	stage("initialize", opts.Agents, start)
+
+	// Seed episodic memories
+	start = time.Now()
+	seeded := 0
+	for _, agentName := range sim.TurnOrder {
+		for _, content := range fixture.Memories[agentName] {
+			sim.captureEpisodicMemory(ctx, agentName, content, 0)
+			seeded++
+		}
+	}
+	stage("seed", seeded, start)
+
+	// Retrieve
+	start = time.Now()
+	for i := range opts.Queries {
+		query := fixture.Queries[i%len(fixture.Queries)]
+		if _, err := sim.MemoryStore.SearchByCanonicalQuery(ctx, query, memory.Filter{Type: "episodic"}, sim.speed().MemoryResults); err != nil {
+			return nil, fmt.Errorf("memory search failed: %w", err)
+		}
+	}
+	stage("retrieve", opts.Queries, start)
+
+	// Play turns with the mock client, timing each from its start to the next
+	var turnStart time.Time
+	sim.OnTurnStart(func(ctx context.Context, turn int) {
+		if !turnStart.IsZero() {
+			report.Turns = append(report.Turns, time.Since(turnStart))
+		}
+		turnStart = time.Now()
+	})
+	start = time.Now()
+	if err := sim.Start(ctx); err != nil {
+		return nil, fmt.Errorf("simulation failed: %w", err)
+	}
+	if !turnStart.IsZero() {
+		report.Turns = append(report.Turns, time.Since(turnStart))
+	}
+	stage("turns", len(report.Turns), start)
+
+	slog.Info("benchmark complete", "agents", opts.Agents, "goals", opts.Goals, "memories", seeded, "turns", len(report.Turns))
+	return report, nil
+}
@@ -0,0 +1,45 @@
+package simulations
+
+import (
+	"testing"
+	"time"
+
+	"github.com/poiesic/wonda/internal/scenarios"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateFixture(t *testing.T) {
+	opts := scenarios.FixtureOptions{Agents: 15, Goals: 3, Memories: 4, Turns: 6, Model: "mock", Seed: 7}
+	fixture, err := scenarios.GenerateFixture(opts)
+	require.NoError(t, err)
+
+	scenario, err := scenarios.LoadScenario(fixture.Scenario)
+	require.NoError(t, err)
+	assert.Len(t, scenario.Agents, 15)
+	assert.Len(t, scenario.Goals, 3)
+	assert.Equal(t, 6, scenario.Basics.MaxTurns)
+	assert.Contains(t, scenario.Agents, "Ada2", "names are numbered once the list runs out")
+
+	for _, agent := range scenario.Agents {
+		data, ok := fixture.Characters[agent.Character]
+		require.True(t, ok, "agent %s has no character", agent.Name)
+		character, err := scenarios.LoadCharacter(data)
+		require.NoError(t, err)
+		assert.NoError(t, character.Validate())
+		assert.Len(t, fixture.Memories[agent.Name], 4)
+	}
+
+	again, err := scenarios.GenerateFixture(opts)
+	require.NoError(t, err)
+	assert.Equal(t, fixture.Scenario, again.Scenario)
+	assert.Equal(t, fixture.Memories, again.Memories)
+
+	_, err = scenarios.GenerateFixture(scenarios.FixtureOptions{Agents: 2, Goals: 0, Model: "mock"})
+	assert.ErrorContains(t, err, "at least 1 goal")
+}
+
+func TestBenchStagePerOp(t *testing.T) {
+	assert.Equal(t, 250*time.Millisecond, BenchStage{Count: 4, Duration: time.Second}.PerOp())
+	assert.Zero(t, BenchStage{Duration: time.Second}.PerOp())
+}
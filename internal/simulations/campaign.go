@@ -0,0 +1,87 @@
+package simulations
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/poiesic/wonda/internal/campaigns"
+	mcpsim "github.com/poiesic/wonda/internal/mcp/simulation"
+)
+
+// loadCampaignRelationships seeds the world with relationships between this
+// scenario's agents from earlier scenarios of its campaign.
+func (s *Simulation) loadCampaignRelationships() error {
+	campaign := s.Scenario.Basics.Campaign
+	if campaign == "" {
+		return nil
+	}
+
+	store, err := campaigns.LoadRelationships(s.ConfigDir, campaign)
+	if err != nil {
+		return err
+	}
+
+	loaded := 0
+	for from := range s.Scenario.Agents {
+		for to := range s.Scenario.Agents {
+			rel, ok := store.Get(from, to)
+			if !ok || from == to {
+				continue
+			}
+			s.World.SetRelationship(mcpsim.Relationship{
+				From:        rel.From,
+				To:          rel.To,
+				Value:       rel.Value,
+				Trust:       rel.Trust,
+				Familiarity: rel.Familiarity,
+				Reason:      rel.Reason,
+			})
+			loaded++
+		}
+	}
+	slog.Info("campaign relationships loaded", "campaign", campaign, "relationships", loaded)
+	return nil
+}
+
+// saveCampaignRelationships writes relationships that changed during the
+// simulation back to the campaign, so they carry into its next scenario.
+func (s *Simulation) saveCampaignRelationships() error {
+	campaign := s.Scenario.Basics.Campaign
+	if campaign == "" {
+		return nil
+	}
+
+	// Reload in case another run of the campaign saved since this one started
+	store, err := campaigns.LoadRelationships(s.ConfigDir, campaign)
+	if err != nil {
+		return err
+	}
+
+	saved := 0
+	now := s.Clock.Now()
+	for _, rel := range s.World.RelationshipList() {
+		if !rel.Changed {
+			continue
+		}
+		store.Set(campaigns.Relationship{
+			From:        rel.From,
+			To:          rel.To,
+			Value:       rel.Value,
+			Trust:       rel.Trust,
+			Familiarity: rel.Familiarity,
+			Reason:      rel.Reason,
+			Scenario:    s.Scenario.Basics.Name,
+			UpdatedAt:   now,
+		})
+		saved++
+	}
+	if saved == 0 {
+		return nil
+	}
+
+	if err := store.Save(s.ConfigDir); err != nil {
+		return fmt.Errorf("failed to save campaign relationships: %w", err)
+	}
+	slog.Info("campaign relationships saved", "campaign", campaign, "relationships", saved)
+	return nil
+}
@@ -0,0 +1,201 @@
+package simulations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrChaos is returned by requests that chaos mode made fail.
+var ErrChaos = errors.New("chaos: injected provider error")
+
+// ChaosConfig sets how often chaos mode injects each kind of failure.
+// Rates are probabilities per LLM request, from 0 to 1.
+type ChaosConfig struct {
+	ErrorRate     float64       // Fail the request with ErrChaos
+	SlowRate      float64       // Delay the request by SlowDelay
+	SlowDelay     time.Duration // How long slow requests are delayed
+	MalformedRate float64       // Corrupt the arguments of each tool call
+	TruncateRate  float64       // Cut the response message short
+	Seed          *int64        // Random seed; nil picks one from the clock
+}
+
+// DefaultChaosConfig returns the rates used by a bare `--chaos on`.
+func DefaultChaosConfig() *ChaosConfig {
+	return &ChaosConfig{
+		ErrorRate:     0.1,
+		SlowRate:      0.1,
+		SlowDelay:     5 * time.Second,
+		MalformedRate: 0.1,
+		TruncateRate:  0.1,
+	}
+}
+
+// ParseChaosSpec parses a chaos specification such as
+// "errors=0.2,slow=0.1,delay=2s,malformed=0.05,truncate=0.1,seed=42".
+// "on" selects the default rates; failure kinds not named in a spec are disabled.
+func ParseChaosSpec(spec string) (*ChaosConfig, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "on" || spec == "default" {
+		return DefaultChaosConfig(), nil
+	}
+
+	cfg := &ChaosConfig{SlowDelay: DefaultChaosConfig().SlowDelay}
+	for _, part := range strings.Split(spec, ",") {
+		key, val, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid chaos setting '%s' (expected key=value)", part)
+		}
+		key = strings.TrimSpace(key)
+		val = strings.TrimSpace(val)
+
+		var err error
+		switch key {
+		case "errors":
+			cfg.ErrorRate, err = parseRate(val)
+		case "slow":
+			cfg.SlowRate, err = parseRate(val)
+		case "malformed":
+			cfg.MalformedRate, err = parseRate(val)
+		case "truncate":
+			cfg.TruncateRate, err = parseRate(val)
+		case "delay":
+			cfg.SlowDelay, err = parseDelay(val)
+		case "seed":
+			var seed int64
+			seed, err = strconv.ParseInt(val, 10, 64)
+			cfg.Seed = &seed
+		default:
+			return nil, fmt.Errorf("unknown chaos setting '%s' (use errors, slow, delay, malformed, truncate, or seed)", key)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid chaos %s: %w", key, err)
+		}
+	}
+	return cfg, nil
+}
+
+// parseRate parses a probability between 0 and 1.
+func parseRate(val string) (float64, error) {
+	rate, err := strconv.ParseFloat(val, 64)
+	if err != nil {
+		return 0, err
+	}
+	if rate < 0 || rate > 1 {
+		return 0, fmt.Errorf("rate must be between 0 and 1 (got %v)", rate)
+	}
+	return rate, nil
+}
+
+// parseDelay parses how long slow requests are delayed.
+func parseDelay(val string) (time.Duration, error) {
+	delay, err := time.ParseDuration(val)
+	if err != nil {
+		return 0, err
+	}
+	if delay < 0 {
+		return 0, fmt.Errorf("delay can't be negative (got %v)", delay)
+	}
+	return delay, nil
+}
+
+// chaosRand is a random source shared by every chaos client in a simulation.
+// It is safe for concurrent use (ensemble samples run in parallel).
+type chaosRand struct {
+	mu  sync.Mutex
+	rng *rand.Rand
+}
+
+func newChaosRand(seed *int64) *chaosRand {
+	source := time.Now().UnixNano()
+	if seed != nil {
+		source = *seed
+	}
+	return &chaosRand{rng: rand.New(rand.NewSource(source))}
+}
+
+// hit reports whether an event with the given probability happens.
+func (r *chaosRand) hit(rate float64) bool {
+	if rate <= 0 {
+		return false
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.rng.Float64() < rate
+}
+
+// intn returns a random int in [0, n).
+func (r *chaosRand) intn(n int) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.rng.Intn(n)
+}
+
+// chaosClient injects failures into requests made through it.
+type chaosClient struct {
+	client Client
+	model  string
+	config *ChaosConfig
+	rand   *chaosRand
+}
+
+// Chat implements Client.
+func (c *chaosClient) Chat(ctx context.Context, req ChatRequest) (ChatResponse, error) {
+	if c.rand.hit(c.config.ErrorRate) {
+		slog.Warn("chaos: failing request", "model", c.model)
+		return ChatResponse{}, ErrChaos
+	}
+
+	if c.rand.hit(c.config.SlowRate) {
+		slog.Warn("chaos: delaying request", "model", c.model, "delay", c.config.SlowDelay)
+		select {
+		case <-time.After(c.config.SlowDelay):
+		case <-ctx.Done():
+			return ChatResponse{}, ctx.Err()
+		}
+	}
+
+	resp, err := c.client.Chat(ctx, req)
+	if err != nil {
+		return resp, err
+	}
+
+	for i := range resp.ToolCalls {
+		if c.rand.hit(c.config.MalformedRate) {
+			slog.Warn("chaos: corrupting tool arguments", "model", c.model, "tool", resp.ToolCalls[i].Name)
+			resp.ToolCalls[i].Arguments = c.corruptArguments(resp.ToolCalls[i].Arguments)
+		}
+	}
+
+	if resp.Message != "" && c.rand.hit(c.config.TruncateRate) {
+		runes := []rune(resp.Message)
+		cut := c.rand.intn(len(runes))
+		slog.Warn("chaos: truncating response", "model", c.model, "from", len(runes), "to", cut)
+		resp.Message = string(runes[:cut])
+	}
+
+	return resp, nil
+}
+
+// corruptArguments returns arguments that fail validation in one of a few ways:
+// all dropped, every value the wrong type, or an unexpected extra argument only.
+func (c *chaosClient) corruptArguments(args map[string]interface{}) map[string]interface{} {
+	switch c.rand.intn(3) {
+	case 0:
+		return map[string]interface{}{}
+	case 1:
+		corrupted := make(map[string]interface{}, len(args))
+		for key := range args {
+			corrupted[key] = 42
+		}
+		return corrupted
+	default:
+		return map[string]interface{}{"chaos": true}
+	}
+}
@@ -0,0 +1,67 @@
+package simulations
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseChaosSpec(t *testing.T) {
+	seed := func(n int64) *int64 { return &n }
+	tests := []struct {
+		name    string
+		spec    string
+		want    *ChaosConfig
+		wantErr string
+	}{
+		{name: "on", spec: "on", want: DefaultChaosConfig()},
+		{name: "default", spec: " default ", want: DefaultChaosConfig()},
+		{
+			name: "every setting",
+			spec: "errors=0.2, slow=0.1,delay=2s,malformed=0.05,truncate=1,seed=42",
+			want: &ChaosConfig{ErrorRate: 0.2, SlowRate: 0.1, SlowDelay: 2 * time.Second, MalformedRate: 0.05, TruncateRate: 1, Seed: seed(42)},
+		},
+		{
+			name: "unnamed kinds are disabled",
+			spec: "errors=0.5",
+			want: &ChaosConfig{ErrorRate: 0.5, SlowDelay: 5 * time.Second},
+		},
+		{name: "seed 0 is a seed", spec: "errors=0.1,seed=0", want: &ChaosConfig{ErrorRate: 0.1, SlowDelay: 5 * time.Second, Seed: seed(0)}},
+		{name: "negative seed", spec: "seed=-7", want: &ChaosConfig{SlowDelay: 5 * time.Second, Seed: seed(-7)}},
+		{name: "zero delay", spec: "slow=1,delay=0s", want: &ChaosConfig{SlowRate: 1}},
+		{name: "negative delay", spec: "slow=1,delay=-2s", wantErr: "invalid chaos delay: delay can't be negative"},
+		{name: "bad delay", spec: "delay=soon", wantErr: "invalid chaos delay"},
+		{name: "rate above 1", spec: "errors=1.5", wantErr: "rate must be between 0 and 1"},
+		{name: "negative rate", spec: "truncate=-0.1", wantErr: "rate must be between 0 and 1"},
+		{name: "bad seed", spec: "seed=abc", wantErr: "invalid chaos seed"},
+		{name: "unknown setting", spec: "floods=0.1", wantErr: "unknown chaos setting 'floods'"},
+		{name: "missing value", spec: "errors", wantErr: "expected key=value"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseChaosSpec(tt.spec)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestChaosSeed(t *testing.T) {
+	draws := func(seed *int64) []int {
+		r := newChaosRand(seed)
+		out := make([]int, 5)
+		for i := range out {
+			out[i] = r.intn(1000)
+		}
+		return out
+	}
+	zero := int64(0)
+	assert.Equal(t, draws(&zero), draws(&zero), "a seed of 0 is reproducible")
+}
@@ -0,0 +1,173 @@
+package simulations
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/oklog/ulid/v2"
+	"github.com/poiesic/wonda/internal/chronicle"
+	mcpsim "github.com/poiesic/wonda/internal/mcp/simulation"
+	"github.com/poiesic/wonda/internal/memory"
+)
+
+// Checkpoint is everything needed to continue a simulation after its last
+// completed turn: the scenario it ran, the world's progress, each agent's state
+// and the memory store. Start writes one next to the chronicle after every turn.
+type Checkpoint struct {
+	SimulationID string      `json:"simulation_id"`
+	ScenarioFile string      `json:"scenario_file,omitempty"`
+	Scenario     string      `json:"scenario"` // Raw scenario definition the run started from
+	Speed        string      `json:"speed"`
+	CiteMemories bool        `json:"cite_memories,omitempty"`
+	DryRun       *MockScript `json:"dry_run,omitempty"`
+
+	// Chronicle to continue, and its size at the end of the checkpointed turn
+	Chronicle          string `json:"chronicle"`
+	ChronicleOffset    int64  `json:"chronicle_offset"`
+	ChronicleChecksums bool   `json:"chronicle_checksums,omitempty"`
+
+	World         mcpsim.WorldCheckpoint `json:"world"`
+	Agents        map[string]AgentState  `json:"agents"`
+	Memory        memory.Snapshot        `json:"memory"`
+	RefusalCounts map[string]int         `json:"refusal_counts,omitempty"`
+	AmbientOnce   map[int]bool           `json:"ambient_once,omitempty"` // Once-only ambient events that already happened
+	Convergence   map[string][]float64   `json:"convergence,omitempty"`  // Goals' support by turn, for the turn budget
+	AtRisk        map[string]int         `json:"at_risk,omitempty"`      // Deadlines of goals projected to miss them
+}
+
+// CheckpointPath returns the path of the checkpoint file, once Start has created the chronicle.
+func (s *Simulation) CheckpointPath() string {
+	if s.chroniclePath == "" {
+		return ""
+	}
+	return strings.TrimSuffix(s.chroniclePath, ".jsonl") + ".checkpoint.json"
+}
+
+// Checkpoint writes the simulation's progress to its checkpoint file so the
+// run can be resumed with 'wonda scenarios resume'. It should be called
+// between turns, once the turn has been written to the chronicle.
+func (s *Simulation) Checkpoint() error {
+	if s.chronicleWriter == nil {
+		return fmt.Errorf("chronicle not initialized")
+	}
+	offset, err := s.chronicleWriter.Offset()
+	if err != nil {
+		return fmt.Errorf("failed to read chronicle offset: %w", err)
+	}
+
+	checkpoint := Checkpoint{
+		SimulationID:       s.ID.String(),
+		ScenarioFile:       s.ScenarioFile,
+		Scenario:           s.ScenarioSource,
+		Speed:              s.speed().Name,
+		CiteMemories:       s.CiteMemories,
+		DryRun:             s.DryRun,
+		Chronicle:          s.chroniclePath,
+		ChronicleOffset:    offset,
+		ChronicleChecksums: s.ChronicleChecksums,
+		World:              s.World.Checkpoint(),
+		Agents:             make(map[string]AgentState, len(s.Agents)),
+		RefusalCounts:      s.refusalCounts,
+		Convergence:        s.convergence,
+		AtRisk:             s.atRisk,
+	}
+	for name, agent := range s.Agents {
+		checkpoint.Agents[name] = agent.State
+	}
+	if s.MemoryStore != nil {
+		checkpoint.Memory = s.MemoryStore.Snapshot()
+	}
+	if s.ambience != nil {
+		checkpoint.AmbientOnce = s.ambience.happened
+	}
+
+	data, err := json.Marshal(checkpoint)
+	if err != nil {
+		return fmt.Errorf("failed to marshal checkpoint: %w", err)
+	}
+
+	// Write then rename, so a crash mid-write leaves the previous checkpoint intact
+	path := s.CheckpointPath()
+	if err := os.WriteFile(path+".tmp", data, 0644); err != nil {
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+	if err := os.Rename(path+".tmp", path); err != nil {
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+	return nil
+}
+
+// LoadCheckpoint reads a checkpoint file.
+func LoadCheckpoint(path string) (*Checkpoint, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read checkpoint: %w", err)
+	}
+
+	var checkpoint Checkpoint
+	if err := json.Unmarshal(data, &checkpoint); err != nil {
+		return nil, fmt.Errorf("failed to parse checkpoint: %w", err)
+	}
+	return &checkpoint, nil
+}
+
+// Resume prepares an initialized simulation to continue from a checkpoint.
+// The simulation must have been built from the checkpoint's scenario; Start
+// then picks up with the turn after the checkpoint, appending to the same chronicle.
+func (s *Simulation) Resume(checkpoint *Checkpoint) error {
+	id, err := ulid.Parse(checkpoint.SimulationID)
+	if err != nil {
+		return fmt.Errorf("invalid checkpoint simulation id: %w", err)
+	}
+
+	for name := range checkpoint.Agents {
+		if _, ok := s.Agents[name]; !ok {
+			return fmt.Errorf("checkpoint agent %s is not in the scenario", name)
+		}
+	}
+	if s.MemoryStore != nil {
+		if err := s.MemoryStore.Restore(checkpoint.Memory); err != nil {
+			return fmt.Errorf("failed to restore memories: %w", err)
+		}
+		s.MemoryStore.SetRun(id.String())
+	}
+	for name, state := range checkpoint.Agents {
+		s.Agents[name].State = state
+	}
+	for name, count := range checkpoint.RefusalCounts {
+		s.refusalCounts[name] = count
+	}
+	for goalName, history := range checkpoint.Convergence {
+		s.convergence[goalName] = history
+	}
+	for goalName, deadline := range checkpoint.AtRisk {
+		s.atRisk[goalName] = deadline
+	}
+	if s.ambience != nil {
+		for i, happened := range checkpoint.AmbientOnce {
+			s.ambience.happened[i] = happened
+		}
+	}
+
+	s.ID = id
+	s.ScenarioFile = checkpoint.ScenarioFile
+	s.ScenarioSource = checkpoint.Scenario
+	s.CiteMemories = checkpoint.CiteMemories
+	s.ChronicleChecksums = checkpoint.ChronicleChecksums
+	s.resumeFrom = checkpoint
+	return nil
+}
+
+// resumeChronicle reopens the checkpoint's chronicle for appending, dropping
+// anything written after the checkpointed turn (such as a partial turn from a crash).
+func (s *Simulation) resumeChronicle(checkpoint *Checkpoint) error {
+	writer, err := chronicle.Append(checkpoint.Chronicle, checkpoint.ChronicleOffset, checkpoint.ChronicleChecksums)
+	if err != nil {
+		return fmt.Errorf("failed to open chronicle file: %w", err)
+	}
+	s.chroniclePath = checkpoint.Chronicle
+	s.chronicleWriter = writer
+	return nil
+}
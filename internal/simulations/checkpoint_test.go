@@ -0,0 +1,69 @@
+package simulations
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/oklog/ulid/v2"
+	"github.com/poiesic/wonda/internal/chronicle"
+	mcpsim "github.com/poiesic/wonda/internal/mcp/simulation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckpointResume(t *testing.T) {
+	newSim := func() *Simulation {
+		world := mcpsim.NewWorldState("Cafe", "")
+		world.AddAgent("Alice", "table")
+		world.AddGoal(mcpsim.NewInteractiveGoal("dinner", "Pick a restaurant", "consensus", 1))
+		return &Simulation{
+			ID:            ulid.Make(),
+			World:         world,
+			Agents:        map[string]*Agent{"Alice": NewAgent("Alice", nil, nil, "local", "model")},
+			refusalCounts: make(map[string]int),
+		}
+	}
+
+	// A run that checkpointed after turn 2 and crashed partway through turn 3
+	chroniclePath := filepath.Join(t.TempDir(), "chronicle-test.jsonl")
+	written := "{\"type\":\"metadata\"}\n{\"type\":\"turn\",\"number\":1}\n{\"type\":\"turn\",\"number\":2}\n"
+	require.NoError(t, os.WriteFile(chroniclePath, []byte(written), 0644))
+	writer, err := chronicle.Append(chroniclePath, int64(len(written)), false)
+	require.NoError(t, err)
+
+	sim := newSim()
+	sim.ScenarioSource = "[basics]\nname = \"Dinner\"\n"
+	sim.chroniclePath = chroniclePath
+	sim.chronicleWriter = writer
+	sim.World.SetTurn(2)
+	sim.World.AddMessage("Alice", "I'm starving.", "", mcpsim.MessageTypeDialogue)
+	sim.Agents["Alice"].State.Emotion = "hungry"
+	sim.refusalCounts["Alice"] = 1
+	require.NoError(t, sim.Checkpoint())
+
+	require.NoError(t, writer.Write(chronicle.Turn{Type: "turn", Number: 3}))
+	require.NoError(t, writer.Close())
+
+	checkpoint, err := LoadCheckpoint(sim.CheckpointPath())
+	require.NoError(t, err)
+	assert.Equal(t, sim.ScenarioSource, checkpoint.Scenario)
+	assert.Equal(t, SpeedBalanced, checkpoint.Speed)
+
+	resumed := newSim()
+	require.NoError(t, resumed.Resume(checkpoint))
+	assert.Equal(t, sim.ID, resumed.ID)
+	assert.Equal(t, "hungry", resumed.Agents["Alice"].State.Emotion)
+	assert.Equal(t, 1, resumed.refusalCounts["Alice"])
+
+	require.NoError(t, resumed.resumeChronicle(checkpoint))
+	defer resumed.chronicleWriter.Close()
+	require.NoError(t, resumed.World.Restore(checkpoint.World))
+	assert.Equal(t, 2, resumed.World.Turn())
+	assert.Len(t, resumed.World.Snapshot().ConversationHistory, 1)
+
+	// The partial turn is dropped so the resumed run can write it again
+	data, err := os.ReadFile(chroniclePath)
+	require.NoError(t, err)
+	assert.Equal(t, written, string(data))
+}
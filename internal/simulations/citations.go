@@ -0,0 +1,57 @@
+package simulations
+
+import (
+	"log/slog"
+	"regexp"
+	"strings"
+
+	"github.com/poiesic/wonda/internal/chronicle"
+)
+
+// citeMemoriesSituation asks agents to tag the memories behind what they say
+// when a run cites memories.
+const citeMemoriesSituation = "\n\nDEBUG: Memories you recall come with an \"id\". End your response with [memories: <id>, <id>] listing the IDs of the memories that informed it, or [memories: none] if none did. The tag is removed before anyone hears you."
+
+// citationPattern matches the memory citation tag at the end of a response.
+var citationPattern = regexp.MustCompile(`(?is)\s*\[memories:\s*([^\]]*)\]\s*$`)
+
+// extractCitations removes the memory citation tag from a response and
+// returns the cited memory IDs.
+func extractCitations(message string) (string, []string) {
+	match := citationPattern.FindStringSubmatchIndex(message)
+	if match == nil {
+		return message, nil
+	}
+
+	var ids []string
+	for _, id := range strings.Split(message[match[2]:match[3]], ",") {
+		id = strings.Trim(strings.TrimSpace(id), "\"'`")
+		if id != "" && !strings.EqualFold(id, "none") {
+			ids = append(ids, id)
+		}
+	}
+	return message[:match[0]], ids
+}
+
+// captureCitations attaches the memories an agent cited to its latest event.
+// IDs that don't match a memory are kept and marked unknown, since a model
+// inventing citations is itself worth seeing.
+func (s *Simulation) captureCitations(agentName string, ids []string) {
+	if len(ids) == 0 || len(s.currentTurnEvents) == 0 || s.MemoryStore == nil {
+		return
+	}
+
+	event := &s.currentTurnEvents[len(s.currentTurnEvents)-1]
+	for _, id := range ids {
+		citation := chronicle.Citation{MemoryID: id}
+		if mem, ok := s.MemoryStore.Get(id); ok {
+			citation.Type = mem.Metadata["type"]
+			citation.Category = mem.Metadata["category"]
+			citation.Content = mem.Content
+		} else {
+			slog.Warn("agent cited unknown memory", "agent", agentName, "memory_id", id)
+			citation.Unknown = true
+		}
+		event.Citations = append(event.Citations, citation)
+	}
+}
@@ -0,0 +1,30 @@
+package simulations
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtractCitations(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		want    string
+		ids     []string
+	}{
+		{"no tag", "Let's get pizza.", "Let's get pizza.", nil},
+		{"ids", "Let's get pizza. [memories: abc, def]", "Let's get pizza.", []string{"abc", "def"}},
+		{"none", "Sure.\n[memories: none]", "Sure.", nil},
+		{"quoted and mixed case", `Fine. [Memories: "abc"]`, "Fine.", []string{"abc"}},
+		{"tag mid-message is left alone", "[memories: abc] then more", "[memories: abc] then more", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			message, ids := extractCitations(tt.message)
+			assert.Equal(t, tt.want, message)
+			assert.Equal(t, tt.ids, ids)
+		})
+	}
+}
@@ -20,6 +20,9 @@ type ChatRequest struct {
 	Messages []Message
 	Model    string
 	Tools    []map[string]interface{} // Tool definitions for the LLM
+
+	// Generation overrides the model's generation parameters for this request
+	Generation config.Generation
 }
 
 // ChatResponse represents the response from a chat completion.
@@ -27,6 +30,32 @@ type ChatResponse struct {
 	Message   string     // The active/spoken content
 	Thinking  string     // Internal reasoning (may be empty if model doesn't support it)
 	ToolCalls []ToolCall // Tools the LLM wants to invoke
+	Usage     Usage      // Token usage reported by the provider (zero if not reported)
+
+	// FinishReason is why the model stopped, as reported by the provider
+	// (e.g. "stop", "tool_calls", "content_filter", "refusal"); empty if not reported.
+	FinishReason string
+	// ProviderRefusal is refusal text the provider reports separately from the message.
+	ProviderRefusal string
+	// Refusals holds the refusals encountered while producing this response (set by Agent.Think).
+	Refusals []Refusal
+
+	// Invocations holds the tools executed while producing this response (set by Agent.Think).
+	Invocations []ToolInvocation
+
+	// Iterations is the number of model requests made while producing this
+	// response, one per round of tool calls (set by Agent.Think).
+	Iterations int
+
+	// Candidates holds every sampled response when produced by an EnsembleClient.
+	// The selected candidate's response is the one returned to the caller.
+	Candidates []EnsembleCandidate
+}
+
+// Usage is the token count of a single chat completion.
+type Usage struct {
+	InputTokens  int
+	OutputTokens int
 }
 
 // ToolCall represents a request from the LLM to invoke a tool.
@@ -36,6 +65,12 @@ type ToolCall struct {
 	Arguments map[string]interface{} // Tool arguments
 }
 
+// ToolInvocation is a tool call that was executed, with its error if it failed.
+type ToolInvocation struct {
+	Call  ToolCall
+	Error string
+}
+
 // Client is the interface for LLM clients.
 // Implementations must be stateless - the caller manages conversation history.
 type Client interface {
@@ -43,6 +78,27 @@ type Client interface {
 	Chat(ctx context.Context, req ChatRequest) (ChatResponse, error)
 }
 
+// StreamingClient is implemented by clients that can deliver a response incrementally.
+type StreamingClient interface {
+	Client
+
+	// ChatStream behaves like Chat but calls onDelta with each piece of message
+	// and thinking content as it arrives. The returned response is the complete message.
+	ChatStream(ctx context.Context, req ChatRequest, onDelta func(delta StreamDelta)) (ChatResponse, error)
+}
+
+// StreamDelta is a piece of a response as it streams in. Usually only one field is set.
+type StreamDelta struct {
+	Message  string // Spoken content
+	Thinking string // Reasoning, for models that stream it apart from the message
+}
+
+// ModelChecker is implemented by clients that can confirm, without generating
+// anything, that the provider accepts the credentials and serves the model.
+type ModelChecker interface {
+	CheckModel(ctx context.Context) error
+}
+
 // ResponseParser extracts thinking/reasoning from model responses.
 type ResponseParser interface {
 	// Parse extracts the message and thinking from a raw response.
@@ -71,15 +127,14 @@ func NewClient(provider *config.Provider, model *config.Model) (Client, error) {
 		return nil, fmt.Errorf("failed to create response parser: %w", err)
 	}
 
-	// Detect client type based on provider name or URL
-	// Check provider name first for explicit configuration
-	if strings.ToLower(provider.Name) == "anthropic" {
-		return newAnthropicClient(provider, model, parser)
+	if err := checkSampling(provider, model); err != nil {
+		return nil, err
+	}
+	if err := checkGeneration(provider, model.Name, model.Generation); err != nil {
+		return nil, err
 	}
 
-	// Check URL for anthropic.com
-	baseURL := strings.ToLower(provider.BaseURL)
-	if strings.Contains(baseURL, "anthropic.com") {
+	if isAnthropicProvider(provider) {
 		return newAnthropicClient(provider, model, parser)
 	}
 
@@ -87,6 +142,45 @@ func NewClient(provider *config.Provider, model *config.Model) (Client, error) {
 	return newOpenAIClient(provider, model, parser)
 }
 
+// isAnthropicProvider reports whether the provider speaks Anthropic's
+// Messages API rather than the OpenAI-compatible one.
+func isAnthropicProvider(provider *config.Provider) bool {
+	// An explicit provider type wins over detection
+	switch provider.Type {
+	case config.ProviderTypeAnthropic:
+		return true
+	case config.ProviderTypeOpenAI, config.ProviderTypeVLLM, config.ProviderTypeTGI:
+		return false
+	}
+
+	// Detect client type based on provider name or URL
+	// Check provider name first for explicit configuration
+	if strings.ToLower(provider.Name) == "anthropic" {
+		return true
+	}
+
+	// Check URL for anthropic.com
+	return strings.Contains(strings.ToLower(provider.BaseURL), "anthropic.com")
+}
+
+// checkSampling rejects sampling options the model's provider can't honor.
+func checkSampling(provider *config.Provider, model *config.Model) error {
+	sampling := model.Sampling
+	if sampling == nil {
+		return nil
+	}
+	if err := sampling.Validate(); err != nil {
+		return fmt.Errorf("model '%s': invalid sampling config: %w", model.Name, err)
+	}
+	if !provider.SelfHosted() {
+		return fmt.Errorf("model '%s': sampling options require a vllm or tgi provider, not '%s'", model.Name, provider.Name)
+	}
+	if provider.Type == config.ProviderTypeTGI && (sampling.TopK > 0 || sampling.BestOf > 0) {
+		return fmt.Errorf("model '%s': top_k and best_of aren't supported by TGI's chat API", model.Name)
+	}
+	return nil
+}
+
 // newResponseParser creates a ResponseParser based on the thinking parser configuration.
 func newResponseParser(cfg *config.ThinkingParserConfig) (ResponseParser, error) {
 	if cfg == nil {
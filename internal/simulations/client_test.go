@@ -3,6 +3,7 @@ package simulations
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -207,6 +208,401 @@ func TestOpenAIClient_Chat(t *testing.T) {
 	})
 }
 
+func TestOpenAIClient_Retry(t *testing.T) {
+	newClient := func(t *testing.T, serverURL string, maxRetries int) Client {
+		provider := &config.Provider{
+			Name:       "openai",
+			BaseURL:    serverURL,
+			MaxRetries: &maxRetries,
+			Backoff:    "1ms",
+		}
+		model := &config.Model{
+			Name:     "gpt-4",
+			Provider: "openai",
+			ThinkingParser: &config.ThinkingParserConfig{
+				Type: config.ThinkingParserNone,
+			},
+		}
+		client, err := NewClient(provider, model)
+		require.NoError(t, err)
+		return client
+	}
+	request := ChatRequest{Messages: []Message{{Role: "user", Content: "Hello"}}}
+
+	t.Run("retries transient failures with the same request", func(t *testing.T) {
+		attempts := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			attempts++
+			var reqBody map[string]interface{}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&reqBody))
+			assert.Equal(t, "gpt-4", reqBody["model"])
+
+			switch attempts {
+			case 1:
+				w.WriteHeader(http.StatusTooManyRequests)
+			case 2:
+				w.WriteHeader(http.StatusBadGateway)
+			default:
+				json.NewEncoder(w).Encode(map[string]interface{}{
+					"choices": []map[string]interface{}{
+						{"message": map[string]interface{}{"role": "assistant", "content": "Hi"}, "finish_reason": "stop"},
+					},
+				})
+			}
+		}))
+		defer server.Close()
+
+		resp, err := newClient(t, server.URL, 3).Chat(context.Background(), request)
+		require.NoError(t, err)
+		assert.Equal(t, "Hi", resp.Message)
+		assert.Equal(t, 3, attempts)
+	})
+
+	t.Run("gives up after max retries", func(t *testing.T) {
+		attempts := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			attempts++
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer server.Close()
+
+		_, err := newClient(t, server.URL, 2).Chat(context.Background(), request)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "503")
+		assert.Equal(t, 3, attempts)
+	})
+
+	t.Run("doesn't retry other statuses", func(t *testing.T) {
+		attempts := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			attempts++
+			w.WriteHeader(http.StatusUnauthorized)
+		}))
+		defer server.Close()
+
+		_, err := newClient(t, server.URL, 3).Chat(context.Background(), request)
+		require.Error(t, err)
+		assert.Equal(t, 1, attempts)
+	})
+}
+
+func TestOpenAIClient_ChatStream(t *testing.T) {
+	t.Run("delivers deltas and assembles the response", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var reqBody map[string]interface{}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&reqBody))
+			assert.Equal(t, true, reqBody["stream"])
+
+			w.Header().Set("Content-Type", "text/event-stream")
+			fmt.Fprint(w, `data: {"choices":[{"delta":{"content":"Hello, "}}]}`+"\n\n")
+			fmt.Fprint(w, `data: {"choices":[{"delta":{"content":"Bob."}}]}`+"\n\n")
+			fmt.Fprint(w, `data: {"choices":[{"delta":{"tool_calls":[{"index":0,"id":"call_1","function":{"name":"speak","arguments":"{\"mess"}}]}}]}`+"\n\n")
+			fmt.Fprint(w, `data: {"choices":[{"delta":{"tool_calls":[{"index":0,"function":{"arguments":"age\":\"hi\"}"}}]}}]}`+"\n\n")
+			fmt.Fprint(w, `data: {"choices":[],"usage":{"prompt_tokens":12,"completion_tokens":5}}`+"\n\n")
+			fmt.Fprint(w, "data: [DONE]\n\n")
+		}))
+		defer server.Close()
+
+		provider := &config.Provider{Name: "openai", BaseURL: server.URL}
+		model := &config.Model{
+			Name:           "gpt-4",
+			Provider:       "openai",
+			ThinkingParser: &config.ThinkingParserConfig{Type: config.ThinkingParserNone},
+		}
+		client, err := NewClient(provider, model)
+		require.NoError(t, err)
+
+		var deltas []string
+		resp, err := client.(StreamingClient).ChatStream(context.Background(), ChatRequest{
+			Messages: []Message{{Role: "user", Content: "Hello"}},
+		}, func(delta StreamDelta) {
+			deltas = append(deltas, delta.Message)
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Hello, ", "Bob."}, deltas)
+		assert.Equal(t, "Hello, Bob.", resp.Message)
+		require.Len(t, resp.ToolCalls, 1)
+		assert.Equal(t, "call_1", resp.ToolCalls[0].ID)
+		assert.Equal(t, "speak", resp.ToolCalls[0].Name)
+		assert.Equal(t, map[string]interface{}{"message": "hi"}, resp.ToolCalls[0].Arguments)
+		assert.Equal(t, Usage{InputTokens: 12, OutputTokens: 5}, resp.Usage)
+	})
+
+	t.Run("streams out-of-band reasoning as thinking", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/event-stream")
+			fmt.Fprint(w, `data: {"choices":[{"delta":{"reasoning_content":"Bob looks tired."}}]}`+"\n\n")
+			fmt.Fprint(w, `data: {"choices":[{"delta":{"content":"Sit down, Bob."}}]}`+"\n\n")
+			fmt.Fprint(w, "data: [DONE]\n\n")
+		}))
+		defer server.Close()
+
+		provider := &config.Provider{Name: "vllm", BaseURL: server.URL}
+		model := &config.Model{
+			Name:     "qwen",
+			Provider: "vllm",
+			ThinkingParser: &config.ThinkingParserConfig{
+				Type:      config.ThinkingParserOutOfBand,
+				FieldPath: "choices[0].message.reasoning_content",
+			},
+		}
+		client, err := NewClient(provider, model)
+		require.NoError(t, err)
+
+		var deltas []StreamDelta
+		resp, err := client.(StreamingClient).ChatStream(context.Background(), ChatRequest{
+			Messages: []Message{{Role: "user", Content: "Hello"}},
+		}, func(delta StreamDelta) {
+			deltas = append(deltas, delta)
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, []StreamDelta{{Thinking: "Bob looks tired."}, {Message: "Sit down, Bob."}}, deltas)
+		assert.Equal(t, "Bob looks tired.", resp.Thinking)
+		assert.Equal(t, "Sit down, Bob.", resp.Message)
+	})
+}
+
+func TestAnthropicClient_ChatStream(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/messages", r.URL.Path)
+		assert.Equal(t, "test-key", r.Header.Get("x-api-key"))
+		var reqBody map[string]interface{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&reqBody))
+		assert.Equal(t, true, reqBody["stream"])
+		assert.Equal(t, "Be Alice.", reqBody["system"])
+		assert.Equal(t, float64(1024), reqBody["max_tokens"])
+		assert.Equal(t, []interface{}{"END"}, reqBody["stop_sequences"])
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		events := []string{
+			`{"type":"message_start","message":{"usage":{"input_tokens":20}}}`,
+			`{"type":"content_block_start","index":0,"content_block":{"type":"thinking"}}`,
+			`{"type":"content_block_delta","index":0,"delta":{"type":"thinking_delta","thinking":"He's stalling."}}`,
+			`{"type":"content_block_start","index":1,"content_block":{"type":"text"}}`,
+			`{"type":"content_block_delta","index":1,"delta":{"type":"text_delta","text":"Out with it, "}}`,
+			`{"type":"content_block_delta","index":1,"delta":{"type":"text_delta","text":"Bob."}}`,
+			`{"type":"content_block_start","index":2,"content_block":{"type":"tool_use","id":"toolu_1","name":"speak"}}`,
+			`{"type":"content_block_delta","index":2,"delta":{"type":"input_json_delta","partial_json":"{\"message\":"}}`,
+			`{"type":"content_block_delta","index":2,"delta":{"type":"input_json_delta","partial_json":"\"hi\"}"}}`,
+			`{"type":"message_delta","delta":{"stop_reason":"tool_use"},"usage":{"output_tokens":9}}`,
+			`{"type":"message_stop"}`,
+		}
+		for _, event := range events {
+			fmt.Fprintf(w, "event: message\ndata: %s\n\n", event)
+		}
+	}))
+	defer server.Close()
+
+	apiKey := "test-key"
+	provider := &config.Provider{Name: "anthropic", Type: config.ProviderTypeAnthropic, BaseURL: server.URL, APIKey: &apiKey}
+	model := &config.Model{Name: "claude", Provider: "anthropic", Generation: config.Generation{MaxTokens: 1024, Stop: []string{"END"}}}
+	client, err := NewClient(provider, model)
+	require.NoError(t, err)
+
+	var deltas []StreamDelta
+	resp, err := client.(StreamingClient).ChatStream(context.Background(), ChatRequest{
+		Messages: []Message{{Role: "system", Content: "Be Alice."}, {Role: "user", Content: "Hello"}},
+	}, func(delta StreamDelta) {
+		deltas = append(deltas, delta)
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, []StreamDelta{{Thinking: "He's stalling."}, {Message: "Out with it, "}, {Message: "Bob."}}, deltas)
+	assert.Equal(t, "Out with it, Bob.", resp.Message)
+	assert.Equal(t, "He's stalling.", resp.Thinking)
+	require.Len(t, resp.ToolCalls, 1)
+	assert.Equal(t, ToolCall{ID: "toolu_1", Name: "speak", Arguments: map[string]interface{}{"message": "hi"}}, resp.ToolCalls[0])
+	assert.Equal(t, Usage{InputTokens: 20, OutputTokens: 9}, resp.Usage)
+	assert.Equal(t, "tool_use", resp.FinishReason)
+}
+
+func TestOpenAIClient_Sampling(t *testing.T) {
+	tools := []map[string]interface{}{
+		{
+			"type": "function",
+			"function": map[string]interface{}{
+				"name":        "speak",
+				"description": "Say something",
+				"parameters": map[string]interface{}{
+					"type":       "object",
+					"properties": map[string]interface{}{"message": map[string]interface{}{"type": "string"}},
+				},
+			},
+		},
+	}
+
+	t.Run("maps vLLM extensions and recovers guided tool calls", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var reqBody map[string]interface{}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&reqBody))
+			assert.Equal(t, float64(40), reqBody["top_k"])
+			assert.Equal(t, float64(3), reqBody["best_of"])
+			assert.NotContains(t, reqBody, "tools")
+			schema, ok := reqBody["guided_json"].(map[string]interface{})
+			require.True(t, ok)
+			assert.Len(t, schema["anyOf"], 2)
+			messages := reqBody["messages"].([]interface{})
+			assert.Contains(t, messages[len(messages)-1].(map[string]interface{})["content"], "- speak: Say something")
+
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"choices": []interface{}{
+					map[string]interface{}{
+						"message":       map[string]interface{}{"role": "assistant", "content": `{"tool": "speak", "arguments": {"message": "hi"}}`},
+						"finish_reason": "stop",
+					},
+				},
+			})
+		}))
+		defer server.Close()
+
+		provider := &config.Provider{Name: "vllm", Type: config.ProviderTypeVLLM, BaseURL: server.URL}
+		model := &config.Model{
+			Name:           "llama",
+			Provider:       "vllm",
+			ThinkingParser: &config.ThinkingParserConfig{Type: config.ThinkingParserNone},
+			Sampling:       &config.SamplingConfig{TopK: 40, BestOf: 3, GuidedTools: true},
+		}
+		client, err := NewClient(provider, model)
+		require.NoError(t, err)
+
+		resp, err := client.Chat(context.Background(), ChatRequest{
+			Messages: []Message{{Role: "user", Content: "Hello"}},
+			Tools:    tools,
+		})
+		require.NoError(t, err)
+		assert.Empty(t, resp.Message)
+		require.Len(t, resp.ToolCalls, 1)
+		assert.Equal(t, "speak", resp.ToolCalls[0].Name)
+		assert.Equal(t, map[string]interface{}{"message": "hi"}, resp.ToolCalls[0].Arguments)
+	})
+
+	t.Run("sends TGI grammars as a response format", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var reqBody map[string]interface{}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&reqBody))
+			assert.Equal(t, map[string]interface{}{"type": "regex", "value": "(yes|no)"}, reqBody["response_format"])
+
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{"choices":[{"message":{"role":"assistant","content":"yes"},"finish_reason":"stop"}]}`)
+		}))
+		defer server.Close()
+
+		provider := &config.Provider{Name: "tgi", Type: config.ProviderTypeTGI, BaseURL: server.URL}
+		model := &config.Model{
+			Name:           "mistral",
+			Provider:       "tgi",
+			ThinkingParser: &config.ThinkingParserConfig{Type: config.ThinkingParserNone},
+			Sampling:       &config.SamplingConfig{GuidedGrammar: "(yes|no)"},
+		}
+		client, err := NewClient(provider, model)
+		require.NoError(t, err)
+
+		resp, err := client.Chat(context.Background(), ChatRequest{
+			Messages: []Message{{Role: "user", Content: "Agree?"}},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "yes", resp.Message)
+	})
+
+	t.Run("sends the model's temperature, even zero", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var reqBody map[string]interface{}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&reqBody))
+			assert.Equal(t, float64(0), reqBody["temperature"])
+
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{"choices":[{"message":{"role":"assistant","content":"yes"},"finish_reason":"stop"}]}`)
+		}))
+		defer server.Close()
+
+		temperature := 0.0
+		provider := &config.Provider{Name: "openai", BaseURL: server.URL}
+		model := &config.Model{
+			Name:           "gpt-4",
+			Provider:       "openai",
+			ThinkingParser: &config.ThinkingParserConfig{Type: config.ThinkingParserNone},
+			Generation:     config.Generation{Temperature: &temperature},
+		}
+		client, err := NewClient(provider, model)
+		require.NoError(t, err)
+
+		resp, err := client.Chat(context.Background(), ChatRequest{
+			Messages: []Message{{Role: "user", Content: "Agree?"}},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "yes", resp.Message)
+	})
+
+	t.Run("sends generation parameters with the request's over the model's", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var reqBody map[string]interface{}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&reqBody))
+			assert.Equal(t, 0.9, reqBody["top_p"])
+			assert.Equal(t, float64(256), reqBody["max_tokens"])
+			assert.Equal(t, 0.5, reqBody["frequency_penalty"])
+			assert.Equal(t, []interface{}{"\n\n"}, reqBody["stop"])
+			assert.Equal(t, 1.2, reqBody["temperature"])
+
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{"choices":[{"message":{"role":"assistant","content":"yes"},"finish_reason":"stop"}]}`)
+		}))
+		defer server.Close()
+
+		temperature, override, topP, penalty := 0.2, 1.2, 0.9, 0.5
+		provider := &config.Provider{Name: "openai", BaseURL: server.URL}
+		model := &config.Model{
+			Name:           "gpt-4",
+			Provider:       "openai",
+			ThinkingParser: &config.ThinkingParserConfig{Type: config.ThinkingParserNone},
+			Generation:     config.Generation{Temperature: &temperature, TopP: &topP, MaxTokens: 256, Stop: []string{"\n\n"}},
+		}
+		client, err := NewClient(provider, model)
+		require.NoError(t, err)
+
+		resp, err := client.Chat(context.Background(), ChatRequest{
+			Messages:   []Message{{Role: "user", Content: "Agree?"}},
+			Generation: config.Generation{Temperature: &override, FrequencyPenalty: &penalty},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "yes", resp.Message)
+	})
+
+	t.Run("rejects generation parameters Anthropic doesn't accept", func(t *testing.T) {
+		temperature, penalty := 1.5, 0.5
+		anthropicProvider := &config.Provider{Name: "anthropic", BaseURL: "https://api.anthropic.com"}
+		assert.ErrorContains(t, checkGeneration(anthropicProvider, "claude", config.Generation{Temperature: &temperature}), "up to 1")
+		assert.ErrorContains(t, checkGeneration(anthropicProvider, "claude", config.Generation{FrequencyPenalty: &penalty}), "frequency_penalty")
+		assert.NoError(t, checkGeneration(&config.Provider{Name: "openai"}, "gpt-4", config.Generation{Temperature: &temperature, FrequencyPenalty: &penalty}))
+	})
+
+	t.Run("rejects sampling options the provider can't honor", func(t *testing.T) {
+		model := &config.Model{
+			Name:     "gpt-4",
+			Provider: "openai",
+			Sampling: &config.SamplingConfig{TopK: 40},
+		}
+		_, err := NewClient(&config.Provider{Name: "openai", BaseURL: "https://api.openai.com/v1"}, model)
+		assert.ErrorContains(t, err, "require a vllm or tgi provider")
+
+		model.Provider = "tgi"
+		_, err = NewClient(&config.Provider{Name: "tgi", Type: config.ProviderTypeTGI, BaseURL: "http://localhost:8080"}, model)
+		assert.ErrorContains(t, err, "aren't supported by TGI")
+	})
+
+	t.Run("leaves plain output alone", func(t *testing.T) {
+		content, calls := parseGuidedOutput("Just talking.")
+		assert.Equal(t, "Just talking.", content)
+		assert.Empty(t, calls)
+
+		content, calls = parseGuidedOutput(`{"content": "Hello there."}`)
+		assert.Equal(t, "Hello there.", content)
+		assert.Empty(t, calls)
+	})
+}
+
 func TestAnthropicClient_Chat(t *testing.T) {
 	t.Run("sends basic chat request", func(t *testing.T) {
 		// Create mock server
@@ -396,3 +792,57 @@ func TestAnthropicClient_Chat(t *testing.T) {
 		assert.Equal(t, "You are a helpful assistant.", receivedSystem)
 	})
 }
+
+func TestOpenAIClient_CheckModel(t *testing.T) {
+	newServer := func(status int) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/models", r.URL.Path)
+			if status != http.StatusOK {
+				w.WriteHeader(status)
+				return
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"data": []map[string]interface{}{
+					{"id": "llama3.2"},
+					{"id": "qwq-32b"},
+				},
+			})
+		}))
+	}
+
+	t.Run("passes when the provider lists the model", func(t *testing.T) {
+		server := newServer(http.StatusOK)
+		defer server.Close()
+
+		client, err := NewClient(&config.Provider{Name: "ollama", BaseURL: server.URL}, &config.Model{Name: "qwq-32b", Provider: "ollama"})
+		require.NoError(t, err)
+
+		checker, ok := client.(ModelChecker)
+		require.True(t, ok)
+		assert.NoError(t, checker.CheckModel(context.Background()))
+	})
+
+	t.Run("fails when the model is missing", func(t *testing.T) {
+		server := newServer(http.StatusOK)
+		defer server.Close()
+
+		client, err := NewClient(&config.Provider{Name: "ollama", BaseURL: server.URL}, &config.Model{Name: "qwen-typo", Provider: "ollama"})
+		require.NoError(t, err)
+
+		err = client.(ModelChecker).CheckModel(context.Background())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "qwen-typo")
+	})
+
+	t.Run("reports rejected credentials", func(t *testing.T) {
+		server := newServer(http.StatusUnauthorized)
+		defer server.Close()
+
+		client, err := NewClient(&config.Provider{Name: "openai", BaseURL: server.URL}, &config.Model{Name: "gpt-4o", Provider: "openai"})
+		require.NoError(t, err)
+
+		err = client.(ModelChecker).CheckModel(context.Background())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "API key")
+	})
+}
@@ -0,0 +1,78 @@
+package simulations
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+
+	"github.com/poiesic/wonda/internal/chronicle"
+	mcpsim "github.com/poiesic/wonda/internal/mcp/simulation"
+	"github.com/poiesic/wonda/internal/memory"
+)
+
+// recordCommitments adds goals completed this turn to the world's commitments ledger
+// and gives every agent a memory of the agreement. Judged and individual goals
+// aren't agreements and are left out.
+func (s *Simulation) recordCommitments(ctx context.Context, completions []chronicle.GoalCompletion, turn int) {
+	for _, completion := range completions {
+		if completion.CompletedAt != turn || completion.Status != string(mcpsim.GoalCompleted) || completion.JudgedBy != "" || completion.CompletedBy != "" {
+			continue
+		}
+
+		s.World.AddCommitment(mcpsim.Commitment{
+			Goal:        completion.GoalName,
+			Description: completion.Solution,
+			ProposedBy:  completion.ProposedBy,
+			Agreed:      completion.VotedYes,
+			Objected:    completion.VotedNo,
+			AgreedAt:    turn,
+		})
+		slog.Info("commitment recorded", "goal", completion.GoalName, "commitment", completion.Solution)
+
+		for _, agentName := range s.TurnOrder {
+			var content string
+			if slices.Contains(completion.VotedNo, agentName) {
+				content = fmt.Sprintf("Over my objection, the group committed to: %s (goal %s, proposed by %s, turn %d)",
+					completion.Solution, completion.GoalName, completion.ProposedBy, turn)
+			} else {
+				content = fmt.Sprintf("We agreed to: %s (goal %s, proposed by %s, turn %d)",
+					completion.Solution, completion.GoalName, completion.ProposedBy, turn)
+			}
+			s.captureCommitmentMemory(ctx, agentName, completion.GoalName, content, turn)
+		}
+	}
+}
+
+// expireCommitments expires the commitments nobody reported fulfilled by the end of the run.
+func (s *Simulation) expireCommitments() {
+	for _, commitment := range s.World.ExpireCommitments(s.World.Turn()) {
+		slog.Info("commitment expired", "goal", commitment.Goal, "commitment", commitment.Description)
+	}
+}
+
+// captureCommitmentMemory stores an agent's memory of a commitment.
+func (s *Simulation) captureCommitmentMemory(ctx context.Context, agentName, goalName, content string, turn int) {
+	if s.MemoryStore == nil {
+		return
+	}
+
+	embedding, err := s.MemoryStore.Embed(ctx, content)
+	if err != nil {
+		// Log error but don't fail the simulation
+		slog.Warn("failed to embed commitment memory", "error", err)
+		return
+	}
+
+	s.MemoryStore.Add(memory.Memory{
+		Content:   content,
+		Embedding: embedding,
+		Metadata: map[string]string{
+			"agent":    agentName,
+			"type":     "commitment",
+			"category": goalName,
+			"turn":     fmt.Sprintf("%d", turn),
+			"run":      s.ID.String(),
+		},
+	})
+}
@@ -0,0 +1,90 @@
+package simulations
+
+import (
+	"context"
+	"testing"
+
+	"github.com/poiesic/wonda/internal/chronicle"
+	mcpsim "github.com/poiesic/wonda/internal/mcp/simulation"
+	"github.com/poiesic/wonda/internal/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// lengthEmbedder embeds text as its length, and is safe for concurrent use.
+type lengthEmbedder struct{}
+
+func (lengthEmbedder) Embed(ctx context.Context, text string) ([]float32, error) {
+	return []float32{float32(len(text)), 1}, nil
+}
+
+func TestCommitments(t *testing.T) {
+	ctx := context.Background()
+	newCommitmentSimulation := func() *Simulation {
+		sim := &Simulation{
+			World:       mcpsim.NewWorldState("cafe", "quiet"),
+			TurnOrder:   []string{"Alex", "Jordan", "Sam"},
+			MemoryStore: memory.NewStore(lengthEmbedder{}),
+		}
+		sim.World.SetTurn(3)
+		return sim
+	}
+	completions := []chronicle.GoalCompletion{
+		{GoalName: "dinner", Status: "completed", Solution: "Dinner at Luigi's", ProposedBy: "Alex", VotedYes: []string{"Alex", "Jordan"}, VotedNo: []string{"Sam"}, CompletedAt: 3},
+		{GoalName: "movie", Status: "failed", CompletedAt: 3},
+		{GoalName: "lunch", Status: "completed", Solution: "Sandwiches", CompletedAt: 2},
+	}
+	commitmentMemories := func(sim *Simulation, agentName string) []string {
+		var contents []string
+		for _, mem := range sim.MemoryStore.Search(ctx, []float32{1, 1}, memory.Filter{Agent: agentName, Type: "commitment"}, 10) {
+			contents = append(contents, mem.Content)
+		}
+		return contents
+	}
+
+	t.Run("records goals completed this turn", func(t *testing.T) {
+		sim := newCommitmentSimulation()
+		sim.recordCommitments(ctx, completions, 3)
+
+		commitments := sim.World.Snapshot().Commitments
+		require.Len(t, commitments, 1, "only goals completed this turn become commitments")
+		assert.Equal(t, mcpsim.Commitment{
+			Goal:        "dinner",
+			Description: "Dinner at Luigi's",
+			ProposedBy:  "Alex",
+			Agreed:      []string{"Alex", "Jordan"},
+			Objected:    []string{"Sam"},
+			AgreedAt:    3,
+			Status:      mcpsim.CommitmentOpen,
+		}, commitments[0])
+
+		assert.Equal(t, []string{"We agreed to: Dinner at Luigi's (goal dinner, proposed by Alex, turn 3)"}, commitmentMemories(sim, "Jordan"))
+		assert.Equal(t, []string{"Over my objection, the group committed to: Dinner at Luigi's (goal dinner, proposed by Alex, turn 3)"}, commitmentMemories(sim, "Sam"))
+	})
+
+	t.Run("fulfilled commitments stay fulfilled when the run ends", func(t *testing.T) {
+		sim := newCommitmentSimulation()
+		sim.recordCommitments(ctx, completions, 3)
+		sim.World.SetTurn(4)
+		_, err := sim.World.FulfillCommitment("dinner", "Jordan")
+		require.NoError(t, err)
+
+		sim.expireCommitments()
+		commitment := sim.World.Snapshot().Commitments[0]
+		assert.Equal(t, mcpsim.CommitmentFulfilled, commitment.Status)
+		assert.Equal(t, "Jordan", commitment.FulfilledBy)
+		assert.Equal(t, 4, commitment.ResolvedAt)
+	})
+
+	t.Run("open commitments expire when the run ends", func(t *testing.T) {
+		sim := newCommitmentSimulation()
+		sim.recordCommitments(ctx, completions, 3)
+		sim.World.SetTurn(6)
+
+		sim.expireCommitments()
+		commitment := sim.World.Snapshot().Commitments[0]
+		assert.Equal(t, mcpsim.CommitmentExpired, commitment.Status)
+		assert.Empty(t, commitment.FulfilledBy)
+		assert.Equal(t, 6, commitment.ResolvedAt)
+	})
+}
@@ -0,0 +1,265 @@
+package simulations
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/poiesic/wonda/internal/config"
+	"github.com/poiesic/wonda/internal/memory"
+	"github.com/poiesic/wonda/internal/prompts"
+)
+
+// compactionSentences is the longest summary the compactor is asked for.
+const compactionSentences = 5
+
+// compactor is the model that summarizes old episodic memories.
+type compactor struct {
+	client Client
+	model  string
+}
+
+// CompactionRecord is an entry in a run's memory archive: the memories of a
+// speaker that were summarized, and the summary that replaced them.
+type CompactionRecord struct {
+	Turn      int              `json:"turn"` // Turn after which the memories were compacted
+	Speaker   string           `json:"speaker"`
+	FirstTurn int              `json:"first_turn"`
+	LastTurn  int              `json:"last_turn"`
+	Model     string           `json:"model"`
+	SummaryID string           `json:"summary_id"`
+	Summary   string           `json:"summary"`
+	Archived  []ArchivedMemory `json:"archived"`
+}
+
+// ArchivedMemory is a memory archived by compaction, as it was stored.
+type ArchivedMemory struct {
+	ID       string            `json:"id"`
+	Turn     int               `json:"turn"`
+	Content  string            `json:"content"`
+	Metadata map[string]string `json:"metadata"`
+}
+
+// initializeCompactor creates the model that compacts memories, if the
+// scenario compacts them.
+func (s *Simulation) initializeCompactor(models map[string]*config.Model, providers *config.Providers) error {
+	if s.Scenario.Memory == nil || s.Scenario.Memory.Compaction == nil {
+		return nil
+	}
+
+	modelName, model, provider, err := s.resolveCompactor(models, providers)
+	if err != nil {
+		return err
+	}
+	client, err := s.newClient(usageCallerCompaction, provider, model)
+	if err != nil {
+		return fmt.Errorf("failed to create memory compactor: %w", err)
+	}
+	s.compactor = &compactor{client: client, model: modelName}
+	slog.Info("memory compaction ready", "model", modelName, "every", *s.Scenario.Memory.Compaction.Every)
+	return nil
+}
+
+// resolveCompactor returns the name, model and provider of the model that
+// compacts memories: the compaction model, or else the scenario's default model.
+func (s *Simulation) resolveCompactor(models map[string]*config.Model, providers *config.Providers) (string, *config.Model, *config.Provider, error) {
+	modelName := s.Scenario.Memory.Compaction.Model
+	if modelName == "" && s.Scenario.Basics.Defaults != nil {
+		modelName = s.Scenario.Basics.Defaults.Model
+	}
+	if modelName == "" {
+		return "", nil, nil, fmt.Errorf("memory compaction needs a model (the scenario has no default model)")
+	}
+
+	model, ok := models[modelName]
+	if !ok {
+		return "", nil, nil, fmt.Errorf("memory compaction model %s not found", modelName)
+	}
+	provider, ok := providers.Providers[model.Provider]
+	if !ok {
+		return "", nil, nil, fmt.Errorf("provider %s (from model %s) not found for memory compaction", model.Provider, modelName)
+	}
+	return modelName, model, provider, nil
+}
+
+// memoryArchivePath is where compacted memories are recorded, next to the chronicle.
+func (s *Simulation) memoryArchivePath() string {
+	return strings.TrimSuffix(s.chroniclePath, ".jsonl") + ".memory-archive.jsonl"
+}
+
+// compactMemories summarizes each speaker's episodic memories from before the
+// turns kept as they are, every few turns as configured. Summaries are stored
+// as episodic memories and the originals archived, after being recorded in
+// the memory archive. Failures are logged and leave a speaker's memories as
+// they are.
+func (s *Simulation) compactMemories(ctx context.Context, turn int) {
+	if s.compactor == nil || s.MemoryStore == nil {
+		return
+	}
+	settings := s.Scenario.Memory.Compaction
+	lastTurn := turn - *settings.KeepTurns
+	if turn%*settings.Every != 0 || lastTurn < 1 {
+		return
+	}
+
+	// This run's dialogue, by speaker; earlier summaries are kept as they are
+	bySpeaker := make(map[string][]memory.Memory)
+	var speakers []string
+	for _, mem := range s.MemoryStore.Memories(memory.Filter{Type: "episodic", Category: "dialogue", MaxTurn: lastTurn}) {
+		if mem.Metadata["run"] != s.ID.String() {
+			continue
+		}
+		speaker := mem.Metadata["speaker"]
+		if _, ok := bySpeaker[speaker]; !ok {
+			speakers = append(speakers, speaker)
+		}
+		bySpeaker[speaker] = append(bySpeaker[speaker], mem)
+	}
+	slices.Sort(speakers)
+
+	for _, speaker := range speakers {
+		memories := bySpeaker[speaker]
+		if len(memories) < *settings.MinMemories {
+			continue
+		}
+		if err := s.compactSpeaker(ctx, turn, speaker, memories); err != nil {
+			slog.Warn("memory compaction failed", "speaker", speaker, "model", s.compactor.model, "error", err)
+		}
+	}
+}
+
+// compactSpeaker replaces a speaker's memories with a summary of them.
+func (s *Simulation) compactSpeaker(ctx context.Context, turn int, speaker string, memories []memory.Memory) error {
+	record := CompactionRecord{Turn: turn, Speaker: speaker, Model: s.compactor.model}
+	var tags []string
+	for _, mem := range memories {
+		memTurn, _ := strconv.Atoi(mem.Metadata["turn"])
+		if record.FirstTurn == 0 || memTurn < record.FirstTurn {
+			record.FirstTurn = memTurn
+		}
+		record.LastTurn = max(record.LastTurn, memTurn)
+		for _, tag := range mem.Tags() {
+			if !slices.Contains(tags, tag) {
+				tags = append(tags, tag)
+			}
+		}
+		record.Archived = append(record.Archived, ArchivedMemory{
+			ID:       mem.ID,
+			Turn:     memTurn,
+			Content:  mem.Content,
+			Metadata: mem.Metadata,
+		})
+	}
+
+	prompt, err := s.buildCompactionPrompt(record)
+	if err != nil {
+		return err
+	}
+	resp, err := s.compactor.client.Chat(ctx, ChatRequest{
+		Messages: []Message{{Role: "user", Content: prompt}},
+	})
+	if err != nil {
+		return err
+	}
+	record.Summary = strings.TrimSpace(resp.Message)
+	if record.Summary == "" {
+		return fmt.Errorf("the model wrote no summary")
+	}
+
+	content := fmt.Sprintf("%s, turns %d to %d: %s", speaker, record.FirstTurn, record.LastTurn, record.Summary)
+	embedding, err := s.MemoryStore.Embed(ctx, content)
+	if err != nil {
+		return fmt.Errorf("failed to embed summary: %w", err)
+	}
+	metadata := map[string]string{
+		"type":       "episodic",
+		"category":   "summary",
+		"turn":       strconv.Itoa(record.LastTurn),
+		"first_turn": strconv.Itoa(record.FirstTurn),
+		"speaker":    speaker,
+		"language":   s.Scenario.AgentLanguage(speaker),
+		"run":        s.ID.String(),
+	}
+	if len(tags) > 0 {
+		metadata[memory.TagsKey] = memory.JoinTags(tags)
+	}
+	summary := memory.Memory{Content: content, Embedding: embedding, Metadata: metadata}
+
+	// Record the originals before they stop being searched; without a record
+	// they stay, and the summary goes
+	record.SummaryID = s.MemoryStore.Add(summary)
+	if err := s.appendMemoryArchive(record); err != nil {
+		s.MemoryStore.Archive(record.SummaryID)
+		return err
+	}
+	ids := make([]string, len(record.Archived))
+	for i, archived := range record.Archived {
+		ids[i] = archived.ID
+	}
+	s.MemoryStore.Archive(ids...)
+	slog.Info("memories compacted", "speaker", speaker, "memories", len(ids), "first_turn", record.FirstTurn, "last_turn", record.LastTurn)
+	return nil
+}
+
+// appendMemoryArchive adds a record to the run's memory archive.
+func (s *Simulation) appendMemoryArchive(record CompactionRecord) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal memory archive record: %w", err)
+	}
+	file, err := os.OpenFile(s.memoryArchivePath(), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open memory archive: %w", err)
+	}
+	defer file.Close()
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write memory archive: %w", err)
+	}
+	return nil
+}
+
+// buildCompactionPrompt renders the memory compaction prompt template.
+func (s *Simulation) buildCompactionPrompt(record CompactionRecord) (string, error) {
+	promptTemplate, err := prompts.GetPrompt("memory_compaction")
+	if err != nil {
+		return "", fmt.Errorf("failed to load memory compaction prompt: %w", err)
+	}
+
+	tmpl, err := template.New("memory_compaction").Parse(promptTemplate)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse template: %w", err)
+	}
+
+	memories := make([]string, len(record.Archived))
+	for i, archived := range record.Archived {
+		memories[i] = fmt.Sprintf("Turn %d: %s", archived.Turn, archived.Content)
+	}
+	data := struct {
+		Scenario     string
+		Speaker      string
+		FirstTurn    int
+		LastTurn     int
+		Memories     []string
+		MaxSentences int
+	}{
+		Scenario:     s.Scenario.Basics.Name,
+		Speaker:      record.Speaker,
+		FirstTurn:    record.FirstTurn,
+		LastTurn:     record.LastTurn,
+		Memories:     memories,
+		MaxSentences: compactionSentences,
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to execute template: %w", err)
+	}
+	return buf.String(), nil
+}
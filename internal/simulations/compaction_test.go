@@ -0,0 +1,104 @@
+package simulations
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/oklog/ulid/v2"
+	mcpsim "github.com/poiesic/wonda/internal/mcp/simulation"
+	"github.com/poiesic/wonda/internal/memory"
+	"github.com/poiesic/wonda/internal/scenarios"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompactMemories(t *testing.T) {
+	ctx := context.Background()
+	newSim := func(reply string) *Simulation {
+		world := mcpsim.NewWorldState("Cafe", "Quiet")
+		world.AddAgent("Alice", "")
+		world.AddAgent("Bob", "")
+
+		every, keepTurns, minMemories := 4, 2, 2
+		settings := &scenarios.MemoryCompactionConfig{Every: &every, KeepTurns: &keepTurns, MinMemories: &minMemories}
+		sim := &Simulation{
+			ID: ulid.Make(),
+			Scenario: &scenarios.Scenario{
+				Basics: &scenarios.BasicScenarioInformation{Name: "Dinner", MaxTurns: 8},
+				Memory: &scenarios.MemoryConfig{Compaction: settings},
+			},
+			World:         world,
+			MemoryStore:   memory.NewStore(lengthEmbedder{}),
+			compactor:     &compactor{client: &fakeClient{response: ChatResponse{Message: reply}}, model: "summarizer"},
+			chroniclePath: filepath.Join(t.TempDir(), "chronicle-dinner.jsonl"),
+		}
+		// Alice speaks every turn, Bob only once
+		for turn := 1; turn <= 4; turn++ {
+			sim.captureEpisodicMemory(ctx, "Alice", "Let's get pizza, again "+strings.Repeat("!", turn), turn)
+		}
+		sim.captureEpisodicMemory(ctx, "Bob", "Sushi.", 1)
+		return sim
+	}
+	dialogue := func(sim *Simulation, speaker string) []memory.Memory {
+		var memories []memory.Memory
+		for _, mem := range sim.MemoryStore.Memories(memory.Filter{Type: "episodic"}) {
+			if mem.Metadata["speaker"] == speaker {
+				memories = append(memories, mem)
+			}
+		}
+		return memories
+	}
+
+	t.Run("summarizes old memories and archives them", func(t *testing.T) {
+		sim := newSim("  Alice kept pushing for pizza.\n")
+		sim.compactMemories(ctx, 4)
+
+		alice := dialogue(sim, "Alice")
+		require.Len(t, alice, 3, "turns 3 and 4 are kept")
+		summary := alice[2]
+		assert.Equal(t, "Alice, turns 1 to 2: Alice kept pushing for pizza.", summary.Content)
+		assert.Equal(t, "summary", summary.Metadata["category"])
+		assert.Equal(t, "2", summary.Metadata["turn"])
+		assert.Equal(t, "1", summary.Metadata["first_turn"])
+		assert.Len(t, dialogue(sim, "Bob"), 1, "too few memories to compact")
+
+		data, err := os.ReadFile(sim.memoryArchivePath())
+		require.NoError(t, err)
+		var record CompactionRecord
+		require.NoError(t, json.Unmarshal(data, &record))
+		assert.Equal(t, 4, record.Turn)
+		assert.Equal(t, "Alice", record.Speaker)
+		assert.Equal(t, summary.ID, record.SummaryID)
+		require.Len(t, record.Archived, 2)
+		assert.Equal(t, 1, record.Archived[0].Turn)
+		assert.True(t, sim.MemoryStore.Archived(record.Archived[0].ID))
+		assert.True(t, strings.HasSuffix(sim.memoryArchivePath(), "chronicle-dinner.memory-archive.jsonl"))
+
+		// Archived memories are no longer found, but are kept in snapshots
+		results := sim.MemoryStore.Search(ctx, []float32{1, 1}, memory.Filter{Type: "episodic"}, 10)
+		for _, mem := range results {
+			assert.False(t, sim.MemoryStore.Archived(mem.ID))
+		}
+		snapshot := sim.MemoryStore.Snapshot()
+		assert.Len(t, snapshot.Memories, 6)
+		assert.Len(t, snapshot.Archived, 2)
+	})
+
+	t.Run("waits for its turn", func(t *testing.T) {
+		sim := newSim("Alice kept pushing for pizza.")
+		sim.compactMemories(ctx, 3)
+		assert.Len(t, dialogue(sim, "Alice"), 4)
+		assert.NoFileExists(t, sim.memoryArchivePath())
+	})
+
+	t.Run("failures leave memories alone", func(t *testing.T) {
+		sim := newSim("   ")
+		sim.compactMemories(ctx, 4)
+		assert.Len(t, dialogue(sim, "Alice"), 4)
+		assert.NoFileExists(t, sim.memoryArchivePath())
+	})
+}
@@ -0,0 +1,42 @@
+package simulations
+
+import "fmt"
+
+// compromiseSituations nudge an agent toward compromise, from gentlest to firmest.
+var compromiseSituations = []string{
+	"\n\nTime is moving on (turn %d of %d). Stay true to your character, but stay open to the others' ideas where you could live with them.",
+	"\n\nTime is running short (turn %d of %d) and a deadlock helps no one. Look for middle ground, and consider backing a proposal that meets your core concerns even if it isn't your first choice.",
+	"\n\nTime is nearly up (turn %d of %d). Unless a proposal is truly unacceptable to your character, be willing to accept a compromise rather than leave with nothing decided.",
+}
+
+// compromisePressure returns how hard an agent is pushed to compromise on a
+// turn, from 0 (not at all) to 1. It rises from the scenario's start point to
+// the last turn, scaled down by the agent's stubbornness.
+func compromisePressure(turn, maxTurns int, start, stubbornness float64) float64 {
+	if maxTurns <= 0 {
+		return 0
+	}
+	progress := float64(turn) / float64(maxTurns)
+	if progress <= start {
+		return 0
+	}
+	return min((progress-start)/(1-start), 1) * (1 - stubbornness)
+}
+
+// compromiseNote returns the situation note nudging an agent toward compromise,
+// or "" when the scenario has no compromise schedule or the agent isn't pushed yet.
+// Observers don't decide goals, so they are never nudged.
+func (s *Simulation) compromiseNote(agentName string, turn int) string {
+	rules := s.Scenario.Compromise
+	if rules == nil || s.isObserver(agentName) {
+		return ""
+	}
+
+	maxTurns := s.MaxTurns()
+	pressure := compromisePressure(turn, maxTurns, *rules.Start, rules.StubbornnessOf(agentName))
+	if pressure <= 0 {
+		return ""
+	}
+	level := min(int(pressure*float64(len(compromiseSituations))), len(compromiseSituations)-1)
+	return fmt.Sprintf(compromiseSituations[level], turn, maxTurns)
+}
@@ -0,0 +1,30 @@
+package simulations
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompromisePressure(t *testing.T) {
+	tests := []struct {
+		name         string
+		turn         int
+		start        float64
+		stubbornness float64
+		want         float64
+	}{
+		{"before start", 3, 0.3, 0, 0},
+		{"at start", 3, 0.3, 0.5, 0},
+		{"halfway from start", 6, 0.2, 0, 0.5},
+		{"last turn", 10, 0.3, 0, 1},
+		{"scaled by stubbornness", 10, 0.3, 0.75, 0.25},
+		{"never softens", 10, 0.3, 1, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.InDelta(t, tt.want, compromisePressure(tt.turn, 10, tt.start, tt.stubbornness), 1e-9)
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package simulations
+
+import (
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+
+	"github.com/poiesic/wonda/internal/chronicle"
+)
+
+// tiredSituation tells a tired agent to keep it short.
+const tiredSituation = "\n\nYou are worn out (condition %d/100). You only have the energy for %d short sentences at most."
+
+// exhaustedReason is recorded when an agent is too exhausted to take part.
+const exhaustedReason = "too exhausted to take part"
+
+// sentenceEnd matches the end of a sentence and the space after it.
+var sentenceEnd = regexp.MustCompile(`[.!?…]+["')\]]*\s+`)
+
+// conditionState reports whether an agent is tired or exhausted.
+// Both are false when the scenario has no condition mechanics.
+func (s *Simulation) conditionState(agentName string) (tired, exhausted bool) {
+	rules := s.Scenario.Condition
+	if rules == nil {
+		return false, false
+	}
+	condition := s.World.Condition(agentName)
+	return condition < rules.TiredBelow, condition < rules.ExhaustedBelow
+}
+
+// tiredNote returns the situation note for a tired agent, or "" when they aren't tired.
+func (s *Simulation) tiredNote(agentName string) string {
+	if tired, _ := s.conditionState(agentName); !tired {
+		return ""
+	}
+	return fmt.Sprintf(tiredSituation, s.World.Condition(agentName), s.Scenario.Condition.TiredSentences)
+}
+
+// limitUtterance cuts a tired agent's response down to their sentence budget.
+func (s *Simulation) limitUtterance(agentName, message string) string {
+	if tired, _ := s.conditionState(agentName); !tired {
+		return message
+	}
+	return limitSentences(message, s.Scenario.Condition.TiredSentences)
+}
+
+// limitSentences returns at most the first n sentences of text.
+func limitSentences(text string, n int) string {
+	ends := sentenceEnd.FindAllStringIndex(text, -1)
+	if len(ends) < n {
+		return text
+	}
+	return strings.TrimSpace(text[:ends[n-1][1]])
+}
+
+// drainCondition takes the scenario's per-turn toll on every agent's condition.
+func (s *Simulation) drainCondition() {
+	if s.Scenario.Condition == nil || s.Scenario.Condition.DrainPerTurn == 0 {
+		return
+	}
+	for _, agentName := range s.TurnOrder {
+		s.World.AdjustCondition(agentName, -s.Scenario.Condition.DrainPerTurn, "end of turn")
+	}
+	s.captureConditionChanges()
+}
+
+// captureConditionChanges records condition changes in the world for the
+// chronicle and updates the agents' state to match.
+func (s *Simulation) captureConditionChanges() {
+	for _, change := range s.World.TakeConditionChanges() {
+		if agent, ok := s.Agents[change.AgentName]; ok {
+			agent.State.Condition = change.After
+		}
+		slog.Info("condition changed", "agent", change.AgentName, "before", change.Before, "after", change.After, "cause", change.Cause)
+		s.currentCondition = append(s.currentCondition, chronicle.ConditionChange{
+			AgentName: change.AgentName,
+			Before:    change.Before,
+			After:     change.After,
+			Cause:     change.Cause,
+		})
+	}
+}
@@ -0,0 +1,28 @@
+package simulations
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLimitSentences(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		n    int
+		want string
+	}{
+		{"within budget", "I'm tired. Let's go.", 2, "I'm tired. Let's go."},
+		{"cut to budget", "I'm tired. Let's go. Now! Please?", 2, "I'm tired. Let's go."},
+		{"one sentence", "Fine... whatever you say. I'm done.", 1, "Fine..."},
+		{"quoted ending", `She said "no." Then she left.`, 1, `She said "no."`},
+		{"no punctuation", "can't think straight", 1, "can't think straight"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, limitSentences(tt.text, tt.n))
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package simulations
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	mcpsim "github.com/poiesic/wonda/internal/mcp/simulation"
+)
+
+// Situation notes reminding an agent of a goal's deadline: while there's
+// time, once a quarter or less of it is left, and once it has run out.
+const (
+	deadlineSituation       = "\n\n'%s' has a deadline: it has to be settled within the next %s."
+	deadlineNearSituation   = "\n\nTime is running out: '%s' fails in %s unless it's settled. Make it your priority."
+	deadlinePassedSituation = "\n\n'%s' is out of time: it fails at the end of this turn unless it's settled now."
+)
+
+// trackRunTime records how long the simulation has been running, so goal
+// deadlines and the time agents are told is left stay current.
+func (s *Simulation) trackRunTime() {
+	s.World.SetElapsed(s.runTimeBefore + s.Clock.Now().Sub(s.runStart))
+}
+
+// deadlineNote returns the situation notes reminding an agent of the time
+// left on the pending goals they decide that have deadlines, firmer once
+// little time is left, or "" when there are none.
+func (s *Simulation) deadlineNote(agentName string) string {
+	if s.isObserver(agentName) {
+		return ""
+	}
+
+	world := s.World.Snapshot()
+	var goals []*mcpsim.InteractiveGoal
+	for _, goal := range world.Goals {
+		if _, ok := goal.TimeRemaining(world.Elapsed); ok && goal.Status == mcpsim.GoalPending && world.CanDecide(goal, agentName) {
+			goals = append(goals, goal)
+		}
+	}
+	sort.Slice(goals, func(i, j int) bool { return goals[i].Name < goals[j].Name })
+
+	note := ""
+	for _, goal := range goals {
+		remaining, _ := goal.TimeRemaining(world.Elapsed)
+		switch {
+		case remaining < time.Second:
+			note += fmt.Sprintf(deadlinePassedSituation, goal.Name)
+		case remaining <= goal.Deadline/4:
+			note += fmt.Sprintf(deadlineNearSituation, goal.Name, remaining.Round(time.Second))
+		default:
+			note += fmt.Sprintf(deadlineSituation, goal.Name, remaining.Round(time.Second))
+		}
+	}
+	return note
+}
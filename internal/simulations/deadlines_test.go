@@ -0,0 +1,82 @@
+package simulations
+
+import (
+	"testing"
+	"time"
+
+	"github.com/poiesic/wonda/internal/chronicle"
+	mcpsim "github.com/poiesic/wonda/internal/mcp/simulation"
+	"github.com/poiesic/wonda/internal/scenarios"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// steppingClock is a clock the test moves forward.
+type steppingClock struct {
+	now time.Time
+}
+
+func (c *steppingClock) Now() time.Time { return c.now }
+
+func TestGoalDeadlines(t *testing.T) {
+	start := time.Date(2026, 10, 16, 19, 0, 0, 0, time.UTC)
+	newSim := func() (*Simulation, *steppingClock) {
+		world := mcpsim.NewWorldState("Bank vault", "")
+		world.AddAgent("Alice", "")
+		world.AddAgent("Bob", "")
+		goal := mcpsim.NewInteractiveGoal("bomb", "Defuse the bomb", "consensus", 1)
+		goal.Deadline = 8 * time.Minute
+		world.AddGoal(goal)
+
+		deadline := scenarios.Duration(8 * time.Minute)
+		clock := &steppingClock{now: start}
+		return &Simulation{
+			Scenario: &scenarios.Scenario{
+				Basics: &scenarios.BasicScenarioInformation{MaxTurns: 10},
+				Goals:  map[string]*scenarios.Goal{"bomb": {Name: "bomb", Deadline: &deadline}},
+				Agents: map[string]*scenarios.Agent{"Bob": {Observer: true}},
+			},
+			World:    world,
+			Clock:    clock,
+			runStart: start,
+		}, clock
+	}
+
+	t.Run("reminds deciding agents of the time left", func(t *testing.T) {
+		sim, clock := newSim()
+		clock.now = start.Add(2 * time.Minute)
+		sim.trackRunTime()
+		assert.Contains(t, sim.deadlineNote("Alice"), "settled within the next 6m0s")
+		assert.Empty(t, sim.deadlineNote("Bob"), "observers don't decide goals")
+
+		clock.now = start.Add(7 * time.Minute)
+		sim.trackRunTime()
+		assert.Contains(t, sim.deadlineNote("Alice"), "Time is running out: 'bomb' fails in 1m0s")
+	})
+
+	t.Run("fails goals once their deadline passes", func(t *testing.T) {
+		sim, clock := newSim()
+		clock.now = start.Add(7 * time.Minute)
+		sim.expireGoals(3)
+		assert.Empty(t, sim.currentGoalCompletions)
+
+		clock.now = start.Add(9 * time.Minute)
+		sim.expireGoals(4)
+		require.Len(t, sim.currentGoalCompletions, 1)
+		assert.Equal(t, chronicle.GoalCompletion{
+			GoalName:    "bomb",
+			Status:      string(mcpsim.GoalFailed),
+			Solution:    "Not completed within 8m0s",
+			CompletedAt: 4,
+		}, sim.currentGoalCompletions[0])
+		assert.Empty(t, sim.deadlineNote("Alice"), "settled goals need no reminder")
+	})
+
+	t.Run("counts the time before a resume", func(t *testing.T) {
+		sim, clock := newSim()
+		sim.runTimeBefore = 7 * time.Minute
+		clock.now = start.Add(time.Minute)
+		sim.trackRunTime()
+		assert.Contains(t, sim.deadlineNote("Alice"), "'bomb' is out of time")
+	})
+}
@@ -0,0 +1,253 @@
+package simulations
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/poiesic/wonda/internal/chronicle"
+	"github.com/poiesic/wonda/internal/config"
+	mcpsim "github.com/poiesic/wonda/internal/mcp/simulation"
+	"github.com/poiesic/wonda/internal/prompts"
+)
+
+// directorTranscriptSize is the number of recent messages the director reads.
+const directorTranscriptSize = 40
+
+// Kinds of injection a director makes.
+const (
+	injectionEvent      = "event"
+	injectionAtmosphere = "atmosphere"
+	injectionNudge      = "nudge"
+)
+
+// director is the model that steers the scene between turns.
+type director struct {
+	client Client
+	model  string
+}
+
+// Direction is a director's reply: what to bring into the scene this turn.
+type Direction struct {
+	Events     []string `json:"events"`     // Things that happen, perceived by everyone
+	Atmosphere string   `json:"atmosphere"` // New feel of the scene; "" leaves it as it is
+	Nudge      string   `json:"nudge"`      // Push for the deciding agents; "" when nothing has stalled
+}
+
+// initializeDirector creates the scenario's director, if it has one.
+func (s *Simulation) initializeDirector(models map[string]*config.Model, providers *config.Providers) error {
+	if s.Scenario.Director == nil {
+		return nil
+	}
+
+	modelName, model, provider, err := s.resolveDirector(models, providers)
+	if err != nil {
+		return err
+	}
+	client, err := s.newClient(usageCallerDirector, provider, model)
+	if err != nil {
+		return fmt.Errorf("failed to create director: %w", err)
+	}
+	s.director = &director{client: client, model: modelName}
+	slog.Info("director ready", "model", modelName, "every", *s.Scenario.Director.Every)
+	return nil
+}
+
+// resolveDirector returns the name, model and provider of the director's
+// model: its model, or else the scenario's default model.
+func (s *Simulation) resolveDirector(models map[string]*config.Model, providers *config.Providers) (string, *config.Model, *config.Provider, error) {
+	modelName := s.Scenario.Director.Model
+	if modelName == "" && s.Scenario.Basics.Defaults != nil {
+		modelName = s.Scenario.Basics.Defaults.Model
+	}
+	if modelName == "" {
+		return "", nil, nil, fmt.Errorf("director needs a model (the scenario has no default model)")
+	}
+
+	model, ok := models[modelName]
+	if !ok {
+		return "", nil, nil, fmt.Errorf("director model %s not found", modelName)
+	}
+	provider, ok := providers.Providers[model.Provider]
+	if !ok {
+		return "", nil, nil, fmt.Errorf("provider %s (from model %s) not found for the director", model.Provider, modelName)
+	}
+	return modelName, model, provider, nil
+}
+
+// direct asks the director how to steer the scene at the start of a turn and
+// applies its direction. The director first acts once there is a turn to
+// react to, then every few turns as configured. Failures are logged and the
+// turn goes on without direction.
+func (s *Simulation) direct(ctx context.Context, turn int) {
+	if s.director == nil || turn == 1 || (turn-1)%*s.Scenario.Director.Every != 0 {
+		return
+	}
+
+	prompt, err := s.buildDirectorPrompt(turn)
+	if err != nil {
+		slog.Warn("director failed", "model", s.director.model, "error", err)
+		return
+	}
+	resp, err := s.director.client.Chat(ctx, ChatRequest{
+		Messages: []Message{{Role: "user", Content: prompt}},
+	})
+	if err != nil {
+		slog.Warn("director failed", "model", s.director.model, "error", err)
+		return
+	}
+	direction, err := parseDirection(resp.Message)
+	if err != nil {
+		slog.Warn("director failed", "model", s.director.model, "error", err)
+		return
+	}
+	s.applyDirection(direction)
+}
+
+// applyDirection brings a director's events, atmosphere and nudge into the
+// scene, recording each in the chronicle. Events beyond the scenario's limit
+// are dropped.
+func (s *Simulation) applyDirection(direction Direction) {
+	injected := 0
+	for _, event := range direction.Events {
+		event = strings.TrimSpace(event)
+		if event == "" {
+			continue
+		}
+		if injected == *s.Scenario.Director.MaxEvents {
+			slog.Debug("director event dropped", "event", event, "reason", "max_events reached")
+			continue
+		}
+		slog.Info("director event", "event", event)
+		s.currentAmbient = append(s.currentAmbient, event)
+		s.World.InjectEvent(event)
+		s.inject(injectionEvent, event)
+		injected++
+	}
+
+	if atmosphere := strings.TrimSpace(direction.Atmosphere); atmosphere != "" {
+		slog.Info("director changed the atmosphere", "atmosphere", atmosphere)
+		s.World.SetAtmosphere(atmosphere)
+		s.inject(injectionAtmosphere, atmosphere)
+	}
+	if nudge := strings.TrimSpace(direction.Nudge); nudge != "" {
+		slog.Info("director nudge", "nudge", nudge)
+		s.currentNudge = nudge
+		s.inject(injectionNudge, nudge)
+	}
+}
+
+// inject records something the director brought into the scene this turn.
+func (s *Simulation) inject(kind, content string) {
+	s.currentInjected = append(s.currentInjected, chronicle.Injection{
+		Kind:    kind,
+		Content: content,
+		Source:  s.director.model,
+	})
+}
+
+// directorNote returns the director's nudge for this turn as a situation
+// note, or "" when there is none. Observers don't decide goals, so they are
+// never nudged.
+func (s *Simulation) directorNote(agentName string) string {
+	if s.currentNudge == "" || s.isObserver(agentName) {
+		return ""
+	}
+	return "\n\n" + s.currentNudge
+}
+
+// parseDirection reads a director reply, tolerating text around the JSON object.
+func parseDirection(reply string) (Direction, error) {
+	match := judgmentPattern.FindString(reply)
+	if match == "" {
+		return Direction{}, fmt.Errorf("director reply contained no JSON: %q", reply)
+	}
+
+	var direction Direction
+	if err := json.Unmarshal([]byte(match), &direction); err != nil {
+		return Direction{}, fmt.Errorf("invalid director reply %q: %w", match, err)
+	}
+	return direction, nil
+}
+
+// directorGoals describes each goal for the director: its status and, for
+// pending goals, how far the agents have got and when they last made progress.
+func directorGoals(world *mcpsim.WorldState) []string {
+	names := make([]string, 0, len(world.Goals))
+	for name := range world.Goals {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	lines := make([]string, 0, len(names))
+	for _, name := range names {
+		goal := world.Goals[name]
+		if goal.Status != mcpsim.GoalPending {
+			lines = append(lines, fmt.Sprintf("%s: %s (%s)", name, goal.Description, goal.Status))
+			continue
+		}
+
+		lastActivity := 0
+		for _, proposal := range goal.Proposals {
+			lastActivity = max(lastActivity, proposal.ProposedAt)
+			for _, vote := range proposal.Votes {
+				lastActivity = max(lastActivity, vote.VotedAt)
+			}
+		}
+		progress := "nothing proposed yet"
+		if len(goal.Proposals) > 0 {
+			progress = fmt.Sprintf("%d proposal(s), last activity on turn %d", len(goal.Proposals), lastActivity)
+		}
+		lines = append(lines, fmt.Sprintf("%s: %s (pending; %s)", name, goal.Description, progress))
+	}
+	return lines
+}
+
+// buildDirectorPrompt renders the director prompt template.
+func (s *Simulation) buildDirectorPrompt(turn int) (string, error) {
+	promptTemplate, err := prompts.GetPrompt("director")
+	if err != nil {
+		return "", fmt.Errorf("failed to load director prompt: %w", err)
+	}
+
+	tmpl, err := template.New("director").Parse(promptTemplate)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse template: %w", err)
+	}
+
+	world := s.World.Snapshot()
+	data := struct {
+		Scenario     string
+		Description  string
+		Location     string
+		Atmosphere   string
+		Turn         int
+		MaxTurns     int
+		Instructions string
+		Goals        []string
+		Transcript   []string
+		MaxEvents    int
+	}{
+		Scenario:     s.Scenario.Basics.Name,
+		Description:  s.Scenario.Basics.Description,
+		Location:     world.Location,
+		Atmosphere:   world.Atmosphere,
+		Turn:         turn,
+		MaxTurns:     s.MaxTurns(),
+		Instructions: s.Scenario.Director.Instructions,
+		Goals:        directorGoals(world),
+		Transcript:   judgeTranscript(world.GetRecentMessages(directorTranscriptSize)),
+		MaxEvents:    *s.Scenario.Director.MaxEvents,
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to execute template: %w", err)
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,74 @@
+package simulations
+
+import (
+	"context"
+	"testing"
+
+	"github.com/poiesic/wonda/internal/chronicle"
+	mcpsim "github.com/poiesic/wonda/internal/mcp/simulation"
+	"github.com/poiesic/wonda/internal/scenarios"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDirection(t *testing.T) {
+	direction, err := parseDirection("Here you go:\n{\"events\": [\"The power goes out.\"], \"nudge\": \"Decide now.\"}")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"The power goes out."}, direction.Events)
+	assert.Empty(t, direction.Atmosphere)
+	assert.Equal(t, "Decide now.", direction.Nudge)
+
+	_, err = parseDirection("nothing to add")
+	assert.Error(t, err)
+}
+
+func TestDirector(t *testing.T) {
+	newSim := func(reply string) *Simulation {
+		world := mcpsim.NewWorldState("Cafe", "Quiet")
+		world.AddAgent("Alice", "")
+		world.AddAgent("Nosy", "")
+		world.AddGoal(mcpsim.NewInteractiveGoal("dinner", "Pick a restaurant", "consensus", 1))
+
+		settings := &scenarios.DirectorConfig{}
+		settings.ApplyDefaults()
+		return &Simulation{
+			Scenario: &scenarios.Scenario{
+				Basics:   &scenarios.BasicScenarioInformation{Name: "Dinner", MaxTurns: 5},
+				Agents:   map[string]*scenarios.Agent{"Alice": {}, "Nosy": {Observer: true}},
+				Director: settings,
+			},
+			World:    world,
+			director: &director{client: &fakeClient{response: ChatResponse{Message: reply}}, model: "director"},
+		}
+	}
+
+	t.Run("brings its direction into the scene", func(t *testing.T) {
+		sim := newSim(`{"events": ["The power goes out.", "A glass breaks."], "atmosphere": "Dark and tense", "nudge": "The staff want to close up - decide."}`)
+		sim.direct(context.Background(), 2)
+
+		world := sim.World.Snapshot()
+		assert.Equal(t, []string{"The power goes out."}, world.AmbientEvents, "one event per intervention by default")
+		assert.Equal(t, "Dark and tense", world.Atmosphere)
+		assert.Equal(t, []string{"The power goes out."}, sim.currentAmbient)
+		assert.Equal(t, []chronicle.Injection{
+			{Kind: "event", Content: "The power goes out.", Source: "director"},
+			{Kind: "atmosphere", Content: "Dark and tense", Source: "director"},
+			{Kind: "nudge", Content: "The staff want to close up - decide.", Source: "director"},
+		}, sim.currentInjected)
+		assert.Contains(t, sim.directorNote("Alice"), "decide")
+		assert.Empty(t, sim.directorNote("Nosy"), "observers aren't nudged")
+	})
+
+	t.Run("waits for a turn to react to", func(t *testing.T) {
+		sim := newSim(`{"events": ["The power goes out."]}`)
+		sim.direct(context.Background(), 1)
+		assert.Empty(t, sim.currentInjected)
+	})
+
+	t.Run("failures leave the scene alone", func(t *testing.T) {
+		sim := newSim("I'd rather not")
+		sim.direct(context.Background(), 2)
+		assert.Empty(t, sim.currentInjected)
+		assert.Equal(t, "Quiet", sim.World.Snapshot().Atmosphere)
+	})
+}
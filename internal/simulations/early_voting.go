@@ -0,0 +1,123 @@
+package simulations
+
+import (
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+
+	mcpsim "github.com/poiesic/wonda/internal/mcp/simulation"
+)
+
+// agreementPattern matches the scenario's agreement phrases as whole words, or
+// is nil when the scenario doesn't vote early.
+func (s *Simulation) agreementPattern() *regexp.Regexp {
+	if s.Scenario.EarlyVoting == nil {
+		return nil
+	}
+	phrases := s.Scenario.EarlyVoting.AgreementPhrases()
+	quoted := make([]string, len(phrases))
+	for i, phrase := range phrases {
+		quoted[i] = regexp.QuoteMeta(phrase)
+	}
+	return regexp.MustCompile(`\b(` + strings.Join(quoted, "|") + `)\b`)
+}
+
+// voicesAgreement reports whether something an agent said goes along with
+// what's been proposed.
+func voicesAgreement(pattern *regexp.Regexp, message string) bool {
+	message = strings.ToLower(strings.ReplaceAll(message, "’", "'"))
+	return pattern.MatchString(message)
+}
+
+// earlyVotingReason returns why the turn can move straight to voting, with
+// agents still to deliberate, or "" if consensus isn't obviously forming.
+// Consensus is forming when enough of the deciding agents have voiced agreement
+// this turn, or when a goal's pending proposals from different agents say
+// nearly the same thing.
+func (s *Simulation) earlyVotingReason(agreed map[string]bool) string {
+	settings := s.Scenario.EarlyVoting
+	if settings == nil || !s.votesAwaited() {
+		return ""
+	}
+
+	var deciding, agreeing int
+	for _, agentName := range s.TurnOrder {
+		if s.isObserver(agentName) {
+			continue
+		}
+		deciding++
+		if agreed[agentName] {
+			agreeing++
+		}
+	}
+	if deciding > 0 && float64(agreeing)/float64(deciding) >= *settings.Agreement {
+		return fmt.Sprintf("%d of %d deciding agents agree", agreeing, deciding)
+	}
+
+	world := s.World.Snapshot()
+	for _, goalName := range slices.Sorted(maps.Keys(world.Goals)) {
+		goal := world.Goals[goalName]
+		if goal.Status != mcpsim.GoalPending {
+			continue
+		}
+		if proposalsConverged(goal, *settings.Similarity) {
+			return fmt.Sprintf("proposals for %s have converged", goalName)
+		}
+	}
+	return ""
+}
+
+// proposalsConverged reports whether a goal has pending proposals from more
+// than one agent and every pair of them overlaps by at least similarity.
+func proposalsConverged(goal *mcpsim.InteractiveGoal, similarity float64) bool {
+	var pending []*mcpsim.Proposal
+	proposers := make(map[string]bool)
+	for _, proposal := range goal.Proposals {
+		if proposal.Status == mcpsim.ProposalPending {
+			pending = append(pending, proposal)
+			proposers[proposal.ProposedBy] = true
+		}
+	}
+	if len(proposers) < 2 {
+		return false
+	}
+
+	for i, a := range pending {
+		for _, b := range pending[i+1:] {
+			if wordOverlap(a.Description, b.Description) < similarity {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// wordOverlap is the Jaccard similarity of the words in two texts, ignoring
+// case and punctuation: 1.0 when they use the same words, 0.0 when they share none.
+func wordOverlap(a, b string) float64 {
+	wordsA, wordsB := wordSet(a), wordSet(b)
+	if len(wordsA) == 0 && len(wordsB) == 0 {
+		return 1
+	}
+	shared := 0
+	for word := range wordsA {
+		if wordsB[word] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(wordsA)+len(wordsB)-shared)
+}
+
+// wordSet returns the distinct lowercased words of a text.
+func wordSet(text string) map[string]bool {
+	words := make(map[string]bool)
+	for _, word := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	}) {
+		words[word] = true
+	}
+	return words
+}
@@ -0,0 +1,76 @@
+package simulations
+
+import (
+	"testing"
+
+	mcpsim "github.com/poiesic/wonda/internal/mcp/simulation"
+	"github.com/poiesic/wonda/internal/scenarios"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVoicesAgreement(t *testing.T) {
+	settings := &scenarios.EarlyVotingConfig{Phrases: []string{"Let's book it"}}
+	settings.ApplyDefaults()
+	sim := &Simulation{Scenario: &scenarios.Scenario{EarlyVoting: settings}}
+	pattern := sim.agreementPattern()
+
+	assert.True(t, voicesAgreement(pattern, "Bella's? Sounds good to me."))
+	assert.True(t, voicesAgreement(pattern, "I’m in."))
+	assert.True(t, voicesAgreement(pattern, "Perfect, let's book it!"))
+	assert.False(t, voicesAgreement(pattern, "I disagreed last time and I still do."))
+	assert.False(t, voicesAgreement(pattern, "What about sushi?"))
+}
+
+func TestWordOverlap(t *testing.T) {
+	assert.Equal(t, 1.0, wordOverlap("Dinner at Bella's, 7pm", "dinner at bella's 7pm"))
+	assert.Equal(t, 0.0, wordOverlap("Sushi", "Pizza"))
+	assert.InDelta(t, 0.8, wordOverlap("dinner at the bistro tonight", "dinner at the bistro"), 0.01)
+}
+
+func TestEarlyVotingReason(t *testing.T) {
+	newSim := func(descriptions map[string]string) *Simulation {
+		world := mcpsim.NewWorldState("Cafe", "")
+		for _, name := range []string{"Alice", "Bob", "Carol"} {
+			world.AddAgent(name, "")
+		}
+		goal := mcpsim.NewInteractiveGoal("dinner", "Pick a restaurant", "consensus", 1)
+		for agent, description := range descriptions {
+			goal.Proposals[agent] = &mcpsim.Proposal{
+				ID:          agent,
+				Description: description,
+				ProposedBy:  agent,
+				Status:      mcpsim.ProposalPending,
+				Votes:       map[string]*mcpsim.Vote{},
+			}
+		}
+		world.AddGoal(goal)
+
+		settings := &scenarios.EarlyVotingConfig{}
+		settings.ApplyDefaults()
+		return &Simulation{
+			Scenario: &scenarios.Scenario{
+				EarlyVoting: settings,
+				Agents:      map[string]*scenarios.Agent{"Alice": {}, "Bob": {}, "Carol": {Observer: true}},
+			},
+			World:     world,
+			TurnOrder: []string{"Alice", "Bob", "Carol"},
+		}
+	}
+
+	t.Run("votes once enough deciding agents agree", func(t *testing.T) {
+		sim := newSim(map[string]string{"Alice": "Bella's"})
+		assert.Empty(t, sim.earlyVotingReason(map[string]bool{"Alice": true}))
+		assert.Empty(t, sim.earlyVotingReason(map[string]bool{"Alice": true, "Carol": true}), "observers aren't deciding")
+		assert.Equal(t, "2 of 2 deciding agents agree", sim.earlyVotingReason(map[string]bool{"Alice": true, "Bob": true}))
+	})
+
+	t.Run("votes once proposals converge", func(t *testing.T) {
+		assert.Equal(t, "proposals for dinner have converged",
+			newSim(map[string]string{"Alice": "Dinner at Bella's at 7pm", "Bob": "dinner at Bella's, 7pm"}).earlyVotingReason(nil))
+		assert.Empty(t, newSim(map[string]string{"Alice": "Bella's at 7pm", "Bob": "Sushi at 8pm"}).earlyVotingReason(nil))
+	})
+
+	t.Run("needs something to vote on", func(t *testing.T) {
+		assert.Empty(t, newSim(nil).earlyVotingReason(map[string]bool{"Alice": true, "Bob": true}))
+	})
+}
@@ -0,0 +1,162 @@
+package simulations
+
+import (
+	"fmt"
+	"log/slog"
+	"path"
+	"sort"
+
+	"github.com/poiesic/wonda/internal/config"
+	"github.com/poiesic/wonda/internal/memory"
+	"github.com/poiesic/wonda/internal/scenarios"
+)
+
+// selectEmbedding returns the in-process (onnx) embedding used for memories:
+// the one the scenario names, or else the first configured, by name.
+// It returns nil when the scenario names none and none is configured, in which
+// case the default gtr-t5-base model is used.
+func selectEmbedding(embeddingsPath string, scenario *scenarios.Scenario) (*config.Embedding, error) {
+	embeddings, err := config.LoadEmbeddingsFromFile(embeddingsPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load embeddings configuration: %w", err)
+	}
+
+	if name := scenario.Basics.Embedding; name != "" {
+		embedding, err := embeddings.Get(name)
+		if err != nil {
+			return nil, err
+		}
+		if embedding.Type != "onnx" {
+			return nil, fmt.Errorf("embedding '%s': simulations only support onnx embeddings", name)
+		}
+		return embedding, nil
+	}
+
+	names := make([]string, 0, len(embeddings.Embeddings))
+	for name, embedding := range embeddings.Embeddings {
+		if embedding.Type == "onnx" {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return nil, nil
+	}
+	sort.Strings(names)
+	return embeddings.Embeddings[names[0]], nil
+}
+
+// NewKnowledgeEmbedder returns the embedder simulations of a scenario use and
+// the name of its model, so documents can be embedded ahead of a run.
+func NewKnowledgeEmbedder(configDir string, scenario *scenarios.Scenario) (*memory.ONNXEmbedder, string, error) {
+	embedding, err := selectEmbedding(path.Join(configDir, "providers.toml"), scenario)
+	if err != nil {
+		return nil, "", err
+	}
+	embedder, err := newEmbedder(path.Join(configDir, "models"), embedding)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to initialize embeddings: %w", err)
+	}
+	return embedder, embeddingModel(embedding), nil
+}
+
+// embeddingModel names the model newEmbedder uses for an embedding.
+func embeddingModel(embedding *config.Embedding) string {
+	if embedding == nil || embedding.ModelURL == "" {
+		return memory.ModelDirName
+	}
+	return embedding.Model
+}
+
+// newEmbedder creates the in-process embedder for an embedding.
+// A model URL makes the embedding's model a replacement for the default one,
+// cached under its model name.
+func newEmbedder(modelsCache string, embedding *config.Embedding) (*memory.ONNXEmbedder, error) {
+	if embedding == nil || embedding.ModelURL == "" {
+		return memory.NewONNXEmbedderWithDownload(modelsCache, "")
+	}
+	return memory.NewONNXEmbedderForModel(modelsCache, embedding.Model, embedding.ModelURL, embedding.Dimensions)
+}
+
+// storeOptions returns the memory store settings for an embedding.
+// Memories are tagged with the scenario's language, and retrieval is limited
+// to the searcher's language unless the embedding is cross-lingual.
+func (s *Simulation) storeOptions(embedding *config.Embedding) (memory.StoreOptions, error) {
+	opts := memory.DefaultStoreOptions()
+	opts.Language = s.Scenario.Basics.Language
+	opts.Run = s.ID.String()
+	if embedding == nil {
+		return opts, nil
+	}
+
+	metric, err := memory.ParseMetric(embedding.Metric)
+	if err != nil {
+		return opts, fmt.Errorf("embedding '%s': %w", embedding.Name, err)
+	}
+	opts.Metric = metric
+	opts.Normalize = embedding.Normalize
+	opts.CrossLingual = embedding.CrossLingual()
+	return opts, nil
+}
+
+// warnUnsupportedLanguages logs the languages in the scenario that the
+// embedding model doesn't handle well, since retrieval will suffer.
+func (s *Simulation) warnUnsupportedLanguages(embedding *config.Embedding) {
+	if embedding == nil {
+		embedding = &config.Embedding{Name: memory.ModelDirName}
+	}
+
+	warned := make(map[string]bool)
+	for agentName := range s.Scenario.Agents {
+		language := s.Scenario.AgentLanguage(agentName)
+		if embedding.SupportsLanguage(language) || warned[language] {
+			continue
+		}
+		warned[language] = true
+		slog.Warn("embedding model may retrieve poorly in this language; set scenario.embedding to a multilingual model",
+			"embedding", embedding.Name, "language", language)
+	}
+}
+
+// newMemoryStore creates the run's memory store. Scenarios naming a store keep
+// memories and embeddings in a file in the config directory, or in a collection
+// of the vector store from providers.toml they name as its backend, shared by
+// every run using that store; otherwise they last only the run.
+func (s *Simulation) newMemoryStore(embedder *memory.ONNXEmbedder, embedding *config.Embedding, providers *config.Providers, opts memory.StoreOptions) (*memory.Store, error) {
+	settings := s.Scenario.Memory
+	if settings == nil || settings.Store == "" {
+		store, err := memory.NewStoreWithOptions(embedder, opts)
+		if err != nil {
+			return nil, fmt.Errorf("invalid memory store configuration: %w", err)
+		}
+		return store, nil
+	}
+
+	var backend memory.Backend
+	if settings.Backend != "" {
+		vectorStore, ok := providers.VectorStores[settings.Backend]
+		if !ok {
+			return nil, fmt.Errorf("memory backend %s not found in providers.toml vector_stores", settings.Backend)
+		}
+		remote, err := memory.OpenVectorStore(vectorStore, settings.Store, embeddingModel(embedding), embedder.Dimensions(), opts)
+		if err != nil {
+			return nil, err
+		}
+		backend = remote
+		slog.Info("memory store opened", "store", settings.Store, "backend", settings.Backend, "url", vectorStore.URL)
+	} else {
+		location := path.Join(s.ConfigDir, "memory", settings.Store+".jsonl")
+		file, err := memory.OpenFileBackend(location, embeddingModel(embedding), opts)
+		if err != nil {
+			return nil, err
+		}
+		backend = file
+		slog.Info("memory store opened", "store", settings.Store, "path", location, "memories", len(file.Memories()))
+	}
+
+	store, err := memory.NewStoreWithBackend(embedder, opts, backend)
+	if err != nil {
+		backend.Close()
+		return nil, fmt.Errorf("invalid memory store configuration: %w", err)
+	}
+	return store, nil
+}
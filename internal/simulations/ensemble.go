@@ -0,0 +1,292 @@
+package simulations
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"text/template"
+
+	"github.com/poiesic/wonda/internal/prompts"
+	"github.com/poiesic/wonda/internal/scenarios"
+)
+
+// Ensemble selectors
+const (
+	// EnsembleSelectorVote picks the response shared by the most samples.
+	EnsembleSelectorVote = "vote"
+	// EnsembleSelectorJudge asks a judge model to pick the best sample.
+	EnsembleSelectorJudge = "judge"
+)
+
+// defaultEnsembleSamples is the number of samples drawn when no model list is given.
+const defaultEnsembleSamples = 3
+
+// EnsembleMember is one client participating in an ensemble.
+type EnsembleMember struct {
+	Client Client
+	Model  string // Model name, recorded with each candidate
+}
+
+// EnsembleCandidate is a single sampled response considered by an ensemble.
+type EnsembleCandidate struct {
+	Model    string
+	Response ChatResponse
+	Selected bool
+}
+
+// EnsembleClient implements Client by sampling every member for the same request
+// and returning the response chosen by the selector (self-consistency).
+// All candidates are attached to the returned response for the chronicle.
+type EnsembleClient struct {
+	members  []EnsembleMember
+	selector string
+	judge    Client
+}
+
+// NewEnsembleClient creates an ensemble over the given members.
+// judge is required when selector is EnsembleSelectorJudge.
+func NewEnsembleClient(members []EnsembleMember, selector string, judge Client) (*EnsembleClient, error) {
+	if len(members) == 0 {
+		return nil, fmt.Errorf("ensemble requires at least one member")
+	}
+	if selector == "" {
+		selector = EnsembleSelectorVote
+	}
+	switch selector {
+	case EnsembleSelectorVote:
+	case EnsembleSelectorJudge:
+		if judge == nil {
+			return nil, fmt.Errorf("ensemble selector 'judge' requires a judge client")
+		}
+	default:
+		return nil, fmt.Errorf("unknown ensemble selector: %s", selector)
+	}
+
+	return &EnsembleClient{
+		members:  members,
+		selector: selector,
+		judge:    judge,
+	}, nil
+}
+
+// newEnsembleClientFromConfig builds an EnsembleClient from scenario configuration.
+// clientFor resolves a model name (from models/*.toml) to a ready client.
+func newEnsembleClientFromConfig(cfg *scenarios.EnsembleConfig, agentModel string, clientFor func(modelName string) (Client, error)) (*EnsembleClient, error) {
+	modelNames := cfg.Models
+	samples := cfg.Samples
+	if len(modelNames) == 0 {
+		modelNames = []string{agentModel}
+		if samples == 0 {
+			samples = defaultEnsembleSamples
+		}
+	}
+	if samples == 0 {
+		samples = 1
+	}
+
+	members := make([]EnsembleMember, 0, len(modelNames)*samples)
+	for _, modelName := range modelNames {
+		client, err := clientFor(modelName)
+		if err != nil {
+			return nil, fmt.Errorf("ensemble model %s: %w", modelName, err)
+		}
+		for i := 0; i < samples; i++ {
+			members = append(members, EnsembleMember{Client: client, Model: modelName})
+		}
+	}
+
+	var judge Client
+	if cfg.Selector == EnsembleSelectorJudge {
+		var err error
+		judge, err = clientFor(cfg.JudgeModel)
+		if err != nil {
+			return nil, fmt.Errorf("ensemble judge model %s: %w", cfg.JudgeModel, err)
+		}
+	}
+
+	return NewEnsembleClient(members, cfg.Selector, judge)
+}
+
+// Chat samples every member concurrently and returns the selected response.
+func (c *EnsembleClient) Chat(ctx context.Context, req ChatRequest) (ChatResponse, error) {
+	responses := make([]ChatResponse, len(c.members))
+	errs := make([]error, len(c.members))
+
+	var wg sync.WaitGroup
+	for i, member := range c.members {
+		wg.Add(1)
+		go func(i int, member EnsembleMember) {
+			defer wg.Done()
+			// Clear the model so each member uses its own configured model
+			memberReq := req
+			memberReq.Model = ""
+			responses[i], errs[i] = member.Client.Chat(ctx, memberReq)
+		}(i, member)
+	}
+	wg.Wait()
+
+	candidates := make([]EnsembleCandidate, 0, len(c.members))
+	var firstErr error
+	for i, member := range c.members {
+		if errs[i] != nil {
+			slog.Warn("ensemble sample failed", "model", member.Model, "error", errs[i])
+			if firstErr == nil {
+				firstErr = errs[i]
+			}
+			continue
+		}
+		candidates = append(candidates, EnsembleCandidate{
+			Model:    member.Model,
+			Response: responses[i],
+		})
+	}
+	if len(candidates) == 0 {
+		return ChatResponse{}, fmt.Errorf("all ensemble samples failed: %w", firstErr)
+	}
+
+	selected := c.selectCandidate(ctx, req, candidates)
+	candidates[selected].Selected = true
+	slog.Debug("ensemble selected candidate", "selector", c.selector, "index", selected, "model", candidates[selected].Model, "candidates", len(candidates))
+
+	result := candidates[selected].Response
+	result.Candidates = candidates
+	return result, nil
+}
+
+// selectCandidate returns the index of the candidate to execute.
+func (c *EnsembleClient) selectCandidate(ctx context.Context, req ChatRequest, candidates []EnsembleCandidate) int {
+	if len(candidates) == 1 {
+		return 0
+	}
+	if c.selector == EnsembleSelectorJudge {
+		idx, err := c.judgeCandidates(ctx, req, candidates)
+		if err == nil {
+			return idx
+		}
+		slog.Warn("ensemble judge failed, falling back to vote", "error", err)
+	}
+	return voteCandidates(candidates)
+}
+
+// voteCandidates returns the index of the candidate whose action was produced
+// by the most samples. Ties go to the earliest candidate.
+func voteCandidates(candidates []EnsembleCandidate) int {
+	counts := make(map[string]int)
+	for _, candidate := range candidates {
+		counts[candidateSignature(candidate.Response)]++
+	}
+
+	best := 0
+	bestCount := 0
+	for i, candidate := range candidates {
+		if count := counts[candidateSignature(candidate.Response)]; count > bestCount {
+			best = i
+			bestCount = count
+		}
+	}
+	return best
+}
+
+// candidateSignature reduces a response to the action it would take so that
+// equivalent samples vote together. Tool calls take precedence over free text.
+func candidateSignature(resp ChatResponse) string {
+	if len(resp.ToolCalls) > 0 {
+		calls := make([]string, len(resp.ToolCalls))
+		for i, tc := range resp.ToolCalls {
+			calls[i] = formatToolCall(tc)
+		}
+		return strings.ToLower(strings.Join(calls, ";"))
+	}
+	return strings.ToLower(strings.Join(strings.Fields(resp.Message), " "))
+}
+
+// formatToolCall renders a tool call as name(arguments) for display and comparison.
+// Arguments are marshaled as JSON, which orders map keys deterministically.
+func formatToolCall(tc ToolCall) string {
+	args, err := json.Marshal(tc.Arguments)
+	if err != nil {
+		return tc.Name + "(?)"
+	}
+	return fmt.Sprintf("%s(%s)", tc.Name, string(args))
+}
+
+// judgeNumberPattern extracts the first number from the judge's reply.
+var judgeNumberPattern = regexp.MustCompile(`\d+`)
+
+// judgeCandidates asks the judge model to pick the best candidate.
+func (c *EnsembleClient) judgeCandidates(ctx context.Context, req ChatRequest, candidates []EnsembleCandidate) (int, error) {
+	prompt, err := buildJudgePrompt(req, candidates)
+	if err != nil {
+		return 0, err
+	}
+
+	resp, err := c.judge.Chat(ctx, ChatRequest{
+		Messages: []Message{{Role: "user", Content: prompt}},
+	})
+	if err != nil {
+		return 0, fmt.Errorf("judge call failed: %w", err)
+	}
+
+	match := judgeNumberPattern.FindString(resp.Message)
+	if match == "" {
+		return 0, fmt.Errorf("judge reply contained no candidate number: %q", resp.Message)
+	}
+	choice, err := strconv.Atoi(match)
+	if err != nil || choice < 1 || choice > len(candidates) {
+		return 0, fmt.Errorf("judge picked invalid candidate %q", match)
+	}
+	return choice - 1, nil
+}
+
+// buildJudgePrompt renders the ensemble judge prompt template.
+// The first message of the request (the agent's prompt) is given as context.
+func buildJudgePrompt(req ChatRequest, candidates []EnsembleCandidate) (string, error) {
+	promptTemplate, err := prompts.GetPrompt("ensemble_judge")
+	if err != nil {
+		return "", fmt.Errorf("failed to load ensemble judge prompt: %w", err)
+	}
+
+	tmpl, err := template.New("ensemble_judge").Funcs(template.FuncMap{
+		"inc": func(i int) int { return i + 1 },
+	}).Parse(promptTemplate)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse template: %w", err)
+	}
+
+	type judgeCandidate struct {
+		Message   string
+		ToolCalls []string
+	}
+	judged := make([]judgeCandidate, len(candidates))
+	for i, candidate := range candidates {
+		judged[i].Message = candidate.Response.Message
+		for _, tc := range candidate.Response.ToolCalls {
+			judged[i].ToolCalls = append(judged[i].ToolCalls, formatToolCall(tc))
+		}
+	}
+
+	var agentPrompt string
+	if len(req.Messages) > 0 {
+		agentPrompt = req.Messages[0].Content
+	}
+
+	data := struct {
+		Context    string
+		Candidates []judgeCandidate
+	}{
+		Context:    agentPrompt,
+		Candidates: judged,
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to execute template: %w", err)
+	}
+	return buf.String(), nil
+}
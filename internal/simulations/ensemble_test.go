@@ -0,0 +1,107 @@
+package simulations
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClient returns a fixed response for every request.
+type fakeClient struct {
+	response ChatResponse
+	err      error
+}
+
+func (f *fakeClient) Chat(ctx context.Context, req ChatRequest) (ChatResponse, error) {
+	return f.response, f.err
+}
+
+func TestEnsembleClient(t *testing.T) {
+	speak := func(text string) *fakeClient {
+		return &fakeClient{response: ChatResponse{
+			ToolCalls: []ToolCall{{Name: "speak", Arguments: map[string]interface{}{"message": text}}},
+		}}
+	}
+
+	t.Run("vote selects majority action", func(t *testing.T) {
+		members := []EnsembleMember{
+			{Client: speak("Pizza?"), Model: "a"},
+			{Client: speak("Sushi?"), Model: "b"},
+			{Client: speak("Sushi?"), Model: "c"},
+		}
+		ensemble, err := NewEnsembleClient(members, EnsembleSelectorVote, nil)
+		require.NoError(t, err)
+
+		resp, err := ensemble.Chat(context.Background(), ChatRequest{})
+		require.NoError(t, err)
+		assert.Equal(t, "Sushi?", resp.ToolCalls[0].Arguments["message"])
+		require.Len(t, resp.Candidates, 3)
+		assert.False(t, resp.Candidates[0].Selected)
+		assert.True(t, resp.Candidates[1].Selected)
+		assert.False(t, resp.Candidates[2].Selected)
+	})
+
+	t.Run("judge selects numbered candidate", func(t *testing.T) {
+		members := []EnsembleMember{
+			{Client: speak("Pizza?"), Model: "a"},
+			{Client: speak("Sushi?"), Model: "b"},
+		}
+		judge := &fakeClient{response: ChatResponse{Message: "1"}}
+		ensemble, err := NewEnsembleClient(members, EnsembleSelectorJudge, judge)
+		require.NoError(t, err)
+
+		resp, err := ensemble.Chat(context.Background(), ChatRequest{Messages: []Message{{Role: "user", Content: "Where to eat?"}}})
+		require.NoError(t, err)
+		assert.Equal(t, "Pizza?", resp.ToolCalls[0].Arguments["message"])
+		assert.True(t, resp.Candidates[0].Selected)
+	})
+
+	t.Run("judge falls back to vote on invalid reply", func(t *testing.T) {
+		members := []EnsembleMember{
+			{Client: speak("Pizza?"), Model: "a"},
+			{Client: speak("Sushi?"), Model: "b"},
+			{Client: speak("Sushi?"), Model: "c"},
+		}
+		judge := &fakeClient{response: ChatResponse{Message: "7"}}
+		ensemble, err := NewEnsembleClient(members, EnsembleSelectorJudge, judge)
+		require.NoError(t, err)
+
+		resp, err := ensemble.Chat(context.Background(), ChatRequest{})
+		require.NoError(t, err)
+		assert.Equal(t, "Sushi?", resp.ToolCalls[0].Arguments["message"])
+	})
+
+	t.Run("ignores failed samples", func(t *testing.T) {
+		members := []EnsembleMember{
+			{Client: &fakeClient{err: fmt.Errorf("boom")}, Model: "a"},
+			{Client: speak("Sushi?"), Model: "b"},
+		}
+		ensemble, err := NewEnsembleClient(members, "", nil)
+		require.NoError(t, err)
+
+		resp, err := ensemble.Chat(context.Background(), ChatRequest{})
+		require.NoError(t, err)
+		require.Len(t, resp.Candidates, 1)
+		assert.Equal(t, "b", resp.Candidates[0].Model)
+	})
+
+	t.Run("returns error when all samples fail", func(t *testing.T) {
+		members := []EnsembleMember{
+			{Client: &fakeClient{err: fmt.Errorf("boom")}, Model: "a"},
+		}
+		ensemble, err := NewEnsembleClient(members, "", nil)
+		require.NoError(t, err)
+
+		_, err = ensemble.Chat(context.Background(), ChatRequest{})
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "all ensemble samples failed")
+	})
+
+	t.Run("judge selector requires judge client", func(t *testing.T) {
+		_, err := NewEnsembleClient([]EnsembleMember{{Client: speak("hi"), Model: "a"}}, EnsembleSelectorJudge, nil)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,61 @@
+package simulations
+
+import (
+	"encoding/json"
+
+	"github.com/poiesic/wonda/internal/chronicle"
+)
+
+// captureInvocations records the tools an agent called on the most recently
+// captured event.
+func (s *Simulation) captureInvocations(invocations []ToolInvocation) {
+	if len(invocations) == 0 || len(s.currentTurnEvents) == 0 {
+		return
+	}
+
+	event := &s.currentTurnEvents[len(s.currentTurnEvents)-1]
+	for _, invocation := range invocations {
+		args, err := json.Marshal(invocation.Call.Arguments)
+		if err != nil {
+			args = []byte("{}")
+		}
+		event.Entries = append(event.Entries, chronicle.Entry{
+			Kind:      chronicle.EntryToolCall,
+			Tool:      invocation.Call.Name,
+			Arguments: string(args),
+			Error:     invocation.Error,
+		})
+	}
+}
+
+// captureModelUse records the model that took an agent's turn, and how many
+// requests its tool loop made, on the most recently captured event.
+func (s *Simulation) captureModelUse(agentName string, iterations int) {
+	agent, ok := s.Agents[agentName]
+	if !ok || len(s.currentTurnEvents) == 0 {
+		return
+	}
+	event := &s.currentTurnEvents[len(s.currentTurnEvents)-1]
+	event.Model = agent.Model
+	event.Iterations = iterations
+}
+
+// captureCompletionEntry records a goal completion on the latest event this
+// turn of the agent it is credited to: who completed an individual goal, or
+// who proposed the accepted solution. Completions credited to an agent who
+// did nothing this turn are only in the turn's goal completions.
+func (s *Simulation) captureCompletionEntry(agentName string, completion chronicle.GoalCompletion) {
+	for i := len(s.currentTurnEvents) - 1; i >= 0; i-- {
+		event := &s.currentTurnEvents[i]
+		if event.AgentName != agentName {
+			continue
+		}
+		event.Entries = append(event.Entries, chronicle.Entry{
+			Kind:     chronicle.EntryGoalCompletion,
+			Goal:     completion.GoalName,
+			Proposal: completion.Solution,
+			Status:   completion.Status,
+		})
+		return
+	}
+}
@@ -0,0 +1,32 @@
+package simulations
+
+import (
+	"testing"
+
+	"github.com/poiesic/wonda/internal/chronicle"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCaptureEntries(t *testing.T) {
+	s := &Simulation{currentTurnEvents: []chronicle.Event{
+		{AgentName: "Alice", Dialogue: "Pizza?"},
+		{AgentName: "Bob", Dialogue: "Sure."},
+	}}
+
+	s.captureInvocations([]ToolInvocation{
+		{Call: ToolCall{Name: "view_goal", Arguments: map[string]interface{}{"goal": "dinner"}}},
+		{Call: ToolCall{Name: "vote", Arguments: map[string]interface{}{}}, Error: "missing proposal_id"},
+	})
+	s.captureCompletionEntry("Alice", chronicle.GoalCompletion{GoalName: "dinner", Status: "completed", Solution: "Pizza"})
+	s.captureCompletionEntry("Carol", chronicle.GoalCompletion{GoalName: "dessert", Status: "completed"})
+
+	assert.Equal(t, []chronicle.Entry{
+		{Kind: chronicle.EntryGoalCompletion, Goal: "dinner", Proposal: "Pizza", Status: "completed"},
+	}, s.currentTurnEvents[0].Entries)
+	assert.Equal(t, []chronicle.Entry{
+		{Kind: chronicle.EntryToolCall, Tool: "view_goal", Arguments: `{"goal":"dinner"}`},
+		{Kind: chronicle.EntryToolCall, Tool: "vote", Arguments: `{}`, Error: "missing proposal_id"},
+	}, s.currentTurnEvents[1].Entries)
+	assert.Len(t, s.currentTurnEvents[1].EntriesOf(chronicle.EntryToolCall), 2)
+	assert.Empty(t, s.currentTurnEvents[1].EntriesOf(chronicle.EntryVote))
+}
@@ -0,0 +1,79 @@
+package simulations
+
+import (
+	"log/slog"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/poiesic/wonda/internal/scenarios"
+)
+
+// ambience rolls the scenario's random ambient events each turn.
+type ambience struct {
+	events     []scenarios.AmbientEvent
+	maxPerTurn int
+	happened   map[int]bool // Indexes of once-only events that already occurred
+	rng        *rand.Rand
+}
+
+// newAmbience creates an event roller for an environment configuration.
+func newAmbience(cfg *scenarios.EnvironmentConfig) *ambience {
+	maxPerTurn := 1
+	if cfg.MaxPerTurn != nil {
+		maxPerTurn = *cfg.MaxPerTurn
+	}
+	return &ambience{
+		events:     cfg.AllEvents(),
+		maxPerTurn: maxPerTurn,
+		happened:   make(map[int]bool),
+		rng:        rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// roll decides which events happen this turn.
+// Events are checked in random order so the cap doesn't favor earlier entries.
+func (a *ambience) roll() []scenarios.AmbientEvent {
+	var occurred []scenarios.AmbientEvent
+	for _, i := range a.rng.Perm(len(a.events)) {
+		if len(occurred) >= a.maxPerTurn {
+			break
+		}
+		event := a.events[i]
+		if event.Once && a.happened[i] {
+			continue
+		}
+		if a.rng.Float64() < event.Probability {
+			occurred = append(occurred, event)
+			a.happened[i] = true
+		}
+	}
+	return occurred
+}
+
+// startAmbientEvents rolls this turn's ambient events and makes them perceivable.
+// Events that affect condition change every agent's.
+func (s *Simulation) startAmbientEvents(turn int) {
+	if s.ambience == nil {
+		return
+	}
+	for _, event := range s.ambience.roll() {
+		slog.Info("ambient event", "turn", turn, "event", event.Description)
+		s.currentAmbient = append(s.currentAmbient, event.Description)
+		if event.Condition != 0 {
+			for _, agentName := range s.TurnOrder {
+				s.World.AdjustCondition(agentName, event.Condition, event.Description)
+			}
+		}
+	}
+	s.World.SetAmbientEvents(s.currentAmbient)
+	s.captureConditionChanges()
+}
+
+// ambientSituation describes this turn's ambient events for agent prompts.
+func (s *Simulation) ambientSituation() string {
+	if len(s.currentAmbient) == 0 {
+		return ""
+	}
+	return "\n\nMeanwhile, around you: " + strings.Join(s.currentAmbient, " ")
+}
@@ -0,0 +1,86 @@
+package simulations
+
+import (
+	"context"
+	"testing"
+
+	mcpsim "github.com/poiesic/wonda/internal/mcp/simulation"
+	"github.com/poiesic/wonda/internal/runtime"
+	"github.com/poiesic/wonda/internal/scenarios"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAmbience(t *testing.T) {
+	newAmbientSimulation := func(t *testing.T, env *scenarios.EnvironmentConfig) *Simulation {
+		scenario := scenarios.NewScenario()
+		scenario.Environment = env
+		sim := NewSimulation(scenario, t.TempDir())
+		sim.World.AddAgent("Alex", "table")
+		sim.TurnOrder = []string{"Alex"}
+		return sim
+	}
+	// nextTurn starts a turn's ambient events, clearing the last turn's as
+	// writing it to the chronicle does
+	nextTurn := func(sim *Simulation, turn int) []string {
+		sim.currentAmbient = nil
+		sim.World.SetTurn(turn)
+		sim.startAmbientEvents(turn)
+		return sim.currentAmbient
+	}
+
+	t.Run("adds the turn's events to the situation", func(t *testing.T) {
+		sim := newAmbientSimulation(t, &scenarios.EnvironmentConfig{Events: []scenarios.AmbientEvent{
+			{Description: "Rain starts drumming against the windows.", Probability: 1},
+		}})
+		nextTurn(sim, 1)
+
+		assert.Equal(t, "\n\nMeanwhile, around you: Rain starts drumming against the windows.", sim.ambientSituation())
+		assert.Equal(t, []string{"Rain starts drumming against the windows."}, sim.World.Snapshot().AmbientEvents)
+
+		result, err := mcpsim.NewPerceiveTool(sim.World).Handler(context.WithValue(context.Background(), runtime.AgentNameKey, "Alex"), map[string]interface{}{})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Rain starts drumming against the windows."}, result.(*mcpsim.PerceptionResult).AmbientEvents)
+	})
+
+	t.Run("leaves the situation alone without events", func(t *testing.T) {
+		sim := newAmbientSimulation(t, nil)
+		nextTurn(sim, 1)
+		assert.Empty(t, sim.ambientSituation())
+
+		sim = newAmbientSimulation(t, &scenarios.EnvironmentConfig{Events: []scenarios.AmbientEvent{
+			{Description: "A dog starts barking.", Probability: 0},
+		}})
+		for turn := 1; turn <= 20; turn++ {
+			assert.Empty(t, nextTurn(sim, turn))
+		}
+		assert.Empty(t, sim.ambientSituation())
+		assert.Empty(t, sim.World.Snapshot().AmbientEvents)
+	})
+
+	t.Run("caps events per turn", func(t *testing.T) {
+		maxPerTurn := 2
+		sim := newAmbientSimulation(t, &scenarios.EnvironmentConfig{
+			Events: []scenarios.AmbientEvent{
+				{Description: "A phone buzzes.", Probability: 1},
+				{Description: "A siren wails past.", Probability: 1},
+				{Description: "The lights flicker.", Probability: 1},
+			},
+			MaxPerTurn: &maxPerTurn,
+		})
+		for turn := 1; turn <= 5; turn++ {
+			assert.Len(t, nextTurn(sim, turn), 2)
+		}
+	})
+
+	t.Run("happens once-only events once", func(t *testing.T) {
+		sim := newAmbientSimulation(t, &scenarios.EnvironmentConfig{Events: []scenarios.AmbientEvent{
+			{Description: "The power goes out.", Probability: 1, Once: true},
+		}})
+		assert.Equal(t, []string{"The power goes out."}, nextTurn(sim, 1))
+		for turn := 2; turn <= 5; turn++ {
+			assert.Empty(t, nextTurn(sim, turn))
+			assert.Empty(t, sim.ambientSituation())
+		}
+	})
+}
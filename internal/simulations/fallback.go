@@ -0,0 +1,71 @@
+package simulations
+
+import (
+	"context"
+	"hash/fnv"
+	"log/slog"
+
+	mcpsim "github.com/poiesic/wonda/internal/mcp/simulation"
+	"github.com/poiesic/wonda/internal/scenarios"
+)
+
+// fallback stands an in-character action in for an agent's turn when their
+// model failed after its retries, and reports whether it did. It doesn't when
+// the scenario has no fallback, the run itself is being cancelled, or the
+// agent has failed too many times in a row; the run then ends as it would
+// without fallbacks.
+func (s *Simulation) fallback(ctx context.Context, agentName string, turn int, cause error) bool {
+	config := s.Scenario.Fallback
+	if config == nil || ctx.Err() != nil {
+		return false
+	}
+	s.fallbacks[agentName]++
+	if s.fallbacks[agentName] > config.MaxConsecutive {
+		slog.Warn("too many model failures in a row for a fallback", "agent", agentName, "failures", s.fallbacks[agentName]-1)
+		return false
+	}
+
+	agent := s.Agents[agentName]
+	text, err := config.Render(fallbackPick(agentName, turn, len(config.Templates)), fallbackValues(agent, turn))
+	if err != nil {
+		slog.Warn("failed to render fallback", "agent", agentName, "error", err)
+		return false
+	}
+	slog.Warn("model failed, falling back", "agent", agentName, "action", text, "error", cause)
+
+	// Others see the action as if the agent had taken it
+	s.World.AddMessage(agentName, text, "", mcpsim.MessageTypeAction)
+	s.captureEvent(agentName, text, "", string(mcpsim.MessageTypeAction))
+	s.currentTurnEvents[len(s.currentTurnEvents)-1].Fallback = cause.Error()
+	return true
+}
+
+// modelSucceeded resets an agent's run of failures.
+func (s *Simulation) modelSucceeded(agentName string) {
+	delete(s.fallbacks, agentName)
+}
+
+// fallbackValues describes an agent to the fallback templates.
+func fallbackValues(agent *Agent, turn int) scenarios.FallbackValues {
+	values := scenarios.FallbackValues{Name: agent.Name, Emotion: agent.State.Emotion, Trait: "quiet"}
+	if agent.Character != nil && agent.Character.External != nil {
+		external := agent.Character.External
+		values.Archetype = external.Archetype
+		traits := append(append([]string(nil), external.PositiveTraits...), external.NegativeTraits...)
+		if len(traits) > 0 {
+			values.Trait = traits[fallbackPick(agent.Name+"/trait", turn, len(traits))]
+		}
+	}
+	if values.Emotion == "" {
+		values.Emotion = "neutral"
+	}
+	return values
+}
+
+// fallbackPick picks one of n options for an agent's turn, so fallbacks vary
+// between agents and turns but a rerun picks the same ones.
+func fallbackPick(key string, turn, n int) int {
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	return int((h.Sum32() + uint32(turn)) % uint32(n))
+}
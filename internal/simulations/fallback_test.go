@@ -0,0 +1,66 @@
+package simulations
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/poiesic/wonda/internal/chronicle"
+	mcpsim "github.com/poiesic/wonda/internal/mcp/simulation"
+	"github.com/poiesic/wonda/internal/scenarios"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFallback(t *testing.T) {
+	newSim := func(fallback *scenarios.FallbackConfig) *Simulation {
+		if fallback != nil {
+			fallback.ApplyDefaults()
+		}
+		character := scenarios.NewCharacter()
+		character.External.Archetype = "skeptic"
+		character.External.NegativeTraits = []string{"stubborn"}
+		world := mcpsim.NewWorldState("Cafe", "")
+		world.AddAgent("Sam", "")
+		return &Simulation{
+			Scenario:  &scenarios.Scenario{Basics: &scenarios.BasicScenarioInformation{}, Fallback: fallback},
+			Agents:    map[string]*Agent{"Sam": {Name: "Sam", Character: character}},
+			World:     world,
+			fallbacks: make(map[string]int),
+		}
+	}
+	modelErr := errors.New("request timed out")
+
+	t.Run("stands an action in for the failed turn", func(t *testing.T) {
+		sim := newSim(&scenarios.FallbackConfig{Templates: []string{"{{.Name}}, the {{.Trait}} {{.Archetype}}, stays quiet."}})
+		require.True(t, sim.fallback(context.Background(), "Sam", 1, modelErr))
+
+		require.Len(t, sim.currentTurnEvents, 1)
+		event := sim.currentTurnEvents[0]
+		assert.Equal(t, chronicle.Event{
+			AgentName: "Sam", Type: "action", Dialogue: "Sam, the stubborn skeptic, stays quiet.", Fallback: "request timed out",
+			Emotion: event.Emotion,
+		}, event)
+		history := sim.World.Snapshot().ConversationHistory
+		require.NotEmpty(t, history)
+		assert.Equal(t, "Sam, the stubborn skeptic, stays quiet.", history[len(history)-1].Content)
+	})
+
+	t.Run("gives up after too many failures in a row", func(t *testing.T) {
+		sim := newSim(&scenarios.FallbackConfig{MaxConsecutive: 2})
+		assert.True(t, sim.fallback(context.Background(), "Sam", 1, modelErr))
+		assert.True(t, sim.fallback(context.Background(), "Sam", 2, modelErr))
+		assert.False(t, sim.fallback(context.Background(), "Sam", 3, modelErr))
+
+		sim.modelSucceeded("Sam")
+		assert.True(t, sim.fallback(context.Background(), "Sam", 4, modelErr))
+	})
+
+	t.Run("not without a fallback or when the run is cancelled", func(t *testing.T) {
+		assert.False(t, newSim(nil).fallback(context.Background(), "Sam", 1, modelErr))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		assert.False(t, newSim(&scenarios.FallbackConfig{}).fallback(ctx, "Sam", 1, modelErr))
+	})
+}
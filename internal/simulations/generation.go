@@ -0,0 +1,79 @@
+package simulations
+
+import (
+	"fmt"
+
+	"github.com/poiesic/wonda/internal/config"
+)
+
+// anthropicDefaultMaxTokens bounds Anthropic responses when the model sets no
+// max_tokens, since the Messages API requires a limit.
+const anthropicDefaultMaxTokens = 4096
+
+// anthropicMaxTemperature is the highest temperature the Messages API accepts.
+const anthropicMaxTemperature = 1
+
+// requestGeneration returns the generation parameters for a request: the
+// model's, with any the request sets in their place.
+func requestGeneration(model *config.Model, req ChatRequest) config.Generation {
+	return model.Generation.Merge(req.Generation)
+}
+
+// applyOpenAIGeneration adds generation parameters to a chat completion request body.
+func applyOpenAIGeneration(reqBody map[string]interface{}, gen config.Generation) {
+	if gen.Temperature != nil {
+		reqBody["temperature"] = *gen.Temperature
+	}
+	if gen.TopP != nil {
+		reqBody["top_p"] = *gen.TopP
+	}
+	if gen.MaxTokens > 0 {
+		reqBody["max_tokens"] = gen.MaxTokens
+	}
+	if gen.FrequencyPenalty != nil {
+		reqBody["frequency_penalty"] = *gen.FrequencyPenalty
+	}
+	if len(gen.Stop) > 0 {
+		reqBody["stop"] = gen.Stop
+	}
+}
+
+// applyAnthropicGeneration adds generation parameters to a Messages request body.
+func applyAnthropicGeneration(reqBody map[string]interface{}, gen config.Generation) {
+	reqBody["max_tokens"] = anthropicMaxTokens(gen)
+	if gen.Temperature != nil {
+		reqBody["temperature"] = *gen.Temperature
+	}
+	if gen.TopP != nil {
+		reqBody["top_p"] = *gen.TopP
+	}
+	if len(gen.Stop) > 0 {
+		reqBody["stop_sequences"] = gen.Stop
+	}
+}
+
+// anthropicMaxTokens returns the response limit for a Messages request.
+func anthropicMaxTokens(gen config.Generation) int {
+	if gen.MaxTokens > 0 {
+		return gen.MaxTokens
+	}
+	return anthropicDefaultMaxTokens
+}
+
+// checkGeneration rejects generation parameters that are out of range or
+// that the provider's API doesn't accept.
+func checkGeneration(provider *config.Provider, modelName string, gen config.Generation) error {
+	if err := gen.Validate(); err != nil {
+		return fmt.Errorf("model '%s': %w", modelName, err)
+	}
+	if !isAnthropicProvider(provider) {
+		return nil
+	}
+	if gen.FrequencyPenalty != nil {
+		return fmt.Errorf("model '%s': frequency_penalty isn't supported by Anthropic", modelName)
+	}
+	if gen.Temperature != nil && *gen.Temperature > anthropicMaxTemperature {
+		return fmt.Errorf("model '%s': Anthropic accepts temperatures up to %d (got %g)", modelName, anthropicMaxTemperature, *gen.Temperature)
+	}
+	return nil
+}
@@ -0,0 +1,209 @@
+package simulations
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"sort"
+	"text/template"
+
+	"github.com/poiesic/wonda/internal/chronicle"
+	"github.com/poiesic/wonda/internal/config"
+	mcpsim "github.com/poiesic/wonda/internal/mcp/simulation"
+	"github.com/poiesic/wonda/internal/prompts"
+	"github.com/poiesic/wonda/internal/scenarios"
+)
+
+// judgeTranscriptSize is the number of recent messages the goal judge reads.
+const judgeTranscriptSize = 30
+
+// goalJudge is the model that checks a judged goal's criteria.
+type goalJudge struct {
+	client Client
+	model  string
+}
+
+// Judgment is a judge's verdict on a goal's progress.
+type Judgment struct {
+	Confidence float64 `json:"confidence"` // 0.0-1.0 that every criterion is met
+	Assessment string  `json:"assessment"` // Which criteria are met and which are not
+}
+
+// initializeGoalJudges creates a judge client for each judged goal in the scenario.
+func (s *Simulation) initializeGoalJudges(models map[string]*config.Model, providers *config.Providers) error {
+	for goalName, goal := range s.Scenario.Goals {
+		if !goal.Judged() {
+			continue
+		}
+
+		modelName, model, provider, err := s.resolveGoalJudge(goalName, goal, models, providers)
+		if err != nil {
+			return err
+		}
+
+		client, err := s.newClient(usageCallerJudge, provider, model)
+		if err != nil {
+			return fmt.Errorf("failed to create judge for goal %s: %w", goalName, err)
+		}
+		s.goalJudges[goalName] = &goalJudge{client: client, model: modelName}
+		slog.Info("goal judge ready", "goal", goalName, "model", modelName, "threshold", goal.Threshold())
+	}
+	return nil
+}
+
+// resolveGoalJudge returns the name, model and provider of the model that
+// judges a goal: its judge_model, or else the scenario's default model.
+func (s *Simulation) resolveGoalJudge(goalName string, goal *scenarios.Goal, models map[string]*config.Model, providers *config.Providers) (string, *config.Model, *config.Provider, error) {
+	modelName := goal.JudgeModel
+	if modelName == "" && s.Scenario.Basics.Defaults != nil {
+		modelName = s.Scenario.Basics.Defaults.Model
+	}
+	if modelName == "" {
+		return "", nil, nil, fmt.Errorf("goal %s needs a judge_model (the scenario has no default model)", goalName)
+	}
+
+	model, ok := models[modelName]
+	if !ok {
+		return "", nil, nil, fmt.Errorf("judge model %s not found for goal %s", modelName, goalName)
+	}
+	provider, ok := providers.Providers[model.Provider]
+	if !ok {
+		return "", nil, nil, fmt.Errorf("provider %s (from model %s) not found for goal %s judge", model.Provider, modelName, goalName)
+	}
+	return modelName, model, provider, nil
+}
+
+// judgeGoals asks each pending judged goal's judge whether the transcript meets its
+// criteria, completing goals whose confidence reaches their threshold.
+// Judge failures are logged and the goal is judged again next turn.
+func (s *Simulation) judgeGoals(ctx context.Context, turn int) {
+	if len(s.goalJudges) == 0 {
+		return
+	}
+
+	world := s.World.Snapshot()
+	transcript := judgeTranscript(world.GetRecentMessages(judgeTranscriptSize))
+
+	goalNames := make([]string, 0, len(s.goalJudges))
+	for goalName := range s.goalJudges {
+		goalNames = append(goalNames, goalName)
+	}
+	sort.Strings(goalNames)
+
+	for _, goalName := range goalNames {
+		goal, ok := world.Goals[goalName]
+		if !ok || goal.Status != mcpsim.GoalPending {
+			continue
+		}
+
+		judge := s.goalJudges[goalName]
+		judgment, err := judge.evaluate(ctx, goal, transcript)
+		if err != nil {
+			slog.Warn("goal judge failed", "goal", goalName, "model", judge.model, "error", err)
+			continue
+		}
+		slog.Info("goal judged", "goal", goalName, "confidence", judgment.Confidence, "assessment", judgment.Assessment)
+
+		var completed bool
+		s.World.Update(func(w *mcpsim.WorldState) error {
+			if g, ok := w.Goals[goalName]; ok {
+				completed = g.RecordJudgment(turn, judgment.Confidence, judgment.Assessment)
+			}
+			return nil
+		})
+		if completed {
+			s.currentGoalCompletions = append(s.currentGoalCompletions, chronicle.GoalCompletion{
+				GoalName:    goalName,
+				Status:      string(mcpsim.GoalCompleted),
+				Solution:    judgment.Assessment,
+				JudgedBy:    judge.model,
+				Confidence:  judgment.Confidence,
+				CompletedAt: turn,
+			})
+		}
+	}
+}
+
+// judgeTranscript formats conversation messages for the judge.
+// Internal monologue is left out; the judge sees only what happened in the scene.
+func judgeTranscript(messages []mcpsim.ConversationMessage) []string {
+	lines := make([]string, 0, len(messages))
+	for _, msg := range messages {
+		if msg.Content == "" || msg.Type == mcpsim.MessageTypeMonologue {
+			continue
+		}
+		if msg.Type == mcpsim.MessageTypeAction {
+			lines = append(lines, fmt.Sprintf("%s *%s*", msg.AgentName, msg.Content))
+		} else {
+			lines = append(lines, fmt.Sprintf("%s: %s", msg.AgentName, msg.Content))
+		}
+	}
+	return lines
+}
+
+// evaluate asks the judge model for a verdict on the goal.
+func (j *goalJudge) evaluate(ctx context.Context, goal *mcpsim.InteractiveGoal, transcript []string) (Judgment, error) {
+	prompt, err := buildGoalJudgePrompt(goal, transcript)
+	if err != nil {
+		return Judgment{}, err
+	}
+
+	resp, err := j.client.Chat(ctx, ChatRequest{
+		Messages: []Message{{Role: "user", Content: prompt}},
+	})
+	if err != nil {
+		return Judgment{}, fmt.Errorf("judge call failed: %w", err)
+	}
+	return parseJudgment(resp.Message)
+}
+
+// judgmentPattern extracts the JSON object from the judge's reply.
+var judgmentPattern = regexp.MustCompile(`(?s)\{.*\}`)
+
+// parseJudgment reads a judge reply, tolerating text around the JSON object.
+// Confidence is clamped to 0.0-1.0.
+func parseJudgment(reply string) (Judgment, error) {
+	match := judgmentPattern.FindString(reply)
+	if match == "" {
+		return Judgment{}, fmt.Errorf("judge reply contained no JSON: %q", reply)
+	}
+
+	var judgment Judgment
+	if err := json.Unmarshal([]byte(match), &judgment); err != nil {
+		return Judgment{}, fmt.Errorf("invalid judge reply %q: %w", match, err)
+	}
+	judgment.Confidence = min(max(judgment.Confidence, 0), 1)
+	return judgment, nil
+}
+
+// buildGoalJudgePrompt renders the goal judge prompt template.
+func buildGoalJudgePrompt(goal *mcpsim.InteractiveGoal, transcript []string) (string, error) {
+	promptTemplate, err := prompts.GetPrompt("goal_judge")
+	if err != nil {
+		return "", fmt.Errorf("failed to load goal judge prompt: %w", err)
+	}
+
+	tmpl, err := template.New("goal_judge").Parse(promptTemplate)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse template: %w", err)
+	}
+
+	data := struct {
+		Goal       string
+		Criteria   []string
+		Transcript []string
+	}{
+		Goal:       goal.Description,
+		Criteria:   goal.Criteria,
+		Transcript: transcript,
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to execute template: %w", err)
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,71 @@
+package simulations
+
+import (
+	"context"
+	"testing"
+
+	mcpsim "github.com/poiesic/wonda/internal/mcp/simulation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseJudgment(t *testing.T) {
+	t.Run("reads JSON surrounded by text", func(t *testing.T) {
+		judgment, err := parseJudgment("Here is my verdict:\n{\"confidence\": 0.85, \"assessment\": \"Bob has calmed down\"}\nDone.")
+		require.NoError(t, err)
+		assert.Equal(t, 0.85, judgment.Confidence)
+		assert.Equal(t, "Bob has calmed down", judgment.Assessment)
+	})
+
+	t.Run("clamps confidence", func(t *testing.T) {
+		judgment, err := parseJudgment(`{"confidence": 7, "assessment": "yes"}`)
+		require.NoError(t, err)
+		assert.Equal(t, 1.0, judgment.Confidence)
+	})
+
+	t.Run("rejects replies without JSON", func(t *testing.T) {
+		_, err := parseJudgment("probably")
+		assert.Error(t, err)
+	})
+}
+
+func TestJudgeGoals(t *testing.T) {
+	newSim := func(reply string) *Simulation {
+		world := mcpsim.NewWorldState("Cafe", "")
+		goal := mcpsim.NewInteractiveGoal("calm_bob", "Calm Bob down", "judged", 1)
+		goal.Criteria = []string{"Bob stops shouting"}
+		goal.Threshold = 0.8
+		world.AddGoal(goal)
+		world.AddMessage("Bob", "Fine. I'm sorry I yelled.", "", mcpsim.MessageTypeDialogue)
+
+		return &Simulation{
+			World: world,
+			goalJudges: map[string]*goalJudge{
+				"calm_bob": {client: &fakeClient{response: ChatResponse{Message: reply}}, model: "judge"},
+			},
+		}
+	}
+
+	t.Run("completes goal when confidence reaches threshold", func(t *testing.T) {
+		sim := newSim(`{"confidence": 0.9, "assessment": "Bob apologized"}`)
+		sim.judgeGoals(context.Background(), 3)
+
+		goal := sim.World.Snapshot().Goals["calm_bob"]
+		assert.Equal(t, mcpsim.GoalCompleted, goal.Status)
+		assert.Equal(t, 3, goal.CompletedAt)
+		require.Len(t, sim.currentGoalCompletions, 1)
+		assert.Equal(t, "judge", sim.currentGoalCompletions[0].JudgedBy)
+		assert.Equal(t, "Bob apologized", sim.currentGoalCompletions[0].Solution)
+	})
+
+	t.Run("records progress below threshold", func(t *testing.T) {
+		sim := newSim(`{"confidence": 0.4, "assessment": "Bob is still tense"}`)
+		sim.judgeGoals(context.Background(), 3)
+
+		goal := sim.World.Snapshot().Goals["calm_bob"]
+		assert.Equal(t, mcpsim.GoalPending, goal.Status)
+		assert.Equal(t, 0.4, goal.Confidence)
+		assert.Equal(t, "Bob is still tense", goal.Assessment)
+		assert.Empty(t, sim.currentGoalCompletions)
+	})
+}
@@ -0,0 +1,154 @@
+package simulations
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/poiesic/wonda/internal/config"
+	"github.com/poiesic/wonda/internal/guardrails"
+	"github.com/poiesic/wonda/internal/scenarios"
+)
+
+// guardedArguments lists the tool arguments that carry agent output into the world.
+// Text in these arguments is checked by guardrails before the tool is executed.
+var guardedArguments = map[string][]string{
+	"speak":              {"message", "rationale"},
+	"whisper":            {"message"},
+	"narrate_action":     {"action"},
+	"internal_monologue": {"thought"},
+	"propose_solution":   {"solution", "comment"},
+	"counter_proposal":   {"solution", "comment"},
+	"vote_on_proposal":   {"comment"},
+}
+
+// guardedText is a piece of flagged agent output and where it came from.
+type guardedText struct {
+	toolIndex int    // Index into ChatResponse.ToolCalls, or -1 for the response message
+	argument  string // Tool argument name (empty for the response message)
+	review    guardrails.Review
+}
+
+// newAgentGuard builds the guard for one agent from the scenario's guardrails.
+// Moderation is only added when the agent's provider has it enabled.
+// Returns nil when no filters apply to the agent.
+func newAgentGuard(cfg *scenarios.GuardrailsConfig, shared []guardrails.Filter, provider *config.Provider) (*guardrails.Guard, error) {
+	filters := append([]guardrails.Filter{}, shared...)
+	if cfg.Moderation {
+		if provider.Moderation {
+			moderation, err := guardrails.NewModerationFilter(provider)
+			if err != nil {
+				return nil, err
+			}
+			filters = append(filters, moderation)
+		} else {
+			slog.Warn("guardrail moderation requested but provider has no moderation endpoint", "provider", provider.Name)
+		}
+	}
+	if len(filters) == 0 {
+		return nil, nil
+	}
+
+	maxRegenerations := 0
+	if cfg.MaxRegenerations != nil {
+		maxRegenerations = *cfg.MaxRegenerations
+	}
+	return guardrails.NewGuard(cfg.Action, maxRegenerations, filters...)
+}
+
+// applyGuardrails checks the output in a response against the agent's guard
+// and applies the guard's policy: redact the flagged content, regenerate the
+// response, or halt with guardrails.ErrHalted.
+// Regeneration falls back to redaction once the attempts are used up.
+func (a *Agent) applyGuardrails(ctx context.Context, req ChatRequest, response ChatResponse) (ChatResponse, error) {
+	for attempt := 0; ; attempt++ {
+		flagged, err := a.reviewResponse(ctx, response)
+		if err != nil {
+			return ChatResponse{}, err
+		}
+		if len(flagged) == 0 {
+			return response, nil
+		}
+
+		reasons := make([]string, len(flagged))
+		for i, f := range flagged {
+			reasons[i] = f.review.Reasons()
+		}
+		reason := strings.Join(reasons, "; ")
+		slog.Warn("guardrail flagged agent output", "agent", a.Name, "action", a.Guard.Action(), "attempt", attempt, "reasons", reason)
+
+		switch {
+		case a.Guard.Action() == guardrails.ActionHalt:
+			return ChatResponse{}, fmt.Errorf("%w: agent %s: %s", guardrails.ErrHalted, a.Name, reason)
+		case a.Guard.Action() == guardrails.ActionRegenerate && attempt < a.Guard.MaxRegenerations():
+			// Ask again, telling the model why the previous response was rejected
+			retry := req
+			retry.Messages = append(append([]Message{}, req.Messages...), Message{
+				Role:    "user",
+				Content: fmt.Sprintf("Your previous response was blocked by the content policy (%s). Respond again, in character, without that content.", reason),
+			})
+			response, err = a.Client.Chat(ctx, retry)
+			if err != nil {
+				return ChatResponse{}, fmt.Errorf("LLM call failed during regeneration: %w", err)
+			}
+		default:
+			return redactResponse(response, flagged), nil
+		}
+	}
+}
+
+// reviewResponse checks the response message and guarded tool arguments,
+// returning only the pieces that were flagged.
+func (a *Agent) reviewResponse(ctx context.Context, response ChatResponse) ([]guardedText, error) {
+	var flagged []guardedText
+
+	review, err := a.Guard.Check(ctx, response.Message)
+	if err != nil {
+		return nil, err
+	}
+	if review.Flagged() {
+		flagged = append(flagged, guardedText{toolIndex: -1, review: review})
+	}
+
+	for i, tc := range response.ToolCalls {
+		for _, argument := range guardedArguments[tc.Name] {
+			text, ok := tc.Arguments[argument].(string)
+			if !ok {
+				continue
+			}
+			review, err := a.Guard.Check(ctx, text)
+			if err != nil {
+				return nil, err
+			}
+			if review.Flagged() {
+				flagged = append(flagged, guardedText{toolIndex: i, argument: argument, review: review})
+			}
+		}
+	}
+
+	return flagged, nil
+}
+
+// redactResponse returns a copy of response with flagged text redacted.
+// Tool call arguments are copied so shared maps (e.g. ensemble candidates) are untouched.
+func redactResponse(response ChatResponse, flagged []guardedText) ChatResponse {
+	redacted := response
+	redacted.ToolCalls = append([]ToolCall{}, response.ToolCalls...)
+
+	for _, f := range flagged {
+		if f.toolIndex < 0 {
+			redacted.Message = f.review.Redacted()
+			continue
+		}
+		tc := &redacted.ToolCalls[f.toolIndex]
+		args := make(map[string]interface{}, len(tc.Arguments))
+		for k, v := range tc.Arguments {
+			args[k] = v
+		}
+		args[f.argument] = f.review.Redacted()
+		tc.Arguments = args
+	}
+
+	return redacted
+}
@@ -0,0 +1,89 @@
+package simulations
+
+import (
+	"context"
+
+	"github.com/poiesic/wonda/internal/chronicle"
+)
+
+// TurnStartFunc is called at the start of each turn, before any agent acts.
+type TurnStartFunc func(ctx context.Context, turn int)
+
+// AgentActionFunc is called for each event an agent produces (dialogue, action,
+// monologue, proposal or vote comment) once it has been captured for the chronicle.
+type AgentActionFunc func(ctx context.Context, turn int, event chronicle.Event)
+
+// PartialUtteranceFunc is called as an agent's utterance streams in, with
+// everything said so far. The complete utterance follows as an AgentActionFunc event.
+type PartialUtteranceFunc func(ctx context.Context, turn int, agentName, text string)
+
+// GoalCompleteFunc is called when a goal completes or fails.
+type GoalCompleteFunc func(ctx context.Context, turn int, completion chronicle.GoalCompletion)
+
+// hooks holds callbacks registered by a host application embedding wonda.
+type hooks struct {
+	turnStart    []TurnStartFunc
+	agentAction  []AgentActionFunc
+	partial      []PartialUtteranceFunc
+	goalComplete []GoalCompleteFunc
+
+	// Number of current-turn events and completions already delivered
+	notifiedEvents      int
+	notifiedCompletions int
+}
+
+// OnTurnStart registers a callback run at the start of every turn.
+// Callbacks run synchronously on the simulation loop, in registration order.
+func (s *Simulation) OnTurnStart(fn TurnStartFunc) {
+	s.hooks.turnStart = append(s.hooks.turnStart, fn)
+}
+
+// OnAgentAction registers a callback run for every event an agent produces.
+// Callbacks run synchronously on the simulation loop, in registration order.
+func (s *Simulation) OnAgentAction(fn AgentActionFunc) {
+	s.hooks.agentAction = append(s.hooks.agentAction, fn)
+}
+
+// OnPartialUtterance registers a callback run as agent utterances stream in.
+// It is only called when the simulation streams (see Simulation.Stream).
+// Callbacks run synchronously on the simulation loop, in registration order.
+func (s *Simulation) OnPartialUtterance(fn PartialUtteranceFunc) {
+	s.hooks.partial = append(s.hooks.partial, fn)
+}
+
+// OnGoalComplete registers a callback run whenever a goal completes or fails.
+// Callbacks run synchronously on the simulation loop, in registration order.
+func (s *Simulation) OnGoalComplete(fn GoalCompleteFunc) {
+	s.hooks.goalComplete = append(s.hooks.goalComplete, fn)
+}
+
+// notifyTurnStart runs the turn start callbacks.
+func (s *Simulation) notifyTurnStart(ctx context.Context, turn int) {
+	for _, fn := range s.hooks.turnStart {
+		fn(ctx, turn)
+	}
+}
+
+// notifyPartial runs the partial utterance callbacks.
+func (s *Simulation) notifyPartial(ctx context.Context, turn int, agentName, text string) {
+	for _, fn := range s.hooks.partial {
+		fn(ctx, turn, agentName, text)
+	}
+}
+
+// notifyCaptured delivers events and goal completions captured since the last call.
+// Events are delivered after they are complete (including ensemble candidates).
+func (s *Simulation) notifyCaptured(ctx context.Context, turn int) {
+	for ; s.hooks.notifiedEvents < len(s.currentTurnEvents); s.hooks.notifiedEvents++ {
+		event := s.currentTurnEvents[s.hooks.notifiedEvents]
+		for _, fn := range s.hooks.agentAction {
+			fn(ctx, turn, event)
+		}
+	}
+	for ; s.hooks.notifiedCompletions < len(s.currentGoalCompletions); s.hooks.notifiedCompletions++ {
+		completion := s.currentGoalCompletions[s.hooks.notifiedCompletions]
+		for _, fn := range s.hooks.goalComplete {
+			fn(ctx, turn, completion)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package simulations
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/poiesic/wonda/internal/chronicle"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHooks(t *testing.T) {
+	ctx := context.Background()
+	sim := &Simulation{}
+
+	// Every callback appends to one log, so the order across kinds is kept
+	var delivered []string
+	sim.OnTurnStart(func(ctx context.Context, turn int) {
+		delivered = append(delivered, fmt.Sprintf("turn %d", turn))
+	})
+	for _, hook := range []string{"first", "second"} {
+		sim.OnAgentAction(func(ctx context.Context, turn int, event chronicle.Event) {
+			delivered = append(delivered, fmt.Sprintf("%s hook: turn %d %s %s %q", hook, turn, event.AgentName, event.Type, event.Dialogue))
+		})
+	}
+	sim.OnGoalComplete(func(ctx context.Context, turn int, completion chronicle.GoalCompletion) {
+		delivered = append(delivered, fmt.Sprintf("goal: turn %d %s %s", turn, completion.GoalName, completion.Status))
+	})
+
+	sim.notifyTurnStart(ctx, 1)
+	sim.currentTurnEvents = append(sim.currentTurnEvents,
+		chronicle.Event{AgentName: "Ada", Type: "dialogue", Dialogue: "Let's settle this."},
+		chronicle.Event{AgentName: "Basil", Type: "action", Dialogue: "nods"},
+	)
+	sim.notifyCaptured(ctx, 1)
+
+	// Only what was captured since the last delivery is delivered
+	sim.notifyCaptured(ctx, 1)
+	sim.currentTurnEvents = append(sim.currentTurnEvents, chronicle.Event{AgentName: "Basil", Type: "vote", Dialogue: "Fine by me."})
+	sim.currentGoalCompletions = append(sim.currentGoalCompletions, chronicle.GoalCompletion{GoalName: "venue", Status: "completed"})
+	sim.notifyCaptured(ctx, 1)
+
+	assert.Equal(t, []string{
+		"turn 1",
+		`first hook: turn 1 Ada dialogue "Let's settle this."`,
+		`second hook: turn 1 Ada dialogue "Let's settle this."`,
+		`first hook: turn 1 Basil action "nods"`,
+		`second hook: turn 1 Basil action "nods"`,
+		`first hook: turn 1 Basil vote "Fine by me."`,
+		`second hook: turn 1 Basil vote "Fine by me."`,
+		"goal: turn 1 venue completed",
+	}, delivered)
+}
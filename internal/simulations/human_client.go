@@ -0,0 +1,378 @@
+package simulations
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/poiesic/wonda/internal/scenarios"
+)
+
+// humanToolIterations is how many commands a person gets in one turn, far
+// more than a model's tool budget since looking around takes a few.
+const humanToolIterations = 50
+
+// HumanConsole is the terminal people play human-controlled agents from.
+// Lines are read in the background, so a prompt gives up when the run is
+// cancelled or times out instead of waiting on input forever.
+type HumanConsole struct {
+	in  io.Reader
+	out io.Writer
+
+	start sync.Once
+	lines chan string
+	err   error // Why input ended; read once lines is closed
+}
+
+// NewHumanConsole creates a console reading commands from in and writing
+// prompts and tool results to out.
+func NewHumanConsole(in io.Reader, out io.Writer) *HumanConsole {
+	return &HumanConsole{in: in, out: out, lines: make(chan string)}
+}
+
+// readLine prompts for and reads one line of input.
+func (c *HumanConsole) readLine(ctx context.Context, prompt string) (string, error) {
+	c.start.Do(func() {
+		go func() {
+			scanner := bufio.NewScanner(c.in)
+			for scanner.Scan() {
+				c.lines <- scanner.Text()
+			}
+			c.err = scanner.Err()
+			if c.err == nil {
+				c.err = io.EOF
+			}
+			close(c.lines)
+		}()
+	})
+
+	fmt.Fprint(c.out, prompt)
+	select {
+	case <-ctx.Done():
+		fmt.Fprintln(c.out)
+		return "", ctx.Err()
+	case line, ok := <-c.lines:
+		if !ok {
+			return "", fmt.Errorf("no more input: %w", c.err)
+		}
+		return strings.TrimSpace(line), nil
+	}
+}
+
+// printf writes to the console.
+func (c *HumanConsole) printf(format string, args ...interface{}) {
+	fmt.Fprintf(c.out, format, args...)
+}
+
+// humanHelp lists the commands a person can give, with the tool each needs.
+var humanHelp = []struct {
+	tool, command, description string
+}{
+	{"", "<text>", "say something"},
+	{"perceive", "/look", "see who's here and what was said"},
+	{"whisper", "/whisper <agent> <text>", "say something only that agent hears"},
+	{"move_to", "/go <location>", "walk to a neighbouring location"},
+	{"inspect_object", "/inspect <object>", "take a close look at an object"},
+	{"pick_up", "/take <object>", "pick up an object near you"},
+	{"give", "/give <object> <agent>", "hand an object you hold to someone"},
+	{"list_goals", "/goals", "list the goals"},
+	{"view_goal", "/goal <goal>", "see a goal's proposals and votes"},
+	{"propose_solution", "/propose <goal> <solution>", "propose a solution (you'll be asked what to say)"},
+	{"vote_on_proposal", "/vote <goal> <proposal-id> yes|no", "vote on a proposal (you'll be asked what to say)"},
+	{"rank_proposals", "/rank <goal> <proposal-id>...", "rank a goal's proposals, favorite first (you'll be asked what to say)"},
+	{"pass_turn", "/pass [reason]", "hold back this turn"},
+	{"", "/help", "show these commands"},
+}
+
+// HumanClient lets a person play an agent. It shows them what the agent
+// would see and turns their commands into the same tool calls a model would
+// make, so a human takes part in deliberation and voting alongside the models.
+type HumanClient struct {
+	name    string
+	console *HumanConsole
+}
+
+// NewHumanClient creates a client that asks a person at the console to play agentName.
+func NewHumanClient(agentName string, console *HumanConsole) *HumanClient {
+	return &HumanClient{name: agentName, console: console}
+}
+
+// Chat implements Client.
+func (c *HumanClient) Chat(ctx context.Context, req ChatRequest) (ChatResponse, error) {
+	tools := make(map[string]bool)
+	for _, tool := range req.Tools {
+		if fn, ok := tool["function"].(map[string]interface{}); ok {
+			if name, ok := fn["name"].(string); ok {
+				tools[name] = true
+			}
+		}
+	}
+
+	situation, results, started := humanTurn(req.Messages)
+	if !started {
+		c.console.printf("\n── %s, it's your turn ──\n", c.name)
+		// Start by looking around, as a model would
+		var calls []ToolCall
+		switch {
+		case tools["perceive"]:
+			calls = append(calls, ToolCall{ID: "human_0", Name: "perceive", Arguments: map[string]interface{}{}})
+		case tools["rank_proposals"]:
+			// The proposals to rank are only listed in the situation
+			c.console.printf("%s\n", situation)
+		case tools["vote_on_proposal"]:
+			for i, match := range mockVotingGoalPattern.FindAllStringSubmatch(situation, -1) {
+				calls = append(calls, ToolCall{
+					ID:        fmt.Sprintf("human_%d", i),
+					Name:      "view_goal",
+					Arguments: map[string]interface{}{"goal_name": match[1]},
+				})
+			}
+		}
+		if len(calls) > 0 {
+			return ChatResponse{ToolCalls: calls, FinishReason: "tool_calls"}, nil
+		}
+	}
+	for _, result := range results {
+		c.console.printf("%s\n", formatHumanResult(result))
+	}
+
+	for {
+		line, err := c.console.readLine(ctx, c.name+"> ")
+		if err != nil {
+			return ChatResponse{}, err
+		}
+		if line == "" {
+			continue
+		}
+		if !strings.HasPrefix(line, "/") {
+			return ChatResponse{Message: line, FinishReason: "stop"}, nil
+		}
+
+		command, rest, _ := strings.Cut(line, " ")
+		tool, ok := humanCommands[command]
+		if !ok {
+			c.printHelp(tools)
+			continue
+		}
+		if !tools[tool] {
+			c.console.printf("You can't do that right now.\n")
+			continue
+		}
+		arguments, err := c.arguments(ctx, command, strings.TrimSpace(rest))
+		if err != nil {
+			return ChatResponse{}, err
+		}
+		if arguments == nil {
+			continue
+		}
+		return ChatResponse{
+			ToolCalls:    []ToolCall{{ID: "human_0", Name: tool, Arguments: arguments}},
+			FinishReason: "tool_calls",
+		}, nil
+	}
+}
+
+// humanCommands maps each slash command to the tool it calls.
+var humanCommands = map[string]string{
+	"/look":    "perceive",
+	"/whisper": "whisper",
+	"/go":      "move_to",
+	"/inspect": "inspect_object",
+	"/take":    "pick_up",
+	"/give":    "give",
+	"/goals":   "list_goals",
+	"/goal":    "view_goal",
+	"/propose": "propose_solution",
+	"/vote":    "vote_on_proposal",
+	"/rank":    "rank_proposals",
+	"/pass":    "pass_turn",
+}
+
+// arguments builds the tool arguments for a command, asking what the agent
+// says as they propose or vote. They are nil if the command was malformed,
+// once its usage has been shown.
+func (c *HumanClient) arguments(ctx context.Context, command, rest string) (map[string]interface{}, error) {
+	args := strings.Fields(rest)
+	usage := func(text string) (map[string]interface{}, error) {
+		c.console.printf("Usage: %s\n", text)
+		return nil, nil
+	}
+	comment := func() (string, error) {
+		for {
+			line, err := c.console.readLine(ctx, "say> ")
+			if err != nil || line != "" {
+				return line, err
+			}
+		}
+	}
+
+	switch command {
+	case "/whisper":
+		if len(args) < 2 {
+			return usage("/whisper <agent> <text>")
+		}
+		return map[string]interface{}{
+			"recipient": args[0],
+			"message":   strings.TrimSpace(strings.TrimPrefix(rest, args[0])),
+		}, nil
+	case "/go":
+		if len(args) != 1 {
+			return usage("/go <location>")
+		}
+		return map[string]interface{}{"location": args[0]}, nil
+	case "/inspect", "/take":
+		if len(args) != 1 {
+			return usage(command + " <object>")
+		}
+		return map[string]interface{}{"object": args[0]}, nil
+	case "/give":
+		if len(args) != 2 {
+			return usage("/give <object> <agent>")
+		}
+		return map[string]interface{}{"object": args[0], "recipient": args[1]}, nil
+	case "/goal":
+		if len(args) != 1 {
+			return usage("/goal <goal>")
+		}
+		return map[string]interface{}{"goal_name": args[0]}, nil
+	case "/propose":
+		if len(args) < 2 {
+			return usage("/propose <goal> <solution>")
+		}
+		said, err := comment()
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{
+			"goal_name": args[0],
+			"solution":  strings.TrimSpace(strings.TrimPrefix(rest, args[0])),
+			"comment":   said,
+		}, nil
+	case "/vote":
+		if len(args) != 3 || (args[2] != "yes" && args[2] != "no") {
+			return usage("/vote <goal> <proposal-id> yes|no")
+		}
+		said, err := comment()
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{
+			"goal_name":   args[0],
+			"proposal_id": args[1],
+			"vote":        args[2],
+			"comment":     said,
+		}, nil
+	case "/rank":
+		if len(args) < 2 {
+			return usage("/rank <goal> <proposal-id>...")
+		}
+		said, err := comment()
+		if err != nil {
+			return nil, err
+		}
+		ranking := make([]interface{}, 0, len(args)-1)
+		for _, id := range args[1:] {
+			ranking = append(ranking, id)
+		}
+		return map[string]interface{}{
+			"goal_name": args[0],
+			"ranking":   ranking,
+			"comment":   said,
+		}, nil
+	case "/pass":
+		return map[string]interface{}{"reason": rest}, nil
+	default:
+		return map[string]interface{}{}, nil
+	}
+}
+
+// printHelp lists the commands available right now.
+func (c *HumanClient) printHelp(tools map[string]bool) {
+	c.console.printf("Commands:\n")
+	for _, help := range humanHelp {
+		if help.tool == "" || tools[help.tool] {
+			c.console.printf("  %-34s %s\n", help.command, help.description)
+		}
+	}
+}
+
+// humanTurn returns the situation the agent was given this turn, the tool
+// results that came back since the person's last command, and whether the
+// person has already done anything this turn.
+func humanTurn(messages []Message) (string, []string, bool) {
+	var results []string
+	i := len(messages) - 1
+	for ; i >= 0 && messages[i].Role == "tool"; i-- {
+		results = append([]string{messages[i].Content}, results...)
+	}
+	started := i >= 0 && messages[i].Role == "assistant"
+	for ; i >= 0; i-- {
+		if messages[i].Role == "user" {
+			return messages[i].Content, results, started
+		}
+	}
+	return "", results, started
+}
+
+// formatHumanResult renders a tool result for a person: what was said
+// recently when looking around, otherwise the tool's JSON as it came back.
+func formatHumanResult(result string) string {
+	header, body, _ := strings.Cut(result, "\n")
+	if !strings.HasPrefix(header, "Tool 'perceive' returned") {
+		return result
+	}
+
+	var perception struct {
+		Location       string   `json:"location"`
+		Position       string   `json:"your_position"`
+		NearbyAgents   []string `json:"nearby_agents"`
+		Exits          []string `json:"exits"`
+		Inventory      []string `json:"inventory"`
+		ObjectsHere    []string `json:"objects_here"`
+		RecentMessages []string `json:"recent_messages"`
+		AmbientEvents  []string `json:"ambient_events"`
+		Whispers       []string `json:"whispers"`
+	}
+	if err := json.Unmarshal([]byte(body), &perception); err != nil {
+		return result
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "You're at %s with %s.\n", perception.Location, strings.Join(perception.NearbyAgents, ", "))
+	if len(perception.Exits) > 0 {
+		fmt.Fprintf(&b, "You're in %s; from here you can /go to %s.\n", perception.Position, strings.Join(perception.Exits, ", "))
+	}
+	for _, object := range perception.ObjectsHere {
+		fmt.Fprintf(&b, "  Here: %s\n", object)
+	}
+	for _, object := range perception.Inventory {
+		fmt.Fprintf(&b, "  In hand: %s\n", object)
+	}
+	for _, event := range perception.AmbientEvents {
+		fmt.Fprintf(&b, "  * %s\n", event)
+	}
+	if len(perception.RecentMessages) == 0 {
+		b.WriteString("Nobody has said anything yet.")
+	} else {
+		b.WriteString("Recently:")
+		for _, msg := range perception.RecentMessages {
+			fmt.Fprintf(&b, "\n  %s", msg)
+		}
+	}
+	for _, msg := range perception.Whispers {
+		fmt.Fprintf(&b, "\n  🤫 %s", msg)
+	}
+	return b.String()
+}
+
+// newHumanAgent creates an agent played by a person at the simulation's console.
+func (s *Simulation) newHumanAgent(agentName string, character *scenarios.Character) *Agent {
+	agent := NewAgent(agentName, character, NewHumanClient(agentName, s.Console), scenarios.ControllerHuman, scenarios.ControllerHuman)
+	agent.Human = true
+	agent.MaxToolIterations = humanToolIterations
+	agent.Prompts = s.prompts
+	return agent
+}
@@ -0,0 +1,103 @@
+package simulations
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHumanClientDeliberation(t *testing.T) {
+	ctx := context.Background()
+	var out bytes.Buffer
+	input := "/goals\n/vote dinner p1 yes\n/propose dinner Luigi's place\n\nLet's go to Luigi's!\nSee you there.\n"
+	client := NewHumanClient("alice", NewHumanConsole(strings.NewReader(input), &out))
+	tools := mockTools("perceive", "list_goals", "speak", "propose_solution")
+	messages := []Message{{Role: "user", Content: "It's your turn."}}
+
+	// The turn starts by looking around
+	resp, err := client.Chat(ctx, ChatRequest{Messages: messages, Tools: tools})
+	require.NoError(t, err)
+	require.Len(t, resp.ToolCalls, 1)
+	assert.Equal(t, "perceive", resp.ToolCalls[0].Name)
+	assert.Contains(t, out.String(), "alice, it's your turn")
+
+	messages = append(messages,
+		Message{Role: "assistant"},
+		Message{Role: "tool", Content: "Tool 'perceive' returned:\n{\"location\": \"the park\", \"nearby_agents\": [\"bob\"], \"recent_messages\": [\"bob: I'm hungry.\"]}"})
+	resp, err = client.Chat(ctx, ChatRequest{Messages: messages, Tools: tools})
+	require.NoError(t, err)
+	require.Len(t, resp.ToolCalls, 1)
+	assert.Equal(t, "list_goals", resp.ToolCalls[0].Name)
+	assert.Contains(t, out.String(), "You're at the park with bob.")
+	assert.Contains(t, out.String(), "bob: I'm hungry.")
+
+	// Voting isn't possible while deliberating; the proposal asks what to say
+	messages = append(messages,
+		Message{Role: "assistant"},
+		Message{Role: "tool", Content: "Tool 'list_goals' returned:\n{\"goals\": []}"})
+	resp, err = client.Chat(ctx, ChatRequest{Messages: messages, Tools: tools})
+	require.NoError(t, err)
+	assert.Contains(t, out.String(), "You can't do that right now.")
+	require.Len(t, resp.ToolCalls, 1)
+	call := resp.ToolCalls[0]
+	assert.Equal(t, "propose_solution", call.Name)
+	assert.Equal(t, "dinner", call.Arguments["goal_name"])
+	assert.Equal(t, "Luigi's place", call.Arguments["solution"])
+	assert.Equal(t, "Let's go to Luigi's!", call.Arguments["comment"])
+
+	// Plain text is said out loud
+	resp, err = client.Chat(ctx, ChatRequest{Messages: append(messages, Message{Role: "assistant"}), Tools: tools})
+	require.NoError(t, err)
+	assert.Empty(t, resp.ToolCalls)
+	assert.Equal(t, "See you there.", resp.Message)
+}
+
+func TestHumanClientVoting(t *testing.T) {
+	var out bytes.Buffer
+	client := NewHumanClient("bob", NewHumanConsole(strings.NewReader("/help\n/vote dinner p1 no\nToo far.\n"), &out))
+	tools := mockTools("view_goal", "vote_on_proposal", "pass_turn")
+	messages := []Message{{Role: "user", Content: "VOTING PHASE\nGoal 'dinner' has 1 pending proposal(s)"}}
+
+	resp, err := client.Chat(context.Background(), ChatRequest{Messages: messages, Tools: tools})
+	require.NoError(t, err)
+	require.Len(t, resp.ToolCalls, 1)
+	assert.Equal(t, "view_goal", resp.ToolCalls[0].Name)
+
+	messages = append(messages,
+		Message{Role: "assistant"},
+		Message{Role: "tool", Content: "Tool 'view_goal' returned:\n{\"name\": \"dinner\"}"})
+	resp, err = client.Chat(context.Background(), ChatRequest{Messages: messages, Tools: tools})
+	require.NoError(t, err)
+	require.Len(t, resp.ToolCalls, 1)
+	assert.Equal(t, map[string]interface{}{
+		"goal_name":   "dinner",
+		"proposal_id": "p1",
+		"vote":        "no",
+		"comment":     "Too far.",
+	}, resp.ToolCalls[0].Arguments)
+
+	// Help lists only what can be done now
+	assert.Contains(t, out.String(), `Tool 'view_goal' returned:`)
+	assert.Contains(t, out.String(), "/vote <goal> <proposal-id> yes|no")
+	assert.NotContains(t, out.String(), "/propose")
+}
+
+func TestHumanConsoleGivesUp(t *testing.T) {
+	reader, writer := io.Pipe()
+	defer writer.Close()
+	console := NewHumanConsole(reader, io.Discard)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := console.readLine(ctx, "> ")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	_, err = NewHumanConsole(strings.NewReader(""), io.Discard).readLine(context.Background(), "> ")
+	assert.ErrorIs(t, err, io.EOF)
+}
@@ -0,0 +1,260 @@
+package simulations
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/poiesic/wonda/internal/config"
+)
+
+const (
+	// llamaHealthInterval is how often a starting llama-server is polled until its model is loaded.
+	llamaHealthInterval = 500 * time.Millisecond
+	// llamaStopTimeout is how long a llama-server gets to exit after being interrupted before it is killed.
+	llamaStopTimeout = 10 * time.Second
+	// llamaOutputLimit bounds the server output kept for error messages.
+	llamaOutputLimit = 4096
+)
+
+// llamaServer is a llama-server process serving one local GGUF model for the run.
+type llamaServer struct {
+	cmd     *exec.Cmd
+	baseURL string
+	output  *outputTail
+	exited  chan struct{} // Closed once the process has exited
+	waitErr error         // Why the process exited; read after exited is closed
+}
+
+// llamaProviderName is the provider registered for a model served by llama-server.
+func llamaProviderName(modelKey string) string {
+	return "llama-" + modelKey
+}
+
+// registerLlamaServers registers a provider for each model that runs on a
+// managed llama-server. The servers aren't started until a model is used.
+func registerLlamaServers(models map[string]*config.Model, providers *config.Providers) error {
+	for key, model := range models {
+		if model.LlamaServer == nil {
+			continue
+		}
+		if model.Name == "" {
+			model.Name = strings.TrimSuffix(filepath.Base(model.LlamaServer.ModelPath), ".gguf")
+		}
+		if err := model.Validate(); err != nil {
+			return fmt.Errorf("model %s: %w", key, err)
+		}
+
+		name := llamaProviderName(key)
+		model.Provider = name
+		providers.Providers[name] = &config.Provider{Name: name, Type: config.ProviderTypeOpenAI}
+	}
+	return nil
+}
+
+// ensureLlamaServer starts the llama-server for a model that runs on one, if it
+// isn't running yet, and points the model's provider at it.
+func (s *Simulation) ensureLlamaServer(ctx context.Context, provider *config.Provider, model *config.Model) error {
+	if model.LlamaServer == nil {
+		return nil
+	}
+
+	s.llamaMu.Lock()
+	defer s.llamaMu.Unlock()
+	if _, ok := s.llamaServers[provider.Name]; ok {
+		return nil
+	}
+
+	server, err := startLlamaServer(ctx, model)
+	if err != nil {
+		return fmt.Errorf("failed to start llama-server for model %s: %w", model.Name, err)
+	}
+	if s.llamaServers == nil {
+		s.llamaServers = make(map[string]*llamaServer)
+	}
+	s.llamaServers[provider.Name] = server
+	provider.BaseURL = server.baseURL
+	return nil
+}
+
+// Close stops the llama-server processes started for the run and closes the
+// memory store. Call it once the run is over; it is safe to call more than once.
+func (s *Simulation) Close() {
+	if s.MemoryStore != nil {
+		if err := s.MemoryStore.Close(); err != nil {
+			slog.Warn("failed to close memory store", "error", err)
+		}
+	}
+
+	s.llamaMu.Lock()
+	defer s.llamaMu.Unlock()
+	for name, server := range s.llamaServers {
+		server.stop()
+		slog.Info("llama-server stopped", "provider", name)
+		delete(s.llamaServers, name)
+	}
+}
+
+// startLlamaServer launches llama-server for a model on a free local port and
+// waits until the model is loaded.
+func startLlamaServer(ctx context.Context, model *config.Model) (*llamaServer, error) {
+	settings := model.LlamaServer
+	port, err := freePort()
+	if err != nil {
+		return nil, fmt.Errorf("failed to find a free port: %w", err)
+	}
+
+	binary := settings.Binary
+	if binary == "" {
+		binary = "llama-server"
+	}
+	// The alias makes the server list the model under its configured name
+	args := []string{
+		"--model", expandHome(settings.ModelPath),
+		"--alias", model.Name,
+		"--host", "127.0.0.1",
+		"--port", strconv.Itoa(port),
+	}
+	if settings.ContextSize > 0 {
+		args = append(args, "--ctx-size", strconv.Itoa(settings.ContextSize))
+	}
+	if settings.GPULayers != nil {
+		args = append(args, "--n-gpu-layers", strconv.Itoa(*settings.GPULayers))
+	}
+	args = append(args, settings.Args...)
+
+	// The server lives as long as the run, not the context it was started under
+	cmd := exec.Command(binary, args...)
+	output := &outputTail{limit: llamaOutputLimit}
+	cmd.Stdout = output
+	cmd.Stderr = output
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	server := &llamaServer{
+		cmd:     cmd,
+		baseURL: fmt.Sprintf("http://127.0.0.1:%d/v1", port),
+		output:  output,
+		exited:  make(chan struct{}),
+	}
+	go func() {
+		server.waitErr = cmd.Wait()
+		close(server.exited)
+	}()
+	slog.Info("llama-server starting", "model", model.Name, "path", settings.ModelPath, "port", port, "pid", cmd.Process.Pid)
+
+	if err := server.waitReady(ctx, settings.Timeout()); err != nil {
+		server.stop()
+		return nil, err
+	}
+	slog.Info("llama-server ready", "model", model.Name, "url", server.baseURL)
+	return server, nil
+}
+
+// waitReady polls the server's health endpoint until the model is loaded,
+// failing early if the process exits.
+func (l *llamaServer) waitReady(ctx context.Context, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	healthURL := strings.TrimSuffix(l.baseURL, "/v1") + "/health"
+	ticker := time.NewTicker(llamaHealthInterval)
+	defer ticker.Stop()
+	for {
+		// The server answers 503 while it loads the model
+		req, err := http.NewRequestWithContext(ctx, "GET", healthURL, nil)
+		if err != nil {
+			return err
+		}
+		if resp, err := http.DefaultClient.Do(req); err == nil {
+			resp.Body.Close()
+			if resp.StatusCode == http.StatusOK {
+				return nil
+			}
+		}
+
+		select {
+		case <-l.exited:
+			return fmt.Errorf("llama-server exited (%v): %s", l.waitErr, l.output)
+		case <-ctx.Done():
+			return fmt.Errorf("llama-server wasn't ready within %s: %s", timeout, l.output)
+		case <-ticker.C:
+		}
+	}
+}
+
+// stop interrupts the server, killing it if it doesn't exit in time.
+func (l *llamaServer) stop() {
+	select {
+	case <-l.exited:
+		return
+	default:
+	}
+
+	if err := l.cmd.Process.Signal(os.Interrupt); err == nil {
+		select {
+		case <-l.exited:
+			return
+		case <-time.After(llamaStopTimeout):
+		}
+	}
+	l.cmd.Process.Kill()
+	<-l.exited
+}
+
+// freePort returns a local TCP port that is free right now.
+func freePort() (int, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, err
+	}
+	defer listener.Close()
+	return listener.Addr().(*net.TCPAddr).Port, nil
+}
+
+// expandHome replaces a leading ~ with the user's home directory.
+func expandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, strings.TrimPrefix(path, "~"))
+}
+
+// outputTail keeps the end of a process's output, for error messages.
+type outputTail struct {
+	mu    sync.Mutex
+	limit int
+	data  []byte
+}
+
+// Write implements io.Writer.
+func (o *outputTail) Write(p []byte) (int, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.data = append(o.data, p...)
+	if len(o.data) > o.limit {
+		o.data = o.data[len(o.data)-o.limit:]
+	}
+	return len(p), nil
+}
+
+// String returns the kept output, trimmed.
+func (o *outputTail) String() string {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return strings.TrimSpace(string(o.data))
+}
@@ -0,0 +1,116 @@
+package simulations
+
+import (
+	"context"
+	"flag"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/poiesic/wonda/internal/config"
+)
+
+// TestFakeLlamaServer isn't a test: the llama-server tests run the test binary
+// through a script as a stand-in for llama-server, and this serves its health
+// and model endpoints until interrupted.
+func TestFakeLlamaServer(t *testing.T) {
+	if os.Getenv("WONDA_FAKE_LLAMA_SERVER") == "" {
+		t.Skip("only runs as a fake llama-server")
+	}
+
+	var port, alias string
+	args := flag.Args()
+	for i := 0; i+1 < len(args); i++ {
+		switch args[i] {
+		case "--port":
+			port = args[i+1]
+		case "--alias":
+			alias = args[i+1]
+		}
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"status":"ok"}`))
+	})
+	mux.HandleFunc("/v1/models", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data":[{"id":"` + alias + `"}]}`))
+	})
+	server := &http.Server{Addr: "127.0.0.1:" + port, Handler: mux}
+	go server.ListenAndServe()
+
+	interrupted := make(chan os.Signal, 1)
+	signal.Notify(interrupted, os.Interrupt)
+	<-interrupted
+	server.Close()
+}
+
+func TestLlamaServer(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake llama-server is a shell script")
+	}
+
+	// A script standing in for llama-server, passing its arguments to TestFakeLlamaServer
+	binary := filepath.Join(t.TempDir(), "llama-server")
+	script := "#!/bin/sh\nexec \"" + os.Args[0] + "\" -test.run='^TestFakeLlamaServer$' -- \"$@\"\n"
+	require.NoError(t, os.WriteFile(binary, []byte(script), 0755))
+	t.Setenv("WONDA_FAKE_LLAMA_SERVER", "1")
+
+	models := map[string]*config.Model{
+		"local": {
+			LlamaServer: &config.LlamaServerConfig{ModelPath: "/models/qwen2.5-7b-instruct.gguf", Binary: binary},
+		},
+	}
+	providers := config.NewProviders()
+	require.NoError(t, registerLlamaServers(models, providers))
+
+	model := models["local"]
+	assert.Equal(t, "qwen2.5-7b-instruct", model.Name)
+	assert.Equal(t, "llama-local", model.Provider)
+	provider := providers.Providers["llama-local"]
+	require.NotNil(t, provider)
+
+	sim := &Simulation{}
+	defer sim.Close()
+	require.NoError(t, sim.ensureLlamaServer(context.Background(), provider, model))
+	assert.NotEmpty(t, provider.BaseURL)
+
+	// The server lists the model under its configured name
+	client, err := NewClient(provider, model)
+	require.NoError(t, err)
+	require.NoError(t, client.(ModelChecker).CheckModel(context.Background()))
+
+	server := sim.llamaServers["llama-local"]
+	sim.Close()
+	assert.Empty(t, sim.llamaServers)
+	select {
+	case <-server.exited:
+	default:
+		t.Fatal("llama-server still running after Close")
+	}
+}
+
+func TestLlamaServerStartupFailure(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake llama-server is a shell script")
+	}
+
+	binary := filepath.Join(t.TempDir(), "llama-server")
+	require.NoError(t, os.WriteFile(binary, []byte("#!/bin/sh\necho 'failed to load model' >&2\nexit 1\n"), 0755))
+
+	model := &config.Model{
+		Name:        "broken",
+		Provider:    "llama-broken",
+		LlamaServer: &config.LlamaServerConfig{ModelPath: "/models/broken.gguf", Binary: binary},
+	}
+	sim := &Simulation{}
+	err := sim.ensureLlamaServer(context.Background(), &config.Provider{Name: "llama-broken"}, model)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to load model")
+}
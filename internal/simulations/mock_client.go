@@ -0,0 +1,407 @@
+package simulations
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pelletier/go-toml/v2"
+)
+
+// MockScript configures the MockClient that answers for every LLM in a dry
+// run. Agents without a script speak, propose and vote with canned responses
+// picked at random; the same seed always picks the same ones.
+type MockScript struct {
+	Seed   int64                       `toml:"seed" json:"seed,omitempty"`     // Optional: seed for the canned responses (default 0)
+	Clock  time.Time                   `toml:"clock" json:"clock,omitzero"`    // Optional: fixed time for the chronicle's name and timestamps (default: the system clock)
+	Agents map[string]*MockAgentScript `toml:"agents" json:"agents,omitempty"` // Optional: scripted responses by agent name
+}
+
+// MockAgentScript scripts what one agent says, proposes and votes in a dry run.
+type MockAgentScript struct {
+	Say     []string `toml:"say" json:"say,omitempty"`         // Lines spoken in order, starting over at the end
+	Propose []string `toml:"propose" json:"propose,omitempty"` // Solutions proposed in order, one per deliberation, until they run out
+	Vote    string   `toml:"vote" json:"vote,omitempty"`       // "yes", "no" or "random" (default "random")
+}
+
+// LoadMockScript loads a dry run script from a TOML file.
+func LoadMockScript(path string) (*MockScript, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var script MockScript
+	if err := toml.Unmarshal(data, &script); err != nil {
+		return nil, fmt.Errorf("invalid dry run script: %w", err)
+	}
+	if err := script.validate(); err != nil {
+		return nil, err
+	}
+	return &script, nil
+}
+
+// validate checks the scripted votes.
+func (s *MockScript) validate() error {
+	for name, agent := range s.Agents {
+		switch agent.Vote {
+		case "", "yes", "no", "random":
+		default:
+			return fmt.Errorf("dry run script for %s: vote must be yes, no or random (got %q)", name, agent.Vote)
+		}
+	}
+	return nil
+}
+
+// mockYesRate is how often a randomly voting mock agent votes yes.
+const mockYesRate = 0.7
+
+// mockProposeRate is how often a mock agent without a script proposes a
+// solution instead of only talking.
+const mockProposeRate = 0.5
+
+// Canned responses for mock agents without a script.
+var (
+	mockLines = []string{
+		"I think we should hear everyone out before deciding.",
+		"That could work, but I have some reservations.",
+		"Let me think about what matters most here.",
+		"I'm open to suggestions.",
+		"We keep going around in circles.",
+		"That sounds reasonable to me.",
+		"I'm not convinced yet.",
+		"Can we find something we can all live with?",
+	}
+	mockSolutions = []string{
+		"Go with the first option",
+		"Go with the second option",
+		"Split the difference",
+		"Do what we did last time",
+	}
+)
+
+// mockToolResultPattern reads the tool name from a tool result message.
+var mockToolResultPattern = regexp.MustCompile(`^Tool '([^']+)' (returned|error)`)
+
+// mockVotingGoalPattern finds the goals with pending proposals in the voting prompt.
+var mockVotingGoalPattern = regexp.MustCompile(`Goal '([^']+)' has \d+ pending proposal`)
+
+// mockEvents are what the dry-run director makes happen.
+var mockEvents = []string{
+	"A door slams somewhere nearby.",
+	"The lights flicker.",
+	"Someone's phone starts ringing.",
+}
+
+// mockRankingPattern finds the goals and proposals a ranked-choice prompt lists.
+var mockRankingPattern = regexp.MustCompile(`(?m)^Goal '([^']+)' - |^- (proposal_\d+): `)
+
+// MockClient is a deterministic stand-in for an LLM, for dry runs that
+// exercise the turn loop, tools and chronicle without API keys or cost. As an
+// agent it lists the goals and proposes, or looks at the pending proposals and
+// votes on them, through the same tools a model would call; as a goal judge or
+// post-mortem judge it answers with well-formed JSON, as an ensemble judge it
+// picks the first candidate, and as the memory compactor it writes a
+// placeholder summary.
+type MockClient struct {
+	caller string
+	script *MockAgentScript // Nil for canned responses
+
+	mu       sync.Mutex
+	rand     *rand.Rand
+	said     int // Scripted lines spoken
+	proposed int // Scripted solutions proposed
+}
+
+// NewMockClient creates a mock LLM answering for caller: an agent name or a
+// usage caller such as the goal judge. Each caller's responses are seeded
+// separately, so they don't depend on the order agents take their turns.
+func NewMockClient(caller string, script *MockScript) *MockClient {
+	hash := fnv.New64a()
+	hash.Write([]byte(caller))
+	client := &MockClient{
+		caller: caller,
+		rand:   rand.New(rand.NewSource(script.Seed ^ int64(hash.Sum64()))),
+	}
+	if script.Agents != nil {
+		client.script = script.Agents[caller]
+	}
+	return client
+}
+
+// Chat implements Client.
+func (c *MockClient) Chat(ctx context.Context, req ChatRequest) (ChatResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return ChatResponse{}, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	switch c.caller {
+	case usageCallerJudge:
+		return c.json(map[string]interface{}{
+			"confidence": float64(c.rand.Intn(11)) / 10,
+			"assessment": "Dry run: no model judged this goal.",
+		})
+	case usageCallerPostmortem:
+		return c.json(map[string]interface{}{
+			"summary": "Dry run: no model reviewed this run.",
+		})
+	case mockCallerEnsembleJudge:
+		return ChatResponse{Message: "1", FinishReason: "stop"}, nil
+	case usageCallerDirector:
+		direction := map[string]interface{}{"events": []string{}}
+		if c.rand.Intn(3) == 0 {
+			direction["events"] = []string{mockEvents[c.rand.Intn(len(mockEvents))]}
+		}
+		return c.json(direction)
+	case usageCallerCompaction:
+		return ChatResponse{Message: "Dry run: no model summarized these memories.", FinishReason: "stop"}, nil
+	}
+
+	tools := make(map[string]bool)
+	for _, tool := range req.Tools {
+		if fn, ok := tool["function"].(map[string]interface{}); ok {
+			if name, ok := fn["name"].(string); ok {
+				tools[name] = true
+			}
+		}
+	}
+	situation, results := mockTurn(req.Messages)
+	switch {
+	case tools["rank_proposals"]:
+		return c.rank(situation, results), nil
+	case tools["vote_on_proposal"]:
+		return c.vote(situation, results), nil
+	case tools["propose_solution"]:
+		return c.deliberate(results), nil
+	default:
+		return ChatResponse{Message: c.line()}, nil
+	}
+}
+
+// deliberate lists the goals, then proposes a solution to one the agent
+// decides or just talks. A failed proposal (an allocation goal, say) is
+// followed by talk.
+func (c *MockClient) deliberate(results map[string][]string) ChatResponse {
+	if _, proposed := results["propose_solution"]; proposed {
+		return ChatResponse{Message: c.line()}
+	}
+	listed, ok := results["list_goals"]
+	if !ok {
+		return mockCall("list_goals", nil)
+	}
+
+	var goals struct {
+		Goals []struct {
+			Name       string `json:"name"`
+			Status     string `json:"status"`
+			YouDecide  bool   `json:"you_decide"`
+			Individual bool   `json:"individual"`
+		} `json:"goals"`
+	}
+	json.Unmarshal([]byte(listed[0]), &goals)
+	var open []string
+	for _, goal := range goals.Goals {
+		if goal.Status == "pending" && goal.YouDecide && !goal.Individual {
+			open = append(open, goal.Name)
+		}
+	}
+	if len(open) == 0 {
+		return ChatResponse{Message: c.line()}
+	}
+
+	solution := c.solution()
+	if solution == "" {
+		return ChatResponse{Message: c.line()}
+	}
+	return mockCall("propose_solution", map[string]interface{}{
+		"goal_name": open[c.rand.Intn(len(open))],
+		"solution":  solution,
+		"comment":   c.line(),
+	})
+}
+
+// vote looks at the goals the voting prompt lists, then votes on every
+// pending proposal the agent hasn't voted on yet.
+func (c *MockClient) vote(situation string, results map[string][]string) ChatResponse {
+	if _, voted := results["vote_on_proposal"]; voted {
+		return ChatResponse{Message: c.line()}
+	}
+	viewed, ok := results["view_goal"]
+	if !ok {
+		var calls []ToolCall
+		for i, match := range mockVotingGoalPattern.FindAllStringSubmatch(situation, -1) {
+			calls = append(calls, ToolCall{
+				ID:        fmt.Sprintf("mock_%d", i),
+				Name:      "view_goal",
+				Arguments: map[string]interface{}{"goal_name": match[1]},
+			})
+		}
+		if len(calls) == 0 {
+			return ChatResponse{Message: c.line()}
+		}
+		return ChatResponse{ToolCalls: calls, FinishReason: "tool_calls"}
+	}
+
+	var calls []ToolCall
+	for _, result := range viewed {
+		var goal struct {
+			Name    string `json:"name"`
+			Pending []struct {
+				ID    string            `json:"id"`
+				Votes map[string]string `json:"votes"`
+			} `json:"pending_proposals"`
+		}
+		json.Unmarshal([]byte(result), &goal)
+		for _, proposal := range goal.Pending {
+			if _, voted := proposal.Votes[c.caller]; voted {
+				continue
+			}
+			calls = append(calls, ToolCall{
+				ID:   fmt.Sprintf("mock_%d", len(calls)),
+				Name: "vote_on_proposal",
+				Arguments: map[string]interface{}{
+					"goal_name":   goal.Name,
+					"proposal_id": proposal.ID,
+					"vote":        c.choice(),
+					"comment":     c.line(),
+				},
+			})
+		}
+	}
+	if len(calls) == 0 {
+		return ChatResponse{Message: c.line()}
+	}
+	return ChatResponse{ToolCalls: calls, FinishReason: "tool_calls"}
+}
+
+// rank ranks the proposals of every goal the ranked-choice prompt lists in a
+// random order.
+func (c *MockClient) rank(situation string, results map[string][]string) ChatResponse {
+	if _, ranked := results["rank_proposals"]; ranked {
+		return ChatResponse{Message: c.line()}
+	}
+
+	var goals []string
+	rankings := make(map[string][]interface{})
+	for _, match := range mockRankingPattern.FindAllStringSubmatch(situation, -1) {
+		if match[1] != "" {
+			goals = append(goals, match[1])
+		} else if len(goals) > 0 {
+			goal := goals[len(goals)-1]
+			rankings[goal] = append(rankings[goal], match[2])
+		}
+	}
+
+	var calls []ToolCall
+	for _, goal := range goals {
+		ranking := rankings[goal]
+		if len(ranking) == 0 {
+			continue
+		}
+		c.rand.Shuffle(len(ranking), func(i, j int) { ranking[i], ranking[j] = ranking[j], ranking[i] })
+		calls = append(calls, ToolCall{
+			ID:   fmt.Sprintf("mock_%d", len(calls)),
+			Name: "rank_proposals",
+			Arguments: map[string]interface{}{
+				"goal_name": goal,
+				"ranking":   ranking,
+				"comment":   c.line(),
+			},
+		})
+	}
+	if len(calls) == 0 {
+		return ChatResponse{Message: c.line()}
+	}
+	return ChatResponse{ToolCalls: calls, FinishReason: "tool_calls"}
+}
+
+// line returns the next scripted line, or a canned one.
+func (c *MockClient) line() string {
+	if c.script != nil && len(c.script.Say) > 0 {
+		line := c.script.Say[c.said%len(c.script.Say)]
+		c.said++
+		return line
+	}
+	return mockLines[c.rand.Intn(len(mockLines))]
+}
+
+// solution returns the next scripted solution, or sometimes a canned one;
+// "" means the agent only talks this time.
+func (c *MockClient) solution() string {
+	if c.script != nil && (len(c.script.Propose) > 0 || len(c.script.Say) > 0) {
+		if c.proposed >= len(c.script.Propose) {
+			return ""
+		}
+		solution := c.script.Propose[c.proposed]
+		c.proposed++
+		return solution
+	}
+	if c.rand.Float64() >= mockProposeRate {
+		return ""
+	}
+	return mockSolutions[c.rand.Intn(len(mockSolutions))]
+}
+
+// choice returns the agent's vote: scripted, or yes most of the time.
+func (c *MockClient) choice() string {
+	if c.script != nil && (c.script.Vote == "yes" || c.script.Vote == "no") {
+		return c.script.Vote
+	}
+	if c.rand.Float64() < mockYesRate {
+		return "yes"
+	}
+	return "no"
+}
+
+// json answers with a JSON object.
+func (c *MockClient) json(reply map[string]interface{}) (ChatResponse, error) {
+	data, err := json.Marshal(reply)
+	if err != nil {
+		return ChatResponse{}, err
+	}
+	return ChatResponse{Message: string(data), FinishReason: "stop"}, nil
+}
+
+// mockCall answers with a single tool call.
+func mockCall(name string, arguments map[string]interface{}) ChatResponse {
+	if arguments == nil {
+		arguments = map[string]interface{}{}
+	}
+	return ChatResponse{
+		ToolCalls:    []ToolCall{{ID: "mock_0", Name: name, Arguments: arguments}},
+		FinishReason: "tool_calls",
+	}
+}
+
+// mockTurn returns the situation an agent was given this turn (the last user
+// message) and the results of the tools it has called since, by tool name.
+// Results are the JSON the tool returned, or its error message.
+func mockTurn(messages []Message) (string, map[string][]string) {
+	results := make(map[string][]string)
+	for i := len(messages) - 1; i >= 0; i-- {
+		msg := messages[i]
+		switch msg.Role {
+		case "user":
+			return msg.Content, results
+		case "tool":
+			match := mockToolResultPattern.FindStringSubmatch(msg.Content)
+			if match == nil {
+				continue
+			}
+			result := msg.Content
+			if _, body, ok := strings.Cut(msg.Content, "\n"); ok && match[2] == "returned" {
+				result = body
+			}
+			results[match[1]] = append(results[match[1]], result)
+		}
+	}
+	return "", results
+}
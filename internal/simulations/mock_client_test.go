@@ -0,0 +1,177 @@
+package simulations
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+	"github.com/poiesic/wonda/internal/chronicle"
+	"github.com/poiesic/wonda/internal/scenarios"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockTools returns tool definitions with the given names.
+func mockTools(names ...string) []map[string]interface{} {
+	tools := make([]map[string]interface{}, len(names))
+	for i, name := range names {
+		tools[i] = map[string]interface{}{
+			"type":     "function",
+			"function": map[string]interface{}{"name": name},
+		}
+	}
+	return tools
+}
+
+func TestMockClientDeliberation(t *testing.T) {
+	ctx := context.Background()
+	script := &MockScript{Agents: map[string]*MockAgentScript{
+		"alice": {Say: []string{"Hello."}, Propose: []string{"Pizza"}},
+	}}
+	client := NewMockClient("alice", script)
+	tools := mockTools("list_goals", "speak", "propose_solution")
+	messages := []Message{{Role: "system", Content: "You are Alice."}, {Role: "user", Content: "It's your turn."}}
+
+	resp, err := client.Chat(ctx, ChatRequest{Messages: messages, Tools: tools})
+	require.NoError(t, err)
+	require.Len(t, resp.ToolCalls, 1)
+	assert.Equal(t, "list_goals", resp.ToolCalls[0].Name)
+
+	messages = append(messages, Message{Role: "tool", Content: `Tool 'list_goals' returned:
+{"goals": [
+  {"name": "dinner", "status": "pending", "you_decide": true},
+  {"name": "own", "status": "pending", "you_decide": true, "individual": true},
+  {"name": "done", "status": "completed", "you_decide": true}
+]}`})
+	resp, err = client.Chat(ctx, ChatRequest{Messages: messages, Tools: tools})
+	require.NoError(t, err)
+	require.Len(t, resp.ToolCalls, 1)
+	assert.Equal(t, "propose_solution", resp.ToolCalls[0].Name)
+	assert.Equal(t, "dinner", resp.ToolCalls[0].Arguments["goal_name"])
+	assert.Equal(t, "Pizza", resp.ToolCalls[0].Arguments["solution"])
+	assert.Equal(t, "Hello.", resp.ToolCalls[0].Arguments["comment"])
+
+	// Once the proposals run out, the agent only talks
+	messages = append(messages, Message{Role: "user", Content: "Next turn."})
+	resp, err = client.Chat(ctx, ChatRequest{Messages: messages, Tools: tools})
+	require.NoError(t, err)
+	require.Len(t, resp.ToolCalls, 1)
+	assert.Equal(t, "list_goals", resp.ToolCalls[0].Name)
+	messages = append(messages, Message{Role: "tool", Content: "Tool 'list_goals' returned:\n{\"goals\": [{\"name\": \"dinner\", \"status\": \"pending\", \"you_decide\": true}]}"})
+	resp, err = client.Chat(ctx, ChatRequest{Messages: messages, Tools: tools})
+	require.NoError(t, err)
+	assert.Empty(t, resp.ToolCalls)
+	assert.Equal(t, "Hello.", resp.Message)
+}
+
+func TestMockClientVoting(t *testing.T) {
+	ctx := context.Background()
+	script := &MockScript{Agents: map[string]*MockAgentScript{"bob": {Vote: "no"}}}
+	client := NewMockClient("bob", script)
+	tools := mockTools("view_goal", "vote_on_proposal")
+	messages := []Message{{Role: "user", Content: "VOTING PHASE\nGoal 'dinner' has 2 pending proposal(s)"}}
+
+	resp, err := client.Chat(ctx, ChatRequest{Messages: messages, Tools: tools})
+	require.NoError(t, err)
+	require.Len(t, resp.ToolCalls, 1)
+	assert.Equal(t, "view_goal", resp.ToolCalls[0].Name)
+	assert.Equal(t, "dinner", resp.ToolCalls[0].Arguments["goal_name"])
+
+	messages = append(messages, Message{Role: "tool", Content: `Tool 'view_goal' returned:
+{"name": "dinner", "pending_proposals": [
+  {"id": "p1", "votes": {"alice": "yes"}},
+  {"id": "p2", "votes": {"bob": "yes"}}
+]}`})
+	resp, err = client.Chat(ctx, ChatRequest{Messages: messages, Tools: tools})
+	require.NoError(t, err)
+	require.Len(t, resp.ToolCalls, 1, "bob already voted on his own proposal")
+	call := resp.ToolCalls[0]
+	assert.Equal(t, "vote_on_proposal", call.Name)
+	assert.Equal(t, "p1", call.Arguments["proposal_id"])
+	assert.Equal(t, "no", call.Arguments["vote"])
+	assert.NotEmpty(t, call.Arguments["comment"])
+}
+
+func TestMockClientJudges(t *testing.T) {
+	ctx := context.Background()
+	script := &MockScript{}
+
+	resp, err := NewMockClient(usageCallerJudge, script).Chat(ctx, ChatRequest{Messages: []Message{{Role: "user", Content: "Judge this."}}})
+	require.NoError(t, err)
+	_, err = parseJudgment(resp.Message)
+	assert.NoError(t, err)
+
+	resp, err = NewMockClient(usageCallerPostmortem, script).Chat(ctx, ChatRequest{Messages: []Message{{Role: "user", Content: "Review this."}}})
+	require.NoError(t, err)
+	_, err = parsePostMortem(resp.Message)
+	assert.NoError(t, err)
+}
+
+func TestMockClientDeterministic(t *testing.T) {
+	ctx := context.Background()
+	req := ChatRequest{Messages: []Message{{Role: "user", Content: "Say something."}}}
+	replies := func(seed int64) []string {
+		client := NewMockClient("carol", &MockScript{Seed: seed})
+		var lines []string
+		for range 10 {
+			resp, err := client.Chat(ctx, req)
+			require.NoError(t, err)
+			lines = append(lines, resp.Message)
+		}
+		return lines
+	}
+	assert.Equal(t, replies(7), replies(7))
+	assert.NotEqual(t, replies(7), replies(8))
+}
+
+func TestLoadMockScript(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "script.toml")
+	require.NoError(t, os.WriteFile(path, []byte(`seed = 42
+clock = 2025-03-14T18:30:00Z
+
+[agents.alice]
+say = ["Hi", "Bye"]
+propose = ["Pizza"]
+vote = "yes"
+`), 0644))
+
+	script, err := LoadMockScript(path)
+	require.NoError(t, err)
+	assert.Equal(t, int64(42), script.Seed)
+	assert.Equal(t, time.Date(2025, 3, 14, 18, 30, 0, 0, time.UTC), script.Clock)
+	assert.Equal(t, []string{"Hi", "Bye"}, script.Agents["alice"].Say)
+	assert.Equal(t, []string{"Pizza"}, script.Agents["alice"].Propose)
+	assert.Equal(t, "yes", script.Agents["alice"].Vote)
+
+	require.NoError(t, os.WriteFile(path, []byte("[agents.alice]\nvote = \"maybe\"\n"), 0644))
+	_, err = LoadMockScript(path)
+	assert.ErrorContains(t, err, "vote must be yes, no or random")
+}
+
+func TestChronicleFilenameClock(t *testing.T) {
+	sim := &Simulation{
+		ID:        ulid.MustParse("01JPAB0000AAAAAAAAAAAAAAAA"),
+		Scenario:  &scenarios.Scenario{Basics: &scenarios.BasicScenarioInformation{Name: "Dinner Plans"}},
+		OutputDir: "runs",
+		Clock:     chronicle.FixedClock{Time: time.Date(2025, 3, 14, 18, 30, 0, 0, time.UTC)},
+	}
+	assert.Equal(t, "runs/chronicle-dinner-plans-20250314-183000-01jpab.jsonl", sim.getChronicleFilename())
+}
+
+func TestMockClientRanking(t *testing.T) {
+	client := NewMockClient("carol", &MockScript{Seed: 7})
+	tools := mockTools("view_goal", "rank_proposals", "pass_turn")
+	situation := "LAST CHANCE\n\nGoal 'dinner' - Pick a restaurant\n- proposal_1: The diner (proposed by alice)\n- proposal_2: The cafe (proposed by bob)"
+
+	resp, err := client.Chat(context.Background(), ChatRequest{Messages: []Message{{Role: "user", Content: situation}}, Tools: tools})
+	require.NoError(t, err)
+	require.Len(t, resp.ToolCalls, 1)
+	call := resp.ToolCalls[0]
+	assert.Equal(t, "rank_proposals", call.Name)
+	assert.Equal(t, "dinner", call.Arguments["goal_name"])
+	assert.ElementsMatch(t, []interface{}{"proposal_1", "proposal_2"}, call.Arguments["ranking"])
+}
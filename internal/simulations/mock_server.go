@@ -0,0 +1,823 @@
+package simulations
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/pelletier/go-toml/v2"
+
+	"github.com/poiesic/wonda/internal/prompts"
+)
+
+// MockServerScript configures a MockServer. Requests no rule answers get the
+// same responses a dry run would, scripted per agent as in a MockScript.
+type MockServerScript struct {
+	MockScript
+	Models  []string   `toml:"models"`  // Optional: models the server lists (default ["mock"])
+	Latency string     `toml:"latency"` // Optional: delay before every response, e.g. "250ms"
+	Rules   []MockRule `toml:"rules"`   // Optional: canned responses; the first rule that applies answers
+}
+
+// MockRule is a canned response a MockServer gives to matching requests.
+// Reply and string arguments are Go templates over MockTemplateData.
+type MockRule struct {
+	Caller    string                 `toml:"caller"`    // Optional: only answer this agent, or "goal judge", "post-mortem", "director", "memory compaction" or "ensemble judge"
+	Match     string                 `toml:"match"`     // Optional: regular expression the prompt must match
+	Reply     string                 `toml:"reply"`     // Optional: text of the response
+	Tool      string                 `toml:"tool"`      // Optional: tool to call, once per turn
+	Arguments map[string]interface{} `toml:"arguments"` // Optional: arguments of the tool call
+	Status    int                    `toml:"status"`    // Optional: fail the request with this HTTP status instead of answering
+	Rate      *float64               `toml:"rate"`      // Optional: chance the rule applies when it matches (default 1)
+	Delay     string                 `toml:"delay"`     // Optional: extra delay before answering, e.g. "2s"
+
+	match *regexp.Regexp
+	delay time.Duration
+	count int // Times the rule has answered
+}
+
+// MockTemplateData is what a rule's reply and arguments are rendered with.
+type MockTemplateData struct {
+	Caller string   // Agent name, or which part of the simulation is asking
+	Model  string   // Model the request named
+	Prompt string   // The situation: the last user message
+	Match  []string // What the rule's pattern matched, then its submatches
+	Count  int      // Times the rule has answered, including this time
+}
+
+// mockCallerEnsembleJudge is the caller a MockServer answers ensemble judge requests as.
+const mockCallerEnsembleJudge = "ensemble judge"
+
+// mockAgentPattern reads the agent's name from the start of an agent prompt.
+var mockAgentPattern = regexp.MustCompile(`^You are ([^,\n]+),`)
+
+// mockServerCallers are the prompts of the parts of a simulation that aren't
+// agents, by the caller a MockServer answers them as.
+var mockServerCallers = map[string]string{
+	"goal_judge":        usageCallerJudge,
+	"postmortem":        usageCallerPostmortem,
+	"director":          usageCallerDirector,
+	"memory_compaction": usageCallerCompaction,
+	"ensemble_judge":    mockCallerEnsembleJudge,
+}
+
+// LoadMockServerScript loads a mock server script from a TOML file.
+func LoadMockServerScript(path string) (*MockServerScript, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var script MockServerScript
+	if err := toml.Unmarshal(data, &script); err != nil {
+		return nil, fmt.Errorf("invalid mock server script: %w", err)
+	}
+	if err := script.Validate(); err != nil {
+		return nil, err
+	}
+	return &script, nil
+}
+
+// Validate checks the script and prepares its rules.
+func (s *MockServerScript) Validate() error {
+	if err := s.MockScript.validate(); err != nil {
+		return err
+	}
+	if s.Latency != "" {
+		if _, err := time.ParseDuration(s.Latency); err != nil {
+			return fmt.Errorf("invalid latency: %w", err)
+		}
+	}
+	for i := range s.Rules {
+		if err := s.Rules[i].prepare(); err != nil {
+			return fmt.Errorf("rule %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+// prepare checks a rule and compiles its pattern.
+func (r *MockRule) prepare() error {
+	if r.Reply == "" && r.Tool == "" && r.Status == 0 {
+		return fmt.Errorf("needs a reply, a tool or a status")
+	}
+	if r.Status != 0 && (r.Status < 400 || r.Status > 599) {
+		return fmt.Errorf("status must be an HTTP error status, 400-599 (got %d)", r.Status)
+	}
+	if r.Rate != nil && (*r.Rate < 0 || *r.Rate > 1) {
+		return fmt.Errorf("rate must be between 0 and 1 (got %v)", *r.Rate)
+	}
+	if r.Match != "" {
+		match, err := regexp.Compile(r.Match)
+		if err != nil {
+			return fmt.Errorf("invalid match: %w", err)
+		}
+		r.match = match
+	}
+	if r.Delay != "" {
+		delay, err := time.ParseDuration(r.Delay)
+		if err != nil {
+			return fmt.Errorf("invalid delay: %w", err)
+		}
+		r.delay = delay
+	}
+	if _, err := template.New("mock").Parse(r.Reply); err != nil {
+		return fmt.Errorf("invalid reply: %w", err)
+	}
+	if err := checkMockTemplates(r.Arguments); err != nil {
+		return fmt.Errorf("invalid arguments: %w", err)
+	}
+	return nil
+}
+
+// checkMockTemplates checks that every string in a tool argument value parses as a template.
+func checkMockTemplates(value interface{}) error {
+	switch v := value.(type) {
+	case string:
+		_, err := template.New("mock").Parse(v)
+		return err
+	case []interface{}:
+		for _, item := range v {
+			if err := checkMockTemplates(item); err != nil {
+				return err
+			}
+		}
+	case map[string]interface{}:
+		for key, item := range v {
+			if err := checkMockTemplates(item); err != nil {
+				return fmt.Errorf("%s: %w", key, err)
+			}
+		}
+	}
+	return nil
+}
+
+// mockStatusError is a failure a MockServer answers with an HTTP error status.
+type mockStatusError struct {
+	status  int
+	message string
+}
+
+func (e *mockStatusError) Error() string {
+	return e.message
+}
+
+// MockServer serves OpenAI- and Anthropic-compatible chat endpoints that
+// answer like a dry run's MockClient, or as the script's rules say, so whole
+// runs, demos and CI jobs can go through the real provider clients without a
+// real provider. Each caller, recognized from its prompt, keeps its own mock
+// for the life of the server.
+type MockServer struct {
+	script  *MockServerScript
+	latency time.Duration
+	chaos   *ChaosConfig // Nil unless failures are injected
+	mux     *http.ServeMux
+
+	mu        sync.Mutex
+	clients   map[string]*MockClient // By caller
+	rand      *rand.Rand             // For rule rates
+	chaosRand *chaosRand
+	requests  int
+}
+
+// NewMockServer creates a mock provider server from a validated script. When
+// chaos is set, requests no rule answers also suffer its failures.
+func NewMockServer(script *MockServerScript, chaos *ChaosConfig) *MockServer {
+	server := &MockServer{
+		script:  script,
+		chaos:   chaos,
+		mux:     http.NewServeMux(),
+		clients: make(map[string]*MockClient),
+		rand:    rand.New(rand.NewSource(script.Seed)),
+	}
+	if script.Latency != "" {
+		server.latency, _ = time.ParseDuration(script.Latency)
+	}
+	if chaos != nil {
+		server.chaosRand = newChaosRand(chaos.Seed)
+	}
+
+	server.mux.HandleFunc("GET /v1/models", server.listModels)
+	server.mux.HandleFunc("GET /v1/models/{model}", server.showModel)
+	server.mux.HandleFunc("POST /v1/chat/completions", server.chatCompletions)
+	server.mux.HandleFunc("POST /v1/messages", server.messages)
+	return server
+}
+
+// ServeHTTP implements http.Handler.
+func (s *MockServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// models returns the models the server lists.
+func (s *MockServer) models() []string {
+	if len(s.script.Models) == 0 {
+		return []string{"mock"}
+	}
+	return s.script.Models
+}
+
+// listModels answers OpenAI's model list.
+func (s *MockServer) listModels(w http.ResponseWriter, r *http.Request) {
+	data := make([]map[string]interface{}, 0, len(s.models()))
+	for _, model := range s.models() {
+		data = append(data, map[string]interface{}{"id": model, "object": "model", "owned_by": "wonda"})
+	}
+	writeMockJSON(w, http.StatusOK, map[string]interface{}{"object": "list", "data": data})
+}
+
+// showModel answers Anthropic's model lookup.
+func (s *MockServer) showModel(w http.ResponseWriter, r *http.Request) {
+	model := r.PathValue("model")
+	for _, known := range s.models() {
+		if known == model {
+			writeMockJSON(w, http.StatusOK, map[string]interface{}{"id": model, "type": "model", "display_name": model})
+			return
+		}
+	}
+	writeAnthropicError(w, &mockStatusError{status: http.StatusNotFound, message: fmt.Sprintf("model %s not found", model)})
+}
+
+// chatCompletions answers an OpenAI chat completion request.
+func (s *MockServer) chatCompletions(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Model    string `json:"model"`
+		Messages []struct {
+			Role    string `json:"role"`
+			Content string `json:"content"`
+		} `json:"messages"`
+		Tools  []map[string]interface{} `json:"tools"`
+		Stream bool                     `json:"stream"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeOpenAIError(w, &mockStatusError{status: http.StatusBadRequest, message: fmt.Sprintf("invalid request: %v", err)})
+		return
+	}
+
+	req := ChatRequest{Model: body.Model, Tools: body.Tools}
+	for _, msg := range body.Messages {
+		req.Messages = append(req.Messages, Message{Role: msg.Role, Content: msg.Content})
+	}
+
+	resp, err := s.answer(r.Context(), req)
+	if err != nil {
+		writeOpenAIError(w, err)
+		return
+	}
+	id := fmt.Sprintf("chatcmpl-mock-%d", s.nextRequest())
+	if body.Stream {
+		writeOpenAIStream(w, id, body.Model, resp)
+		return
+	}
+	writeMockJSON(w, http.StatusOK, openAIResponse(id, body.Model, resp))
+}
+
+// messages answers an Anthropic Messages request. Tool results arrive as
+// user messages and are read back as the tool messages they were.
+func (s *MockServer) messages(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Model    string          `json:"model"`
+		System   json.RawMessage `json:"system"`
+		Messages []struct {
+			Role    string          `json:"role"`
+			Content json.RawMessage `json:"content"`
+		} `json:"messages"`
+		Tools []struct {
+			Name        string      `json:"name"`
+			Description string      `json:"description"`
+			InputSchema interface{} `json:"input_schema"`
+		} `json:"tools"`
+		Stream bool `json:"stream"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeAnthropicError(w, &mockStatusError{status: http.StatusBadRequest, message: fmt.Sprintf("invalid request: %v", err)})
+		return
+	}
+
+	req := ChatRequest{Model: body.Model}
+	if system := anthropicText(body.System); system != "" {
+		req.Messages = append(req.Messages, Message{Role: "system", Content: system})
+	}
+	for _, msg := range body.Messages {
+		content := anthropicText(msg.Content)
+		role := msg.Role
+		if role == "user" && mockToolResultPattern.MatchString(content) {
+			role = "tool"
+		}
+		req.Messages = append(req.Messages, Message{Role: role, Content: content})
+	}
+	for _, tool := range body.Tools {
+		req.Tools = append(req.Tools, map[string]interface{}{
+			"type": "function",
+			"function": map[string]interface{}{
+				"name":        tool.Name,
+				"description": tool.Description,
+				"parameters":  tool.InputSchema,
+			},
+		})
+	}
+
+	resp, err := s.answer(r.Context(), req)
+	if err != nil {
+		writeAnthropicError(w, err)
+		return
+	}
+	id := fmt.Sprintf("msg_mock_%d", s.nextRequest())
+	if body.Stream {
+		writeAnthropicStream(w, id, body.Model, resp)
+		return
+	}
+	writeMockJSON(w, http.StatusOK, anthropicResponse(id, body.Model, resp))
+}
+
+// answer produces the response to a request: from the first rule that
+// applies, or else from the caller's mock.
+func (s *MockServer) answer(ctx context.Context, req ChatRequest) (ChatResponse, error) {
+	caller := mockCaller(req.Messages)
+	situation, results := mockTurn(req.Messages)
+
+	rule, data := s.applicableRule(caller, req.Model, situation, results)
+	delay := s.latency
+	if rule != nil {
+		delay += rule.delay
+	}
+	if delay > 0 {
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return ChatResponse{}, ctx.Err()
+		}
+	}
+
+	var resp ChatResponse
+	var err error
+	if rule != nil {
+		slog.Debug("mock rule answering", "caller", caller, "match", rule.Match, "count", data.Count)
+		resp, err = rule.respond(data)
+	} else {
+		resp, err = s.client(caller, req.Model).Chat(ctx, req)
+	}
+	if err != nil {
+		return ChatResponse{}, err
+	}
+
+	for _, msg := range req.Messages {
+		resp.Usage.InputTokens += mockTokens(msg.Content)
+	}
+	resp.Usage.OutputTokens = mockTokens(resp.Message)
+	for _, call := range resp.ToolCalls {
+		arguments, _ := json.Marshal(call.Arguments)
+		resp.Usage.OutputTokens += mockTokens(call.Name + string(arguments))
+	}
+	return resp, nil
+}
+
+// applicableRule returns the first rule that applies to a request, with the
+// data to render it with, or nil if none does. A rule calling a tool doesn't
+// apply once the tool has been called this turn.
+func (s *MockServer) applicableRule(caller, model, situation string, results map[string][]string) (*MockRule, MockTemplateData) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i := range s.script.Rules {
+		rule := &s.script.Rules[i]
+		if rule.Caller != "" && rule.Caller != caller {
+			continue
+		}
+		if _, called := results[rule.Tool]; rule.Tool != "" && called {
+			continue
+		}
+		var match []string
+		if rule.match != nil {
+			if match = rule.match.FindStringSubmatch(situation); match == nil {
+				continue
+			}
+		}
+		if rule.Rate != nil && s.rand.Float64() >= *rule.Rate {
+			continue
+		}
+		rule.count++
+		return rule, MockTemplateData{Caller: caller, Model: model, Prompt: situation, Match: match, Count: rule.count}
+	}
+	return nil, MockTemplateData{}
+}
+
+// respond renders a rule's response.
+func (r *MockRule) respond(data MockTemplateData) (ChatResponse, error) {
+	if r.Status != 0 {
+		return ChatResponse{}, &mockStatusError{status: r.Status, message: "mock: injected error"}
+	}
+
+	reply, err := renderMockTemplate(r.Reply, data)
+	if err != nil {
+		return ChatResponse{}, err
+	}
+	if r.Tool == "" {
+		return ChatResponse{Message: reply, FinishReason: "stop"}, nil
+	}
+	arguments, err := renderMockArguments(r.Arguments, data)
+	if err != nil {
+		return ChatResponse{}, err
+	}
+	resp := mockCall(r.Tool, arguments)
+	resp.Message = reply
+	return resp, nil
+}
+
+// client returns the caller's mock, wrapped to inject failures in chaos mode.
+func (s *MockServer) client(caller, model string) Client {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	mock, ok := s.clients[caller]
+	if !ok {
+		mock = NewMockClient(caller, &s.script.MockScript)
+		s.clients[caller] = mock
+	}
+	if s.chaos == nil {
+		return mock
+	}
+	return &chaosClient{client: mock, model: model, config: s.chaos, rand: s.chaosRand}
+}
+
+// nextRequest numbers the server's responses.
+func (s *MockServer) nextRequest() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests++
+	return s.requests
+}
+
+// mockCaller recognizes who is asking from the first prompt of a request:
+// one of the parts of a simulation that aren't agents, or the agent the prompt
+// is written for. It returns "" when the prompt is neither.
+func mockCaller(messages []Message) string {
+	var prompt string
+	for _, msg := range messages {
+		if msg.Role == "user" {
+			prompt = msg.Content
+			break
+		}
+	}
+
+	for name, caller := range mockServerCallers {
+		promptTemplate, err := prompts.GetPrompt(name)
+		if err != nil {
+			continue
+		}
+		opening, _, _ := strings.Cut(promptTemplate, "{{")
+		if opening = strings.TrimSpace(opening); opening != "" && strings.HasPrefix(prompt, opening) {
+			return caller
+		}
+	}
+	if match := mockAgentPattern.FindStringSubmatch(prompt); match != nil {
+		return match[1]
+	}
+	return ""
+}
+
+// mockTokens roughly counts the tokens in text, at about four characters each.
+func mockTokens(text string) int {
+	if text == "" {
+		return 0
+	}
+	return max(1, len(text)/4)
+}
+
+// renderMockTemplate renders a rule template.
+func renderMockTemplate(text string, data MockTemplateData) (string, error) {
+	if text == "" {
+		return "", nil
+	}
+	tmpl, err := template.New("mock").Parse(text)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// renderMockArguments renders the string values of tool arguments as
+// templates, including those inside lists and tables.
+func renderMockArguments(arguments map[string]interface{}, data MockTemplateData) (map[string]interface{}, error) {
+	rendered := make(map[string]interface{}, len(arguments))
+	for key, value := range arguments {
+		value, err := renderMockValue(value, data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", key, err)
+		}
+		rendered[key] = value
+	}
+	return rendered, nil
+}
+
+// renderMockValue renders one argument value.
+func renderMockValue(value interface{}, data MockTemplateData) (interface{}, error) {
+	switch v := value.(type) {
+	case string:
+		return renderMockTemplate(v, data)
+	case []interface{}:
+		rendered := make([]interface{}, len(v))
+		for i, item := range v {
+			item, err := renderMockValue(item, data)
+			if err != nil {
+				return nil, err
+			}
+			rendered[i] = item
+		}
+		return rendered, nil
+	case map[string]interface{}:
+		return renderMockArguments(v, data)
+	default:
+		return value, nil
+	}
+}
+
+// anthropicText reads Anthropic content: a string, or a list of blocks whose
+// text is joined.
+func anthropicText(raw json.RawMessage) string {
+	if len(raw) == 0 {
+		return ""
+	}
+	var text string
+	if err := json.Unmarshal(raw, &text); err == nil {
+		return text
+	}
+	var blocks []struct {
+		Type    string `json:"type"`
+		Text    string `json:"text"`
+		Content string `json:"content"` // Tool results
+	}
+	if err := json.Unmarshal(raw, &blocks); err != nil {
+		return ""
+	}
+	var parts []string
+	for _, block := range blocks {
+		switch block.Type {
+		case "text":
+			parts = append(parts, block.Text)
+		case "tool_result":
+			parts = append(parts, block.Content)
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
+// openAIToolCalls renders tool calls in OpenAI's format.
+func openAIToolCalls(calls []ToolCall) []map[string]interface{} {
+	rendered := make([]map[string]interface{}, len(calls))
+	for i, call := range calls {
+		arguments, _ := json.Marshal(call.Arguments)
+		rendered[i] = map[string]interface{}{
+			"index": i,
+			"id":    call.ID,
+			"type":  "function",
+			"function": map[string]interface{}{
+				"name":      call.Name,
+				"arguments": string(arguments),
+			},
+		}
+	}
+	return rendered
+}
+
+// openAIFinishReason returns the finish reason OpenAI would give a response.
+func openAIFinishReason(resp ChatResponse) string {
+	if len(resp.ToolCalls) > 0 {
+		return "tool_calls"
+	}
+	return "stop"
+}
+
+// openAIResponse renders a chat completion.
+func openAIResponse(id, model string, resp ChatResponse) map[string]interface{} {
+	message := map[string]interface{}{"role": "assistant", "content": resp.Message}
+	if len(resp.ToolCalls) > 0 {
+		message["tool_calls"] = openAIToolCalls(resp.ToolCalls)
+	}
+	return map[string]interface{}{
+		"id":      id,
+		"object":  "chat.completion",
+		"created": time.Now().Unix(),
+		"model":   model,
+		"choices": []map[string]interface{}{{
+			"index":         0,
+			"message":       message,
+			"finish_reason": openAIFinishReason(resp),
+		}},
+		"usage": map[string]interface{}{
+			"prompt_tokens":     resp.Usage.InputTokens,
+			"completion_tokens": resp.Usage.OutputTokens,
+			"total_tokens":      resp.Usage.InputTokens + resp.Usage.OutputTokens,
+		},
+	}
+}
+
+// writeOpenAIStream streams a chat completion as server-sent events: the
+// message word by word, then the tool calls, the finish reason and the usage.
+func writeOpenAIStream(w http.ResponseWriter, id, model string, resp ChatResponse) {
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.WriteHeader(http.StatusOK)
+	chunk := func(choices []map[string]interface{}, usage map[string]interface{}) {
+		event := map[string]interface{}{
+			"id":      id,
+			"object":  "chat.completion.chunk",
+			"created": time.Now().Unix(),
+			"model":   model,
+			"choices": choices,
+		}
+		if usage != nil {
+			event["usage"] = usage
+		}
+		writeMockEvent(w, "", event)
+	}
+	delta := func(delta map[string]interface{}) {
+		chunk([]map[string]interface{}{{"index": 0, "delta": delta}}, nil)
+	}
+
+	delta(map[string]interface{}{"role": "assistant"})
+	for _, word := range mockStreamPieces(resp.Message) {
+		delta(map[string]interface{}{"content": word})
+	}
+	if len(resp.ToolCalls) > 0 {
+		delta(map[string]interface{}{"tool_calls": openAIToolCalls(resp.ToolCalls)})
+	}
+	chunk([]map[string]interface{}{{"index": 0, "delta": map[string]interface{}{}, "finish_reason": openAIFinishReason(resp)}}, nil)
+	chunk([]map[string]interface{}{}, map[string]interface{}{
+		"prompt_tokens":     resp.Usage.InputTokens,
+		"completion_tokens": resp.Usage.OutputTokens,
+		"total_tokens":      resp.Usage.InputTokens + resp.Usage.OutputTokens,
+	})
+	fmt.Fprint(w, "data: [DONE]\n\n")
+}
+
+// anthropicStopReason returns the stop reason Anthropic would give a response.
+func anthropicStopReason(resp ChatResponse) string {
+	if len(resp.ToolCalls) > 0 {
+		return "tool_use"
+	}
+	return "end_turn"
+}
+
+// anthropicResponse renders a Messages response.
+func anthropicResponse(id, model string, resp ChatResponse) map[string]interface{} {
+	content := []map[string]interface{}{}
+	if resp.Message != "" {
+		content = append(content, map[string]interface{}{"type": "text", "text": resp.Message})
+	}
+	for _, call := range resp.ToolCalls {
+		content = append(content, map[string]interface{}{"type": "tool_use", "id": call.ID, "name": call.Name, "input": call.Arguments})
+	}
+	return map[string]interface{}{
+		"id":            id,
+		"type":          "message",
+		"role":          "assistant",
+		"model":         model,
+		"content":       content,
+		"stop_reason":   anthropicStopReason(resp),
+		"stop_sequence": nil,
+		"usage": map[string]interface{}{
+			"input_tokens":  resp.Usage.InputTokens,
+			"output_tokens": resp.Usage.OutputTokens,
+		},
+	}
+}
+
+// writeAnthropicStream streams a Messages response as server-sent events:
+// a text block word by word, then a block for each tool call.
+func writeAnthropicStream(w http.ResponseWriter, id, model string, resp ChatResponse) {
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.WriteHeader(http.StatusOK)
+
+	writeMockEvent(w, "message_start", map[string]interface{}{
+		"type": "message_start",
+		"message": map[string]interface{}{
+			"id": id, "type": "message", "role": "assistant", "model": model, "content": []interface{}{},
+			"usage": map[string]interface{}{"input_tokens": resp.Usage.InputTokens, "output_tokens": 0},
+		},
+	})
+	index := 0
+	if resp.Message != "" {
+		writeMockEvent(w, "content_block_start", map[string]interface{}{
+			"type": "content_block_start", "index": index,
+			"content_block": map[string]interface{}{"type": "text", "text": ""},
+		})
+		for _, word := range mockStreamPieces(resp.Message) {
+			writeMockEvent(w, "content_block_delta", map[string]interface{}{
+				"type": "content_block_delta", "index": index,
+				"delta": map[string]interface{}{"type": "text_delta", "text": word},
+			})
+		}
+		writeMockEvent(w, "content_block_stop", map[string]interface{}{"type": "content_block_stop", "index": index})
+		index++
+	}
+	for _, call := range resp.ToolCalls {
+		arguments, _ := json.Marshal(call.Arguments)
+		writeMockEvent(w, "content_block_start", map[string]interface{}{
+			"type": "content_block_start", "index": index,
+			"content_block": map[string]interface{}{"type": "tool_use", "id": call.ID, "name": call.Name, "input": map[string]interface{}{}},
+		})
+		writeMockEvent(w, "content_block_delta", map[string]interface{}{
+			"type": "content_block_delta", "index": index,
+			"delta": map[string]interface{}{"type": "input_json_delta", "partial_json": string(arguments)},
+		})
+		writeMockEvent(w, "content_block_stop", map[string]interface{}{"type": "content_block_stop", "index": index})
+		index++
+	}
+	writeMockEvent(w, "message_delta", map[string]interface{}{
+		"type":  "message_delta",
+		"delta": map[string]interface{}{"stop_reason": anthropicStopReason(resp), "stop_sequence": nil},
+		"usage": map[string]interface{}{"output_tokens": resp.Usage.OutputTokens},
+	})
+	writeMockEvent(w, "message_stop", map[string]interface{}{"type": "message_stop"})
+}
+
+// mockStreamPieces splits a message into the pieces it is streamed in: each
+// word with the space after it.
+func mockStreamPieces(message string) []string {
+	if message == "" {
+		return nil
+	}
+	return strings.SplitAfter(message, " ")
+}
+
+// writeMockEvent writes one server-sent event, named if name isn't "", and
+// flushes it so the client sees it straight away.
+func writeMockEvent(w http.ResponseWriter, name string, data interface{}) {
+	encoded, _ := json.Marshal(data)
+	if name != "" {
+		fmt.Fprintf(w, "event: %s\n", name)
+	}
+	fmt.Fprintf(w, "data: %s\n\n", encoded)
+	if flusher, ok := w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// writeMockJSON writes a JSON response.
+func writeMockJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+// mockErrorStatus returns the HTTP status a failure is answered with.
+// Injected chaos failures are server errors.
+func mockErrorStatus(err error) (int, string) {
+	var statusErr *mockStatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.status, statusErr.message
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return http.StatusRequestTimeout, err.Error()
+	}
+	return http.StatusInternalServerError, err.Error()
+}
+
+// writeOpenAIError writes an error in OpenAI's format.
+func writeOpenAIError(w http.ResponseWriter, err error) {
+	status, message := mockErrorStatus(err)
+	errorType := "server_error"
+	switch {
+	case status == http.StatusTooManyRequests:
+		errorType = "rate_limit_exceeded"
+	case status < 500:
+		errorType = "invalid_request_error"
+	}
+	writeMockJSON(w, status, map[string]interface{}{
+		"error": map[string]interface{}{"message": message, "type": errorType},
+	})
+}
+
+// writeAnthropicError writes an error in Anthropic's format.
+func writeAnthropicError(w http.ResponseWriter, err error) {
+	status, message := mockErrorStatus(err)
+	errorType := "api_error"
+	switch {
+	case status == http.StatusTooManyRequests:
+		errorType = "rate_limit_error"
+	case status == http.StatusNotFound:
+		errorType = "not_found_error"
+	case status == 529:
+		errorType = "overloaded_error"
+	case status < 500:
+		errorType = "invalid_request_error"
+	}
+	writeMockJSON(w, status, map[string]interface{}{
+		"type":  "error",
+		"error": map[string]interface{}{"type": errorType, "message": message},
+	})
+}
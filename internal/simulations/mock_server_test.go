@@ -0,0 +1,147 @@
+package simulations
+
+import (
+	"context"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/poiesic/wonda/internal/config"
+)
+
+// mockServerClient creates a client of the given provider type talking to a mock server.
+func mockServerClient(t *testing.T, script *MockServerScript, providerType string) Client {
+	t.Helper()
+	require.NoError(t, script.Validate())
+	server := httptest.NewServer(NewMockServer(script, nil))
+	t.Cleanup(server.Close)
+
+	provider := &config.Provider{Name: "mock", Type: providerType, BaseURL: server.URL + "/v1"}
+	model := &config.Model{
+		Name:           "mock",
+		Provider:       "mock",
+		ThinkingParser: &config.ThinkingParserConfig{Type: config.ThinkingParserNone},
+	}
+	client, err := NewClient(provider, model)
+	require.NoError(t, err)
+	return client
+}
+
+func TestMockServer(t *testing.T) {
+	ctx := context.Background()
+	agentPrompt := "You are Alice, a stubborn chef\n\nDELIBERATION PHASE: pick a restaurant."
+	deliberationTools := []map[string]interface{}{
+		{"type": "function", "function": map[string]interface{}{"name": "list_goals", "description": "List goals", "parameters": map[string]interface{}{"type": "object"}}},
+		{"type": "function", "function": map[string]interface{}{"name": "propose_solution", "description": "Propose", "parameters": map[string]interface{}{"type": "object"}}},
+	}
+
+	t.Run("answers agents like a dry run over the OpenAI API", func(t *testing.T) {
+		client := mockServerClient(t, &MockServerScript{}, config.ProviderTypeOpenAI)
+		resp, err := client.Chat(ctx, ChatRequest{
+			Messages: []Message{{Role: "user", Content: agentPrompt}},
+			Tools:    deliberationTools,
+		})
+		require.NoError(t, err)
+		require.Len(t, resp.ToolCalls, 1)
+		assert.Equal(t, "list_goals", resp.ToolCalls[0].Name)
+		assert.Equal(t, "tool_calls", resp.FinishReason)
+		assert.Positive(t, resp.Usage.InputTokens)
+	})
+
+	t.Run("streams rule replies rendered as templates", func(t *testing.T) {
+		script := &MockServerScript{Rules: []MockRule{
+			{Caller: "Alice", Match: `pick (a \w+)`, Reply: "{{.Caller}} wants to pick {{index .Match 1}} (reply {{.Count}})"},
+		}}
+		client := mockServerClient(t, script, config.ProviderTypeOpenAI).(StreamingClient)
+
+		var streamed strings.Builder
+		resp, err := client.ChatStream(ctx, ChatRequest{Messages: []Message{{Role: "user", Content: agentPrompt}}},
+			func(delta StreamDelta) { streamed.WriteString(delta.Message) })
+		require.NoError(t, err)
+		assert.Equal(t, "Alice wants to pick a restaurant (reply 1)", resp.Message)
+		assert.Equal(t, resp.Message, streamed.String())
+	})
+
+	t.Run("makes canned tool calls over the Anthropic API, once per turn", func(t *testing.T) {
+		script := &MockServerScript{Rules: []MockRule{
+			{Tool: "propose_solution", Arguments: map[string]interface{}{"goal_name": "dinner", "solution": "{{.Caller}}'s place"}},
+		}}
+		client := mockServerClient(t, script, config.ProviderTypeAnthropic).(StreamingClient)
+
+		resp, err := client.ChatStream(ctx, ChatRequest{Messages: []Message{{Role: "user", Content: agentPrompt}}, Tools: deliberationTools}, nil)
+		require.NoError(t, err)
+		require.Len(t, resp.ToolCalls, 1)
+		assert.Equal(t, "propose_solution", resp.ToolCalls[0].Name)
+		assert.Equal(t, map[string]interface{}{"goal_name": "dinner", "solution": "Alice's place"}, resp.ToolCalls[0].Arguments)
+		assert.Equal(t, "tool_use", resp.FinishReason)
+
+		// Once the tool has been called, the agent's mock answers instead
+		resp, err = client.ChatStream(ctx, ChatRequest{Messages: []Message{
+			{Role: "user", Content: agentPrompt},
+			{Role: "assistant", Content: ""},
+			{Role: "tool", Content: "Tool 'propose_solution' returned:\n{\"success\": true}"},
+		}, Tools: deliberationTools}, nil)
+		require.NoError(t, err)
+		assert.Empty(t, resp.ToolCalls)
+		assert.NotEmpty(t, resp.Message)
+	})
+
+	t.Run("injects errors", func(t *testing.T) {
+		script := &MockServerScript{Rules: []MockRule{{Status: 400}}}
+		client := mockServerClient(t, script, config.ProviderTypeOpenAI)
+		_, err := client.Chat(ctx, ChatRequest{Messages: []Message{{Role: "user", Content: agentPrompt}}})
+		assert.ErrorContains(t, err, "mock: injected error")
+	})
+}
+
+func TestMockCaller(t *testing.T) {
+	judgePrompt, err := buildJudgePrompt(ChatRequest{}, nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, "Alice", mockCaller([]Message{{Role: "user", Content: "You are Alice, a stubborn chef"}}))
+	assert.Equal(t, mockCallerEnsembleJudge, mockCaller([]Message{{Role: "user", Content: judgePrompt}}))
+	assert.Empty(t, mockCaller([]Message{{Role: "user", Content: "Hello"}}))
+}
+
+func TestLoadMockServerScript(t *testing.T) {
+	write := func(t *testing.T, content string) string {
+		path := filepath.Join(t.TempDir(), "mock.toml")
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+		return path
+	}
+
+	t.Run("reads agent scripts alongside rules", func(t *testing.T) {
+		script, err := LoadMockServerScript(write(t, `
+seed = 7
+models = ["mock-large"]
+latency = "10ms"
+
+[agents.Alice]
+vote = "no"
+
+[[rules]]
+caller = "director"
+reply = '{"events": ["The lights go out."]}'
+`))
+		require.NoError(t, err)
+		assert.Equal(t, int64(7), script.Seed)
+		assert.Equal(t, "no", script.Agents["Alice"].Vote)
+		assert.Equal(t, []string{"mock-large"}, script.Models)
+		require.Len(t, script.Rules, 1)
+		assert.Equal(t, usageCallerDirector, script.Rules[0].Caller)
+	})
+
+	t.Run("rejects unusable rules", func(t *testing.T) {
+		_, err := LoadMockServerScript(write(t, "[[rules]]\nmatch = \"(\"\nreply = \"hi\"\n"))
+		assert.ErrorContains(t, err, "rule 1: invalid match")
+		_, err = LoadMockServerScript(write(t, "[[rules]]\nreply = \"{{.Nope\"\n"))
+		assert.ErrorContains(t, err, "rule 1: invalid reply")
+		_, err = LoadMockServerScript(write(t, "[[rules]]\ncaller = \"Alice\"\n"))
+		assert.ErrorContains(t, err, "needs a reply, a tool or a status")
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
@@ -19,12 +20,15 @@ import (
 
 // OpenAIClient implements the Client interface for OpenAI-compatible APIs.
 type OpenAIClient struct {
-	client  *openai.Client
-	model   *config.Model
-	parser  ResponseParser
-	modelID string
-	baseURL string
-	apiKey  string
+	client       *openai.Client
+	model        *config.Model
+	parser       ResponseParser
+	modelID      string
+	baseURL      string
+	apiKey       string
+	providerType string
+	sampling     *config.SamplingConfig
+	httpClient   *http.Client // Retries transient failures per the provider's policy
 }
 
 // newOpenAIClient creates a new OpenAI-compatible client.
@@ -38,19 +42,65 @@ func newOpenAIClient(provider *config.Provider, model *config.Model, parser Resp
 	// Create OpenAI client configuration
 	clientConfig := openai.DefaultConfig(apiKey)
 	clientConfig.BaseURL = provider.BaseURL
+	httpClient := newHTTPClient(provider)
+	clientConfig.HTTPClient = httpClient
 
 	client := openai.NewClientWithConfig(clientConfig)
 
 	return &OpenAIClient{
-		client:  client,
-		model:   model,
-		parser:  parser,
-		modelID: model.Name,
-		baseURL: provider.BaseURL,
-		apiKey:  apiKey,
+		client:       client,
+		model:        model,
+		parser:       parser,
+		modelID:      model.Name,
+		baseURL:      provider.BaseURL,
+		apiKey:       apiKey,
+		providerType: provider.Type,
+		sampling:     model.Sampling,
+		httpClient:   httpClient,
 	}, nil
 }
 
+// CheckModel looks the model up in the provider's model list.
+func (c *OpenAIClient) CheckModel(ctx context.Context) error {
+	// baseURL already includes /v1, just append the endpoint
+	url := strings.TrimRight(c.baseURL, "/") + "/models"
+	httpReq, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	if c.apiKey != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+
+	body, err := doPreflightRequest(httpReq)
+	if err != nil {
+		if errors.Is(err, errPreflightNotFound) {
+			return fmt.Errorf("provider has no model list at %s", url)
+		}
+		return err
+	}
+
+	// TGI serves a single model and ignores the name in requests
+	if c.providerType == config.ProviderTypeTGI {
+		return nil
+	}
+
+	var list struct {
+		Data []struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &list); err != nil {
+		return fmt.Errorf("failed to parse model list: %w", err)
+	}
+	for _, m := range list.Data {
+		if m.ID == c.modelID {
+			return nil
+		}
+	}
+	return fmt.Errorf("model %s is not available from the provider", c.modelID)
+}
+
 // Chat sends a chat completion request to an OpenAI-compatible API.
 func (c *OpenAIClient) Chat(ctx context.Context, req ChatRequest) (ChatResponse, error) {
 	// If we have an out-of-band parser (need to extract custom fields like reasoning),
@@ -59,6 +109,12 @@ func (c *OpenAIClient) Chat(ctx context.Context, req ChatRequest) (ChatResponse,
 		return c.chatRaw(ctx, req)
 	}
 
+	// vLLM and TGI extensions aren't part of the library's request type, and
+	// the library drops generation parameters set to zero
+	if c.sampling != nil || !requestGeneration(c.model, req).IsZero() {
+		return c.chatRaw(ctx, req)
+	}
+
 	// Otherwise use the go-openai library (faster, more reliable for standard fields)
 	return c.chatWithLibrary(ctx, req)
 }
@@ -184,6 +240,12 @@ func (c *OpenAIClient) chatWithLibrary(ctx context.Context, req ChatRequest) (Ch
 		Message:   content,
 		Thinking:  thinking,
 		ToolCalls: toolCalls,
+		Usage: Usage{
+			InputTokens:  resp.Usage.PromptTokens,
+			OutputTokens: resp.Usage.CompletionTokens,
+		},
+		FinishReason:    string(resp.Choices[0].FinishReason),
+		ProviderRefusal: message.Refusal,
 	}, nil
 }
 
@@ -213,6 +275,8 @@ func (c *OpenAIClient) chatRaw(ctx context.Context, req ChatRequest) (ChatRespon
 	if len(req.Tools) > 0 {
 		reqBody["tools"] = req.Tools
 	}
+	applyOpenAIGeneration(reqBody, requestGeneration(c.model, req))
+	c.applySampling(reqBody, req)
 
 	jsonBody, err := json.Marshal(reqBody)
 	if err != nil {
@@ -233,7 +297,7 @@ func (c *OpenAIClient) chatRaw(ctx context.Context, req ChatRequest) (ChatRespon
 	}
 
 	// Send request
-	httpResp, err := http.DefaultClient.Do(httpReq)
+	httpResp, err := c.httpClient.Do(httpReq)
 	if err != nil {
 		return ChatResponse{}, fmt.Errorf("http request failed: %w", err)
 	}
@@ -273,6 +337,8 @@ func (c *OpenAIClient) chatRaw(ctx context.Context, req ChatRequest) (ChatRespon
 
 	content, _ := message["content"].(string)
 	content = cleanModelArtifacts(content)
+	finishReason, _ := choice["finish_reason"].(string)
+	refusal, _ := message["refusal"].(string)
 
 	// Extract tool calls
 	var toolCalls []ToolCall
@@ -312,12 +378,34 @@ func (c *OpenAIClient) chatRaw(ctx context.Context, req ChatRequest) (ChatRespon
 		if thinking != "" {
 			slog.Debug("thinking extracted", "length", len(thinking))
 		}
+	} else {
+		// Models with sampling options come through here with in-band parsers too
+		content, thinking = c.parser.Parse(content)
+	}
+
+	// Recover tool calls from guided JSON output
+	if c.guidesTools(req) && len(toolCalls) == 0 {
+		content, toolCalls = parseGuidedOutput(content)
+	}
+
+	// Extract token usage if reported
+	var usage Usage
+	if rawUsage, ok := rawResp["usage"].(map[string]interface{}); ok {
+		if n, ok := rawUsage["prompt_tokens"].(float64); ok {
+			usage.InputTokens = int(n)
+		}
+		if n, ok := rawUsage["completion_tokens"].(float64); ok {
+			usage.OutputTokens = int(n)
+		}
 	}
 
 	return ChatResponse{
-		Message:   content,
-		Thinking:  thinking,
-		ToolCalls: toolCalls,
+		Message:         content,
+		Thinking:        thinking,
+		ToolCalls:       toolCalls,
+		Usage:           usage,
+		FinishReason:    finishReason,
+		ProviderRefusal: refusal,
 	}, nil
 }
 
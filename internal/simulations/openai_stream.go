@@ -0,0 +1,231 @@
+package simulations
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// streamChunk is one server-sent event of a streamed chat completion.
+type streamChunk struct {
+	Choices []struct {
+		Delta struct {
+			Content   string `json:"content"`
+			ToolCalls []struct {
+				Index    int    `json:"index"`
+				ID       string `json:"id"`
+				Function struct {
+					Name      string `json:"name"`
+					Arguments string `json:"arguments"`
+				} `json:"function"`
+			} `json:"tool_calls"`
+		} `json:"delta"`
+		FinishReason string `json:"finish_reason"`
+	} `json:"choices"`
+	Usage *struct {
+		PromptTokens     int `json:"prompt_tokens"`
+		CompletionTokens int `json:"completion_tokens"`
+	} `json:"usage"`
+}
+
+// streamedToolCall accumulates a tool call whose arguments arrive in pieces.
+type streamedToolCall struct {
+	id        string
+	name      string
+	arguments strings.Builder
+}
+
+// ChatStream implements StreamingClient.
+// Models without a thinking parser stream their message, and models with an
+// out-of-band parser also stream their reasoning field. Thinking delimiters
+// can't be separated from partial content, so in-band models fall back to Chat
+// and deliver no deltas. So do requests under guided tool decoding, whose
+// partial output is JSON rather than speech.
+func (c *OpenAIClient) ChatStream(ctx context.Context, req ChatRequest, onDelta func(delta StreamDelta)) (ChatResponse, error) {
+	var thinkingField string
+	switch parser := c.parser.(type) {
+	case *NoOpParser:
+	case *OutOfBandParser:
+		thinkingField = streamedField(parser.FieldPath())
+	default:
+		return c.Chat(ctx, req)
+	}
+	if c.guidesTools(req) {
+		return c.Chat(ctx, req)
+	}
+
+	modelID := req.Model
+	if modelID == "" {
+		modelID = c.modelID
+	}
+
+	messages := make([]map[string]interface{}, len(req.Messages))
+	for i, msg := range req.Messages {
+		messages[i] = map[string]interface{}{
+			"role":    msg.Role,
+			"content": msg.Content,
+		}
+	}
+
+	reqBody := map[string]interface{}{
+		"model":          modelID,
+		"messages":       messages,
+		"stream":         true,
+		"stream_options": map[string]interface{}{"include_usage": true},
+	}
+	if len(req.Tools) > 0 {
+		reqBody["tools"] = req.Tools
+	}
+	applyOpenAIGeneration(reqBody, requestGeneration(c.model, req))
+	c.applySampling(reqBody, req)
+
+	jsonBody, err := json.Marshal(reqBody)
+	if err != nil {
+		return ChatResponse{}, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	url := strings.TrimRight(c.baseURL, "/") + "/chat/completions"
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(jsonBody))
+	if err != nil {
+		return ChatResponse{}, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Accept", "text/event-stream")
+	if c.apiKey != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+
+	httpResp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return ChatResponse{}, fmt.Errorf("http request failed: %w", err)
+	}
+	defer httpResp.Body.Close()
+
+	if httpResp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(httpResp.Body)
+		return ChatResponse{}, fmt.Errorf("api error (status %d): %s", httpResp.StatusCode, string(respBody))
+	}
+
+	return readChatStream(httpResp.Body, thinkingField, onDelta)
+}
+
+// streamedField returns the delta field that streams an out-of-band thinking
+// field, e.g. "reasoning_content" for "choices[0].message.reasoning_content".
+func streamedField(fieldPath string) string {
+	return fieldPath[strings.LastIndex(fieldPath, ".")+1:]
+}
+
+// readChatStream assembles a response from an OpenAI-style event stream,
+// passing each piece of message content, and of the thinking field if there
+// is one, to onDelta as it is read.
+func readChatStream(body io.Reader, thinkingField string, onDelta func(delta StreamDelta)) (ChatResponse, error) {
+	var content, thinking strings.Builder
+	var toolCalls []*streamedToolCall
+	var usage Usage
+	var finishReason string
+
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		data, ok := strings.CutPrefix(line, "data:")
+		if !ok {
+			continue // Blank separators, comments and other SSE fields
+		}
+		data = strings.TrimSpace(data)
+		if data == "[DONE]" {
+			break
+		}
+
+		var chunk streamChunk
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			return ChatResponse{}, fmt.Errorf("failed to parse stream chunk: %w", err)
+		}
+
+		if chunk.Usage != nil {
+			usage.InputTokens = chunk.Usage.PromptTokens
+			usage.OutputTokens = chunk.Usage.CompletionTokens
+		}
+		if len(chunk.Choices) == 0 {
+			continue
+		}
+
+		if chunk.Choices[0].FinishReason != "" {
+			finishReason = chunk.Choices[0].FinishReason
+		}
+		delta := chunk.Choices[0].Delta
+		if thinkingField != "" {
+			if text := chunkField(data, thinkingField); text != "" {
+				thinking.WriteString(text)
+				if onDelta != nil {
+					onDelta(StreamDelta{Thinking: text})
+				}
+			}
+		}
+		if delta.Content != "" {
+			content.WriteString(delta.Content)
+			if onDelta != nil {
+				onDelta(StreamDelta{Message: delta.Content})
+			}
+		}
+		for _, tc := range delta.ToolCalls {
+			for len(toolCalls) <= tc.Index {
+				toolCalls = append(toolCalls, &streamedToolCall{})
+			}
+			call := toolCalls[tc.Index]
+			if tc.ID != "" {
+				call.id = tc.ID
+			}
+			if tc.Function.Name != "" {
+				call.name = tc.Function.Name
+			}
+			call.arguments.WriteString(tc.Function.Arguments)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return ChatResponse{}, fmt.Errorf("failed to read stream: %w", err)
+	}
+
+	response := ChatResponse{
+		Message:      cleanModelArtifacts(content.String()),
+		Thinking:     thinking.String(),
+		Usage:        usage,
+		FinishReason: finishReason,
+	}
+	for _, call := range toolCalls {
+		var args map[string]interface{}
+		if err := json.Unmarshal([]byte(call.arguments.String()), &args); err != nil {
+			// If parsing fails, use empty args
+			args = make(map[string]interface{})
+		}
+		response.ToolCalls = append(response.ToolCalls, ToolCall{
+			ID:        call.id,
+			Name:      call.name,
+			Arguments: args,
+		})
+	}
+	return response, nil
+}
+
+// chunkField returns a string field of a stream chunk's first delta, or "" if it has none.
+func chunkField(data, field string) string {
+	var chunk struct {
+		Choices []struct {
+			Delta map[string]json.RawMessage `json:"delta"`
+		} `json:"choices"`
+	}
+	if err := json.Unmarshal([]byte(data), &chunk); err != nil || len(chunk.Choices) == 0 {
+		return ""
+	}
+	var text string
+	if err := json.Unmarshal(chunk.Choices[0].Delta[field], &text); err != nil {
+		return ""
+	}
+	return text
+}
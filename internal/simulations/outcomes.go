@@ -0,0 +1,176 @@
+package simulations
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"sort"
+	"strings"
+
+	mcpsim "github.com/poiesic/wonda/internal/mcp/simulation"
+)
+
+// Outcomes summarizes how a simulation's goals ended, for tools that consume
+// results without replaying the chronicle.
+type Outcomes struct {
+	SimulationID string        `json:"simulation_id"`
+	Scenario     string        `json:"scenario"`
+	Turns        int           `json:"turns"`
+	MaxTurns     int           `json:"max_turns"`
+	Chronicle    string        `json:"chronicle,omitempty"`
+	Goals        []GoalOutcome `json:"goals"`
+	Success      *Success      `json:"success,omitempty"`     // Set when the scenario defines success criteria
+	Error        string        `json:"error,omitempty"`       // Why the run stopped early, if it did
+	PostMortem   *PostMortem   `json:"post_mortem,omitempty"` // Set when goals failed or the run stopped early
+}
+
+// GoalOutcome is how one goal ended.
+type GoalOutcome struct {
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	Status      string `json:"status"`
+	CompletedAt int    `json:"completed_at,omitempty"`
+	Solution    string `json:"solution,omitempty"`
+	ProposedBy  string `json:"proposed_by,omitempty"`
+
+	// Set for allocation goals that completed
+	Resource   string             `json:"resource,omitempty"`
+	Allocation map[string]float64 `json:"allocation,omitempty"`
+
+	// Set for judged goals
+	Confidence float64 `json:"confidence,omitempty"`
+	Assessment string  `json:"assessment,omitempty"`
+
+	// Set for individual goals: each agent's summary, by agent who completed theirs
+	Completions map[string]string `json:"completions,omitempty"`
+}
+
+// Success is whether a run met the scenario's success criteria, and the goal
+// outcomes that decided it.
+type Success struct {
+	Criteria string          `json:"criteria"`
+	Passed   bool            `json:"passed"`
+	Factors  []SuccessFactor `json:"factors"` // Goals the criteria reference, in the order they first appear
+}
+
+// SuccessFactor is how one goal the success criteria reference ended.
+type SuccessFactor struct {
+	Goal   string `json:"goal"`
+	Status string `json:"status"`
+	Met    bool   `json:"met"` // The goal was completed
+}
+
+// OutcomesPath returns the path of the outcomes file, once Start has written it.
+func (s *Simulation) OutcomesPath() string {
+	return s.outcomesPath
+}
+
+// buildOutcomes collects the final state of every goal, sorted by name.
+func (s *Simulation) buildOutcomes() Outcomes {
+	world := s.World.Snapshot()
+	outcomes := Outcomes{
+		SimulationID: s.ID.String(),
+		Scenario:     s.Scenario.Basics.Name,
+		Turns:        world.CurrentTurn,
+		MaxTurns:     s.MaxTurns(),
+		Chronicle:    s.chroniclePath,
+		Goals:        make([]GoalOutcome, 0, len(world.Goals)),
+	}
+
+	for _, goal := range world.Goals {
+		outcome := GoalOutcome{
+			Name:        goal.Name,
+			Type:        goal.Type,
+			Status:      string(goal.Status),
+			CompletedAt: goal.CompletedAt,
+		}
+		if goal.Judged() {
+			outcome.Confidence = goal.Confidence
+			outcome.Assessment = goal.Assessment
+		}
+		if len(goal.Completions) > 0 {
+			outcome.Completions = make(map[string]string, len(goal.Completions))
+			for agentName, completion := range goal.Completions {
+				outcome.Completions[agentName] = completion.Summary
+			}
+		}
+		for _, proposal := range goal.Proposals {
+			if proposal.Status != mcpsim.ProposalAccepted {
+				continue
+			}
+			outcome.Solution = proposal.Description
+			outcome.ProposedBy = proposal.ProposedBy
+			if goal.Allocation != nil {
+				outcome.Resource = goal.Allocation.Resource
+				outcome.Allocation = proposal.Allocation
+			}
+		}
+		outcomes.Goals = append(outcomes.Goals, outcome)
+	}
+	sort.Slice(outcomes.Goals, func(i, j int) bool { return outcomes.Goals[i].Name < outcomes.Goals[j].Name })
+	outcomes.Success = s.evaluateSuccess(outcomes.Goals)
+	return outcomes
+}
+
+// Success evaluates the scenario's success criteria against how its goals
+// stand, or returns nil if the scenario defines none.
+func (s *Simulation) Success() *Success {
+	return s.evaluateSuccess(s.buildOutcomes().Goals)
+}
+
+// evaluateSuccess decides whether goals met the scenario's success criteria,
+// with each goal counting as met once completed.
+func (s *Simulation) evaluateSuccess(goals []GoalOutcome) *Success {
+	criteria, err := s.Scenario.SuccessCriteria()
+	if err != nil {
+		slog.Warn("failed to compile success criteria", "error", err)
+		return nil
+	}
+	if criteria == nil {
+		return nil
+	}
+
+	statuses := make(map[string]string, len(goals))
+	met := make(map[string]bool, len(goals))
+	for _, goal := range goals {
+		statuses[goal.Name] = goal.Status
+		met[goal.Name] = goal.Status == string(mcpsim.GoalCompleted)
+	}
+	passed, err := criteria.Holds(met)
+	if err != nil {
+		slog.Warn("failed to evaluate success criteria", "criteria", criteria.String(), "error", err)
+		return nil
+	}
+
+	success := &Success{Criteria: criteria.String(), Passed: passed}
+	for _, name := range criteria.Variables() {
+		success.Factors = append(success.Factors, SuccessFactor{Goal: name, Status: statuses[name], Met: met[name]})
+	}
+	return success
+}
+
+// writeOutcomes writes the outcomes file alongside the chronicle, with a
+// post-mortem when the run stopped with an error or left goals unmet.
+func (s *Simulation) writeOutcomes(ctx context.Context, runErr error) error {
+	outcomes := s.buildOutcomes()
+	if runErr != nil {
+		outcomes.Error = runErr.Error()
+	}
+	if needsPostMortem(outcomes, runErr) {
+		outcomes.PostMortem = s.writePostMortem(ctx, runErr)
+	}
+
+	data, err := json.MarshalIndent(outcomes, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal outcomes: %w", err)
+	}
+
+	outcomesPath := strings.TrimSuffix(s.chroniclePath, ".jsonl") + ".outcomes.json"
+	if err := os.WriteFile(outcomesPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write outcomes: %w", err)
+	}
+	s.outcomesPath = outcomesPath
+	return nil
+}
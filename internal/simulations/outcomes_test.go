@@ -0,0 +1,46 @@
+package simulations
+
+import (
+	"testing"
+
+	mcpsim "github.com/poiesic/wonda/internal/mcp/simulation"
+	"github.com/poiesic/wonda/internal/scenarios"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvaluateSuccess(t *testing.T) {
+	newSim := func(criteria string) *Simulation {
+		return &Simulation{Scenario: &scenarios.Scenario{
+			Basics: &scenarios.BasicScenarioInformation{Name: "Dinner", Success: criteria},
+			Goals:  map[string]*scenarios.Goal{"restaurant": {}, "dessert": {}, "drinks": {}},
+		}}
+	}
+	goals := []GoalOutcome{
+		{Name: "dessert", Status: string(mcpsim.GoalFailed)},
+		{Name: "drinks", Status: string(mcpsim.GoalCompleted)},
+		{Name: "restaurant", Status: string(mcpsim.GoalCompleted)},
+	}
+
+	t.Run("passes with the goals it needs", func(t *testing.T) {
+		success := newSim("restaurant && (dessert || drinks)").evaluateSuccess(goals)
+		require.NotNil(t, success)
+		assert.True(t, success.Passed)
+		assert.Equal(t, "restaurant && (dessert || drinks)", success.Criteria)
+		assert.Equal(t, []SuccessFactor{
+			{Goal: "restaurant", Status: "completed", Met: true},
+			{Goal: "dessert", Status: "failed"},
+			{Goal: "drinks", Status: "completed", Met: true},
+		}, success.Factors)
+	})
+
+	t.Run("fails without them", func(t *testing.T) {
+		success := newSim("restaurant && !drinks || dessert").evaluateSuccess(goals)
+		require.NotNil(t, success)
+		assert.False(t, success.Passed)
+	})
+
+	t.Run("is left out without criteria", func(t *testing.T) {
+		assert.Nil(t, newSim("").evaluateSuccess(goals))
+	})
+}
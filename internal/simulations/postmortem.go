@@ -0,0 +1,271 @@
+package simulations
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/poiesic/wonda/internal/chronicle"
+	"github.com/poiesic/wonda/internal/config"
+	mcpsim "github.com/poiesic/wonda/internal/mcp/simulation"
+	"github.com/poiesic/wonda/internal/prompts"
+)
+
+// PostMortem explains why a run ended with failed goals or an error.
+// It is written by a judge model from the chronicle.
+type PostMortem struct {
+	Model           string            `json:"model"`
+	Summary         string            `json:"summary"`
+	Stalls          []string          `json:"stalls,omitempty"`           // Where deliberation stalled
+	FailedProposals []FailedProposal  `json:"failed_proposals,omitempty"` // Proposals that kept failing
+	Blockers        map[string]string `json:"blockers,omitempty"`         // What held each agent back
+}
+
+// FailedProposal is a proposal that failed, and why according to the votes against it.
+type FailedProposal struct {
+	Proposal string `json:"proposal"`
+	Reason   string `json:"reason"`
+}
+
+// initializePostMortemJudge picks the model that writes post-mortems: the
+// judge of the first judged goal, or else the scenario's default model.
+// Without either, failed runs get no post-mortem.
+func (s *Simulation) initializePostMortemJudge(models map[string]*config.Model, providers *config.Providers) error {
+	goalNames := make([]string, 0, len(s.goalJudges))
+	for goalName := range s.goalJudges {
+		goalNames = append(goalNames, goalName)
+	}
+	sort.Strings(goalNames)
+	if len(goalNames) > 0 {
+		s.postMortemJudge = s.goalJudges[goalNames[0]]
+		return nil
+	}
+
+	if s.Scenario.Basics.Defaults == nil || s.Scenario.Basics.Defaults.Model == "" {
+		return nil
+	}
+	modelName := s.Scenario.Basics.Defaults.Model
+	model, ok := models[modelName]
+	if !ok {
+		return fmt.Errorf("post-mortem model %s not found", modelName)
+	}
+	provider, ok := providers.Providers[model.Provider]
+	if !ok {
+		return fmt.Errorf("provider %s (from model %s) not found for post-mortems", model.Provider, modelName)
+	}
+	client, err := s.newClient(usageCallerPostmortem, provider, model)
+	if err != nil {
+		return fmt.Errorf("failed to create post-mortem judge: %w", err)
+	}
+	s.postMortemJudge = &goalJudge{client: client, model: modelName}
+	return nil
+}
+
+// needsPostMortem reports whether a run ended with an error or unmet goals.
+func needsPostMortem(outcomes Outcomes, runErr error) bool {
+	if runErr != nil {
+		return true
+	}
+	for _, goal := range outcomes.Goals {
+		if goal.Status != string(mcpsim.GoalCompleted) {
+			return true
+		}
+	}
+	return false
+}
+
+// writePostMortem asks the post-mortem judge why the run failed.
+// Failures are logged and leave the run without a post-mortem.
+func (s *Simulation) writePostMortem(ctx context.Context, runErr error) *PostMortem {
+	if s.postMortemJudge == nil {
+		slog.Warn("no judge model for a post-mortem; set a default model in the scenario")
+		return nil
+	}
+
+	_, turns, err := chronicle.ReadFile(s.chroniclePath)
+	if err != nil {
+		slog.Warn("failed to read chronicle for post-mortem", "error", err)
+		return nil
+	}
+	prompt, err := buildPostMortemPrompt(s.Scenario.Basics.Name, s.World.Snapshot(), turns, runErr)
+	if err != nil {
+		slog.Warn("failed to build post-mortem prompt", "error", err)
+		return nil
+	}
+
+	resp, err := s.postMortemJudge.client.Chat(ctx, ChatRequest{
+		Messages: []Message{{Role: "user", Content: prompt}},
+	})
+	if err != nil {
+		slog.Warn("post-mortem failed", "model", s.postMortemJudge.model, "error", err)
+		return nil
+	}
+	postMortem, err := parsePostMortem(resp.Message)
+	if err != nil {
+		slog.Warn("post-mortem failed", "model", s.postMortemJudge.model, "error", err)
+		return nil
+	}
+	postMortem.Model = s.postMortemJudge.model
+	slog.Info("post-mortem", "summary", postMortem.Summary)
+	return postMortem
+}
+
+// parsePostMortem reads a post-mortem reply, tolerating text around the JSON object.
+func parsePostMortem(reply string) (*PostMortem, error) {
+	match := judgmentPattern.FindString(reply)
+	if match == "" {
+		return nil, fmt.Errorf("post-mortem reply contained no JSON: %q", reply)
+	}
+
+	var postMortem PostMortem
+	if err := json.Unmarshal([]byte(match), &postMortem); err != nil {
+		return nil, fmt.Errorf("invalid post-mortem reply %q: %w", match, err)
+	}
+	if postMortem.Summary == "" {
+		return nil, fmt.Errorf("post-mortem reply has no summary: %q", match)
+	}
+	return &postMortem, nil
+}
+
+// buildPostMortemPrompt renders the post-mortem prompt template from the
+// final world state and the chronicle.
+func buildPostMortemPrompt(scenario string, world *mcpsim.WorldState, turns []chronicle.Turn, runErr error) (string, error) {
+	promptTemplate, err := prompts.GetPrompt("postmortem")
+	if err != nil {
+		return "", fmt.Errorf("failed to load post-mortem prompt: %w", err)
+	}
+
+	tmpl, err := template.New("postmortem").Parse(promptTemplate)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse template: %w", err)
+	}
+
+	data := struct {
+		Scenario  string
+		Error     string
+		Goals     []string
+		Proposals []string
+		Chronicle []string
+	}{
+		Scenario:  scenario,
+		Goals:     postMortemGoals(world),
+		Proposals: postMortemProposals(world),
+		Chronicle: postMortemChronicle(turns),
+	}
+	if runErr != nil {
+		data.Error = runErr.Error()
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to execute template: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// postMortemGoals describes each goal and how it ended, sorted by name.
+func postMortemGoals(world *mcpsim.WorldState) []string {
+	lines := make([]string, 0, len(world.Goals))
+	for _, goal := range world.Goals {
+		lines = append(lines, fmt.Sprintf("%s (%s): %s", goal.Name, goal.Status, goal.Description))
+	}
+	sort.Strings(lines)
+	return lines
+}
+
+// postMortemProposals describes every proposal with its outcome and who voted
+// which way, in the order they were made.
+func postMortemProposals(world *mcpsim.WorldState) []string {
+	var proposals []*mcpsim.Proposal
+	goalOf := make(map[*mcpsim.Proposal]string)
+	for _, goal := range world.Goals {
+		for _, proposal := range goal.Proposals {
+			proposals = append(proposals, proposal)
+			goalOf[proposal] = goal.Name
+		}
+	}
+	sort.Slice(proposals, func(i, j int) bool {
+		if proposals[i].ProposedAt != proposals[j].ProposedAt {
+			return proposals[i].ProposedAt < proposals[j].ProposedAt
+		}
+		return proposals[i].ID < proposals[j].ID
+	})
+
+	lines := make([]string, 0, len(proposals))
+	for _, proposal := range proposals {
+		var yes, no []string
+		for voter, vote := range proposal.Votes {
+			if vote.Choice == "yes" {
+				yes = append(yes, voter)
+			} else {
+				no = append(no, voter)
+			}
+		}
+		sort.Strings(yes)
+		sort.Strings(no)
+		lines = append(lines, fmt.Sprintf("turn %d, %s by %s for %s: %q (%s; yes: %s; no: %s)",
+			proposal.ProposedAt, proposal.ID, proposal.ProposedBy, goalOf[proposal], proposal.Description,
+			proposal.Status, joinOrNone(yes), joinOrNone(no)))
+	}
+	return lines
+}
+
+// postMortemChronicle flattens the chronicle into lines the judge can read.
+func postMortemChronicle(turns []chronicle.Turn) []string {
+	var lines []string
+	for _, turn := range turns {
+		lines = append(lines, fmt.Sprintf("--- Turn %d ---", turn.Number))
+		for _, ambient := range turn.Ambient {
+			lines = append(lines, fmt.Sprintf("(%s)", ambient))
+		}
+		for _, injection := range turn.Injected {
+			switch injection.Kind {
+			case injectionAtmosphere:
+				lines = append(lines, fmt.Sprintf("(The director changed the atmosphere: %s)", injection.Content))
+			case injectionNudge:
+				lines = append(lines, fmt.Sprintf("(The director nudged the agents: %s)", injection.Content))
+			}
+		}
+		for _, event := range turn.Events {
+			switch {
+			case event.Type == string(mcpsim.MessageTypePass):
+				lines = append(lines, fmt.Sprintf("%s passes. %s", event.AgentName, event.Dialogue))
+			case event.Refusal != nil:
+				lines = append(lines, fmt.Sprintf("%s refused to respond (%s)", event.AgentName, event.Refusal.Reason))
+			case event.Type == string(mcpsim.MessageTypeAction):
+				lines = append(lines, fmt.Sprintf("%s *%s*", event.AgentName, event.Dialogue))
+			case event.Type == string(mcpsim.MessageTypeMonologue):
+				// Private thoughts are left out, as for the goal judge
+			case event.Type == string(mcpsim.MessageTypeWhisper):
+				lines = append(lines, fmt.Sprintf("%s (whispering to %s): %s", event.AgentName, event.Recipient, event.Dialogue))
+			case event.Dialogue != "":
+				lines = append(lines, fmt.Sprintf("%s: %s", event.AgentName, event.Dialogue))
+			}
+		}
+		for _, skip := range turn.Skipped {
+			if skip.AgentName == "" {
+				lines = append(lines, fmt.Sprintf("(%s phase skipped: %s)", skip.Phase, skip.Reason))
+			}
+		}
+		for _, tieBreak := range turn.TieBreaks {
+			lines = append(lines, fmt.Sprintf("(tied vote on %s broken by %s: %s, as %s)", tieBreak.ProposalID, tieBreak.Policy, tieBreak.Outcome, tieBreak.Reason))
+		}
+		for _, completion := range turn.GoalCompletions {
+			lines = append(lines, fmt.Sprintf("(goal %s %s: %s)", completion.GoalName, completion.Status, completion.Solution))
+		}
+	}
+	return lines
+}
+
+// joinOrNone joins names with commas, or returns "none".
+func joinOrNone(names []string) string {
+	if len(names) == 0 {
+		return "none"
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,77 @@
+package simulations
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/poiesic/wonda/internal/chronicle"
+	mcpsim "github.com/poiesic/wonda/internal/mcp/simulation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePostMortem(t *testing.T) {
+	t.Run("reads JSON surrounded by text", func(t *testing.T) {
+		postMortem, err := parsePostMortem("Post-mortem:\n" +
+			`{"summary": "Price killed every plan.", "stalls": ["turns 3-5"], "failed_proposals": [{"proposal": "Bella's", "reason": "too expensive"}], "blockers": {"Bob": "budget"}}`)
+		require.NoError(t, err)
+		assert.Equal(t, "Price killed every plan.", postMortem.Summary)
+		assert.Equal(t, []string{"turns 3-5"}, postMortem.Stalls)
+		assert.Equal(t, []FailedProposal{{Proposal: "Bella's", Reason: "too expensive"}}, postMortem.FailedProposals)
+		assert.Equal(t, map[string]string{"Bob": "budget"}, postMortem.Blockers)
+	})
+
+	t.Run("rejects replies without a summary", func(t *testing.T) {
+		_, err := parsePostMortem(`{"stalls": []}`)
+		assert.Error(t, err)
+		_, err = parsePostMortem("no idea")
+		assert.Error(t, err)
+	})
+}
+
+func TestNeedsPostMortem(t *testing.T) {
+	completed := Outcomes{Goals: []GoalOutcome{{Name: "dinner", Status: string(mcpsim.GoalCompleted)}}}
+	pending := Outcomes{Goals: []GoalOutcome{{Name: "dinner", Status: string(mcpsim.GoalPending)}}}
+
+	assert.False(t, needsPostMortem(completed, nil))
+	assert.True(t, needsPostMortem(completed, errors.New("boom")))
+	assert.True(t, needsPostMortem(pending, nil))
+}
+
+func TestBuildPostMortemPrompt(t *testing.T) {
+	world := mcpsim.NewWorldState("Cafe", "")
+	goal := mcpsim.NewInteractiveGoal("dinner", "Pick a restaurant", "consensus", 1)
+	goal.Proposals["proposal_1"] = &mcpsim.Proposal{
+		ID:          "proposal_1",
+		Description: "Bella's",
+		ProposedBy:  "Alice",
+		ProposedAt:  2,
+		Status:      mcpsim.ProposalRejected,
+		Votes: map[string]*mcpsim.Vote{
+			"Alice": {AgentName: "Alice", Choice: "yes"},
+			"Bob":   {AgentName: "Bob", Choice: "no"},
+		},
+	}
+	world.AddGoal(goal)
+
+	turns := []chronicle.Turn{{
+		Number: 2,
+		Events: []chronicle.Event{
+			{AgentName: "Bob", Type: "dialogue", Dialogue: "Too pricey."},
+			{AgentName: "Bob", Type: "monologue", Dialogue: "I'm broke."},
+			{AgentName: "Carol", Type: "pass", Dialogue: "Nothing to add."},
+		},
+		Skipped: []chronicle.PhaseSkip{{Phase: "voting", Reason: "no proposals awaiting votes"}},
+	}}
+
+	prompt, err := buildPostMortemPrompt("Dinner", world, turns, errors.New("agent Bob failed to vote"))
+	require.NoError(t, err)
+	assert.Contains(t, prompt, "agent Bob failed to vote")
+	assert.Contains(t, prompt, "dinner (pending): Pick a restaurant")
+	assert.Contains(t, prompt, `turn 2, proposal_1 by Alice for dinner: "Bella's" (rejected; yes: Alice; no: Bob)`)
+	assert.Contains(t, prompt, "Bob: Too pricey.")
+	assert.Contains(t, prompt, "Carol passes. Nothing to add.")
+	assert.Contains(t, prompt, "(voting phase skipped: no proposals awaiting votes)")
+	assert.False(t, strings.Contains(prompt, "I'm broke."), "private thoughts are left out")
+}
@@ -0,0 +1,184 @@
+package simulations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/poiesic/wonda/internal/config"
+	"github.com/poiesic/wonda/internal/scenarios"
+)
+
+// preflightTimeout bounds each provider check so an unreachable endpoint
+// fails the run quickly instead of hanging until the scenario's max runtime.
+const preflightTimeout = 30 * time.Second
+
+// errPreflightNotFound is returned by doPreflightRequest when the endpoint answers 404.
+var errPreflightNotFound = errors.New("not found")
+
+// preflightTarget is one provider/model combination used by the scenario's agents.
+type preflightTarget struct {
+	provider *config.Provider
+	model    *config.Model
+	agents   []string // Agents that use the model, for error messages
+}
+
+// agentModelName returns the model an agent runs on, falling back to the scenario default.
+func (s *Simulation) agentModelName(agentName string, agentConfig *scenarios.Agent) (string, error) {
+	modelName := agentConfig.Model
+	if modelName == "" && s.Scenario.Basics.Defaults != nil {
+		modelName = s.Scenario.Basics.Defaults.Model
+	}
+	if modelName == "" {
+		return "", fmt.Errorf("agent %s missing model configuration", agentName)
+	}
+	return modelName, nil
+}
+
+// preflight checks every provider/model combination the agents use, including
+// ensemble members, before any time is spent on embeddings or seeding. All
+// problems are reported together so a misconfigured scenario can be fixed in one pass.
+func (s *Simulation) preflight(ctx context.Context, models map[string]*config.Model, providers *config.Providers) error {
+	targets, err := s.preflightTargets(models, providers)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, target := range targets {
+		if err := s.ensureLlamaServer(ctx, target.provider, target.model); err != nil {
+			errs = append(errs, fmt.Errorf("model %s (agents: %s): %w", target.model.Name, strings.Join(target.agents, ", "), err))
+			continue
+		}
+		client, err := NewClient(target.provider, target.model)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("model %s (agents: %s): %w", target.model.Name, strings.Join(target.agents, ", "), err))
+			continue
+		}
+		checker, ok := client.(ModelChecker)
+		if !ok {
+			continue
+		}
+
+		checkCtx, cancel := context.WithTimeout(ctx, preflightTimeout)
+		err = checker.CheckModel(checkCtx)
+		cancel()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("provider %s, model %s (agents: %s): %w", target.provider.Name, target.model.Name, strings.Join(target.agents, ", "), err))
+			continue
+		}
+		slog.Info("preflight check passed", "provider", target.provider.Name, "model", target.model.Name)
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("preflight check failed: %w", errors.Join(errs...))
+	}
+	return nil
+}
+
+// preflightTargets resolves the distinct provider/model combinations the agents use.
+// Missing model or provider configuration is reported for every agent at once.
+func (s *Simulation) preflightTargets(models map[string]*config.Model, providers *config.Providers) ([]*preflightTarget, error) {
+	targets, errs := s.resolveAgentModels(models, providers)
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("preflight check failed: %w", errors.Join(errs...))
+	}
+	return targets, nil
+}
+
+// resolveAgentModels resolves the distinct provider/model combinations the
+// agents use, returning every model or provider that can't be resolved.
+func (s *Simulation) resolveAgentModels(models map[string]*config.Model, providers *config.Providers) ([]*preflightTarget, []error) {
+	agentNames := make([]string, 0, len(s.Scenario.Agents))
+	for agentName := range s.Scenario.Agents {
+		agentNames = append(agentNames, agentName)
+	}
+	sort.Strings(agentNames)
+
+	byModel := make(map[string]*preflightTarget)
+	var order []string
+	var errs []error
+	add := func(agentName, modelName string) {
+		if target, ok := byModel[modelName]; ok {
+			if target.agents[len(target.agents)-1] != agentName {
+				target.agents = append(target.agents, agentName)
+			}
+			return
+		}
+		model, ok := models[modelName]
+		if !ok {
+			errs = append(errs, fmt.Errorf("model %s not found for agent %s", modelName, agentName))
+			return
+		}
+		if model.Provider == "" {
+			errs = append(errs, fmt.Errorf("model %s does not specify a provider", modelName))
+			return
+		}
+		provider, ok := providers.Providers[model.Provider]
+		if !ok {
+			errs = append(errs, fmt.Errorf("provider %s (from model %s) not found for agent %s", model.Provider, modelName, agentName))
+			return
+		}
+		byModel[modelName] = &preflightTarget{provider: provider, model: model, agents: []string{agentName}}
+		order = append(order, modelName)
+	}
+
+	for _, agentName := range agentNames {
+		agentConfig := s.Scenario.Agents[agentName]
+		if agentConfig.Human() {
+			continue
+		}
+		modelName, err := s.agentModelName(agentName, agentConfig)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		add(agentName, modelName)
+
+		if agentConfig.Ensemble != nil {
+			for _, member := range agentConfig.Ensemble.Models {
+				add(agentName, member)
+			}
+			if agentConfig.Ensemble.JudgeModel != "" {
+				add(agentName, agentConfig.Ensemble.JudgeModel)
+			}
+		}
+	}
+
+	targets := make([]*preflightTarget, 0, len(order))
+	for _, modelName := range order {
+		targets = append(targets, byModel[modelName])
+	}
+	return targets, errs
+}
+
+// doPreflightRequest sends a provider check and returns the response body,
+// translating HTTP failures into errors a scenario author can act on.
+func doPreflightRequest(req *http.Request) ([]byte, error) {
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("provider unreachable: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		return nil, fmt.Errorf("provider rejected the API key (status %d)", resp.StatusCode)
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, errPreflightNotFound
+	case resp.StatusCode < 200 || resp.StatusCode >= 300:
+		return nil, fmt.Errorf("api error (status %d): %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return body, nil
+}
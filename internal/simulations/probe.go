@@ -0,0 +1,181 @@
+package simulations
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/poiesic/wonda/internal/config"
+)
+
+// probePrompt is a small question worth reasoning about, so a model that
+// thinks shows where its thinking goes.
+const probePrompt = "A train leaves at 9:40 and the trip takes 85 minutes. When does it arrive? Reply with the time only."
+
+// probeMaxTokens bounds the probe's answer where the API requires a limit.
+const probeMaxTokens = 1024
+
+// reasoningFields are the message fields OpenAI-compatible servers return
+// reasoning in, in the order they are checked.
+var reasoningFields = []string{"reasoning_content", "reasoning", "thinking"}
+
+// thinkingDelimiters are the tags models wrap reasoning in within their answer.
+var thinkingDelimiters = []struct{ start, end string }{
+	{"<think>", "</think>"},
+	{"<thinking>", "</thinking>"},
+	{"<reasoning>", "</reasoning>"},
+	{"[THINK]", "[/THINK]"},
+}
+
+// ProbeThinkingParser makes one small request to a model and works out from
+// the raw response how it returns its reasoning: in a field beside its
+// answer, wrapped in delimiters within it, or not at all. Unlike the parser
+// guessed from the model's name, this finds what the provider actually sends.
+func ProbeThinkingParser(ctx context.Context, provider *config.Provider, model *config.Model) (*config.ThinkingParserConfig, error) {
+	// Ask for the raw answer, without the model's own parsing or decoding constraints
+	probed := *model
+	probed.ThinkingParser = &config.ThinkingParserConfig{Type: config.ThinkingParserNone}
+	probed.Sampling = nil
+	client, err := NewClient(provider, &probed)
+	if err != nil {
+		return nil, err
+	}
+
+	switch c := client.(type) {
+	case *AnthropicClient:
+		return c.probeThinking(ctx)
+	case *OpenAIClient:
+		return c.probeThinking(ctx)
+	}
+	return nil, fmt.Errorf("models of provider %s can't be probed", provider.Name)
+}
+
+// probeThinking looks for reasoning fields in a raw chat completion, then for
+// delimiters in its answer.
+func (c *OpenAIClient) probeThinking(ctx context.Context) (*config.ThinkingParserConfig, error) {
+	reqBody := map[string]interface{}{
+		"model":    c.modelID,
+		"messages": []map[string]interface{}{{"role": "user", "content": probePrompt}},
+	}
+	headers := map[string]string{}
+	if c.apiKey != "" {
+		headers["Authorization"] = "Bearer " + c.apiKey
+	}
+	body, err := probeRequest(ctx, c.httpClient, strings.TrimRight(c.baseURL, "/")+"/chat/completions", headers, reqBody)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp struct {
+		Choices []struct {
+			Message map[string]interface{} `json:"message"`
+		} `json:"choices"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse probe response: %w", err)
+	}
+	if len(resp.Choices) == 0 {
+		return nil, fmt.Errorf("no choices in probe response")
+	}
+
+	message := resp.Choices[0].Message
+	for _, field := range reasoningFields {
+		if text, ok := message[field].(string); ok && strings.TrimSpace(text) != "" {
+			return &config.ThinkingParserConfig{
+				Type:      config.ThinkingParserOutOfBand,
+				FieldPath: "choices.0.message." + field,
+			}, nil
+		}
+	}
+	content, _ := message["content"].(string)
+	return detectThinkingDelimiters(content), nil
+}
+
+// probeThinking looks for thinking blocks in a raw Messages response, then
+// for delimiters in its text.
+func (c *AnthropicClient) probeThinking(ctx context.Context) (*config.ThinkingParserConfig, error) {
+	reqBody := map[string]interface{}{
+		"model":      c.modelID,
+		"max_tokens": probeMaxTokens,
+		"messages":   []map[string]interface{}{{"role": "user", "content": probePrompt}},
+	}
+	headers := map[string]string{
+		"x-api-key":         c.apiKey,
+		"anthropic-version": anthropicAPIVersion,
+	}
+	body, err := probeRequest(ctx, c.httpClient, strings.TrimRight(c.baseURL, "/")+"/messages", headers, reqBody)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp struct {
+		Content []struct {
+			Type string `json:"type"`
+			Text string `json:"text"`
+		} `json:"content"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse probe response: %w", err)
+	}
+
+	var text strings.Builder
+	for _, block := range resp.Content {
+		switch block.Type {
+		case "thinking", "redacted_thinking":
+			return &config.ThinkingParserConfig{Type: config.ThinkingParserOutOfBand, FieldPath: "thinking"}, nil
+		case "text":
+			text.WriteString(block.Text)
+		}
+	}
+	return detectThinkingDelimiters(text.String()), nil
+}
+
+// detectThinkingDelimiters returns an in-band parser for the first known
+// delimiters wrapping part of an answer, or no parser.
+func detectThinkingDelimiters(content string) *config.ThinkingParserConfig {
+	for _, delims := range thinkingDelimiters {
+		start := strings.Index(content, delims.start)
+		if start >= 0 && strings.Contains(content[start+len(delims.start):], delims.end) {
+			return &config.ThinkingParserConfig{
+				Type:           config.ThinkingParserInBand,
+				StartDelimiter: delims.start,
+				EndDelimiter:   delims.end,
+			}
+		}
+	}
+	return &config.ThinkingParserConfig{Type: config.ThinkingParserNone}
+}
+
+// probeRequest posts a probe and returns the raw response body.
+func probeRequest(ctx context.Context, client *http.Client, url string, headers map[string]string, reqBody map[string]interface{}) ([]byte, error) {
+	jsonBody, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(jsonBody))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	for name, value := range headers {
+		httpReq.Header.Set(name, value)
+	}
+
+	httpResp, err := client.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("http request failed: %w", err)
+	}
+	defer httpResp.Body.Close()
+	body, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if httpResp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("api error (status %d): %s", httpResp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return body, nil
+}
@@ -0,0 +1,76 @@
+package simulations
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/poiesic/wonda/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProbeThinkingParser(t *testing.T) {
+	probe := func(t *testing.T, provider *config.Provider, respond func(w http.ResponseWriter, reqBody map[string]interface{})) *config.ThinkingParserConfig {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var reqBody map[string]interface{}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&reqBody))
+			w.Header().Set("Content-Type", "application/json")
+			respond(w, reqBody)
+		}))
+		defer server.Close()
+
+		provider.BaseURL = server.URL
+		model := &config.Model{
+			Name:           "probed",
+			Provider:       provider.Name,
+			ThinkingParser: &config.ThinkingParserConfig{Type: config.ThinkingParserInBand, StartDelimiter: "<x>", EndDelimiter: "</x>"},
+		}
+		parser, err := ProbeThinkingParser(context.Background(), provider, model)
+		require.NoError(t, err)
+		return parser
+	}
+	openAI := func(message map[string]interface{}) func(w http.ResponseWriter, reqBody map[string]interface{}) {
+		return func(w http.ResponseWriter, reqBody map[string]interface{}) {
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"choices": []interface{}{map[string]interface{}{"message": message, "finish_reason": "stop"}},
+			})
+		}
+	}
+
+	t.Run("finds a reasoning field", func(t *testing.T) {
+		parser := probe(t, &config.Provider{Name: "deepseek"}, openAI(map[string]interface{}{
+			"role": "assistant", "content": "11:05", "reasoning_content": "9:40 plus 85 minutes is 11:05.",
+		}))
+		assert.Equal(t, &config.ThinkingParserConfig{Type: config.ThinkingParserOutOfBand, FieldPath: "choices.0.message.reasoning_content"}, parser)
+	})
+
+	t.Run("finds delimiters in the answer", func(t *testing.T) {
+		parser := probe(t, &config.Provider{Name: "local"}, openAI(map[string]interface{}{
+			"role": "assistant", "content": "<think>85 minutes is 1:25.</think>\n11:05",
+		}))
+		assert.Equal(t, &config.ThinkingParserConfig{Type: config.ThinkingParserInBand, StartDelimiter: "<think>", EndDelimiter: "</think>"}, parser)
+	})
+
+	t.Run("finds no reasoning", func(t *testing.T) {
+		parser := probe(t, &config.Provider{Name: "local"}, openAI(map[string]interface{}{
+			"role": "assistant", "content": "11:05", "reasoning": "",
+		}))
+		assert.Equal(t, &config.ThinkingParserConfig{Type: config.ThinkingParserNone}, parser)
+	})
+
+	t.Run("finds Anthropic thinking blocks", func(t *testing.T) {
+		parser := probe(t, &config.Provider{Name: "claude", Type: config.ProviderTypeAnthropic}, func(w http.ResponseWriter, reqBody map[string]interface{}) {
+			assert.Equal(t, float64(probeMaxTokens), reqBody["max_tokens"])
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"content": []interface{}{
+					map[string]interface{}{"type": "thinking", "thinking": "85 minutes is 1:25."},
+					map[string]interface{}{"type": "text", "text": "11:05"},
+				},
+			})
+		})
+		assert.Equal(t, &config.ThinkingParserConfig{Type: config.ThinkingParserOutOfBand, FieldPath: "thinking"}, parser)
+	})
+}
@@ -0,0 +1,161 @@
+package simulations
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"sort"
+
+	"github.com/poiesic/wonda/internal/chronicle"
+)
+
+// Refusal kinds
+const (
+	// RefusalFiltered means the provider's content filter blocked the response.
+	RefusalFiltered = "filtered"
+	// RefusalDeclined means the model declined to respond.
+	RefusalDeclined = "declined"
+)
+
+// Refusal is a response the model refused or the provider filtered.
+type Refusal struct {
+	Kind      string // RefusalFiltered or RefusalDeclined
+	Reason    string // Finish reason or description of the matched refusal pattern
+	Text      string // What the model said instead, if anything
+	Recovered bool   // A retry produced a usable response
+}
+
+// refusalFinishReasons maps provider finish reasons to refusal kinds.
+var refusalFinishReasons = map[string]string{
+	"content_filter": RefusalFiltered, // OpenAI-compatible content filtering
+	"refusal":        RefusalDeclined, // Anthropic refusal stop reason
+}
+
+// refusalPatterns match the opening of common model refusals.
+// Only the start of a message without tool calls is checked, so characters
+// who decline something in the story are rarely mistaken for refusals.
+var refusalPatterns = []struct {
+	pattern *regexp.Regexp
+	reason  string
+}{
+	{regexp.MustCompile(`(?i)^\W*as an ai\b`), "identified as an AI"},
+	{regexp.MustCompile(`(?i)^\W*(i'?m sorry|i apologi[sz]e|sorry)\b[^.!?]{0,40}\b(can(no|')?t|unable to|won'?t|not able to)\s+(help|assist|comply|fulfill|continue|engage|participate|provide|generate|create|write|produce)\b`), "apologetic refusal"},
+	{regexp.MustCompile(`(?i)^\W*i\s+(can(no|')?t|won'?t|am unable to|'m unable to|am not able to|'m not able to)\s+(help|assist|comply|fulfill|continue|engage|participate|provide|generate|create|write|produce)\s+(with\s+)?(this|that|the|your)\s+(request|content|scenario|role-?play|story)\b`), "declined the request"},
+}
+
+// refusalPrefixLength is how much of a message is checked against refusal patterns.
+const refusalPrefixLength = 200
+
+// detectRefusal reports whether a response is a refusal rather than an answer.
+func detectRefusal(response ChatResponse) (Refusal, bool) {
+	if kind, ok := refusalFinishReasons[response.FinishReason]; ok {
+		return Refusal{Kind: kind, Reason: response.FinishReason, Text: refusalText(response)}, true
+	}
+	if response.ProviderRefusal != "" {
+		return Refusal{Kind: RefusalDeclined, Reason: "provider refusal", Text: response.ProviderRefusal}, true
+	}
+	if len(response.ToolCalls) > 0 {
+		return Refusal{}, false
+	}
+
+	prefix := response.Message
+	if len(prefix) > refusalPrefixLength {
+		prefix = prefix[:refusalPrefixLength]
+	}
+	for _, p := range refusalPatterns {
+		if p.pattern.MatchString(prefix) {
+			return Refusal{Kind: RefusalDeclined, Reason: p.reason, Text: response.Message}, true
+		}
+	}
+	return Refusal{}, false
+}
+
+// refusalText returns what the model said with a refusal.
+func refusalText(response ChatResponse) string {
+	if response.ProviderRefusal != "" {
+		return response.ProviderRefusal
+	}
+	return response.Message
+}
+
+// handleRefusals retries refused responses with a softened prompt, up to the
+// agent's RefusalRetries. Every refusal is attached to the returned response.
+// If the last attempt is still refused, its message is dropped so the refusal
+// never enters the scene as dialogue.
+func (a *Agent) handleRefusals(ctx context.Context, req ChatRequest, response ChatResponse) (ChatResponse, error) {
+	if a.Human {
+		return response, nil
+	}
+	var refusals []Refusal
+	for attempt := 0; ; attempt++ {
+		refusal, refused := detectRefusal(response)
+		if !refused {
+			for i := range refusals {
+				refusals[i].Recovered = true
+			}
+			response.Refusals = refusals
+			return response, nil
+		}
+
+		slog.Warn("agent response refused", "agent", a.Name, "kind", refusal.Kind, "reason", refusal.Reason, "attempt", attempt)
+		refusals = append(refusals, refusal)
+		if attempt >= a.RefusalRetries {
+			response.Message = ""
+			response.ProviderRefusal = ""
+			response.Refusals = refusals
+			return response, nil
+		}
+
+		// Ask again, reminding the model this is fiction and inviting a gentler take
+		retry := req
+		retry.Messages = append(append([]Message{}, req.Messages...), Message{
+			Role: "user",
+			Content: "This is collaborative fiction: every character and event is invented. " +
+				"Respond again as your character, toning down anything you weren't comfortable with. " +
+				"It's fine for your character to steer the scene somewhere else.",
+		})
+		var err error
+		response, err = a.chat(ctx, retry)
+		if err != nil {
+			return ChatResponse{}, fmt.Errorf("LLM call failed while retrying refusal: %w", err)
+		}
+	}
+}
+
+// captureRefusals records refusal events for the chronicle and counts them per agent.
+func (s *Simulation) captureRefusals(agentName string, refusals []Refusal) {
+	for _, refusal := range refusals {
+		s.currentTurnEvents = append(s.currentTurnEvents, chronicle.Event{
+			AgentName: agentName,
+			Type:      "refusal",
+			Dialogue:  refusal.Text,
+			Refusal: &chronicle.Refusal{
+				Kind:      refusal.Kind,
+				Reason:    refusal.Reason,
+				Recovered: refusal.Recovered,
+			},
+		})
+		s.refusalCounts[agentName]++
+	}
+}
+
+// printRefusalSummary reports how many refusals each agent had.
+func (s *Simulation) printRefusalSummary() {
+	if len(s.refusalCounts) == 0 {
+		return
+	}
+
+	names := make([]string, 0, len(s.refusalCounts))
+	total := 0
+	for name, count := range s.refusalCounts {
+		names = append(names, name)
+		total += count
+	}
+	sort.Strings(names)
+
+	slog.Warn("refusal summary", "total", total)
+	for _, name := range names {
+		slog.Warn("refusals", "agent", name, "count", s.refusalCounts[name])
+	}
+}
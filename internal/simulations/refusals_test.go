@@ -0,0 +1,117 @@
+package simulations
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectRefusal(t *testing.T) {
+	t.Run("reports content filtering", func(t *testing.T) {
+		refusal, ok := detectRefusal(ChatResponse{FinishReason: "content_filter"})
+		require.True(t, ok)
+		assert.Equal(t, RefusalFiltered, refusal.Kind)
+	})
+
+	t.Run("reports provider refusals", func(t *testing.T) {
+		refusal, ok := detectRefusal(ChatResponse{ProviderRefusal: "I can't continue this scene."})
+		require.True(t, ok)
+		assert.Equal(t, RefusalDeclined, refusal.Kind)
+		assert.Equal(t, "I can't continue this scene.", refusal.Text)
+	})
+
+	t.Run("recognizes refusal text", func(t *testing.T) {
+		for _, message := range []string{
+			"As an AI, I don't have opinions on this.",
+			"I'm sorry, but I can't help with that.",
+			"I cannot continue this role-play.",
+		} {
+			_, ok := detectRefusal(ChatResponse{Message: message})
+			assert.True(t, ok, message)
+		}
+	})
+
+	t.Run("ignores characters declining in the story", func(t *testing.T) {
+		for _, message := range []string{
+			"I can't help you move that couch, my back is killing me.",
+			"Sorry, Bob. I won't lend you the car again.",
+			"Look, I'm not doing that. Find someone else.",
+		} {
+			_, ok := detectRefusal(ChatResponse{Message: message, FinishReason: "stop"})
+			assert.False(t, ok, message)
+		}
+	})
+
+	t.Run("ignores responses with tool calls", func(t *testing.T) {
+		_, ok := detectRefusal(ChatResponse{
+			Message:   "I'm sorry, but I can't help with that.",
+			ToolCalls: []ToolCall{{Name: "speak"}},
+		})
+		assert.False(t, ok)
+	})
+}
+
+// sequenceClient returns its responses in order, repeating the last one.
+type sequenceClient struct {
+	responses []ChatResponse
+	requests  []ChatRequest
+}
+
+func (s *sequenceClient) Chat(ctx context.Context, req ChatRequest) (ChatResponse, error) {
+	s.requests = append(s.requests, req)
+	response := s.responses[min(len(s.requests), len(s.responses))-1]
+	return response, nil
+}
+
+func TestHandleRefusals(t *testing.T) {
+	refused := ChatResponse{Message: "I'm sorry, but I can't help with that."}
+	req := ChatRequest{Messages: []Message{{Role: "user", Content: "Your turn."}}}
+
+	t.Run("passes answers through", func(t *testing.T) {
+		agent := &Agent{Name: "Alice", Client: &sequenceClient{}, RefusalRetries: 1}
+		response, err := agent.handleRefusals(context.Background(), req, ChatResponse{Message: "Hello."})
+		require.NoError(t, err)
+		assert.Equal(t, "Hello.", response.Message)
+		assert.Empty(t, response.Refusals)
+	})
+
+	t.Run("retries with a softened prompt", func(t *testing.T) {
+		client := &sequenceClient{responses: []ChatResponse{{Message: "Fine, let's talk."}}}
+		agent := &Agent{Name: "Alice", Client: client, RefusalRetries: 1}
+
+		response, err := agent.handleRefusals(context.Background(), req, refused)
+		require.NoError(t, err)
+		assert.Equal(t, "Fine, let's talk.", response.Message)
+		require.Len(t, response.Refusals, 1)
+		assert.True(t, response.Refusals[0].Recovered)
+
+		require.Len(t, client.requests, 1)
+		assert.Len(t, client.requests[0].Messages, 2)
+		assert.Len(t, req.Messages, 1, "original request must not be modified")
+	})
+
+	t.Run("drops the message when retries run out", func(t *testing.T) {
+		client := &sequenceClient{responses: []ChatResponse{refused}}
+		agent := &Agent{Name: "Alice", Client: client, RefusalRetries: 2}
+
+		response, err := agent.handleRefusals(context.Background(), req, refused)
+		require.NoError(t, err)
+		assert.Empty(t, response.Message)
+		assert.Len(t, response.Refusals, 3)
+		assert.False(t, response.Refusals[2].Recovered)
+		assert.Len(t, client.requests, 2)
+	})
+
+	t.Run("records without retrying by default", func(t *testing.T) {
+		client := &sequenceClient{}
+		agent := &Agent{Name: "Alice", Client: client}
+
+		response, err := agent.handleRefusals(context.Background(), req, refused)
+		require.NoError(t, err)
+		assert.Empty(t, response.Message)
+		assert.Len(t, response.Refusals, 1)
+		assert.Empty(t, client.requests)
+	})
+}
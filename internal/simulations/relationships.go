@@ -0,0 +1,44 @@
+package simulations
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+
+	mcpsim "github.com/poiesic/wonda/internal/mcp/simulation"
+)
+
+// relationshipSituation introduces an agent's relationships in their prompt.
+const relationshipSituation = "\n\nHOW YOU FEEL ABOUT THE OTHERS:\n%s\nLet this color how you treat each of them: warmer and more open with friends, guarded with those you don't trust."
+
+// loadScenarioRelationships seeds the world with the relationships the
+// scenario starts with. Relationships carried in from a campaign replace them.
+func (s *Simulation) loadScenarioRelationships() {
+	loaded := 0
+	for from, toward := range s.Scenario.Relationships {
+		for to, rel := range toward {
+			s.World.SetRelationship(mcpsim.Relationship{
+				From:        from,
+				To:          to,
+				Value:       rel.Affinity,
+				Trust:       rel.Trust,
+				Familiarity: rel.Familiarity,
+				Reason:      rel.Reason,
+			})
+			loaded++
+		}
+	}
+	if loaded > 0 {
+		slog.Info("scenario relationships loaded", "relationships", loaded)
+	}
+}
+
+// relationshipNote returns the situation note describing how an agent feels
+// about the others, or "" when they have no relationships yet.
+func (s *Simulation) relationshipNote(agentName string) string {
+	notes := s.World.RelationshipNotes(agentName)
+	if len(notes) == 0 {
+		return ""
+	}
+	return fmt.Sprintf(relationshipSituation, "- "+strings.Join(notes, "\n- "))
+}
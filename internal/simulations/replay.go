@@ -0,0 +1,58 @@
+package simulations
+
+import (
+	"log/slog"
+
+	"github.com/poiesic/wonda/internal/chronicle"
+)
+
+// Replay compares a run's turns with those of a recorded run as they are
+// written, to pinpoint where a code change made a run that should play out
+// the same way, such as a dry run with the same script, behave differently.
+type Replay struct {
+	recorded   map[int]string // Recorded turn hashes by turn number
+	lastTurn   int            // Last recorded turn number
+	compared   int            // Last turn of this run compared
+	divergence int            // First turn whose hash differed; 0 while all match
+}
+
+// NewReplay creates a replay of a recorded run's turns.
+func NewReplay(turns []chronicle.Turn) (*Replay, error) {
+	recorded, err := chronicle.TurnHashes(turns)
+	if err != nil {
+		return nil, err
+	}
+	r := &Replay{recorded: recorded}
+	for number := range recorded {
+		r.lastTurn = max(r.lastTurn, number)
+	}
+	return r, nil
+}
+
+// check compares one of the run's turns, hashed, with the recording's.
+func (r *Replay) check(turn chronicle.Turn) {
+	r.compared = turn.Number
+	if r.divergence != 0 {
+		return
+	}
+	if recorded, ok := r.recorded[turn.Number]; !ok || recorded != turn.Hash {
+		r.divergence = turn.Number
+		slog.Warn("replay diverged from the recording", "turn", turn.Number, "recorded", recorded, "replayed", turn.Hash)
+	}
+}
+
+// Turns returns the number of turns the recorded run reached.
+func (r *Replay) Turns() int {
+	return r.lastTurn
+}
+
+// FirstDivergence returns the first turn where the run differed from the
+// recording, or 0 if every turn matched. A run that stopped short of the
+// recording diverges at the first turn it didn't reach, and one that went on
+// past it at the first turn the recording doesn't have.
+func (r *Replay) FirstDivergence() int {
+	if r.divergence == 0 && r.compared < r.lastTurn {
+		return r.compared + 1
+	}
+	return r.divergence
+}
@@ -0,0 +1,51 @@
+package simulations
+
+import (
+	"testing"
+
+	"github.com/poiesic/wonda/internal/chronicle"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReplay(t *testing.T) {
+	recorded := []chronicle.Turn{
+		{Type: "turn", Number: 1, Events: []chronicle.Event{{AgentName: "Alice", Dialogue: "Bella's?"}}},
+		{Type: "turn", Number: 2, Events: []chronicle.Event{{AgentName: "Bob", Dialogue: "Fine."}}},
+		{Type: "turn", Number: 3},
+	}
+	hashed := func(turn chronicle.Turn) chronicle.Turn {
+		hash, err := chronicle.HashTurn(turn)
+		require.NoError(t, err)
+		turn.Hash = hash
+		return turn
+	}
+	replay := func(turns ...chronicle.Turn) *Replay {
+		r, err := NewReplay(recorded)
+		require.NoError(t, err)
+		for _, turn := range turns {
+			r.check(hashed(turn))
+		}
+		return r
+	}
+
+	t.Run("matches a run that played out the same", func(t *testing.T) {
+		r := replay(recorded...)
+		assert.Zero(t, r.FirstDivergence())
+		assert.Equal(t, 3, r.Turns())
+	})
+
+	t.Run("finds the first turn that differs", func(t *testing.T) {
+		changed := chronicle.Turn{Type: "turn", Number: 2, Events: []chronicle.Event{{AgentName: "Bob", Dialogue: "No."}}}
+		r := replay(recorded[0], changed, chronicle.Turn{Type: "turn", Number: 3, Ambient: []string{"Rain"}})
+		assert.Equal(t, 2, r.FirstDivergence())
+	})
+
+	t.Run("diverges where a shorter run stopped", func(t *testing.T) {
+		assert.Equal(t, 3, replay(recorded[:2]...).FirstDivergence())
+	})
+
+	t.Run("diverges where a longer run went on", func(t *testing.T) {
+		assert.Equal(t, 4, replay(append(recorded, chronicle.Turn{Type: "turn", Number: 4})...).FirstDivergence())
+	})
+}
@@ -0,0 +1,109 @@
+package simulations
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/poiesic/wonda/internal/config"
+)
+
+// retryTransport retries requests to a provider that fail with a network error
+// or a retryable status, backing off exponentially between attempts, so a single
+// rate limit or server hiccup doesn't end the simulation.
+type retryTransport struct {
+	base     http.RoundTripper
+	provider string
+	policy   config.RetryPolicy
+}
+
+// newHTTPClient returns an HTTP client that retries requests to the provider
+// according to its retry policy.
+func newHTTPClient(provider *config.Provider) *http.Client {
+	return &http.Client{
+		Transport: &retryTransport{
+			base:     http.DefaultTransport,
+			provider: provider.Name,
+			policy:   provider.RetryPolicy(),
+		},
+	}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	delay := t.policy.Backoff
+	for retries := 0; ; retries++ {
+		resp, err := t.base.RoundTrip(req)
+		if retries >= t.policy.MaxRetries || !t.retryable(req, resp, err) {
+			if retries > 0 && err == nil {
+				slog.Info("LLM request retried", "provider", t.provider, "retries", retries, "status", resp.StatusCode)
+			}
+			return resp, err
+		}
+
+		wait := delay
+		var reason string
+		if err != nil {
+			reason = err.Error()
+		} else {
+			reason = resp.Status
+			if after := retryAfter(resp); after > 0 {
+				wait = after
+			}
+			// Drain the body so the connection can be reused
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		slog.Warn("retrying LLM request", "provider", t.provider, "reason", reason,
+			"retry", retries+1, "max_retries", t.policy.MaxRetries, "delay", wait)
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+		delay *= 2
+
+		// Each attempt needs a fresh copy of the body
+		next := req.Clone(ctx)
+		if req.Body != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			next.Body = body
+		}
+		req = next
+	}
+}
+
+// retryable reports whether a failed attempt is worth repeating: the request
+// can be replayed and it failed on the network or with a retryable status.
+func (t *retryTransport) retryable(req *http.Request, resp *http.Response, err error) bool {
+	if req.Context().Err() != nil {
+		return false
+	}
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return false
+	}
+	if err != nil {
+		return true
+	}
+	return slices.Contains(t.policy.RetryOnStatus, resp.StatusCode)
+}
+
+// retryAfter returns the delay a response asks for in its Retry-After header,
+// or 0 if it doesn't give one in seconds.
+func retryAfter(resp *http.Response) time.Duration {
+	seconds, err := strconv.Atoi(resp.Header.Get("Retry-After"))
+	if err != nil || seconds <= 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
@@ -0,0 +1,153 @@
+package simulations
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/poiesic/wonda/internal/config"
+)
+
+// guidedToolsInstruction tells a model under guided tool decoding what its
+// JSON output means, since the server no longer sees the tools natively.
+const guidedToolsInstruction = `Respond with a single JSON object. To speak or act without a tool, use {"content": "<your response>"}. To call a tool, use {"tool": "<tool name>", "arguments": {...}}.
+
+Available tools:
+%s`
+
+// guidesTools reports whether a request's tool use is enforced with guided
+// JSON decoding instead of the server's native tool calling.
+func (c *OpenAIClient) guidesTools(req ChatRequest) bool {
+	return c.sampling != nil && c.sampling.GuidedTools && len(req.Tools) > 0
+}
+
+// applySampling adds the model's vLLM or TGI extensions to an OpenAI-compatible
+// request body. Under guided tool decoding the tools are replaced by a JSON
+// schema the output must match and an instruction describing it.
+func (c *OpenAIClient) applySampling(reqBody map[string]interface{}, req ChatRequest) {
+	sampling := c.sampling
+	if sampling == nil {
+		return
+	}
+
+	switch c.providerType {
+	case config.ProviderTypeVLLM:
+		if sampling.TopK > 0 {
+			reqBody["top_k"] = sampling.TopK
+		}
+		if sampling.BestOf > 0 {
+			reqBody["best_of"] = sampling.BestOf
+		}
+		if sampling.GuidedGrammar != "" {
+			reqBody["guided_grammar"] = sampling.GuidedGrammar
+		}
+	case config.ProviderTypeTGI:
+		if sampling.GuidedGrammar != "" {
+			reqBody["response_format"] = map[string]interface{}{"type": "regex", "value": sampling.GuidedGrammar}
+		}
+	}
+
+	if !c.guidesTools(req) {
+		return
+	}
+
+	schema := guidedToolSchema(req.Tools)
+	delete(reqBody, "tools")
+	if messages, ok := reqBody["messages"].([]map[string]interface{}); ok {
+		reqBody["messages"] = append(messages, map[string]interface{}{
+			"role":    "system",
+			"content": fmt.Sprintf(guidedToolsInstruction, describeTools(req.Tools)),
+		})
+	}
+	switch c.providerType {
+	case config.ProviderTypeVLLM:
+		reqBody["guided_json"] = schema
+	case config.ProviderTypeTGI:
+		reqBody["response_format"] = map[string]interface{}{"type": "json", "value": schema}
+	}
+}
+
+// guidedToolSchema builds a JSON schema accepting either plain content or a
+// call to one of the tools with arguments matching its parameters.
+func guidedToolSchema(tools []map[string]interface{}) map[string]interface{} {
+	options := []interface{}{
+		map[string]interface{}{
+			"type":                 "object",
+			"properties":           map[string]interface{}{"content": map[string]interface{}{"type": "string"}},
+			"required":             []string{"content"},
+			"additionalProperties": false,
+		},
+	}
+	for _, tool := range tools {
+		name, parameters := toolFunction(tool)
+		if name == "" {
+			continue
+		}
+		if parameters == nil {
+			parameters = map[string]interface{}{"type": "object"}
+		}
+		options = append(options, map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"tool":      map[string]interface{}{"const": name},
+				"arguments": parameters,
+			},
+			"required":             []string{"tool", "arguments"},
+			"additionalProperties": false,
+		})
+	}
+	return map[string]interface{}{"anyOf": options}
+}
+
+// describeTools lists tools by name and description for the guided instruction.
+func describeTools(tools []map[string]interface{}) string {
+	var b strings.Builder
+	for _, tool := range tools {
+		name, _ := toolFunction(tool)
+		if name == "" {
+			continue
+		}
+		fn := tool["function"].(map[string]interface{})
+		description, _ := fn["description"].(string)
+		fmt.Fprintf(&b, "- %s: %s\n", name, description)
+	}
+	return b.String()
+}
+
+// toolFunction returns the name and parameter schema of a tool definition.
+func toolFunction(tool map[string]interface{}) (string, interface{}) {
+	fn, ok := tool["function"].(map[string]interface{})
+	if !ok {
+		return "", nil
+	}
+	name, _ := fn["name"].(string)
+	return name, fn["parameters"]
+}
+
+// parseGuidedOutput turns guided JSON output back into content or a tool call.
+// Output that isn't guided JSON is returned unchanged.
+func parseGuidedOutput(content string) (string, []ToolCall) {
+	var output struct {
+		Content   *string                `json:"content"`
+		Tool      string                 `json:"tool"`
+		Arguments map[string]interface{} `json:"arguments"`
+	}
+	if err := json.Unmarshal([]byte(strings.TrimSpace(content)), &output); err != nil {
+		return content, nil
+	}
+
+	if output.Tool != "" {
+		if output.Arguments == nil {
+			output.Arguments = make(map[string]interface{})
+		}
+		return "", []ToolCall{{
+			ID:        "guided-" + output.Tool,
+			Name:      output.Tool,
+			Arguments: output.Arguments,
+		}}
+	}
+	if output.Content != nil {
+		return *output.Content, nil
+	}
+	return content, nil
+}
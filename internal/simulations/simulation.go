@@ -4,23 +4,30 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"log/slog"
+	"maps"
 	"os"
 	"path"
 	"regexp"
+	"slices"
 	"strings"
+	"sync"
 	"text/template"
 	"time"
 
 	"github.com/oklog/ulid/v2"
 	"github.com/poiesic/wonda/internal/chronicle"
 	"github.com/poiesic/wonda/internal/config"
+	"github.com/poiesic/wonda/internal/guardrails"
 	"github.com/poiesic/wonda/internal/mcp"
 	mcpsim "github.com/poiesic/wonda/internal/mcp/simulation"
 	"github.com/poiesic/wonda/internal/memory"
 	"github.com/poiesic/wonda/internal/prompts"
 	"github.com/poiesic/wonda/internal/runtime"
 	"github.com/poiesic/wonda/internal/scenarios"
+	"github.com/poiesic/wonda/internal/usage"
+	"golang.org/x/sync/errgroup"
 )
 
 // Simulation represents a running instance of a scenario.
@@ -38,11 +45,129 @@ type Simulation struct {
 	World       *mcpsim.WorldState
 	MemoryStore *memory.Store
 
+	// Token usage per provider/model, written to the usage catalog when the run ends
+	Usage *usage.Tracker
+
+	// Chaos injects failures into LLM requests when set before Initialize
+	Chaos     *ChaosConfig
+	chaosRand *chaosRand
+
+	// Console is where people play human-controlled agents (default stdin and stdout)
+	Console *HumanConsole
+
+	// DryRun answers every LLM request with a MockClient when set before
+	// Initialize, so a scenario can be exercised without API keys or cost
+	DryRun *MockScript
+
+	// Stream writes agent utterances to the chronicle and hooks as they are generated
+	Stream bool
+
+	// Echo, when set, receives agent thinking and dialogue token by token as it streams in
+	Echo io.Writer
+
+	// Knowledge holds documents ingested for the scenario; when set before
+	// Initialize they are seeded as shared scene knowledge
+	Knowledge *memory.KnowledgeBase
+
+	// CiteMemories asks agents to tag the memory IDs behind what they say, and
+	// records the cited memories on their chronicle events (a debug mode)
+	CiteMemories bool
+
+	// ToolTranscript records every tool call agents make, with its arguments,
+	// result, latency and caller, in a JSONL file next to the chronicle (a debug mode)
+	ToolTranscript bool
+
+	// ChronicleChecksums ends every chronicle line with a CRC-32, so damage
+	// to the file can be detected when it is read
+	ChronicleChecksums bool
+
+	// Characters, by character name, used instead of loading them from the
+	// config directory when set before Initialize (e.g. generated fixtures)
+	Characters map[string]*scenarios.Character
+
+	// Clock tells the time for the chronicle's name and timestamps and the
+	// usage report (default: the system clock, or the dry run script's clock)
+	Clock chronicle.Clock
+
+	// OutputDir is where the chronicle and the files named after it are
+	// written (default: the working directory)
+	OutputDir string
+
+	// Speed trades fidelity for speed: turns, tool budgets and memory results (nil is balanced)
+	Speed *SpeedProfile
+
+	// Temperature, when set before Initialize, overrides the sampling
+	// temperature of every model the run uses (e.g. in a batch sweep)
+	Temperature *float64
+
+	// Replay, when set before Start, compares each turn with a recorded run's
+	// so the first turn where this run plays out differently is reported
+	Replay *Replay
+
+	// Scenario file and raw definition, recorded in checkpoints so the run can be resumed
+	ScenarioFile   string
+	ScenarioSource string
+
+	// Checkpoint to continue from, set by Resume
+	resumeFrom *Checkpoint
+
 	// Chronicle
-	chroniclePath          string                   // Path to chronicle JSONL file
-	chronicleFile          *os.File                 // Open file handle for appending
-	currentTurnEvents      []chronicle.Event        // Events being collected for current turn
-	currentGoalCompletions []chronicle.GoalCompletion // Goal completions for current turn
+	chroniclePath          string                      // Path to chronicle JSONL file
+	outcomesPath           string                      // Path to outcomes JSON file, once written
+	chronicleWriter        *chronicle.Writer           // Open chronicle, synced after every turn
+	currentTurnEvents      []chronicle.Event           // Events being collected for current turn
+	currentGoalCompletions []chronicle.GoalCompletion  // Goal completions for current turn
+	currentAmbient         []string                    // Ambient events for current turn
+	currentSkips           []chronicle.PhaseSkip       // Phases and agent turns skipped this turn
+	currentCondition       []chronicle.ConditionChange // Condition changes this turn
+	currentForecasts       []chronicle.GoalForecast    // Turn budget projections this turn
+	currentInjected        []chronicle.Injection       // What the director brought in this turn
+	currentTieBreaks       []chronicle.TieBreak        // Tied votes broken this turn
+	currentNudge           string                      // Director's nudge to the deciding agents this turn
+
+	// Random ambient events from the scenario's environment (nil when not configured)
+	ambience *ambience
+
+	// Judges for goals completed by rubric, by goal name
+	goalJudges map[string]*goalJudge
+
+	// Judge that writes post-mortems for failed runs (nil when there is no judge model)
+	postMortemJudge *goalJudge
+
+	// Model that steers the scene between turns (nil when the scenario has no director)
+	director *director
+
+	// Model that summarizes old episodic memories (nil when the scenario doesn't compact them)
+	compactor *compactor
+
+	// Serializes writes to the tool transcript
+	toolTranscriptMu sync.Mutex
+
+	// When this run started, and how long the simulation had already run
+	// before it (when resumed), for goal deadlines
+	runStart      time.Time
+	runTimeBefore time.Duration
+
+	// Prompt templates agents are given, with the config directory's and scenario's overrides
+	prompts *prompts.Library
+
+	// Refusals per agent, for the end-of-run summary
+	refusalCounts map[string]int
+
+	// Model failures in a row covered by fallbacks, by agent
+	fallbacks map[string]int
+
+	// Turn budget: each goal's support by turn, and the deadlines of goals
+	// projected to miss them, by goal name
+	convergence map[string][]float64
+	atRisk      map[string]int
+
+	// llama-server processes serving local models, by provider name; stopped by Close
+	llamaMu      sync.Mutex
+	llamaServers map[string]*llamaServer
+
+	// Callbacks registered by host applications
+	hooks hooks
 }
 
 // NewSimulation creates a new simulation from a scenario.
@@ -56,10 +181,25 @@ func NewSimulation(scenario *scenarios.Scenario, configDir string) *Simulation {
 		scenario.Basics.Atmosphere,
 	)
 
+	world.SetReasoningShared(scenario.ReasoningShared())
+	for name, location := range scenario.Locations {
+		world.AddPlace(name, location.Description, location.Exits)
+	}
+	for name, object := range scenario.Objects {
+		world.AddObject(mcpsim.WorldObject{
+			Name:        name,
+			Description: object.Description,
+			Details:     object.Details,
+			Position:    object.Location,
+			Holder:      object.Holder,
+			Fixed:       object.Fixed,
+		})
+	}
+
 	// Create MCP server with simulation tools
 	mcpServer := mcpsim.NewSimulationServer(world)
 
-	return &Simulation{
+	sim := &Simulation{
 		ID:        id,
 		Scenario:  scenario,
 		Agents:    make(map[string]*Agent),
@@ -67,11 +207,50 @@ func NewSimulation(scenario *scenarios.Scenario, configDir string) *Simulation {
 		TurnOrder: make([]string, 0),
 		MCPServer: mcpServer,
 		World:     world,
+		Usage:     usage.NewTracker(),
+		Clock:     chronicle.SystemClock{},
+
+		goalJudges:    make(map[string]*goalJudge),
+		refusalCounts: make(map[string]int),
+		fallbacks:     make(map[string]int),
+		convergence:   make(map[string][]float64),
+		atRisk:        make(map[string]int),
 	}
+	if scenario.Environment != nil {
+		sim.ambience = newAmbience(scenario.Environment)
+	}
+	return sim
 }
 
 // Initialize sets up the simulation by loading characters and creating agents.
-func (s *Simulation) Initialize(ctx context.Context) error {
+func (s *Simulation) Initialize(ctx context.Context) (err error) {
+	// Don't leave local model servers running if the run can't start
+	defer func() {
+		if err != nil {
+			s.Close()
+		}
+	}()
+
+	if s.Chaos != nil {
+		s.chaosRand = newChaosRand(s.Chaos.Seed)
+		slog.Warn("chaos mode enabled",
+			"errors", s.Chaos.ErrorRate,
+			"slow", s.Chaos.SlowRate,
+			"delay", s.Chaos.SlowDelay,
+			"malformed", s.Chaos.MalformedRate,
+			"truncate", s.Chaos.TruncateRate)
+	}
+
+	if s.DryRun != nil {
+		slog.Warn("dry run: LLM requests are answered by a mock client", "seed", s.DryRun.Seed, "scripted_agents", len(s.DryRun.Agents))
+		if !s.DryRun.Clock.IsZero() {
+			s.Clock = chronicle.FixedClock{Time: s.DryRun.Clock}
+		}
+	}
+
+	speed := s.speed()
+	slog.Info("speed profile", "name", speed.Name, "max_turns", speed.MaxTurns, "tool_iterations", speed.MaxToolIterations, "memory_results", speed.MemoryResults)
+
 	// Load providers configuration
 	providersPath := path.Join(s.ConfigDir, "providers.toml")
 	providers, err := config.LoadProvidersFromFile(providersPath)
@@ -79,18 +258,58 @@ func (s *Simulation) Initialize(ctx context.Context) error {
 		return fmt.Errorf("failed to load providers: %w", err)
 	}
 
+	// Load models configuration
+	modelsDir := path.Join(s.ConfigDir, "models")
+	models, err := config.LoadModelsFromDir(modelsDir)
+	if err != nil {
+		return fmt.Errorf("failed to load models: %w", err)
+	}
+
+	// Prompt overrides are checked before any time is spent on providers
+	if s.prompts, err = s.loadPrompts(); err != nil {
+		return err
+	}
+
+	// Local GGUF models get a provider for the llama-server run for them
+	if err := registerLlamaServers(models, providers); err != nil {
+		return fmt.Errorf("failed to load models: %w", err)
+	}
+
+	// Fail fast on bad credentials or model names before embedding and seeding
+	if s.DryRun == nil {
+		if err := s.preflight(ctx, models, providers); err != nil {
+			return err
+		}
+	}
+
 	// Initialize memory store with ONNX embeddings (internal implementation)
 	slog.Info("initializing memory store", "type", "in-process embeddings")
 
+	// The scenario may choose the embedding, e.g. a multilingual model
+	embedding, err := selectEmbedding(providersPath, s.Scenario)
+	if err != nil {
+		return err
+	}
+	s.warnUnsupportedLanguages(embedding)
+
 	// Use ~/.config/wonda/models for embedding model cache
 	modelsCache := path.Join(s.ConfigDir, "models")
-	embedder, err := memory.NewONNXEmbedderWithDownload(modelsCache, "")
+	embedder, err := newEmbedder(modelsCache, embedding)
 	if err != nil {
 		return fmt.Errorf("failed to initialize embeddings: %w", err)
 	}
 
-	s.MemoryStore = memory.NewStore(embedder)
-	slog.Info("memory store ready", "dimensions", 768)
+	// Similarity and language settings come from the embedding, if any
+	storeOptions, err := s.storeOptions(embedding)
+	if err != nil {
+		return err
+	}
+
+	s.MemoryStore, err = s.newMemoryStore(embedder, embedding, providers, storeOptions)
+	if err != nil {
+		return err
+	}
+	slog.Info("memory store ready", "dimensions", embedder.Dimensions(), "metric", storeOptions.Metric, "normalize", storeOptions.Normalize, "language", storeOptions.Language, "cross_lingual", storeOptions.CrossLingual)
 
 	// Seed scenario context (shared across all agents)
 	slog.Info("seeding scenario memories")
@@ -99,113 +318,288 @@ func (s *Simulation) Initialize(ctx context.Context) error {
 	}
 	slog.Info("seeded scenario memories", "count", s.MemoryStore.CountByFilter(memory.Filter{Type: "scene"}))
 
-	// Load models configuration
-	modelsDir := path.Join(s.ConfigDir, "models")
-	models, err := config.LoadModelsFromDir(modelsDir)
-	if err != nil {
-		return fmt.Errorf("failed to load models: %w", err)
+	// Seed documents ingested for the scenario
+	if s.Knowledge != nil && len(s.Knowledge.Chunks) > 0 {
+		if err := memory.SeedKnowledge(ctx, s.MemoryStore, s.Knowledge, embeddingModel(embedding)); err != nil {
+			return fmt.Errorf("failed to seed knowledge: %w", err)
+		}
+		slog.Info("seeded scenario knowledge", "chunks", len(s.Knowledge.Chunks), "model", s.Knowledge.Model)
+	}
+
+	// Build content policy filters shared by all agents
+	var guardFilters []guardrails.Filter
+	if s.Scenario.Guardrails != nil && len(s.Scenario.Guardrails.Patterns) > 0 {
+		regexFilter, err := guardrails.NewRegexFilter(s.Scenario.Guardrails.Patterns)
+		if err != nil {
+			return fmt.Errorf("failed to create guardrails: %w", err)
+		}
+		guardFilters = append(guardFilters, regexFilter)
 	}
 
-	// Create agents from scenario
-	for agentName, agentConfig := range s.Scenario.Agents {
-		// Load character definition
+	// People play their agents from the terminal unless told otherwise
+	if humans := s.Scenario.Humans(); len(humans) > 0 {
+		if s.Console == nil {
+			s.Console = NewHumanConsole(os.Stdin, os.Stdout)
+		}
+		slog.Info("human-controlled agents", "agents", humans)
+	}
+
+	// Load every character up front, as each agent learns about the others
+	agentNames := slices.Sorted(maps.Keys(s.Scenario.Agents))
+	characters := make(map[string]*scenarios.Character, len(agentNames))
+	for _, agentName := range agentNames {
+		agentConfig := s.Scenario.Agents[agentName]
+		if character, ok := s.Characters[agentConfig.Character]; ok {
+			characters[agentName] = character
+			continue
+		}
 		characterPath := path.Join(s.ConfigDir, "characters", agentConfig.Character+".toml")
 		character, err := scenarios.LoadCharacterFromFile(characterPath)
 		if err != nil {
 			return fmt.Errorf("failed to load character %s for agent %s: %w", agentConfig.Character, agentName, err)
 		}
+		characters[agentName] = character
+	}
 
-		// Determine which model to use
-		modelName := agentConfig.Model
-		// Use scenario defaults if not specified at agent level
-		if modelName == "" && s.Scenario.Basics.Defaults != nil {
-			modelName = s.Scenario.Basics.Defaults.Model
-		}
-		if modelName == "" {
-			return fmt.Errorf("agent %s missing model configuration", agentName)
-		}
+	// Create agents and seed their memories in parallel; embedding is the slow part
+	agents := make([]*Agent, len(agentNames))
+	group, groupCtx := errgroup.WithContext(ctx)
+	group.SetLimit(agentInitConcurrency)
+	for i, agentName := range agentNames {
+		group.Go(func() error {
+			agent, err := s.createAgent(groupCtx, agentName, characters, models, providers, guardFilters)
+			agents[i] = agent
+			return err
+		})
+	}
+	if err := group.Wait(); err != nil {
+		return err
+	}
 
-		// Get model config
-		model, ok := models[modelName]
-		if !ok {
-			return fmt.Errorf("model %s not found for agent %s", modelName, agentName)
-		}
+	// Register agents in a stable turn order, whichever finished first
+	for i, agentName := range agentNames {
+		agent := agents[i]
+		s.Agents[agentName] = agent
+		s.TurnOrder = append(s.TurnOrder, agentName)
 
-		// Get provider from model config
-		providerName := model.Provider
-		if providerName == "" {
-			return fmt.Errorf("model %s does not specify a provider", modelName)
+		// Register agent in world state
+		s.World.AddAgent(agentName, agent.State.Position)
+		if s.Scenario.Agents[agentName].Observer {
+			s.World.SetObserver(agentName)
 		}
+		s.World.SetCondition(agentName, agent.State.Condition)
+		s.World.SetEmotion(agentName, agent.State.Emotion, agent.State.EmotionIntensity)
+	}
 
-		provider, ok := providers.Providers[providerName]
-		if !ok {
-			return fmt.Errorf("provider %s (from model %s) not found for agent %s", providerName, modelName, agentName)
-		}
+	slog.Info("memory store initialized", "total_memories", s.MemoryStore.Count())
+
+	// Start from the scenario's relationships, then carry relationships in
+	// from earlier scenarios of the campaign
+	s.loadScenarioRelationships()
+	if err := s.loadCampaignRelationships(); err != nil {
+		return err
+	}
+
+	// Create judges for goals completed by rubric
+	if err := s.initializeGoalJudges(models, providers); err != nil {
+		return err
+	}
+	if err := s.initializePostMortemJudge(models, providers); err != nil {
+		return err
+	}
+	if err := s.initializeDirector(models, providers); err != nil {
+		return err
+	}
+	if err := s.initializeCompactor(models, providers); err != nil {
+		return err
+	}
+
+	// Register memory tools with MCP server
+	s.MCPServer.RegisterTool(mcpsim.NewQuerySelfTool(s.MemoryStore, s.memorySearch("query_self")))
+	s.MCPServer.RegisterTool(mcpsim.NewQueryBackgroundTool(s.MemoryStore, s.memorySearch("query_background")))
+	s.MCPServer.RegisterTool(mcpsim.NewQueryCommunicationStyleTool(s.MemoryStore, s.memorySearch("query_communication_style")))
+	s.MCPServer.RegisterTool(mcpsim.NewQuerySceneTool(s.MemoryStore, s.memorySearch("query_scene")))
+	s.MCPServer.RegisterTool(mcpsim.NewQueryCharacterTool(s.MemoryStore, s.memorySearch("query_character")))
+	s.MCPServer.RegisterTool(mcpsim.NewQueryMemoryTool(s.MemoryStore, s.memorySearch("query_memory")))
+	if s.Knowledge != nil && len(s.Knowledge.Chunks) > 0 {
+		s.MCPServer.RegisterTool(mcpsim.NewQueryKnowledgeTool(s.MemoryStore, s.memorySearch("query_knowledge")))
+	}
+	if s.Scenario.Condition != nil {
+		s.MCPServer.RegisterTool(mcpsim.NewRestTool(s.World, s.Scenario.Condition.RestRecovery))
+	}
+	if len(s.Scenario.Locations) > 0 {
+		s.MCPServer.RegisterTool(mcpsim.NewMoveToTool(s.World))
+		s.MCPServer.RegisterTool(mcpsim.NewLookAroundTool(s.World))
+	}
+	if len(s.Scenario.Objects) > 0 {
+		s.MCPServer.RegisterTool(mcpsim.NewPickUpTool(s.World))
+		s.MCPServer.RegisterTool(mcpsim.NewGiveTool(s.World))
+		s.MCPServer.RegisterTool(mcpsim.NewInspectObjectTool(s.World))
+	}
+
+	return nil
+}
 
-		// Create LLM client
-		client, err := NewClient(provider, model)
+// agentInitConcurrency bounds how many agents are created and seeded at once,
+// so a local embedding model or server isn't swamped.
+const agentInitConcurrency = 4
+
+// createAgent creates an agent with its client and guardrails, and seeds its
+// memories of its own character and of the others. It is safe to call for
+// several agents at once.
+func (s *Simulation) createAgent(ctx context.Context, agentName string, characters map[string]*scenarios.Character,
+	models map[string]*config.Model, providers *config.Providers, guardFilters []guardrails.Filter) (*Agent, error) {
+	agentConfig := s.Scenario.Agents[agentName]
+	character := characters[agentName]
+
+	// People play their agents from the console; models need a client
+	var agent *Agent
+	if agentConfig.Human() {
+		agent = s.newHumanAgent(agentName, character)
+	} else {
+		var err error
+		agent, err = s.newModelAgent(agentName, agentConfig, character, models, providers, guardFilters)
 		if err != nil {
-			return fmt.Errorf("failed to create client for agent %s: %w", agentName, err)
+			return nil, err
 		}
+	}
 
-		// Create agent
-		// Use model.Name (API model ID) instead of modelName (map key)
-		agent := NewAgent(agentName, character, client, providerName, model.Name)
+	// Apply initial state overrides from scenario
+	agent.ApplyInitialState(agentConfig.Initial)
 
-		// Apply initial state overrides from scenario
-		agent.ApplyInitialState(agentConfig.Initial)
+	// Seed character memories for this agent
+	slog.Debug("seeding agent memories", "agent", agentName)
+	if err := memory.SeedCharacter(ctx, s.MemoryStore, agentName, character); err != nil {
+		return nil, fmt.Errorf("failed to seed character memories for %s: %w", agentName, err)
+	}
 
-		// Seed character memories for this agent
-		slog.Debug("seeding agent memories", "agent", agentName)
-		if err := memory.SeedCharacter(ctx, s.MemoryStore, agentName, character); err != nil {
-			return fmt.Errorf("failed to seed character memories for %s: %w", agentName, err)
+	// Seed knowledge about the other characters
+	for otherAgentName, otherCharacter := range characters {
+		if otherAgentName == agentName {
+			continue
+		}
+		if err := memory.SeedOtherCharacter(ctx, s.MemoryStore, agentName, otherAgentName, otherCharacter); err != nil {
+			return nil, fmt.Errorf("failed to seed knowledge about %s for %s: %w", otherAgentName, agentName, err)
 		}
+	}
 
-		// Store agent
-		s.Agents[agentName] = agent
+	slog.Info("agent initialized", "agent", agentName, "character", agentConfig.Character, "provider", agent.Provider, "model", agent.Model)
+	return agent, nil
+}
 
-		// Add to turn order
-		s.TurnOrder = append(s.TurnOrder, agentName)
+// newModelAgent creates an agent played by its model, with the client,
+// ensemble, refusal handling and guardrails the scenario gives it.
+func (s *Simulation) newModelAgent(agentName string, agentConfig *scenarios.Agent, character *scenarios.Character,
+	models map[string]*config.Model, providers *config.Providers, guardFilters []guardrails.Filter) (*Agent, error) {
+	// Determine which model to use
+	modelName, err := s.agentModelName(agentName, agentConfig)
+	if err != nil {
+		return nil, err
+	}
 
-		// Register agent in world state
-		s.World.AddAgent(agentName, agent.State.Position)
+	// Get model config
+	model, ok := models[modelName]
+	if !ok {
+		return nil, fmt.Errorf("model %s not found for agent %s", modelName, agentName)
+	}
 
-		slog.Info("agent initialized", "agent", agentName, "character", agentConfig.Character, "provider", providerName, "model", modelName)
+	// Get provider from model config
+	providerName := model.Provider
+	if providerName == "" {
+		return nil, fmt.Errorf("model %s does not specify a provider", modelName)
 	}
 
-	// Seed knowledge about other characters for each agent
-	slog.Info("seeding inter-character knowledge")
-	for agentName := range s.Scenario.Agents {
-		for otherAgentName, otherAgentConfig := range s.Scenario.Agents {
-			if agentName == otherAgentName {
-				continue
-			}
+	provider, ok := providers.Providers[providerName]
+	if !ok {
+		return nil, fmt.Errorf("provider %s (from model %s) not found for agent %s", providerName, modelName, agentName)
+	}
 
-			// Load other character
-			otherCharacterPath := path.Join(s.ConfigDir, "characters", otherAgentConfig.Character+".toml")
-			otherCharacter, err := scenarios.LoadCharacterFromFile(otherCharacterPath)
-			if err != nil {
-				return fmt.Errorf("failed to load character %s: %w", otherAgentConfig.Character, err)
-			}
+	// Create LLM client
+	client, err := s.newClient(agentName, provider, model)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create client for agent %s: %w", agentName, err)
+	}
+	if err := checkGeneration(provider, model.Name, model.Generation.Merge(agentConfig.Generation)); err != nil {
+		return nil, fmt.Errorf("agent %s: %w", agentName, err)
+	}
 
-			// Seed knowledge
-			if err := memory.SeedOtherCharacter(ctx, s.MemoryStore, agentName, otherAgentName, otherCharacter); err != nil {
-				return fmt.Errorf("failed to seed knowledge about %s for %s: %w", otherAgentName, agentName, err)
+	// Wrap in an ensemble if the agent samples multiple responses per turn;
+	// a dry run's mock members would all answer alike
+	if agentConfig.Ensemble != nil && s.DryRun == nil {
+		ensemble, err := newEnsembleClientFromConfig(agentConfig.Ensemble, modelName, func(name string) (Client, error) {
+			m, ok := models[name]
+			if !ok {
+				return nil, fmt.Errorf("model %s not found", name)
+			}
+			p, ok := providers.Providers[m.Provider]
+			if !ok {
+				return nil, fmt.Errorf("provider %s (from model %s) not found", m.Provider, name)
 			}
+			if err := checkGeneration(p, m.Name, m.Generation.Merge(agentConfig.Generation)); err != nil {
+				return nil, err
+			}
+			return s.newClient(agentName, p, m)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create ensemble for agent %s: %w", agentName, err)
 		}
+		client = ensemble
+		slog.Info("agent ensemble enabled", "agent", agentName, "members", len(ensemble.members), "selector", ensemble.selector)
 	}
 
-	slog.Info("memory store initialized", "total_memories", s.MemoryStore.Count())
+	// Create agent
+	// Use model.Name (API model ID) instead of modelName (map key)
+	agent := NewAgent(agentName, character, client, providerName, model.Name)
+	agent.MaxToolIterations = s.speed().MaxToolIterations
+	agent.Prompts = s.prompts
+
+	// The agent's generation parameters apply over its model's, save a swept
+	// temperature, which applies to every model
+	agent.Generation = agentConfig.Generation
+	if s.Temperature != nil {
+		agent.Generation.Temperature = s.Temperature
+	}
 
-	// Register memory tools with MCP server
-	s.MCPServer.RegisterTool(mcpsim.NewQuerySelfTool(s.MemoryStore))
-	s.MCPServer.RegisterTool(mcpsim.NewQueryBackgroundTool(s.MemoryStore))
-	s.MCPServer.RegisterTool(mcpsim.NewQueryCommunicationStyleTool(s.MemoryStore))
-	s.MCPServer.RegisterTool(mcpsim.NewQuerySceneTool(s.MemoryStore))
-	s.MCPServer.RegisterTool(mcpsim.NewQueryCharacterTool(s.MemoryStore))
-	s.MCPServer.RegisterTool(mcpsim.NewQueryMemoryTool(s.MemoryStore))
+	// Retry refusals when the scenario asks for it
+	if s.Scenario.Refusals != nil {
+		agent.RefusalRetries = *s.Scenario.Refusals.Retries
+	}
 
-	return nil
+	// Apply content policy guardrails; moderation calls the provider, so a dry
+	// run keeps only the patterns
+	if s.Scenario.Guardrails != nil {
+		guardConfig := s.Scenario.Guardrails
+		if s.DryRun != nil && guardConfig.Moderation {
+			dryRunConfig := *guardConfig
+			dryRunConfig.Moderation = false
+			guardConfig = &dryRunConfig
+		}
+		guard, err := newAgentGuard(guardConfig, guardFilters, provider)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create guardrails for agent %s: %w", agentName, err)
+		}
+		agent.Guard = guard
+	}
+
+	return agent, nil
+}
+
+// memorySearch returns the scenario's result limit and relevance threshold for a memory tool.
+func (s *Simulation) memorySearch(toolName string) mcpsim.SearchOptions {
+	if s.Scenario.Memory == nil {
+		return mcpsim.SearchOptions{}
+	}
+	topK, minRelevance := s.Scenario.Memory.ToolSettings(toolName)
+	search := mcpsim.SearchOptions{TopK: topK, MinRelevance: minRelevance}
+	if toolName == "query_memory" && s.Scenario.Memory.Working != nil {
+		search.WorkingFrom = func() int { return s.workingFrom(s.World.Turn()) }
+	}
+	return search
+}
+
+// ChroniclePath returns the path of the chronicle file, once Start has created it.
+func (s *Simulation) ChroniclePath() string {
+	return s.chroniclePath
 }
 
 // initializeChronicle creates the chronicle file and writes the metadata line.
@@ -213,31 +607,42 @@ func (s *Simulation) initializeChronicle() error {
 	// Generate chronicle filename
 	s.chroniclePath = s.getChronicleFilename()
 
-	// Create/open file for writing (append mode)
-	file, err := os.Create(s.chroniclePath)
+	writer, err := chronicle.Create(s.chroniclePath, s.ChronicleChecksums)
 	if err != nil {
 		return fmt.Errorf("failed to create chronicle file: %w", err)
 	}
-	s.chronicleFile = file
+	s.chronicleWriter = writer
 
 	// Create metadata
 	metadata := chronicle.NewMetadata(
+		s.Clock,
 		s.ID,
 		s.Scenario.Basics.Name,
 		s.Scenario.Basics.Location,
 		s.Scenario.Basics.TOD,
 		s.Scenario.Basics.Atmosphere,
 	)
-
-	// Write metadata as first JSONL line
-	jsonBytes, err := chronicle.ToJSON(metadata)
-	if err != nil {
-		return fmt.Errorf("failed to marshal metadata: %w", err)
+	metadata.MaxTurns = s.MaxTurns()
+	metadata.DryRun = s.DryRun != nil
+	metadata.ReasoningShared = s.World.Snapshot().ReasoningShared
+	for name, agent := range s.Agents {
+		if agent.Character == nil || agent.Character.Presentation == nil {
+			continue
+		}
+		if metadata.Characters == nil {
+			metadata.Characters = make(map[string]chronicle.CharacterProfile)
+		}
+		p := agent.Character.Presentation
+		metadata.Characters[name] = chronicle.CharacterProfile{Pronouns: p.Pronouns, Age: p.Age, Portrait: p.Portrait, Bio: p.Bio}
 	}
 
-	if _, err := s.chronicleFile.WriteString(string(jsonBytes) + "\n"); err != nil {
+	// Write metadata as first JSONL line
+	if err := s.chronicleWriter.Write(metadata); err != nil {
 		return fmt.Errorf("failed to write metadata: %w", err)
 	}
+	if err := s.chronicleWriter.Sync(); err != nil {
+		return fmt.Errorf("failed to sync chronicle: %w", err)
+	}
 
 	return nil
 }
@@ -290,6 +695,12 @@ func cleanDialogue(dialogue string) string {
 
 // captureEvent adds an event to the current turn's event list.
 func (s *Simulation) captureEvent(agentName, dialogue, reasoning, msgType string) {
+	// Look up the reason given for it before cleaning changes the words
+	var rationale string
+	if dialogue != "" {
+		rationale = s.World.RationaleFor(agentName, dialogue)
+	}
+
 	// Clean the dialogue to remove artifacts
 	dialogue = cleanDialogue(dialogue)
 
@@ -300,37 +711,118 @@ func (s *Simulation) captureEvent(agentName, dialogue, reasoning, msgType string
 		Type:      msgType,
 		Dialogue:  dialogue,
 		Reasoning: reasoning,
+		Topics:    s.World.FocusTags(agentName),
+		Goal:      s.World.DiscussedGoal(agentName),
+		Rationale: rationale,
 	}
 
 	// Capture emotion if available
 	if agent != nil {
+		before := chronicle.EmotionState{
+			Emotion:   agent.State.Emotion,
+			Intensity: agent.State.EmotionIntensity,
+		}
+		// Agents may have updated how they feel; carry it into their next prompt
+		emotion, intensity, cause := s.World.Emotion(agentName)
+		if emotion != "" {
+			agent.State.Emotion = emotion
+			agent.State.EmotionIntensity = intensity
+		}
 		event.Emotion = &chronicle.AgentEmotion{
-			Before: chronicle.EmotionState{
-				Emotion:   agent.State.Emotion,
-				Intensity: agent.State.EmotionIntensity,
-			},
+			Before: before,
 			After: chronicle.EmotionState{
 				Emotion:   agent.State.Emotion,
 				Intensity: agent.State.EmotionIntensity,
 			},
 		}
+		if event.Emotion.After != before {
+			event.Emotion.Cause = cause
+		}
 	}
 
 	s.currentTurnEvents = append(s.currentTurnEvents, event)
 }
 
-// captureGoalCompletionsForTurn scans for goals that were completed or failed this turn.
-func (s *Simulation) captureGoalCompletionsForTurn(turn int) {
-	for goalName, goal := range s.World.Goals {
+// captureToolDialogue records dialogue from a tool call, with the proposal
+// it made or the vote it cast.
+func (s *Simulation) captureToolDialogue(msg mcpsim.ConversationMessage) {
+	s.captureEvent(msg.AgentName, msg.Content, "", string(msg.Type))
+	event := &s.currentTurnEvents[len(s.currentTurnEvents)-1]
+	if msg.Goal != "" {
+		event.Goal = msg.Goal
+	}
+	switch {
+	case msg.Vote != "":
+		event.Votes = []chronicle.Vote{{ProposalID: msg.ProposalID, Choice: msg.Vote}}
+		event.Entries = append(event.Entries, chronicle.Entry{Kind: chronicle.EntryVote, Goal: msg.Goal, ProposalID: msg.ProposalID, Choice: msg.Vote})
+	case msg.ProposalID != "":
+		event.Proposals = []string{msg.Proposal}
+		event.ProposalIDs = []string{msg.ProposalID}
+		event.Entries = append(event.Entries, chronicle.Entry{Kind: chronicle.EntryProposal, Goal: msg.Goal, ProposalID: msg.ProposalID, Proposal: msg.Proposal})
+	}
+	if msg.Type == mcpsim.MessageTypeWhisper {
+		event.Recipient = msg.Recipient
+		event.Visibility = chronicle.VisibilityPrivate
+	}
+}
+
+// skipPhase records that a phase, or one agent's turn in it when agentName
+// is set, was skipped because its preconditions weren't met.
+func (s *Simulation) skipPhase(phase mcpsim.Phase, agentName, reason string) {
+	if agentName == "" {
+		slog.Info("skipping phase", "phase", phase, "reason", reason)
+	} else {
+		slog.Debug("skipping agent turn", "agent", agentName, "phase", phase, "reason", reason)
+	}
+	s.currentSkips = append(s.currentSkips, chronicle.PhaseSkip{
+		Phase:     string(phase),
+		AgentName: agentName,
+		Reason:    reason,
+	})
+}
+
+// captureCandidates attaches ensemble candidates to the most recently captured event.
+func (s *Simulation) captureCandidates(candidates []EnsembleCandidate) {
+	if len(candidates) == 0 || len(s.currentTurnEvents) == 0 {
+		return
+	}
+
+	event := &s.currentTurnEvents[len(s.currentTurnEvents)-1]
+	for _, candidate := range candidates {
+		var toolCalls []string
+		for _, tc := range candidate.Response.ToolCalls {
+			toolCalls = append(toolCalls, formatToolCall(tc))
+		}
+		event.Candidates = append(event.Candidates, chronicle.Candidate{
+			Model:     candidate.Model,
+			Dialogue:  cleanDialogue(candidate.Response.Message),
+			Reasoning: candidate.Response.Thinking,
+			ToolCalls: toolCalls,
+			Selected:  candidate.Selected,
+		})
+	}
+}
+
+// captureGoalCompletionsForTurn scans for goals that were completed or failed this turn
+// and returns the completions it captured. Goals already captured this turn are
+// skipped, and individual goals are captured by captureIndividualCompletions instead.
+func (s *Simulation) captureGoalCompletionsForTurn(turn int) []chronicle.GoalCompletion {
+	world := s.World.Snapshot()
+	captured := len(s.currentGoalCompletions)
+	for goalName, goal := range world.Goals {
+		if goal.Individual() {
+			continue
+		}
+
 		// Only capture goals that changed status this turn
-		if goal.CompletedAt != turn {
+		if goal.CompletedAt != turn || s.completedThisTurn(goalName) {
 			continue
 		}
 
 		// Find the accepted proposal
 		for _, proposal := range goal.Proposals {
 			if proposal.Status == mcpsim.ProposalAccepted {
-				// Collect voters
+				// Collect voters, in name order so identical runs record them alike
 				votedYes := []string{}
 				votedNo := []string{}
 				for agentName, vote := range proposal.Votes {
@@ -340,26 +832,94 @@ func (s *Simulation) captureGoalCompletionsForTurn(turn int) {
 						votedNo = append(votedNo, agentName)
 					}
 				}
+				slices.Sort(votedYes)
+				slices.Sort(votedNo)
 
 				// Capture the completion
-				s.currentGoalCompletions = append(s.currentGoalCompletions, chronicle.GoalCompletion{
-					GoalName:    goalName,
-					Status:      string(goal.Status),
-					Solution:    proposal.Description,
-					ProposedBy:  proposal.ProposedBy,
-					VotedYes:    votedYes,
-					VotedNo:     votedNo,
-					CompletedAt: turn,
-				})
+				completion := chronicle.GoalCompletion{
+					GoalName:     goalName,
+					Status:       string(goal.Status),
+					Solution:     proposal.Description,
+					ProposedBy:   proposal.ProposedBy,
+					VotedYes:     votedYes,
+					VotedNo:      votedNo,
+					CompletedAt:  turn,
+					Support:      proposal.Support(len(world.GoalParticipants(goal))),
+					Allocation:   proposal.Allocation,
+					RankedChoice: goal.RankedChoice,
+				}
+				if goal.RankedChoice {
+					completion.Rankings = goal.Rankings
+					completion.Runoff = runoffRounds(goal.Runoff)
+				}
+				if goal.Negotiation != nil {
+					completion.Offers = negotiationOffers(goal.Offers())
+				}
+				s.currentGoalCompletions = append(s.currentGoalCompletions, completion)
+				s.captureCompletionEntry(proposal.ProposedBy, completion)
 				break // Only one accepted proposal per goal
 			}
 		}
 	}
+	return s.currentGoalCompletions[captured:]
+}
+
+// completedThisTurn reports whether a goal's completion has already been captured this turn.
+func (s *Simulation) completedThisTurn(goalName string) bool {
+	return slices.ContainsFunc(s.currentGoalCompletions, func(completion chronicle.GoalCompletion) bool {
+		return completion.GoalName == goalName
+	})
+}
+
+// negotiationOffers converts a negotiation's offers for the chronicle.
+func negotiationOffers(offers []mcpsim.Offer) []chronicle.Offer {
+	converted := make([]chronicle.Offer, len(offers))
+	for i, offer := range offers {
+		converted[i] = chronicle.Offer{
+			ProposalID: offer.ProposalID,
+			Side:       offer.Side,
+			ProposedBy: offer.ProposedBy,
+			Turn:       offer.Turn,
+			Terms:      offer.Terms,
+			Amount:     offer.Amount,
+			Counters:   offer.Counters,
+			Concession: offer.Concession,
+			Status:     string(offer.Status),
+		}
+	}
+	return converted
+}
+
+// captureIndividualCompletions records each agent who completed their part of
+// an individual goal this turn.
+func (s *Simulation) captureIndividualCompletions(turn int) {
+	world := s.World.Snapshot()
+	for goalName, goal := range world.Goals {
+		if !goal.Individual() {
+			continue
+		}
+		for _, agentName := range goal.CompletedBy() {
+			completion := goal.Completions[agentName]
+			if completion.CompletedAt != turn {
+				continue
+			}
+			slog.Info("individual goal completed", "goal", goalName, "agent", agentName, "summary", completion.Summary)
+			captured := chronicle.GoalCompletion{
+				GoalName:    goalName,
+				Status:      string(mcpsim.GoalCompleted),
+				Solution:    completion.Summary,
+				CompletedBy: agentName,
+				CompletedAt: turn,
+			}
+			s.currentGoalCompletions = append(s.currentGoalCompletions, captured)
+			s.captureCompletionEntry(agentName, captured)
+		}
+	}
 }
 
 // writeTurnToChronicle writes the current turn's events to the chronicle and clears them.
 func (s *Simulation) writeTurnToChronicle(turnNumber int) error {
-	if s.chronicleFile == nil {
+	if s.chronicleWriter == nil {
 		return nil // Chronicle not initialized
 	}
 
@@ -367,45 +927,127 @@ func (s *Simulation) writeTurnToChronicle(turnNumber int) error {
 	turn := chronicle.Turn{
 		Type:            "turn",
 		Number:          turnNumber,
+		Ambient:         s.currentAmbient,
 		Events:          s.currentTurnEvents,
 		GoalCompletions: s.currentGoalCompletions,
+		Skipped:         s.currentSkips,
+		Condition:       s.currentCondition,
+		Forecasts:       s.currentForecasts,
+		Injected:        s.currentInjected,
+		TieBreaks:       s.currentTieBreaks,
 	}
-
-	// Convert to JSON
-	jsonBytes, err := chronicle.ToJSON(turn)
+	hash, err := chronicle.HashTurn(turn)
 	if err != nil {
-		return fmt.Errorf("failed to marshal turn: %w", err)
+		return err
+	}
+	turn.Hash = hash
+	if s.Replay != nil {
+		s.Replay.check(turn)
 	}
 
-	// Write to file
-	if _, err := s.chronicleFile.WriteString(string(jsonBytes) + "\n"); err != nil {
+	// Write the turn and make sure it reaches the disk before the next begins
+	if err := s.chronicleWriter.Write(turn); err != nil {
 		return fmt.Errorf("failed to write turn: %w", err)
 	}
+	if err := s.chronicleWriter.Sync(); err != nil {
+		return fmt.Errorf("failed to sync chronicle: %w", err)
+	}
 
 	// Clear events and completions for next turn
 	s.currentTurnEvents = nil
 	s.currentGoalCompletions = nil
+	s.currentAmbient = nil
+	s.currentSkips = nil
+	s.currentCondition = nil
+	s.currentForecasts = nil
+	s.currentInjected = nil
+	s.currentTieBreaks = nil
+	s.currentNudge = ""
+	s.hooks.notifiedEvents = 0
+	s.hooks.notifiedCompletions = 0
 
 	return nil
 }
 
+// writeEndToChronicle records why the simulation stopped as the chronicle's last line.
+func (s *Simulation) writeEndToChronicle(reason string, runErr error) error {
+	if s.chronicleWriter == nil {
+		return nil // Chronicle not initialized
+	}
+
+	// Usage so far comes first, so the end record stays last
+	if err := s.chronicleWriter.Write(s.usageRecord()); err != nil {
+		return fmt.Errorf("failed to write usage: %w", err)
+	}
+
+	end := chronicle.End{
+		Type:     "end",
+		Turns:    s.World.Turn(),
+		MaxTurns: s.MaxTurns(),
+		Reason:   reason,
+	}
+	if runErr != nil {
+		end.Error = runErr.Error()
+	}
+
+	if err := s.chronicleWriter.Write(end); err != nil {
+		return fmt.Errorf("failed to write end: %w", err)
+	}
+	return s.chronicleWriter.Sync()
+}
+
+// agentNotes returns the situation notes for an agent's turn: how they feel
+// about the others, how tired they are, the pressure to settle goals, their
+// working memory and the director's nudge.
+func (s *Simulation) agentNotes(agentName string, turn int) string {
+	return s.relationshipNote(agentName) + s.tiredNote(agentName) + s.compromiseNote(agentName, turn) +
+		s.urgencyNote(agentName, turn) + s.deadlineNote(agentName) + s.workingMemoryNote(agentName, turn) +
+		s.directorNote(agentName)
+}
+
 // Start begins the simulation execution.
 // Runs multiple turns until goals are completed or max turns is reached.
-func (s *Simulation) Start(ctx context.Context) error {
+func (s *Simulation) Start(ctx context.Context) (err error) {
 	if len(s.Agents) == 0 {
 		return fmt.Errorf("no agents initialized")
 	}
 
-	// Initialize chronicle
-	if err := s.initializeChronicle(); err != nil {
+	// Initialize chronicle, or continue the checkpoint's
+	if s.resumeFrom != nil {
+		if err := s.resumeChronicle(s.resumeFrom); err != nil {
+			return fmt.Errorf("failed to resume chronicle: %w", err)
+		}
+	} else if err := s.initializeChronicle(); err != nil {
 		return fmt.Errorf("failed to initialize chronicle: %w", err)
 	}
 	defer func() {
-		if s.chronicleFile != nil {
-			s.chronicleFile.Close()
+		if s.chronicleWriter != nil {
+			if err := s.chronicleWriter.Close(); err != nil {
+				slog.Warn("failed to close chronicle", "error", err)
+			}
+		}
+	}()
+
+	// A run that stops with an error still gets its last turn and outcomes
+	// written, with a post-mortem even if the run was cancelled
+	defer func() {
+		if err == nil {
+			return
+		}
+		if writeErr := s.writeTurnToChronicle(s.World.Turn()); writeErr != nil {
+			slog.Warn("failed to write turn to chronicle", "error", writeErr)
+		}
+		if writeErr := s.writeEndToChronicle(chronicle.EndError, err); writeErr != nil {
+			slog.Warn("failed to write end to chronicle", "error", writeErr)
+		}
+		if writeErr := s.writeOutcomes(context.WithoutCancel(ctx), err); writeErr != nil {
+			slog.Warn("failed to write outcomes", "error", writeErr)
 		}
 	}()
 
+	// Record token usage in the catalog however the run ends
+	defer s.writeUsageReport(s.Clock.Now())
+
 	// Display scenario information
 	slog.Info("chronicle", "file", s.chroniclePath)
 	slog.Info("starting simulation", "name", s.Scenario.Basics.Name)
@@ -425,32 +1067,149 @@ func (s *Simulation) Start(ctx context.Context) error {
 		slog.Info("goal", "name", name, "description", goal.Description)
 
 		// Create interactive goal in world state
-		s.World.Goals[name] = mcpsim.NewInteractiveGoal(
+		goalType := "consensus"
+		switch {
+		case goal.Judged():
+			goalType = "judged"
+		case goal.Allocation():
+			goalType = "allocation"
+		case goal.Type == scenarios.GoalTypeMajority:
+			goalType = "majority"
+		case goal.Type == scenarios.GoalTypeWeightedVote:
+			goalType = "weighted"
+		case goal.Individual():
+			goalType = "individual"
+		case goal.RankedChoice():
+			goalType = "ranked"
+		case goal.Negotiation():
+			goalType = "negotiation"
+		}
+		interactiveGoal := mcpsim.NewInteractiveGoal(
 			name,
 			goal.Description,
-			"consensus", // Default to consensus for now
+			goalType,
 			goal.Priority,
 		)
+		if goal.Judged() {
+			interactiveGoal.Criteria = goal.Criteria
+			interactiveGoal.Threshold = goal.Threshold()
+		}
+		if goal.Allocation() {
+			constraints, err := goal.AllocationConstraints()
+			if err != nil {
+				return fmt.Errorf("goal %s: %w", name, err)
+			}
+			interactiveGoal.Allocation = &mcpsim.AllocationRules{
+				Resource:       goal.Resource,
+				Total:          goal.Total,
+				Recipients:     goal.Recipients,
+				Constraints:    constraints,
+				AllowRemainder: goal.AllowRemainder,
+			}
+		}
+		if goal.Voting() {
+			interactiveGoal.Voting = &mcpsim.VotingRules{
+				Threshold:   goal.VotingThreshold(),
+				Weights:     goal.Weights,
+				TieBreak:    goal.TieBreak,
+				Facilitator: goal.Facilitator,
+				Order:       goal.TieBreakOrder,
+				Seed:        goal.CoinFlipSeed(),
+			}
+		}
+		if goal.Negotiation() {
+			interactiveGoal.Negotiation = &mcpsim.NegotiationRules{
+				Sides: goal.Sides,
+				Term:  goal.Term,
+			}
+		}
+		rule, err := goal.ConsensusRule()
+		if err != nil {
+			return fmt.Errorf("goal %s: %w", name, err)
+		}
+		interactiveGoal.Consensus = rule
+		interactiveGoal.Quorum = goal.Quorum()
+		interactiveGoal.Assigned = goal.Assignment
+		interactiveGoal.Tags = goal.Tags
+		interactiveGoal.MaxTurns = goal.MaxTurns
+		if goal.Deadline != nil {
+			interactiveGoal.Deadline = goal.Deadline.ToDuration()
+		}
+		for _, outcome := range s.Scenario.Forbidden {
+			if !outcome.AppliesTo(name) {
+				continue
+			}
+			pattern, err := outcome.Regexp()
+			if err != nil {
+				return fmt.Errorf("goal %s: %w", name, err)
+			}
+			interactiveGoal.Forbidden = append(interactiveGoal.Forbidden, &mcpsim.ForbiddenOutcome{
+				Phrases: outcome.Match,
+				Pattern: pattern,
+				Reason:  outcome.Reason,
+			})
+		}
+		s.World.AddGoal(interactiveGoal)
+	}
+
+	// Pick up after the checkpointed turn when resuming
+	firstTurn := 1
+	if s.resumeFrom != nil {
+		if err := s.World.Restore(s.resumeFrom.World); err != nil {
+			return fmt.Errorf("failed to restore world: %w", err)
+		}
+		firstTurn = s.resumeFrom.World.Turn + 1
+		s.resumeActs(s.resumeFrom.World.Turn)
+		slog.Info("resuming simulation", "after_turn", s.resumeFrom.World.Turn)
 	}
 
+	// Goal deadlines count the time spent running, resumed runs included
+	s.runStart = s.Clock.Now()
+	s.runTimeBefore = s.World.RunTime()
+
 	// Multi-turn loop with two phases: deliberation and voting
-	maxTurns := 10
-	for turn := 1; turn <= maxTurns; turn++ {
-		s.World.CurrentTurn = turn
+	maxTurns := s.MaxTurns()
+	s.World.SetMaxTurns(maxTurns)
+	endReason := chronicle.EndMaxTurns
+	for turn := firstTurn; turn <= maxTurns; turn++ {
+		s.World.SetTurn(turn)
+		s.trackRunTime()
+		s.Usage.SetTurn(turn)
 		slog.Info("turn starting", "turn", turn)
+		s.startAmbientEvents(turn)
+		s.beginAct(ctx, turn)
+		s.direct(ctx, turn)
+		s.notifyTurnStart(ctx, turn)
 
 		// Phase 1: Deliberation - agents perceive, discuss, and propose solutions
 		slog.Debug("deliberation phase starting")
+		s.World.SetPhase(mcpsim.PhaseDeliberation)
 		deliberationTools := s.getDeliberationTools()
-		deliberationSituation := s.buildDeliberationPrompt(turn)
+		deliberationSituation := s.buildDeliberationPrompt(turn) + s.ambientSituation()
+		if s.CiteMemories {
+			deliberationSituation += citeMemoriesSituation
+		}
+		passed := make(map[string]bool)
+		agreement := s.agreementPattern()
+		agreed := make(map[string]bool) // Agents who voiced agreement this turn
 
-		for _, agentName := range s.TurnOrder {
+		for i, agentName := range s.TurnOrder {
 			agent := s.Agents[agentName]
 
+			// Exhausted agents sit the turn out
+			if _, exhausted := s.conditionState(agentName); exhausted {
+				slog.Info("pass", "agent", agentName, "reason", exhaustedReason)
+				s.captureEvent(agentName, exhaustedReason, "", string(mcpsim.MessageTypePass))
+				passed[agentName] = true
+				s.notifyCaptured(ctx, turn)
+				continue
+			}
+
 			slog.Debug("agent turn starting", "agent", agentName, "phase", "deliberation")
+			s.trackRunTime()
 
 			// Create context with agent name
-			agentCtx := context.WithValue(ctx, runtime.AgentNameKey, agentName)
+			agentCtx := s.agentContext(ctx, agentName)
 
 			// Track proposals before this agent's turn
 			proposalsBefore := s.countProposals()
@@ -466,11 +1225,36 @@ func (s *Simulation) Start(ctx context.Context) error {
 				}
 			}
 
+			// Observers take part in the conversation but not in deciding goals
+			tools, situation := deliberationTools, deliberationSituation
+			if s.isObserver(agentName) {
+				tools = withoutTools(deliberationTools, decisionTools)
+				situation += observerSituation
+			}
+			situation += s.agentNotes(agentName, turn)
+
 			// Agent deliberates: perceive, speak, propose
-			response, err := agent.Think(agentCtx, deliberationSituation, sceneCtx, deliberationTools, s.MCPServer)
+			finishStream := s.streamUtterance(ctx, turn, agent)
+			response, err := agent.Think(agentCtx, situation, sceneCtx, tools, s.toolExecutor())
 			if err != nil {
-				return fmt.Errorf("agent %s failed to deliberate: %w", agentName, err)
+				if !s.fallback(ctx, agentName, turn, err) {
+					return fmt.Errorf("agent %s failed to deliberate: %w", agentName, err)
+				}
+				finishStream("")
+				s.notifyCaptured(ctx, turn)
+				continue
+			}
+			s.modelSucceeded(agentName)
+			// Agents may have moved during their turn; their next prompt should say where they are
+			if position := s.World.Position(agentName); position != "" {
+				agent.State.Position = position
+			}
+			var citations []string
+			if s.CiteMemories {
+				response.Message, citations = extractCitations(response.Message)
 			}
+			response.Message = s.limitUtterance(agentName, response.Message)
+			finishStream(response.Message)
 
 			// Display response
 			if response.Thinking != "" {
@@ -486,58 +1270,133 @@ func (s *Simulation) Start(ctx context.Context) error {
 				s.displayNewProposals(agentName)
 			}
 
-			// Add to conversation history
-			if len(s.World.ConversationHistory) == 0 ||
-				s.World.ConversationHistory[len(s.World.ConversationHistory)-1].AgentName != agentName {
-				s.World.AddMessage(agentName, response.Message, response.Thinking, mcpsim.MessageTypeDialogue)
-			}
+			// Add to conversation history, unless the agent already said it with speak()
+			s.World.AddMessage(agentName, response.Message, response.Thinking, mcpsim.MessageTypeDialogue)
 
 			// Capture episodic memory
 			if response.Message != "" {
 				s.captureEpisodicMemory(agentCtx, agentName, response.Message, turn)
 			}
+			if agreement != nil && voicesAgreement(agreement, response.Message) {
+				agreed[agentName] = true
+			}
 
 			// Capture event for chronicle
+			s.captureRefusals(agentName, response.Refusals)
 			s.captureEvent(agentName, response.Message, response.Thinking, "dialogue")
-
-			// Capture pending dialogue from tool calls (proposal/vote comments)
-			for _, msg := range s.World.PendingDialogue {
-				s.captureEvent(msg.AgentName, msg.Content, "", string(msg.Type))
-				s.captureEpisodicMemory(agentCtx, msg.AgentName, msg.Content, turn)
+			s.captureCandidates(response.Candidates)
+			s.captureInvocations(response.Invocations)
+			s.captureModelUse(agentName, response.Iterations)
+			s.captureCitations(agentName, citations)
+
+			// Capture pending dialogue from tool calls (proposal/vote comments, passes)
+			for _, msg := range s.World.TakePendingDialogue() {
+				s.captureToolDialogue(msg)
+				if msg.Type == mcpsim.MessageTypePass {
+					slog.Info("pass", "agent", msg.AgentName, "reason", msg.Content)
+					passed[msg.AgentName] = true
+					continue
+				}
+				if msg.Type == mcpsim.MessageTypeWhisper {
+					s.captureWhisperMemory(agentCtx, msg, turn)
+					continue
+				}
+				if msg.Content != "" {
+					s.captureEpisodicMemory(agentCtx, msg.AgentName, msg.Content, turn)
+				}
+				if agreement != nil && voicesAgreement(agreement, msg.Content) {
+					agreed[msg.AgentName] = true
+				}
+			}
+			s.captureConditionChanges()
+			s.notifyCaptured(ctx, turn)
+
+			// Move straight to voting once consensus is obviously forming
+			if i < len(s.TurnOrder)-1 {
+				if reason := s.earlyVotingReason(agreed); reason != "" {
+					slog.Info("consensus forming, voting early", "reason", reason)
+					for _, remaining := range s.TurnOrder[i+1:] {
+						s.skipPhase(mcpsim.PhaseDeliberation, remaining, reason)
+					}
+					break
+				}
 			}
-			s.World.ClearPendingDialogue()
 		}
 
+		// Agents complete individual goals on their own during deliberation
+		s.captureIndividualCompletions(turn)
+		s.notifyCaptured(ctx, turn)
+
 		// Check for automatic consensus (identical proposals)
 		if s.checkAutomaticConsensus(turn) {
 			// Goals completed via automatic consensus, skip voting
 			slog.Info("automatic consensus detected, skipping voting phase")
 
 			// Capture goal completions from automatic consensus
-			s.captureGoalCompletionsForTurn(turn)
+			completions := s.captureGoalCompletionsForTurn(turn)
+			s.recordCommitments(ctx, completions, turn)
+			s.notifyCaptured(ctx, turn)
+		} else if len(passed) == len(s.TurnOrder) {
+			// Nobody proposed anything new, so there's nothing to vote on
+			s.skipPhase(mcpsim.PhaseVoting, "", "everyone passed during deliberation")
+		} else if !s.votesAwaited() {
+			s.skipPhase(mcpsim.PhaseVoting, "", "no proposals awaiting votes")
 		} else {
 			// Phase 2: Voting - agents vote on all pending proposals
 			slog.Debug("voting phase starting")
+			s.World.SetPhase(mcpsim.PhaseVoting)
 			votingTools := s.getVotingTools()
-			votingSituation := s.buildVotingPrompt()
 
 			for _, agentName := range s.TurnOrder {
 				agent := s.Agents[agentName]
 
+				if s.isObserver(agentName) {
+					s.skipPhase(mcpsim.PhaseVoting, agentName, "observers don't vote")
+					continue
+				}
+				if _, exhausted := s.conditionState(agentName); exhausted {
+					s.skipPhase(mcpsim.PhaseVoting, agentName, exhaustedReason)
+					continue
+				}
+
+				// Agents with nothing left to vote on would only acknowledge and wait
+				if s.World.ProposalsAwaitingVote(agentName) == 0 {
+					s.skipPhase(mcpsim.PhaseVoting, agentName, "already voted on every pending proposal")
+					continue
+				}
+
 				slog.Debug("agent turn starting", "agent", agentName, "phase", "voting")
+				s.trackRunTime()
 
 				// Create context with agent name
-				agentCtx := context.WithValue(ctx, runtime.AgentNameKey, agentName)
+				agentCtx := s.agentContext(ctx, agentName)
+				votingSituation := s.buildVotingPrompt(agentName)
+				if s.CiteMemories {
+					votingSituation += citeMemoriesSituation
+				}
 
 				// Track votes before
 				votesBefore := s.collectVotes()
 
 				// Agent votes on all pending proposals
 				// No scene context needed for voting phase (not turn 1)
-				response, err := agent.Think(agentCtx, votingSituation, nil, votingTools, s.MCPServer)
+				finishStream := s.streamUtterance(ctx, turn, agent)
+				response, err := agent.Think(agentCtx, votingSituation+s.agentNotes(agentName, turn), nil, votingTools, s.toolExecutor())
 				if err != nil {
-					return fmt.Errorf("agent %s failed to vote: %w", agentName, err)
+					if !s.fallback(ctx, agentName, turn, err) {
+						return fmt.Errorf("agent %s failed to vote: %w", agentName, err)
+					}
+					finishStream("")
+					s.notifyCaptured(ctx, turn)
+					continue
 				}
+				s.modelSucceeded(agentName)
+				var citations []string
+				if s.CiteMemories {
+					response.Message, citations = extractCitations(response.Message)
+				}
+				response.Message = s.limitUtterance(agentName, response.Message)
+				finishStream(response.Message)
 
 				// Display response
 				if response.Thinking != "" {
@@ -552,37 +1411,105 @@ func (s *Simulation) Start(ctx context.Context) error {
 				s.displayNewVotes(agentName, votesBefore, votesAfter)
 
 				// Capture event for chronicle
+				s.captureRefusals(agentName, response.Refusals)
 				s.captureEvent(agentName, response.Message, response.Thinking, "dialogue")
-
-				// Capture pending dialogue from tool calls (vote comments)
-				for _, msg := range s.World.PendingDialogue {
-					s.captureEvent(msg.AgentName, msg.Content, "", string(msg.Type))
+				s.captureCandidates(response.Candidates)
+				s.captureInvocations(response.Invocations)
+				s.captureModelUse(agentName, response.Iterations)
+				s.captureCitations(agentName, citations)
+
+				// Capture pending dialogue from tool calls (vote comments, passes)
+				for _, msg := range s.World.TakePendingDialogue() {
+					s.captureToolDialogue(msg)
+					if msg.Type == mcpsim.MessageTypePass {
+						slog.Info("pass", "agent", msg.AgentName, "reason", msg.Content)
+					}
 				}
-				s.World.ClearPendingDialogue()
+				s.notifyCaptured(ctx, turn)
 			}
 
 			// Display voting results
 			s.displayVotingResults()
 
 			// Capture goal completions that occurred during voting
-			s.captureGoalCompletionsForTurn(turn)
+			completions := s.captureGoalCompletionsForTurn(turn)
+			s.recordCommitments(ctx, completions, turn)
+			s.notifyCaptured(ctx, turn)
 		}
 
+		// Settle ranked-choice goals, and goals out of time rather than leave them unresolved, by ranked choice
+		if err := s.runRankedChoice(ctx, turn); err != nil {
+			return err
+		}
+
+		// Judge goals completed by rubric rather than by vote
+		s.judgeGoals(ctx, turn)
+		s.notifyCaptured(ctx, turn)
+
+		// Close the turn's votes, then record how tied votes were broken
+		s.World.CloseVoting(turn)
+		s.recordTieBreaks(turn)
+
+		// Project when open goals will be settled, to warn agents about any running late
+		s.forecastGoals(turn)
+
+		// Fail goals whose turn limit has run out
+		s.expireGoals(turn)
+
+		// Take the turn's toll on everyone's condition
+		s.drainCondition()
+
+		// Agents who talked this turn know each other a little better
+		s.World.GrowFamiliarity()
+
+		// Summarize old episodic memories so searches stay focused in long runs
+		s.compactMemories(ctx, turn)
+
 		// Write turn events to chronicle
 		if err := s.writeTurnToChronicle(turn); err != nil {
 			slog.Warn("failed to write turn to chronicle", "error", err)
 		}
 
-		// Check if all goals are completed
+		// Save progress so a crashed or timed out run can be resumed
+		if err := s.Checkpoint(); err != nil {
+			slog.Warn("failed to write checkpoint", "error", err)
+		}
+
+		// Stop once every goal is completed, or decided one way or the other
 		if s.allGoalsCompleted() {
 			slog.Info("all goals completed")
+			endReason = chronicle.EndGoalsCompleted
+			break
+		}
+		if s.goalsDecided() {
+			slog.Info("all goals decided")
+			endReason = chronicle.EndGoalsDecided
 			break
 		}
 	}
+	if err := s.writeEndToChronicle(endReason, nil); err != nil {
+		slog.Warn("failed to write end to chronicle", "error", err)
+	}
+
+	// Agreements nobody followed through on expire with the run
+	s.expireCommitments()
 
 	// Final summary
 	s.printGoalSummary()
-	slog.Info("simulation complete", "total_turns", s.World.CurrentTurn, "chronicle", s.chroniclePath)
+	s.printRefusalSummary()
+	if err := s.saveCampaignRelationships(); err != nil {
+		slog.Warn("failed to save campaign relationships", "error", err)
+	}
+	if err := s.writeOutcomes(ctx, nil); err != nil {
+		slog.Warn("failed to write outcomes", "error", err)
+	}
+	// After the outcomes, so the summary includes the post-mortem
+	s.printUsageSummary()
+	// A finished run has nothing left to resume
+	if err := os.Remove(s.CheckpointPath()); err != nil && !os.IsNotExist(err) {
+		slog.Warn("failed to remove checkpoint", "error", err)
+	}
+	slog.Info("simulation complete", "total_turns", s.World.Turn(), "chronicle", s.chroniclePath, "outcomes", s.outcomesPath)
 	return nil
 }
 
@@ -591,9 +1518,11 @@ func (s *Simulation) getDeliberationTools() []map[string]interface{} {
 	allowedTools := []string{
 		// Memory tools - essential for discovering identity and context
 		"query_self", "query_background", "query_communication_style",
-		"query_scene", "query_character", "query_memory",
+		"query_scene", "query_character", "query_memory", "query_knowledge",
 		// Goal and interaction tools
-		"list_goals", "view_goal", "perceive", "speak", "propose_solution",
+		"list_goals", "view_goal", "perceive", "look_around", "move_to", "pick_up", "give", "inspect_object", "speak", "whisper", "propose_solution", "counter_proposal", "complete_goal", "pass_turn", "rest",
+		"list_commitments", "fulfill_commitment", "simulation_status",
+		"view_relationships", "query_relationship", "adjust_relationship", "update_emotion",
 	}
 	allTools := s.MCPServer.GetToolDefinitions()
 
@@ -613,14 +1542,41 @@ func (s *Simulation) getDeliberationTools() []map[string]interface{} {
 	return filtered
 }
 
+// decisionTools are the tools observers don't get.
+var decisionTools = []string{"propose_solution", "counter_proposal", "vote_on_proposal", "rank_proposals", "withdraw_proposal", "complete_goal"}
+
+// observerSituation tells an observer what their part in the scene is.
+const observerSituation = "\n\nYou are here to observe, not to decide. Watch, react, comment and ask questions as your character would, but leave proposals and decisions to the others."
+
+// isObserver reports whether an agent observes rather than decides goals.
+func (s *Simulation) isObserver(agentName string) bool {
+	agent, ok := s.Scenario.Agents[agentName]
+	return ok && agent.Observer
+}
+
+// withoutTools returns the tool definitions minus the named tools.
+func withoutTools(tools []map[string]interface{}, names []string) []map[string]interface{} {
+	filtered := make([]map[string]interface{}, 0, len(tools))
+	for _, tool := range tools {
+		if fn, ok := tool["function"].(map[string]interface{}); ok {
+			if name, ok := fn["name"].(string); ok && slices.Contains(names, name) {
+				continue
+			}
+		}
+		filtered = append(filtered, tool)
+	}
+	return filtered
+}
+
 // getVotingTools returns only tools available during voting phase.
 func (s *Simulation) getVotingTools() []map[string]interface{} {
 	allowedTools := []string{
 		// Memory tools - agents still need access to their identity and memories
 		"query_self", "query_background", "query_communication_style",
-		"query_scene", "query_character", "query_memory",
+		"query_scene", "query_character", "query_memory", "query_knowledge",
 		// Voting tools
-		"view_goal", "vote_on_proposal",
+		"view_goal", "vote_on_proposal", "counter_proposal", "pass_turn", "simulation_status",
+		"view_relationships", "query_relationship", "update_emotion",
 	}
 	allTools := s.MCPServer.GetToolDefinitions()
 
@@ -641,7 +1597,7 @@ func (s *Simulation) getVotingTools() []map[string]interface{} {
 }
 
 // buildDeliberationPrompt creates the prompt for deliberation phase.
-// Prompts are loaded from the prompts package.
+// Prompts are loaded from the simulation's prompt library.
 func (s *Simulation) buildDeliberationPrompt(turn int) string {
 	var promptName string
 	if turn == 1 {
@@ -651,7 +1607,7 @@ func (s *Simulation) buildDeliberationPrompt(turn int) string {
 	}
 
 	// Get prompt template
-	prompt, err := prompts.GetPrompt(promptName)
+	prompt, err := s.prompts.Get(promptName)
 	if err != nil {
 		// Fallback to a simple message if file can't be read
 		return fmt.Sprintf("DELIBERATION PHASE (Turn %d): Use available tools to work on goals.", turn)
@@ -660,13 +1616,39 @@ func (s *Simulation) buildDeliberationPrompt(turn int) string {
 	return prompt
 }
 
-// buildVotingPrompt creates the prompt for voting phase.
-// The prompt template is loaded from the prompts package.
-func (s *Simulation) buildVotingPrompt() string {
-	// Build a list of all pending proposals across all goals
+// loadPrompts loads the prompt templates agents are given: the embedded ones,
+// overridden by the config directory's prompts directory and then by the
+// files the scenario names.
+func (s *Simulation) loadPrompts() (*prompts.Library, error) {
+	overrides, err := prompts.LoadOverrides(path.Join(s.ConfigDir, prompts.OverrideDir))
+	if err != nil {
+		return nil, err
+	}
+	for name, file := range s.Scenario.Prompts {
+		content, err := os.ReadFile(path.Join(s.ConfigDir, file))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read override for prompt %s: %w", name, err)
+		}
+		overrides[name] = string(content)
+	}
+	library, err := prompts.NewLibrary(overrides)
+	if err != nil {
+		return nil, err
+	}
+	if overridden := library.Overridden(); len(overridden) > 0 {
+		slog.Info("prompt overrides", "prompts", overridden)
+	}
+	return library, nil
+}
+
+// buildVotingPrompt creates an agent's prompt for the voting phase, listing
+// the pending proposals on the goals they decide, but for ranked-choice goals.
+// The prompt template is loaded from the simulation's prompt library.
+func (s *Simulation) buildVotingPrompt(agentName string) string {
+	world := s.World.Snapshot()
 	proposalList := ""
-	for goalName, goal := range s.World.Goals {
-		if goal.Status != mcpsim.GoalPending {
+	for goalName, goal := range world.Goals {
+		if goal.Status != mcpsim.GoalPending || !world.CanDecide(goal, agentName) || goal.Ranked() {
 			continue
 		}
 
@@ -687,7 +1669,7 @@ func (s *Simulation) buildVotingPrompt() string {
 	}
 
 	// Get prompt template
-	promptTemplate, err := prompts.GetPrompt("voting")
+	promptTemplate, err := s.prompts.Get("voting")
 	if err != nil {
 		// Fallback to simple format if template can't be read
 		return fmt.Sprintf("VOTING PHASE: Now you must vote on proposals.%s", proposalList)
@@ -717,18 +1699,67 @@ func (s *Simulation) buildVotingPrompt() string {
 
 // allGoalsCompleted checks if all goals have been completed.
 func (s *Simulation) allGoalsCompleted() bool {
-	for _, goal := range s.World.Goals {
+	world := s.World.Snapshot()
+	for _, goal := range world.Goals {
 		if goal.Status != mcpsim.GoalCompleted {
 			return false
 		}
 	}
-	return len(s.World.Goals) > 0 // Only return true if there are goals and they're all complete
+	return len(world.Goals) > 0 // Only return true if there are goals and they're all complete
+}
+
+// goalsDecided reports whether every goal has been completed or failed.
+func (s *Simulation) goalsDecided() bool {
+	world := s.World.Snapshot()
+	for _, goal := range world.Goals {
+		if goal.Status == mcpsim.GoalPending {
+			return false
+		}
+	}
+	return len(world.Goals) > 0
+}
+
+// expireGoals fails goals whose turn limit ends with this turn, or whose
+// deadline has passed, recording them as failed completions in the chronicle.
+func (s *Simulation) expireGoals(turn int) {
+	for _, goalName := range s.World.ExpireGoals(turn) {
+		slog.Info("goal failed", "goal", goalName, "reason", "turn limit reached", "turn", turn)
+		s.currentGoalCompletions = append(s.currentGoalCompletions, chronicle.GoalCompletion{
+			GoalName:    goalName,
+			Status:      string(mcpsim.GoalFailed),
+			Solution:    fmt.Sprintf("Not completed by turn %d", turn),
+			CompletedAt: turn,
+		})
+	}
+
+	s.trackRunTime()
+	for _, goalName := range s.World.ExpireDeadlines(turn) {
+		deadline := s.Scenario.Goals[goalName].Deadline.ToDuration()
+		slog.Info("goal failed", "goal", goalName, "reason", "deadline passed", "deadline", deadline, "turn", turn)
+		s.currentGoalCompletions = append(s.currentGoalCompletions, chronicle.GoalCompletion{
+			GoalName:    goalName,
+			Status:      string(mcpsim.GoalFailed),
+			Solution:    fmt.Sprintf("Not completed within %s", deadline),
+			CompletedAt: turn,
+		})
+	}
+}
+
+// votesAwaited reports whether any agent has a pending proposal left to vote on.
+func (s *Simulation) votesAwaited() bool {
+	for _, agentName := range s.TurnOrder {
+		if s.World.ProposalsAwaitingVote(agentName) > 0 {
+			return true
+		}
+	}
+	return false
 }
 
 // countProposals returns the total number of proposals across all goals.
 func (s *Simulation) countProposals() int {
+	world := s.World.Snapshot()
 	count := 0
-	for _, goal := range s.World.Goals {
+	for _, goal := range world.Goals {
 		count += len(goal.Proposals)
 	}
 	return count
@@ -736,9 +1767,10 @@ func (s *Simulation) countProposals() int {
 
 // displayNewProposals shows proposals that were just made by an agent.
 func (s *Simulation) displayNewProposals(agentName string) {
-	for _, goal := range s.World.Goals {
+	world := s.World.Snapshot()
+	for _, goal := range world.Goals {
 		for _, proposal := range goal.Proposals {
-			if proposal.ProposedBy == agentName && proposal.ProposedAt == s.World.CurrentTurn {
+			if proposal.ProposedBy == agentName && proposal.ProposedAt == world.CurrentTurn {
 				slog.Info("proposal", "agent", agentName, "description", proposal.Description)
 			}
 		}
@@ -747,8 +1779,9 @@ func (s *Simulation) displayNewProposals(agentName string) {
 
 // collectVotes returns a snapshot of all votes for comparison.
 func (s *Simulation) collectVotes() map[string]map[string]map[string]string {
+	world := s.World.Snapshot()
 	votes := make(map[string]map[string]map[string]string)
-	for goalName, goal := range s.World.Goals {
+	for goalName, goal := range world.Goals {
 		votes[goalName] = make(map[string]map[string]string)
 		for proposalID, proposal := range goal.Proposals {
 			votes[goalName][proposalID] = make(map[string]string)
@@ -762,6 +1795,7 @@ func (s *Simulation) collectVotes() map[string]map[string]map[string]string {
 
 // displayNewVotes shows votes that were just cast by an agent.
 func (s *Simulation) displayNewVotes(agentName string, before, after map[string]map[string]map[string]string) {
+	world := s.World.Snapshot()
 	for goalName, goalVotesAfter := range after {
 		goalVotesBefore := before[goalName]
 		for proposalID, proposalVotesAfter := range goalVotesAfter {
@@ -773,7 +1807,7 @@ func (s *Simulation) displayNewVotes(agentName string, before, after map[string]
 
 			if hasVoteAfter && !hasVoteBefore {
 				// Find the proposal to get its description
-				goal := s.World.Goals[goalName]
+				goal := world.Goals[goalName]
 				if proposal, ok := goal.Proposals[proposalID]; ok {
 					slog.Info("vote", "agent", agentName, "choice", voteAfter, "proposal", proposal.Description)
 				}
@@ -784,10 +1818,11 @@ func (s *Simulation) displayNewVotes(agentName string, before, after map[string]
 
 // displayVotingResults shows the outcome of the voting phase.
 func (s *Simulation) displayVotingResults() {
-	for _, goal := range s.World.Goals {
+	world := s.World.Snapshot()
+	for _, goal := range world.Goals {
 		for _, proposal := range goal.Proposals {
 			// Only show proposals that were resolved this turn
-			if proposal.ResolvedAt == s.World.CurrentTurn {
+			if proposal.ResolvedAt == world.CurrentTurn {
 				yesCount := 0
 				noCount := 0
 				for _, vote := range proposal.Votes {
@@ -811,9 +1846,10 @@ func (s *Simulation) displayVotingResults() {
 
 // printGoalSummary displays a summary of goal completion.
 func (s *Simulation) printGoalSummary() {
+	world := s.World.Snapshot()
 	slog.Info("goal summary")
 
-	for _, goal := range s.World.Goals {
+	for _, goal := range world.Goals {
 		statusText := string(goal.Status)
 
 		switch goal.Status {
@@ -825,6 +1861,10 @@ func (s *Simulation) printGoalSummary() {
 
 		slog.Info("goal status", "name", goal.Name, "status", statusText)
 
+		if goal.Judged() && goal.Assessment != "" {
+			slog.Info("goal judgment", "goal", goal.Name, "confidence", goal.Confidence, "threshold", goal.Threshold, "assessment", goal.Assessment)
+		}
+
 		if goal.Status == mcpsim.GoalCompleted {
 			// Show accepted proposal
 			for _, proposal := range goal.Proposals {
@@ -848,6 +1888,24 @@ func (s *Simulation) printGoalSummary() {
 	}
 }
 
+// agentContext returns a context identifying the agent, and its language and
+// the tags of the goal it is working on if known, to the tools it calls, along
+// with how many results its memory searches return.
+func (s *Simulation) agentContext(ctx context.Context, agentName string) context.Context {
+	ctx = context.WithValue(ctx, runtime.AgentNameKey, agentName)
+	if language := s.Scenario.AgentLanguage(agentName); language != "" {
+		ctx = context.WithValue(ctx, runtime.AgentLanguageKey, language)
+	}
+	if s.CiteMemories {
+		ctx = context.WithValue(ctx, runtime.CiteMemoriesKey, true)
+	}
+	ctx = context.WithValue(ctx, runtime.MemoryResultsKey, s.speed().MemoryResults)
+	if tags := s.World.FocusTags(agentName); len(tags) > 0 {
+		ctx = context.WithValue(ctx, runtime.GoalTagsKey, tags)
+	}
+	return ctx
+}
+
 // captureEpisodicMemory stores agent dialogue and actions as episodic memories.
 func (s *Simulation) captureEpisodicMemory(ctx context.Context, agentName, content string, turn int) {
 	if s.MemoryStore == nil {
@@ -865,87 +1923,136 @@ func (s *Simulation) captureEpisodicMemory(ctx context.Context, agentName, conte
 		return
 	}
 
-	// Store as episodic memory
+	// Store as episodic memory, tagged with the topics of the goal being discussed
+	metadata := map[string]string{
+		"type":     "episodic",
+		"category": "dialogue",
+		"turn":     fmt.Sprintf("%d", turn),
+		"speaker":  agentName,
+		"language": s.Scenario.AgentLanguage(agentName),
+		"run":      s.ID.String(),
+	}
+	if tags := s.World.FocusTags(agentName); len(tags) > 0 {
+		metadata[memory.TagsKey] = memory.JoinTags(tags)
+	}
 	s.MemoryStore.Add(memory.Memory{
 		Content:   episodicContent,
 		Embedding: embedding,
-		Metadata: map[string]string{
-			"type":     "episodic",
-			"category": "dialogue",
-			"turn":     fmt.Sprintf("%d", turn),
-			"speaker":  agentName,
-		},
+		Metadata:  metadata,
 	})
 }
 
+// captureWhisperMemory stores a whisper as a private memory of the agent who
+// sent it and the one who heard it, kept apart from the shared episodic
+// memories so nobody else can recall it.
+func (s *Simulation) captureWhisperMemory(ctx context.Context, msg mcpsim.ConversationMessage, turn int) {
+	if s.MemoryStore == nil {
+		return
+	}
+
+	content := fmt.Sprintf("%s whispered to %s: %s", msg.AgentName, msg.Recipient, msg.Content)
+	embedding, err := s.MemoryStore.Embed(ctx, content)
+	if err != nil {
+		// Log error but don't fail the simulation
+		slog.Warn("failed to embed whisper memory", "error", err)
+		return
+	}
+
+	for _, agentName := range []string{msg.AgentName, msg.Recipient} {
+		s.MemoryStore.Add(memory.Memory{
+			Content:   content,
+			Embedding: embedding,
+			Metadata: map[string]string{
+				"agent":     agentName,
+				"type":      "whisper",
+				"category":  "dialogue",
+				"turn":      fmt.Sprintf("%d", turn),
+				"speaker":   msg.AgentName,
+				"recipient": msg.Recipient,
+				"language":  s.Scenario.AgentLanguage(msg.AgentName),
+				"run":       s.ID.String(),
+			},
+		})
+	}
+}
+
 // checkAutomaticConsensus detects when all agents have made identical proposals.
 // If consensus is detected, auto-accepts the proposal and returns true.
 func (s *Simulation) checkAutomaticConsensus(turn int) bool {
 	foundConsensus := false
 
-	for _, goal := range s.World.Goals {
-		// Only check pending goals
-		if goal.Status != mcpsim.GoalPending {
-			continue
-		}
+	s.World.Update(func(w *mcpsim.WorldState) error {
+		for _, goal := range w.Goals {
+			// Only check pending goals
+			if goal.Status != mcpsim.GoalPending {
+				continue
+			}
 
-		// Get all proposals made this turn
-		turnProposals := make([]*mcpsim.Proposal, 0)
-		for _, proposal := range goal.Proposals {
-			if proposal.ProposedAt == turn && proposal.Status == mcpsim.ProposalPending {
-				turnProposals = append(turnProposals, proposal)
+			// Get all proposals made this turn
+			turnProposals := make([]*mcpsim.Proposal, 0)
+			for _, proposal := range goal.Proposals {
+				if proposal.ProposedAt == turn && proposal.Status == mcpsim.ProposalPending {
+					turnProposals = append(turnProposals, proposal)
+				}
 			}
-		}
 
-		// Need exactly as many proposals as agents
-		if len(turnProposals) != len(s.TurnOrder) {
-			continue
-		}
+			// Need exactly as many proposals as agents deciding the goal
+			participants := w.GoalParticipants(goal)
+			if len(turnProposals) != len(participants) {
+				continue
+			}
 
-		// Check if all proposals have identical descriptions
-		if len(turnProposals) == 0 {
-			continue
-		}
+			// Check if all proposals have identical descriptions
+			if len(turnProposals) == 0 {
+				continue
+			}
 
-		firstDescription := turnProposals[0].Description
-		allIdentical := true
-		for _, proposal := range turnProposals[1:] {
-			if proposal.Description != firstDescription {
-				allIdentical = false
-				break
+			firstDescription := turnProposals[0].Description
+			allIdentical := true
+			for _, proposal := range turnProposals[1:] {
+				if proposal.Description != firstDescription {
+					allIdentical = false
+					break
+				}
 			}
-		}
 
-		if allIdentical {
-			// Auto-accept the first proposal (they're all the same)
-			acceptedProposal := turnProposals[0]
+			// A forbidden outcome can't be accepted, however unanimous
+			if allIdentical && goal.ForbiddenReason(firstDescription) != "" {
+				continue
+			}
 
-			// Mark all agents as having voted yes
-			for _, agentName := range s.TurnOrder {
-				acceptedProposal.Votes[agentName] = &mcpsim.Vote{
-					AgentName: agentName,
-					Choice:    "yes",
-					VotedAt:   turn,
+			if allIdentical {
+				// Auto-accept the first proposal (they're all the same)
+				acceptedProposal := turnProposals[0]
+
+				// Mark all deciding agents as having voted yes
+				for _, agentName := range participants {
+					acceptedProposal.Votes[agentName] = &mcpsim.Vote{
+						AgentName: agentName,
+						Choice:    "yes",
+						VotedAt:   turn,
+					}
 				}
-			}
 
-			// Update proposal status
-			acceptedProposal.Status = mcpsim.ProposalAccepted
-			acceptedProposal.ResolvedAt = turn
+				// Update proposal status
+				acceptedProposal.Status = mcpsim.ProposalAccepted
+				acceptedProposal.ResolvedAt = turn
 
-			// Mark other identical proposals as withdrawn
-			for _, proposal := range turnProposals[1:] {
-				proposal.Status = mcpsim.ProposalWithdrawn
-				proposal.ResolvedAt = turn
-			}
+				// Mark other identical proposals as withdrawn
+				for _, proposal := range turnProposals[1:] {
+					proposal.Status = mcpsim.ProposalWithdrawn
+					proposal.ResolvedAt = turn
+				}
 
-			// Complete the goal
-			goal.CheckConsensus(turn)
+				// Complete the goal
+				goal.CheckConsensus(turn)
 
-			slog.Info("automatic consensus", "goal", goal.Name, "proposal", firstDescription)
-			foundConsensus = true
+				slog.Info("automatic consensus", "goal", goal.Name, "proposal", firstDescription)
+				foundConsensus = true
+			}
 		}
-	}
+		return nil
+	})
 
 	return foundConsensus
 }
@@ -954,7 +2061,7 @@ func (s *Simulation) checkAutomaticConsensus(turn int) bool {
 // Format: chronicle-<scenario-slug>-<timestamp>-<short-id>.jsonl
 func (s *Simulation) getChronicleFilename() string {
 	// Generate timestamp
-	timestamp := time.Now().Format("20060102-150405")
+	timestamp := s.Clock.Now().Format("20060102-150405")
 
 	// Slugify scenario name
 	scenarioSlug := slugify(s.Scenario.Basics.Name)
@@ -962,7 +2069,7 @@ func (s *Simulation) getChronicleFilename() string {
 	// Get first 6 characters of ULID (lowercase)
 	shortID := strings.ToLower(s.ID.String()[0:6])
 
-	return fmt.Sprintf("chronicle-%s-%s-%s.jsonl", scenarioSlug, timestamp, shortID)
+	return path.Join(s.OutputDir, fmt.Sprintf("chronicle-%s-%s-%s.jsonl", scenarioSlug, timestamp, shortID))
 }
 
 // slugify converts a string to a URL-safe slug.
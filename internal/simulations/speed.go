@@ -0,0 +1,64 @@
+package simulations
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SpeedProfile bundles the settings that trade simulation fidelity for speed,
+// so a run can be a quick smoke test or a deep, high-fidelity session.
+type SpeedProfile struct {
+	Name              string
+	MaxTurns          int // Turns before the simulation ends
+	MaxToolIterations int // LLM calls an agent may make in one turn while using tools
+	MemoryResults     int // Results returned by each memory and knowledge search
+}
+
+// Speed profile names.
+const (
+	SpeedFast     = "fast"
+	SpeedBalanced = "balanced"
+	SpeedThorough = "thorough"
+)
+
+// speedProfiles are the available presets; balanced matches the historical defaults.
+var speedProfiles = map[string]SpeedProfile{
+	SpeedFast:     {Name: SpeedFast, MaxTurns: 4, MaxToolIterations: 15, MemoryResults: 3},
+	SpeedBalanced: {Name: SpeedBalanced, MaxTurns: 10, MaxToolIterations: 50, MemoryResults: 5},
+	SpeedThorough: {Name: SpeedThorough, MaxTurns: 20, MaxToolIterations: 80, MemoryResults: 10},
+}
+
+// SpeedProfileNames lists the speed profiles from fastest to most thorough.
+var SpeedProfileNames = []string{SpeedFast, SpeedBalanced, SpeedThorough}
+
+// DefaultSpeedProfile returns the balanced profile.
+func DefaultSpeedProfile() *SpeedProfile {
+	profile := speedProfiles[SpeedBalanced]
+	return &profile
+}
+
+// ParseSpeedProfile returns the named speed profile.
+func ParseSpeedProfile(name string) (*SpeedProfile, error) {
+	profile, ok := speedProfiles[strings.ToLower(strings.TrimSpace(name))]
+	if !ok {
+		return nil, fmt.Errorf("unknown speed profile '%s' (use %s)", name, strings.Join(SpeedProfileNames, ", "))
+	}
+	return &profile, nil
+}
+
+// speed returns the simulation's speed profile, defaulting to balanced.
+func (s *Simulation) speed() *SpeedProfile {
+	if s.Speed == nil {
+		return DefaultSpeedProfile()
+	}
+	return s.Speed
+}
+
+// MaxTurns returns the turns the simulation may run: the scenario's max_turns
+// when it sets one, otherwise the speed profile's.
+func (s *Simulation) MaxTurns() int {
+	if s.Scenario != nil && s.Scenario.Basics.MaxTurns > 0 {
+		return s.Scenario.Basics.MaxTurns
+	}
+	return s.speed().MaxTurns
+}
@@ -0,0 +1,89 @@
+package simulations
+
+import (
+	"context"
+	"testing"
+
+	"github.com/poiesic/wonda/internal/runtime"
+	"github.com/poiesic/wonda/internal/scenarios"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSpeedProfile(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{input: "fast", want: SpeedFast},
+		{input: "balanced", want: SpeedBalanced},
+		{input: "thorough", want: SpeedThorough},
+		{input: "FAST", want: SpeedFast},
+		{input: " Thorough\n", want: SpeedThorough},
+		{input: "", wantErr: true},
+		{input: "turbo", wantErr: true},
+		{input: "fastest", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			profile, err := ParseSpeedProfile(tt.input)
+			if tt.wantErr {
+				assert.EqualError(t, err, "unknown speed profile '"+tt.input+"' (use fast, balanced, thorough)")
+				assert.Nil(t, profile)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, profile.Name)
+		})
+	}
+}
+
+func TestSpeedProfiles(t *testing.T) {
+	t.Run("run longer and search wider from fastest to most thorough", func(t *testing.T) {
+		var profiles []*SpeedProfile
+		for _, name := range SpeedProfileNames {
+			profile, err := ParseSpeedProfile(name)
+			require.NoError(t, err)
+			profiles = append(profiles, profile)
+		}
+		require.Len(t, profiles, 3)
+
+		assert.Equal(t, SpeedProfile{Name: SpeedFast, MaxTurns: 4, MaxToolIterations: 15, MemoryResults: 3}, *profiles[0])
+		assert.Equal(t, SpeedProfile{Name: SpeedThorough, MaxTurns: 20, MaxToolIterations: 80, MemoryResults: 10}, *profiles[2])
+		for i := 1; i < len(profiles); i++ {
+			assert.Greater(t, profiles[i].MaxTurns, profiles[i-1].MaxTurns)
+			assert.Greater(t, profiles[i].MaxToolIterations, profiles[i-1].MaxToolIterations)
+			assert.Greater(t, profiles[i].MemoryResults, profiles[i-1].MemoryResults)
+		}
+	})
+
+	t.Run("defaults to the historical settings", func(t *testing.T) {
+		assert.Equal(t, &SpeedProfile{Name: SpeedBalanced, MaxTurns: 10, MaxToolIterations: 50, MemoryResults: 5}, DefaultSpeedProfile())
+		assert.Equal(t, DefaultSpeedProfile(), (&Simulation{}).speed())
+	})
+
+	t.Run("returns copies of the presets", func(t *testing.T) {
+		profile, err := ParseSpeedProfile(SpeedFast)
+		require.NoError(t, err)
+		profile.MaxTurns = 1
+
+		again, err := ParseSpeedProfile(SpeedFast)
+		require.NoError(t, err)
+		assert.Equal(t, 4, again.MaxTurns)
+	})
+
+	t.Run("limits memory results in the agent's context", func(t *testing.T) {
+		scenario := scenarios.NewScenario()
+		sim := NewSimulation(scenario, t.TempDir())
+		sim.World.AddAgent("Alex", "table")
+
+		ctx := sim.agentContext(context.Background(), "Alex")
+		assert.Equal(t, 5, ctx.Value(runtime.MemoryResultsKey))
+
+		sim.Speed, _ = ParseSpeedProfile(SpeedThorough)
+		ctx = sim.agentContext(context.Background(), "Alex")
+		assert.Equal(t, 10, ctx.Value(runtime.MemoryResultsKey))
+	})
+}
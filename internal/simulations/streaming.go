@@ -0,0 +1,120 @@
+package simulations
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/poiesic/wonda/internal/chronicle"
+)
+
+// partialInterval is the minimum time between partial lines written to the chronicle.
+// Hooks receive every update; the chronicle is throttled to keep the file small.
+const partialInterval = 250 * time.Millisecond
+
+// streamUtterance streams the agent's next response to partial utterance hooks
+// and the chronicle, and echoes it live when the simulation has an echo writer.
+// The returned function must be called with the final message once the
+// response is complete; it marks the utterance final and detaches the stream.
+func (s *Simulation) streamUtterance(ctx context.Context, turn int, agent *Agent) func(message string) {
+	if !s.Stream && s.Echo == nil {
+		return func(string) {}
+	}
+
+	var echo *liveEcho
+	if s.Echo != nil {
+		echo = &liveEcho{w: s.Echo, agentName: agent.Name}
+	}
+	var lastWrite time.Time
+	var lastMessage string
+	written := false
+	agent.Stream = func(message, thinking string) {
+		echo.update(message, thinking)
+		if !s.Stream || message == lastMessage {
+			return
+		}
+		lastMessage = message
+		s.notifyPartial(ctx, turn, agent.Name, message)
+
+		// Write on sentence boundaries or after the interval, whichever comes first
+		trimmed := strings.TrimSpace(message)
+		sentenceEnd := trimmed != "" && strings.ContainsAny(trimmed[len(trimmed)-1:], ".!?")
+		if !sentenceEnd && time.Since(lastWrite) < partialInterval {
+			return
+		}
+		s.writePartial(chronicle.Partial{Type: "partial", Turn: turn, AgentName: agent.Name, Text: message})
+		lastWrite = time.Now()
+		written = true
+	}
+
+	return func(message string) {
+		agent.Stream = nil
+		echo.finish()
+		if written {
+			s.writePartial(chronicle.Partial{Type: "partial", Turn: turn, AgentName: agent.Name, Text: cleanDialogue(message), Final: true})
+		}
+	}
+}
+
+// liveEcho prints an agent's streaming thinking and message to a terminal as
+// they arrive. A nil liveEcho prints nothing.
+type liveEcho struct {
+	w         io.Writer
+	agentName string
+	thinking  string // Thinking printed for the current request
+	message   string // Message printed for the current request
+	printed   bool
+}
+
+// update prints whatever has arrived since the last update. Text that doesn't
+// continue what was printed belongs to the next request of the agent's tool loop.
+func (e *liveEcho) update(message, thinking string) {
+	if e == nil {
+		return
+	}
+	if !strings.HasPrefix(thinking, e.thinking) || !strings.HasPrefix(message, e.message) {
+		e.thinking, e.message = "", ""
+	}
+	if len(thinking) > len(e.thinking) {
+		if e.thinking == "" {
+			fmt.Fprintf(e.w, "\n%s (thinking): ", e.agentName)
+		}
+		fmt.Fprint(e.w, thinking[len(e.thinking):])
+		e.thinking = thinking
+		e.printed = true
+	}
+	if len(message) > len(e.message) {
+		if e.message == "" {
+			fmt.Fprintf(e.w, "\n%s: ", e.agentName)
+		}
+		fmt.Fprint(e.w, message[len(e.message):])
+		e.message = message
+		e.printed = true
+	}
+}
+
+// finish ends the echoed response's line.
+func (e *liveEcho) finish() {
+	if e != nil && e.printed {
+		fmt.Fprintln(e.w)
+	}
+}
+
+// writePartial appends a partial utterance line to the chronicle.
+// Failures are logged; partial lines are a convenience for live viewers.
+func (s *Simulation) writePartial(partial chronicle.Partial) {
+	if s.chronicleWriter == nil {
+		return
+	}
+	if err := s.chronicleWriter.Write(partial); err != nil {
+		slog.Warn("failed to write partial utterance", "error", err)
+		return
+	}
+	// Live viewers tail the file; partials needn't reach the disk before the turn does
+	if err := s.chronicleWriter.Flush(); err != nil {
+		slog.Warn("failed to write partial utterance", "error", err)
+	}
+}
@@ -0,0 +1,27 @@
+package simulations
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLiveEcho(t *testing.T) {
+	var out strings.Builder
+	echo := &liveEcho{w: &out, agentName: "Alice"}
+
+	echo.update("", "Bob is")
+	echo.update("", "Bob is stalling.")
+	echo.update("Out with", "Bob is stalling.")
+	echo.update("Out with it.", "Bob is stalling.")
+	// The next request of the agent's tool loop starts over
+	echo.update("Fine.", "")
+	echo.finish()
+
+	assert.Equal(t, "\nAlice (thinking): Bob is stalling.\nAlice: Out with it.\nAlice: Fine.\n", out.String())
+
+	var nilEcho *liveEcho
+	nilEcho.update("ignored", "")
+	nilEcho.finish()
+}
@@ -0,0 +1,43 @@
+package simulations
+
+import (
+	"log/slog"
+	"sort"
+
+	"github.com/poiesic/wonda/internal/chronicle"
+	mcpsim "github.com/poiesic/wonda/internal/mcp/simulation"
+)
+
+// recordTieBreaks records the tied votes broken this turn in the chronicle,
+// ordered by goal and proposal.
+func (s *Simulation) recordTieBreaks(turn int) {
+	world := s.World.Snapshot()
+	var tieBreaks []chronicle.TieBreak
+	for _, goal := range world.Goals {
+		for _, proposal := range goal.Proposals {
+			if proposal.TieBreak == nil || proposal.TieBreak.Turn != turn {
+				continue
+			}
+			tieBreaks = append(tieBreaks, chronicle.TieBreak{
+				GoalName:   goal.Name,
+				ProposalID: proposal.ID,
+				Proposal:   proposal.Description,
+				Policy:     proposal.TieBreak.Policy,
+				Outcome:    proposal.TieBreak.Outcome,
+				Reason:     proposal.TieBreak.Reason,
+			})
+		}
+	}
+	sort.Slice(tieBreaks, func(i, j int) bool {
+		if tieBreaks[i].GoalName != tieBreaks[j].GoalName {
+			return tieBreaks[i].GoalName < tieBreaks[j].GoalName
+		}
+		return mcpsim.ProposalNumber(tieBreaks[i].ProposalID) < mcpsim.ProposalNumber(tieBreaks[j].ProposalID)
+	})
+
+	for _, tieBreak := range tieBreaks {
+		slog.Info("tie broken", "goal", tieBreak.GoalName, "proposal", tieBreak.ProposalID,
+			"policy", tieBreak.Policy, "outcome", tieBreak.Outcome, "reason", tieBreak.Reason)
+	}
+	s.currentTieBreaks = append(s.currentTieBreaks, tieBreaks...)
+}
@@ -0,0 +1,36 @@
+package simulations
+
+import (
+	"testing"
+
+	mcpsim "github.com/poiesic/wonda/internal/mcp/simulation"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecordTieBreaks(t *testing.T) {
+	world := mcpsim.NewWorldState("Town hall", "")
+	for _, name := range []string{"vote", "budget"} {
+		goal := mcpsim.NewInteractiveGoal(name, "Hold a vote", "majority", 1)
+		for i := 1; i <= 11; i++ {
+			proposalID := goal.AddProposal("Alice", "Option", 1)
+			// Ties broken in other turns aren't recorded again
+			turn := 2
+			if i%2 == 0 {
+				turn = 1
+			}
+			goal.Proposals[proposalID].TieBreak = &mcpsim.TieBreak{Policy: mcpsim.TieBreakCoinFlip, Turn: turn, Outcome: "accepted"}
+		}
+		world.AddGoal(goal)
+	}
+	sim := &Simulation{World: world}
+
+	sim.recordTieBreaks(2)
+	var order []string
+	for _, tieBreak := range sim.currentTieBreaks {
+		order = append(order, tieBreak.GoalName+"/"+tieBreak.ProposalID)
+	}
+	assert.Equal(t, []string{
+		"budget/proposal_1", "budget/proposal_3", "budget/proposal_5", "budget/proposal_7", "budget/proposal_9", "budget/proposal_11",
+		"vote/proposal_1", "vote/proposal_3", "vote/proposal_5", "vote/proposal_7", "vote/proposal_9", "vote/proposal_11",
+	}, order, "by goal, then proposal number")
+}
@@ -0,0 +1,103 @@
+package simulations
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/poiesic/wonda/internal/mcp"
+	"github.com/poiesic/wonda/internal/runtime"
+)
+
+// ToolCallRecord is an entry in a run's tool transcript: one tool call an
+// agent made, what it returned and how long it took.
+type ToolCallRecord struct {
+	Time      time.Time              `json:"time"`
+	Turn      int                    `json:"turn"`
+	Agent     string                 `json:"agent"`
+	Tool      string                 `json:"tool"`
+	CallID    string                 `json:"call_id,omitempty"`
+	Arguments map[string]interface{} `json:"arguments"`
+	Result    interface{}            `json:"result,omitempty"` // Set when the tool succeeded
+	Error     string                 `json:"error,omitempty"`  // Set when the tool failed
+	LatencyMS float64                `json:"latency_ms"`
+	EndsTurn  bool                   `json:"ends_turn,omitempty"`
+}
+
+// transcribingExecutor executes tool calls on the simulation's MCP server,
+// recording each one in the run's tool transcript.
+type transcribingExecutor struct {
+	sim *Simulation
+}
+
+// toolExecutor returns what runs agents' tool calls: the MCP server, by way
+// of the tool transcript when one is kept.
+func (s *Simulation) toolExecutor() ToolExecutor {
+	if !s.ToolTranscript {
+		return s.MCPServer
+	}
+	return &transcribingExecutor{sim: s}
+}
+
+// ExecuteTool executes a tool call and records it. Failing to record the call
+// is logged and doesn't affect the result.
+func (e *transcribingExecutor) ExecuteTool(ctx context.Context, toolCall *mcp.ToolCall) *mcp.ToolResult {
+	start := time.Now()
+	result := e.sim.MCPServer.ExecuteTool(ctx, toolCall)
+	latency := time.Since(start)
+
+	agentName, _ := ctx.Value(runtime.AgentNameKey).(string)
+	record := ToolCallRecord{
+		Time:      e.sim.Clock.Now(),
+		Turn:      e.sim.World.Turn(),
+		Agent:     agentName,
+		Tool:      toolCall.Name,
+		CallID:    toolCall.ID,
+		Arguments: toolCall.Arguments,
+		LatencyMS: float64(latency.Microseconds()) / 1000,
+		EndsTurn:  result.EndsTurn,
+	}
+	if result.IsError {
+		record.Error = fmt.Sprint(result.Content)
+	} else {
+		record.Result = result.Content
+	}
+	if err := e.sim.appendToolTranscript(record); err != nil {
+		slog.Warn("failed to record tool call", "agent", agentName, "tool", toolCall.Name, "error", err)
+	}
+	return result
+}
+
+// toolTranscriptPath is where tool calls are recorded, next to the chronicle.
+func (s *Simulation) toolTranscriptPath() string {
+	return strings.TrimSuffix(s.chroniclePath, ".jsonl") + ".tools.jsonl"
+}
+
+// appendToolTranscript adds a record to the run's tool transcript. Agents
+// may call tools at the same time, so writes are serialized.
+func (s *Simulation) appendToolTranscript(record ToolCallRecord) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		// Results are whatever the tool returned; keep the call if they don't marshal
+		record.Result = fmt.Sprint(record.Result)
+		if data, err = json.Marshal(record); err != nil {
+			return fmt.Errorf("failed to marshal tool call record: %w", err)
+		}
+	}
+
+	s.toolTranscriptMu.Lock()
+	defer s.toolTranscriptMu.Unlock()
+	file, err := os.OpenFile(s.toolTranscriptPath(), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open tool transcript: %w", err)
+	}
+	defer file.Close()
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write tool transcript: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package simulations
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/poiesic/wonda/internal/chronicle"
+	"github.com/poiesic/wonda/internal/mcp"
+	mcpsim "github.com/poiesic/wonda/internal/mcp/simulation"
+	"github.com/poiesic/wonda/internal/runtime"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToolTranscript(t *testing.T) {
+	newSim := func(transcript bool) *Simulation {
+		server := mcp.NewServer("test", "1.0")
+		server.RegisterTool(&mcp.Tool{
+			Name:        "echo",
+			InputSchema: map[string]interface{}{"type": "object"},
+			Handler: func(ctx context.Context, arguments map[string]interface{}) (interface{}, error) {
+				return map[string]interface{}{"said": arguments["text"]}, nil
+			},
+		})
+		server.RegisterTool(&mcp.Tool{
+			Name:        "speak",
+			InputSchema: map[string]interface{}{"type": "object"},
+			EndsTurn:    true,
+			Handler: func(ctx context.Context, arguments map[string]interface{}) (interface{}, error) {
+				return nil, errors.New("nobody is listening")
+			},
+		})
+		world := mcpsim.NewWorldState("Cafe", "Quiet")
+		world.SetTurn(3)
+		return &Simulation{
+			MCPServer:      server,
+			World:          world,
+			Clock:          chronicle.FixedClock{Time: time.Date(2026, 10, 16, 19, 0, 0, 0, time.UTC)},
+			ToolTranscript: transcript,
+			chroniclePath:  filepath.Join(t.TempDir(), "chronicle-dinner.jsonl"),
+		}
+	}
+	ctx := context.WithValue(context.Background(), runtime.AgentNameKey, "Alice")
+
+	t.Run("records every call with its caller and result", func(t *testing.T) {
+		sim := newSim(true)
+		executor := sim.toolExecutor()
+		result := executor.ExecuteTool(ctx, &mcp.ToolCall{ID: "call_1", Name: "echo", Arguments: map[string]interface{}{"text": "hi"}})
+		assert.False(t, result.IsError)
+		result = executor.ExecuteTool(ctx, &mcp.ToolCall{ID: "call_2", Name: "speak", Arguments: map[string]interface{}{}})
+		assert.True(t, result.IsError)
+
+		file, err := os.Open(sim.toolTranscriptPath())
+		require.NoError(t, err)
+		defer file.Close()
+		var records []ToolCallRecord
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			var record ToolCallRecord
+			require.NoError(t, json.Unmarshal(scanner.Bytes(), &record))
+			records = append(records, record)
+		}
+		require.Len(t, records, 2)
+
+		assert.Equal(t, "Alice", records[0].Agent)
+		assert.Equal(t, 3, records[0].Turn)
+		assert.Equal(t, "echo", records[0].Tool)
+		assert.Equal(t, "call_1", records[0].CallID)
+		assert.Equal(t, map[string]interface{}{"text": "hi"}, records[0].Arguments)
+		assert.Equal(t, map[string]interface{}{"said": "hi"}, records[0].Result)
+		assert.Empty(t, records[0].Error)
+		assert.GreaterOrEqual(t, records[0].LatencyMS, 0.0)
+
+		assert.Equal(t, "nobody is listening", records[1].Error)
+		assert.Nil(t, records[1].Result)
+		assert.True(t, records[1].EndsTurn)
+	})
+
+	t.Run("off by default", func(t *testing.T) {
+		sim := newSim(false)
+		sim.toolExecutor().ExecuteTool(ctx, &mcp.ToolCall{ID: "call_1", Name: "echo", Arguments: map[string]interface{}{}})
+		_, err := os.Stat(sim.toolTranscriptPath())
+		assert.True(t, os.IsNotExist(err))
+	})
+}
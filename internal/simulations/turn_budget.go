@@ -0,0 +1,339 @@
+package simulations
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"slices"
+	"sort"
+	"text/template"
+
+	"github.com/poiesic/wonda/internal/chronicle"
+	mcpsim "github.com/poiesic/wonda/internal/mcp/simulation"
+	"github.com/poiesic/wonda/internal/prompts"
+)
+
+// urgencySituations warn an agent that a goal is projected to miss its
+// deadline, from gentlest (several turns left) to firmest (the final turn).
+var urgencySituations = []string{
+	"\n\nAt the pace things are going, '%s' won't be settled by turn %d. Keep it moving - look for what the group could actually agree on.",
+	"\n\n'%s' has to be settled by turn %d, and the group isn't getting there. Focus on it, and be ready to back a proposal that isn't your first choice.",
+	"\n\nThis is the last turn to settle '%s' (turn %d).",
+}
+
+// rankedChoiceWarning is added to the final urgency note when the goal will go
+// to a ranked-choice vote if nothing passes.
+const rankedChoiceWarning = " If no proposal passes, everyone will rank the proposals made so far and the one most broadly preferred will be adopted."
+
+// projectTurns fits a line to a goal's support over the turns so far and
+// returns how many more turns it will take to reach the support needed. It
+// returns false if support isn't rising.
+func projectTurns(history []float64, needed float64) (int, bool) {
+	n := float64(len(history))
+	var sumX, sumY, sumXY, sumXX float64
+	for i, support := range history {
+		x := float64(i)
+		sumX += x
+		sumY += support
+		sumXY += x * support
+		sumXX += x * x
+	}
+	denominator := n*sumXX - sumX*sumX
+	if denominator == 0 {
+		return 0, false
+	}
+	slope := (n*sumXY - sumX*sumY) / denominator
+	if slope <= 0 {
+		return 0, false
+	}
+	current := (sumY-slope*sumX)/n + slope*(n-1)
+	if current >= needed {
+		return 0, true
+	}
+	return int(math.Ceil((needed - current) / slope)), true
+}
+
+// goalDeadline returns the last turn a goal can be settled on: its own turn
+// limit, or the simulation's if that comes first.
+func (s *Simulation) goalDeadline(goal *mcpsim.InteractiveGoal) int {
+	deadline := s.MaxTurns()
+	if goal.MaxTurns > 0 && goal.MaxTurns < deadline {
+		deadline = goal.MaxTurns
+	}
+	return deadline
+}
+
+// forecastGoals records how much support each pending goal decided by vote
+// won this turn and, once there are enough turns to see a trend, projects
+// when it will be settled. Goals projected to miss their deadline are marked
+// at risk, which brings urgency notes on the turns that follow.
+func (s *Simulation) forecastGoals(turn int) {
+	rules := s.Scenario.TurnBudget
+	if rules == nil {
+		return
+	}
+
+	world := s.World.Snapshot()
+	names := make([]string, 0, len(world.Goals))
+	for name := range world.Goals {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, goalName := range names {
+		goal := world.Goals[goalName]
+		if goal.Status != mcpsim.GoalPending || goal.Judged() || goal.Individual() {
+			delete(s.atRisk, goalName)
+			continue
+		}
+
+		participants := world.GoalParticipants(goal)
+		support := goal.Support(participants, turn)
+		s.convergence[goalName] = append(s.convergence[goalName], support)
+		history := s.convergence[goalName]
+		if len(history) < *rules.PredictAfter {
+			continue
+		}
+
+		deadline := s.goalDeadline(goal)
+		needed := goal.SupportNeeded(len(participants))
+		forecast := chronicle.GoalForecast{
+			GoalName: goalName,
+			Support:  support,
+			Needed:   needed,
+			Deadline: deadline,
+		}
+		if remaining, converging := projectTurns(history, needed); converging {
+			forecast.ProjectedTurn = turn + remaining
+		}
+		forecast.AtRisk = forecast.ProjectedTurn == 0 || forecast.ProjectedTurn > deadline
+		s.currentForecasts = append(s.currentForecasts, forecast)
+
+		if !forecast.AtRisk {
+			delete(s.atRisk, goalName)
+			continue
+		}
+		if _, warned := s.atRisk[goalName]; !warned {
+			slog.Warn("goal projected to miss its deadline", "goal", goalName, "support", support, "needed", needed,
+				"projected_turn", forecast.ProjectedTurn, "deadline", deadline)
+		}
+		s.atRisk[goalName] = deadline
+	}
+}
+
+// urgencyNote returns the situation notes warning an agent about the goals
+// they decide that are projected to miss their deadline, firmer as each
+// deadline nears, or "" when there are none or urgency is turned off.
+func (s *Simulation) urgencyNote(agentName string, turn int) string {
+	rules := s.Scenario.TurnBudget
+	if rules == nil || !*rules.Urgency || len(s.atRisk) == 0 || s.isObserver(agentName) {
+		return ""
+	}
+
+	world := s.World.Snapshot()
+	var goals []string
+	for goalName := range s.atRisk {
+		goal, ok := world.Goals[goalName]
+		if ok && goal.Status == mcpsim.GoalPending && world.CanDecide(goal, agentName) {
+			goals = append(goals, goalName)
+		}
+	}
+	sort.Strings(goals)
+
+	note := ""
+	for _, goalName := range goals {
+		deadline := s.atRisk[goalName]
+		level := max(len(urgencySituations)-1-(deadline-turn), 0)
+		note += fmt.Sprintf(urgencySituations[level], goalName, deadline)
+		if deadline == turn && *rules.RankedChoice {
+			note += rankedChoiceWarning
+		}
+	}
+	return note
+}
+
+// rankedChoiceGoals returns the goals a ranked-choice vote settles this turn,
+// those with at least two proposals to choose between: ranked-choice goals,
+// and goals decided by vote still pending on their final turn. A goal still
+// open after its last vote has plainly run out of time, whatever it was
+// projected to do.
+func (s *Simulation) rankedChoiceGoals(turn int) []string {
+	rules := s.Scenario.TurnBudget
+	lastResort := rules != nil && *rules.RankedChoice
+
+	world := s.World.Snapshot()
+	var goals []string
+	for goalName, goal := range world.Goals {
+		if goal.Status != mcpsim.GoalPending || goal.Judged() || goal.Individual() {
+			continue
+		}
+		if !goal.Ranked() && (!lastResort || s.goalDeadline(goal) != turn) {
+			continue
+		}
+		if len(goal.RankedChoiceCandidates()) < 2 {
+			continue
+		}
+		goals = append(goals, goalName)
+	}
+	sort.Strings(goals)
+	return goals
+}
+
+// runRankedChoice settles ranked-choice goals, and goals that ran out of time,
+// by a ranked-choice vote: each agent who decides them ranks their proposals,
+// and an instant-runoff count adopts the one most broadly preferred.
+func (s *Simulation) runRankedChoice(ctx context.Context, turn int) error {
+	goals := s.rankedChoiceGoals(turn)
+	if len(goals) == 0 {
+		return nil
+	}
+
+	slog.Info("ranked-choice vote starting", "goals", goals)
+	s.World.SetPhase(mcpsim.PhaseRanking)
+	rankingTools := s.getRankingTools()
+	rankingSituation := s.buildRankingPrompt(goals)
+
+	for _, agentName := range s.TurnOrder {
+		agent := s.Agents[agentName]
+
+		if s.isObserver(agentName) {
+			s.skipPhase(mcpsim.PhaseRanking, agentName, "observers don't vote")
+			continue
+		}
+		if _, exhausted := s.conditionState(agentName); exhausted {
+			s.skipPhase(mcpsim.PhaseRanking, agentName, exhaustedReason)
+			continue
+		}
+		if !s.decidesAny(agentName, goals) {
+			s.skipPhase(mcpsim.PhaseRanking, agentName, "no say in the goals being ranked")
+			continue
+		}
+
+		slog.Debug("agent turn starting", "agent", agentName, "phase", "ranking")
+		agentCtx := s.agentContext(ctx, agentName)
+
+		finishStream := s.streamUtterance(ctx, turn, agent)
+		response, err := agent.Think(agentCtx, rankingSituation+s.tiredNote(agentName), nil, rankingTools, s.toolExecutor())
+		if err != nil {
+			return fmt.Errorf("agent %s failed to rank proposals: %w", agentName, err)
+		}
+		response.Message = s.limitUtterance(agentName, response.Message)
+		finishStream(response.Message)
+
+		if response.Thinking != "" {
+			slog.Debug("reasoning", "agent", agentName, "thinking", response.Thinking)
+		}
+		if response.Message != "" {
+			slog.Info("dialogue", "agent", agentName, "message", response.Message)
+		}
+
+		// Capture event for chronicle
+		s.captureRefusals(agentName, response.Refusals)
+		s.captureEvent(agentName, response.Message, response.Thinking, "dialogue")
+		s.captureCandidates(response.Candidates)
+		s.captureInvocations(response.Invocations)
+		s.captureModelUse(agentName, response.Iterations)
+
+		// Capture pending dialogue from tool calls (ranking comments, passes)
+		for _, msg := range s.World.TakePendingDialogue() {
+			s.captureToolDialogue(msg)
+			if msg.Type == mcpsim.MessageTypePass {
+				slog.Info("pass", "agent", msg.AgentName, "reason", msg.Content)
+			}
+		}
+		s.notifyCaptured(ctx, turn)
+	}
+
+	for _, goalName := range goals {
+		winner, err := s.World.ResolveRankedChoice(goalName, turn)
+		if err != nil {
+			slog.Warn("failed to count ranked-choice vote", "goal", goalName, "error", err)
+			continue
+		}
+		if winner == nil {
+			slog.Info("ranked-choice vote failed", "goal", goalName, "reason", "nobody ranked the proposals")
+			continue
+		}
+		slog.Info("goal settled by ranked choice", "goal", goalName, "proposal", winner.ID, "description", winner.Description)
+	}
+
+	completions := s.captureGoalCompletionsForTurn(turn)
+	s.recordCommitments(ctx, completions, turn)
+	s.notifyCaptured(ctx, turn)
+	return nil
+}
+
+// runoffRounds converts an instant-runoff count for the chronicle.
+func runoffRounds(rounds []mcpsim.RunoffRound) []chronicle.RunoffRound {
+	if len(rounds) == 0 {
+		return nil
+	}
+	converted := make([]chronicle.RunoffRound, len(rounds))
+	for i, round := range rounds {
+		converted[i] = chronicle.RunoffRound{Counts: round.Counts, Eliminated: round.Eliminated}
+	}
+	return converted
+}
+
+// decidesAny reports whether an agent has a say in any of the goals.
+func (s *Simulation) decidesAny(agentName string, goals []string) bool {
+	world := s.World.Snapshot()
+	return slices.ContainsFunc(goals, func(goalName string) bool {
+		goal, ok := world.Goals[goalName]
+		return ok && world.CanDecide(goal, agentName)
+	})
+}
+
+// getRankingTools returns the tools available in a ranked-choice vote.
+func (s *Simulation) getRankingTools() []map[string]interface{} {
+	allowedTools := []string{
+		// Memory tools - agents still need access to their identity and memories
+		"query_self", "query_background", "query_communication_style",
+		"query_scene", "query_character", "query_memory", "query_knowledge",
+		// Ranking tools
+		"view_goal", "rank_proposals", "pass_turn",
+	}
+	filtered := []map[string]interface{}{}
+	for _, tool := range s.MCPServer.GetToolDefinitions() {
+		if fn, ok := tool["function"].(map[string]interface{}); ok {
+			if name, ok := fn["name"].(string); ok && slices.Contains(allowedTools, name) {
+				filtered = append(filtered, tool)
+			}
+		}
+	}
+	return filtered
+}
+
+// buildRankingPrompt creates the prompt for a ranked-choice vote, listing
+// each goal's proposals with the IDs to rank them by.
+func (s *Simulation) buildRankingPrompt(goals []string) string {
+	world := s.World.Snapshot()
+	proposalList := ""
+	for _, goalName := range goals {
+		goal, ok := world.Goals[goalName]
+		if !ok {
+			continue
+		}
+		proposalList += fmt.Sprintf("\n\nGoal '%s' - %s", goalName, goal.Description)
+		for _, proposal := range goal.RankedChoiceCandidates() {
+			proposalList += fmt.Sprintf("\n- %s: %s (proposed by %s)", proposal.ID, proposal.Description, proposal.ProposedBy)
+		}
+	}
+
+	fallback := fmt.Sprintf("RANKED-CHOICE VOTE: Rank each goal's proposals with rank_proposals, most preferred first.%s", proposalList)
+	promptTemplate, err := prompts.GetPrompt("ranking")
+	if err != nil {
+		return fallback
+	}
+	tmpl, err := template.New("ranking").Parse(promptTemplate)
+	if err != nil {
+		return fallback
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, struct{ ProposalList string }{ProposalList: proposalList}); err != nil {
+		return fallback
+	}
+	return buf.String()
+}
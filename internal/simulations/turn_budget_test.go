@@ -0,0 +1,101 @@
+package simulations
+
+import (
+	"testing"
+
+	mcpsim "github.com/poiesic/wonda/internal/mcp/simulation"
+	"github.com/poiesic/wonda/internal/scenarios"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProjectTurns(t *testing.T) {
+	tests := []struct {
+		name       string
+		history    []float64
+		needed     float64
+		want       int
+		converging bool
+	}{
+		{"steady climb", []float64{0.25, 0.5}, 1, 2, true},
+		{"already there", []float64{0.5, 1}, 1, 0, true},
+		{"noisy climb", []float64{0, 0.5, 0.25, 0.75}, 1, 2, true},
+		{"flat", []float64{0.5, 0.5, 0.5}, 1, 0, false},
+		{"falling", []float64{0.75, 0.5}, 1, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, converging := projectTurns(tt.history, tt.needed)
+			assert.Equal(t, tt.converging, converging)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestTurnBudget(t *testing.T) {
+	newSim := func() *Simulation {
+		world := mcpsim.NewWorldState("Cafe", "")
+		for _, name := range []string{"Alice", "Bob", "Carol", "Dave"} {
+			world.AddAgent(name, "")
+		}
+		goal := mcpsim.NewInteractiveGoal("dinner", "Pick a restaurant", "consensus", 1)
+		goal.MaxTurns = 4
+		world.AddGoal(goal)
+
+		settings := &scenarios.TurnBudgetConfig{}
+		settings.ApplyDefaults()
+		return &Simulation{
+			Scenario: &scenarios.Scenario{
+				Basics:     &scenarios.BasicScenarioInformation{MaxTurns: 10},
+				TurnBudget: settings,
+			},
+			World:       world,
+			convergence: make(map[string][]float64),
+			atRisk:      make(map[string]int),
+		}
+	}
+	vote := func(sim *Simulation, turn int, yes ...string) {
+		sim.World.Update(func(w *mcpsim.WorldState) error {
+			goal := w.Goals["dinner"]
+			id := goal.AddProposal(yes[0], "Somewhere", turn)
+			for _, agentName := range yes {
+				goal.Vote(id, agentName, "yes", turn)
+			}
+			return nil
+		})
+	}
+
+	t.Run("slow convergence puts a goal at risk", func(t *testing.T) {
+		sim := newSim()
+		vote(sim, 1, "Alice")
+		sim.forecastGoals(1)
+		assert.Empty(t, sim.currentForecasts, "too few turns to see a trend")
+
+		vote(sim, 2, "Bob")
+		sim.forecastGoals(2)
+		require.Len(t, sim.currentForecasts, 1)
+		forecast := sim.currentForecasts[0]
+		assert.Equal(t, 4, forecast.Deadline, "the goal's own limit comes first")
+		assert.Equal(t, 0, forecast.ProjectedTurn, "support isn't rising")
+		assert.True(t, forecast.AtRisk)
+		assert.Equal(t, 4, sim.atRisk["dinner"])
+
+		assert.Contains(t, sim.urgencyNote("Alice", 3), "has to be settled by turn 4")
+		note := sim.urgencyNote("Alice", 4)
+		assert.Contains(t, note, "last turn to settle 'dinner'")
+		assert.Contains(t, note, "rank the proposals")
+	})
+
+	t.Run("fast convergence doesn't", func(t *testing.T) {
+		sim := newSim()
+		vote(sim, 1, "Alice")
+		sim.forecastGoals(1)
+		vote(sim, 2, "Alice", "Bob", "Carol")
+		sim.forecastGoals(2)
+		require.Len(t, sim.currentForecasts, 1)
+		assert.Equal(t, 3, sim.currentForecasts[0].ProjectedTurn)
+		assert.False(t, sim.currentForecasts[0].AtRisk)
+		assert.Empty(t, sim.urgencyNote("Alice", 3))
+	})
+}
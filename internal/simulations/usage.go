@@ -0,0 +1,169 @@
+package simulations
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/poiesic/wonda/internal/chronicle"
+	"github.com/poiesic/wonda/internal/config"
+	"github.com/poiesic/wonda/internal/usage"
+)
+
+// Usage callers that aren't agents
+const (
+	usageCallerJudge      = "goal judge"
+	usageCallerPostmortem = "post-mortem"
+	usageCallerDirector   = "director"
+	usageCallerCompaction = "memory compaction"
+)
+
+// trackedClient records the token usage of every request made through it.
+type trackedClient struct {
+	client   Client
+	caller   string // Agent name, or which part of the simulation uses the client
+	provider string
+	model    *config.Model
+	tracker  *usage.Tracker
+}
+
+// Chat implements Client.
+func (c *trackedClient) Chat(ctx context.Context, req ChatRequest) (ChatResponse, error) {
+	resp, err := c.client.Chat(ctx, req)
+	if err != nil {
+		return resp, err
+	}
+
+	c.record(resp)
+	return resp, nil
+}
+
+// ChatStream implements StreamingClient.
+// Clients that can't stream (chaos mode, for one) answer through Chat without deltas.
+func (c *trackedClient) ChatStream(ctx context.Context, req ChatRequest, onDelta func(delta StreamDelta)) (ChatResponse, error) {
+	streaming, ok := c.client.(StreamingClient)
+	if !ok {
+		return c.Chat(ctx, req)
+	}
+
+	resp, err := streaming.ChatStream(ctx, req, onDelta)
+	if err != nil {
+		return resp, err
+	}
+
+	c.record(resp)
+	return resp, nil
+}
+
+// record adds a response's token usage to the tracker.
+func (c *trackedClient) record(resp ChatResponse) {
+	c.tracker.Record(c.caller, c.provider, c.model.Name, resp.Usage.InputTokens, resp.Usage.OutputTokens,
+		c.model.Cost(resp.Usage.InputTokens, resp.Usage.OutputTokens))
+}
+
+// newClient creates an LLM client whose usage is recorded in the simulation's
+// tracker under caller: the agent it speaks for, or a usageCaller. In chaos mode the client also injects failures. Models served by llama-server
+// have their server started first, if the preflight check hasn't already.
+// In a dry run the client is a MockClient and no server is started.
+func (s *Simulation) newClient(caller string, provider *config.Provider, model *config.Model) (Client, error) {
+	if s.Temperature != nil {
+		tuned := *model
+		tuned.Temperature = s.Temperature
+		model = &tuned
+	}
+	var client Client
+	if s.DryRun != nil {
+		client = NewMockClient(caller, s.DryRun)
+	} else {
+		if err := s.ensureLlamaServer(context.Background(), provider, model); err != nil {
+			return nil, err
+		}
+		var err error
+		client, err = NewClient(provider, model)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if s.Chaos != nil {
+		client = &chaosClient{
+			client: client,
+			model:  model.Name,
+			config: s.Chaos,
+			rand:   s.chaosRand,
+		}
+	}
+	return &trackedClient{
+		client:   client,
+		caller:   caller,
+		provider: provider.Name,
+		model:    model,
+		tracker:  s.Usage,
+	}, nil
+}
+
+// writeUsageReport appends this run's usage to the catalog in the config directory.
+// Failures are logged rather than returned so they never mask the simulation's result.
+// Dry runs have no usage to report.
+func (s *Simulation) writeUsageReport(startTime time.Time) {
+	entries := s.Usage.Entries()
+	if len(entries) == 0 || s.DryRun != nil {
+		return
+	}
+
+	report := usage.Report{
+		SimulationID: s.ID.String(),
+		Scenario:     s.Scenario.Basics.Name,
+		Chronicle:    s.chroniclePath,
+		StartTime:    startTime,
+		EndTime:      s.Clock.Now(),
+		Entries:      entries,
+	}
+
+	catalogPath := usage.CatalogPath(s.ConfigDir)
+	if err := usage.AppendReport(catalogPath, report); err != nil {
+		slog.Warn("failed to write usage report", "path", catalogPath, "error", err)
+		return
+	}
+	slog.Debug("usage report written", "path", catalogPath, "entries", len(entries))
+}
+
+// usageRecord summarizes the run's usage so far for the chronicle.
+func (s *Simulation) usageRecord() chronicle.Usage {
+	return chronicle.Usage{
+		Type:   "usage",
+		Total:  s.Usage.Total(),
+		Models: s.Usage.Entries(),
+		Agents: s.Usage.Callers(),
+		Turns:  s.Usage.Turns(),
+	}
+}
+
+// printUsageSummary logs the run's token usage and cost, in total, per model
+// and per agent. Costs are only shown for models with configured prices.
+func (s *Simulation) printUsageSummary() {
+	total := s.Usage.Total()
+	if total.Requests == 0 {
+		return
+	}
+
+	slog.Info("usage summary", "requests", total.Requests, "input_tokens", total.InputTokens,
+		"output_tokens", total.OutputTokens, "cost", fmt.Sprintf("%.4f", total.Cost))
+	for _, entry := range s.Usage.Entries() {
+		slog.Info("model usage", "provider", entry.Provider, "model", entry.Model, "requests", entry.Requests,
+			"input_tokens", entry.InputTokens, "output_tokens", entry.OutputTokens, "cost", fmt.Sprintf("%.4f", entry.Cost))
+	}
+
+	callers := s.Usage.Callers()
+	names := make([]string, 0, len(callers))
+	for name := range callers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		totals := callers[name]
+		slog.Info("agent usage", "agent", name, "requests", totals.Requests,
+			"input_tokens", totals.InputTokens, "output_tokens", totals.OutputTokens, "cost", fmt.Sprintf("%.4f", totals.Cost))
+	}
+}
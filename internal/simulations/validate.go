@@ -0,0 +1,92 @@
+package simulations
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"path"
+	"slices"
+
+	"github.com/poiesic/wonda/internal/config"
+	"github.com/poiesic/wonda/internal/scenarios"
+)
+
+// Validate statically checks what a loaded scenario refers to: that its
+// characters exist, that every agent's model, ensemble member and goal judge,
+// and the director, resolves to a configured provider, that its embedding and memory backend are
+// configured, and that its durations are positive. It makes no requests and
+// starts no servers, so problems that would otherwise stop a run partway
+// through are found up front. All problems are reported together.
+func (s *Simulation) Validate() error {
+	var errs []error
+
+	// Characters
+	agentNames := slices.Sorted(maps.Keys(s.Scenario.Agents))
+	for _, agentName := range agentNames {
+		agentConfig := s.Scenario.Agents[agentName]
+		characterPath := path.Join(s.ConfigDir, "characters", agentConfig.Character+".toml")
+		if _, err := scenarios.LoadCharacterFromFile(characterPath); err != nil {
+			errs = append(errs, fmt.Errorf("agent %s: failed to load character %s: %w", agentName, agentConfig.Character, err))
+		}
+	}
+
+	// Durations
+	if s.Scenario.Basics.MaxRuntime.ToDuration() <= 0 {
+		errs = append(errs, fmt.Errorf("max_runtime must be positive (got %s)", s.Scenario.Basics.MaxRuntime.ToDuration()))
+	}
+	goalNames := slices.Sorted(maps.Keys(s.Scenario.Goals))
+	for _, goalName := range goalNames {
+		if deadline := s.Scenario.Goals[goalName].Deadline; deadline != nil && deadline.ToDuration() <= 0 {
+			errs = append(errs, fmt.Errorf("goal %s: deadline must be positive (got %s)", goalName, deadline.ToDuration()))
+		}
+	}
+
+	// Models and providers
+	providersPath := path.Join(s.ConfigDir, "providers.toml")
+	providers, err := config.LoadProvidersFromFile(providersPath)
+	if err != nil {
+		errs = append(errs, fmt.Errorf("failed to load providers: %w", err))
+	}
+	models, err := config.LoadModelsFromDir(path.Join(s.ConfigDir, "models"))
+	if err != nil {
+		errs = append(errs, fmt.Errorf("failed to load models: %w", err))
+	}
+	if providers != nil && models != nil {
+		if err := registerLlamaServers(models, providers); err != nil {
+			errs = append(errs, fmt.Errorf("failed to load models: %w", err))
+		}
+		_, modelErrs := s.resolveAgentModels(models, providers)
+		errs = append(errs, modelErrs...)
+		for _, goalName := range goalNames {
+			goal := s.Scenario.Goals[goalName]
+			if !goal.Judged() {
+				continue
+			}
+			if _, _, _, err := s.resolveGoalJudge(goalName, goal, models, providers); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		if s.Scenario.Director != nil {
+			if _, _, _, err := s.resolveDirector(models, providers); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		if s.Scenario.Memory != nil && s.Scenario.Memory.Compaction != nil {
+			if _, _, _, err := s.resolveCompactor(models, providers); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	// Embedding and memory store
+	if _, err := selectEmbedding(providersPath, s.Scenario); err != nil {
+		errs = append(errs, err)
+	}
+	if settings := s.Scenario.Memory; settings != nil && settings.Backend != "" && providers != nil {
+		if _, ok := providers.VectorStores[settings.Backend]; !ok {
+			errs = append(errs, fmt.Errorf("memory backend %s not found in providers.toml vector_stores", settings.Backend))
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,107 @@
+package simulations
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/poiesic/wonda/internal/scenarios"
+)
+
+func TestValidate(t *testing.T) {
+	configDir := t.TempDir()
+	write := func(name, contents string) {
+		path := filepath.Join(configDir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(contents), 0644))
+	}
+	write("providers.toml", `
+version = "1.0.0"
+
+[providers.ollama]
+type = "openai"
+base_url = "http://localhost:11434/v1"
+`)
+	write("models/local.toml", `
+version = "1.0.0"
+name = "qwen2.5"
+provider = "ollama"
+`)
+	write("models/remote.toml", `
+version = "1.0.0"
+name = "gpt-4o"
+provider = "openai"
+`)
+	write("characters/pragmatist.toml", `
+version = "1.0.0"
+
+[external]
+archetype = "The Pragmatist"
+description = "Gets things done without fuss"
+communication_style = "Short and to the point"
+positive_traits = ["practical"]
+negative_traits = ["impatient"]
+
+[internal]
+decision_style = "Picks what works"
+`)
+
+	load := func(agents, goals string) *Simulation {
+		scenario, err := scenarios.LoadScenario([]byte(`
+version = "1.0.0"
+
+[scenario]
+name = "Dinner"
+description = "Pick a restaurant"
+max_runtime = "30m"
+
+[scenario.defaults]
+model = "local"
+` + agents + goals))
+		require.NoError(t, err)
+		return NewSimulation(scenario, configDir)
+	}
+
+	t.Run("accepts a scenario whose references resolve", func(t *testing.T) {
+		sim := load(`
+[agents.alice]
+character = "pragmatist"
+`, `
+[goals.restaurant]
+description = "Agree on a restaurant"
+priority = 1
+deadline = "10m"
+`)
+		assert.NoError(t, sim.Validate())
+	})
+
+	t.Run("reports every problem at once", func(t *testing.T) {
+		sim := load(`
+[agents.alice]
+character = "pragmatist"
+
+[agents.bob]
+character = "skeptic"
+model = "missing"
+
+[agents.carol]
+character = "pragmatist"
+model = "remote"
+`, `
+[goals.restaurant]
+description = "Agree on a restaurant"
+priority = 1
+deadline = "-5m"
+`)
+		err := sim.Validate()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "agent bob: failed to load character skeptic")
+		assert.Contains(t, err.Error(), "model missing not found for agent bob")
+		assert.Contains(t, err.Error(), "provider openai (from model remote) not found for agent carol")
+		assert.Contains(t, err.Error(), "goal restaurant: deadline must be positive")
+		assert.Len(t, err.(interface{ Unwrap() []error }).Unwrap(), 4)
+	})
+}
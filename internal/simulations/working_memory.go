@@ -0,0 +1,93 @@
+package simulations
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	mcpsim "github.com/poiesic/wonda/internal/mcp/simulation"
+)
+
+// workingMemorySituation gives an agent their working memory in their prompt.
+const workingMemorySituation = "\n\nWHAT'S FRESH IN YOUR MIND:\n%s\nYou remember all this already - only search your memories for what happened before it."
+
+// workingFrom returns the first turn agents hold in working memory on a turn.
+func (s *Simulation) workingFrom(turn int) int {
+	return max(turn-*s.Scenario.Memory.Working.Turns, 1)
+}
+
+// workingMemoryNote returns the situation note holding an agent's working
+// memory: what they heard said in the last few turns, the goal they're
+// working on and the proposals on it, and what the group has committed to.
+// It is "" when the scenario keeps no working memory or there's nothing in it.
+func (s *Simulation) workingMemoryNote(agentName string, turn int) string {
+	if s.Scenario.Memory == nil || s.Scenario.Memory.Working == nil {
+		return ""
+	}
+	settings := s.Scenario.Memory.Working
+	world := s.World.Snapshot()
+
+	var sections []string
+
+	from := s.workingFrom(turn)
+	var said []string
+	for _, msg := range world.GetHeardMessages(agentName, 0) {
+		if msg.Turn < from || msg.Content == "" || msg.Type == mcpsim.MessageTypePass || msg.Type == mcpsim.MessageTypeMonologue {
+			continue
+		}
+		speaker := msg.AgentName
+		if speaker == agentName {
+			speaker = "You"
+		}
+		said = append(said, fmt.Sprintf("- %s (turn %d): %s", speaker, msg.Turn, msg.Content))
+	}
+	if len(said) > *settings.Messages {
+		said = said[len(said)-*settings.Messages:]
+	}
+	if len(said) > 0 {
+		sections = append(sections, "Recently said:\n"+strings.Join(said, "\n"))
+	}
+
+	if goal, ok := world.Goals[world.DiscussedGoal(agentName)]; ok && goal.Status == mcpsim.GoalPending {
+		section := fmt.Sprintf("You're working on '%s': %s", goal.Name, goal.Description)
+		var pending []*mcpsim.Proposal
+		for _, proposal := range goal.Proposals {
+			if proposal.Status == mcpsim.ProposalPending {
+				pending = append(pending, proposal)
+			}
+		}
+		sort.Slice(pending, func(i, j int) bool {
+			return mcpsim.ProposalNumber(pending[i].ID) < mcpsim.ProposalNumber(pending[j].ID)
+		})
+		if len(pending) == 0 {
+			section += "\nNo proposals are on the table yet."
+		} else {
+			section += "\nOn the table:"
+			for _, proposal := range pending {
+				section += fmt.Sprintf("\n- %s by %s: %s", proposal.ID, proposal.ProposedBy, proposal.Description)
+				if vote, ok := proposal.Votes[agentName]; ok {
+					section += fmt.Sprintf(" (you voted %s)", vote.Choice)
+				}
+			}
+		}
+		sections = append(sections, section)
+	}
+
+	var committed []string
+	for _, commitment := range world.Commitments {
+		line := fmt.Sprintf("- %s: %s (turn %d)", commitment.Goal, commitment.Description, commitment.AgreedAt)
+		if slices.Contains(commitment.Objected, agentName) {
+			line += " - over your objection"
+		}
+		committed = append(committed, line)
+	}
+	if len(committed) > 0 {
+		sections = append(sections, "What the group has committed to:\n"+strings.Join(committed, "\n"))
+	}
+
+	if len(sections) == 0 {
+		return ""
+	}
+	return fmt.Sprintf(workingMemorySituation, strings.Join(sections, "\n\n"))
+}
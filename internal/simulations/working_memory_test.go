@@ -0,0 +1,81 @@
+package simulations
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	mcpsim "github.com/poiesic/wonda/internal/mcp/simulation"
+	"github.com/poiesic/wonda/internal/scenarios"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorkingMemoryNote(t *testing.T) {
+	world := mcpsim.NewWorldState("Kitchen", "")
+	world.AddAgent("Alice", "")
+	world.AddAgent("Bob", "")
+	goal := mcpsim.NewInteractiveGoal("dinner", "Pick a restaurant", "consensus", 1)
+	world.AddGoal(goal)
+
+	for turn, line := range []string{"I'm starving.", "Pizza again?", "Let's try Bella's."} {
+		world.SetTurn(turn + 1)
+		world.AddMessage("Bob", line, "", mcpsim.MessageTypeDialogue)
+	}
+	world.AddMessage("Alice", "Bella's is fine by me.", "", mcpsim.MessageTypeDialogue)
+	require.NoError(t, world.Update(func(w *mcpsim.WorldState) error {
+		id := w.Goals["dinner"].AddProposal("Bob", "Dinner at Bella's", 3)
+		return w.Goals["dinner"].Vote(id, "Alice", "yes", 3)
+	}))
+	world.AddCommitment(mcpsim.Commitment{Goal: "movie", Description: "Watch Alien", ProposedBy: "Alice", Objected: []string{"Alice"}, AgreedAt: 2})
+
+	turns, messages := 1, 20
+	sim := &Simulation{
+		Scenario: &scenarios.Scenario{Memory: &scenarios.MemoryConfig{
+			Working: &scenarios.WorkingMemoryConfig{Turns: &turns, Messages: &messages},
+		}},
+		World: world,
+	}
+
+	note := sim.workingMemoryNote("Alice", 3)
+	assert.NotContains(t, note, "I'm starving", "older turns are left to the memory store")
+	assert.Contains(t, note, "- Bob (turn 2): Pizza again?")
+	assert.Contains(t, note, "- You (turn 3): Bella's is fine by me.")
+	assert.Contains(t, note, "You're working on 'dinner': Pick a restaurant")
+	assert.Contains(t, note, "- proposal_1 by Bob: Dinner at Bella's (you voted yes)")
+	assert.Contains(t, note, "- movie: Watch Alien (turn 2) - over your objection")
+	assert.Equal(t, 2, sim.workingFrom(3))
+
+	sim.Scenario.Memory.Working = nil
+	assert.Empty(t, sim.workingMemoryNote("Alice", 3), "working memory is opt-in")
+}
+
+func TestWorkingMemoryNoteOrdersProposals(t *testing.T) {
+	world := mcpsim.NewWorldState("Kitchen", "")
+	world.AddAgent("Alice", "")
+	goal := mcpsim.NewInteractiveGoal("dinner", "Pick a restaurant", "consensus", 1)
+	for i := 1; i <= 11; i++ {
+		goal.AddProposal("Alice", fmt.Sprintf("Option %d", i), 1)
+	}
+	world.AddGoal(goal)
+	world.SetTurn(1)
+	world.AddMessage("Alice", "So many options.", "", mcpsim.MessageTypeDialogue)
+
+	turns, messages := 1, 20
+	sim := &Simulation{
+		Scenario: &scenarios.Scenario{Memory: &scenarios.MemoryConfig{
+			Working: &scenarios.WorkingMemoryConfig{Turns: &turns, Messages: &messages},
+		}},
+		World: world,
+	}
+
+	note := sim.workingMemoryNote("Alice", 1)
+	last := -1
+	for i := 1; i <= 11; i++ {
+		line := fmt.Sprintf("- proposal_%d by Alice: Option %d\n", i, i)
+		at := strings.Index(note+"\n", line)
+		require.NotEqual(t, -1, at, "%s is on the table", line)
+		assert.Greater(t, at, last, "proposal_%d is listed in proposal order", i)
+		last = at
+	}
+}
@@ -0,0 +1,71 @@
+package usage
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+)
+
+// CatalogFile is the name of the usage catalog in the config directory.
+// The catalog is JSONL: one Report per completed run.
+const CatalogFile = "usage.jsonl"
+
+// CatalogPath returns the usage catalog path for a config directory.
+func CatalogPath(configDir string) string {
+	return path.Join(configDir, CatalogFile)
+}
+
+// AppendReport appends a run's report to the catalog, creating it if needed.
+func AppendReport(catalogPath string, report Report) error {
+	data, err := json.Marshal(report)
+	if err != nil {
+		return fmt.Errorf("failed to marshal usage report: %w", err)
+	}
+
+	file, err := os.OpenFile(catalogPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open usage catalog: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write usage report: %w", err)
+	}
+	return nil
+}
+
+// LoadReports reads every report in the catalog.
+// A missing catalog yields no reports.
+func LoadReports(catalogPath string) ([]Report, error) {
+	file, err := os.Open(catalogPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer file.Close()
+
+	var reports []Report
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		var report Report
+		if err := json.Unmarshal(line, &report); err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNum, err)
+		}
+		reports = append(reports, report)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return reports, nil
+}
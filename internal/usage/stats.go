@@ -0,0 +1,135 @@
+package usage
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// Aggregation periods for Aggregate
+const (
+	PeriodDay   = "day"
+	PeriodWeek  = "week"
+	PeriodMonth = "month"
+	PeriodAll   = "all"
+)
+
+// Row is aggregated usage for one provider/model in one period.
+type Row struct {
+	Period       string
+	Provider     string
+	Model        string
+	Runs         int
+	Requests     int
+	InputTokens  int
+	OutputTokens int
+	Cost         float64
+}
+
+// periodKey labels the period a run started in.
+func periodKey(t time.Time, period string) (string, error) {
+	switch period {
+	case PeriodDay:
+		return t.Format("2006-01-02"), nil
+	case PeriodWeek:
+		year, week := t.ISOWeek()
+		return fmt.Sprintf("%d-W%02d", year, week), nil
+	case PeriodMonth:
+		return t.Format("2006-01"), nil
+	case PeriodAll, "":
+		return "all", nil
+	default:
+		return "", fmt.Errorf("unknown period '%s' (use day, week, month, or all)", period)
+	}
+}
+
+// Aggregate sums reports per period, provider, and model.
+// Reports starting before since are skipped (zero since includes everything).
+// Rows are ordered by period, then provider, then model.
+func Aggregate(reports []Report, period string, since time.Time) ([]Row, error) {
+	rows := make(map[string]*Row)
+	for _, report := range reports {
+		if !since.IsZero() && report.StartTime.Before(since) {
+			continue
+		}
+		key, err := periodKey(report.StartTime.Local(), period)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range report.Entries {
+			rowKey := key + "\x00" + entry.Provider + "\x00" + entry.Model
+			row, ok := rows[rowKey]
+			if !ok {
+				row = &Row{Period: key, Provider: entry.Provider, Model: entry.Model}
+				rows[rowKey] = row
+			}
+			row.Runs++
+			row.Requests += entry.Requests
+			row.InputTokens += entry.InputTokens
+			row.OutputTokens += entry.OutputTokens
+			row.Cost += entry.Cost
+		}
+	}
+
+	result := make([]Row, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, *row)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Period != result[j].Period {
+			return result[i].Period < result[j].Period
+		}
+		if result[i].Provider != result[j].Provider {
+			return result[i].Provider < result[j].Provider
+		}
+		return result[i].Model < result[j].Model
+	})
+	return result, nil
+}
+
+// Spent sums the cost of the reports that started in the same period as now,
+// as when checking a budget. PeriodAll sums every report.
+func Spent(reports []Report, period string, now time.Time) (float64, error) {
+	current, err := periodKey(now.Local(), period)
+	if err != nil {
+		return 0, err
+	}
+	var spent float64
+	for _, report := range reports {
+		if key, _ := periodKey(report.StartTime.Local(), period); key != current {
+			continue
+		}
+		for _, entry := range report.Entries {
+			spent += entry.Cost
+		}
+	}
+	return spent, nil
+}
+
+// WriteCSV writes aggregated usage rows as CSV with a header line.
+func WriteCSV(out io.Writer, rows []Row) error {
+	w := csv.NewWriter(out)
+	if err := w.Write([]string{"period", "provider", "model", "runs", "requests", "input_tokens", "output_tokens", "cost"}); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		record := []string{
+			row.Period,
+			row.Provider,
+			row.Model,
+			strconv.Itoa(row.Runs),
+			strconv.Itoa(row.Requests),
+			strconv.Itoa(row.InputTokens),
+			strconv.Itoa(row.OutputTokens),
+			strconv.FormatFloat(row.Cost, 'f', 6, 64),
+		}
+		if err := w.Write(record); err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
@@ -0,0 +1,115 @@
+package usage
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func statsReports() []Report {
+	at := func(month time.Month, day int) time.Time {
+		return time.Date(2025, month, day, 12, 0, 0, 0, time.Local)
+	}
+	return []Report{
+		{StartTime: at(time.March, 3), Entries: []Entry{
+			{Provider: "openai", Model: "gpt-4o", Requests: 2, InputTokens: 100, OutputTokens: 10, Cost: 0.5},
+			{Provider: "ollama", Model: "llama3", Requests: 1, InputTokens: 50, OutputTokens: 5},
+		}},
+		{StartTime: at(time.March, 4), Entries: []Entry{
+			{Provider: "openai", Model: "gpt-4o", Requests: 3, InputTokens: 200, OutputTokens: 20, Cost: 1},
+		}},
+		{StartTime: at(time.April, 7), Entries: []Entry{
+			{Provider: "openai", Model: "gpt-4o", Requests: 1, InputTokens: 40, OutputTokens: 4, Cost: 0.25},
+		}},
+	}
+}
+
+func TestAggregate(t *testing.T) {
+	type key struct{ period, provider string }
+	tests := []struct {
+		period string
+		want   []key
+	}{
+		{period: PeriodDay, want: []key{{"2025-03-03", "ollama"}, {"2025-03-03", "openai"}, {"2025-03-04", "openai"}, {"2025-04-07", "openai"}}},
+		{period: PeriodWeek, want: []key{{"2025-W10", "ollama"}, {"2025-W10", "openai"}, {"2025-W15", "openai"}}},
+		{period: PeriodMonth, want: []key{{"2025-03", "ollama"}, {"2025-03", "openai"}, {"2025-04", "openai"}}},
+		{period: PeriodAll, want: []key{{"all", "ollama"}, {"all", "openai"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.period, func(t *testing.T) {
+			rows, err := Aggregate(statsReports(), tt.period, time.Time{})
+			require.NoError(t, err)
+
+			keys := make([]key, len(rows))
+			for i, row := range rows {
+				keys[i] = key{row.Period, row.Provider}
+			}
+			assert.Equal(t, tt.want, keys)
+		})
+	}
+
+	t.Run("sums runs in a period", func(t *testing.T) {
+		rows, err := Aggregate(statsReports(), PeriodMonth, time.Time{})
+		require.NoError(t, err)
+		assert.Equal(t, Row{Period: "2025-03", Provider: "openai", Model: "gpt-4o", Runs: 2, Requests: 5, InputTokens: 300, OutputTokens: 30, Cost: 1.5}, rows[1])
+	})
+
+	t.Run("since skips earlier runs", func(t *testing.T) {
+		since := time.Date(2025, time.March, 4, 0, 0, 0, 0, time.Local)
+		rows, err := Aggregate(statsReports(), PeriodAll, since)
+		require.NoError(t, err)
+		assert.Equal(t, []Row{
+			{Period: "all", Provider: "openai", Model: "gpt-4o", Runs: 2, Requests: 4, InputTokens: 240, OutputTokens: 24, Cost: 1.25},
+		}, rows)
+	})
+
+	t.Run("unknown period", func(t *testing.T) {
+		_, err := Aggregate(statsReports(), "fortnight", time.Time{})
+		assert.ErrorContains(t, err, "unknown period 'fortnight'")
+	})
+}
+
+func TestWriteCSV(t *testing.T) {
+	rows := []Row{
+		{Period: "2025-03", Provider: "openai", Model: "gpt-4o", Runs: 2, Requests: 5, InputTokens: 300, OutputTokens: 30, Cost: 1.5},
+		{Period: "2025-03", Provider: "ollama", Model: "llama3", Runs: 1, Requests: 1, InputTokens: 50, OutputTokens: 5},
+	}
+
+	var out bytes.Buffer
+	require.NoError(t, WriteCSV(&out, rows))
+	assert.Equal(t, "period,provider,model,runs,requests,input_tokens,output_tokens,cost\n"+
+		"2025-03,openai,gpt-4o,2,5,300,30,1.500000\n"+
+		"2025-03,ollama,llama3,1,1,50,5,0.000000\n", out.String())
+
+	out.Reset()
+	require.NoError(t, WriteCSV(&out, nil))
+	assert.Equal(t, "period,provider,model,runs,requests,input_tokens,output_tokens,cost\n", out.String())
+}
+
+func TestSpent(t *testing.T) {
+	now := time.Date(2025, time.March, 4, 18, 0, 0, 0, time.Local)
+	tests := []struct {
+		period string
+		want   float64
+	}{
+		{period: PeriodDay, want: 1},
+		{period: PeriodWeek, want: 1.5},
+		{period: PeriodMonth, want: 1.5},
+		{period: PeriodAll, want: 1.75},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.period, func(t *testing.T) {
+			spent, err := Spent(statsReports(), tt.period, now)
+			require.NoError(t, err)
+			assert.InDelta(t, tt.want, spent, 1e-9)
+		})
+	}
+
+	_, err := Spent(statsReports(), "fortnight", now)
+	assert.ErrorContains(t, err, "unknown period")
+}
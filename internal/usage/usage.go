@@ -0,0 +1,168 @@
+package usage
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Entry is the token usage and cost of one provider/model during a run.
+type Entry struct {
+	Provider     string  `json:"provider"`
+	Model        string  `json:"model"`
+	Requests     int     `json:"requests"`
+	InputTokens  int     `json:"input_tokens"`
+	OutputTokens int     `json:"output_tokens"`
+	Cost         float64 `json:"cost,omitempty"` // In the currency of the model's configured prices
+}
+
+// Report is the usage of a single simulation run, as stored in the catalog.
+type Report struct {
+	SimulationID string    `json:"simulation_id"`
+	Scenario     string    `json:"scenario"`
+	Chronicle    string    `json:"chronicle,omitempty"`
+	StartTime    time.Time `json:"start_time"`
+	EndTime      time.Time `json:"end_time"`
+	Entries      []Entry   `json:"entries"`
+}
+
+// Totals is the token usage and cost of a group of requests, such as one
+// agent's or one turn's.
+type Totals struct {
+	Requests     int     `json:"requests"`
+	InputTokens  int     `json:"input_tokens"`
+	OutputTokens int     `json:"output_tokens"`
+	Cost         float64 `json:"cost,omitempty"` // In the currency of the models' configured prices
+}
+
+// add counts one request.
+func (t *Totals) add(inputTokens, outputTokens int, cost float64) {
+	t.Requests++
+	t.InputTokens += inputTokens
+	t.OutputTokens += outputTokens
+	t.Cost += cost
+}
+
+// TurnTotals is the usage of one turn.
+type TurnTotals struct {
+	Turn int `json:"turn"`
+	Totals
+}
+
+// Tracker accumulates usage during a run, per provider/model and broken down
+// by who made the request and in which turn.
+// It is safe for concurrent use (ensemble samples run in parallel).
+type Tracker struct {
+	mu      sync.Mutex
+	turn    int
+	entries map[string]*Entry
+	callers map[string]*Totals
+	turns   map[int]*Totals
+}
+
+// NewTracker creates an empty usage tracker.
+func NewTracker() *Tracker {
+	return &Tracker{
+		entries: make(map[string]*Entry),
+		callers: make(map[string]*Totals),
+		turns:   make(map[int]*Totals),
+	}
+}
+
+// SetTurn sets the turn that requests recorded from now on are counted in.
+// Requests made before the first turn are counted in turn 0.
+func (t *Tracker) SetTurn(turn int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.turn = turn
+}
+
+// Record adds one request's usage for a provider/model, made by caller (an
+// agent's name, or the part of the simulation that made it).
+func (t *Tracker) Record(caller, provider, model string, inputTokens, outputTokens int, cost float64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	key := provider + "/" + model
+	entry, ok := t.entries[key]
+	if !ok {
+		entry = &Entry{Provider: provider, Model: model}
+		t.entries[key] = entry
+	}
+	entry.Requests++
+	entry.InputTokens += inputTokens
+	entry.OutputTokens += outputTokens
+	entry.Cost += cost
+
+	totals, ok := t.callers[caller]
+	if !ok {
+		totals = &Totals{}
+		t.callers[caller] = totals
+	}
+	totals.add(inputTokens, outputTokens, cost)
+
+	totals, ok = t.turns[t.turn]
+	if !ok {
+		totals = &Totals{}
+		t.turns[t.turn] = totals
+	}
+	totals.add(inputTokens, outputTokens, cost)
+}
+
+// Total returns the usage of every request recorded.
+func (t *Tracker) Total() Totals {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var total Totals
+	for _, entry := range t.entries {
+		total.Requests += entry.Requests
+		total.InputTokens += entry.InputTokens
+		total.OutputTokens += entry.OutputTokens
+		total.Cost += entry.Cost
+	}
+	return total
+}
+
+// Callers returns the usage of each caller that made requests.
+func (t *Tracker) Callers() map[string]Totals {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	callers := make(map[string]Totals, len(t.callers))
+	for caller, totals := range t.callers {
+		callers[caller] = *totals
+	}
+	return callers
+}
+
+// Turns returns the usage of each turn with requests, in turn order.
+func (t *Tracker) Turns() []TurnTotals {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	turns := make([]TurnTotals, 0, len(t.turns))
+	for turn, totals := range t.turns {
+		turns = append(turns, TurnTotals{Turn: turn, Totals: *totals})
+	}
+	sort.Slice(turns, func(i, j int) bool { return turns[i].Turn < turns[j].Turn })
+	return turns
+}
+
+// Entries returns the accumulated usage sorted by provider and model.
+func (t *Tracker) Entries() []Entry {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	entries := make([]Entry, 0, len(t.entries))
+	for _, entry := range t.entries {
+		entries = append(entries, *entry)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Provider != entries[j].Provider {
+			return entries[i].Provider < entries[j].Provider
+		}
+		return entries[i].Model < entries[j].Model
+	})
+	return entries
+}
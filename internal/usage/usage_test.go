@@ -0,0 +1,34 @@
+package usage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrackerBreakdown(t *testing.T) {
+	tracker := NewTracker()
+	tracker.Record("alice", "openai", "gpt-4o", 100, 10, 0.5)
+	tracker.SetTurn(1)
+	tracker.Record("alice", "openai", "gpt-4o", 200, 20, 1)
+	tracker.Record("bob", "ollama", "llama3", 300, 30, 0)
+	tracker.SetTurn(2)
+	tracker.Record("goal judge", "openai", "gpt-4o", 50, 5, 0.25)
+
+	assert.Equal(t, Totals{Requests: 4, InputTokens: 650, OutputTokens: 65, Cost: 1.75}, tracker.Total())
+	assert.Equal(t, map[string]Totals{
+		"alice":      {Requests: 2, InputTokens: 300, OutputTokens: 30, Cost: 1.5},
+		"bob":        {Requests: 1, InputTokens: 300, OutputTokens: 30},
+		"goal judge": {Requests: 1, InputTokens: 50, OutputTokens: 5, Cost: 0.25},
+	}, tracker.Callers())
+	assert.Equal(t, []TurnTotals{
+		{Turn: 0, Totals: Totals{Requests: 1, InputTokens: 100, OutputTokens: 10, Cost: 0.5}},
+		{Turn: 1, Totals: Totals{Requests: 2, InputTokens: 500, OutputTokens: 50, Cost: 1}},
+		{Turn: 2, Totals: Totals{Requests: 1, InputTokens: 50, OutputTokens: 5, Cost: 0.25}},
+	}, tracker.Turns())
+
+	entries := tracker.Entries()
+	assert.Len(t, entries, 2)
+	assert.Equal(t, "ollama", entries[0].Provider)
+	assert.Equal(t, 3, entries[1].Requests)
+}